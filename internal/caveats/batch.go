@@ -0,0 +1,218 @@
+package caveats
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+
+	"github.com/authzed/spicedb/pkg/caveats"
+	"github.com/authzed/spicedb/pkg/datastore"
+	"github.com/authzed/spicedb/pkg/genutil/mapz"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+// EvalItem is a single caveat expression and the request context under which to evaluate it,
+// as one entry in a batch given to EvaluateBatch.
+type EvalItem struct {
+	// Expression is the caveat expression to evaluate.
+	Expression *core.CaveatExpression
+
+	// Context is the request-supplied context for the evaluation.
+	Context map[string]any
+}
+
+// EvalResult is the result of evaluating a single EvalItem. Exactly one of Result or Err is
+// set.
+type EvalResult struct {
+	// Result is the evaluation result for the item, if evaluation succeeded.
+	Result ExpressionResult
+
+	// Err is the error encountered evaluating the item, if any. An error on one item does not
+	// affect the evaluation of the other items in the batch.
+	Err error
+}
+
+// EvaluateBatch evaluates the given items over a bounded number of goroutines, returning the
+// per-item results in the same order as the input items. Evaluation errors are reported
+// per-item rather than failing the batch; only errors loading the referenced caveat
+// definitions fail the batch as a whole.
+//
+// The runner's deserialized caveats are shared across the evaluation goroutines; compiled CEL
+// programs are safe for concurrent use. If an evaluation observer is set on the runner, it
+// must likewise be safe for concurrent invocation.
+func (cr *CaveatRunner) EvaluateBatch(ctx context.Context, items []EvalItem, reader datastore.CaveatReader, concurrency int) ([]EvalResult, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]EvalResult, len(items))
+
+	// Load the definitions for all caveats referenced across the batch in a single lookup.
+	caveatNames := mapz.NewSet[string]()
+	for _, item := range items {
+		if item.Expression == nil {
+			continue
+		}
+		collectCaveatNames(item.Expression, caveatNames)
+	}
+
+	if err := cr.populateCaveatDefinitions(ctx, caveatNames, reader); err != nil {
+		return nil, err
+	}
+
+	// Deserialize each referenced caveat and decode its metadata up front, so that the shared
+	// caches are read-only during the concurrent phase. Caveats that fail to prepare are
+	// reported as errors on the items referencing them.
+	prepareErrors := map[string]error{}
+	for _, caveatName := range caveatNames.AsSlice() {
+		if err := cr.prepare(caveatName); err != nil {
+			prepareErrors[caveatName] = err
+		}
+	}
+
+	pending := make([]int, 0, len(items))
+	for index, item := range items {
+		if item.Expression == nil {
+			results[index] = EvalResult{Err: errors.New("received empty caveat expression")}
+			continue
+		}
+
+		if err := prepareErrorForExpr(item.Expression, prepareErrors); err != nil {
+			results[index] = EvalResult{Err: err}
+			continue
+		}
+
+		pending = append(pending, index)
+	}
+
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	// Evaluate the remaining items over a bounded pool of workers, each with its own local
+	// result cache to avoid contention; the local caches are merged back into the runner once
+	// the batch completes.
+	indexes := make(chan int)
+	workers := make([]*CaveatRunner, min(concurrency, len(pending)))
+
+	var wg sync.WaitGroup
+	for i := range workers {
+		worker := cr.forWorker()
+		workers[i] = worker
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			env := caveats.NewEnvironmentWithTypeSet(cr.caveatTypeSet)
+			for index := range indexes {
+				if err := ctx.Err(); err != nil {
+					results[index] = EvalResult{Err: err}
+					continue
+				}
+
+				result, err := worker.runExpressionWithCaveats(ctx, env, items[index].Expression, items[index].Context, RunCaveatExpressionNoDebugging)
+				results[index] = EvalResult{Result: result, Err: err}
+			}
+		}()
+	}
+
+	for _, index := range pending {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, worker := range workers {
+		maps.Copy(cr.evalResults, worker.evalResults)
+	}
+
+	return results, nil
+}
+
+// populateCaveatDefinitions loads the definitions for the given caveat names into the
+// runner's cache, skipping any already loaded.
+func (cr *CaveatRunner) populateCaveatDefinitions(ctx context.Context, caveatNames *mapz.Set[string], reader datastore.CaveatReader) error {
+	if caveatNames.IsEmpty() {
+		return nil
+	}
+
+	remaining := caveatNames.Copy()
+	for name := range cr.caveatDefs {
+		remaining.Delete(name)
+	}
+
+	if remaining.IsEmpty() {
+		return nil
+	}
+
+	caveatDefs, err := reader.LookupCaveatsWithNames(ctx, remaining.AsSlice())
+	if err != nil {
+		return err
+	}
+
+	for _, cd := range caveatDefs {
+		cr.caveatDefs[cd.Definition.GetName()] = cd.Definition
+	}
+
+	return nil
+}
+
+// prepare deserializes the named caveat and decodes its parameter defaults and
+// relationship-only designations, populating the runner's caches.
+func (cr *CaveatRunner) prepare(caveatName string) error {
+	caveat, _, err := cr.get(caveatName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := cr.parameterDefaults(caveat); err != nil {
+		return err
+	}
+
+	if _, err := cr.relationshipOnlyParameters(caveat); err != nil {
+		return err
+	}
+
+	if _, err := cr.sensitiveParameters(caveatName); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// prepareErrorForExpr returns the preparation error for the first caveat referenced by the
+// given expression that failed to prepare, if any.
+func prepareErrorForExpr(expr *core.CaveatExpression, prepareErrors map[string]error) error {
+	if len(prepareErrors) == 0 {
+		return nil
+	}
+
+	caveatNames := mapz.NewSet[string]()
+	collectCaveatNames(expr, caveatNames)
+	for _, caveatName := range caveatNames.AsSlice() {
+		if err, ok := prepareErrors[caveatName]; ok {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// forWorker returns a runner sharing this runner's prepared caveat caches but holding its own
+// evaluation result cache, for use by a single batch evaluation goroutine.
+func (cr *CaveatRunner) forWorker() *CaveatRunner {
+	return &CaveatRunner{
+		caveatTypeSet:          cr.caveatTypeSet,
+		caveatDefs:             cr.caveatDefs,
+		deserializedCaveats:    cr.deserializedCaveats,
+		caveatDefaults:         cr.caveatDefaults,
+		relationshipOnly:       cr.relationshipOnly,
+		sensitiveParams:        cr.sensitiveParams,
+		relationshipOnlyOption: cr.relationshipOnlyOption,
+		evalResults:            map[string]ExpressionResult{},
+		sharedEvalCache:        cr.sharedEvalCache,
+		observer:               cr.observer,
+	}
+}
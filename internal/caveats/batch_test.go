@@ -0,0 +1,176 @@
+package caveats
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/pkg/caveats/types"
+	"github.com/authzed/spicedb/pkg/datastore"
+)
+
+const batchTestSchema = `
+	caveat some_caveat(somecondition int) {
+		somecondition == 42
+	}
+
+	caveat expected_value(expected string, provided string) {
+		expected == provided
+	}
+	`
+
+func TestEvaluateBatchPreservesOrderAndAggregatesErrors(t *testing.T) {
+	reader := caveatReaderForSchema(t, batchTestSchema)
+
+	items := []EvalItem{
+		// Evaluates to true.
+		{caveatexpr("some_caveat"), map[string]any{"somecondition": int64(42)}},
+
+		// References an unknown caveat; reported as a per-item error.
+		{caveatexpr("unknown_caveat"), map[string]any{}},
+
+		// Evaluates to false.
+		{caveatexpr("some_caveat"), map[string]any{"somecondition": int64(41)}},
+
+		// Missing an expression; reported as a per-item error.
+		{nil, map[string]any{}},
+
+		// Partial, due to missing context.
+		{caveatexpr("expected_value"), map[string]any{"expected": "hi"}},
+
+		// Evaluates to true.
+		{caveatexpr("expected_value"), map[string]any{"expected": "hi", "provided": "hi"}},
+	}
+
+	for _, concurrency := range []int{0, 1, 4} {
+		t.Run(fmt.Sprintf("concurrency-%d", concurrency), func(t *testing.T) {
+			req := require.New(t)
+
+			runner := NewCaveatRunner(types.Default.TypeSet)
+			results, err := runner.EvaluateBatch(t.Context(), items, reader, concurrency)
+			req.NoError(err)
+			req.Len(results, len(items))
+
+			req.NoError(results[0].Err)
+			req.True(results[0].Result.Value())
+
+			req.ErrorContains(results[1].Err, "unknown_caveat")
+
+			req.NoError(results[2].Err)
+			req.False(results[2].Result.Value())
+
+			req.ErrorContains(results[3].Err, "empty caveat expression")
+
+			req.NoError(results[4].Err)
+			req.True(results[4].Result.IsPartial())
+			missingNames, err := results[4].Result.MissingVarNames()
+			req.NoError(err)
+			req.Equal([]string{"provided"}, missingNames)
+
+			req.NoError(results[5].Err)
+			req.True(results[5].Result.Value())
+		})
+	}
+}
+
+func TestEvaluateBatchSharesCompiledProgramsSafely(t *testing.T) {
+	req := require.New(t)
+	reader := caveatReaderForSchema(t, batchTestSchema)
+
+	// All items reference the same caveats, so the deserialized CEL programs are shared across
+	// the evaluation goroutines; run with -race to verify the sharing is safe.
+	items := make([]EvalItem, 0, 1000)
+	for i := range cap(items) {
+		switch i % 3 {
+		case 0:
+			items = append(items, EvalItem{caveatexpr("some_caveat"), map[string]any{"somecondition": int64(i)}})
+
+		case 1:
+			items = append(items, EvalItem{caveatexpr("expected_value"), map[string]any{
+				"expected": "value",
+				"provided": fmt.Sprintf("value%d", i),
+			}})
+
+		default:
+			items = append(items, EvalItem{
+				caveatAnd(caveatexpr("some_caveat"), caveatexpr("expected_value")),
+				map[string]any{"somecondition": int64(42), "expected": "value", "provided": "value"},
+			})
+		}
+	}
+
+	runner := NewCaveatRunner(types.Default.TypeSet)
+	results, err := runner.EvaluateBatch(t.Context(), items, reader, 16)
+	req.NoError(err)
+	req.Len(results, len(items))
+
+	for i, result := range results {
+		req.NoError(result.Err)
+		switch i % 3 {
+		case 0:
+			req.Equal(i == 42, result.Result.Value(), "index %d", i)
+
+		case 1:
+			req.False(result.Result.Value(), "index %d", i)
+
+		default:
+			req.True(result.Result.Value(), "index %d", i)
+		}
+	}
+}
+
+func TestEvaluateBatchUsesLoadedDefinitions(t *testing.T) {
+	req := require.New(t)
+	reader := caveatReaderForSchema(t, batchTestSchema)
+
+	runner := NewCaveatRunner(types.Default.TypeSet)
+	items := []EvalItem{
+		{caveatexpr("some_caveat"), map[string]any{"somecondition": int64(42)}},
+	}
+
+	results, err := runner.EvaluateBatch(t.Context(), items, reader, 2)
+	req.NoError(err)
+	req.True(results[0].Result.Value())
+
+	// Evaluate another batch over a reader that will error if used, ensuring the definitions
+	// loaded by the first batch are reused.
+	var readerDS datastore.Reader = noCaveatsReader{reader}
+	results, err = runner.EvaluateBatch(t.Context(), items, readerDS, 2)
+	req.NoError(err)
+	req.True(results[0].Result.Value())
+}
+
+func BenchmarkEvaluateBatch(b *testing.B) {
+	reader := caveatReaderForSchema(b, batchTestSchema)
+
+	// Use a distinct context per item so that every item requires a CEL evaluation rather than
+	// being served from the evaluation result cache.
+	items := make([]EvalItem, 0, 5000)
+	for i := range cap(items) {
+		items = append(items, EvalItem{
+			caveatexpr("expected_value"),
+			map[string]any{
+				"expected": "value",
+				"provided": fmt.Sprintf("value%d", i),
+			},
+		})
+	}
+
+	for _, concurrency := range []int{1, 2, 4, 8, 16} {
+		b.Run(fmt.Sprintf("concurrency-%d", concurrency), func(b *testing.B) {
+			for b.Loop() {
+				runner := NewCaveatRunner(types.Default.TypeSet)
+				results, err := runner.EvaluateBatch(b.Context(), items, reader, concurrency)
+				if err != nil {
+					b.Fatal(err)
+				}
+				for _, result := range results {
+					if result.Err != nil {
+						b.Fatal(result.Err)
+					}
+				}
+			}
+		})
+	}
+}
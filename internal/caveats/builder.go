@@ -78,11 +78,16 @@ func Or(first *core.CaveatExpression, second *core.CaveatExpression) *core.Cavea
 		return first
 	}
 
+	children := flattenedChildren(core.CaveatOperation_OR, first, second)
+	if len(children) == 1 {
+		return children[0]
+	}
+
 	return &core.CaveatExpression{
 		OperationOrCaveat: &core.CaveatExpression_Operation{
 			Operation: &core.CaveatOperation{
 				Op:       core.CaveatOperation_OR,
-				Children: []*core.CaveatExpression{first, second},
+				Children: children,
 			},
 		},
 	}
@@ -102,16 +107,65 @@ func And(first *core.CaveatExpression, second *core.CaveatExpression) *core.Cave
 		return first
 	}
 
+	children := flattenedChildren(core.CaveatOperation_AND, first, second)
+	if len(children) == 1 {
+		return children[0]
+	}
+
 	return &core.CaveatExpression{
 		OperationOrCaveat: &core.CaveatExpression_Operation{
 			Operation: &core.CaveatOperation{
 				Op:       core.CaveatOperation_AND,
-				Children: []*core.CaveatExpression{first, second},
+				Children: children,
 			},
 		},
 	}
 }
 
+// flattenedChildren flattens any of the given expressions that are themselves operations with
+// the given operator into their children, and drops expressions identical to one already
+// collected, producing the children for a single n-ary operation.
+func flattenedChildren(op core.CaveatOperation_Operation, exprs ...*core.CaveatExpression) []*core.CaveatExpression {
+	collected := make([]*core.CaveatExpression, 0, len(exprs))
+
+	var collect func(expr *core.CaveatExpression)
+	collect = func(expr *core.CaveatExpression) {
+		if operation, ok := expr.OperationOrCaveat.(*core.CaveatExpression_Operation); ok && operation.Operation.Op == op {
+			for _, child := range operation.Operation.Children {
+				collect(child)
+			}
+			return
+		}
+
+		for _, existing := range collected {
+			if existing.EqualVT(expr) {
+				return
+			}
+		}
+		collected = append(collected, expr)
+	}
+
+	for _, expr := range exprs {
+		collect(expr)
+	}
+	return collected
+}
+
+// Not returns the negation of the given caveat expression, eliminating double negation:
+// Not(Not(expr)) returns expr unchanged. If the expression is nil, returns nil.
+func Not(ce *core.CaveatExpression) *core.CaveatExpression {
+	if ce == nil {
+		return nil
+	}
+
+	if operation, ok := ce.OperationOrCaveat.(*core.CaveatExpression_Operation); ok &&
+		operation.Operation.Op == core.CaveatOperation_NOT && len(operation.Operation.Children) == 1 {
+		return operation.Operation.Children[0]
+	}
+
+	return Invert(ce)
+}
+
 // Invert returns the caveat expression with a `!` placed in front of it. If the expression is
 // nil, returns nil.
 func Invert(ce *core.CaveatExpression) *core.CaveatExpression {
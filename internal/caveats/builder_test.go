@@ -84,6 +84,66 @@ func TestOr(t *testing.T) {
 				},
 			},
 		},
+		{
+			MustCaveatExprForTestingWithContext("first", map[string]any{"some": "value"}),
+			MustCaveatExprForTestingWithContext("first", map[string]any{"some": "value"}),
+			MustCaveatExprForTestingWithContext("first", map[string]any{"some": "value"}),
+		},
+		{
+			MustCaveatExprForTestingWithContext("first", map[string]any{"some": "value"}),
+			MustCaveatExprForTestingWithContext("first", map[string]any{"some": "othervalue"}),
+			&core.CaveatExpression{
+				OperationOrCaveat: &core.CaveatExpression_Operation{
+					Operation: &core.CaveatOperation{
+						Op: core.CaveatOperation_OR,
+						Children: []*core.CaveatExpression{
+							MustCaveatExprForTestingWithContext("first", map[string]any{"some": "value"}),
+							MustCaveatExprForTestingWithContext("first", map[string]any{"some": "othervalue"}),
+						},
+					},
+				},
+			},
+		},
+		{
+			Or(CaveatExprForTesting("first"), CaveatExprForTesting("second")),
+			CaveatExprForTesting("third"),
+			&core.CaveatExpression{
+				OperationOrCaveat: &core.CaveatExpression_Operation{
+					Operation: &core.CaveatOperation{
+						Op: core.CaveatOperation_OR,
+						Children: []*core.CaveatExpression{
+							CaveatExprForTesting("first"),
+							CaveatExprForTesting("second"),
+							CaveatExprForTesting("third"),
+						},
+					},
+				},
+			},
+		},
+		{
+			Or(CaveatExprForTesting("first"), CaveatExprForTesting("second")),
+			CaveatExprForTesting("second"),
+			&core.CaveatExpression{
+				OperationOrCaveat: &core.CaveatExpression_Operation{
+					Operation: &core.CaveatOperation{
+						Op:       core.CaveatOperation_OR,
+						Children: []*core.CaveatExpression{CaveatExprForTesting("first"), CaveatExprForTesting("second")},
+					},
+				},
+			},
+		},
+		{
+			Or(CaveatExprForTesting("first"), CaveatExprForTesting("second")),
+			Or(CaveatExprForTesting("second"), CaveatExprForTesting("first")),
+			&core.CaveatExpression{
+				OperationOrCaveat: &core.CaveatExpression_Operation{
+					Operation: &core.CaveatOperation{
+						Op:       core.CaveatOperation_OR,
+						Children: []*core.CaveatExpression{CaveatExprForTesting("first"), CaveatExprForTesting("second")},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range tcs {
@@ -132,6 +192,49 @@ func TestAnd(t *testing.T) {
 				},
 			},
 		},
+		{
+			And(CaveatExprForTesting("first"), CaveatExprForTesting("second")),
+			CaveatExprForTesting("third"),
+			&core.CaveatExpression{
+				OperationOrCaveat: &core.CaveatExpression_Operation{
+					Operation: &core.CaveatOperation{
+						Op: core.CaveatOperation_AND,
+						Children: []*core.CaveatExpression{
+							CaveatExprForTesting("first"),
+							CaveatExprForTesting("second"),
+							CaveatExprForTesting("third"),
+						},
+					},
+				},
+			},
+		},
+		{
+			And(CaveatExprForTesting("first"), CaveatExprForTesting("second")),
+			CaveatExprForTesting("first"),
+			&core.CaveatExpression{
+				OperationOrCaveat: &core.CaveatExpression_Operation{
+					Operation: &core.CaveatOperation{
+						Op:       core.CaveatOperation_AND,
+						Children: []*core.CaveatExpression{CaveatExprForTesting("first"), CaveatExprForTesting("second")},
+					},
+				},
+			},
+		},
+		{
+			Or(CaveatExprForTesting("first"), CaveatExprForTesting("second")),
+			CaveatExprForTesting("first"),
+			&core.CaveatExpression{
+				OperationOrCaveat: &core.CaveatExpression_Operation{
+					Operation: &core.CaveatOperation{
+						Op: core.CaveatOperation_AND,
+						Children: []*core.CaveatExpression{
+							Or(CaveatExprForTesting("first"), CaveatExprForTesting("second")),
+							CaveatExprForTesting("first"),
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range tcs {
@@ -142,6 +245,41 @@ func TestAnd(t *testing.T) {
 	}
 }
 
+func TestNot(t *testing.T) {
+	tcs := []struct {
+		first    *core.CaveatExpression
+		expected *core.CaveatExpression
+	}{
+		{
+			nil,
+			nil,
+		},
+		{
+			CaveatExprForTesting("first"),
+			Invert(CaveatExprForTesting("first")),
+		},
+		{
+			Invert(CaveatExprForTesting("first")),
+			CaveatExprForTesting("first"),
+		},
+		{
+			Not(Not(CaveatExprForTesting("first"))),
+			Invert(CaveatExprForTesting("first")),
+		},
+		{
+			And(CaveatExprForTesting("first"), CaveatExprForTesting("second")),
+			Invert(And(CaveatExprForTesting("first"), CaveatExprForTesting("second"))),
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(fmt.Sprintf("%v", tc.first), func(t *testing.T) {
+			testutil.RequireProtoEqual(t, tc.expected, Not(tc.first), "mismatch")
+		})
+	}
+}
+
 func TestInvert(t *testing.T) {
 	tcs := []struct {
 		first    *core.CaveatExpression
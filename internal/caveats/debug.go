@@ -14,6 +14,209 @@ import (
 	"github.com/authzed/spicedb/pkg/spiceerrors"
 )
 
+// RedactedContextValue is the placeholder reported in debug output in place of context
+// values for parameters designated as sensitive.
+const RedactedContextValue = "(redacted)"
+
+// CaveatStepResult is the tri-state outcome of evaluating a single caveat within a caveat
+// expression.
+type CaveatStepResult int
+
+const (
+	// CaveatStepResultFalse indicates the caveat evaluated to false.
+	CaveatStepResultFalse CaveatStepResult = iota
+
+	// CaveatStepResultTrue indicates the caveat evaluated to true.
+	CaveatStepResultTrue
+
+	// CaveatStepResultPartial indicates the caveat was only partially evaluated, due to
+	// missing context.
+	CaveatStepResultPartial
+)
+
+func (csr CaveatStepResult) String() string {
+	switch csr {
+	case CaveatStepResultFalse:
+		return "false"
+
+	case CaveatStepResultTrue:
+		return "true"
+
+	case CaveatStepResultPartial:
+		return "partial"
+
+	default:
+		return "unknown"
+	}
+}
+
+// CaveatEvalStep describes the evaluation of a single caveat within a larger caveat
+// expression, for inclusion in debug traces.
+type CaveatEvalStep struct {
+	// CaveatName is the name of the evaluated caveat.
+	CaveatName string
+
+	// Result is whether the caveat evaluated to true, false or partially.
+	Result CaveatStepResult
+
+	// MissingFields holds the names of the context fields whose absence made the evaluation
+	// partial. Empty unless Result is CaveatStepResultPartial.
+	MissingFields []string
+
+	// ResidualExpression is the expression remaining after evaluation, rendered via the
+	// pretty-printer: the residual expression for partial results, or the caveat's full
+	// expression otherwise.
+	ResidualExpression string
+
+	// Context holds the context values used for the evaluation, with the values of
+	// parameters designated sensitive replaced by RedactedContextValue.
+	Context *structpb.Struct
+}
+
+// BuildEvaluationSteps returns one CaveatEvalStep per caveat evaluated within the given
+// result, in evaluation order. The result must have been produced by this runner with
+// debugging enabled.
+func (cr *CaveatRunner) BuildEvaluationSteps(exprResult ExpressionResult) ([]CaveatEvalStep, error) {
+	steps := []CaveatEvalStep{}
+	if err := cr.collectEvaluationSteps(exprResult, &steps); err != nil {
+		return nil, err
+	}
+
+	return steps, nil
+}
+
+func (cr *CaveatRunner) collectEvaluationSteps(exprResult ExpressionResult, steps *[]CaveatEvalStep) error {
+	if concrete, ok := exprResult.(*caveats.CaveatResult); ok {
+		step, err := cr.evaluationStepForLeaf(concrete)
+		if err != nil {
+			return err
+		}
+
+		*steps = append(*steps, step)
+		return nil
+	}
+
+	sr, ok := exprResult.(syntheticResult)
+	if !ok {
+		return spiceerrors.MustBugf("unknown ExpressionResult type: %T", exprResult)
+	}
+
+	for _, child := range sr.exprResultsForDebug {
+		if err := cr.collectEvaluationSteps(child, steps); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EvaluationStepForResult returns the CaveatEvalStep for the given single-caveat evaluation
+// result, if the result is for a single caveat; operation results return false.
+func (cr *CaveatRunner) EvaluationStepForResult(result ExpressionResult) (CaveatEvalStep, bool, error) {
+	concrete, ok := result.(*caveats.CaveatResult)
+	if !ok {
+		return CaveatEvalStep{}, false, nil
+	}
+
+	step, err := cr.evaluationStepForLeaf(concrete)
+	if err != nil {
+		return CaveatEvalStep{}, false, err
+	}
+
+	return step, true, nil
+}
+
+func (cr *CaveatRunner) evaluationStepForLeaf(result *caveats.CaveatResult) (CaveatEvalStep, error) {
+	caveatName := result.ParentCaveat().Name()
+
+	stepResult := CaveatStepResultFalse
+	var missingFields []string
+	var residualExpression string
+
+	if result.IsPartial() {
+		stepResult = CaveatStepResultPartial
+
+		missingNames, err := result.MissingVarNames()
+		if err != nil {
+			return CaveatEvalStep{}, err
+		}
+		missingFields = missingNames
+
+		partialValue, err := result.PartialValue()
+		if err != nil {
+			return CaveatEvalStep{}, err
+		}
+
+		residualExpression, err = partialValue.ExprString()
+		if err != nil {
+			return CaveatEvalStep{}, err
+		}
+	} else {
+		if result.Value() {
+			stepResult = CaveatStepResultTrue
+		}
+
+		exprString, err := result.ExpressionString()
+		if err != nil {
+			return CaveatEvalStep{}, err
+		}
+		residualExpression = exprString
+	}
+
+	contextStruct, err := result.ContextStruct()
+	if err != nil {
+		return CaveatEvalStep{}, err
+	}
+
+	if err := cr.redactSensitiveFields(caveatName, contextStruct); err != nil {
+		return CaveatEvalStep{}, err
+	}
+
+	return CaveatEvalStep{
+		CaveatName:         caveatName,
+		Result:             stepResult,
+		MissingFields:      missingFields,
+		ResidualExpression: residualExpression,
+		Context:            contextStruct,
+	}, nil
+}
+
+// RedactSensitiveContext replaces, in the given context struct, the values of any parameters
+// designated as sensitive by the caveats referenced in the given expression.
+func (cr *CaveatRunner) RedactSensitiveContext(expr *corev1.CaveatExpression, context *structpb.Struct) error {
+	caveatNames := mapz.NewSet[string]()
+	collectCaveatNames(expr, caveatNames)
+
+	for _, caveatName := range caveatNames.AsSlice() {
+		if err := cr.redactSensitiveFields(caveatName, context); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// redactSensitiveFields replaces the values of the given context struct's fields designated
+// as sensitive by the named caveat with RedactedContextValue.
+func (cr *CaveatRunner) redactSensitiveFields(caveatName string, context *structpb.Struct) error {
+	if context == nil {
+		return nil
+	}
+
+	sensitiveNames, err := cr.sensitiveParameters(caveatName)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range sensitiveNames {
+		if _, ok := context.Fields[name]; ok {
+			context.Fields[name] = structpb.NewStringValue(RedactedContextValue)
+		}
+	}
+
+	return nil
+}
+
 // BuildDebugInformation returns a human-readable string representation of the given
 // ExpressionResult and a Struct representation of the context values used in the expression.
 func BuildDebugInformation(exprResult ExpressionResult) (string, *structpb.Struct, error) {
@@ -1,15 +1,22 @@
 package caveats
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/authzed/spicedb/internal/datastore/dsfortesting"
+	"github.com/authzed/spicedb/internal/datastore/memdb"
 	"github.com/authzed/spicedb/pkg/caveats"
 	"github.com/authzed/spicedb/pkg/caveats/types"
+	"github.com/authzed/spicedb/pkg/datastore"
 	"github.com/authzed/spicedb/pkg/genutil/mapz"
+	"github.com/authzed/spicedb/pkg/namespace"
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/schemadsl/compiler"
+	"github.com/authzed/spicedb/pkg/schemadsl/input"
 )
 
 var testTime = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
@@ -245,6 +252,167 @@ func TestBuildDebugInformation(t *testing.T) {
 	}
 }
 
+func TestBuildEvaluationSteps(t *testing.T) {
+	req := require.New(t)
+
+	reader := caveatReaderForSchema(t, `
+		caveat always_true(first int) {
+			first == 42
+		}
+
+		caveat needs_context(second string) {
+			second == "hello"
+		}
+		`)
+
+	runner := NewCaveatRunner(types.Default.TypeSet)
+	result, err := runner.RunCaveatExpression(
+		t.Context(),
+		And(caveatexpr("always_true"), caveatexpr("needs_context")),
+		map[string]any{
+			"first": "42",
+		},
+		reader,
+		RunCaveatExpressionWithDebugInformation,
+	)
+	req.NoError(err)
+	req.True(result.IsPartial())
+
+	steps, err := runner.BuildEvaluationSteps(result)
+	req.NoError(err)
+	req.Len(steps, 2)
+
+	// An AND of one true caveat and one partial caveat yields exactly one partial leaf.
+	req.Equal("always_true", steps[0].CaveatName)
+	req.Equal(CaveatStepResultTrue, steps[0].Result)
+	req.Empty(steps[0].MissingFields)
+	req.Equal("first == 42", steps[0].ResidualExpression)
+
+	req.Equal("needs_context", steps[1].CaveatName)
+	req.Equal(CaveatStepResultPartial, steps[1].Result)
+	req.Equal([]string{"second"}, steps[1].MissingFields)
+	req.Equal(`second == "hello"`, steps[1].ResidualExpression)
+}
+
+func TestBuildEvaluationStepsForFalseResult(t *testing.T) {
+	req := require.New(t)
+
+	reader := caveatReaderForSchema(t, `
+		caveat some_caveat(somecondition int) {
+			somecondition == 42
+		}
+		`)
+
+	runner := NewCaveatRunner(types.Default.TypeSet)
+	result, err := runner.RunCaveatExpression(
+		t.Context(),
+		caveatexpr("some_caveat"),
+		map[string]any{
+			"somecondition": "41",
+		},
+		reader,
+		RunCaveatExpressionWithDebugInformation,
+	)
+	req.NoError(err)
+	req.False(result.Value())
+
+	steps, err := runner.BuildEvaluationSteps(result)
+	req.NoError(err)
+	req.Len(steps, 1)
+	req.Equal("some_caveat", steps[0].CaveatName)
+	req.Equal(CaveatStepResultFalse, steps[0].Result)
+	req.Empty(steps[0].MissingFields)
+	req.Equal("somecondition == 42", steps[0].ResidualExpression)
+}
+
+func TestBuildEvaluationStepsRedactsSensitiveContext(t *testing.T) {
+	req := require.New(t)
+
+	reader := caveatReaderWithSensitiveParameters(t, `
+		caveat token_match(token string, expected string) {
+			token == expected
+		}
+		`, "token_match", []string{"token"})
+
+	runner := NewCaveatRunner(types.Default.TypeSet)
+	result, err := runner.RunCaveatExpression(
+		t.Context(),
+		caveatexpr("token_match"),
+		map[string]any{
+			"token":    "super-secret",
+			"expected": "super-secret",
+		},
+		reader,
+		RunCaveatExpressionWithDebugInformation,
+	)
+	req.NoError(err)
+	req.True(result.Value())
+
+	steps, err := runner.BuildEvaluationSteps(result)
+	req.NoError(err)
+	req.Len(steps, 1)
+
+	fields := steps[0].Context.GetFields()
+	req.Equal(RedactedContextValue, fields["token"].GetStringValue())
+	req.Equal("super-secret", fields["expected"].GetStringValue())
+}
+
+func TestRedactSensitiveContext(t *testing.T) {
+	req := require.New(t)
+
+	reader := caveatReaderWithSensitiveParameters(t, `
+		caveat token_match(token string, expected string) {
+			token == expected
+		}
+		`, "token_match", []string{"token"})
+
+	runner := NewCaveatRunner(types.Default.TypeSet)
+	expr := caveatexpr("token_match")
+	result, err := runner.RunCaveatExpression(t.Context(), expr, map[string]any{
+		"token":    "super-secret",
+		"expected": "super-secret",
+	}, reader, RunCaveatExpressionWithDebugInformation)
+	req.NoError(err)
+
+	_, contextStruct, err := BuildDebugInformation(result)
+	req.NoError(err)
+
+	req.NoError(runner.RedactSensitiveContext(expr, contextStruct))
+
+	fields := contextStruct.GetFields()
+	req.Equal(RedactedContextValue, fields["token"].GetStringValue())
+	req.Equal("super-secret", fields["expected"].GetStringValue())
+}
+
+// caveatReaderWithSensitiveParameters compiles the given schema, designates the given
+// parameters of the named caveat as sensitive, and returns a reader over the result.
+func caveatReaderWithSensitiveParameters(t *testing.T, schema string, caveatName string, parameterNames []string) datastore.Reader {
+	req := require.New(t)
+
+	compiled, err := compiler.Compile(compiler.InputSchema{
+		Source:       input.Source("schema"),
+		SchemaString: schema,
+	}, compiler.AllowUnprefixedObjectType())
+	req.NoError(err)
+
+	for _, def := range compiled.CaveatDefinitions {
+		if def.Name == caveatName {
+			def.Metadata, err = namespace.SetSensitiveCaveatParameters(def.Metadata, parameterNames)
+			req.NoError(err)
+		}
+	}
+
+	ds, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	req.NoError(err)
+
+	revision, err := ds.ReadWriteTx(t.Context(), func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.WriteCaveats(ctx, compiled.CaveatDefinitions)
+	})
+	req.NoError(err)
+
+	return ds.SnapshotReader(revision)
+}
+
 func and(opFuncs ...func(t *testing.T) ExpressionResult) func(t *testing.T) ExpressionResult {
 	return func(t *testing.T) ExpressionResult {
 		ops := make([]ExpressionResult, 0, len(opFuncs))
@@ -0,0 +1,123 @@
+package caveats
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/authzed/spicedb/pkg/caveats"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+// NormalizeExpression returns the normal form of the given caveat expression: same-operator
+// AND/OR nesting is flattened, commutative operands are sorted by a canonical leaf ordering
+// and deduplicated, and NOT operands are normalized in place. Since NOT is the only
+// non-commutative operator, exclusion operand order is preserved. The given expression is
+// not modified; a nil expression normalizes to nil.
+func NormalizeExpression(expr *core.CaveatExpression) *core.CaveatExpression {
+	if expr == nil {
+		return nil
+	}
+
+	if expr.GetCaveat() != nil {
+		return expr
+	}
+
+	operation := expr.GetOperation()
+	if operation.Op == core.CaveatOperation_NOT {
+		normalizedChildren := make([]*core.CaveatExpression, 0, len(operation.Children))
+		for _, child := range operation.Children {
+			normalizedChildren = append(normalizedChildren, NormalizeExpression(child))
+		}
+
+		return &core.CaveatExpression{
+			OperationOrCaveat: &core.CaveatExpression_Operation{
+				Operation: &core.CaveatOperation{
+					Op:       core.CaveatOperation_NOT,
+					Children: normalizedChildren,
+				},
+			},
+		}
+	}
+
+	// Flatten same-operator nesting into a single n-ary operation over normalized children.
+	flattened := make([]*core.CaveatExpression, 0, len(operation.Children))
+	var collect func(child *core.CaveatExpression)
+	collect = func(child *core.CaveatExpression) {
+		normalized := NormalizeExpression(child)
+		if childOp := normalized.GetOperation(); childOp != nil && childOp.Op == operation.Op {
+			for _, grandchild := range childOp.Children {
+				collect(grandchild)
+			}
+			return
+		}
+		flattened = append(flattened, normalized)
+	}
+	for _, child := range operation.Children {
+		collect(child)
+	}
+
+	// Sort the commutative operands by their canonical keys and deduplicate.
+	slices.SortStableFunc(flattened, func(first *core.CaveatExpression, second *core.CaveatExpression) int {
+		return strings.Compare(canonicalExpressionKey(first), canonicalExpressionKey(second))
+	})
+
+	deduplicated := make([]*core.CaveatExpression, 0, len(flattened))
+	for _, child := range flattened {
+		if len(deduplicated) > 0 && deduplicated[len(deduplicated)-1].EqualVT(child) {
+			continue
+		}
+		deduplicated = append(deduplicated, child)
+	}
+
+	if len(deduplicated) == 1 {
+		return deduplicated[0]
+	}
+
+	return &core.CaveatExpression{
+		OperationOrCaveat: &core.CaveatExpression_Operation{
+			Operation: &core.CaveatOperation{
+				Op:       operation.Op,
+				Children: deduplicated,
+			},
+		},
+	}
+}
+
+// ExpressionsEquivalent returns whether the two caveat expressions are semantically equivalent
+// up to commutativity and duplication of AND/OR operands, by comparing their normal forms.
+// Unlike EqualVT, `Or(a, b)` and `Or(b, a)` compare as equivalent.
+func ExpressionsEquivalent(first *core.CaveatExpression, second *core.CaveatExpression) bool {
+	if first == nil || second == nil {
+		return first == nil && second == nil
+	}
+
+	return NormalizeExpression(first).EqualVT(NormalizeExpression(second))
+}
+
+// canonicalExpressionKey returns a deterministic string key for the given (already normalized)
+// expression, used as the sort order for commutative operands.
+func canonicalExpressionKey(expr *core.CaveatExpression) string {
+	var b strings.Builder
+	appendCanonicalExpressionKey(&b, expr)
+	return b.String()
+}
+
+func appendCanonicalExpressionKey(b *strings.Builder, expr *core.CaveatExpression) {
+	if caveat := expr.GetCaveat(); caveat != nil {
+		b.WriteString(caveat.CaveatName)
+		b.WriteString("|")
+		b.WriteString(caveats.StableContextStringForHashing(caveat.GetContext()))
+		return
+	}
+
+	operation := expr.GetOperation()
+	b.WriteString(operation.Op.String())
+	b.WriteString("(")
+	for index, child := range operation.Children {
+		if index > 0 {
+			b.WriteString(",")
+		}
+		appendCanonicalExpressionKey(b, child)
+	}
+	b.WriteString(")")
+}
@@ -0,0 +1,188 @@
+package caveats
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+func opExpr(op core.CaveatOperation_Operation, children ...*core.CaveatExpression) *core.CaveatExpression {
+	return &core.CaveatExpression{
+		OperationOrCaveat: &core.CaveatExpression_Operation{
+			Operation: &core.CaveatOperation{
+				Op:       op,
+				Children: children,
+			},
+		},
+	}
+}
+
+func TestExpressionsEquivalent(t *testing.T) {
+	tcs := []struct {
+		name       string
+		first      *core.CaveatExpression
+		second     *core.CaveatExpression
+		equivalent bool
+	}{
+		{
+			"nil expressions",
+			nil,
+			nil,
+			true,
+		},
+		{
+			"nil vs non-nil",
+			nil,
+			caveatexpr("first"),
+			false,
+		},
+		{
+			"identical leaves",
+			caveatexpr("first"),
+			caveatexpr("first"),
+			true,
+		},
+		{
+			"different leaves",
+			caveatexpr("first"),
+			caveatexpr("second"),
+			false,
+		},
+		{
+			"same leaf with different context",
+			MustCaveatExprForTestingWithContext("first", map[string]any{"some": "value"}),
+			MustCaveatExprForTestingWithContext("first", map[string]any{"some": "othervalue"}),
+			false,
+		},
+		{
+			"commuted or",
+			opExpr(core.CaveatOperation_OR, caveatexpr("first"), caveatexpr("second")),
+			opExpr(core.CaveatOperation_OR, caveatexpr("second"), caveatexpr("first")),
+			true,
+		},
+		{
+			"commuted and",
+			opExpr(core.CaveatOperation_AND, caveatexpr("first"), caveatexpr("second")),
+			opExpr(core.CaveatOperation_AND, caveatexpr("second"), caveatexpr("first")),
+			true,
+		},
+		{
+			"and vs or",
+			opExpr(core.CaveatOperation_AND, caveatexpr("first"), caveatexpr("second")),
+			opExpr(core.CaveatOperation_OR, caveatexpr("first"), caveatexpr("second")),
+			false,
+		},
+		{
+			"flattened nesting",
+			opExpr(core.CaveatOperation_OR, caveatexpr("first"), opExpr(core.CaveatOperation_OR, caveatexpr("second"), caveatexpr("third"))),
+			opExpr(core.CaveatOperation_OR, opExpr(core.CaveatOperation_OR, caveatexpr("third"), caveatexpr("first")), caveatexpr("second")),
+			true,
+		},
+		{
+			"duplicated operand",
+			opExpr(core.CaveatOperation_OR, caveatexpr("first"), caveatexpr("first")),
+			caveatexpr("first"),
+			true,
+		},
+		{
+			"exclusion operand order is preserved",
+			Subtract(caveatexpr("first"), caveatexpr("second")),
+			Subtract(caveatexpr("second"), caveatexpr("first")),
+			false,
+		},
+		{
+			"commuted exclusion operands",
+			Subtract(opExpr(core.CaveatOperation_OR, caveatexpr("first"), caveatexpr("second")), caveatexpr("third")),
+			Subtract(opExpr(core.CaveatOperation_OR, caveatexpr("second"), caveatexpr("first")), caveatexpr("third")),
+			true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.equivalent, ExpressionsEquivalent(tc.first, tc.second))
+			require.Equal(t, tc.equivalent, ExpressionsEquivalent(tc.second, tc.first))
+		})
+	}
+}
+
+func TestNormalizeExpressionDoesNotModifyInput(t *testing.T) {
+	expr := opExpr(core.CaveatOperation_OR, caveatexpr("second"), caveatexpr("first"))
+	cloned := expr.CloneVT()
+
+	normalized := NormalizeExpression(expr)
+	require.True(t, expr.EqualVT(cloned), "input expression was modified")
+	require.Equal(t, "first", normalized.GetOperation().Children[0].GetCaveat().CaveatName)
+	require.Equal(t, "second", normalized.GetOperation().Children[1].GetCaveat().CaveatName)
+}
+
+// randomExpression generates a random caveat expression tree of the given depth, without going
+// through the normalizing And/Or constructors.
+func randomExpression(r *rand.Rand, depth int) *core.CaveatExpression {
+	if depth == 0 || r.Intn(3) == 0 {
+		name := fmt.Sprintf("caveat%d", r.Intn(5))
+		if r.Intn(2) == 0 {
+			return MustCaveatExprForTestingWithContext(name, map[string]any{
+				"value": int64(r.Intn(3)),
+			})
+		}
+		return caveatexpr(name)
+	}
+
+	switch r.Intn(3) {
+	case 0:
+		return opExpr(core.CaveatOperation_NOT, randomExpression(r, depth-1))
+
+	case 1:
+		return opExpr(core.CaveatOperation_AND, randomExpression(r, depth-1), randomExpression(r, depth-1))
+
+	default:
+		return opExpr(core.CaveatOperation_OR, randomExpression(r, depth-1), randomExpression(r, depth-1))
+	}
+}
+
+// shuffleExpression returns a copy of the given expression with the operands of commutative
+// operations randomly reordered and occasionally duplicated.
+func shuffleExpression(r *rand.Rand, expr *core.CaveatExpression) *core.CaveatExpression {
+	operation := expr.GetOperation()
+	if operation == nil {
+		return expr
+	}
+
+	shuffled := make([]*core.CaveatExpression, 0, len(operation.Children)+1)
+	for _, child := range operation.Children {
+		shuffled = append(shuffled, shuffleExpression(r, child))
+	}
+
+	if operation.Op != core.CaveatOperation_NOT {
+		if r.Intn(4) == 0 {
+			shuffled = append(shuffled, shuffled[r.Intn(len(shuffled))])
+		}
+		r.Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		})
+	}
+
+	return opExpr(operation.Op, shuffled...)
+}
+
+func TestExpressionsEquivalentOnShuffledTrees(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	for i := 0; i < 500; i++ {
+		expr := randomExpression(r, 4)
+		shuffled := shuffleExpression(r, expr)
+		require.True(t, ExpressionsEquivalent(expr, shuffled), "expected equivalence between %s and %s", StringifyExpression(expr), StringifyExpression(shuffled))
+	}
+
+	// Trees over disjoint caveat names are never equivalent.
+	for i := 0; i < 100; i++ {
+		expr := randomExpression(r, 3)
+		other := opExpr(core.CaveatOperation_OR, caveatexpr("unrelated"), randomExpression(r, 2))
+		require.False(t, ExpressionsEquivalent(expr, other), "expected no equivalence between %s and %s", StringifyExpression(expr), StringifyExpression(other))
+	}
+}
@@ -93,6 +93,52 @@ func (err ParameterTypeError) GRPCStatus() *status.Status {
 	)
 }
 
+// RelationshipOnlyParameterError is an error raised when request context supplies a value for
+// a caveat parameter that may only be supplied via relationship context.
+type RelationshipOnlyParameterError struct {
+	error
+	caveatExpr    *core.CaveatExpression
+	parameterName string
+}
+
+// MarshalZerologObject implements zerolog.LogObjectMarshaler
+func (err RelationshipOnlyParameterError) MarshalZerologObject(e *zerolog.Event) {
+	e.Err(err.error).
+		Str("caveat_name", err.caveatExpr.GetCaveat().CaveatName).
+		Str("parameter_name", err.parameterName)
+}
+
+// DetailsMetadata returns the metadata for details for this error.
+func (err RelationshipOnlyParameterError) DetailsMetadata() map[string]string {
+	return map[string]string{
+		"caveat_name":    err.caveatExpr.GetCaveat().CaveatName,
+		"parameter_name": err.parameterName,
+	}
+}
+
+func (err RelationshipOnlyParameterError) GRPCStatus() *status.Status {
+	return spiceerrors.WithCodeAndDetails(
+		err,
+		codes.InvalidArgument,
+		spiceerrors.ForReason(
+			v1.ErrorReason_ERROR_REASON_CAVEAT_PARAMETER_TYPE_ERROR,
+			err.DetailsMetadata(),
+		),
+	)
+}
+
+func NewRelationshipOnlyParameterError(caveatExpr *core.CaveatExpression, parameterName string) RelationshipOnlyParameterError {
+	return RelationshipOnlyParameterError{
+		fmt.Errorf(
+			"the parameter `%s` for caveat `%s` may only be supplied via relationship context",
+			parameterName,
+			caveatExpr.GetCaveat().CaveatName,
+		),
+		caveatExpr,
+		parameterName,
+	}
+}
+
 func NewParameterTypeError(caveatExpr *core.CaveatExpression, err error) ParameterTypeError {
 	conversionError := &caveats.ParameterConversionError{}
 	if !errors.As(err, conversionError) {
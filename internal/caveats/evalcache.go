@@ -0,0 +1,177 @@
+package caveats
+
+import (
+	"container/list"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EvaluationResultCache is an optional process-wide cache of caveat evaluation results, with
+// LRU eviction and a per-entry TTL. A CaveatRunner always caches within its own scope (one
+// runner per request); attaching one of these via NewCaveatRunnerWithSharedCache additionally
+// shares results across runners. Entries are keyed by the caveat name, a digest of its
+// serialized expression, and a canonical serialization of the full evaluation context, so a
+// schema or context change always misses.
+type EvaluationResultCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	entries    map[string]*list.Element // GUARDED_BY(mu)
+	order      *list.List               // GUARDED_BY(mu)
+
+	// now is replaceable for tests.
+	now func() time.Time
+}
+
+type evaluationResultCacheEntry struct {
+	key       string
+	result    ExpressionResult
+	expiresAt time.Time
+}
+
+// NewEvaluationResultCache constructs an empty cache holding at most maxEntries results, each
+// expiring after the given TTL.
+func NewEvaluationResultCache(maxEntries int, ttl time.Duration) *EvaluationResultCache {
+	return &EvaluationResultCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		entries:    make(map[string]*list.Element, maxEntries),
+		order:      list.New(),
+		now:        time.Now,
+	}
+}
+
+func (c *EvaluationResultCache) get(key string) (ExpressionResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*evaluationResultCacheEntry)
+	if c.now().After(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.result, true
+}
+
+func (c *EvaluationResultCache) set(key string, result ExpressionResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&evaluationResultCacheEntry{
+		key:       key,
+		result:    result,
+		expiresAt: c.now().Add(c.ttl),
+	})
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*evaluationResultCacheEntry).key)
+	}
+}
+
+// evaluationCacheKey returns a stable cache key for evaluating the named caveat, with the
+// given serialized expression, over the given full context map.
+func evaluationCacheKey(caveatName string, serializedExpression []byte, context map[string]any) (string, error) {
+	exprHasher := fnv.New64a()
+	if _, err := exprHasher.Write(serializedExpression); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(caveatName)
+	b.WriteString("@")
+	b.WriteString(hex.EncodeToString(exprHasher.Sum(nil)))
+	b.WriteString("|")
+	if err := appendContextMapToKey(&b, context); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// appendContextMapToKey serializes the map with sorted keys, so that equal maps always produce
+// the same key regardless of iteration order.
+func appendContextMapToKey(b *strings.Builder, context map[string]any) error {
+	keys := slices.Collect(maps.Keys(context))
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		b.WriteString("`")
+		b.WriteString(url.PathEscape(key))
+		b.WriteString("`:")
+		if err := appendContextValueToKey(b, context[key]); err != nil {
+			return err
+		}
+		b.WriteString(",")
+	}
+	return nil
+}
+
+// appendContextValueToKey serializes a single context value, tagged by kind so that values of
+// different types never collide: `i:1` (int64) is distinct from `d:1` (double).
+func appendContextValueToKey(b *strings.Builder, value any) error {
+	switch v := value.(type) {
+	case nil:
+		b.WriteString("null")
+
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+
+	case string:
+		b.WriteString("`")
+		b.WriteString(url.PathEscape(v))
+		b.WriteString("`")
+
+	case int, int32, int64, uint, uint32, uint64:
+		fmt.Fprintf(b, "i:%d", v)
+
+	case float32:
+		b.WriteString("d:")
+		b.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 64))
+
+	case float64:
+		b.WriteString("d:")
+		b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+
+	case []any:
+		b.WriteString("[")
+		for _, item := range v {
+			if err := appendContextValueToKey(b, item); err != nil {
+				return err
+			}
+			b.WriteString(",")
+		}
+		b.WriteString("]")
+
+	case map[string]any:
+		b.WriteString("{")
+		if err := appendContextMapToKey(b, v); err != nil {
+			return err
+		}
+		b.WriteString("}")
+
+	default:
+		return fmt.Errorf("uncacheable context value of type %T", value)
+	}
+	return nil
+}
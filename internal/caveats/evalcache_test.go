@@ -0,0 +1,196 @@
+package caveats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/internal/datastore/dsfortesting"
+	"github.com/authzed/spicedb/internal/datastore/memdb"
+	"github.com/authzed/spicedb/internal/testfixtures"
+	"github.com/authzed/spicedb/pkg/caveats/types"
+	"github.com/authzed/spicedb/pkg/datastore"
+)
+
+func TestEvaluationCacheKeyDistinguishesValueTypes(t *testing.T) {
+	intKey, err := evaluationCacheKey("somecaveat", []byte("expr"), map[string]any{"value": int64(1)})
+	require.NoError(t, err)
+
+	doubleKey, err := evaluationCacheKey("somecaveat", []byte("expr"), map[string]any{"value": float64(1)})
+	require.NoError(t, err)
+	require.NotEqual(t, intKey, doubleKey)
+
+	stringKey, err := evaluationCacheKey("somecaveat", []byte("expr"), map[string]any{"value": "1"})
+	require.NoError(t, err)
+	require.NotEqual(t, intKey, stringKey)
+	require.NotEqual(t, doubleKey, stringKey)
+}
+
+func TestEvaluationCacheKeyIsOrderIndependent(t *testing.T) {
+	first, err := evaluationCacheKey("somecaveat", []byte("expr"), map[string]any{"a": "1", "b": "2", "c": "3"})
+	require.NoError(t, err)
+
+	second, err := evaluationCacheKey("somecaveat", []byte("expr"), map[string]any{"c": "3", "b": "2", "a": "1"})
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+}
+
+func TestEvaluationCacheKeyDistinguishesNestedStructFields(t *testing.T) {
+	first, err := evaluationCacheKey("somecaveat", []byte("expr"), map[string]any{
+		"themap": map[string]any{"first": int64(42)},
+	})
+	require.NoError(t, err)
+
+	second, err := evaluationCacheKey("somecaveat", []byte("expr"), map[string]any{
+		"themap": map[string]any{"first": int64(41)},
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, first, second)
+}
+
+func caveatReaderForSchema(t testing.TB, schema string) datastore.Reader {
+	req := require.New(t)
+
+	rawDS, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	req.NoError(err)
+
+	ds, _ := testfixtures.DatastoreFromSchemaAndTestRelationships(rawDS, schema, nil, req)
+
+	headRevision, err := ds.HeadRevision(t.Context())
+	req.NoError(err)
+
+	return ds.SnapshotReader(headRevision)
+}
+
+func TestCaveatRunnerCachesEvaluationResults(t *testing.T) {
+	req := require.New(t)
+
+	reader := caveatReaderForSchema(t, `
+		caveat firstCaveat(first int) {
+			first == 42
+		}
+		`)
+
+	runner := NewCaveatRunner(types.Default.TypeSet)
+
+	result, err := runner.RunCaveatExpression(t.Context(), caveatexpr("firstCaveat"), map[string]any{
+		"first": "42",
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.True(result.Value())
+	req.Len(runner.evalResults, 1)
+
+	// A second evaluation over the same context is served from the cache.
+	cachedResult, err := runner.RunCaveatExpression(t.Context(), caveatexpr("firstCaveat"), map[string]any{
+		"first": "42",
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.Same(result, cachedResult)
+	req.Len(runner.evalResults, 1)
+}
+
+func TestCaveatRunnerCacheNestedContexts(t *testing.T) {
+	req := require.New(t)
+
+	reader := caveatReaderForSchema(t, `
+		caveat some_caveat(themap map<any>) {
+			themap.first == 42
+		}
+		`)
+
+	// Contexts differing only in a nested struct field must never share results.
+	runner := NewCaveatRunner(types.Default.TypeSet)
+
+	result, err := runner.RunCaveatExpression(t.Context(), caveatexpr("some_caveat"), map[string]any{
+		"themap": map[string]any{"first": int64(42)},
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.True(result.Value())
+
+	otherResult, err := runner.RunCaveatExpression(t.Context(), caveatexpr("some_caveat"), map[string]any{
+		"themap": map[string]any{"first": int64(41)},
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.False(otherResult.Value())
+	req.Len(runner.evalResults, 2)
+}
+
+func TestSharedEvaluationCache(t *testing.T) {
+	req := require.New(t)
+
+	reader := caveatReaderForSchema(t, `
+		caveat firstCaveat(first int) {
+			first == 42
+		}
+		`)
+
+	currentTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	shared := NewEvaluationResultCache(10, time.Minute)
+	shared.now = func() time.Time { return currentTime }
+
+	firstRunner := NewCaveatRunnerWithSharedCache(types.Default.TypeSet, shared)
+	result, err := firstRunner.RunCaveatExpression(t.Context(), caveatexpr("firstCaveat"), map[string]any{
+		"first": "42",
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.True(result.Value())
+
+	// A different runner sharing the cache reuses the result.
+	secondRunner := NewCaveatRunnerWithSharedCache(types.Default.TypeSet, shared)
+	cachedResult, err := secondRunner.RunCaveatExpression(t.Context(), caveatexpr("firstCaveat"), map[string]any{
+		"first": "42",
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.Same(result, cachedResult)
+
+	// Once the TTL elapses, the shared entry is dropped and recomputed.
+	currentTime = currentTime.Add(2 * time.Minute)
+	thirdRunner := NewCaveatRunnerWithSharedCache(types.Default.TypeSet, shared)
+	recomputed, err := thirdRunner.RunCaveatExpression(t.Context(), caveatexpr("firstCaveat"), map[string]any{
+		"first": "42",
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.NotSame(result, recomputed)
+	req.True(recomputed.Value())
+}
+
+func BenchmarkBulkCaveatEvaluationSharedCaveat(b *testing.B) {
+	reader := caveatReaderForSchema(b, `
+		caveat firstCaveat(first int) {
+			first == 42
+		}
+		`)
+
+	context := map[string]any{"first": "42"}
+
+	// A bulk check evaluating one shared caveat for 10k candidate resources.
+	const itemCount = 10_000
+
+	b.Run("without result caching", func(b *testing.B) {
+		runner := NewCaveatRunner(types.Default.TypeSet)
+		runner.evalResults = nil
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for range itemCount {
+				if _, err := runner.RunCaveatExpression(b.Context(), caveatexpr("firstCaveat"), context, reader, RunCaveatExpressionNoDebugging); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("with result caching", func(b *testing.B) {
+		runner := NewCaveatRunner(types.Default.TypeSet)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for range itemCount {
+				if _, err := runner.RunCaveatExpression(b.Context(), caveatexpr("firstCaveat"), context, reader, RunCaveatExpressionNoDebugging); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package caveats
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+// MergeORedCaveats returns a copy of the given expression in which leaves of an OR that
+// reference the same caveat are merged into a single leaf, when every context parameter
+// whose values differ between the leaves is list-typed: the lists are concatenated and
+// deduplicated. Leaves are left untouched when no parameter type information is available
+// for their caveat, when the leaves disagree on which parameters are set, or when a
+// differing parameter is scalar.
+//
+// This rewrite is only valid for caveats that use their list parameters monotonically
+// (e.g. membership checks such as `ip in allowed_ips`), which is why it is a separate,
+// opt-in pass rather than part of the Or constructor.
+func MergeORedCaveats(expr *core.CaveatExpression, parameterTypes map[string]map[string]*core.CaveatTypeReference) *core.CaveatExpression {
+	if expr == nil {
+		return nil
+	}
+
+	operation := expr.GetOperation()
+	if operation == nil {
+		return expr
+	}
+
+	children := make([]*core.CaveatExpression, 0, len(operation.Children))
+	for _, child := range operation.Children {
+		children = append(children, MergeORedCaveats(child, parameterTypes))
+	}
+
+	if operation.Op == core.CaveatOperation_OR {
+		children = mergeORedLeaves(children, parameterTypes)
+		if len(children) == 1 {
+			return children[0]
+		}
+	}
+
+	return &core.CaveatExpression{
+		OperationOrCaveat: &core.CaveatExpression_Operation{
+			Operation: &core.CaveatOperation{
+				Op:       operation.Op,
+				Children: children,
+			},
+		},
+	}
+}
+
+func mergeORedLeaves(children []*core.CaveatExpression, parameterTypes map[string]map[string]*core.CaveatTypeReference) []*core.CaveatExpression {
+	merged := make([]*core.CaveatExpression, 0, len(children))
+
+outer:
+	for _, child := range children {
+		if caveat := child.GetCaveat(); caveat != nil {
+			for index, existing := range merged {
+				existingCaveat := existing.GetCaveat()
+				if existingCaveat == nil || existingCaveat.CaveatName != caveat.CaveatName {
+					continue
+				}
+
+				combined, ok := mergeCaveatContexts(existingCaveat, caveat, parameterTypes[caveat.CaveatName])
+				if !ok {
+					continue
+				}
+
+				merged[index] = CaveatAsExpr(combined)
+				continue outer
+			}
+		}
+
+		merged = append(merged, child)
+	}
+
+	return merged
+}
+
+// mergeCaveatContexts merges the contexts of two leaves referencing the same caveat,
+// unioning list-typed parameters. Returns false if the contexts cannot be merged.
+func mergeCaveatContexts(first *core.ContextualizedCaveat, second *core.ContextualizedCaveat, parameterTypes map[string]*core.CaveatTypeReference) (*core.ContextualizedCaveat, bool) {
+	if parameterTypes == nil {
+		return nil, false
+	}
+
+	firstFields := first.GetContext().GetFields()
+	secondFields := second.GetContext().GetFields()
+	if len(firstFields) != len(secondFields) {
+		return nil, false
+	}
+
+	mergedFields := make(map[string]*structpb.Value, len(firstFields))
+	for key, firstValue := range firstFields {
+		secondValue, ok := secondFields[key]
+		if !ok {
+			return nil, false
+		}
+
+		if proto.Equal(firstValue, secondValue) {
+			mergedFields[key] = firstValue
+			continue
+		}
+
+		paramType, ok := parameterTypes[key]
+		if !ok || paramType.TypeName != "list" {
+			return nil, false
+		}
+
+		firstList := firstValue.GetListValue()
+		secondList := secondValue.GetListValue()
+		if firstList == nil || secondList == nil {
+			return nil, false
+		}
+
+		mergedFields[key] = structpb.NewListValue(unionListValues(firstList, secondList))
+	}
+
+	var mergedContext *structpb.Struct
+	if len(mergedFields) > 0 {
+		mergedContext = &structpb.Struct{Fields: mergedFields}
+	}
+
+	return &core.ContextualizedCaveat{
+		CaveatName: first.CaveatName,
+		Context:    mergedContext,
+	}, true
+}
+
+// unionListValues concatenates the two lists, dropping values equal to one already collected.
+func unionListValues(first *structpb.ListValue, second *structpb.ListValue) *structpb.ListValue {
+	values := make([]*structpb.Value, 0, len(first.Values)+len(second.Values))
+
+outer:
+	for _, value := range append(append([]*structpb.Value{}, first.Values...), second.Values...) {
+		for _, existing := range values {
+			if proto.Equal(existing, value) {
+				continue outer
+			}
+		}
+		values = append(values, value)
+	}
+
+	return &structpb.ListValue{Values: values}
+}
@@ -0,0 +1,149 @@
+package caveats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/pkg/caveats/types"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/testutil"
+)
+
+func ipCheckParameterTypes() map[string]map[string]*core.CaveatTypeReference {
+	return map[string]map[string]*core.CaveatTypeReference{
+		"ip_check": types.EncodeParameterTypes(map[string]types.VariableType{
+			"allowed": types.Default.MustListType(types.Default.StringType),
+			"cidr":    types.Default.StringType,
+		}),
+	}
+}
+
+func TestMergeORedCaveats(t *testing.T) {
+	tcs := []struct {
+		name     string
+		expr     *core.CaveatExpression
+		expected *core.CaveatExpression
+	}{
+		{
+			"nil expression",
+			nil,
+			nil,
+		},
+		{
+			"leaf is left alone",
+			MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"10.0.0.1"}}),
+			MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"10.0.0.1"}}),
+		},
+		{
+			"differing list parameters are unioned",
+			Or(
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"10.0.0.1", "10.0.0.2"}}),
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"10.0.0.2", "192.168.0.1"}}),
+			),
+			MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"10.0.0.1", "10.0.0.2", "192.168.0.1"}}),
+		},
+		{
+			"differing scalar parameters are not merged",
+			Or(
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"cidr": "10.0.0.0/8"}),
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"cidr": "192.168.0.0/16"}),
+			),
+			Or(
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"cidr": "10.0.0.0/8"}),
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"cidr": "192.168.0.0/16"}),
+			),
+		},
+		{
+			"matching scalar parameters do not block the merge",
+			Or(
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"10.0.0.1"}, "cidr": "10.0.0.0/8"}),
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"192.168.0.1"}, "cidr": "10.0.0.0/8"}),
+			),
+			MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"10.0.0.1", "192.168.0.1"}, "cidr": "10.0.0.0/8"}),
+		},
+		{
+			"leaves disagreeing on which parameters are set are not merged",
+			Or(
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"10.0.0.1"}}),
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"192.168.0.1"}, "cidr": "10.0.0.0/8"}),
+			),
+			Or(
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"10.0.0.1"}}),
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"192.168.0.1"}, "cidr": "10.0.0.0/8"}),
+			),
+		},
+		{
+			"caveats without type information are not merged",
+			Or(
+				MustCaveatExprForTestingWithContext("unknown_caveat", map[string]any{"allowed": []any{"10.0.0.1"}}),
+				MustCaveatExprForTestingWithContext("unknown_caveat", map[string]any{"allowed": []any{"192.168.0.1"}}),
+			),
+			Or(
+				MustCaveatExprForTestingWithContext("unknown_caveat", map[string]any{"allowed": []any{"10.0.0.1"}}),
+				MustCaveatExprForTestingWithContext("unknown_caveat", map[string]any{"allowed": []any{"192.168.0.1"}}),
+			),
+		},
+		{
+			"merging applies within a nested OR under an AND",
+			And(
+				CaveatExprForTesting("other_caveat"),
+				Or(
+					MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"10.0.0.1"}}),
+					MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"192.168.0.1"}}),
+				),
+			),
+			And(
+				CaveatExprForTesting("other_caveat"),
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"10.0.0.1", "192.168.0.1"}}),
+			),
+		},
+		{
+			"leaves of an AND are not merged",
+			And(
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"10.0.0.1"}}),
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"192.168.0.1"}}),
+			),
+			And(
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"10.0.0.1"}}),
+				MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"192.168.0.1"}}),
+			),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			result := MergeORedCaveats(tc.expr, ipCheckParameterTypes())
+			testutil.RequireProtoEqual(t, tc.expected, result, "mismatch")
+		})
+	}
+}
+
+func TestMergeORedCaveatsPreservesEvaluation(t *testing.T) {
+	req := require.New(t)
+
+	reader := caveatReaderForSchema(t, `
+		caveat ip_check(allowed list<string>, ip string) {
+			ip in allowed
+		}
+		`)
+
+	expr := Or(
+		MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"10.0.0.1"}}),
+		MustCaveatExprForTestingWithContext("ip_check", map[string]any{"allowed": []any{"192.168.0.1"}}),
+	)
+	merged := MergeORedCaveats(expr, ipCheckParameterTypes())
+	req.NotNil(merged.GetCaveat(), "expected the OR to merge into a single leaf")
+
+	for _, ip := range []string{"10.0.0.1", "192.168.0.1", "172.16.0.1"} {
+		originalResult, err := NewCaveatRunner(types.Default.TypeSet).RunCaveatExpression(
+			t.Context(), expr, map[string]any{"ip": ip}, reader, RunCaveatExpressionNoDebugging)
+		req.NoError(err)
+
+		mergedResult, err := NewCaveatRunner(types.Default.TypeSet).RunCaveatExpression(
+			t.Context(), merged, map[string]any{"ip": ip}, reader, RunCaveatExpressionNoDebugging)
+		req.NoError(err)
+
+		req.Equal(originalResult.Value(), mergedResult.Value(), "evaluation mismatch for ip %s", ip)
+	}
+}
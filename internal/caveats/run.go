@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"maps"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -13,6 +14,7 @@ import (
 	caveattypes "github.com/authzed/spicedb/pkg/caveats/types"
 	"github.com/authzed/spicedb/pkg/datastore"
 	"github.com/authzed/spicedb/pkg/genutil/mapz"
+	"github.com/authzed/spicedb/pkg/namespace"
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 	"github.com/authzed/spicedb/pkg/spiceerrors"
 )
@@ -31,6 +33,21 @@ const (
 	RunCaveatExpressionWithDebugInformation RunCaveatExpressionDebugOption = 1
 )
 
+// RelationshipOnlyParameterOption configures how a CaveatRunner handles request context that
+// supplies values for caveat parameters designated as relationship-only via the caveat's
+// metadata.
+type RelationshipOnlyParameterOption int
+
+const (
+	// ErrorForRelationshipOnlyParameters rejects request-supplied values for relationship-only
+	// parameters with an error. This is the default.
+	ErrorForRelationshipOnlyParameters RelationshipOnlyParameterOption = 0
+
+	// IgnoreRelationshipOnlyParameters drops request-supplied values for relationship-only
+	// parameters before evaluation.
+	IgnoreRelationshipOnlyParameters RelationshipOnlyParameterOption = 1
+)
+
 // RunSingleCaveatExpression runs a caveat expression over the given context and returns the result.
 // This instantiates its own CaveatRunner, and should therefore only be used in one-off situations.
 func RunSingleCaveatExpression(
@@ -45,11 +62,49 @@ func RunSingleCaveatExpression(
 	return runner.RunCaveatExpression(ctx, expr, context, reader, debugOption)
 }
 
-// CaveatRunner is a helper for running caveats, providing a cache for deserialized caveats.
+// CaveatRunner is a helper for running caveats, providing a cache for deserialized caveats
+// and for the evaluation results of individual caveats.
 type CaveatRunner struct {
 	caveatTypeSet       *caveattypes.TypeSet
 	caveatDefs          map[string]*core.CaveatDefinition
 	deserializedCaveats map[string]*caveats.CompiledCaveat
+	caveatDefaults      map[string]map[string]any
+	relationshipOnly    map[string]map[string]struct{}
+	sensitiveParams     map[string][]string
+
+	// relationshipOnlyOption configures handling of request-supplied values for
+	// relationship-only parameters.
+	relationshipOnlyOption RelationshipOnlyParameterOption
+
+	// evalResults caches single-caveat evaluation results within this runner's scope, so a
+	// batch evaluating the same caveat over the same context many times only runs CEL once.
+	evalResults map[string]ExpressionResult
+
+	// sharedEvalCache, if set, additionally shares evaluation results across runners.
+	sharedEvalCache *EvaluationResultCache
+
+	// observer, if set, is invoked for each leaf caveat evaluation.
+	observer EvaluationObserver
+}
+
+// EvaluationObserver is a callback invoked for every leaf caveat evaluation performed by a
+// CaveatRunner, including partial evaluations and cached results. Observers receive a copy
+// of the full evaluation context and cannot mutate the result through the ExpressionResult
+// interface.
+type EvaluationObserver func(caveatName string, context map[string]any, result ExpressionResult, duration time.Duration)
+
+// WithEvaluationObserver sets the observer invoked for each leaf caveat evaluation and
+// returns the runner. Evaluation with a nil observer (the default) incurs no overhead.
+func (cr *CaveatRunner) WithEvaluationObserver(observer EvaluationObserver) *CaveatRunner {
+	cr.observer = observer
+	return cr
+}
+
+// WithRelationshipOnlyParameterOption sets the handling of request-supplied values for
+// relationship-only caveat parameters and returns the runner.
+func (cr *CaveatRunner) WithRelationshipOnlyParameterOption(option RelationshipOnlyParameterOption) *CaveatRunner {
+	cr.relationshipOnlyOption = option
+	return cr
 }
 
 // NewCaveatRunner creates a new CaveatRunner.
@@ -58,6 +113,43 @@ func NewCaveatRunner(ts *caveattypes.TypeSet) *CaveatRunner {
 		caveatTypeSet:       ts,
 		caveatDefs:          map[string]*core.CaveatDefinition{},
 		deserializedCaveats: map[string]*caveats.CompiledCaveat{},
+		caveatDefaults:      map[string]map[string]any{},
+		relationshipOnly:    map[string]map[string]struct{}{},
+		sensitiveParams:     map[string][]string{},
+		evalResults:         map[string]ExpressionResult{},
+	}
+}
+
+// NewCaveatRunnerWithSharedCache creates a new CaveatRunner that also consults and populates
+// the given process-wide evaluation result cache.
+func NewCaveatRunnerWithSharedCache(ts *caveattypes.TypeSet, sharedEvalCache *EvaluationResultCache) *CaveatRunner {
+	runner := NewCaveatRunner(ts)
+	runner.sharedEvalCache = sharedEvalCache
+	return runner
+}
+
+// cachedResult returns the cached evaluation result for the given key, if any.
+func (cr *CaveatRunner) cachedResult(key string) (ExpressionResult, bool) {
+	if cr.evalResults != nil {
+		if result, ok := cr.evalResults[key]; ok {
+			return result, true
+		}
+	}
+
+	if cr.sharedEvalCache != nil {
+		return cr.sharedEvalCache.get(key)
+	}
+	return nil, false
+}
+
+// storeResult caches the evaluation result for the given key.
+func (cr *CaveatRunner) storeResult(key string, result ExpressionResult) {
+	if cr.evalResults != nil {
+		cr.evalResults[key] = result
+	}
+
+	if cr.sharedEvalCache != nil {
+		cr.sharedEvalCache.set(key, result)
 	}
 }
 
@@ -147,6 +239,68 @@ func (cr *CaveatRunner) get(caveatDefName string) (*core.CaveatDefinition, *cave
 	return caveat, justDeserialized, nil
 }
 
+// parameterDefaults returns the default values declared for the given caveat's parameters,
+// if any, caching the decoded defaults for reuse.
+func (cr *CaveatRunner) parameterDefaults(caveat *core.CaveatDefinition) (map[string]any, error) {
+	if defaults, ok := cr.caveatDefaults[caveat.Name]; ok {
+		return defaults, nil
+	}
+
+	defaults, err := namespace.GetCaveatParameterDefaults(caveat.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	cr.caveatDefaults[caveat.Name] = defaults
+	return defaults, nil
+}
+
+// relationshipOnlyParameters returns the set of the given caveat's parameters designated as
+// suppliable only via relationship context, if any, caching the decoded set for reuse.
+func (cr *CaveatRunner) relationshipOnlyParameters(caveat *core.CaveatDefinition) (map[string]struct{}, error) {
+	if parameters, ok := cr.relationshipOnly[caveat.Name]; ok {
+		return parameters, nil
+	}
+
+	parameterNames, err := namespace.GetRelationshipOnlyCaveatParameters(caveat.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	var parameters map[string]struct{}
+	if len(parameterNames) > 0 {
+		parameters = make(map[string]struct{}, len(parameterNames))
+		for _, parameterName := range parameterNames {
+			parameters[parameterName] = struct{}{}
+		}
+	}
+
+	cr.relationshipOnly[caveat.Name] = parameters
+	return parameters, nil
+}
+
+// sensitiveParameters returns the names of the given caveat's parameters designated as
+// sensitive, if any, caching the decoded names for reuse. Caveats not loaded into the runner
+// have no known designations.
+func (cr *CaveatRunner) sensitiveParameters(caveatName string) ([]string, error) {
+	if names, ok := cr.sensitiveParams[caveatName]; ok {
+		return names, nil
+	}
+
+	caveat, ok := cr.caveatDefs[caveatName]
+	if !ok {
+		return nil, nil
+	}
+
+	names, err := namespace.GetSensitiveCaveatParameters(caveat.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	cr.sensitiveParams[caveatName] = names
+	return names, nil
+}
+
 func collectCaveatNames(expr *core.CaveatExpression, caveatNames *mapz.Set[string]) {
 	if expr.GetCaveat() != nil {
 		caveatNames.Add(expr.GetCaveat().CaveatName)
@@ -183,9 +337,57 @@ func (cr *CaveatRunner) runExpressionWithCaveats(
 			untypedFullContext = map[string]any{}
 		}
 
+		// Enforce any relationship-only designations on the caveat's parameters: values for
+		// such parameters may not be supplied by the request context.
+		relationshipOnly, err := cr.relationshipOnlyParameters(caveat)
+		if err != nil {
+			return nil, err
+		}
+
+		for parameterName := range relationshipOnly {
+			if _, ok := untypedFullContext[parameterName]; !ok {
+				continue
+			}
+
+			if cr.relationshipOnlyOption == ErrorForRelationshipOnlyParameters {
+				return nil, NewRelationshipOnlyParameterError(expr, parameterName)
+			}
+			delete(untypedFullContext, parameterName)
+		}
+
 		relationshipContext := expr.GetCaveat().GetContext().AsMap()
 		maps.Copy(untypedFullContext, relationshipContext)
 
+		// Fill in any defaults declared on the caveat's parameters for parameters not
+		// otherwise specified, so that such parameters are never reported as missing.
+		defaults, err := cr.parameterDefaults(caveat)
+		if err != nil {
+			return nil, err
+		}
+
+		for paramName, defaultValue := range defaults {
+			if _, ok := untypedFullContext[paramName]; !ok {
+				untypedFullContext[paramName] = defaultValue
+			}
+		}
+
+		var evalStart time.Time
+		if cr.observer != nil {
+			evalStart = time.Now()
+		}
+
+		// Serve a previously computed result for the same caveat and context, if any. Contexts
+		// holding values that cannot be canonically serialized simply skip the cache.
+		cacheKey, cacheKeyErr := evaluationCacheKey(caveat.Name, caveat.SerializedExpression, untypedFullContext)
+		if cacheKeyErr == nil {
+			if cached, ok := cr.cachedResult(cacheKey); ok {
+				if cr.observer != nil {
+					cr.observer(caveat.Name, maps.Clone(untypedFullContext), cached, time.Since(evalStart))
+				}
+				return cached, nil
+			}
+		}
+
 		// Perform type checking and conversion on the context map.
 		typedParameters, err := caveats.ConvertContextToParameters(
 			cr.caveatTypeSet,
@@ -207,6 +409,13 @@ func (cr *CaveatRunner) runExpressionWithCaveats(
 			return nil, err
 		}
 
+		if cacheKeyErr == nil {
+			cr.storeResult(cacheKey, result)
+		}
+
+		if cr.observer != nil {
+			cr.observer(caveat.Name, maps.Clone(untypedFullContext), result, time.Since(evalStart))
+		}
 		return result, nil
 	}
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -15,7 +16,10 @@ import (
 	"github.com/authzed/spicedb/pkg/caveats/types"
 	"github.com/authzed/spicedb/pkg/datastore"
 	"github.com/authzed/spicedb/pkg/genutil/mapz"
+	"github.com/authzed/spicedb/pkg/namespace"
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/schemadsl/compiler"
+	"github.com/authzed/spicedb/pkg/schemadsl/input"
 )
 
 var (
@@ -828,3 +832,358 @@ func TestUnknownCaveatOperation(t *testing.T) {
 		)
 	})
 }
+
+func TestNotOverPartialResult(t *testing.T) {
+	req := require.New(t)
+
+	reader := caveatReaderForSchema(t, `
+		caveat firstCaveat(first int) {
+			first == 42
+		}
+		`)
+
+	expr := Not(caveatexpr("firstCaveat"))
+
+	// Without the required context, the negation remains partial.
+	partialResult, err := NewCaveatRunner(types.Default.TypeSet).RunCaveatExpression(
+		t.Context(), expr, nil, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.True(partialResult.IsPartial())
+
+	missingVars, err := partialResult.MissingVarNames()
+	req.NoError(err)
+	req.Equal([]string{"first"}, missingVars)
+
+	// With the context provided, the negation resolves to the inverted value.
+	trueResult, err := NewCaveatRunner(types.Default.TypeSet).RunCaveatExpression(
+		t.Context(), expr, map[string]any{"first": int64(41)}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.False(trueResult.IsPartial())
+	req.True(trueResult.Value())
+
+	falseResult, err := NewCaveatRunner(types.Default.TypeSet).RunCaveatExpression(
+		t.Context(), expr, map[string]any{"first": int64(42)}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.False(falseResult.IsPartial())
+	req.False(falseResult.Value())
+
+	// Double negation is eliminated at construction and evaluates as the original caveat.
+	doubleNegated := Not(Not(caveatexpr("firstCaveat")))
+	originalResult, err := NewCaveatRunner(types.Default.TypeSet).RunCaveatExpression(
+		t.Context(), doubleNegated, map[string]any{"first": int64(42)}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.True(originalResult.Value())
+}
+
+type observedEvaluation struct {
+	caveatName string
+	value      bool
+	isPartial  bool
+}
+
+func TestEvaluationObserver(t *testing.T) {
+	req := require.New(t)
+
+	reader := caveatReaderForSchema(t, `
+		caveat firstCaveat(first int) {
+			first == 42
+		}
+
+		caveat secondCaveat(second int) {
+			second == 42
+		}
+		`)
+
+	var observed []observedEvaluation
+	runner := NewCaveatRunner(types.Default.TypeSet).WithEvaluationObserver(
+		func(caveatName string, context map[string]any, result ExpressionResult, duration time.Duration) {
+			observed = append(observed, observedEvaluation{caveatName, result.Value(), result.IsPartial()})
+		})
+
+	// An OR short-circuits after its first true leaf; the second leaf is never observed.
+	result, err := runner.RunCaveatExpression(
+		t.Context(),
+		caveatOr(caveatexpr("firstCaveat"), caveatexpr("secondCaveat")),
+		map[string]any{"first": int64(42), "second": int64(42)},
+		reader,
+		RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.True(result.Value())
+	req.Equal([]observedEvaluation{{"firstCaveat", true, false}}, observed)
+
+	// A false first leaf means both leaves are observed.
+	observed = nil
+	result, err = runner.RunCaveatExpression(
+		t.Context(),
+		caveatOr(caveatexpr("firstCaveat"), caveatexpr("secondCaveat")),
+		map[string]any{"first": int64(41), "second": int64(42)},
+		reader,
+		RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.True(result.Value())
+	req.Equal([]observedEvaluation{{"firstCaveat", false, false}, {"secondCaveat", true, false}}, observed)
+
+	// Partial evaluations are observed as well.
+	observed = nil
+	result, err = runner.RunCaveatExpression(
+		t.Context(),
+		caveatexpr("firstCaveat"),
+		nil,
+		reader,
+		RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.True(result.IsPartial())
+	req.Equal([]observedEvaluation{{"firstCaveat", false, true}}, observed)
+
+	// Cached results are observed too.
+	observed = nil
+	result, err = runner.RunCaveatExpression(
+		t.Context(),
+		caveatexpr("firstCaveat"),
+		map[string]any{"first": int64(42)},
+		reader,
+		RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.True(result.Value())
+
+	cachedResult, err := runner.RunCaveatExpression(
+		t.Context(),
+		caveatexpr("firstCaveat"),
+		map[string]any{"first": int64(42)},
+		reader,
+		RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.Same(result, cachedResult)
+	req.Equal([]observedEvaluation{{"firstCaveat", true, false}, {"firstCaveat", true, false}}, observed)
+}
+
+func BenchmarkLeafEvaluationObserver(b *testing.B) {
+	reader := caveatReaderForSchema(b, `
+		caveat firstCaveat(first int) {
+			first == 42
+		}
+		`)
+
+	context := map[string]any{"first": "42"}
+
+	// Guards the claim that a nil observer adds no overhead to leaf evaluation.
+	b.Run("nil observer", func(b *testing.B) {
+		runner := NewCaveatRunner(types.Default.TypeSet)
+		runner.evalResults = nil
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := runner.RunCaveatExpression(b.Context(), caveatexpr("firstCaveat"), context, reader, RunCaveatExpressionNoDebugging); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("with observer", func(b *testing.B) {
+		runner := NewCaveatRunner(types.Default.TypeSet).WithEvaluationObserver(
+			func(caveatName string, context map[string]any, result ExpressionResult, duration time.Duration) {})
+		runner.evalResults = nil
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := runner.RunCaveatExpression(b.Context(), caveatexpr("firstCaveat"), context, reader, RunCaveatExpressionNoDebugging); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestCaveatParameterDefaults(t *testing.T) {
+	req := require.New(t)
+
+	reader := caveatReaderForSchema(t, `
+		caveat limit_check(limit int = 10, count int) {
+			count < limit
+		}
+		`)
+
+	runner := NewCaveatRunner(types.Default.TypeSet)
+
+	// The default for `limit` is used when the parameter is not in the context.
+	result, err := runner.RunCaveatExpression(t.Context(), caveatexpr("limit_check"), map[string]any{
+		"count": int64(5),
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.False(result.IsPartial())
+	req.True(result.Value())
+
+	result, err = runner.RunCaveatExpression(t.Context(), caveatexpr("limit_check"), map[string]any{
+		"count": int64(15),
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.False(result.IsPartial())
+	req.False(result.Value())
+
+	// A value specified in the context overrides the default.
+	result, err = runner.RunCaveatExpression(t.Context(), caveatexpr("limit_check"), map[string]any{
+		"count": int64(15),
+		"limit": int64(20),
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.False(result.IsPartial())
+	req.True(result.Value())
+
+	// A missing parameter without a default still produces a partial result, with only
+	// that parameter reported as missing.
+	result, err = runner.RunCaveatExpression(t.Context(), caveatexpr("limit_check"), map[string]any{}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.True(result.IsPartial())
+
+	missingVarNames, err := result.MissingVarNames()
+	req.NoError(err)
+	req.Equal([]string{"count"}, missingVarNames)
+}
+
+func TestCaveatDecimalParameters(t *testing.T) {
+	req := require.New(t)
+
+	reader := caveatReaderForSchema(t, `
+		caveat under_limit(amount decimal, limit decimal) {
+			amount + decimal("0.2") <= limit
+		}
+		`)
+
+	runner := NewCaveatRunner(types.Default.TypeSet)
+
+	// String-encoded decimals are accepted in both the relationship and request contexts, and
+	// arithmetic is exact: 0.1 + 0.2 is precisely 0.3.
+	expr := MustCaveatExprForTestingWithContext("under_limit", map[string]any{
+		"amount": "0.1",
+	})
+
+	result, err := runner.RunCaveatExpression(t.Context(), expr, map[string]any{
+		"limit": "0.3",
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.False(result.IsPartial())
+	req.True(result.Value())
+
+	result, err = runner.RunCaveatExpression(t.Context(), expr, map[string]any{
+		"limit": "0.29999999",
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.False(result.IsPartial())
+	req.False(result.Value())
+
+	// A double provided for a decimal parameter is a type mismatch.
+	_, err = runner.RunCaveatExpression(t.Context(), expr, map[string]any{
+		"limit": float64(0.3),
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.ErrorContains(err, "decimal requires a decimal string, found: float64 `0.3`")
+}
+
+// caveatReaderWithRelationshipOnlyParameters compiles the given schema, designates the given
+// parameters of the named caveat as relationship-only, and returns a reader over the result.
+func caveatReaderWithRelationshipOnlyParameters(t *testing.T, schema string, caveatName string, parameterNames []string) datastore.Reader {
+	req := require.New(t)
+
+	compiled, err := compiler.Compile(compiler.InputSchema{
+		Source:       input.Source("schema"),
+		SchemaString: schema,
+	}, compiler.AllowUnprefixedObjectType())
+	req.NoError(err)
+
+	for _, def := range compiled.CaveatDefinitions {
+		if def.Name == caveatName {
+			def.Metadata, err = namespace.SetRelationshipOnlyCaveatParameters(def.Metadata, parameterNames)
+			req.NoError(err)
+		}
+	}
+
+	ds, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	req.NoError(err)
+
+	revision, err := ds.ReadWriteTx(t.Context(), func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.WriteCaveats(ctx, compiled.CaveatDefinitions)
+	})
+	req.NoError(err)
+
+	return ds.SnapshotReader(revision)
+}
+
+func TestCaveatRelationshipOnlyParameters(t *testing.T) {
+	req := require.New(t)
+
+	reader := caveatReaderWithRelationshipOnlyParameters(t, `
+		caveat admin_check(is_admin bool, tier string) {
+			is_admin && tier == "gold"
+		}
+		`, "admin_check", []string{"is_admin"})
+
+	// A request-supplied value for a relationship-only parameter is rejected by default, even
+	// when the stored relationship context would override it.
+	runner := NewCaveatRunner(types.Default.TypeSet)
+	expr := MustCaveatExprForTestingWithContext("admin_check", map[string]any{
+		"is_admin": true,
+	})
+
+	_, err := runner.RunCaveatExpression(t.Context(), expr, map[string]any{
+		"is_admin": true,
+		"tier":     "gold",
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.ErrorContains(err, "the parameter `is_admin` for caveat `admin_check` may only be supplied via relationship context")
+
+	// Parameters not designated as relationship-only remain suppliable via request context.
+	result, err := runner.RunCaveatExpression(t.Context(), expr, map[string]any{
+		"tier": "gold",
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.False(result.IsPartial())
+	req.True(result.Value())
+
+	// In ignore mode, the request-supplied value is dropped rather than rejected: with no
+	// stored value for the parameter, it is simply reported as missing.
+	ignoring := NewCaveatRunner(types.Default.TypeSet).WithRelationshipOnlyParameterOption(IgnoreRelationshipOnlyParameters)
+	result, err = ignoring.RunCaveatExpression(t.Context(), caveatexpr("admin_check"), map[string]any{
+		"is_admin": true,
+		"tier":     "gold",
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.True(result.IsPartial())
+
+	missingVarNames, err := result.MissingVarNames()
+	req.NoError(err)
+	req.Equal([]string{"is_admin"}, missingVarNames)
+
+	// With a stored value present, the dropped request value has no effect on the result.
+	result, err = ignoring.RunCaveatExpression(t.Context(), MustCaveatExprForTestingWithContext("admin_check", map[string]any{
+		"is_admin": false,
+	}), map[string]any{
+		"is_admin": true,
+		"tier":     "gold",
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.False(result.IsPartial())
+	req.False(result.Value())
+}
+
+func TestCaveatRelationshipContextPrecedence(t *testing.T) {
+	req := require.New(t)
+
+	reader := caveatReaderForSchema(t, `
+		caveat limit_check(limit int, count int) {
+			count < limit
+		}
+		`)
+
+	runner := NewCaveatRunner(types.Default.TypeSet)
+
+	// The stored relationship context always takes precedence over the request context for
+	// the same key.
+	expr := MustCaveatExprForTestingWithContext("limit_check", map[string]any{
+		"limit": 10,
+	})
+
+	result, err := runner.RunCaveatExpression(t.Context(), expr, map[string]any{
+		"limit": int64(100),
+		"count": int64(50),
+	}, reader, RunCaveatExpressionNoDebugging)
+	req.NoError(err)
+	req.False(result.IsPartial())
+	req.False(result.Value())
+}
@@ -0,0 +1,130 @@
+package caveats
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+// maxStringifiedValueLength is the maximum length of a rendered context value before it
+// is elided.
+const maxStringifiedValueLength = 32
+
+// StringifyExpression returns a compact, human-readable infix form of the given caveat
+// expression, such as `(ip_allowlist && !expired_token) || is_admin`. Contextual caveat
+// parameters are rendered inline and long values are elided. Intended for debug output
+// and error messages; the result is not parseable.
+func StringifyExpression(expr *core.CaveatExpression) string {
+	if expr == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	stringifyInto(&b, expr, core.CaveatOperation_UNKNOWN)
+	return b.String()
+}
+
+func stringifyInto(b *strings.Builder, expr *core.CaveatExpression, parentOp core.CaveatOperation_Operation) {
+	if expr == nil || expr.OperationOrCaveat == nil {
+		return
+	}
+
+	if caveat := expr.GetCaveat(); caveat != nil {
+		stringifyCaveat(b, caveat)
+		return
+	}
+
+	operation := expr.GetOperation()
+	if operation.Op == core.CaveatOperation_NOT && len(operation.Children) == 1 {
+		b.WriteString("!")
+		child := operation.Children[0]
+		if child.GetOperation() != nil {
+			b.WriteString("(")
+			stringifyInto(b, child, core.CaveatOperation_UNKNOWN)
+			b.WriteString(")")
+			return
+		}
+
+		stringifyInto(b, child, core.CaveatOperation_NOT)
+		return
+	}
+
+	var operator string
+	switch operation.Op {
+	case core.CaveatOperation_AND:
+		operator = " && "
+
+	case core.CaveatOperation_OR:
+		operator = " || "
+
+	default:
+		operator = " ?OP? "
+	}
+
+	if len(operation.Children) == 0 {
+		b.WriteString("?OP?")
+		return
+	}
+
+	// Parenthesize unless this operation is at the top level or nested under the same
+	// operator, where associativity makes the parentheses redundant. The parentheses
+	// around a negated operation (e.g. the subtrahend of a subtraction) are always kept,
+	// as they affect meaning.
+	needsParens := parentOp != core.CaveatOperation_UNKNOWN && operation.Op != parentOp
+	if needsParens {
+		b.WriteString("(")
+	}
+
+	for index, child := range operation.Children {
+		if index > 0 {
+			b.WriteString(operator)
+		}
+		stringifyInto(b, child, operation.Op)
+	}
+
+	if needsParens {
+		b.WriteString(")")
+	}
+}
+
+func stringifyCaveat(b *strings.Builder, caveat *core.ContextualizedCaveat) {
+	b.WriteString(caveat.CaveatName)
+
+	fields := caveat.GetContext().GetFields()
+	if len(fields) == 0 {
+		return
+	}
+
+	b.WriteString("[")
+	for index, key := range slices.Sorted(maps.Keys(fields)) {
+		if index > 0 {
+			b.WriteString(", ")
+		}
+
+		b.WriteString(key)
+		b.WriteString("=")
+		b.WriteString(stringifyContextValue(fields[key].AsInterface()))
+	}
+	b.WriteString("]")
+}
+
+func stringifyContextValue(value any) string {
+	var rendered string
+	switch v := value.(type) {
+	case string:
+		rendered = strconv.Quote(v)
+
+	default:
+		rendered = fmt.Sprintf("%v", v)
+	}
+
+	if len(rendered) > maxStringifiedValueLength {
+		return rendered[:maxStringifiedValueLength-3] + "..."
+	}
+
+	return rendered
+}
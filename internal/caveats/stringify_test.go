@@ -0,0 +1,115 @@
+package caveats
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+func TestStringifyExpression(t *testing.T) {
+	tcs := []struct {
+		name     string
+		expr     *core.CaveatExpression
+		expected string
+	}{
+		{
+			"nil expression",
+			nil,
+			"",
+		},
+		{
+			"leaf caveat",
+			CaveatExprForTesting("is_admin"),
+			"is_admin",
+		},
+		{
+			"leaf caveat with context",
+			MustCaveatExprForTestingWithContext("ip_allowlist", map[string]any{
+				"ip":    "192.168.0.1",
+				"count": 42,
+			}),
+			`ip_allowlist[count=42, ip="192.168.0.1"]`,
+		},
+		{
+			"leaf caveat with a long context value elided",
+			MustCaveatExprForTestingWithContext("token_check", map[string]any{
+				"token": strings.Repeat("a", 50),
+			}),
+			`token_check[token="` + strings.Repeat("a", 28) + `...]`,
+		},
+		{
+			"or of and with negation",
+			Or(CaveatExprForTesting("is_admin"), And(CaveatExprForTesting("ip_allowlist"), Invert(CaveatExprForTesting("expired_token")))),
+			"is_admin || (ip_allowlist && !expired_token)",
+		},
+		{
+			"subtraction",
+			Subtract(CaveatExprForTesting("has_access"), CaveatExprForTesting("is_banned")),
+			"has_access && !is_banned",
+		},
+		{
+			"subtraction of a disjunction keeps its parentheses",
+			Subtract(CaveatExprForTesting("has_access"), Or(CaveatExprForTesting("is_banned"), CaveatExprForTesting("is_suspended"))),
+			"has_access && !(is_banned || is_suspended)",
+		},
+		{
+			"nested same operator renders without redundant parentheses",
+			&core.CaveatExpression{
+				OperationOrCaveat: &core.CaveatExpression_Operation{
+					Operation: &core.CaveatOperation{
+						Op: core.CaveatOperation_OR,
+						Children: []*core.CaveatExpression{
+							{
+								OperationOrCaveat: &core.CaveatExpression_Operation{
+									Operation: &core.CaveatOperation{
+										Op:       core.CaveatOperation_OR,
+										Children: []*core.CaveatExpression{CaveatExprForTesting("first"), CaveatExprForTesting("second")},
+									},
+								},
+							},
+							CaveatExprForTesting("third"),
+						},
+					},
+				},
+			},
+			"first || second || third",
+		},
+		{
+			"double negation",
+			Invert(Invert(CaveatExprForTesting("first"))),
+			"!(!first)",
+		},
+		{
+			"unknown operation",
+			&core.CaveatExpression{
+				OperationOrCaveat: &core.CaveatExpression_Operation{
+					Operation: &core.CaveatOperation{
+						Op:       core.CaveatOperation_Operation(99),
+						Children: []*core.CaveatExpression{CaveatExprForTesting("first"), CaveatExprForTesting("second")},
+					},
+				},
+			},
+			"first ?OP? second",
+		},
+		{
+			"operation without children",
+			&core.CaveatExpression{
+				OperationOrCaveat: &core.CaveatExpression_Operation{
+					Operation: &core.CaveatOperation{
+						Op: core.CaveatOperation_AND,
+					},
+				},
+			},
+			"?OP?",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, StringifyExpression(tc.expr))
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package caveats
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/authzed/spicedb/pkg/caveats"
+	caveattypes "github.com/authzed/spicedb/pkg/caveats/types"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+// CaveatTypeInfo provides the schema information used by ExpressionToCEL to inline the CEL
+// source of referenced caveats with their relationship context bound as literal values.
+type CaveatTypeInfo struct {
+	// TypeSet is the set of caveat types in use by the schema. If nil, the default type set
+	// is used.
+	TypeSet *caveattypes.TypeSet
+
+	// Definitions holds the definitions of the caveats referenced in the expression, by name.
+	// Caveats without a definition present are rendered symbolically.
+	Definitions map[string]*core.CaveatDefinition
+}
+
+// ExpressionToCEL renders the given caveat expression tree as a single CEL expression.
+//
+// When a referenced caveat's definition is available in the given type information, its CEL
+// source is inlined, with any relationship context bound as literal values; leaves whose
+// context fully determines their value fold to `true` or `false`. When the definition is not
+// available (including when typeInfo is nil), a best-effort symbolic call of the form
+// `caveat_name({...})` is rendered instead; such expressions preserve the structure for
+// external systems but are not evaluable in the caveat environment.
+func ExpressionToCEL(expr *core.CaveatExpression, typeInfo *CaveatTypeInfo) (string, error) {
+	if expr == nil {
+		return "", fmt.Errorf("cannot convert an empty caveat expression to CEL")
+	}
+
+	var b strings.Builder
+	if err := convertIntoCEL(&b, expr, typeInfo); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+func convertIntoCEL(b *strings.Builder, expr *core.CaveatExpression, typeInfo *CaveatTypeInfo) error {
+	if expr.GetCaveat() != nil {
+		rendered, err := celForLeaf(expr.GetCaveat(), typeInfo)
+		if err != nil {
+			return err
+		}
+
+		b.WriteString(rendered)
+		return nil
+	}
+
+	operation := expr.GetOperation()
+	if operation.Op == core.CaveatOperation_NOT {
+		if len(operation.Children) != 1 {
+			return fmt.Errorf("found NOT operation with %d children", len(operation.Children))
+		}
+
+		b.WriteString("!(")
+		if err := convertIntoCEL(b, operation.Children[0], typeInfo); err != nil {
+			return err
+		}
+		b.WriteString(")")
+		return nil
+	}
+
+	var operator string
+	switch operation.Op {
+	case core.CaveatOperation_AND:
+		operator = " && "
+
+	case core.CaveatOperation_OR:
+		operator = " || "
+
+	default:
+		return fmt.Errorf("unknown caveat operation: %v", operation.Op)
+	}
+
+	if len(operation.Children) == 0 {
+		return fmt.Errorf("found %v operation without children", operation.Op)
+	}
+
+	for index, child := range operation.Children {
+		if index > 0 {
+			b.WriteString(operator)
+		}
+
+		// Parenthesize child operations to preserve the tree's precedence. NOT operations
+		// render their own parentheses.
+		if childOp := child.GetOperation(); childOp != nil && childOp.Op != core.CaveatOperation_NOT {
+			b.WriteString("(")
+			if err := convertIntoCEL(b, child, typeInfo); err != nil {
+				return err
+			}
+			b.WriteString(")")
+			continue
+		}
+
+		if err := convertIntoCEL(b, child, typeInfo); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func celForLeaf(caveat *core.ContextualizedCaveat, typeInfo *CaveatTypeInfo) (string, error) {
+	if typeInfo != nil {
+		if def, ok := typeInfo.Definitions[caveat.CaveatName]; ok {
+			return inlineCaveatSource(caveat, def, caveattypes.TypeSetOrDefault(typeInfo.TypeSet))
+		}
+	}
+
+	return symbolicCaveatCall(caveat), nil
+}
+
+// inlineCaveatSource renders the CEL source of the given caveat, with the relationship context
+// bound as literal values via partial evaluation.
+func inlineCaveatSource(caveat *core.ContextualizedCaveat, def *core.CaveatDefinition, ts *caveattypes.TypeSet) (string, error) {
+	parameterTypes, err := caveattypes.DecodeParameterTypes(ts, def.ParameterTypes)
+	if err != nil {
+		return "", err
+	}
+
+	compiled, err := caveats.DeserializeCaveatWithTypeSet(ts, def.SerializedExpression, parameterTypes)
+	if err != nil {
+		return "", err
+	}
+
+	contextMap := caveat.GetContext().AsMap()
+	if len(contextMap) == 0 {
+		exprString, err := compiled.ExprString()
+		if err != nil {
+			return "", err
+		}
+
+		return "(" + strings.TrimSpace(exprString) + ")", nil
+	}
+
+	typedContext, err := caveats.ConvertContextToParameters(ts, contextMap, def.ParameterTypes, caveats.SkipUnknownParameters)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := caveats.EvaluateCaveat(compiled, typedContext)
+	if err != nil {
+		return "", err
+	}
+
+	// If the context fully determines the caveat, it folds to a boolean literal.
+	if !result.IsPartial() {
+		return strconv.FormatBool(result.Value()), nil
+	}
+
+	partial, err := result.PartialValue()
+	if err != nil {
+		return "", err
+	}
+
+	exprString, err := partial.ExprString()
+	if err != nil {
+		return "", err
+	}
+
+	return "(" + strings.TrimSpace(exprString) + ")", nil
+}
+
+// symbolicCaveatCall renders the caveat as a symbolic call of its name over its context, for
+// use when the caveat's source is not available.
+func symbolicCaveatCall(caveat *core.ContextualizedCaveat) string {
+	var b strings.Builder
+	b.WriteString(caveat.CaveatName)
+	b.WriteString("(")
+	writeCELValue(&b, caveat.GetContext().AsMap())
+	b.WriteString(")")
+	return b.String()
+}
+
+func writeCELValue(b *strings.Builder, value any) {
+	switch typed := value.(type) {
+	case nil:
+		b.WriteString("null")
+
+	case string:
+		b.WriteString(strconv.Quote(typed))
+
+	case bool:
+		b.WriteString(strconv.FormatBool(typed))
+
+	case float64:
+		b.WriteString(strconv.FormatFloat(typed, 'g', -1, 64))
+
+	case []any:
+		b.WriteString("[")
+		for index, item := range typed {
+			if index > 0 {
+				b.WriteString(", ")
+			}
+			writeCELValue(b, item)
+		}
+		b.WriteString("]")
+
+	case map[string]any:
+		b.WriteString("{")
+		for index, key := range slices.Sorted(maps.Keys(typed)) {
+			if index > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(strconv.Quote(key))
+			b.WriteString(": ")
+			writeCELValue(b, typed[key])
+		}
+		b.WriteString("}")
+
+	default:
+		fmt.Fprintf(b, "%v", typed)
+	}
+}
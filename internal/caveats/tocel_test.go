@@ -0,0 +1,150 @@
+package caveats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pkgcaveats "github.com/authzed/spicedb/pkg/caveats"
+	"github.com/authzed/spicedb/pkg/caveats/types"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/schemadsl/compiler"
+	"github.com/authzed/spicedb/pkg/schemadsl/input"
+)
+
+const toCELTestSchema = `
+	caveat first(a int, b int) {
+		a + b == 42
+	}
+
+	caveat second(ip string, allowed list<string>) {
+		ip in allowed
+	}
+`
+
+func toCELTypeInfoForTesting(t *testing.T) *CaveatTypeInfo {
+	compiled, err := compiler.Compile(compiler.InputSchema{
+		Source:       input.Source("tocel"),
+		SchemaString: toCELTestSchema,
+	}, compiler.AllowUnprefixedObjectType())
+	require.NoError(t, err)
+
+	typeInfo := &CaveatTypeInfo{
+		Definitions: map[string]*core.CaveatDefinition{},
+	}
+	for _, def := range compiled.CaveatDefinitions {
+		typeInfo.Definitions[def.Name] = def
+	}
+	return typeInfo
+}
+
+// evaluateCEL compiles the given generated CEL source in an environment holding the given
+// variables and evaluates it over the given context.
+func evaluateCEL(t *testing.T, celSource string, vars map[string]types.VariableType, context map[string]any) bool {
+	req := require.New(t)
+
+	env, err := pkgcaveats.EnvForVariablesWithTypeSet(types.Default.TypeSet, vars)
+	req.NoError(err)
+
+	compiled, err := pkgcaveats.CompileCaveatWithName(env, celSource, "generated")
+	req.NoError(err)
+
+	typedContext, err := pkgcaveats.ConvertContextToParameters(types.Default.TypeSet, context, env.EncodedParametersTypes(), pkgcaveats.ErrorForUnknownParameters)
+	req.NoError(err)
+
+	result, err := pkgcaveats.EvaluateCaveat(compiled, typedContext)
+	req.NoError(err)
+	req.False(result.IsPartial())
+	return result.Value()
+}
+
+func TestExpressionToCELInlinesSources(t *testing.T) {
+	req := require.New(t)
+	typeInfo := toCELTypeInfoForTesting(t)
+
+	// A leaf without context inlines the caveat's source directly.
+	celSource, err := ExpressionToCEL(caveatexpr("first"), typeInfo)
+	req.NoError(err)
+	req.Equal("(a + b == 42)", celSource)
+
+	// A leaf with partial context binds the context values as literals.
+	boundLeaf := MustCaveatExprForTestingWithContext("second", map[string]any{
+		"ip": "1.2.3.4",
+	})
+
+	celSource, err = ExpressionToCEL(boundLeaf, typeInfo)
+	req.NoError(err)
+
+	allowedVar := map[string]types.VariableType{"allowed": types.Default.MustListType(types.Default.StringType)}
+	req.True(evaluateCEL(t, celSource, allowedVar, map[string]any{
+		"allowed": []any{"1.2.3.4", "4.5.6.7"},
+	}))
+	req.False(evaluateCEL(t, celSource, allowedVar, map[string]any{
+		"allowed": []any{"4.5.6.7"},
+	}))
+
+	// A leaf whose context fully determines its value folds to a boolean literal.
+	foldedLeaf := MustCaveatExprForTestingWithContext("second", map[string]any{
+		"ip":      "1.2.3.4",
+		"allowed": []any{"1.2.3.4"},
+	})
+
+	celSource, err = ExpressionToCEL(foldedLeaf, typeInfo)
+	req.NoError(err)
+	req.Equal("true", celSource)
+}
+
+func TestExpressionToCELEvaluationEquivalence(t *testing.T) {
+	req := require.New(t)
+	typeInfo := toCELTypeInfoForTesting(t)
+	reader := caveatReaderForSchema(t, toCELTestSchema)
+
+	// first && !second, with second's `ip` bound by the relationship context.
+	expr := Subtract(caveatexpr("first"), MustCaveatExprForTestingWithContext("second", map[string]any{
+		"ip": "1.2.3.4",
+	}))
+
+	celSource, err := ExpressionToCEL(expr, typeInfo)
+	req.NoError(err)
+
+	vars := map[string]types.VariableType{
+		"a":       types.Default.IntType,
+		"b":       types.Default.IntType,
+		"allowed": types.Default.MustListType(types.Default.StringType),
+	}
+
+	sampleContexts := []map[string]any{
+		{"a": int64(1), "b": int64(41), "allowed": []any{"4.5.6.7"}},
+		{"a": int64(1), "b": int64(41), "allowed": []any{"1.2.3.4"}},
+		{"a": int64(1), "b": int64(2), "allowed": []any{"4.5.6.7"}},
+	}
+
+	for _, context := range sampleContexts {
+		expected, err := NewCaveatRunner(types.Default.TypeSet).RunCaveatExpression(t.Context(), expr, context, reader, RunCaveatExpressionNoDebugging)
+		req.NoError(err)
+		req.False(expected.IsPartial())
+		req.Equal(expected.Value(), evaluateCEL(t, celSource, vars, context), "mismatch for context %v", context)
+	}
+}
+
+func TestExpressionToCELSymbolicCalls(t *testing.T) {
+	req := require.New(t)
+
+	// Without type information, caveats render as symbolic calls over their context.
+	expr := Subtract(caveatexpr("first"), MustCaveatExprForTestingWithContext("second", map[string]any{
+		"ip": "1.2.3.4",
+	}))
+
+	celSource, err := ExpressionToCEL(expr, nil)
+	req.NoError(err)
+	req.Equal(`first({}) && !(second({"ip": "1.2.3.4"}))`, celSource)
+
+	// Unknown caveats render symbolically even when other definitions are available.
+	typeInfo := toCELTypeInfoForTesting(t)
+	celSource, err = ExpressionToCEL(And(caveatexpr("first"), caveatexpr("unknowncaveat")), typeInfo)
+	req.NoError(err)
+	req.Equal("(a + b == 42) && unknowncaveat({})", celSource)
+
+	_, err = ExpressionToCEL(nil, typeInfo)
+	req.Error(err)
+}
@@ -0,0 +1,26 @@
+package common
+
+import (
+	"github.com/authzed/spicedb/pkg/datastore"
+	"github.com/authzed/spicedb/pkg/datastore/options"
+)
+
+// ValidateCursorResumption checks that a query resuming from a cursor is running at the
+// same revision at which the cursor was created, returning a CursorRevisionMismatchError
+// otherwise. The check is skipped if the caller opted into best-effort resumption or if
+// the reader's revision is unknown (such as within a read-write transaction).
+func ValidateCursorResumption(queryOpts *options.QueryOptions, atRevision datastore.Revision) error {
+	if queryOpts.After == nil || queryOpts.AfterRevision == "" || queryOpts.BestEffortResumption {
+		return nil
+	}
+
+	if atRevision == nil {
+		return nil
+	}
+
+	if atRevision.String() != queryOpts.AfterRevision {
+		return datastore.NewCursorRevisionMismatchErr(queryOpts.AfterRevision, atRevision.String())
+	}
+
+	return nil
+}
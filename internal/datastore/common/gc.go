@@ -50,6 +50,13 @@ var (
 		Help:      "The number of stale namespaces deleted by the datastore garbage collection.",
 	})
 
+	gcWatermarkAgeGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "spicedb",
+		Subsystem: "datastore",
+		Name:      "gc_watermark_age_seconds",
+		Help:      "The age of the garbage collection watermark, in seconds, as of the last completed run.",
+	})
+
 	gcFailureCounterConfig = prometheus.CounterOpts{
 		Namespace: "spicedb",
 		Subsystem: "datastore",
@@ -65,8 +72,10 @@ func RegisterGCMetrics() error {
 	for _, metric := range []prometheus.Collector{
 		gcDurationHistogram,
 		gcRelationshipsCounter,
+		gcExpiredRelationshipsCounter,
 		gcTransactionsCounter,
 		gcNamespacesCounter,
+		gcWatermarkAgeGauge,
 		gcFailureCounter,
 	} {
 		if err := prometheus.Register(metric); err != nil {
@@ -128,12 +137,38 @@ type GarbageCollector interface {
 	Close()
 }
 
+// ManualGCDatastore is implemented by datastores which support manually
+// triggered garbage collection passes. Detect support via datastore.UnwrapAs.
+type ManualGCDatastore interface {
+	// RunGC runs a single garbage collection pass against the datastore and
+	// returns the statistics of the pass.
+	RunGC(ctx context.Context) (GCStats, error)
+}
+
+// GCStats holds the statistics of a single garbage collection pass.
+type GCStats struct {
+	// Collected holds the counts of stale rows deleted by the pass.
+	Collected DeletionCounts `json:"collected"`
+
+	// ExpiredRelationships is the number of expired relationships deleted by
+	// the pass.
+	ExpiredRelationships int64 `json:"expired_relationships"`
+
+	// Duration is the total duration of the pass.
+	Duration time.Duration `json:"duration"`
+
+	// WatermarkAge is the time elapsed, as of the end of the pass, since the
+	// watermark cut-off before which data was collected. It includes any skew
+	// between the datastore's clock and the local clock.
+	WatermarkAge time.Duration `json:"watermark_age"`
+}
+
 // DeletionCounts tracks the amount of deletions that occurred when calling
 // DeleteBeforeTx.
 type DeletionCounts struct {
-	Relationships int64
-	Transactions  int64
-	Namespaces    int64
+	Relationships int64 `json:"relationships"`
+	Transactions  int64 `json:"transactions"`
+	Namespaces    int64 `json:"namespaces"`
 }
 
 func (g DeletionCounts) MarshalZerologObject(e *zerolog.Event) {
@@ -200,6 +235,19 @@ func startGarbageCollectorWithMaxElapsedTime(ctx context.Context, collectable Ga
 
 // RunGarbageCollection runs garbage collection for the datastore.
 func RunGarbageCollection(collectable GarbageCollectableDatastore, window, timeout time.Duration) error {
+	_, err := RunGarbageCollectionWithStats(collectable, window, timeout)
+	return err
+}
+
+// RunGarbageCollectionWithStats runs garbage collection for the datastore and
+// returns the statistics of the pass. A pass which was skipped (datastore not
+// ready, or another node holds the garbage collection lock) returns zero stats
+// and a nil error.
+func RunGarbageCollectionWithStats(collectable GarbageCollectableDatastore, window, timeout time.Duration) (GCStats, error) {
+	return runGarbageCollectionWithStats(collectable, window, timeout, time.Now)
+}
+
+func runGarbageCollectionWithStats(collectable GarbageCollectableDatastore, window, timeout time.Duration, localNow func() time.Time) (GCStats, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
@@ -207,32 +255,32 @@ func RunGarbageCollection(collectable GarbageCollectableDatastore, window, timeo
 	defer span.End()
 
 	// Before attempting anything, check if the datastore is ready.
-	startTime := time.Now()
+	startTime := localNow()
 	ready, err := collectable.ReadyState(ctx)
 	if err != nil {
-		return err
+		return GCStats{}, err
 	}
 	if !ready.IsReady {
 		log.Ctx(ctx).Warn().
 			Msgf("datastore wasn't ready when attempting garbage collection: %s", ready.Message)
-		return nil
+		return GCStats{}, nil
 	}
 
 	gc, err := collectable.BuildGarbageCollector(ctx)
 	if err != nil {
-		return fmt.Errorf("error building garbage collector: %w", err)
+		return GCStats{}, fmt.Errorf("error building garbage collector: %w", err)
 	}
 	defer gc.Close()
 
 	ok, err := gc.LockForGCRun(ctx)
 	if err != nil {
-		return fmt.Errorf("error locking for gc run: %w", err)
+		return GCStats{}, fmt.Errorf("error locking for gc run: %w", err)
 	}
 
 	if !ok {
 		log.Info().
 			Msg("datastore garbage collection already in progress on another node")
-		return nil
+		return GCStats{}, nil
 	}
 
 	defer func() {
@@ -246,12 +294,13 @@ func RunGarbageCollection(collectable GarbageCollectableDatastore, window, timeo
 
 	now, err := gc.Now(ctx)
 	if err != nil {
-		return fmt.Errorf("error retrieving now: %w", err)
+		return GCStats{}, fmt.Errorf("error retrieving now: %w", err)
 	}
 
-	watermark, err := gc.TxIDBefore(ctx, now.Add(-1*window))
+	watermarkTime := now.Add(-1 * window)
+	watermark, err := gc.TxIDBefore(ctx, watermarkTime)
 	if err != nil {
-		return fmt.Errorf("error retrieving watermark: %w", err)
+		return GCStats{}, fmt.Errorf("error retrieving watermark: %w", err)
 	}
 
 	collected, err := gc.DeleteBeforeTx(ctx, watermark)
@@ -264,25 +313,36 @@ func RunGarbageCollection(collectable GarbageCollectableDatastore, window, timeo
 	gcTransactionsCounter.Add(float64(collected.Transactions))
 	gcNamespacesCounter.Add(float64(collected.Namespaces))
 	gcExpiredRelationshipsCounter.Add(float64(expiredRelationshipsCount))
-	collectionDuration := time.Since(startTime)
+	collectionDuration := localNow().Sub(startTime)
 	gcDurationHistogram.Observe(collectionDuration.Seconds())
 
+	watermarkAge := localNow().Sub(watermarkTime)
+	gcWatermarkAgeGauge.Set(watermarkAge.Seconds())
+
+	stats := GCStats{
+		Collected:            collected,
+		ExpiredRelationships: expiredRelationshipsCount,
+		Duration:             collectionDuration,
+		WatermarkAge:         watermarkAge,
+	}
+
 	if err != nil {
-		return fmt.Errorf("error deleting in gc: %w", err)
+		return stats, fmt.Errorf("error deleting in gc: %w", err)
 	}
 
 	if eerr != nil {
-		return fmt.Errorf("error deleting expired relationships in gc: %w", eerr)
+		return stats, fmt.Errorf("error deleting expired relationships in gc: %w", eerr)
 	}
 
 	log.Ctx(ctx).Info().
 		Stringer("highestTxID", watermark).
 		Dur("duration", collectionDuration).
+		Dur("watermarkAge", watermarkAge).
 		Time("nowTime", now).
 		Interface("collected", collected).
 		Int64("expiredRelationships", expiredRelationshipsCount).
 		Msg("datastore garbage collection completed successfully")
 
 	collectable.MarkGCCompleted()
-	return nil
+	return stats, nil
 }
@@ -26,6 +26,9 @@ type fakeGC struct {
 	lastRevision uint64    // GUARDED_BY(lock)
 	wasLocked    bool      // GUARDED_BY(lock)
 	wasUnlocked  bool      // GUARDED_BY(lock)
+
+	// now, if set, is used as the datastore's clock in place of time.Now.
+	now func() time.Time
 }
 
 type gcMetrics struct {
@@ -101,7 +104,10 @@ func (gc *fakeGC) UnlockAfterGCRun() error {
 	return nil
 }
 
-func (*fakeGC) Now(_ context.Context) (time.Time, error) {
+func (gc *fakeGC) Now(_ context.Context) (time.Time, error) {
+	if gc.now != nil {
+		return gc.now(), nil
+	}
 	return time.Now(), nil
 }
 
@@ -153,6 +159,20 @@ type gcDeleter interface {
 	DeleteExpiredRels() (int64, error)
 }
 
+// Returns fixed deletion counts without erroring
+type fixedDeleter struct {
+	counts  DeletionCounts
+	expired int64
+}
+
+func (d fixedDeleter) DeleteBeforeTx(_ uint64) (DeletionCounts, error) {
+	return d.counts, nil
+}
+
+func (d fixedDeleter) DeleteExpiredRels() (int64, error) {
+	return d.expired, nil
+}
+
 // Always error trying to perform a delete
 type alwaysErrorDeleter struct{}
 
@@ -181,6 +201,35 @@ func (d revisionErrorDeleter) DeleteExpiredRels() (int64, error) {
 	return 0, nil
 }
 
+// Ensure the watermark age is computed against the local clock, including any
+// skew between the datastore's clock and the local clock.
+func TestGCStatsWatermarkAge(t *testing.T) {
+	localTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	datastoreTime := localTime.Add(-2 * time.Minute) // datastore clock runs behind
+
+	gc := newFakeGCStore(fixedDeleter{
+		counts: DeletionCounts{
+			Relationships: 10,
+			Transactions:  20,
+			Namespaces:    1,
+		},
+		expired: 5,
+	})
+	gc.fakeGC.now = func() time.Time { return datastoreTime }
+
+	window := 5 * time.Minute
+	stats, err := runGarbageCollectionWithStats(gc, window, 1*time.Minute, func() time.Time { return localTime })
+	require.NoError(t, err)
+
+	// The watermark cut-off is the datastore's now minus the window, so its
+	// age relative to the local clock includes the two minutes of skew.
+	require.Equal(t, window+2*time.Minute, stats.WatermarkAge)
+	require.Equal(t, time.Duration(0), stats.Duration)
+	require.Equal(t, DeletionCounts{Relationships: 10, Transactions: 20, Namespaces: 1}, stats.Collected)
+	require.Equal(t, int64(5), stats.ExpiredRelationships)
+	require.True(t, gc.HasGCRun())
+}
+
 func TestGCFailureBackoff(t *testing.T) {
 	localCounter := prometheus.NewCounter(gcFailureCounterConfig)
 	reg := prometheus.NewRegistry()
@@ -178,19 +178,26 @@ func QueryRelationships[R Rows, C ~map[string]any](ctx context.Context, builder
 					expiration = &t
 				}
 
+				resource := tuple.ObjectAndRelation{
+					ObjectType: resourceObjectType,
+					ObjectID:   resourceObjectID,
+					Relation:   resourceRelation,
+				}
+				subject := tuple.ObjectAndRelation{
+					ObjectType: subjectObjectType,
+					ObjectID:   subjectObjectID,
+					Relation:   subjectRelation,
+				}
+				if builder.InternONRs {
+					resource = tuple.InternONR(resource)
+					subject = tuple.InternONR(subject)
+				}
+
 				relCount++
 				if !yield(tuple.Relationship{
 					RelationshipReference: tuple.RelationshipReference{
-						Resource: tuple.ObjectAndRelation{
-							ObjectType: resourceObjectType,
-							ObjectID:   resourceObjectID,
-							Relation:   resourceRelation,
-						},
-						Subject: tuple.ObjectAndRelation{
-							ObjectType: subjectObjectType,
-							ObjectID:   subjectObjectID,
-							Relation:   subjectRelation,
-						},
+						Resource: resource,
+						Subject:  subject,
 					},
 					OptionalCaveat:     caveat,
 					OptionalExpiration: expiration,
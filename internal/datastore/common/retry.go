@@ -0,0 +1,136 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	log "github.com/authzed/spicedb/internal/logging"
+)
+
+var retriesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "spicedb",
+	Subsystem: "datastore",
+	Name:      "transaction_retries_total",
+	Help:      "The number of retried transaction attempts, by backend and error class.",
+}, []string{"backend", "class"})
+
+// RecordRetry records a retried transaction attempt for backends whose retry
+// handling cannot be expressed via RetryPolicy, such as the CRDB pool, which
+// also resets connections and tracks node health between attempts.
+func RecordRetry(backend, class string) {
+	retriesCounter.WithLabelValues(backend, class).Inc()
+}
+
+const (
+	// defaultRetryBaseDelay is the backoff before the first retry when no base
+	// delay is configured on a RetryPolicy.
+	defaultRetryBaseDelay = 25 * time.Millisecond
+
+	// maxRetryBackoffShift caps the exponential backoff growth to avoid overflow.
+	maxRetryBackoffShift = 16
+)
+
+// RetryErrorClassifier classifies an error encountered during a transaction
+// attempt, returning the class label used for metrics and whether the error is
+// retryable.
+type RetryErrorClassifier func(err error) (class string, retryable bool)
+
+// RetryPolicy retries transaction attempts that fail with transient errors,
+// applying exponential backoff with optional jitter between attempts.
+type RetryPolicy struct {
+	// Backend is the datastore engine name used as the metric label.
+	Backend string
+
+	// MaxRetries is the maximum number of retries performed after the initial
+	// attempt.
+	MaxRetries uint8
+
+	// BaseDelay is the backoff before the first retry, doubled for each retry
+	// thereafter. If given the zero value, defaultRetryBaseDelay is used.
+	BaseDelay time.Duration
+
+	// JitterFraction, if non-zero, scales each backoff by a random factor in
+	// [1-JitterFraction, 1+JitterFraction].
+	JitterFraction float64
+
+	// Classifier determines whether an error encountered during an attempt is
+	// retryable.
+	Classifier RetryErrorClassifier
+
+	// sleepFn, if non-nil, overrides sleeping between attempts for tests.
+	sleepFn func(ctx context.Context, after time.Duration) error
+}
+
+// ExecuteWithRetries invokes the given attempt function until it succeeds, fails
+// with a non-retryable error, or the configured maximum number of retries is
+// exhausted, in which case the last error is wrapped to indicate so. If
+// disableRetries is true, the first error is returned regardless of its class.
+// Context cancellation is honored between attempts.
+func (p RetryPolicy) ExecuteWithRetries(ctx context.Context, disableRetries bool, attempt func(ctx context.Context) error) error {
+	for retries := uint8(0); ; retries++ {
+		err := attempt(ctx)
+		if err == nil {
+			if retries > 0 {
+				log.Ctx(ctx).Debug().Uint8("retries", retries).Msg("transaction succeeded after retry")
+			}
+			return nil
+		}
+
+		class, retryable := p.Classifier(err)
+		if !retryable || disableRetries {
+			return err
+		}
+
+		if retries >= p.MaxRetries {
+			return fmt.Errorf("max retries exceeded: %w", err)
+		}
+
+		retriesCounter.WithLabelValues(p.Backend, class).Inc()
+		if sleepErr := p.sleepBeforeRetry(ctx, err, retries); sleepErr != nil {
+			return sleepErr
+		}
+	}
+}
+
+// sleepBeforeRetry sleeps for the backoff computed for the given retry count,
+// returning early if the context is closed.
+func (p RetryPolicy) sleepBeforeRetry(ctx context.Context, err error, retries uint8) error {
+	after := p.backoff(retries)
+	log.Ctx(ctx).Debug().Err(err).Dur("after", after).Uint8("retry", retries+1).Msg("retrying on database error")
+
+	if p.sleepFn != nil {
+		return p.sleepFn(ctx, after)
+	}
+
+	select {
+	case <-time.After(after):
+		return nil
+	case <-ctx.Done():
+		return errors.Join(ctx.Err(), err)
+	}
+}
+
+// backoff returns the delay before the retry following the given number of
+// completed retries.
+func (p RetryPolicy) backoff(retries uint8) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+
+	if retries > maxRetryBackoffShift {
+		retries = maxRetryBackoffShift
+	}
+
+	delay := base << retries
+	if p.JitterFraction > 0 {
+		delay = time.Duration(float64(delay) * (1 - p.JitterFraction + 2*p.JitterFraction*rand.Float64()))
+	}
+	return delay
+}
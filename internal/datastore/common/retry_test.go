@@ -0,0 +1,164 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errFlaky = errors.New("flaky error")
+
+func alwaysRetryable(error) (string, bool) {
+	return "flaky", true
+}
+
+func neverRetryable(error) (string, bool) {
+	return "", false
+}
+
+// flakyExecutor fails with the given error for the first failures attempts and
+// succeeds thereafter, counting every attempt made.
+type flakyExecutor struct {
+	failures int
+	err      error
+	attempts int
+}
+
+func (f *flakyExecutor) attempt(_ context.Context) error {
+	f.attempts++
+	if f.attempts <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func recordingSleepFn(delays *[]time.Duration) func(ctx context.Context, after time.Duration) error {
+	return func(_ context.Context, after time.Duration) error {
+		*delays = append(*delays, after)
+		return nil
+	}
+}
+
+func TestRetryPolicySucceedsAfterRetries(t *testing.T) {
+	var delays []time.Duration
+	executor := &flakyExecutor{failures: 3, err: errFlaky}
+	policy := RetryPolicy{
+		Backend:    "test",
+		MaxRetries: 5,
+		BaseDelay:  25 * time.Millisecond,
+		Classifier: alwaysRetryable,
+		sleepFn:    recordingSleepFn(&delays),
+	}
+
+	err := policy.ExecuteWithRetries(t.Context(), false, executor.attempt)
+	require.NoError(t, err)
+	require.Equal(t, 4, executor.attempts)
+
+	// Without jitter, the backoff doubles on each retry.
+	require.Equal(t, []time.Duration{
+		25 * time.Millisecond,
+		50 * time.Millisecond,
+		100 * time.Millisecond,
+	}, delays)
+}
+
+func TestRetryPolicyBackoffJitterBounds(t *testing.T) {
+	var delays []time.Duration
+	executor := &flakyExecutor{failures: 4, err: errFlaky}
+	policy := RetryPolicy{
+		Backend:        "test",
+		MaxRetries:     5,
+		BaseDelay:      25 * time.Millisecond,
+		JitterFraction: 0.5,
+		Classifier:     alwaysRetryable,
+		sleepFn:        recordingSleepFn(&delays),
+	}
+
+	err := policy.ExecuteWithRetries(t.Context(), false, executor.attempt)
+	require.NoError(t, err)
+	require.Len(t, delays, 4)
+
+	// Each backoff grows exponentially, scaled by a jitter factor in [0.5, 1.5].
+	expected := 25 * time.Millisecond
+	for _, delay := range delays {
+		require.GreaterOrEqual(t, delay, expected/2)
+		require.LessOrEqual(t, delay, expected*3/2)
+		expected *= 2
+	}
+}
+
+func TestRetryPolicyNonRetryableErrorPassesThrough(t *testing.T) {
+	var delays []time.Duration
+	errFatal := errors.New("fatal error")
+	executor := &flakyExecutor{failures: 10, err: errFatal}
+	policy := RetryPolicy{
+		Backend:    "test",
+		MaxRetries: 5,
+		Classifier: neverRetryable,
+		sleepFn:    recordingSleepFn(&delays),
+	}
+
+	err := policy.ExecuteWithRetries(t.Context(), false, executor.attempt)
+	require.Equal(t, errFatal, err)
+	require.Equal(t, 1, executor.attempts)
+	require.Empty(t, delays)
+}
+
+func TestRetryPolicyMaxRetriesExhausted(t *testing.T) {
+	var delays []time.Duration
+	executor := &flakyExecutor{failures: 10, err: errFlaky}
+	policy := RetryPolicy{
+		Backend:    "test",
+		MaxRetries: 2,
+		Classifier: alwaysRetryable,
+		sleepFn:    recordingSleepFn(&delays),
+	}
+
+	err := policy.ExecuteWithRetries(t.Context(), false, executor.attempt)
+	require.ErrorIs(t, err, errFlaky)
+	require.ErrorContains(t, err, "max retries exceeded")
+	require.Equal(t, 3, executor.attempts)
+	require.Len(t, delays, 2)
+}
+
+func TestRetryPolicyDisableRetries(t *testing.T) {
+	var delays []time.Duration
+	executor := &flakyExecutor{failures: 10, err: errFlaky}
+	policy := RetryPolicy{
+		Backend:    "test",
+		MaxRetries: 5,
+		Classifier: alwaysRetryable,
+		sleepFn:    recordingSleepFn(&delays),
+	}
+
+	err := policy.ExecuteWithRetries(t.Context(), true, executor.attempt)
+	require.Equal(t, errFlaky, err)
+	require.Equal(t, 1, executor.attempts)
+	require.Empty(t, delays)
+}
+
+func TestRetryPolicyHonorsContextBetweenAttempts(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+
+	attempts := 0
+	policy := RetryPolicy{
+		Backend:    "test",
+		MaxRetries: 5,
+		BaseDelay:  1 * time.Hour,
+		Classifier: alwaysRetryable,
+	}
+
+	startTime := time.Now()
+	err := policy.ExecuteWithRetries(ctx, false, func(_ context.Context) error {
+		attempts++
+		cancel()
+		return errFlaky
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, err, errFlaky)
+	require.Equal(t, 1, attempts)
+	require.Less(t, time.Since(startTime), time.Minute)
+}
@@ -194,21 +194,35 @@ func (sqf SchemaQueryFilterer) UnderlyingQueryBuilder() sq.SelectBuilder {
 	spiceerrors.DebugAssertf(func() bool {
 		return sqf.isCustomQuery
 	}, "UnderlyingQueryBuilder should only be called on custom queries")
-	return sqf.queryBuilderWithMaybeExpirationFilter(false)
+	return sqf.queryBuilderWithMaybeExpirationFilter(false, options.ExcludeExpiredRelationships)
 }
 
 // queryBuilderWithMaybeExpirationFilter returns the query builder with the expiration filter applied, when necessary.
 // Note that this adds the clause to the existing builder.
-func (sqf SchemaQueryFilterer) queryBuilderWithMaybeExpirationFilter(skipExpiration bool) sq.SelectBuilder {
+func (sqf SchemaQueryFilterer) queryBuilderWithMaybeExpirationFilter(skipExpiration bool, expirationBehavior options.ExpirationBehavior) sq.SelectBuilder {
 	if sqf.schema.ExpirationDisabled || skipExpiration {
 		return sqf.queryBuilder
 	}
 
-	// Filter out any expired relationships.
-	return sqf.queryBuilder.Where(sq.Or{
-		sq.Eq{sqf.schema.ColExpiration: nil},
-		sq.Expr(sqf.schema.ColExpiration + " > " + sqf.schema.NowFunction + "()"),
-	})
+	switch expirationBehavior {
+	case options.IncludeExpiredRelationships:
+		// Return expired-but-uncollected relationships alongside live relationships.
+		return sqf.queryBuilder
+
+	case options.OnlyExpiredRelationships:
+		// Return only relationships whose expiration has passed.
+		return sqf.queryBuilder.Where(sq.And{
+			sq.NotEq{sqf.schema.ColExpiration: nil},
+			sq.Expr(sqf.schema.ColExpiration + " <= " + sqf.schema.NowFunction + "()"),
+		})
+
+	default:
+		// Filter out any expired relationships.
+		return sqf.queryBuilder.Where(sq.Or{
+			sq.Eq{sqf.schema.ColExpiration: nil},
+			sq.Expr(sqf.schema.ColExpiration + " > " + sqf.schema.NowFunction + "()"),
+		})
+	}
 }
 
 func (sqf SchemaQueryFilterer) TupleOrder(order options.SortOrder) SchemaQueryFilterer {
@@ -778,6 +792,8 @@ func (exc QueryRelationshipsExecutor) ExecuteQuery(
 		Schema:                    query.schema,
 		SkipCaveats:               queryOpts.SkipCaveats,
 		SkipExpiration:            queryOpts.SkipExpiration,
+		ExpirationBehavior:        queryOpts.ExpirationBehavior,
+		InternONRs:                queryOpts.InternONRs,
 		SQLCheckAssertionForTest:  queryOpts.SQLCheckAssertionForTest,
 		SQLExplainCallbackForTest: queryOpts.SQLExplainCallbackForTest,
 		filteringValues:           query.filteringColumnTracker,
@@ -791,9 +807,11 @@ func (exc QueryRelationshipsExecutor) ExecuteQuery(
 // RelationshipsQueryBuilder is a builder for producing the SQL and arguments necessary for reading
 // relationships.
 type RelationshipsQueryBuilder struct {
-	Schema         SchemaInformation
-	SkipCaveats    bool
-	SkipExpiration bool
+	Schema             SchemaInformation
+	SkipCaveats        bool
+	SkipExpiration     bool
+	ExpirationBehavior options.ExpirationBehavior
+	InternONRs         bool
 
 	filteringValues           columnTrackerMap
 	baseQueryBuilder          SchemaQueryFilterer
@@ -860,7 +878,7 @@ func (b RelationshipsQueryBuilder) SelectSQL() (string, []any, error) {
 		columnNamesToSelect = append(columnNamesToSelect, "1")
 	}
 
-	sqlBuilder := b.baseQueryBuilder.queryBuilderWithMaybeExpirationFilter(b.SkipExpiration)
+	sqlBuilder := b.baseQueryBuilder.queryBuilderWithMaybeExpirationFilter(b.SkipExpiration, b.ExpirationBehavior)
 	sqlBuilder = sqlBuilder.Columns(columnNamesToSelect...)
 
 	sql, args, err := sqlBuilder.ToSql()
@@ -831,7 +831,7 @@ func TestSchemaQueryFilterer(t *testing.T) {
 
 					require.ElementsMatch(t, expected.staticCols, foundStaticColumns)
 
-					ran.queryBuilder = ran.queryBuilderWithMaybeExpirationFilter(test.withExpirationDisabled).Columns("*")
+					ran.queryBuilder = ran.queryBuilderWithMaybeExpirationFilter(test.withExpirationDisabled, options.ExcludeExpiredRelationships).Columns("*")
 
 					sql, args, err := ran.queryBuilder.ToSql()
 					require.NoError(t, err)
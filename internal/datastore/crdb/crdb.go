@@ -61,6 +61,8 @@ const (
 	queryTransactionNow       = "SHOW COMMIT TIMESTAMP"
 	queryShowZoneConfig       = "SHOW ZONE CONFIGURATION FOR RANGE default;"
 
+	queryFollowerReadTimestamp = "follower_read_timestamp()"
+
 	spicedbTransactionKey = "$spicedb_transaction_key"
 )
 
@@ -195,6 +197,8 @@ func newCRDBDatastore(ctx context.Context, url string, options ...Option) (datas
 		filterMaximumIDCount:    config.filterMaximumIDCount,
 		supportsIntegrity:       config.withIntegrity,
 		gcWindow:                config.gcWindow,
+		followerReadsEnabled:    config.followerReadsEnabled,
+		followerReadThreshold:   config.followerReadThreshold,
 		watchEnabled:            !config.watchDisabled,
 		schema:                  *schema.Schema(config.columnOptimizationOption, config.withIntegrity, false),
 	}
@@ -282,6 +286,8 @@ type crdbDatastore struct {
 	overlapKeyInit          func(ctx context.Context) keySet
 	analyzeBeforeStatistics bool
 	gcWindow                time.Duration
+	followerReadsEnabled    bool
+	followerReadThreshold   time.Duration
 	schema                  common.SchemaInformation
 	acquireTimeout          time.Duration
 
@@ -306,6 +312,14 @@ func (cds *crdbDatastore) SnapshotReader(rev datastore.Revision) datastore.Reade
 	executor := common.QueryRelationshipsExecutor{
 		Executor: pgxcommon.NewPGXQueryRelationshipsExecutor(cds.readPool, cds),
 	}
+
+	atSpecificRevision := rev.String()
+	followerRead := false
+	if cds.followerReadsEnabled && cds.isFollowerReadCandidate(rev) {
+		atSpecificRevision = queryFollowerReadTimestamp
+		followerRead = true
+	}
+
 	return &crdbReader{
 		schema:               cds.schema,
 		query:                cds.readPool,
@@ -314,10 +328,25 @@ func (cds *crdbDatastore) SnapshotReader(rev datastore.Revision) datastore.Reade
 		overlapKeySet:        nil,
 		filterMaximumIDCount: cds.filterMaximumIDCount,
 		withIntegrity:        cds.supportsIntegrity,
-		atSpecificRevision:   rev.String(),
+		atSpecificRevision:   atSpecificRevision,
+		followerRead:         followerRead,
 	}
 }
 
+// isFollowerReadCandidate returns true if the requested revision is older than
+// the follower read threshold. Since the threshold is at least the cluster's
+// follower read lag, follower_read_timestamp() is then guaranteed to be at
+// least as fresh as the revision, so substituting it never serves data staler
+// than the requested zedtoken allows.
+func (cds *crdbDatastore) isFollowerReadCandidate(rev datastore.Revision) bool {
+	hlc, ok := rev.(revisions.HLCRevision)
+	if !ok {
+		return false
+	}
+
+	return time.Since(time.Unix(0, hlc.TimestampNanoSec())) > cds.followerReadThreshold
+}
+
 func (cds *crdbDatastore) MetricsID() (string, error) {
 	return common.MetricsIDFromURL(cds.dburl)
 }
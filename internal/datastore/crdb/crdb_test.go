@@ -18,6 +18,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -178,6 +179,67 @@ func TestCRDBDatastoreWithFollowerReads(t *testing.T) {
 	}
 }
 
+func TestCRDBDatastoreFollowerReadsEnabled(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	engine := testdatastore.RunCRDBForTesting(t, "", crdbTestVersion())
+	ds := engine.NewDatastore(t, func(engineName, uri string) datastore.Datastore {
+		ds, err := NewCRDBDatastore(
+			ctx,
+			uri,
+			GCWindow(veryLargeGCWindow),
+			RevisionQuantization(0),
+			WithFollowerReadsEnabled(true),
+			FollowerReadThreshold(100*time.Millisecond),
+			WithAcquireTimeout(5*time.Second),
+		)
+		require.NoError(err)
+		return ds
+	})
+	t.Cleanup(func() {
+		require.NoError(ds.Close())
+	})
+
+	ds, writtenRev := testfixtures.StandardDatastoreWithData(ds, require)
+
+	// Wait until follower_read_timestamp() lands after the schema and data
+	// were written; on a fresh single-node cluster it trails realtime by
+	// several seconds and would otherwise predate the tables themselves.
+	time.Sleep(6 * time.Second)
+
+	queryAtRevision := func(rev datastore.Revision) (count int, sawFollowerRead bool) {
+		iter, err := ds.SnapshotReader(rev).QueryRelationships(ctx, datastore.RelationshipsFilter{
+			OptionalResourceType: testfixtures.DocumentNS.Name,
+		},
+			options.WithQueryShape(queryshape.FindResourceOfType),
+			options.WithSQLCheckAssertionForTest(func(sql string) {
+				sawFollowerRead = strings.Contains(sql, queryFollowerReadTimestamp)
+			}))
+		require.NoError(err)
+		for _, err := range iter {
+			require.NoError(err)
+			count++
+		}
+		return count, sawFollowerRead
+	}
+
+	// A revision older than the follower read threshold must be served at
+	// follower_read_timestamp().
+	count, sawFollowerRead := queryAtRevision(writtenRev)
+	require.NotZero(count)
+	require.True(sawFollowerRead, "expected AS OF SYSTEM TIME follower_read_timestamp() clause")
+
+	// The head revision is too fresh for a follower read and must be served
+	// at the revision itself.
+	headRev, err := ds.HeadRevision(ctx)
+	require.NoError(err)
+	count, sawFollowerRead = queryAtRevision(headRev)
+	require.NotZero(count)
+	require.False(sawFollowerRead, "expected query at the requested revision")
+}
+
 var defaultKeyForTesting = proxy.KeyConfig{
 	ID: "defaultfortest",
 	Bytes: (func() []byte {
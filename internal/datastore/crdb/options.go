@@ -20,6 +20,8 @@ type crdbOptions struct {
 	watchConnectTimeout            time.Duration
 	revisionQuantization           time.Duration
 	followerReadDelay              time.Duration
+	followerReadsEnabled           bool
+	followerReadThreshold          time.Duration
 	maxRevisionStalenessPercent    float64
 	gcWindow                       time.Duration
 	maxRetries                     uint8
@@ -38,15 +40,21 @@ type crdbOptions struct {
 }
 
 const (
-	errQuantizationTooLarge = "revision quantization (%s) must be less than GC window (%s)"
+	errQuantizationTooLarge        = "revision quantization (%s) must be less than GC window (%s)"
+	errFollowerReadThresholdNotSet = "follower read threshold (%s) must be positive when follower reads are enabled"
 
 	overlapStrategyRequest  = "request"
 	overlapStrategyPrefix   = "prefix"
 	overlapStrategyStatic   = "static"
 	overlapStrategyInsecure = "insecure"
 
-	defaultRevisionQuantization        = 5 * time.Second
-	defaultFollowerReadDelay           = 0 * time.Second
+	defaultRevisionQuantization = 5 * time.Second
+	defaultFollowerReadDelay    = 0 * time.Second
+	defaultFollowerReadsEnabled = false
+
+	// defaultFollowerReadThreshold is CockroachDB's default follower read lag,
+	// i.e. the amount by which follower_read_timestamp() trails realtime.
+	defaultFollowerReadThreshold       = 4800 * time.Millisecond
 	defaultMaxRevisionStalenessPercent = 0.1
 	defaultWatchBufferLength           = 128
 	defaultWatchBufferWriteTimeout     = 1 * time.Second
@@ -80,6 +88,8 @@ func generateConfig(options []Option) (crdbOptions, error) {
 		watchConnectTimeout:            defaultWatchConnectTimeout,
 		revisionQuantization:           defaultRevisionQuantization,
 		followerReadDelay:              defaultFollowerReadDelay,
+		followerReadsEnabled:           defaultFollowerReadsEnabled,
+		followerReadThreshold:          defaultFollowerReadThreshold,
 		maxRevisionStalenessPercent:    defaultMaxRevisionStalenessPercent,
 		maxRetries:                     defaultMaxRetries,
 		overlapKey:                     defaultOverlapKey,
@@ -108,6 +118,10 @@ func generateConfig(options []Option) (crdbOptions, error) {
 		)
 	}
 
+	if computed.followerReadsEnabled && computed.followerReadThreshold <= 0 {
+		return computed, fmt.Errorf(errFollowerReadThresholdNotSet, computed.followerReadThreshold)
+	}
+
 	if computed.filterMaximumIDCount == 0 {
 		computed.filterMaximumIDCount = 100
 		log.Warn().Msg("filterMaximumIDCount not set, defaulting to 100")
@@ -287,6 +301,26 @@ func FollowerReadDelay(delay time.Duration) Option {
 	return func(po *crdbOptions) { po.followerReadDelay = delay }
 }
 
+// WithFollowerReadsEnabled marks whether snapshot reads for revisions older
+// than the follower read threshold are served using CockroachDB's
+// follower_read_timestamp(), allowing them to be answered by the nearest
+// replica instead of the leaseholder.
+//
+// Follower reads are disabled by default.
+func WithFollowerReadsEnabled(followerReadsEnabled bool) Option {
+	return func(po *crdbOptions) { po.followerReadsEnabled = followerReadsEnabled }
+}
+
+// FollowerReadThreshold is the minimum age a requested revision must have
+// before its snapshot reads are served at follower_read_timestamp(). It must
+// be at least the cluster's follower read lag, so that the follower read
+// timestamp is guaranteed to be at least as fresh as the requested revision.
+//
+// This value defaults to 4.8 seconds, CockroachDB's default follower read lag.
+func FollowerReadThreshold(threshold time.Duration) Option {
+	return func(po *crdbOptions) { po.followerReadThreshold = threshold }
+}
+
 // MaxRevisionStalenessPercent is the amount of time, expressed as a percentage of
 // the revision quantization window, that a previously computed rounded revision
 // can still be advertised after the next rounded revision would otherwise be ready.
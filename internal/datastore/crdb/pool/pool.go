@@ -15,6 +15,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/time/rate"
 
+	dscommon "github.com/authzed/spicedb/internal/datastore/common"
 	"github.com/authzed/spicedb/internal/datastore/postgres/common"
 	log "github.com/authzed/spicedb/internal/logging"
 )
@@ -333,6 +334,7 @@ func (p *RetryPool) withRetries(ctx context.Context, acquireTimeout time.Duratio
 		)
 		if errors.As(err, &resettable) || conn.Conn().IsClosed() {
 			log.Ctx(ctx).Info().Err(err).Uint8("retries", retries).Msg("resettable error")
+			dscommon.RecordRetry("cockroachdb", "resettable")
 
 			nodeID := p.Node(conn.Conn())
 			p.GC(conn.Conn())
@@ -355,6 +357,7 @@ func (p *RetryPool) withRetries(ctx context.Context, acquireTimeout time.Duratio
 		}
 		if errors.As(err, &retryable) {
 			log.Ctx(ctx).Info().Err(err).Uint8("retries", retries).Msg("retryable error")
+			dscommon.RecordRetry("cockroachdb", "retryable")
 			common.SleepOnErr(ctx, err, retries)
 			continue
 		}
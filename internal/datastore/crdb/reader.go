@@ -9,6 +9,7 @@ import (
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/shopspring/decimal"
 
 	"github.com/authzed/spicedb/internal/datastore/common"
@@ -27,6 +28,17 @@ const (
 	errUnableToReadCounter    = "unable to read relationship counter: %w"
 )
 
+var followerReadQueryCount = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "spicedb",
+	Subsystem: "datastore",
+	Name:      "crdb_follower_read_queries_total",
+	Help:      "number of snapshot queries served at follower_read_timestamp()",
+})
+
+func init() {
+	prometheus.MustRegister(followerReadQueryCount)
+}
+
 var (
 	queryReadNamespace = psql.Select(schema.ColConfig, schema.ColTimestamp)
 
@@ -49,6 +61,7 @@ type crdbReader struct {
 	filterMaximumIDCount uint16
 	withIntegrity        bool
 	atSpecificRevision   string
+	followerRead         bool
 }
 
 const (
@@ -65,6 +78,7 @@ func (cr *crdbReader) addFromToQuery(query sq.SelectBuilder, tableName string, i
 		return query.From(tableName)
 	}
 
+	cr.countFollowerRead()
 	return query.From(tableName + " " + asOfSystemTime + " " + cr.atSpecificRevision)
 }
 
@@ -73,9 +87,18 @@ func (cr *crdbReader) fromSuffix() string {
 		return ""
 	}
 
+	cr.countFollowerRead()
 	return " " + asOfSystemTime + " " + cr.atSpecificRevision
 }
 
+// countFollowerRead increments the follower read metric when the reader serves
+// its queries at follower_read_timestamp().
+func (cr *crdbReader) countFollowerRead() {
+	if cr.followerRead {
+		followerReadQueryCount.Inc()
+	}
+}
+
 func (cr *crdbReader) assertHasExpectedAsOfSystemTime(sql string) {
 	spiceerrors.DebugAssertf(func() bool {
 		if cr.atSpecificRevision == "" {
@@ -286,6 +309,7 @@ func (cr *crdbReader) ReverseQueryRelationships(
 		options.WithSort(queryOpts.SortForReverse),
 		options.WithSkipCaveats(queryOpts.SkipCaveatsForReverse),
 		options.WithSkipExpiration(queryOpts.SkipExpirationForReverse),
+		options.WithExpirationBehavior(queryOpts.ExpirationBehaviorForReverse),
 		options.WithQueryShape(queryOpts.QueryShapeForReverse),
 		options.WithSQLExplainCallbackForTest(queryOpts.SQLExplainCallbackForTestForReverse),
 	}
@@ -33,6 +33,22 @@ func NewMemDBDatastoreForTesting(
 	return validatingDatastore{ds}, nil
 }
 
+// NewMemDBDatastoreWithRequiredIntegrityForTesting creates a new in-memory datastore for
+// testing which requires that all written relationships carry integrity information, as
+// NewMemDBDatastoreForTesting does for a standard memdb datastore.
+func NewMemDBDatastoreWithRequiredIntegrityForTesting(
+	watchBufferLength uint16,
+	revisionQuantization,
+	gcWindow time.Duration,
+) (datastore.Datastore, error) {
+	ds, err := memdb.NewMemdbDatastoreWithRequiredIntegrity(watchBufferLength, revisionQuantization, gcWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	return validatingDatastore{ds}, nil
+}
+
 type validatingDatastore struct {
 	datastore.Datastore
 }
@@ -1,12 +1,15 @@
 package memdb
 
 import (
+	"fmt"
+
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	v1 "github.com/authzed/authzed-go/proto/authzed/api/v1"
 
 	"github.com/authzed/spicedb/pkg/spiceerrors"
+	"github.com/authzed/spicedb/pkg/tuple"
 )
 
 // SerializationMaxRetriesReachedError occurs when a write request has reached its maximum number
@@ -22,6 +25,19 @@ func NewSerializationMaxRetriesReachedErr(baseErr error) error {
 	}
 }
 
+// RelationshipIntegrityRequiredError occurs when a relationship is written without integrity
+// information to a datastore constructed with integrity required.
+type RelationshipIntegrityRequiredError struct {
+	error
+}
+
+// NewRelationshipIntegrityRequiredErr constructs a new relationship integrity required error.
+func NewRelationshipIntegrityRequiredErr(rel tuple.Relationship) error {
+	return RelationshipIntegrityRequiredError{
+		error: fmt.Errorf("relationship `%s` is missing required integrity data", tuple.StringWithoutCaveatOrExpiration(rel)),
+	}
+}
+
 // GRPCStatus implements retrieving the gRPC status for the error.
 func (err SerializationMaxRetriesReachedError) GRPCStatus() *status.Status {
 	return spiceerrors.WithCodeAndDetails(
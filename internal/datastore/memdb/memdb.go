@@ -43,6 +43,27 @@ func NewMemdbDatastore(
 	watchBufferLength uint16,
 	revisionQuantization,
 	gcWindow time.Duration,
+) (datastore.Datastore, error) {
+	return newMemdbDatastore(watchBufferLength, revisionQuantization, gcWindow, false)
+}
+
+// NewMemdbDatastoreWithRequiredIntegrity creates a new Datastore compliant datastore backed
+// by memdb which rejects any written relationship that does not carry integrity information.
+// This mirrors the behavior of backends configured with integrity enabled and is intended for
+// running the relationship integrity machinery fully in memory.
+func NewMemdbDatastoreWithRequiredIntegrity(
+	watchBufferLength uint16,
+	revisionQuantization,
+	gcWindow time.Duration,
+) (datastore.Datastore, error) {
+	return newMemdbDatastore(watchBufferLength, revisionQuantization, gcWindow, true)
+}
+
+func newMemdbDatastore(
+	watchBufferLength uint16,
+	revisionQuantization,
+	gcWindow time.Duration,
+	requireIntegrity bool,
 ) (datastore.Datastore, error) {
 	if revisionQuantization > gcWindow {
 		return nil, errors.New("gc window must be larger than quantization interval")
@@ -78,6 +99,7 @@ func NewMemdbDatastore(
 		quantizationPeriod:      revisionQuantization.Nanoseconds(),
 		watchBufferLength:       watchBufferLength,
 		watchBufferWriteTimeout: 100 * time.Millisecond,
+		requireIntegrity:        requireIntegrity,
 		uniqueID:                uniqueID,
 	}, nil
 }
@@ -95,6 +117,7 @@ type memdbDatastore struct {
 	quantizationPeriod      int64
 	watchBufferLength       uint16
 	watchBufferWriteTimeout time.Duration
+	requireIntegrity        bool
 	uniqueID                string
 }
 
@@ -116,15 +139,15 @@ func (mdb *memdbDatastore) SnapshotReader(dr datastore.Revision) datastore.Reade
 	defer mdb.RUnlock()
 
 	if err := mdb.checkNotClosed(); err != nil {
-		return &memdbReader{nil, nil, err, time.Now()}
+		return &memdbReader{nil, nil, err, time.Now(), dr}
 	}
 
 	if len(mdb.revisions) == 0 {
-		return &memdbReader{nil, nil, errors.New("memdb datastore is not ready"), time.Now()}
+		return &memdbReader{nil, nil, errors.New("memdb datastore is not ready"), time.Now(), dr}
 	}
 
 	if err := mdb.checkRevisionLocalCallerMustLock(dr); err != nil {
-		return &memdbReader{nil, nil, err, time.Now()}
+		return &memdbReader{nil, nil, err, time.Now(), dr}
 	}
 
 	revIndex := sort.Search(len(mdb.revisions), func(i int) bool {
@@ -138,7 +161,7 @@ func (mdb *memdbDatastore) SnapshotReader(dr datastore.Revision) datastore.Reade
 
 	rev := mdb.revisions[revIndex]
 	if rev.db == nil {
-		return &memdbReader{nil, nil, errors.New("memdb datastore is already closed"), time.Now()}
+		return &memdbReader{nil, nil, errors.New("memdb datastore is already closed"), time.Now(), dr}
 	}
 
 	roTxn := rev.db.Txn(false)
@@ -146,7 +169,7 @@ func (mdb *memdbDatastore) SnapshotReader(dr datastore.Revision) datastore.Reade
 		return roTxn, nil
 	}
 
-	return &memdbReader{noopTryLocker{}, txSrc, nil, time.Now()}
+	return &memdbReader{noopTryLocker{}, txSrc, nil, time.Now(), dr}
 }
 
 func (mdb *memdbDatastore) SupportsIntegrity() bool {
@@ -191,7 +214,7 @@ func (mdb *memdbDatastore) ReadWriteTx(
 		}
 
 		newRevision := mdb.newRevisionID()
-		rwt := &memdbReadWriteTx{memdbReader{&sync.Mutex{}, txSrc, nil, time.Now()}, newRevision}
+		rwt := &memdbReadWriteTx{memdbReader{&sync.Mutex{}, txSrc, nil, time.Now(), nil}, newRevision, mdb.requireIntegrity}
 		if err := f(ctx, rwt); err != nil {
 			mdb.Lock()
 			if tx != nil {
@@ -354,6 +377,9 @@ func (mdb *memdbDatastore) OfflineFeatures() (*datastore.Features, error) {
 		WatchEmitsImmediately: datastore.Feature{
 			Status: datastore.FeatureUnsupported,
 		},
+		StrictCursorResumption: datastore.Feature{
+			Status: datastore.FeatureSupported,
+		},
 	}, nil
 }
 
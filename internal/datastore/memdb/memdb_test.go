@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/authzed/spicedb/pkg/datastore"
@@ -30,6 +31,106 @@ func TestMemdbDatastore(t *testing.T) {
 	test.All(t, memDBTest{}, true)
 }
 
+func TestRelationshipCounterLifecycle(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	ds, err := NewMemdbDatastore(0, 1*time.Hour, 1*time.Hour)
+	require.NoError(err)
+
+	ctx := t.Context()
+
+	// Write a namespace and some relationships to count.
+	_, err = ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.WriteNamespaces(ctx,
+			ns.Namespace("document", ns.MustRelation("viewer", nil)),
+			ns.Namespace("user"),
+		)
+	})
+	require.NoError(err)
+
+	_, err = ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.WriteRelationships(ctx, []tuple.RelationshipUpdate{
+			tuple.Create(tuple.MustParse("document:first#viewer@user:alice")),
+			tuple.Create(tuple.MustParse("document:second#viewer@user:alice")),
+			tuple.Create(tuple.MustParse("document:third#viewer@user:bob")),
+		})
+	})
+	require.NoError(err)
+
+	filter := &corev1.RelationshipFilter{ResourceType: "document"}
+
+	// Register a counter and ensure duplicate registration returns the typed error.
+	_, err = ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.RegisterCounter(ctx, "documents", filter)
+	})
+	require.NoError(err)
+
+	_, err = ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.RegisterCounter(ctx, "documents", filter)
+	})
+	alreadyRegisteredErr := datastore.CounterAlreadyRegisteredError{}
+	require.ErrorAs(err, &alreadyRegisteredErr)
+
+	// Counts are computed by scanning at the requested revision.
+	countedRev, err := ds.HeadRevision(ctx)
+	require.NoError(err)
+
+	count, err := ds.SnapshotReader(countedRev).CountRelationships(ctx, "documents")
+	require.NoError(err)
+	require.Equal(3, count)
+
+	// Write another relationship; the count at the earlier revision is unchanged.
+	newRev, err := ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.WriteRelationships(ctx, []tuple.RelationshipUpdate{
+			tuple.Create(tuple.MustParse("document:fourth#viewer@user:bob")),
+		})
+	})
+	require.NoError(err)
+
+	count, err = ds.SnapshotReader(newRev).CountRelationships(ctx, "documents")
+	require.NoError(err)
+	require.Equal(4, count)
+
+	count, err = ds.SnapshotReader(countedRev).CountRelationships(ctx, "documents")
+	require.NoError(err)
+	require.Equal(3, count)
+
+	// Store a computed value and ensure the computed-at-revision bookkeeping is
+	// returned on lookup.
+	_, err = ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.StoreCounterValue(ctx, "documents", count, countedRev)
+	})
+	require.NoError(err)
+
+	headRev, err := ds.HeadRevision(ctx)
+	require.NoError(err)
+
+	counters, err := ds.SnapshotReader(headRev).LookupCounters(ctx)
+	require.NoError(err)
+	require.Len(counters, 1)
+	require.Equal("documents", counters[0].Name)
+	require.Equal(count, counters[0].Count)
+	require.True(countedRev.Equal(counters[0].ComputedAtRevision))
+
+	// Unregister the counter and ensure counting it is rejected thereafter.
+	_, err = ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.UnregisterCounter(ctx, "documents")
+	})
+	require.NoError(err)
+
+	headRev, err = ds.HeadRevision(ctx)
+	require.NoError(err)
+
+	counters, err = ds.SnapshotReader(headRev).LookupCounters(ctx)
+	require.NoError(err)
+	require.Empty(counters)
+
+	_, err = ds.SnapshotReader(headRev).CountRelationships(ctx, "documents")
+	notRegisteredErr := datastore.CounterNotRegisteredError{}
+	require.ErrorAs(err, &notRegisteredErr)
+}
+
 func TestConcurrentWritePanic(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -114,6 +215,58 @@ func TestConcurrentWriteRelsError(t *testing.T) {
 	require.ErrorContains(werr, "serialization max retries exceeded")
 }
 
+func TestWatchEmitsIntervalCheckpoints(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	require := require.New(t)
+
+	ds, err := NewMemdbDatastore(0, 1*time.Hour, 1*time.Hour)
+	require.NoError(err)
+	defer ds.Close()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	lowestRevision, err := ds.HeadRevision(ctx)
+	require.NoError(err)
+
+	changes, errchan := ds.Watch(ctx, lowestRevision, datastore.WatchOptions{
+		Content:            datastore.WatchCheckpoints,
+		CheckpointInterval: 10 * time.Millisecond,
+	})
+
+	// An empty transaction advances the head revision without writing a changelog entry,
+	// so only an interval-driven checkpoint can cover its revision.
+	emptyTxnRevision, err := ds.ReadWriteTx(ctx, func(_ context.Context, _ datastore.ReadWriteTransaction) error {
+		return nil
+	})
+	require.NoError(err)
+
+	var lastCheckpoint datastore.Revision
+	for lastCheckpoint == nil || lastCheckpoint.LessThan(emptyTxnRevision) {
+		select {
+		case change, ok := <-changes:
+			require.True(ok)
+			require.True(change.IsCheckpoint, "expected only checkpoints, found: %v", change)
+			if lastCheckpoint != nil {
+				require.True(change.Revision.GreaterThan(lastCheckpoint), "expected monotonic checkpoints; found %v after %v", change.Revision, lastCheckpoint)
+			}
+			lastCheckpoint = change.Revision
+		case err := <-errchan:
+			require.Fail("received unexpected error", "%v", err)
+		case <-time.After(5 * time.Second):
+			require.Fail("timed out waiting for an interval checkpoint")
+		}
+	}
+
+	// Canceling the watch must clean up the emitting goroutine; verified by goleak above.
+	cancel()
+	err = <-errchan
+	require.ErrorAs(err, &datastore.WatchCanceledError{})
+	for range changes {
+	}
+}
+
 func TestAnythingAfterCloseDoesNotPanic(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
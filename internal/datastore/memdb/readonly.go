@@ -25,6 +25,10 @@ type memdbReader struct {
 	txSource txFactory
 	initErr  error
 	now      time.Time
+
+	// atRevision is the revision at which this reader operates, or nil if unknown
+	// (such as within a read-write transaction).
+	atRevision datastore.Revision
 }
 
 func (r *memdbReader) CountRelationships(ctx context.Context, name string) (int, error) {
@@ -134,6 +138,10 @@ func (r *memdbReader) QueryRelationships(
 		return nil, datastore.ErrCursorsWithoutSorting
 	}
 
+	if err := common.ValidateCursorResumption(queryOpts, r.atRevision); err != nil {
+		return nil, err
+	}
+
 	matchingRelationshipsFilterFunc := filterFuncForFilters(
 		filter.OptionalResourceType,
 		filter.OptionalResourceIds,
@@ -151,11 +159,11 @@ func (r *memdbReader) QueryRelationships(
 		fallthrough
 
 	case options.ByResource:
-		iter := newMemdbTupleIterator(r.now, filteredIterator, queryOpts.Limit, queryOpts.SkipCaveats, queryOpts.SkipExpiration)
+		iter := newMemdbTupleIterator(r.now, filteredIterator, queryOpts.Limit, queryOpts.SkipCaveats, queryOpts.SkipExpiration, queryOpts.ExpirationBehavior)
 		return iter, nil
 
 	case options.BySubject:
-		return newSubjectSortedIterator(r.now, filteredIterator, queryOpts.Limit, queryOpts.SkipCaveats, queryOpts.SkipExpiration)
+		return newSubjectSortedIterator(r.now, filteredIterator, queryOpts.Limit, queryOpts.SkipCaveats, queryOpts.SkipExpiration, queryOpts.ExpirationBehavior)
 
 	default:
 		return nil, spiceerrors.MustBugf("unsupported sort order: %v", queryOpts.Sort)
@@ -214,11 +222,11 @@ func (r *memdbReader) ReverseQueryRelationships(
 		fallthrough
 
 	case options.ByResource:
-		iter := newMemdbTupleIterator(r.now, filteredIterator, queryOpts.LimitForReverse, queryOpts.SkipCaveatsForReverse, queryOpts.SkipExpirationForReverse)
+		iter := newMemdbTupleIterator(r.now, filteredIterator, queryOpts.LimitForReverse, queryOpts.SkipCaveatsForReverse, queryOpts.SkipExpirationForReverse, queryOpts.ExpirationBehaviorForReverse)
 		return iter, nil
 
 	case options.BySubject:
-		return newSubjectSortedIterator(r.now, filteredIterator, queryOpts.LimitForReverse, queryOpts.SkipCaveatsForReverse, queryOpts.SkipExpirationForReverse)
+		return newSubjectSortedIterator(r.now, filteredIterator, queryOpts.LimitForReverse, queryOpts.SkipCaveatsForReverse, queryOpts.SkipExpirationForReverse, queryOpts.ExpirationBehaviorForReverse)
 
 	default:
 		return nil, spiceerrors.MustBugf("unsupported sort order: %v", queryOpts.SortForReverse)
@@ -478,7 +486,7 @@ func makeCursorFilterFn(after options.Cursor, order options.SortOrder) func(tpl
 	return noopCursorFilter
 }
 
-func newSubjectSortedIterator(now time.Time, it memdb.ResultIterator, limit *uint64, skipCaveats bool, skipExpiration bool) (datastore.RelationshipIterator, error) {
+func newSubjectSortedIterator(now time.Time, it memdb.ResultIterator, limit *uint64, skipCaveats bool, skipExpiration bool, expirationBehavior options.ExpirationBehavior) (datastore.RelationshipIterator, error) {
 	results := make([]tuple.Relationship, 0)
 
 	// Coalesce all of the results into memory
@@ -488,7 +496,7 @@ func newSubjectSortedIterator(now time.Time, it memdb.ResultIterator, limit *uin
 			return nil, err
 		}
 
-		if rt.OptionalExpiration != nil && rt.OptionalExpiration.Before(now) {
+		if !matchesExpirationBehavior(rt, now, expirationBehavior) {
 			continue
 		}
 
@@ -536,7 +544,23 @@ func eq(lhsNamespace, lhsObjectID, lhsRelation string, rhs tuple.ObjectAndRelati
 	return lhsNamespace == rhs.ObjectType && lhsObjectID == rhs.ObjectID && lhsRelation == rhs.Relation
 }
 
-func newMemdbTupleIterator(now time.Time, it memdb.ResultIterator, limit *uint64, skipCaveats bool, skipExpiration bool) datastore.RelationshipIterator {
+// matchesExpirationBehavior returns whether the relationship should be returned under
+// the given expiration behavior, based on whether its expiration has passed.
+func matchesExpirationBehavior(rel tuple.Relationship, now time.Time, behavior options.ExpirationBehavior) bool {
+	expired := rel.OptionalExpiration != nil && rel.OptionalExpiration.Before(now)
+	switch behavior {
+	case options.IncludeExpiredRelationships:
+		return true
+
+	case options.OnlyExpiredRelationships:
+		return expired
+
+	default:
+		return !expired
+	}
+}
+
+func newMemdbTupleIterator(now time.Time, it memdb.ResultIterator, limit *uint64, skipCaveats bool, skipExpiration bool, expirationBehavior options.ExpirationBehavior) datastore.RelationshipIterator {
 	var count uint64
 	return func(yield func(tuple.Relationship, error) bool) {
 		for {
@@ -565,7 +589,7 @@ func newMemdbTupleIterator(now time.Time, it memdb.ResultIterator, limit *uint64
 				continue
 			}
 
-			if rt.OptionalExpiration != nil && rt.OptionalExpiration.Before(now) {
+			if !matchesExpirationBehavior(rt, now, expirationBehavior) {
 				continue
 			}
 
@@ -22,6 +22,10 @@ import (
 type memdbReadWriteTx struct {
 	memdbReader
 	newRevision datastore.Revision
+
+	// requireIntegrity, if set, causes any written relationship lacking integrity
+	// information to be rejected.
+	requireIntegrity bool
 }
 
 func (rwt *memdbReadWriteTx) WriteRelationships(_ context.Context, mutations []tuple.RelationshipUpdate) error {
@@ -52,6 +56,11 @@ func (rwt *memdbReadWriteTx) toIntegrity(mutation tuple.RelationshipUpdate) *rel
 func (rwt *memdbReadWriteTx) write(tx *memdb.Txn, mutations ...tuple.RelationshipUpdate) error {
 	// Apply the mutations
 	for _, mutation := range mutations {
+		if rwt.requireIntegrity && mutation.Operation != tuple.UpdateOperationDelete &&
+			mutation.Relationship.OptionalIntegrity == nil {
+			return NewRelationshipIntegrityRequiredErr(mutation.Relationship)
+		}
+
 		rel := &relationship{
 			mutation.Relationship.Resource.ObjectType,
 			mutation.Relationship.Resource.ObjectID,
@@ -102,7 +111,7 @@ func (rwt *memdbReadWriteTx) write(tx *memdb.Txn, mutations ...tuple.Relationshi
 				if err != nil {
 					return err
 				}
-				if tuple.MustString(rt) == tuple.MustString(mutation.Relationship) {
+				if tuple.CanonicalString(rt) == tuple.CanonicalString(mutation.Relationship) {
 					continue
 				}
 			}
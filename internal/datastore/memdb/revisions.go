@@ -72,6 +72,22 @@ func (mdb *memdbDatastore) OptimizedRevision(_ context.Context) (datastore.Revis
 	return revisions.NewForTimestamp(now.TimestampNanoSec() - now.TimestampNanoSec()%mdb.quantizationPeriod), nil
 }
 
+func (mdb *memdbDatastore) OptimizedRevisionWithStaleness(ctx context.Context, maxStaleness time.Duration) (datastore.Revision, error) {
+	stalenessPeriod := maxStaleness.Nanoseconds()
+	if stalenessPeriod <= mdb.quantizationPeriod {
+		return mdb.OptimizedRevision(ctx)
+	}
+
+	mdb.RLock()
+	defer mdb.RUnlock()
+	if err := mdb.checkNotClosed(); err != nil {
+		return nil, err
+	}
+
+	now := nowRevision()
+	return revisions.NewForTimestamp(now.TimestampNanoSec() - now.TimestampNanoSec()%stalenessPeriod), nil
+}
+
 func (mdb *memdbDatastore) CheckRevision(_ context.Context, dr datastore.Revision) error {
 	mdb.RLock()
 	defer mdb.RUnlock()
@@ -88,7 +104,8 @@ func (mdb *memdbDatastore) checkRevisionLocalCallerMustLock(dr datastore.Revisio
 	// Ensure the revision has not fallen outside of the GC window. If it has, it is considered
 	// invalid.
 	if mdb.revisionOutsideGCWindow(now, dr) {
-		return datastore.NewInvalidRevisionErr(dr, datastore.RevisionStale)
+		oldestValid := revisions.NewForTimestamp(now.TimestampNanoSec() + mdb.negativeGCWindow)
+		return datastore.NewStaleRevisionErr(dr, oldestValid)
 	}
 
 	// If the revision <= now and later than the GC window, it is assumed to be valid, even if
@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/internal/datastore/revisions"
+	"github.com/authzed/spicedb/pkg/datastore"
 )
 
 func TestHeadRevision(t *testing.T) {
@@ -25,6 +28,32 @@ func TestHeadRevision(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestOptimizedRevisionWithStaleness(t *testing.T) {
+	ds, err := NewMemdbDatastore(0, 10*time.Millisecond, 1*time.Hour)
+	require.NoError(t, err)
+
+	bounded, ok := ds.(datastore.BoundedStalenessDatastore)
+	require.True(t, ok, "memdb datastore should support bounded staleness revisions")
+
+	staleness := 10 * time.Minute
+	rev, err := bounded.OptimizedRevisionWithStaleness(t.Context(), staleness)
+	require.NoError(t, err)
+
+	// The revision must be quantized to the requested staleness window, and therefore
+	// fall inside it.
+	revTime := time.Unix(0, rev.(revisions.TimestampRevision).TimestampNanoSec())
+	require.Zero(t, revTime.UnixNano()%staleness.Nanoseconds())
+	require.LessOrEqual(t, time.Since(revTime), staleness)
+
+	// A staleness below the configured quantization window falls back to the standard
+	// optimized revision.
+	rev, err = bounded.OptimizedRevisionWithStaleness(t.Context(), 1*time.Millisecond)
+	require.NoError(t, err)
+
+	revTime = time.Unix(0, rev.(revisions.TimestampRevision).TimestampNanoSec())
+	require.Zero(t, revTime.UnixNano()%(10*time.Millisecond).Nanoseconds())
+}
+
 func (mdb *memdbDatastore) ExampleRetryableError() error {
 	return ErrSerialization
 }
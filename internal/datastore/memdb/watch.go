@@ -14,6 +14,10 @@ import (
 
 const errWatchError = "watch error: %w"
 
+// defaultCheckpointInterval is the interval at which checkpoints are emitted when
+// requested and no interval was specified in the watch options.
+const defaultCheckpointInterval = 100 * time.Millisecond
+
 func (mdb *memdbDatastore) Watch(ctx context.Context, ar datastore.Revision, options datastore.WatchOptions) (<-chan datastore.RevisionChanges, <-chan error) {
 	watchBufferLength := options.WatchBufferLength
 	if watchBufferLength == 0 {
@@ -56,11 +60,18 @@ func (mdb *memdbDatastore) Watch(ctx context.Context, ar datastore.Revision, opt
 		}
 	}
 
+	emitCheckpoints := options.Content&datastore.WatchCheckpoints == datastore.WatchCheckpoints
+	checkpointInterval := options.CheckpointInterval
+	if checkpointInterval <= 0 {
+		checkpointInterval = defaultCheckpointInterval
+	}
+
 	go func() {
 		defer close(updates)
 		defer close(errs)
 
 		currentTxn := ar.(revisions.TimestampRevision).TimestampNanoSec()
+		lastCheckpointTxn := currentTxn
 
 		for {
 			var stagedUpdates []datastore.RevisionChanges
@@ -79,11 +90,46 @@ func (mdb *memdbDatastore) Watch(ctx context.Context, ar datastore.Revision, opt
 				}
 			}
 
+			// loadChanges emits a checkpoint after each change batch, so any change batch
+			// advances the last checkpointed revision.
+			if currentTxn > lastCheckpointTxn {
+				lastCheckpointTxn = currentTxn
+			}
+
 			// Wait for new changes
 			ws := memdb.NewWatchSet()
 			ws.Add(watchChan)
 
-			err = ws.WatchCtx(ctx)
+			if emitCheckpoints {
+				// Bound the wait by the checkpoint interval, so that a checkpoint is
+				// emitted whenever the head revision has advanced past the last
+				// checkpoint without producing watched changes.
+				waitCtx, cancelWait := context.WithTimeout(ctx, checkpointInterval)
+				err = ws.WatchCtx(waitCtx)
+				cancelWait()
+
+				if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+					checkpointTxn, ok, err := mdb.checkpointRevision(watchChan, lastCheckpointTxn)
+					if err != nil {
+						errs <- err
+						return
+					}
+
+					if ok {
+						if !sendChange(datastore.RevisionChanges{
+							Revision:     revisions.NewForTimestamp(checkpointTxn),
+							IsCheckpoint: true,
+						}) {
+							return
+						}
+						lastCheckpointTxn = checkpointTxn
+					}
+					continue
+				}
+			} else {
+				err = ws.WatchCtx(ctx)
+			}
+
 			if err != nil {
 				switch {
 				case errors.Is(err, context.Canceled):
@@ -99,6 +145,32 @@ func (mdb *memdbDatastore) Watch(ctx context.Context, ar datastore.Revision, opt
 	return updates, errs
 }
 
+// checkpointRevision returns the head revision to be checkpointed, if any. It returns
+// false if new changes are pending on the given watch channel (those must be reported
+// before a checkpoint may cover them) or if the head revision has not advanced past the
+// last emitted checkpoint, keeping checkpoints monotonic.
+func (mdb *memdbDatastore) checkpointRevision(watchChan <-chan struct{}, lastCheckpointTxn int64) (int64, bool, error) {
+	mdb.RLock()
+	defer mdb.RUnlock()
+
+	if err := mdb.checkNotClosed(); err != nil {
+		return 0, false, err
+	}
+
+	select {
+	case <-watchChan:
+		return 0, false, nil
+	default:
+	}
+
+	headTxn := mdb.headRevisionNoLock().TimestampNanoSec()
+	if headTxn <= lastCheckpointTxn {
+		return 0, false, nil
+	}
+
+	return headTxn, true, nil
+}
+
 func (mdb *memdbDatastore) loadChanges(_ context.Context, currentTxn int64, options datastore.WatchOptions) ([]datastore.RevisionChanges, int64, <-chan struct{}, error) {
 	mdb.RLock()
 	defer mdb.RUnlock()
@@ -291,9 +291,15 @@ func newMySQLDatastore(ctx context.Context, uri string, replicaIndex int, option
 		createBaseTxn:           createBaseTxn,
 		QueryBuilder:            queryBuilder,
 		readTxOptions:           &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true},
-		maxRetries:              config.maxRetries,
-		analyzeBeforeStats:      config.analyzeBeforeStats,
-		schema:                  *schema,
+		retryPolicy: common.RetryPolicy{
+			Backend:        Engine,
+			MaxRetries:     config.maxRetries,
+			BaseDelay:      25 * time.Millisecond,
+			JitterFraction: 0.5,
+			Classifier:     classifyRetryableError,
+		},
+		analyzeBeforeStats: config.analyzeBeforeStats,
+		schema:             *schema,
 		CachedOptimizedRevisions: revisions.NewCachedOptimizedRevisions(
 			maxRevisionStaleness,
 		),
@@ -372,15 +378,15 @@ func (mds *Datastore) ReadWriteTx(
 ) (datastore.Revision, error) {
 	config := options.NewRWTOptionsWithOptions(opts...)
 
-	var err error
-	for i := uint8(0); i <= mds.maxRetries; i++ {
-		var newTxnID uint64
-		if err = migrations.BeginTxFunc(ctx, mds.db, &sql.TxOptions{Isolation: sql.LevelSerializable}, func(tx *sql.Tx) error {
+	var newTxnID uint64
+	err := mds.retryPolicy.ExecuteWithRetries(ctx, config.DisableRetries, func(ctx context.Context) error {
+		return migrations.BeginTxFunc(ctx, mds.db, &sql.TxOptions{Isolation: sql.LevelSerializable}, func(tx *sql.Tx) error {
 			var metadata map[string]any
 			if config.Metadata != nil {
 				metadata = config.Metadata.AsMap()
 			}
 
+			var err error
 			newTxnID, err = mds.createNewTransaction(ctx, tx, metadata)
 			if err != nil {
 				return fmt.Errorf("unable to create new txn ID: %w", err)
@@ -409,21 +415,13 @@ func (mds *Datastore) ReadWriteTx(
 			}
 
 			return fn(ctx, rwt)
-		}); err != nil {
-			if !config.DisableRetries && isErrorRetryable(err) {
-				continue
-			}
-
-			return datastore.NoRevision, wrapError(err)
-		}
-
-		return revisions.NewForTransactionID(newTxnID), nil
-	}
-	if !config.DisableRetries {
-		err = fmt.Errorf("max retries exceeded: %w", err)
+		})
+	})
+	if err != nil {
+		return datastore.NoRevision, wrapError(err)
 	}
 
-	return datastore.NoRevision, wrapError(err)
+	return revisions.NewForTransactionID(newTxnID), nil
 }
 
 // wrapError maps any mysql internal error into a SpiceDB typed error or an error
@@ -433,17 +431,38 @@ func wrapError(err error) error {
 		return cerr
 	}
 
+	// Deadlocks and lock wait timeouts are retried above; if one escapes here,
+	// retries were disabled or exhausted, so surface it as a serialization
+	// failure for the services layer to map.
+	if isErrorRetryable(err) {
+		return common.NewSerializationError(err)
+	}
+
 	return err
 }
 
 func isErrorRetryable(err error) bool {
+	_, retryable := classifyRetryableError(err)
+	return retryable
+}
+
+// classifyRetryableError determines whether the given error is retryable and, if so,
+// the error class label to record for the retry.
+func classifyRetryableError(err error) (string, bool) {
 	var mysqlerr *mysql.MySQLError
 	if !errors.As(err, &mysqlerr) {
 		log.Debug().Err(err).Msg("couldn't determine a sqlstate error code")
-		return false
+		return "", false
 	}
 
-	return mysqlerr.Number == errMysqlDeadlock || mysqlerr.Number == errMysqlLockWaitTimeout
+	switch mysqlerr.Number {
+	case errMysqlDeadlock:
+		return "deadlock", true
+	case errMysqlLockWaitTimeout:
+		return "lock_wait_timeout", true
+	default:
+		return "", false
+	}
 }
 
 type querier interface {
@@ -504,7 +523,7 @@ type Datastore struct {
 	watchBufferLength       uint16
 	watchBufferWriteTimeout time.Duration
 	watchEnabled            bool
-	maxRetries              uint8
+	retryPolicy             common.RetryPolicy
 	filterMaximumIDCount    uint16
 	schema                  common.SchemaInformation
 
@@ -17,6 +17,7 @@ import (
 
 var (
 	_ common.GarbageCollectableDatastore = (*Datastore)(nil)
+	_ common.ManualGCDatastore           = (*Datastore)(nil)
 	_ common.GarbageCollector            = (*mysqlGarbageCollector)(nil)
 )
 
@@ -41,6 +42,12 @@ func (mds *Datastore) ResetGCCompleted() {
 	mds.gcHasRun.Store(false)
 }
 
+// RunGC runs a single garbage collection pass using the datastore's configured
+// window and timeout, returning the statistics of the pass.
+func (mds *Datastore) RunGC(_ context.Context) (common.GCStats, error) {
+	return common.RunGarbageCollectionWithStats(mds, mds.gcWindow, mds.gcTimeout)
+}
+
 func (mcc *mysqlGarbageCollector) Close() {
 	mcc.isClosed = true
 }
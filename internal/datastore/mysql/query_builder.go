@@ -30,6 +30,9 @@ type QueryBuilder struct {
 	QueryChangedQuery            sq.SelectBuilder
 	CountRelsQuery               sq.SelectBuilder
 
+	QueryChangedNamespacesQuery sq.SelectBuilder
+	QueryChangedCaveatsQuery    sq.SelectBuilder
+
 	WriteCaveatQuery  sq.InsertBuilder
 	ReadCaveatQuery   sq.SelectBuilder
 	ListCaveatsQuery  sq.SelectBuilder
@@ -66,6 +69,10 @@ func NewQueryBuilder(driver *migrations.MySQLDriver) *QueryBuilder {
 	builder.QueryChangedQuery = queryChanged(driver.RelationTuple())
 	builder.CountRelsQuery = countRels(driver.RelationTuple())
 
+	// watch builders
+	builder.QueryChangedNamespacesQuery = queryChangedNamespaces(driver.Namespace())
+	builder.QueryChangedCaveatsQuery = queryChangedCaveats(driver.Caveat())
+
 	// caveat builders
 	builder.ReadCaveatQuery = readCaveat(driver.Caveat())
 	builder.ListCaveatsQuery = listCaveats(driver.Caveat())
@@ -223,3 +230,19 @@ func queryChanged(tableTuple string) sq.SelectBuilder {
 		colDeletedTxn,
 	).From(tableTuple)
 }
+
+func queryChangedNamespaces(tableNamespace string) sq.SelectBuilder {
+	return sb.Select(
+		colConfig,
+		colCreatedTxn,
+		colDeletedTxn,
+	).From(tableNamespace)
+}
+
+func queryChangedCaveats(tableCaveat string) sq.SelectBuilder {
+	return sb.Select(
+		colCaveatDefinition,
+		colCreatedTxn,
+		colDeletedTxn,
+	).From(tableCaveat)
+}
@@ -203,6 +203,7 @@ func (mr *mysqlReader) ReverseQueryRelationships(
 		options.WithSort(queryOpts.SortForReverse),
 		options.WithSkipCaveats(queryOpts.SkipCaveatsForReverse),
 		options.WithSkipExpiration(queryOpts.SkipExpirationForReverse),
+		options.WithExpirationBehavior(queryOpts.ExpirationBehaviorForReverse),
 		options.WithQueryShape(queryOpts.QueryShapeForReverse),
 		options.WithSQLExplainCallbackForTest(queryOpts.SQLExplainCallbackForTestForReverse),
 	)
@@ -527,6 +527,10 @@ func (rwt *mysqlReadWriteTXN) BulkLoad(ctx context.Context, iter datastore.BulkW
 		var batchLen uint64
 
 		for ; rel != nil && err == nil && batchLen < bulkInsertRowsLimit; rel, err = iter.Next(ctx) {
+			if rel.OptionalIntegrity != nil {
+				return 0, datastore.NewRelationshipIntegrityNotSupportedErr(tuple.StringWithoutCaveatOrExpiration(*rel))
+			}
+
 			if batchLen != 0 {
 				sqlStmt.WriteString(",(?,?,?,?,?,?,?,?,?,?)")
 			}
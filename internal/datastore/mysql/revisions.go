@@ -44,10 +44,11 @@ const (
 		)) as revision,
 		%[4]d - CAST(UNIX_TIMESTAMP(UTC_TIMESTAMP(6)) * 1000000000 AS UNSIGNED INTEGER) %% %[4]d as validForNanos;`
 
-	// queryValidTransaction will return a single row with two values, one boolean
+	// queryValidTransaction will return a single row with three values, one boolean
 	// for whether the specified transaction ID is newer than the garbage collection
-	// window, and one boolean for whether the transaction ID represents a transaction
-	// that will occur in the future.
+	// window, one boolean for whether the transaction ID represents a transaction
+	// that will occur in the future, and the ID of the minimum valid (i.e. within the
+	// GC window) transaction.
 	// It treats the current head transaction as always valid even if it falls
 	// outside the GC window.
 	//
@@ -56,18 +57,20 @@ const (
 	//   %[3] Name of timestamp column
 	//   %[4] Inverse of GC window (in seconds)
 	queryValidTransaction = `
-		SELECT ? >= COALESCE((
-			SELECT MIN(%[1]s)
-			FROM   %[2]s
-			WHERE  %[3]s >= TIMESTAMPADD(SECOND, %.6[4]f, UTC_TIMESTAMP(6))
-		),( 
-		    SELECT MAX(%[1]s)
-		    FROM %[2]s
-		    LIMIT 1
-		)) as fresh, ? > (
+		SELECT ? >= minvalid.id as fresh, ? > (
 			SELECT MAX(%[1]s)
 			FROM   %[2]s
-		) as unknown;`
+		) as unknown, minvalid.id as min_valid FROM (
+			SELECT COALESCE((
+				SELECT MIN(%[1]s)
+				FROM   %[2]s
+				WHERE  %[3]s >= TIMESTAMPADD(SECOND, %.6[4]f, UTC_TIMESTAMP(6))
+			),(
+				SELECT MAX(%[1]s)
+				FROM %[2]s
+				LIMIT 1
+			)) as id
+		) minvalid;`
 )
 
 func (mds *Datastore) optimizedRevisionFunc(ctx context.Context) (datastore.Revision, time.Duration, error) {
@@ -103,13 +106,13 @@ func (mds *Datastore) CheckRevision(ctx context.Context, revision datastore.Revi
 	}
 
 	revisionTx := rev.TransactionID()
-	freshEnough, unknown, err := mds.checkValidTransaction(ctx, revisionTx)
+	freshEnough, unknown, minValidTx, err := mds.checkValidTransaction(ctx, revisionTx)
 	if err != nil {
 		return fmt.Errorf(errCheckRevision, err)
 	}
 
 	if !freshEnough {
-		return datastore.NewInvalidRevisionErr(revision, datastore.RevisionStale)
+		return datastore.NewStaleRevisionErr(revision, revisions.NewForTransactionID(minValidTx))
 	}
 	if unknown {
 		return datastore.NewInvalidRevisionErr(revision, datastore.CouldNotDetermineRevision)
@@ -144,16 +147,17 @@ func (mds *Datastore) loadRevision(ctx context.Context) (uint64, error) {
 	return *revision, nil
 }
 
-func (mds *Datastore) checkValidTransaction(ctx context.Context, revisionTx uint64) (bool, bool, error) {
+func (mds *Datastore) checkValidTransaction(ctx context.Context, revisionTx uint64) (bool, bool, uint64, error) {
 	ctx, span := tracer.Start(ctx, "checkValidTransaction")
 	defer span.End()
 
 	var freshEnough, unknown sql.NullBool
+	var minValid sql.NullInt64
 
 	err := mds.db.QueryRowContext(ctx, mds.validTransactionQuery, revisionTx, revisionTx).
-		Scan(&freshEnough, &unknown)
+		Scan(&freshEnough, &unknown, &minValid)
 	if err != nil {
-		return false, false, fmt.Errorf(errCheckRevision, err)
+		return false, false, 0, fmt.Errorf(errCheckRevision, err)
 	}
 
 	span.AddEvent(otelconv.EventDatastoreMySQLTransactionValidated, trace.WithAttributes(
@@ -161,7 +165,12 @@ func (mds *Datastore) checkValidTransaction(ctx context.Context, revisionTx uint
 		attribute.Bool(otelconv.AttrDatastoreMySQLTransactionUnknown, unknown.Bool),
 	))
 
-	return freshEnough.Bool, unknown.Bool, nil
+	minValidTx, err := safecast.ToUint64(minValid.Int64)
+	if err != nil {
+		return false, false, 0, spiceerrors.MustBugf("minimum valid transaction ID was negative: %v", err)
+	}
+
+	return freshEnough.Bool, unknown.Bool, minValidTx, nil
 }
 
 func (mds *Datastore) createNewTransaction(ctx context.Context, tx *sql.Tx, metadata map[string]any) (newTxnID uint64, err error) {
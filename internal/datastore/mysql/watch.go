@@ -3,6 +3,7 @@ package mysql
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
@@ -10,11 +11,12 @@ import (
 	"github.com/authzed/spicedb/internal/datastore/common"
 	"github.com/authzed/spicedb/internal/datastore/revisions"
 	"github.com/authzed/spicedb/pkg/datastore"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 	"github.com/authzed/spicedb/pkg/tuple"
 )
 
 const (
-	watchSleep = 100 * time.Millisecond
+	minimumWatchSleep = 100 * time.Millisecond
 )
 
 // Watch notifies the caller about all changes to tuples.
@@ -35,12 +37,6 @@ func (mds *Datastore) Watch(ctx context.Context, afterRevisionRaw datastore.Revi
 		return updates, errs
 	}
 
-	if options.Content&datastore.WatchSchema == datastore.WatchSchema {
-		close(updates)
-		errs <- errors.New("schema watch unsupported in MySQL")
-		return updates, errs
-	}
-
 	if options.EmissionStrategy == datastore.EmitImmediatelyStrategy {
 		close(updates)
 		errs <- errors.New("emit immediately strategy is unsupported in MySQL")
@@ -53,6 +49,19 @@ func (mds *Datastore) Watch(ctx context.Context, afterRevisionRaw datastore.Revi
 		return updates, errs
 	}
 
+	// Ensure the starting revision has not already been garbage collected; watching
+	// from a collected revision cannot produce a complete stream of changes.
+	if err := mds.CheckRevision(ctx, afterRevision); err != nil {
+		close(updates)
+		errs <- err
+		return updates, errs
+	}
+
+	watchSleep := options.CheckpointInterval
+	if watchSleep < minimumWatchSleep {
+		watchSleep = minimumWatchSleep
+	}
+
 	watchBufferWriteTimeout := options.WatchBufferWriteTimeout
 	if watchBufferWriteTimeout <= 0 {
 		watchBufferWriteTimeout = mds.watchBufferWriteTimeout
@@ -84,8 +93,11 @@ func (mds *Datastore) Watch(ctx context.Context, afterRevisionRaw datastore.Revi
 		defer close(updates)
 		defer close(errs)
 
+		requestedCheckpoints := options.Content&datastore.WatchCheckpoints == datastore.WatchCheckpoints
 		currentTxn := afterRevision.TransactionID()
 		for {
+			previousTxn := currentTxn
+
 			var stagedUpdates []datastore.RevisionChanges
 			var err error
 			stagedUpdates, currentTxn, err = mds.loadChanges(ctx, currentTxn, options)
@@ -106,8 +118,20 @@ func (mds *Datastore) Watch(ctx context.Context, afterRevisionRaw datastore.Revi
 				}
 			}
 
-			// If there were no changes, sleep a bit
-			if len(stagedUpdates) == 0 {
+			if currentTxn > previousTxn {
+				// If checkpoints were requested, output a checkpoint. Even if none of the
+				// changes in the range matched the requested content, the checkpoint
+				// indicates that the stream has caught up to the new revision.
+				if requestedCheckpoints {
+					if !sendChange(datastore.RevisionChanges{
+						Revision:     revisions.NewForTransactionID(currentTxn),
+						IsCheckpoint: true,
+					}) {
+						return
+					}
+				}
+			} else {
+				// If there were no new transactions, sleep a bit before polling again.
 				sleep := time.NewTimer(watchSleep)
 
 				select {
@@ -187,8 +211,30 @@ func (mds *Datastore) loadChanges(
 		return changes, newRevision, err
 	}
 
-	// Load the changes relationships for the revision range.
-	sql, args, err = mds.QueryChangedQuery.Where(sq.Or{
+	// Load the changed relationships for the revision range.
+	if options.Content&datastore.WatchRelationships == datastore.WatchRelationships {
+		if err := mds.loadRelationshipChanges(ctx, afterRevision, newRevision, stagedChanges); err != nil {
+			return changes, newRevision, err
+		}
+	}
+
+	// Load the changed namespaces and caveats for the revision range.
+	if options.Content&datastore.WatchSchema == datastore.WatchSchema {
+		if err := mds.loadNamespaceChanges(ctx, afterRevision, newRevision, stagedChanges); err != nil {
+			return changes, newRevision, err
+		}
+
+		if err := mds.loadCaveatChanges(ctx, afterRevision, newRevision, stagedChanges); err != nil {
+			return changes, newRevision, err
+		}
+	}
+
+	changes, err = stagedChanges.AsRevisionChanges(revisions.TransactionIDKeyLessThanFunc)
+	return changes, newRevision, err
+}
+
+func (mds *Datastore) loadRelationshipChanges(ctx context.Context, afterRevision uint64, newRevision uint64, stagedChanges *common.Changes[revisions.TransactionIDRevision, uint64]) error {
+	sql, args, err := mds.QueryChangedQuery.Where(sq.Or{
 		sq.And{
 			sq.Gt{colCreatedTxn: afterRevision},
 			sq.LtOrEq{colCreatedTxn: newRevision},
@@ -199,15 +245,15 @@ func (mds *Datastore) loadChanges(
 		},
 	}).ToSql()
 	if err != nil {
-		return changes, newRevision, err
+		return err
 	}
 
-	rows, err = mds.db.QueryContext(ctx, sql, args...)
+	rows, err := mds.db.QueryContext(ctx, sql, args...)
 	if err != nil {
 		if errors.Is(err, context.Canceled) {
 			err = datastore.NewWatchCanceledErr()
 		}
-		return changes, newRevision, err
+		return err
 	}
 	defer common.LogOnError(ctx, rows.Close)
 
@@ -237,7 +283,7 @@ func (mds *Datastore) loadChanges(
 			&deletedTxn,
 		)
 		if err != nil {
-			return changes, newRevision, err
+			return err
 		}
 
 		relationship := tuple.Relationship{
@@ -258,25 +304,124 @@ func (mds *Datastore) loadChanges(
 
 		relationship.OptionalCaveat, err = common.ContextualizedCaveatFrom(caveatName, caveatContext)
 		if err != nil {
-			return changes, newRevision, err
+			return err
 		}
 
 		if createdTxn > afterRevision && createdTxn <= newRevision {
 			if err = stagedChanges.AddRelationshipChange(ctx, revisions.NewForTransactionID(createdTxn), relationship, tuple.UpdateOperationTouch); err != nil {
-				return changes, newRevision, err
+				return err
 			}
 		}
 
 		if deletedTxn > afterRevision && deletedTxn <= newRevision {
 			if err = stagedChanges.AddRelationshipChange(ctx, revisions.NewForTransactionID(deletedTxn), relationship, tuple.UpdateOperationDelete); err != nil {
-				return changes, newRevision, err
+				return err
 			}
 		}
 	}
-	if err = rows.Err(); err != nil {
-		return changes, newRevision, err
+	return rows.Err()
+}
+
+func (mds *Datastore) loadNamespaceChanges(ctx context.Context, afterRevision uint64, newRevision uint64, stagedChanges *common.Changes[revisions.TransactionIDRevision, uint64]) error {
+	sql, args, err := mds.QueryChangedNamespacesQuery.Where(sq.Or{
+		sq.And{
+			sq.Gt{colCreatedTxn: afterRevision},
+			sq.LtOrEq{colCreatedTxn: newRevision},
+		},
+		sq.And{
+			sq.Gt{colDeletedTxn: afterRevision},
+			sq.LtOrEq{colDeletedTxn: newRevision},
+		},
+	}).ToSql()
+	if err != nil {
+		return err
 	}
 
-	changes, err = stagedChanges.AsRevisionChanges(revisions.TransactionIDKeyLessThanFunc)
-	return changes, newRevision, err
+	rows, err := mds.db.QueryContext(ctx, sql, args...)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			err = datastore.NewWatchCanceledErr()
+		}
+		return err
+	}
+	defer common.LogOnError(ctx, rows.Close)
+
+	for rows.Next() {
+		var config []byte
+		var createdTxn uint64
+		var deletedTxn uint64
+		if err := rows.Scan(&config, &createdTxn, &deletedTxn); err != nil {
+			return err
+		}
+
+		loaded := &core.NamespaceDefinition{}
+		if err := loaded.UnmarshalVT(config); err != nil {
+			return fmt.Errorf(errUnableToReadConfig, err)
+		}
+
+		if createdTxn > afterRevision && createdTxn <= newRevision {
+			if err := stagedChanges.AddChangedDefinition(ctx, revisions.NewForTransactionID(createdTxn), loaded); err != nil {
+				return err
+			}
+		}
+
+		if deletedTxn > afterRevision && deletedTxn <= newRevision {
+			if err := stagedChanges.AddDeletedNamespace(ctx, revisions.NewForTransactionID(deletedTxn), loaded.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return rows.Err()
+}
+
+func (mds *Datastore) loadCaveatChanges(ctx context.Context, afterRevision uint64, newRevision uint64, stagedChanges *common.Changes[revisions.TransactionIDRevision, uint64]) error {
+	sql, args, err := mds.QueryChangedCaveatsQuery.Where(sq.Or{
+		sq.And{
+			sq.Gt{colCreatedTxn: afterRevision},
+			sq.LtOrEq{colCreatedTxn: newRevision},
+		},
+		sq.And{
+			sq.Gt{colDeletedTxn: afterRevision},
+			sq.LtOrEq{colDeletedTxn: newRevision},
+		},
+	}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := mds.db.QueryContext(ctx, sql, args...)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			err = datastore.NewWatchCanceledErr()
+		}
+		return err
+	}
+	defer common.LogOnError(ctx, rows.Close)
+
+	for rows.Next() {
+		var definition []byte
+		var createdTxn uint64
+		var deletedTxn uint64
+		if err := rows.Scan(&definition, &createdTxn, &deletedTxn); err != nil {
+			return err
+		}
+
+		loaded := &core.CaveatDefinition{}
+		if err := loaded.UnmarshalVT(definition); err != nil {
+			return fmt.Errorf(errUnableToReadConfig, err)
+		}
+
+		if createdTxn > afterRevision && createdTxn <= newRevision {
+			if err := stagedChanges.AddChangedDefinition(ctx, revisions.NewForTransactionID(createdTxn), loaded); err != nil {
+				return err
+			}
+		}
+
+		if deletedTxn > afterRevision && deletedTxn <= newRevision {
+			if err := stagedChanges.AddDeletedCaveat(ctx, revisions.NewForTransactionID(deletedTxn), loaded.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return rows.Err()
 }
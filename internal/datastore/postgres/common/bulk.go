@@ -48,7 +48,11 @@ func (tg *tupleSourceAdapter) Values() ([]any, error) {
 	tg.valuesBuffer[7] = caveatContext
 	tg.valuesBuffer[8] = tg.current.OptionalExpiration
 
-	if len(tg.colNames) > 9 && tg.current.OptionalIntegrity != nil {
+	if tg.current.OptionalIntegrity != nil {
+		if len(tg.colNames) <= 9 {
+			return nil, datastore.NewRelationshipIntegrityNotSupportedErr(tuple.StringWithoutCaveatOrExpiration(*tg.current))
+		}
+
 		tg.valuesBuffer[9] = tg.current.OptionalIntegrity.KeyId
 		tg.valuesBuffer[10] = tg.current.OptionalIntegrity.Hash
 		tg.valuesBuffer[11] = tg.current.OptionalIntegrity.HashedAt.AsTime()
@@ -18,6 +18,7 @@ import (
 
 var (
 	_ common.GarbageCollectableDatastore = (*pgDatastore)(nil)
+	_ common.ManualGCDatastore           = (*pgDatastore)(nil)
 	_ common.GarbageCollector            = (*pgGarbageCollector)(nil)
 
 	// we are using "tableoid" to globally identify the row through the "ctid" in partitioned environments
@@ -53,6 +54,12 @@ func (pgd *pgDatastore) ResetGCCompleted() {
 	pgd.gcHasRun.Store(false)
 }
 
+// RunGC runs a single garbage collection pass using the datastore's configured
+// window and timeout, returning the statistics of the pass.
+func (pgd *pgDatastore) RunGC(_ context.Context) (common.GCStats, error) {
+	return common.RunGarbageCollectionWithStats(pgd, pgd.gcWindow, pgd.gcTimeout)
+}
+
 func (pgg *pgGarbageCollector) Close() {
 	pgg.isClosed = true
 	pgg.conn.Release()
@@ -126,16 +133,60 @@ func (pgg *pgGarbageCollector) DeleteExpiredRels(ctx context.Context) (int64, er
 		return 0, err
 	}
 
-	return pgg.batchDelete(
+	return pgg.batchDeleteRelationships(
 		ctx,
 		pgg.conn,
-		schema.TableTuple,
-		gcPKCols,
 		sq.Lt{schema.ColExpiration: now.Add(-1 * pgg.pgd.gcWindow)},
 		&schema.IndexExpiringRelationships,
 	)
 }
 
+// batchDeleteRelationships batch deletes rows from the relationship table
+// matching the given filter. On the partitioned layout, each partition is
+// processed in turn so that every batch scans a single partition.
+func (pgg *pgGarbageCollector) batchDeleteRelationships(
+	ctx context.Context,
+	conn exec,
+	filter sqlFilter,
+	index *common.IndexDefinition,
+) (int64, error) {
+	if !pgg.pgd.tablePartitioned {
+		return pgg.batchDelete(ctx, conn, schema.TableTuple, gcPKCols, filter, index)
+	}
+
+	rows, err := pgg.conn.Query(ctx, queryRelationshipPartitions)
+	if err != nil {
+		return -1, err
+	}
+
+	partitions := make([]string, 0, pgg.pgd.relationshipPartitionCount)
+	for rows.Next() {
+		var partition string
+		if err := rows.Scan(&partition); err != nil {
+			rows.Close()
+			return -1, err
+		}
+		partitions = append(partitions, partition)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return -1, err
+	}
+
+	var deletedCount int64
+	for _, partition := range partitions {
+		// No index hint is used here: the index names on the parent table do not
+		// match the auto-named per-partition indexes.
+		deleted, err := pgg.batchDelete(ctx, conn, partition, gcPKCols, filter, nil)
+		if err != nil {
+			return deletedCount, err
+		}
+		deletedCount += deleted
+	}
+
+	return deletedCount, nil
+}
+
 type exec interface {
 	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
 }
@@ -155,11 +206,9 @@ func (pgg *pgGarbageCollector) deleteBeforeTx(ctx context.Context, conn exec, tx
 	removed := common.DeletionCounts{}
 	var err error
 	// Delete any relationship rows that were already dead when this transaction started
-	removed.Relationships, err = pgg.batchDelete(
+	removed.Relationships, err = pgg.batchDeleteRelationships(
 		ctx,
 		conn,
-		schema.TableTuple,
-		gcPKCols,
 		sq.Lt{schema.ColDeletedXid: minTxAlive},
 		&schema.IndexGCDeadRelationships,
 	)
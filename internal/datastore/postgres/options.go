@@ -26,6 +26,8 @@ type postgresOptions struct {
 	maxRetries              uint8
 	filterMaximumIDCount    uint16
 
+	relationshipPartitionCount uint16
+
 	enablePrometheusStats          bool
 	analyzeBeforeStatistics        bool
 	gcEnabled                      bool
@@ -78,9 +80,10 @@ const (
 	defaultIncludeQueryParametersInTraces    = false
 	defaultWatchDisabled                     = false
 	// no follower delay by default, it should only be set if using read replicas
-	defaultFollowerReadDelay     = 0
-	defaultRevisionHeartbeat     = true
-	defaultRelaxedIsolationLevel = false
+	defaultFollowerReadDelay          = 0
+	defaultRevisionHeartbeat          = true
+	defaultRelaxedIsolationLevel      = false
+	defaultRelationshipPartitionCount = 16
 )
 
 // Option provides the facility to configure how clients within the
@@ -109,6 +112,7 @@ func generateConfig(options []Option) (postgresOptions, error) {
 		followerReadDelay:              defaultFollowerReadDelay,
 		revisionHeartbeatEnabled:       defaultRevisionHeartbeat,
 		relaxedIsolationLevel:          defaultRelaxedIsolationLevel,
+		relationshipPartitionCount:     defaultRelationshipPartitionCount,
 	}
 
 	for _, option := range options {
@@ -331,6 +335,15 @@ func GCMaxOperationTime(time time.Duration) Option {
 	return func(po *postgresOptions) { po.gcMaxOperationTime = time }
 }
 
+// RelationshipPartitionCount is the number of hash partitions created for the
+// relationship table by the partition-relationships repair operation. It has
+// no effect on datastores that remain on the non-partitioned layout.
+//
+// This value defaults to 16.
+func RelationshipPartitionCount(count uint16) Option {
+	return func(po *postgresOptions) { po.relationshipPartitionCount = count }
+}
+
 // MaxRetries is the maximum number of times a retriable transaction will be
 // client-side retried.
 // Default: 10
@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/authzed/spicedb/internal/datastore/postgres/schema"
+	log "github.com/authzed/spicedb/internal/logging"
+	"github.com/authzed/spicedb/pkg/spiceerrors"
+)
+
+const repairPartitionRelationshipsOperation = "partition-relationships"
+
+var (
+	// queryRelationshipsPartitioned reports whether the relationship table uses
+	// the opt-in partitioned layout.
+	queryRelationshipsPartitioned = fmt.Sprintf(`SELECT EXISTS (
+		SELECT FROM pg_partitioned_table pt
+		JOIN pg_class c ON c.oid = pt.partrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relname = '%s' AND n.nspname = current_schema()
+	)`, schema.TableTuple)
+
+	// queryRelationshipIndexDefs returns the definitions of all indexes on the
+	// relationship table.
+	queryRelationshipIndexDefs = fmt.Sprintf(`SELECT indexname, indexdef FROM pg_indexes
+		WHERE schemaname = current_schema() AND tablename = '%s'`, schema.TableTuple)
+
+	// queryRelationshipPartitions returns the names of the partitions backing the
+	// relationship table, in name order.
+	queryRelationshipPartitions = fmt.Sprintf(`SELECT c.relname FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		JOIN pg_class p ON p.oid = i.inhparent
+		JOIN pg_namespace n ON n.oid = p.relnamespace
+		WHERE p.relname = '%s' AND n.nspname = current_schema()
+		ORDER BY c.relname`, schema.TableTuple)
+)
+
+// rowQuerier is the subset of connection functionality needed to inspect the
+// relationship table layout.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, optionsAndArgs ...any) pgx.Row
+}
+
+// relationshipsPartitioned queries whether the relationship table uses the
+// partitioned layout, allowing the datastore to detect at startup which layout
+// it is talking to.
+func relationshipsPartitioned(ctx context.Context, querier rowQuerier) (bool, error) {
+	var partitioned bool
+	if err := querier.QueryRow(ctx, queryRelationshipsPartitioned).Scan(&partitioned); err != nil {
+		return false, fmt.Errorf("unable to determine relationship table layout: %w", err)
+	}
+	return partitioned, nil
+}
+
+func (pgd *pgDatastore) repairPartitionRelationships(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, pgd.dburl)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	return partitionRelationshipsTable(ctx, conn, pgd.relationshipPartitionCount)
+}
+
+// partitionRelationshipsTable rebuilds the relationship table hash-partitioned
+// by namespace with the given number of partitions, copying all existing rows
+// into the new layout. The rebuild runs in a single transaction that takes an
+// exclusive lock on the relationship table, so it must be run while the
+// datastore is not serving traffic. Non-partitioned deployments that never run
+// this operation are untouched.
+func partitionRelationshipsTable(ctx context.Context, conn *pgx.Conn, partitionCount uint16) error {
+	if partitionCount == 0 {
+		return spiceerrors.MustBugf("partition count must be non-zero")
+	}
+
+	alreadyPartitioned, err := relationshipsPartitioned(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if alreadyPartitioned {
+		return fmt.Errorf("table %s is already partitioned", schema.TableTuple)
+	}
+
+	return pgx.BeginTxFunc(ctx, conn, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		// Capture the existing index definitions before the rename so they can be
+		// recreated, under their original names, on the partitioned table. The
+		// names must be preserved because unique violations report the index name,
+		// which the error conversion matches against.
+		indexDefs := make([]string, 0, 8)
+		rows, err := tx.Query(ctx, queryRelationshipIndexDefs)
+		if err != nil {
+			return fmt.Errorf("unable to read relationship index definitions: %w", err)
+		}
+		for rows.Next() {
+			var indexName, indexDef string
+			if err := rows.Scan(&indexName, &indexDef); err != nil {
+				rows.Close()
+				return err
+			}
+
+			// The primary key is recreated as a constraint below, since partitioned
+			// tables do not support attaching an existing index as a primary key.
+			if indexName == "pk_relation_tuple" {
+				continue
+			}
+			indexDefs = append(indexDefs, indexDef)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		statements := []string{
+			fmt.Sprintf("ALTER TABLE %s RENAME TO %s_unpartitioned", schema.TableTuple, schema.TableTuple),
+			fmt.Sprintf("CREATE TABLE %s (LIKE %s_unpartitioned INCLUDING DEFAULTS INCLUDING STORAGE) PARTITION BY HASH (%s)",
+				schema.TableTuple, schema.TableTuple, schema.ColNamespace),
+		}
+
+		for remainder := uint16(0); remainder < partitionCount; remainder++ {
+			statements = append(statements, fmt.Sprintf(
+				"CREATE TABLE %s_p%04d PARTITION OF %s FOR VALUES WITH (MODULUS %d, REMAINDER %d)",
+				schema.TableTuple, remainder, schema.TableTuple, partitionCount, remainder,
+			))
+		}
+
+		statements = append(statements,
+			fmt.Sprintf("INSERT INTO %s SELECT * FROM %s_unpartitioned", schema.TableTuple, schema.TableTuple),
+			// Dropping the old table releases the original index names for reuse.
+			fmt.Sprintf("DROP TABLE %s_unpartitioned", schema.TableTuple),
+			fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT pk_relation_tuple PRIMARY KEY (%s, %s, %s, %s, %s, %s, %s, %s)",
+				schema.TableTuple,
+				schema.ColNamespace,
+				schema.ColObjectID,
+				schema.ColRelation,
+				schema.ColUsersetNamespace,
+				schema.ColUsersetObjectID,
+				schema.ColUsersetRelation,
+				schema.ColCreatedXid,
+				schema.ColDeletedXid,
+			),
+		)
+		statements = append(statements, indexDefs...)
+
+		for _, statement := range statements {
+			if _, err := tx.Exec(ctx, statement); err != nil {
+				return fmt.Errorf("unable to partition relationship table: %w", err)
+			}
+		}
+
+		log.Ctx(ctx).Info().Uint16("partitions", partitionCount).Msg("partitioned relationship table")
+		return nil
+	})
+}
@@ -240,6 +240,15 @@ func newPostgresDatastore(
 		}
 	}
 
+	// Detect which relationship table layout this datastore is talking to.
+	tablePartitioned, err := relationshipsPartitioned(initializationContext, readPool)
+	if err != nil {
+		return nil, err
+	}
+	if tablePartitioned {
+		log.Info().Msg("relationship table is hash-partitioned by namespace")
+	}
+
 	if config.enablePrometheusStats {
 		replicaIndexStr := strconv.Itoa(replicaIndex)
 		dbname := "spicedb"
@@ -327,30 +336,39 @@ func newPostgresDatastore(
 		CachedOptimizedRevisions: revisions.NewCachedOptimizedRevisions(
 			maxRevisionStaleness,
 		),
-		MigrationValidator:      common.NewMigrationValidator(headMigration, config.allowedMigrations),
-		dburl:                   pgURL,
-		readPool:                pgxcommon.MustNewInterceptorPooler(readPool, config.queryInterceptor),
-		writePool:               nil, /* disabled by default */
-		watchBufferLength:       config.watchBufferLength,
-		watchBufferWriteTimeout: config.watchBufferWriteTimeout,
-		optimizedRevisionQuery:  revisionQuery,
-		validTransactionQuery:   validTransactionQuery,
-		revisionHeartbeatQuery:  revisionHeartbeatQuery,
-		gcWindow:                config.gcWindow,
-		gcInterval:              config.gcInterval,
-		gcTimeout:               config.gcMaxOperationTime,
-		analyzeBeforeStatistics: config.analyzeBeforeStatistics,
-		watchEnabled:            watchEnabled,
-		workerCtx:               gcCtx,
-		cancelGc:                cancelGc,
-		readTxOptions:           pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly},
-		maxRetries:              config.maxRetries,
+		MigrationValidator:         common.NewMigrationValidator(headMigration, config.allowedMigrations),
+		dburl:                      pgURL,
+		readPool:                   pgxcommon.MustNewInterceptorPooler(readPool, config.queryInterceptor),
+		writePool:                  nil, /* disabled by default */
+		watchBufferLength:          config.watchBufferLength,
+		watchBufferWriteTimeout:    config.watchBufferWriteTimeout,
+		optimizedRevisionQuery:     revisionQuery,
+		validTransactionQuery:      validTransactionQuery,
+		revisionHeartbeatQuery:     revisionHeartbeatQuery,
+		gcWindow:                   config.gcWindow,
+		gcInterval:                 config.gcInterval,
+		gcTimeout:                  config.gcMaxOperationTime,
+		analyzeBeforeStatistics:    config.analyzeBeforeStatistics,
+		watchEnabled:               watchEnabled,
+		tablePartitioned:           tablePartitioned,
+		relationshipPartitionCount: config.relationshipPartitionCount,
+		workerCtx:                  gcCtx,
+		cancelGc:                   cancelGc,
+		readTxOptions:              pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly},
+		retryPolicy: common.RetryPolicy{
+			Backend:        Engine,
+			MaxRetries:     config.maxRetries,
+			BaseDelay:      25 * time.Millisecond,
+			JitterFraction: 0.5,
+			Classifier:     classifyRetryableError,
+		},
 		credentialsProvider:     credentialsProvider,
 		isPrimary:               isPrimary,
 		inStrictReadMode:        config.readStrictMode,
 		filterMaximumIDCount:    config.filterMaximumIDCount,
 		schema:                  *schema.Schema(config.columnOptimizationOption, false),
 		quantizationPeriodNanos: quantizationPeriodNanos,
+		followerReadDelayNanos:  followerReadDelayNanos,
 		isolationLevel:          isolationLevel,
 	}
 
@@ -407,10 +425,12 @@ type pgDatastore struct {
 	gcTimeout                      time.Duration
 	analyzeBeforeStatistics        bool
 	readTxOptions                  pgx.TxOptions
-	maxRetries                     uint8
+	retryPolicy                    common.RetryPolicy
 	watchEnabled                   bool
 	isPrimary                      bool
 	inStrictReadMode               bool
+	tablePartitioned               bool
+	relationshipPartitionCount     uint16
 	schema                         common.SchemaInformation
 	includeQueryParametersInTraces bool
 
@@ -423,6 +443,7 @@ type pgDatastore struct {
 	gcHasRun                atomic.Bool
 	filterMaximumIDCount    uint16
 	quantizationPeriodNanos int64
+	followerReadDelayNanos  int64
 	isolationLevel          pgx.TxIsoLevel
 }
 
@@ -452,6 +473,7 @@ func (pgd *pgDatastore) SnapshotReader(revRaw datastore.Revision) datastore.Read
 		buildLivingObjectFilterForRevision(rev),
 		pgd.filterMaximumIDCount,
 		pgd.schema,
+		rev,
 	}
 }
 
@@ -468,11 +490,10 @@ func (pgd *pgDatastore) ReadWriteTx(
 
 	config := options.NewRWTOptionsWithOptions(opts...)
 
-	var err error
-	for i := uint8(0); i <= pgd.maxRetries; i++ {
-		var newXID xid8
-		var newSnapshot pgSnapshot
-		err = wrapError(pgx.BeginTxFunc(ctx, pgd.writePool, pgx.TxOptions{IsoLevel: pgd.isolationLevel}, func(tx pgx.Tx) error {
+	var newXID xid8
+	var newSnapshot pgSnapshot
+	err := pgd.retryPolicy.ExecuteWithRetries(ctx, config.DisableRetries, func(ctx context.Context) error {
+		return wrapError(pgx.BeginTxFunc(ctx, pgd.writePool, pgx.TxOptions{IsoLevel: pgd.isolationLevel}, func(tx pgx.Tx) error {
 			var err error
 			var metadata map[string]any
 			if config.Metadata != nil && len(config.Metadata.GetFields()) > 0 {
@@ -496,34 +517,21 @@ func (pgd *pgDatastore) ReadWriteTx(
 					currentlyLivingObjects,
 					pgd.filterMaximumIDCount,
 					pgd.schema,
+					nil,
 				},
 				tx,
 				newXID,
+				false,
 			}
 
 			return fn(ctx, rwt)
 		}))
-		if err != nil {
-			if !config.DisableRetries && errorRetryable(err) {
-				pgxcommon.SleepOnErr(ctx, err, i)
-				continue
-			}
-
-			return datastore.NoRevision, err
-		}
-
-		if i > 0 {
-			log.Debug().Uint8("retries", i).Msg("transaction succeeded after retry")
-		}
-
-		return postgresRevision{snapshot: newSnapshot.markComplete(newXID.Uint64), optionalTxID: newXID}, nil
-	}
-
-	if !config.DisableRetries {
-		err = fmt.Errorf("max retries exceeded: %w", err)
+	})
+	if err != nil {
+		return datastore.NoRevision, err
 	}
 
-	return datastore.NoRevision, err
+	return postgresRevision{snapshot: newSnapshot.markComplete(newXID.Uint64), optionalTxID: newXID}, nil
 }
 
 const repairTransactionIDsOperation = "transaction-ids"
@@ -533,6 +541,9 @@ func (pgd *pgDatastore) Repair(ctx context.Context, operationName string, output
 	case repairTransactionIDsOperation:
 		return pgd.repairTransactionIDs(ctx, outputProgress)
 
+	case repairPartitionRelationshipsOperation:
+		return pgd.repairPartitionRelationships(ctx)
+
 	default:
 		return errors.New("unknown operation")
 	}
@@ -615,6 +626,10 @@ func (pgd *pgDatastore) RepairOperations() []datastore.RepairOperation {
 			Name:        repairTransactionIDsOperation,
 			Description: "Brings the Postgres database up to the expected transaction ID (Postgres v15+ only)",
 		},
+		{
+			Name:        repairPartitionRelationshipsOperation,
+			Description: "Rebuilds the relationship table hash-partitioned by namespace; must be run offline",
+		},
 	}
 }
 
@@ -667,20 +682,27 @@ func (pgd *pgDatastore) Close() error {
 }
 
 func errorRetryable(err error) bool {
+	_, retryable := classifyRetryableError(err)
+	return retryable
+}
+
+// classifyRetryableError determines whether the given error is retryable and, if so,
+// the error class label to record for the retry.
+func classifyRetryableError(err error) (string, bool) {
 	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-		return false
+		return "", false
 	}
 
-	if pgconn.SafeToRetry(err) {
-		return true
+	if pgxcommon.IsSerializationError(err) {
+		return "serialization", true
 	}
 
-	if pgxcommon.IsSerializationError(err) {
-		return true
+	if pgconn.SafeToRetry(err) {
+		return "transient", true
 	}
 
 	log.Warn().Err(err).Msg("unable to determine if pgx error is retryable")
-	return false
+	return "", false
 }
 
 func (pgd *pgDatastore) ReadyState(ctx context.Context) (datastore.ReadyState, error) {
@@ -733,6 +755,9 @@ func (pgd *pgDatastore) OfflineFeatures() (*datastore.Features, error) {
 			WatchEmitsImmediately: datastore.Feature{
 				Status: datastore.FeatureUnsupported,
 			},
+			StrictCursorResumption: datastore.Feature{
+				Status: datastore.FeatureSupported,
+			},
 		}, nil
 	}
 
@@ -746,6 +771,9 @@ func (pgd *pgDatastore) OfflineFeatures() (*datastore.Features, error) {
 		ContinuousCheckpointing: datastore.Feature{
 			Status: datastore.FeatureUnsupported,
 		},
+		StrictCursorResumption: datastore.Feature{
+			Status: datastore.FeatureSupported,
+		},
 	}, nil
 }
 
@@ -155,6 +155,10 @@ func testPostgresDatastore(t *testing.T, config postgresTestConfig) {
 			OverlappingRevisionTest(t, b)
 		})
 
+		t.Run("OptimizedRevisionWithStaleness", func(t *testing.T) {
+			OptimizedRevisionWithStalenessTest(t, b)
+		})
+
 		t.Run("WatchNotEnabled", func(t *testing.T) {
 			WatchNotEnabledTest(t, b, config.pgVersion)
 		})
@@ -215,6 +219,38 @@ func testPostgresDatastore(t *testing.T, config postgresTestConfig) {
 				MigrationPhase(config.migrationPhase),
 			))
 
+			t.Run("PartitionedLayout", func(t *testing.T) {
+				PartitionedLayoutTest(t, b)
+			})
+
+			// Run the full conformance suite against the opt-in partitioned layout.
+			t.Run("PartitionedLayoutConformance", func(t *testing.T) {
+				test.AllWithExceptions(t, test.DatastoreTesterFunc(func(revisionQuantization, _, gcWindow time.Duration, watchBufferLength uint16) (datastore.Datastore, error) {
+					ds := b.NewDatastore(t, func(engine, uri string) datastore.Datastore {
+						conn, err := pgx.Connect(ctx, uri)
+						require.NoError(t, err)
+						require.NoError(t, partitionRelationshipsTable(ctx, conn, 4))
+						require.NoError(t, conn.Close(ctx))
+
+						ds, err := newPostgresDatastore(ctx, uri, primaryInstanceID,
+							RevisionQuantization(revisionQuantization),
+							GCWindow(gcWindow),
+							GCInterval(veryLargeGCInterval),
+							WatchBufferLength(watchBufferLength),
+							DebugAnalyzeBeforeStatistics(),
+							WithRevisionHeartbeat(false), // heartbeat revision messes with tests that assert over revisions
+						)
+						require.NoError(t, err)
+
+						// NOTE: the index-checking proxy is not applied here, as its
+						// expected index names do not match the auto-named per-partition
+						// indexes.
+						return ds
+					})
+					return ds, nil
+				}), test.WithCategories(test.GCCategory), false)
+			})
+
 			t.Run("TestNullCaveatWatch", createDatastoreTest(
 				b,
 				NullCaveatWatchTest,
@@ -295,6 +331,16 @@ func testPostgresDatastore(t *testing.T, config postgresTestConfig) {
 			WatchBufferLength(1),
 			MigrationPhase(config.migrationPhase),
 		))
+
+		t.Run("BulkLoadFallback", createDatastoreTest(
+			b,
+			BulkLoadFallbackTest,
+			RevisionQuantization(0),
+			GCWindow(1*time.Millisecond),
+			GCInterval(veryLargeGCInterval),
+			WatchBufferLength(1),
+			MigrationPhase(config.migrationPhase),
+		))
 	})
 }
 
@@ -929,6 +975,70 @@ func QuantizedRevisionTest(t *testing.T, b testdatastore.RunningEngineForTest) {
 	}
 }
 
+func OptimizedRevisionWithStalenessTest(t *testing.T, b testdatastore.RunningEngineForTest) {
+	require := require.New(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var conn *pgx.Conn
+	ds := b.NewDatastore(t, func(engine, uri string) datastore.Datastore {
+		var err error
+		conn, err = pgx.Connect(ctx, uri)
+		require.NoError(err)
+
+		RegisterTypes(conn.TypeMap())
+
+		ds, err := newPostgresDatastore(
+			ctx,
+			uri,
+			primaryInstanceID,
+			RevisionQuantization(1*time.Millisecond),
+			GCWindow(24*time.Hour),
+			WatchBufferLength(1),
+		)
+		require.NoError(err)
+
+		return ds
+	})
+	defer ds.Close()
+
+	var dbNow time.Time
+	err := conn.QueryRow(ctx, "SELECT (NOW() AT TIME ZONE 'utc')").Scan(&dbNow)
+	require.NoError(err)
+
+	// Insert transactions both inside and outside the requested staleness window.
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	insertTxn := psql.Insert(schema.TableTransaction).Columns(schema.ColTimestamp)
+	for _, offset := range []time.Duration{-2 * time.Hour, -30 * time.Minute, -10 * time.Second} {
+		sql, args, err := insertTxn.Values(dbNow.Add(offset)).ToSql()
+		require.NoError(err)
+
+		_, err = conn.Exec(ctx, sql, args...)
+		require.NoError(err)
+	}
+
+	bounded, ok := ds.(datastore.BoundedStalenessDatastore)
+	require.True(ok, "postgres datastore should support bounded staleness revisions")
+
+	staleness := 1 * time.Hour
+	rev, err := bounded.OptimizedRevisionWithStaleness(ctx, staleness)
+	require.NoError(err)
+
+	pgRev, ok := rev.(postgresRevision)
+	require.True(ok)
+
+	var revTimestamp time.Time
+	err = conn.QueryRow(
+		ctx,
+		fmt.Sprintf("SELECT %s FROM %s WHERE %s = $1", schema.ColTimestamp, schema.TableTransaction, schema.ColXID),
+		pgRev.optionalTxID,
+	).Scan(&revTimestamp)
+	require.NoError(err)
+
+	// The selected revision must fall inside the requested staleness window.
+	require.LessOrEqual(dbNow.Sub(revTimestamp), staleness)
+}
+
 func OverlappingRevisionTest(t *testing.T, b testdatastore.RunningEngineForTest) {
 	testCases := []struct {
 		testName          string
@@ -1473,6 +1583,85 @@ func BenchmarkPostgresQuery(b *testing.B) {
 	})
 }
 
+// BenchmarkPostgresBulkLoad documents the speedup of the COPY-based bulk
+// import over the INSERT fallback used when the transaction already contains
+// other writes.
+func BenchmarkPostgresBulkLoad(b *testing.B) {
+	b.StopTimer()
+	req := require.New(b)
+
+	ds := testdatastore.RunPostgresForTesting(b, "", migrate.Head, pgversion.MinimumSupportedPostgresVersion, false).NewDatastore(b, func(engine, uri string) datastore.Datastore {
+		ctx := context.Background()
+		ds, err := newPostgresDatastore(ctx, uri,
+			primaryInstanceID,
+			RevisionQuantization(0),
+			GCWindow(time.Millisecond*1),
+			GCInterval(veryLargeGCInterval),
+			WatchBufferLength(1),
+		)
+		require.NoError(b, err)
+		return ds
+	})
+	ds, _ = testfixtures.StandardDatastoreWithSchema(ds, req)
+	b.Cleanup(func() {
+		_ = ds.Close()
+	})
+
+	const bulkLoadBenchmarkSize = 1_000
+	ctx := context.Background()
+
+	b.StartTimer()
+
+	// Each iteration loads under a distinct resource type so that rows never
+	// collide across iterations.
+	b.Run("copy", func(b *testing.B) {
+		require := require.New(b)
+
+		for i := 0; i < b.N; i++ {
+			_, err := ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+				loaded, err := rwt.BulkLoad(ctx, testfixtures.NewBulkRelationshipGenerator(
+					fmt.Sprintf("copyresource%d", i),
+					"viewer",
+					testfixtures.UserNS.Name,
+					bulkLoadBenchmarkSize,
+					nil,
+				))
+				require.NoError(err)
+				require.Equal(uint64(bulkLoadBenchmarkSize), loaded)
+				return nil
+			})
+			require.NoError(err)
+		}
+	})
+
+	b.Run("insert fallback", func(b *testing.B) {
+		require := require.New(b)
+
+		for i := 0; i < b.N; i++ {
+			_, err := ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+				err := rwt.WriteRelationships(ctx, []tuple.RelationshipUpdate{
+					tuple.Touch(tuple.MustParse(fmt.Sprintf("insertresource%d:sourcedoc#viewer@user:someuser", i))),
+				})
+				if err != nil {
+					return err
+				}
+
+				loaded, err := rwt.BulkLoad(ctx, testfixtures.NewBulkRelationshipGenerator(
+					fmt.Sprintf("insertresource%d", i),
+					"viewer",
+					testfixtures.UserNS.Name,
+					bulkLoadBenchmarkSize,
+					nil,
+				))
+				require.NoError(err)
+				require.Equal(uint64(bulkLoadBenchmarkSize), loaded)
+				return nil
+			})
+			require.NoError(err)
+		}
+	})
+}
+
 func datastoreWithInterceptorAndTestData(t *testing.T, interceptor pgcommon.QueryInterceptor, pgVersion string) datastore.Datastore {
 	require := require.New(t)
 
@@ -1708,6 +1897,90 @@ func RepairTransactionsTest(t *testing.T, ds datastore.Datastore) {
 	require.Greater(t, currentMaximumID, 12345)
 }
 
+func PartitionedLayoutTest(t *testing.T, b testdatastore.RunningEngineForTest) {
+	require := require.New(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	ds := b.NewDatastore(t, func(engine, uri string) datastore.Datastore {
+		conn, err := pgx.Connect(ctx, uri)
+		require.NoError(err)
+		defer conn.Close(ctx)
+
+		// Convert the fresh database to the partitioned layout, then ensure the
+		// conversion refuses to run twice.
+		require.NoError(partitionRelationshipsTable(ctx, conn, 4))
+		require.Error(partitionRelationshipsTable(ctx, conn, 4))
+
+		ds, err := newPostgresDatastore(ctx, uri, primaryInstanceID,
+			RevisionQuantization(0),
+			GCWindow(1*time.Millisecond),
+			GCInterval(veryLargeGCInterval),
+			WatchBufferLength(1),
+		)
+		require.NoError(err)
+		return ds
+	})
+	defer ds.Close()
+
+	pds := ds.(*pgDatastore)
+	require.True(pds.tablePartitioned, "expected the partitioned layout to be detected at startup")
+
+	// Write relationships across several namespaces, so that rows land in
+	// multiple partitions.
+	rels := make([]tuple.Relationship, 0, 8)
+	for i := 0; i < 8; i++ {
+		rels = append(rels, tuple.MustParse(fmt.Sprintf("resource%d:someobj#reader@user:tom", i)))
+	}
+
+	_, err := ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		updates := make([]tuple.RelationshipUpdate, 0, len(rels))
+		for _, rel := range rels {
+			updates = append(updates, tuple.Touch(rel))
+		}
+		return rwt.WriteRelationships(ctx, updates)
+	})
+	require.NoError(err)
+
+	headRev, err := ds.HeadRevision(ctx)
+	require.NoError(err)
+
+	reader := ds.SnapshotReader(headRev)
+	it, err := reader.QueryRelationships(ctx, datastore.RelationshipsFilter{
+		OptionalResourceType: "resource3",
+	})
+	require.NoError(err)
+
+	found, err := datastore.IteratorToSlice(it)
+	require.NoError(err)
+	require.Len(found, 1)
+
+	// Ensure unique violations are still mapped to the expected error, which
+	// requires the index names to have been preserved through the rebuild.
+	_, err = ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.WriteRelationships(ctx, []tuple.RelationshipUpdate{tuple.Create(rels[0])})
+	})
+	require.Error(err)
+	require.ErrorAs(err, &common.CreateRelationshipExistsError{})
+
+	// Delete a relationship and garbage collect the dead row per-partition.
+	_, err = ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.WriteRelationships(ctx, []tuple.RelationshipUpdate{tuple.Delete(rels[0])})
+	})
+	require.NoError(err)
+
+	gcRev, err := ds.HeadRevision(ctx)
+	require.NoError(err)
+
+	pgg, err := pds.BuildGarbageCollector(ctx)
+	require.NoError(err)
+	defer pgg.Close()
+
+	removed, err := pgg.DeleteBeforeTx(ctx, gcRev)
+	require.NoError(err)
+	require.Positive(removed.Relationships)
+}
+
 func LockingTest(t *testing.T, ds datastore.Datastore, ds2 datastore.Datastore) {
 	pds := ds.(*pgDatastore)
 	pds2 := ds2.(*pgDatastore)
@@ -2073,4 +2346,74 @@ func ExceedInsertQuerySizeTest(t *testing.T, ds datastore.Datastore) {
 	require.Equal(0, count, "expected to have 0 relationships, but found %d", count)
 }
 
+func BulkLoadFallbackTest(t *testing.T, ds datastore.Datastore) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ds, _ = testfixtures.StandardDatastoreWithSchema(ds, require)
+
+	// A relationship write earlier in the transaction forces BulkLoad off the
+	// COPY path and onto the INSERT fallback.
+	const bulkSourceSize = 2_500
+	_, err := ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		err := rwt.WriteRelationships(ctx, []tuple.RelationshipUpdate{
+			tuple.Touch(tuple.MustParse("document:sourcedoc#viewer@user:firstuser")),
+		})
+		if err != nil {
+			return err
+		}
+
+		loaded, err := rwt.BulkLoad(ctx, testfixtures.NewBulkRelationshipGenerator(
+			testfixtures.DocumentNS.Name,
+			"viewer",
+			testfixtures.UserNS.Name,
+			bulkSourceSize,
+			t,
+		))
+		require.NoError(err)
+		require.Equal(uint64(bulkSourceSize), loaded)
+		return nil
+	})
+	require.NoError(err)
+
+	headRev, err := ds.HeadRevision(ctx)
+	require.NoError(err)
+
+	iter, err := ds.SnapshotReader(headRev).QueryRelationships(ctx, datastore.RelationshipsFilter{
+		OptionalResourceType: testfixtures.DocumentNS.Name,
+	}, options.WithQueryShape(queryshape.FindResourceOfType))
+	require.NoError(err)
+
+	count := 0
+	for _, err := range iter {
+		require.NoError(err)
+		count++
+	}
+	require.Equal(bulkSourceSize+1, count)
+
+	// Duplicates in the source must surface as the standard already-exists
+	// error on the fallback path as well.
+	_, err = ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		err := rwt.WriteRelationships(ctx, []tuple.RelationshipUpdate{
+			tuple.Touch(tuple.MustParse("document:sourcedoc#viewer@user:seconduser")),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, serr := rwt.BulkLoad(ctx, testfixtures.NewBulkRelationshipGenerator(
+			testfixtures.DocumentNS.Name,
+			"viewer",
+			testfixtures.UserNS.Name,
+			bulkSourceSize,
+			t,
+		))
+		return serr
+	}, options.WithDisableRetries(true))
+
+	existsErr := common.CreateRelationshipExistsError{}
+	require.ErrorAs(err, &existsErr)
+	require.NotNil(existsErr.Relationship)
+}
+
 const waitForChangesTimeout = 10 * time.Second
@@ -22,6 +22,10 @@ type pgReader struct {
 	aliveFilter          queryFilterer
 	filterMaximumIDCount uint16
 	schema               common.SchemaInformation
+
+	// atRevision is the revision at which this reader operates, or nil if unknown
+	// (such as within a read-write transaction).
+	atRevision datastore.Revision
 }
 
 type queryFilterer func(original sq.SelectBuilder) sq.SelectBuilder
@@ -158,6 +162,10 @@ func (r *pgReader) QueryRelationships(
 	}
 
 	builtOpts := options.NewQueryOptionsWithOptions(opts...)
+	if err := common.ValidateCursorResumption(builtOpts, r.atRevision); err != nil {
+		return nil, err
+	}
+
 	indexingHint := schema.IndexingHintForQueryShape(r.schema, builtOpts.QueryShape)
 	qBuilder = qBuilder.WithIndexingHint(indexingHint)
 
@@ -194,6 +202,7 @@ func (r *pgReader) ReverseQueryRelationships(
 		options.WithSort(queryOpts.SortForReverse),
 		options.WithSkipCaveats(queryOpts.SkipCaveatsForReverse),
 		options.WithSkipExpiration(queryOpts.SkipExpirationForReverse),
+		options.WithExpirationBehavior(queryOpts.ExpirationBehaviorForReverse),
 		options.WithQueryShape(queryOpts.QueryShapeForReverse),
 		options.WithSQLExplainCallbackForTest(queryOpts.SQLExplainCallbackForTestForReverse),
 	)
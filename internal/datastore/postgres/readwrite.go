@@ -82,6 +82,11 @@ type pgReadWriteTXN struct {
 	*pgReader
 	tx     pgx.Tx
 	newXID xid8
+
+	// hasNonBulkWrites is set when the transaction has mutated the relationship
+	// table outside of BulkLoad, in which case BulkLoad falls back from COPY to
+	// the INSERT path.
+	hasNonBulkWrites bool
 }
 
 func appendForInsertion(builder sq.InsertBuilder, tpl tuple.Relationship) sq.InsertBuilder {
@@ -169,6 +174,8 @@ func (rwt *pgReadWriteTXN) collectSimplifiedTouchTypes(ctx context.Context, muta
 }
 
 func (rwt *pgReadWriteTXN) WriteRelationships(ctx context.Context, mutations []tuple.RelationshipUpdate) error {
+	rwt.hasNonBulkWrites = true
+
 	touchMutationsByNonCaveat := make(map[string]tuple.RelationshipUpdate, len(mutations))
 	hasCreateInserts := false
 
@@ -430,6 +437,8 @@ func handleWriteError(err error) error {
 }
 
 func (rwt *pgReadWriteTXN) DeleteRelationships(ctx context.Context, filter *v1.RelationshipFilter, opts ...options.DeleteOptionsOption) (uint64, bool, error) {
+	rwt.hasNonBulkWrites = true
+
 	delOpts := options.NewDeleteOptionsWithOptionsAndDefaults(opts...)
 	if delOpts.DeleteLimit != nil && *delOpts.DeleteLimit > 0 {
 		return rwt.deleteRelationshipsWithLimit(ctx, filter, *delOpts.DeleteLimit)
@@ -607,6 +616,8 @@ func (rwt *pgReadWriteTXN) WriteNamespaces(ctx context.Context, newConfigs ...*c
 }
 
 func (rwt *pgReadWriteTXN) DeleteNamespaces(ctx context.Context, nsNames ...string) error {
+	rwt.hasNonBulkWrites = true
+
 	aliveFilter := func(original sq.SelectBuilder) sq.SelectBuilder {
 		return original.Where(sq.Eq{schema.ColDeletedXid: liveDeletedTxnID})
 	}
@@ -766,10 +777,56 @@ var copyCols = []string{
 	schema.ColExpiration,
 }
 
+// bulkLoadInsertChunkSize caps the number of rows per INSERT statement when
+// falling back from COPY, keeping each statement well under Postgres' bind
+// parameter limit.
+const bulkLoadInsertChunkSize = 1_000
+
 func (rwt *pgReadWriteTXN) BulkLoad(ctx context.Context, iter datastore.BulkWriteRelationshipSource) (uint64, error) {
+	// COPY is the fastest way to load relationships, but once the transaction
+	// has performed other relationship mutations, fall back to the INSERT path
+	// so that the bulk load interacts with those mutations in the same manner
+	// as any other write.
+	if rwt.hasNonBulkWrites {
+		return rwt.bulkLoadViaInserts(ctx, iter)
+	}
+
 	return pgxcommon.BulkLoad(ctx, rwt.tx, schema.TableTuple, copyCols, iter)
 }
 
+func (rwt *pgReadWriteTXN) bulkLoadViaInserts(ctx context.Context, iter datastore.BulkWriteRelationshipSource) (numWritten uint64, err error) {
+	var rel *tuple.Relationship
+
+	rel, err = iter.Next(ctx)
+	for rel != nil && err == nil {
+		insert := writeTuple
+		var chunkLen uint64
+		for ; rel != nil && err == nil && chunkLen < bulkLoadInsertChunkSize; rel, err = iter.Next(ctx) {
+			insert = appendForInsertion(insert, *rel)
+			chunkLen++
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		sql, args, err := insert.ToSql()
+		if err != nil {
+			return 0, handleWriteError(err)
+		}
+
+		if _, err := rwt.tx.Exec(ctx, sql, args...); err != nil {
+			return 0, handleWriteError(err)
+		}
+
+		numWritten += chunkLen
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return numWritten, nil
+}
+
 func exactRelationshipClause(r tuple.Relationship) sq.Eq {
 	return sq.Eq{
 		schema.ColNamespace:        r.Resource.ObjectType,
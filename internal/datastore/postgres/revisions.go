@@ -118,6 +118,38 @@ func (pgd *pgDatastore) optimizedRevisionFunc(ctx context.Context) (datastore.Re
 	return postgresRevision{snapshot: snapshot, optionalTxID: revision}, validForNanos, nil
 }
 
+// OptimizedRevisionWithStaleness implements datastore.BoundedStalenessDatastore by selecting
+// a revision quantized to the requested staleness window, rather than the configured
+// quantization period.
+func (pgd *pgDatastore) OptimizedRevisionWithStaleness(ctx context.Context, maxStaleness time.Duration) (datastore.Revision, error) {
+	stalenessPeriodNanos := maxStaleness.Nanoseconds()
+	if stalenessPeriodNanos <= pgd.quantizationPeriodNanos {
+		return pgd.OptimizedRevision(ctx)
+	}
+
+	revisionQuery := fmt.Sprintf(
+		querySelectRevision,
+		schema.ColXID,
+		schema.TableTransaction,
+		schema.ColTimestamp,
+		stalenessPeriodNanos,
+		schema.ColSnapshot,
+		pgd.followerReadDelayNanos,
+	)
+
+	var revision xid8
+	var snapshot pgSnapshot
+	var validForNanos time.Duration
+	if err := pgd.readPool.QueryRow(ctx, revisionQuery).
+		Scan(&revision, &snapshot, &validForNanos); err != nil {
+		return datastore.NoRevision, fmt.Errorf(errRevision, err)
+	}
+
+	snapshot = snapshot.markComplete(revision.Uint64)
+
+	return postgresRevision{snapshot: snapshot, optionalTxID: revision}, nil
+}
+
 func (pgd *pgDatastore) HeadRevision(ctx context.Context) (datastore.Revision, error) {
 	ctx, span := tracer.Start(ctx, "HeadRevision")
 	defer span.End()
@@ -162,8 +194,8 @@ func (pgd *pgDatastore) CheckRevision(ctx context.Context, revisionRaw datastore
 	if revisionRaw.GreaterThan(postgresRevision{snapshot: currentSnapshot}) {
 		return datastore.NewInvalidRevisionErr(revision, datastore.CouldNotDetermineRevision)
 	}
-	if minSnapshot.markComplete(minXid.Uint64).GreaterThan(revision.snapshot) {
-		return datastore.NewInvalidRevisionErr(revision, datastore.RevisionStale)
+	if minValidSnapshot := minSnapshot.markComplete(minXid.Uint64); minValidSnapshot.GreaterThan(revision.snapshot) {
+		return datastore.NewStaleRevisionErr(revision, postgresRevision{snapshot: minValidSnapshot})
 	}
 
 	return nil
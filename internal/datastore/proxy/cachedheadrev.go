@@ -0,0 +1,208 @@
+package proxy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	log "github.com/authzed/spicedb/internal/logging"
+	"github.com/authzed/spicedb/pkg/datastore"
+	"github.com/authzed/spicedb/pkg/datastore/options"
+)
+
+var (
+	headRevisionCacheHitCount = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "spicedb",
+		Subsystem: "datastore",
+		Name:      "head_revision_cache_hit_total",
+		Help:      "number of optimized revision requests served from the head revision cache",
+	})
+
+	headRevisionCacheMissCount = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "spicedb",
+		Subsystem: "datastore",
+		Name:      "head_revision_cache_miss_total",
+		Help:      "number of optimized revision requests that bypassed the head revision cache because it was empty or stale",
+	})
+
+	headRevisionCacheAgeGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "spicedb",
+		Subsystem: "datastore",
+		Name:      "head_revision_cache_age_seconds",
+		Help:      "age of the cached head revision when it was last served",
+	})
+)
+
+// NewCachedHeadRevisionProxy creates a proxy which serves optimized revisions
+// from a head revision refreshed in the background at the given interval, so
+// long as the cached revision is no older than maxStaleness. Requests for the
+// head revision itself (used by fully-consistent operations) always bypass the
+// cache, and the cache is invalidated after any local write so that
+// read-your-writes holds within the process.
+func NewCachedHeadRevisionProxy(delegate datastore.Datastore, refreshInterval time.Duration, maxStaleness time.Duration) datastore.Datastore {
+	proxy := &cachedHeadRevProxy{
+		delegate:     delegate,
+		maxStaleness: maxStaleness,
+		clockFn:      clock.New(),
+		closed:       make(chan struct{}),
+	}
+	go proxy.refreshLoop(refreshInterval)
+	return proxy
+}
+
+type cachedHeadRevProxy struct {
+	delegate     datastore.Datastore
+	maxStaleness time.Duration
+	clockFn      clock.Clock
+
+	sync.RWMutex
+	cachedRev  datastore.Revision // GUARDED_BY(RWMutex)
+	fetchedAt  time.Time          // GUARDED_BY(RWMutex)
+	generation uint64             // GUARDED_BY(RWMutex)
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+var _ datastore.Datastore = (*cachedHeadRevProxy)(nil)
+
+func (p *cachedHeadRevProxy) refreshLoop(refreshInterval time.Duration) {
+	ticker := p.clockFn.Ticker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.closed:
+			return
+
+		case <-ticker.C:
+			p.refresh(context.Background())
+		}
+	}
+}
+
+// refresh fetches the current head revision from the delegate and stores it in
+// the cache, unless the cache was invalidated while the fetch was in flight.
+func (p *cachedHeadRevProxy) refresh(ctx context.Context) {
+	p.RLock()
+	generation := p.generation
+	p.RUnlock()
+
+	rev, err := p.delegate.HeadRevision(ctx)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("error refreshing cached head revision")
+		return
+	}
+
+	fetchedAt := p.clockFn.Now()
+
+	p.Lock()
+	defer p.Unlock()
+
+	// If a write invalidated the cache after the fetch began, the fetched
+	// revision may predate the write; drop it rather than serve it.
+	if p.generation != generation {
+		return
+	}
+
+	p.cachedRev = rev
+	p.fetchedAt = fetchedAt
+}
+
+// invalidate clears the cached revision and ensures any in-flight refresh
+// started before the invalidation cannot repopulate the cache.
+func (p *cachedHeadRevProxy) invalidate() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.generation++
+	p.cachedRev = nil
+}
+
+func (p *cachedHeadRevProxy) OptimizedRevision(ctx context.Context) (datastore.Revision, error) {
+	p.RLock()
+	cachedRev := p.cachedRev
+	fetchedAt := p.fetchedAt
+	p.RUnlock()
+
+	if cachedRev != nil {
+		age := p.clockFn.Since(fetchedAt)
+		if age <= p.maxStaleness {
+			headRevisionCacheHitCount.Inc()
+			headRevisionCacheAgeGauge.Set(age.Seconds())
+			return cachedRev, nil
+		}
+	}
+
+	headRevisionCacheMissCount.Inc()
+	return p.delegate.OptimizedRevision(ctx)
+}
+
+func (p *cachedHeadRevProxy) HeadRevision(ctx context.Context) (datastore.Revision, error) {
+	// NOTE: fully-consistent operations resolve their revision via HeadRevision,
+	// so it must always bypass the cache.
+	return p.delegate.HeadRevision(ctx)
+}
+
+func (p *cachedHeadRevProxy) ReadWriteTx(ctx context.Context, f datastore.TxUserFunc, opts ...options.RWTOptionsOption) (datastore.Revision, error) {
+	rev, err := p.delegate.ReadWriteTx(ctx, f, opts...)
+	if err == nil {
+		p.invalidate()
+	}
+	return rev, err
+}
+
+func (p *cachedHeadRevProxy) MetricsID() (string, error) {
+	return p.delegate.MetricsID()
+}
+
+func (p *cachedHeadRevProxy) UniqueID(ctx context.Context) (string, error) {
+	return p.delegate.UniqueID(ctx)
+}
+
+func (p *cachedHeadRevProxy) SnapshotReader(rev datastore.Revision) datastore.Reader {
+	return p.delegate.SnapshotReader(rev)
+}
+
+func (p *cachedHeadRevProxy) CheckRevision(ctx context.Context, revision datastore.Revision) error {
+	return p.delegate.CheckRevision(ctx, revision)
+}
+
+func (p *cachedHeadRevProxy) RevisionFromString(serialized string) (datastore.Revision, error) {
+	return p.delegate.RevisionFromString(serialized)
+}
+
+func (p *cachedHeadRevProxy) Watch(ctx context.Context, afterRevision datastore.Revision, options datastore.WatchOptions) (<-chan datastore.RevisionChanges, <-chan error) {
+	return p.delegate.Watch(ctx, afterRevision, options)
+}
+
+func (p *cachedHeadRevProxy) Statistics(ctx context.Context) (datastore.Stats, error) {
+	return p.delegate.Statistics(ctx)
+}
+
+func (p *cachedHeadRevProxy) Features(ctx context.Context) (*datastore.Features, error) {
+	return p.delegate.Features(ctx)
+}
+
+func (p *cachedHeadRevProxy) OfflineFeatures() (*datastore.Features, error) {
+	return p.delegate.OfflineFeatures()
+}
+
+func (p *cachedHeadRevProxy) ReadyState(ctx context.Context) (datastore.ReadyState, error) {
+	return p.delegate.ReadyState(ctx)
+}
+
+func (p *cachedHeadRevProxy) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.closed)
+	})
+	return p.delegate.Close()
+}
+
+func (p *cachedHeadRevProxy) Unwrap() datastore.Datastore {
+	return p.delegate
+}
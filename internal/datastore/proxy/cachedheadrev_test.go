@@ -0,0 +1,172 @@
+package proxy
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/pkg/datastore"
+	"github.com/authzed/spicedb/pkg/datastore/revisionparsing"
+)
+
+type fakeHeadRevDatastore struct {
+	fakeDatastore
+
+	lock           sync.Mutex
+	headRev        datastore.Revision
+	headCalls      int
+	optimizedCalls int
+
+	// headStarted and blockHead, if non-nil, allow tests to pause HeadRevision
+	// calls in flight.
+	headStarted chan struct{}
+	blockHead   chan struct{}
+}
+
+func (f *fakeHeadRevDatastore) HeadRevision(_ context.Context) (datastore.Revision, error) {
+	f.lock.Lock()
+	f.headCalls++
+	headStarted := f.headStarted
+	blockHead := f.blockHead
+	rev := f.headRev
+	f.lock.Unlock()
+
+	if headStarted != nil {
+		headStarted <- struct{}{}
+	}
+	if blockHead != nil {
+		<-blockHead
+	}
+
+	return rev, nil
+}
+
+func (f *fakeHeadRevDatastore) OptimizedRevision(_ context.Context) (datastore.Revision, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.optimizedCalls++
+	return f.headRev, nil
+}
+
+func (f *fakeHeadRevDatastore) counts() (int, int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.headCalls, f.optimizedCalls
+}
+
+func newCachedHeadRevProxyForTest(delegate datastore.Datastore, maxStaleness time.Duration, clockFn clock.Clock) *cachedHeadRevProxy {
+	return &cachedHeadRevProxy{
+		delegate:     delegate,
+		maxStaleness: maxStaleness,
+		clockFn:      clockFn,
+		closed:       make(chan struct{}),
+	}
+}
+
+func TestCachedHeadRevisionServesWithinStalenessBound(t *testing.T) {
+	mockClock := clock.NewMock()
+	delegate := &fakeHeadRevDatastore{headRev: revisionparsing.MustParseRevisionForTest("1")}
+	proxy := newCachedHeadRevProxyForTest(delegate, 5*time.Second, mockClock)
+
+	// With an empty cache, optimized revisions fall through to the delegate.
+	_, err := proxy.OptimizedRevision(t.Context())
+	require.NoError(t, err)
+	_, optimizedCalls := delegate.counts()
+	require.Equal(t, 1, optimizedCalls)
+
+	// Once refreshed, optimized revisions are served from the cache.
+	proxy.refresh(t.Context())
+	rev, err := proxy.OptimizedRevision(t.Context())
+	require.NoError(t, err)
+	require.True(t, revisionparsing.MustParseRevisionForTest("1").Equal(rev))
+
+	mockClock.Add(3 * time.Second)
+	_, err = proxy.OptimizedRevision(t.Context())
+	require.NoError(t, err)
+	_, optimizedCalls = delegate.counts()
+	require.Equal(t, 1, optimizedCalls)
+
+	// Once the cached revision exceeds the staleness bound, the delegate is
+	// used again.
+	mockClock.Add(10 * time.Second)
+	_, err = proxy.OptimizedRevision(t.Context())
+	require.NoError(t, err)
+	_, optimizedCalls = delegate.counts()
+	require.Equal(t, 2, optimizedCalls)
+}
+
+func TestCachedHeadRevisionFullConsistencyBypassesCache(t *testing.T) {
+	mockClock := clock.NewMock()
+	delegate := &fakeHeadRevDatastore{headRev: revisionparsing.MustParseRevisionForTest("1")}
+	proxy := newCachedHeadRevProxyForTest(delegate, 5*time.Second, mockClock)
+
+	proxy.refresh(t.Context())
+	headCalls, _ := delegate.counts()
+	require.Equal(t, 1, headCalls)
+
+	// Even with a fresh cache, head revision requests always hit the delegate.
+	_, err := proxy.HeadRevision(t.Context())
+	require.NoError(t, err)
+	_, err = proxy.HeadRevision(t.Context())
+	require.NoError(t, err)
+
+	headCalls, _ = delegate.counts()
+	require.Equal(t, 3, headCalls)
+}
+
+func TestCachedHeadRevisionInvalidatedByWrite(t *testing.T) {
+	mockClock := clock.NewMock()
+	delegate := &fakeHeadRevDatastore{headRev: revisionparsing.MustParseRevisionForTest("1")}
+	proxy := newCachedHeadRevProxyForTest(delegate, 5*time.Second, mockClock)
+
+	proxy.refresh(t.Context())
+	_, err := proxy.OptimizedRevision(t.Context())
+	require.NoError(t, err)
+	_, optimizedCalls := delegate.counts()
+	require.Equal(t, 0, optimizedCalls)
+
+	// A successful write invalidates the cache, so the next optimized revision
+	// request must consult the delegate.
+	_, err = proxy.ReadWriteTx(t.Context(), nil)
+	require.NoError(t, err)
+
+	_, err = proxy.OptimizedRevision(t.Context())
+	require.NoError(t, err)
+	_, optimizedCalls = delegate.counts()
+	require.Equal(t, 1, optimizedCalls)
+}
+
+func TestCachedHeadRevisionInFlightRefreshCannotRepopulateAfterInvalidation(t *testing.T) {
+	mockClock := clock.NewMock()
+	delegate := &fakeHeadRevDatastore{
+		headRev:     revisionparsing.MustParseRevisionForTest("1"),
+		headStarted: make(chan struct{}, 1),
+		blockHead:   make(chan struct{}),
+	}
+	proxy := newCachedHeadRevProxyForTest(delegate, 5*time.Second, mockClock)
+
+	// Start a refresh and pause it mid-fetch.
+	refreshed := make(chan struct{})
+	go func() {
+		proxy.refresh(context.Background())
+		close(refreshed)
+	}()
+	<-delegate.headStarted
+
+	// A write lands while the refresh is in flight; the fetched revision may
+	// predate the write, so the refresh must not repopulate the cache.
+	proxy.invalidate()
+	close(delegate.blockHead)
+	<-refreshed
+
+	_, err := proxy.OptimizedRevision(t.Context())
+	require.NoError(t, err)
+	_, optimizedCalls := delegate.counts()
+	require.Equal(t, 1, optimizedCalls, "stale refresh should not have repopulated the cache")
+}
@@ -0,0 +1,326 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/authzed/spicedb/internal/datastore/common"
+	log "github.com/authzed/spicedb/internal/logging"
+	"github.com/authzed/spicedb/pkg/cache"
+	"github.com/authzed/spicedb/pkg/datastore"
+	"github.com/authzed/spicedb/pkg/datastore/options"
+	"github.com/authzed/spicedb/pkg/tuple"
+)
+
+// watchRetryInterval is the amount of time to wait before restarting the
+// invalidation watch after it disconnects.
+const watchRetryInterval = 1 * time.Second
+
+// NewRelationshipCachingProxy creates a proxy which caches the materialized
+// results of QueryRelationships and ReverseQueryRelationships calls for the
+// given namespaces, keyed by (revision, canonicalized filter).
+//
+// Cached relationships are stored as decoded tuples and replayed unchanged,
+// so caveat context and expiration are preserved byte-for-byte. Entries are
+// invalidated by watching the delegate's relationship changes: a change to a
+// cached namespace bumps that namespace's generation, orphaning all of its
+// existing entries so they age out of the bounded cache.
+//
+// Only unpaginated queries (no limit and no cursor) are cached; all other
+// queries pass through to the delegate.
+func NewRelationshipCachingProxy(delegate datastore.Datastore, cacheConfig *cache.Config, cachedNamespaces []string) (datastore.Datastore, error) {
+	relCache, err := cache.NewStandardCacheWithMetrics[cache.StringKey, []tuple.Relationship]("relationship_cache", cacheConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create relationship cache: %w", err)
+	}
+
+	generations := make(map[string]*atomic.Uint64, len(cachedNamespaces))
+	for _, namespaceName := range cachedNamespaces {
+		generations[namespaceName] = &atomic.Uint64{}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &relationshipCachingProxy{
+		Datastore:   delegate,
+		relCache:    relCache,
+		generations: generations,
+		cancelWatch: cancel,
+	}
+
+	// Establish the invalidation watch before returning, so that no write made
+	// after construction can be missed.
+	headRev, err := delegate.HeadRevision(ctx)
+	if err != nil {
+		cancel()
+		relCache.Close()
+		return nil, fmt.Errorf("unable to establish relationship cache invalidation watch: %w", err)
+	}
+
+	changes, errChan := delegate.Watch(ctx, headRev, datastore.WatchJustRelationships())
+	go p.watchForInvalidations(ctx, changes, errChan)
+	return p, nil
+}
+
+type relationshipCachingProxy struct {
+	datastore.Datastore
+
+	relCache cache.Cache[cache.StringKey, []tuple.Relationship]
+
+	// generations holds a monotonically increasing counter for each opted-in
+	// namespace. The current value is embedded in every cache key, so bumping
+	// a counter invalidates all cached entries for that namespace.
+	generations map[string]*atomic.Uint64
+
+	cancelWatch context.CancelFunc
+}
+
+func (p *relationshipCachingProxy) SnapshotReader(rev datastore.Revision) datastore.Reader {
+	return &relationshipCachingReader{p.Datastore.SnapshotReader(rev), rev, p}
+}
+
+func (p *relationshipCachingProxy) Close() error {
+	p.cancelWatch()
+	p.relCache.Close()
+	return p.Datastore.Close()
+}
+
+// watchForInvalidations consumes the delegate's relationship changes and bumps
+// the generation of any cached namespace that is written. If the watch
+// disconnects, all generations are bumped before reconnecting, since changes
+// may have been missed in the interim.
+func (p *relationshipCachingProxy) watchForInvalidations(ctx context.Context, changes <-chan datastore.RevisionChanges, errChan <-chan error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case change, ok := <-changes:
+			if !ok {
+				changes, errChan = p.reestablishWatch(ctx)
+				continue
+			}
+
+			for _, relChange := range change.RelationshipChanges {
+				p.invalidateNamespace(relChange.Relationship.Resource.ObjectType)
+			}
+
+		case err := <-errChan:
+			if ctx.Err() != nil {
+				return
+			}
+
+			log.Warn().Err(err).Msg("relationship cache invalidation watch disconnected; restarting")
+			changes, errChan = p.reestablishWatch(ctx)
+		}
+	}
+}
+
+// reestablishWatch restarts the invalidation watch after a disconnect,
+// retrying until the head revision can be resolved. All generations are
+// bumped first, as changes may have been missed while disconnected.
+func (p *relationshipCachingProxy) reestablishWatch(ctx context.Context) (<-chan datastore.RevisionChanges, <-chan error) {
+	for {
+		p.invalidateAll()
+
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-time.After(watchRetryInterval):
+		}
+
+		headRev, err := p.Datastore.HeadRevision(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil
+			}
+
+			log.Warn().Err(err).Msg("unable to resolve head revision for relationship cache invalidation")
+			continue
+		}
+
+		return p.Datastore.Watch(ctx, headRev, datastore.WatchJustRelationships())
+	}
+}
+
+func (p *relationshipCachingProxy) invalidateNamespace(namespaceName string) {
+	if generation, ok := p.generations[namespaceName]; ok {
+		generation.Add(1)
+	}
+}
+
+func (p *relationshipCachingProxy) invalidateAll() {
+	for _, generation := range p.generations {
+		generation.Add(1)
+	}
+}
+
+// generationFor returns the current cache generation for the namespace, or
+// false if the namespace has not been opted into caching.
+func (p *relationshipCachingProxy) generationFor(namespaceName string) (uint64, bool) {
+	generation, ok := p.generations[namespaceName]
+	if !ok {
+		return 0, false
+	}
+	return generation.Load(), true
+}
+
+type relationshipCachingReader struct {
+	datastore.Reader
+	rev datastore.Revision
+	p   *relationshipCachingProxy
+}
+
+func (r *relationshipCachingReader) QueryRelationships(
+	ctx context.Context,
+	filter datastore.RelationshipsFilter,
+	opts ...options.QueryOptionsOption,
+) (datastore.RelationshipIterator, error) {
+	generation, cacheable := r.p.generationFor(filter.OptionalResourceType)
+	queryOpts := options.NewQueryOptionsWithOptions(opts...)
+	if !cacheable || queryOpts.Limit != nil || queryOpts.After != nil {
+		return r.Reader.QueryRelationships(ctx, filter, opts...)
+	}
+
+	key := cache.StringKey(fmt.Sprintf("f:%d:%s:%s:%d:%t:%t:%d",
+		generation,
+		r.rev,
+		canonicalizedRelationshipsFilter(filter),
+		queryOpts.Sort,
+		queryOpts.SkipCaveats,
+		queryOpts.SkipExpiration,
+		queryOpts.ExpirationBehavior,
+	))
+	if rels, ok := r.p.relCache.Get(key); ok {
+		return common.NewSliceRelationshipIterator(rels), nil
+	}
+
+	iter, err := r.Reader.QueryRelationships(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.materializeAndCache(iter, key)
+}
+
+func (r *relationshipCachingReader) ReverseQueryRelationships(
+	ctx context.Context,
+	subjectsFilter datastore.SubjectsFilter,
+	opts ...options.ReverseQueryOptionsOption,
+) (datastore.RelationshipIterator, error) {
+	queryOpts := options.NewReverseQueryOptionsWithOptions(opts...)
+
+	// Reverse queries are cached under the resource namespace of their
+	// results, so they are only cacheable when restricted to a single
+	// resource relation.
+	cacheable := false
+	var generation uint64
+	if queryOpts.ResRelation != nil {
+		generation, cacheable = r.p.generationFor(queryOpts.ResRelation.Namespace)
+	}
+
+	if !cacheable || queryOpts.LimitForReverse != nil || queryOpts.AfterForReverse != nil {
+		return r.Reader.ReverseQueryRelationships(ctx, subjectsFilter, opts...)
+	}
+
+	key := cache.StringKey(fmt.Sprintf("r:%d:%s:%s:%s:%s:%d:%t:%t:%d",
+		generation,
+		r.rev,
+		canonicalizedSubjectsSelector(subjectsFilter.AsSelector()),
+		queryOpts.ResRelation.Namespace,
+		queryOpts.ResRelation.Relation,
+		queryOpts.SortForReverse,
+		queryOpts.SkipCaveatsForReverse,
+		queryOpts.SkipExpirationForReverse,
+		queryOpts.ExpirationBehaviorForReverse,
+	))
+	if rels, ok := r.p.relCache.Get(key); ok {
+		return common.NewSliceRelationshipIterator(rels), nil
+	}
+
+	iter, err := r.Reader.ReverseQueryRelationships(ctx, subjectsFilter, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.materializeAndCache(iter, key)
+}
+
+func (r *relationshipCachingReader) materializeAndCache(iter datastore.RelationshipIterator, key cache.StringKey) (datastore.RelationshipIterator, error) {
+	rels, err := datastore.IteratorToSlice(iter)
+	if err != nil {
+		return nil, err
+	}
+
+	cost := int64(0)
+	for _, rel := range rels {
+		cost += estimatedRelationshipSize(rel)
+	}
+	r.p.relCache.Set(key, rels, cost)
+
+	return common.NewSliceRelationshipIterator(rels), nil
+}
+
+// estimatedRelationshipSize returns a rough byte size for a relationship, for
+// use as the cache entry cost.
+func estimatedRelationshipSize(rel tuple.Relationship) int64 {
+	size := int64(len(rel.Resource.ObjectType) + len(rel.Resource.ObjectID) + len(rel.Resource.Relation) +
+		len(rel.Subject.ObjectType) + len(rel.Subject.ObjectID) + len(rel.Subject.Relation))
+	if rel.OptionalCaveat != nil {
+		size += int64(rel.OptionalCaveat.SizeVT())
+	}
+	if rel.OptionalExpiration != nil {
+		size += 24
+	}
+	return size
+}
+
+// canonicalizedRelationshipsFilter returns a stable string form of the filter,
+// with all ID sets sorted, for use as a cache key component.
+func canonicalizedRelationshipsFilter(filter datastore.RelationshipsFilter) string {
+	resourceIDs := slices.Clone(filter.OptionalResourceIds)
+	slices.Sort(resourceIDs)
+
+	selectors := make([]string, 0, len(filter.OptionalSubjectsSelectors))
+	for _, selector := range filter.OptionalSubjectsSelectors {
+		selectors = append(selectors, canonicalizedSubjectsSelector(selector))
+	}
+	slices.Sort(selectors)
+
+	var sb strings.Builder
+	sb.WriteString(filter.OptionalResourceType)
+	sb.WriteRune('|')
+	sb.WriteString(strings.Join(resourceIDs, ","))
+	sb.WriteRune('|')
+	sb.WriteString(filter.OptionalResourceIDPrefix)
+	sb.WriteRune('|')
+	sb.WriteString(filter.OptionalResourceRelation)
+	sb.WriteRune('|')
+	sb.WriteString(strings.Join(selectors, "&"))
+	sb.WriteRune('|')
+	sb.WriteString(strconv.Itoa(int(filter.OptionalCaveatNameFilter.Option)))
+	sb.WriteString(filter.OptionalCaveatNameFilter.CaveatName)
+	sb.WriteRune('|')
+	sb.WriteString(strconv.Itoa(int(filter.OptionalExpirationOption)))
+	return sb.String()
+}
+
+func canonicalizedSubjectsSelector(selector datastore.SubjectsSelector) string {
+	subjectIDs := slices.Clone(selector.OptionalSubjectIds)
+	slices.Sort(subjectIDs)
+
+	return selector.OptionalSubjectType + ";" +
+		strings.Join(subjectIDs, ",") + ";" +
+		selector.RelationFilter.NonEllipsisRelation + ";" +
+		strconv.FormatBool(selector.RelationFilter.IncludeEllipsisRelation) + ";" +
+		strconv.FormatBool(selector.RelationFilter.OnlyNonEllipsisRelations)
+}
+
+var (
+	_ datastore.Datastore = (*relationshipCachingProxy)(nil)
+	_ datastore.Reader    = (*relationshipCachingReader)(nil)
+)
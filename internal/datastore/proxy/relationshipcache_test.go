@@ -0,0 +1,166 @@
+package proxy
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/internal/datastore/memdb"
+	"github.com/authzed/spicedb/internal/testfixtures"
+	"github.com/authzed/spicedb/pkg/cache"
+	"github.com/authzed/spicedb/pkg/datastore"
+	"github.com/authzed/spicedb/pkg/datastore/options"
+	"github.com/authzed/spicedb/pkg/datastore/queryshape"
+	"github.com/authzed/spicedb/pkg/tuple"
+)
+
+// queryCountingDatastore counts the QueryRelationships calls that reach the
+// delegate, allowing tests to prove whether a query was served from cache.
+type queryCountingDatastore struct {
+	datastore.Datastore
+	queryCount atomic.Uint64
+}
+
+func (c *queryCountingDatastore) SnapshotReader(rev datastore.Revision) datastore.Reader {
+	return &queryCountingReader{c.Datastore.SnapshotReader(rev), c}
+}
+
+type queryCountingReader struct {
+	datastore.Reader
+	c *queryCountingDatastore
+}
+
+func (r *queryCountingReader) QueryRelationships(
+	ctx context.Context,
+	filter datastore.RelationshipsFilter,
+	opts ...options.QueryOptionsOption,
+) (datastore.RelationshipIterator, error) {
+	r.c.queryCount.Add(1)
+	return r.Reader.QueryRelationships(ctx, filter, opts...)
+}
+
+func newCachingProxyForTesting(t *testing.T, require *require.Assertions) (datastore.Datastore, *relationshipCachingProxy, *queryCountingDatastore) {
+	memDS, err := memdb.NewMemdbDatastore(16, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	counting := &queryCountingDatastore{Datastore: memDS}
+	ds, err := NewRelationshipCachingProxy(counting, &cache.Config{
+		NumCounters: 1_000,
+		MaxCost:     1 << 20,
+	}, []string{testfixtures.DocumentNS.Name})
+	require.NoError(err)
+	t.Cleanup(func() {
+		require.NoError(ds.Close())
+	})
+
+	return ds, ds.(*relationshipCachingProxy), counting
+}
+
+func queryAllDocuments(ctx context.Context, require *require.Assertions, ds datastore.Datastore, rev datastore.Revision) []tuple.Relationship {
+	iter, err := ds.SnapshotReader(rev).QueryRelationships(ctx, datastore.RelationshipsFilter{
+		OptionalResourceType: testfixtures.DocumentNS.Name,
+	}, options.WithQueryShape(queryshape.FindResourceOfType))
+	require.NoError(err)
+
+	rels, err := datastore.IteratorToSlice(iter)
+	require.NoError(err)
+	return rels
+}
+
+// awaitStableGeneration waits until the watch stream has finished delivering
+// the invalidations for any writes already made, so that cache hit assertions
+// are not raced by pending generation bumps.
+func awaitStableGeneration(require *require.Assertions, proxy *relationshipCachingProxy, namespaceName string) {
+	require.Eventually(func() bool {
+		before, ok := proxy.generationFor(namespaceName)
+		require.True(ok)
+		time.Sleep(50 * time.Millisecond)
+		after, _ := proxy.generationFor(namespaceName)
+		return before == after
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRelationshipCachingProxyCacheHit(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ds, proxy, counting := newCachingProxyForTesting(t, require)
+	ds, rev := testfixtures.StandardDatastoreWithCaveatedData(ds, require)
+	awaitStableGeneration(require, proxy, testfixtures.DocumentNS.Name)
+
+	firstRead := queryAllDocuments(ctx, require, ds, rev)
+	require.NotEmpty(firstRead)
+	countAfterFirst := counting.queryCount.Load()
+
+	// Wait for the cache to apply the pending set, then re-issue the identical
+	// query; it must be served from cache without reaching the delegate, with
+	// caveat context and expiration preserved exactly.
+	proxy.relCache.Wait()
+	secondRead := queryAllDocuments(ctx, require, ds, rev)
+	require.Equal(firstRead, secondRead)
+	require.Equal(countAfterFirst, counting.queryCount.Load(), "expected the second query to be served from cache")
+}
+
+func TestRelationshipCachingProxyUncachedNamespace(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ds, proxy, counting := newCachingProxyForTesting(t, require)
+	ds, rev := testfixtures.StandardDatastoreWithData(ds, require)
+
+	queryFolders := func() {
+		iter, err := ds.SnapshotReader(rev).QueryRelationships(ctx, datastore.RelationshipsFilter{
+			OptionalResourceType: testfixtures.FolderNS.Name,
+		}, options.WithQueryShape(queryshape.FindResourceOfType))
+		require.NoError(err)
+		_, err = datastore.IteratorToSlice(iter)
+		require.NoError(err)
+	}
+
+	// The folder namespace is not opted into caching, so every query must
+	// reach the delegate.
+	queryFolders()
+	proxy.relCache.Wait()
+	countAfterFirst := counting.queryCount.Load()
+	queryFolders()
+	require.Equal(countAfterFirst+1, counting.queryCount.Load())
+}
+
+func TestRelationshipCachingProxyWatchInvalidation(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ds, proxy, counting := newCachingProxyForTesting(t, require)
+	ds, rev := testfixtures.StandardDatastoreWithData(ds, require)
+	awaitStableGeneration(require, proxy, testfixtures.DocumentNS.Name)
+
+	firstRead := queryAllDocuments(ctx, require, ds, rev)
+	require.NotEmpty(firstRead)
+	proxy.relCache.Wait()
+
+	generationBefore, ok := proxy.generationFor(testfixtures.DocumentNS.Name)
+	require.True(ok)
+	countBefore := counting.queryCount.Load()
+
+	// Write to the document namespace; the watch stream must bump the
+	// namespace's generation, causing the same query at the same revision to
+	// miss the cache and reach the delegate again.
+	_, err := ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.WriteRelationships(ctx, []tuple.RelationshipUpdate{
+			tuple.Touch(tuple.MustParse("document:newdoc#viewer@user:newuser")),
+		})
+	})
+	require.NoError(err)
+
+	require.Eventually(func() bool {
+		generation, _ := proxy.generationFor(testfixtures.DocumentNS.Name)
+		return generation > generationBefore
+	}, 5*time.Second, 10*time.Millisecond, "expected the watch stream to invalidate the namespace")
+
+	secondRead := queryAllDocuments(ctx, require, ds, rev)
+	require.Equal(firstRead, secondRead)
+	require.Greater(counting.queryCount.Load(), countBefore, "expected the invalidated query to reach the delegate")
+}
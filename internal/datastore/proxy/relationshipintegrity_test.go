@@ -11,6 +11,7 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/authzed/spicedb/internal/datastore/dsfortesting"
+	"github.com/authzed/spicedb/internal/datastore/memdb"
 	"github.com/authzed/spicedb/pkg/datastore"
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 	"github.com/authzed/spicedb/pkg/tuple"
@@ -75,6 +76,176 @@ func TestWriteWithPredefinedIntegrity(t *testing.T) {
 	})
 }
 
+func TestFullIntegrityLoopOverDatastoreWithRequiredIntegrity(t *testing.T) {
+	ds, err := dsfortesting.NewMemDBDatastoreWithRequiredIntegrityForTesting(0, 5*time.Second, 1*time.Hour)
+	require.NoError(t, err)
+
+	// Writes which bypass the proxy carry no integrity and are rejected outright.
+	_, err = ds.ReadWriteTx(t.Context(), func(ctx context.Context, tx datastore.ReadWriteTransaction) error {
+		return tx.WriteRelationships(t.Context(), []tuple.RelationshipUpdate{
+			tuple.Create(tuple.MustParse("resource:foo#viewer@user:tom")),
+		})
+	})
+	require.Error(t, err)
+	require.ErrorAs(t, err, &memdb.RelationshipIntegrityRequiredError{})
+
+	// Writes through the proxy have integrity computed and attached.
+	pds, err := NewRelationshipIntegrityProxy(ds, DefaultKeyForTesting, nil)
+	require.NoError(t, err)
+
+	_, err = pds.ReadWriteTx(t.Context(), func(ctx context.Context, tx datastore.ReadWriteTransaction) error {
+		return tx.WriteRelationships(t.Context(), []tuple.RelationshipUpdate{
+			tuple.Create(tuple.MustParse("resource:foo#viewer@user:tom")),
+			tuple.Create(tuple.MustParse("resource:foo#viewer@user:fred")),
+			tuple.Touch(tuple.MustParse("resource:bar#viewer@user:sarah")),
+		})
+	})
+	require.NoError(t, err)
+
+	// Read them back through the proxy, which verifies each hash.
+	headRev, err := pds.HeadRevision(t.Context())
+	require.NoError(t, err)
+
+	reader := pds.SnapshotReader(headRev)
+	iter, err := reader.QueryRelationships(
+		t.Context(),
+		datastore.RelationshipsFilter{OptionalResourceType: "resource"},
+	)
+	require.NoError(t, err)
+
+	rels, err := datastore.IteratorToSlice(iter)
+	require.NoError(t, err)
+	require.Len(t, rels, 3)
+	for _, rel := range rels {
+		require.Nil(t, rel.OptionalIntegrity, "proxy should strip integrity after verification")
+	}
+
+	// The underlying datastore holds the computed integrity.
+	iter, err = ds.SnapshotReader(headRev).QueryRelationships(
+		t.Context(),
+		datastore.RelationshipsFilter{OptionalResourceType: "resource"},
+	)
+	require.NoError(t, err)
+
+	rels, err = datastore.IteratorToSlice(iter)
+	require.NoError(t, err)
+	require.Len(t, rels, 3)
+	for _, rel := range rels {
+		require.NotNil(t, rel.OptionalIntegrity)
+		require.Equal(t, DefaultKeyForTesting.ID, rel.OptionalIntegrity.KeyId)
+	}
+}
+
+// sliceBulkSource replays a fixed set of relationships as a bulk import source.
+type sliceBulkSource struct {
+	rels  []tuple.Relationship
+	index int
+}
+
+func (s *sliceBulkSource) Next(_ context.Context) (*tuple.Relationship, error) {
+	if s.index >= len(s.rels) {
+		return nil, nil
+	}
+
+	rel := &s.rels[s.index]
+	s.index++
+	return rel, nil
+}
+
+func TestBulkImportRoundTripPreservesIntegrity(t *testing.T) {
+	sourceDS, err := dsfortesting.NewMemDBDatastoreWithRequiredIntegrityForTesting(0, 5*time.Second, 1*time.Hour)
+	require.NoError(t, err)
+
+	pds, err := NewRelationshipIntegrityProxy(sourceDS, DefaultKeyForTesting, nil)
+	require.NoError(t, err)
+
+	// Write relationships through the proxy, including a caveat context which must
+	// round-trip exactly for the integrity hash to remain valid.
+	_, err = pds.ReadWriteTx(t.Context(), func(ctx context.Context, tx datastore.ReadWriteTransaction) error {
+		return tx.WriteRelationships(t.Context(), []tuple.RelationshipUpdate{
+			tuple.Create(tuple.MustParse("resource:foo#viewer@user:tom")),
+			tuple.Create(tuple.MustParse(`resource:foo#viewer@user:fred[somecaveat:{"value": 42, "nested": {"items": [1, 2.5, "three"]}}]`)),
+			tuple.Create(tuple.MustParse("resource:bar#viewer@user:sarah[expiration:2500-01-01T00:00:00Z]")),
+		})
+	})
+	require.NoError(t, err)
+
+	// Export from the underlying datastore so the integrity blocks are included.
+	sourceRev, err := sourceDS.HeadRevision(t.Context())
+	require.NoError(t, err)
+
+	iter, err := sourceDS.SnapshotReader(sourceRev).QueryRelationships(
+		t.Context(),
+		datastore.RelationshipsFilter{OptionalResourceType: "resource"},
+	)
+	require.NoError(t, err)
+
+	exported, err := datastore.IteratorToSlice(iter)
+	require.NoError(t, err)
+	require.Len(t, exported, 3)
+
+	// Import into a second in-memory datastore which also requires integrity.
+	targetDS, err := dsfortesting.NewMemDBDatastoreWithRequiredIntegrityForTesting(0, 5*time.Second, 1*time.Hour)
+	require.NoError(t, err)
+
+	_, err = targetDS.ReadWriteTx(t.Context(), func(ctx context.Context, tx datastore.ReadWriteTransaction) error {
+		loaded, err := tx.BulkLoad(ctx, &sliceBulkSource{rels: exported})
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), loaded)
+		return err
+	})
+	require.NoError(t, err)
+
+	// Compare the imported relationships byte-for-byte against the export.
+	targetRev, err := targetDS.HeadRevision(t.Context())
+	require.NoError(t, err)
+
+	iter, err = targetDS.SnapshotReader(targetRev).QueryRelationships(
+		t.Context(),
+		datastore.RelationshipsFilter{OptionalResourceType: "resource"},
+	)
+	require.NoError(t, err)
+
+	imported, err := datastore.IteratorToSlice(iter)
+	require.NoError(t, err)
+	require.Len(t, imported, len(exported))
+
+	canonicalized := func(rels []tuple.Relationship) map[string]*core.RelationshipIntegrity {
+		byKey := make(map[string]*core.RelationshipIntegrity, len(rels))
+		for _, rel := range rels {
+			canonical, err := tuple.CanonicalBytes(rel)
+			require.NoError(t, err)
+			byKey[string(canonical)] = rel.OptionalIntegrity
+		}
+		return byKey
+	}
+
+	exportedByKey := canonicalized(exported)
+	for canonical, integrity := range canonicalized(imported) {
+		expectedIntegrity, ok := exportedByKey[canonical]
+		require.True(t, ok, "imported relationship not found in export: %s", canonical)
+		require.NotNil(t, integrity)
+		require.Equal(t, expectedIntegrity.KeyId, integrity.KeyId)
+		require.Equal(t, expectedIntegrity.Hash, integrity.Hash)
+		require.Equal(t, expectedIntegrity.HashedAt.AsTime(), integrity.HashedAt.AsTime())
+	}
+
+	// A proxy read over the target verifies every imported hash, proving the
+	// hashed bytes survived the round trip unchanged.
+	tpds, err := NewRelationshipIntegrityProxy(targetDS, DefaultKeyForTesting, nil)
+	require.NoError(t, err)
+
+	iter, err = tpds.SnapshotReader(targetRev).QueryRelationships(
+		t.Context(),
+		datastore.RelationshipsFilter{OptionalResourceType: "resource"},
+	)
+	require.NoError(t, err)
+
+	verified, err := datastore.IteratorToSlice(iter)
+	require.NoError(t, err)
+	require.Len(t, verified, len(exported))
+}
+
 func TestReadWithMissingIntegrity(t *testing.T) {
 	ds, err := dsfortesting.NewMemDBDatastoreForTesting(0, 5*time.Second, 1*time.Hour)
 	require.NoError(t, err)
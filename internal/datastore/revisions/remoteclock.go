@@ -112,7 +112,8 @@ func (rcr *RemoteClockRevisions) CheckRevision(ctx context.Context, dsRevision d
 	isStale := revisionNanos < (nowNanos - rcr.gcWindowNanos)
 	if isStale {
 		log.Ctx(ctx).Debug().Stringer("now", now).Stringer("revision", revision).Msg("stale revision")
-		return datastore.NewInvalidRevisionErr(revision, datastore.RevisionStale)
+		oldestValid := nowTS.ConstructForTimestamp(nowNanos - rcr.gcWindowNanos)
+		return datastore.NewStaleRevisionErr(revision, oldestValid)
 	}
 
 	isUnknown := revisionNanos > nowNanos
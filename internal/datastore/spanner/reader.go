@@ -177,6 +177,7 @@ func (sr spannerReader) ReverseQueryRelationships(
 		options.WithSort(queryOpts.SortForReverse),
 		options.WithSkipCaveats(queryOpts.SkipCaveatsForReverse),
 		options.WithSkipExpiration(queryOpts.SkipExpirationForReverse),
+		options.WithExpirationBehavior(queryOpts.ExpirationBehaviorForReverse),
 		options.WithQueryShape(queryOpts.QueryShapeForReverse),
 		options.WithSQLExplainCallbackForTest(queryOpts.SQLExplainCallbackForTestForReverse),
 	)
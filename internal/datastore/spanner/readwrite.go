@@ -25,10 +25,39 @@ import (
 type spannerReadWriteTXN struct {
 	spannerReader
 	spannerRWT *spanner.ReadWriteTransaction
+
+	// client is used by BulkLoad to split oversized loads across additional
+	// commits outside of the wrapping transaction.
+	client *spanner.Client
 }
 
 const inLimit = 10_000 // https://cloud.google.com/spanner/quotas#query-limits
 
+const (
+	// maxMutationsPerCommit is Spanner's hard limit on the number of mutations
+	// in a single commit, where each modified column and each affected secondary
+	// index entry counts as one mutation.
+	// See https://cloud.google.com/spanner/quotas#limits-for
+	maxMutationsPerCommit = 80_000
+
+	// relationshipIndexCount is the number of secondary indexes over the
+	// relationship table, each of which contributes one mutation for every
+	// written or deleted row.
+	relationshipIndexCount = 2
+)
+
+// relationshipMutationCount estimates the number of Spanner mutations incurred
+// by applying the given operation to a single relationship, including the
+// entries in the relationship table's secondary indexes.
+func relationshipMutationCount(operation tuple.UpdateOperation) uint64 {
+	if operation == tuple.UpdateOperationDelete {
+		// Deletes count once per row, rather than once per column.
+		return 1 + relationshipIndexCount
+	}
+
+	return uint64(len(allRelationshipCols)) + relationshipIndexCount
+}
+
 func (rwt spannerReadWriteTXN) RegisterCounter(ctx context.Context, name string, filter *core.RelationshipFilter) error {
 	// Ensure the counter doesn't already exist.
 	counters, err := rwt.lookupCounters(ctx, name)
@@ -108,6 +137,17 @@ func (rwt spannerReadWriteTXN) StoreCounterValue(ctx context.Context, name strin
 }
 
 func (rwt spannerReadWriteTXN) WriteRelationships(ctx context.Context, mutations []tuple.RelationshipUpdate) error {
+	// Reject writes that would exceed Spanner's per-commit mutation limit before
+	// buffering anything: the write is transactional, so it cannot be split
+	// across multiple commits.
+	var estimatedMutationCount uint64
+	for _, mutation := range mutations {
+		estimatedMutationCount += relationshipMutationCount(mutation.Operation)
+	}
+	if estimatedMutationCount > maxMutationsPerCommit {
+		return datastore.NewWriteTooLargeErr(estimatedMutationCount, maxMutationsPerCommit)
+	}
+
 	var rowCountChange int64
 	for _, mutation := range mutations {
 		txnMut, countChange, err := spannerMutation(ctx, mutation.Operation, mutation.Relationship)
@@ -408,25 +448,49 @@ func (rwt spannerReadWriteTXN) DeleteNamespaces(ctx context.Context, nsNames ...
 }
 
 func (rwt spannerReadWriteTXN) BulkLoad(ctx context.Context, iter datastore.BulkWriteRelationshipSource) (uint64, error) {
+	mutationCountPerRelationship := relationshipMutationCount(tuple.UpdateOperationCreate)
+
 	var numLoaded uint64
+	var estimatedMutationCount uint64
+	var staged []*spanner.Mutation
 	var rel *tuple.Relationship
 	var err error
 	for rel, err = iter.Next(ctx); err == nil && rel != nil; rel, err = iter.Next(ctx) {
+		if rel.OptionalIntegrity != nil {
+			return 0, datastore.NewRelationshipIntegrityNotSupportedErr(tuple.StringWithoutCaveatOrExpiration(*rel))
+		}
+
+		// If buffering the next relationship would push the commit over Spanner's
+		// mutation limit, apply the staged mutations in their own commit. Bulk
+		// loading does not require the entire load to be atomic, so oversized
+		// loads are split across as many commits as necessary.
+		if estimatedMutationCount+mutationCountPerRelationship > maxMutationsPerCommit {
+			if _, err := rwt.client.Apply(ctx, staged); err != nil {
+				return 0, fmt.Errorf(errUnableToBulkLoadRelationships, err)
+			}
+			staged = staged[:0]
+			estimatedMutationCount = 0
+		}
+
 		txnMut, _, err := spannerMutation(ctx, tuple.UpdateOperationCreate, *rel)
 		if err != nil {
 			return 0, fmt.Errorf(errUnableToBulkLoadRelationships, err)
 		}
 		numLoaded++
-
-		if err := rwt.spannerRWT.BufferWrite([]*spanner.Mutation{txnMut}); err != nil {
-			return 0, fmt.Errorf(errUnableToBulkLoadRelationships, err)
-		}
+		estimatedMutationCount += mutationCountPerRelationship
+		staged = append(staged, txnMut)
 	}
 
 	if err != nil {
 		return 0, fmt.Errorf(errUnableToBulkLoadRelationships, err)
 	}
 
+	// Buffer the remaining mutations into the wrapping transaction, keeping
+	// loads that fit within a single commit fully atomic.
+	if err := rwt.spannerRWT.BufferWrite(staged); err != nil {
+		return 0, fmt.Errorf(errUnableToBulkLoadRelationships, err)
+	}
+
 	return numLoaded, nil
 }
 
@@ -39,6 +39,7 @@ import (
 
 func init() {
 	datastore.Engines = append(datastore.Engines, Engine)
+	prometheus.MustRegister(mutationCountHistogram)
 }
 
 const (
@@ -79,6 +80,14 @@ var (
 	tracer = otel.Tracer("spicedb/internal/datastore/spanner")
 
 	alreadyExistsRegex = regexp.MustCompile(`^Table relation_tuple: Row {String\("([^\"]+)"\), String\("([^\"]+)"\), String\("([^\"]+)"\), String\("([^\"]+)"\), String\("([^\"]+)"\), String\("([^\"]+)"\)} already exists.$`)
+
+	mutationCountHistogram = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "spicedb",
+		Subsystem: "datastore",
+		Name:      "spanner_commit_mutation_count",
+		Help:      "mutation count distribution for spanner commits",
+		Buckets:   []float64{1, 10, 100, 1_000, 10_000, 40_000, float64(maxMutationsPerCommit)},
+	})
 )
 
 type spannerDatastore struct {
@@ -349,6 +358,7 @@ func (sd *spannerDatastore) ReadWriteTx(ctx context.Context, fn datastore.TxUser
 		rwt := spannerReadWriteTXN{
 			spannerReader{executor, txSource, sd.filterMaximumIDCount, sd.schema},
 			spannerRWT,
+			sd.client,
 		}
 		err := func() error {
 			innerCtx, innerSpan := tracer.Start(ctx, "TxUserFunc")
@@ -364,14 +374,27 @@ func (sd *spannerDatastore) ReadWriteTx(ctx context.Context, fn datastore.TxUser
 		}
 
 		return nil
-	}, spanner.TransactionOptions{TransactionTag: transactionTag})
+	}, spanner.TransactionOptions{
+		TransactionTag: transactionTag,
+		CommitOptions:  spanner.CommitOptions{ReturnCommitStats: true},
+	})
 	if err != nil {
 		if cerr := convertToWriteConstraintError(err); cerr != nil {
 			return datastore.NoRevision, cerr
 		}
+		// The Spanner client retries aborted transactions internally; an abort
+		// surfaced here means the transaction ultimately could not commit, so
+		// report it as a serialization failure for the services layer to map.
+		if spanner.ErrCode(err) == codes.Aborted {
+			return datastore.NoRevision, common.NewSerializationError(err)
+		}
 		return datastore.NoRevision, err
 	}
 
+	if rs.CommitStats != nil {
+		mutationCountHistogram.Observe(float64(rs.CommitStats.MutationCount))
+	}
+
 	return revisions.NewForTime(rs.CommitTs), nil
 }
 
@@ -5,6 +5,7 @@ package spanner
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/authzed/spicedb/internal/testfixtures"
 	testdatastore "github.com/authzed/spicedb/internal/testserver/datastore"
 	"github.com/authzed/spicedb/pkg/datastore"
 	"github.com/authzed/spicedb/pkg/datastore/test"
@@ -50,6 +52,11 @@ func TestSpannerDatastore(t *testing.T) {
 		b,
 		FakeStatsTest,
 	))
+
+	t.Run("TestMutationLimits", createDatastoreTest(
+		b,
+		MutationLimitsTest,
+	))
 }
 
 type datastoreTestFunc func(t *testing.T, ds datastore.Datastore)
@@ -146,3 +153,65 @@ func FakeStatsTest(t *testing.T, ds datastore.Datastore) {
 	require.NoError(t, err)
 	require.Equal(t, uint64(3), stats.EstimatedRelationshipCount)
 }
+
+func MutationLimitsTest(t *testing.T, ds datastore.Datastore) {
+	ctx := context.Background()
+
+	mutationsPerRelationship := relationshipMutationCount(tuple.UpdateOperationCreate)
+	atLimitCount := maxMutationsPerCommit / mutationsPerRelationship
+
+	buildUpdates := func(count uint64) []tuple.RelationshipUpdate {
+		updates := make([]tuple.RelationshipUpdate, 0, count)
+		for i := uint64(0); i < count; i++ {
+			updates = append(updates, tuple.Create(tuple.MustParse(fmt.Sprintf("document:doc-%d#viewer@user:tom", i))))
+		}
+		return updates
+	}
+
+	// A write whose estimate lands exactly at the limit must succeed.
+	_, err := ds.ReadWriteTx(ctx, func(ctx context.Context, tx datastore.ReadWriteTransaction) error {
+		return tx.WriteRelationships(ctx, buildUpdates(atLimitCount))
+	})
+	require.NoError(t, err)
+
+	// One relationship more crosses the limit and must be rejected with the
+	// typed error carrying the computed mutation count.
+	_, err = ds.ReadWriteTx(ctx, func(ctx context.Context, tx datastore.ReadWriteTransaction) error {
+		return tx.WriteRelationships(ctx, buildUpdates(atLimitCount+1))
+	})
+	require.Error(t, err)
+
+	var tooLarge datastore.WriteTooLargeError
+	require.ErrorAs(t, err, &tooLarge)
+	require.Equal(t, (atLimitCount+1)*mutationsPerRelationship, tooLarge.EstimatedMutationCount())
+
+	// Bulk loads are not transactional, so an oversized load is split across
+	// multiple commits instead of being rejected.
+	bulkCount := atLimitCount + 10
+	var loaded uint64
+	_, err = ds.ReadWriteTx(ctx, func(ctx context.Context, tx datastore.ReadWriteTransaction) error {
+		var lerr error
+		loaded, lerr = tx.BulkLoad(ctx, testfixtures.NewBulkRelationshipGenerator(
+			"bulkdoc",
+			"viewer",
+			"user",
+			int(bulkCount),
+			t,
+		))
+		return lerr
+	})
+	require.NoError(t, err)
+	require.Equal(t, bulkCount, loaded)
+
+	headRev, err := ds.HeadRevision(ctx)
+	require.NoError(t, err)
+
+	it, err := ds.SnapshotReader(headRev).QueryRelationships(ctx, datastore.RelationshipsFilter{
+		OptionalResourceType: "bulkdoc",
+	})
+	require.NoError(t, err)
+
+	found, err := datastore.IteratorToSlice(it)
+	require.NoError(t, err)
+	require.Len(t, found, int(bulkCount))
+}
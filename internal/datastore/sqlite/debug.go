@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"strings"
+
+	"github.com/authzed/spicedb/pkg/datastore"
+	"github.com/authzed/spicedb/pkg/genutil/mapz"
+)
+
+// See: https://www.sqlite.org/eqp.html
+func (sds *Datastore) PreExplainStatements() []string {
+	return nil
+}
+
+func (sds *Datastore) BuildExplainQuery(sql string, args []any) (string, []any, error) {
+	return "EXPLAIN QUERY PLAN " + sql, args, nil
+}
+
+func (sds *Datastore) ParseExplain(explain string) (datastore.ParsedExplain, error) {
+	// Extract the index name(s) used. The query plan reports index scans as
+	// `SEARCH <table> USING [COVERING] INDEX <name> (...)`.
+	indexesUsed := mapz.NewSet[string]()
+	for _, line := range strings.Split(explain, "\n") {
+		_, after, ok := strings.Cut(line, " INDEX ")
+		if !ok {
+			continue
+		}
+
+		indexName, _, _ := strings.Cut(strings.TrimSpace(after), " ")
+		if indexName != "" {
+			indexesUsed.Add(indexName)
+		}
+	}
+
+	return datastore.ParsedExplain{
+		IndexesUsed: indexesUsed.AsSlice(),
+	}, nil
+}
+
+var _ datastore.SQLDatastore = &Datastore{}
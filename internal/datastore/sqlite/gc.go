@@ -0,0 +1,187 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/ccoveille/go-safecast"
+
+	"github.com/authzed/spicedb/internal/datastore/common"
+	"github.com/authzed/spicedb/internal/datastore/revisions"
+	log "github.com/authzed/spicedb/internal/logging"
+	"github.com/authzed/spicedb/pkg/datastore"
+	"github.com/authzed/spicedb/pkg/spiceerrors"
+)
+
+var (
+	_ common.GarbageCollectableDatastore = (*Datastore)(nil)
+	_ common.ManualGCDatastore           = (*Datastore)(nil)
+	_ common.GarbageCollector            = (*sqliteGarbageCollector)(nil)
+)
+
+type sqliteGarbageCollector struct {
+	sds      *Datastore
+	isClosed bool
+}
+
+func (sds *Datastore) BuildGarbageCollector(ctx context.Context) (common.GarbageCollector, error) {
+	return &sqliteGarbageCollector{sds: sds, isClosed: false}, nil
+}
+
+func (sds *Datastore) HasGCRun() bool {
+	return sds.gcHasRun.Load()
+}
+
+func (sds *Datastore) MarkGCCompleted() {
+	sds.gcHasRun.Store(true)
+}
+
+func (sds *Datastore) ResetGCCompleted() {
+	sds.gcHasRun.Store(false)
+}
+
+// RunGC runs a single garbage collection pass using the datastore's configured
+// window and timeout, returning the statistics of the pass.
+func (sds *Datastore) RunGC(_ context.Context) (common.GCStats, error) {
+	return common.RunGarbageCollectionWithStats(sds, sds.gcWindow, sds.gcTimeout)
+}
+
+func (sgc *sqliteGarbageCollector) Close() {
+	sgc.isClosed = true
+}
+
+// LockForGCRun takes an in-process lock; since SQLite is embedded, no other
+// process can be garbage collecting the same database file.
+func (sgc *sqliteGarbageCollector) LockForGCRun(ctx context.Context) (bool, error) {
+	return sgc.sds.gcRunning.CompareAndSwap(false, true), nil
+}
+
+func (sgc *sqliteGarbageCollector) UnlockAfterGCRun() error {
+	sgc.sds.gcRunning.Store(false)
+	return nil
+}
+
+func (sgc *sqliteGarbageCollector) Now(ctx context.Context) (time.Time, error) {
+	if sgc.isClosed {
+		return time.Time{}, spiceerrors.MustBugf("sqliteGarbageCollector is closed")
+	}
+
+	// SQLite is embedded, so the process clock is the database clock.
+	return time.Now().UTC(), nil
+}
+
+func (sgc *sqliteGarbageCollector) TxIDBefore(ctx context.Context, before time.Time) (datastore.Revision, error) {
+	if sgc.isClosed {
+		return datastore.NoRevision, spiceerrors.MustBugf("sqliteGarbageCollector is closed")
+	}
+
+	// Find the highest transaction ID before the GC window.
+	query, args, err := sgc.sds.GetLastRevision.Where(sq.Lt{colTimestamp: before.UnixNano()}).ToSql()
+	if err != nil {
+		return datastore.NoRevision, err
+	}
+
+	var value sql.NullInt64
+	err = sgc.sds.db.QueryRowContext(ctx, query, args...).Scan(&value)
+	if err != nil {
+		return datastore.NoRevision, err
+	}
+
+	if !value.Valid {
+		log.Ctx(ctx).Debug().Time("before", before).Msg("no stale transactions found in the datastore")
+		return datastore.NoRevision, nil
+	}
+
+	uintValue, err := safecast.ToUint64(value.Int64)
+	if err != nil {
+		return datastore.NoRevision, spiceerrors.MustBugf("value could not be cast to uint64: %v", err)
+	}
+
+	return revisions.NewForTransactionID(uintValue), nil
+}
+
+func (sgc *sqliteGarbageCollector) DeleteBeforeTx(
+	ctx context.Context,
+	txID datastore.Revision,
+) (removed common.DeletionCounts, err error) {
+	if sgc.isClosed {
+		return removed, spiceerrors.MustBugf("sqliteGarbageCollector is closed")
+	}
+
+	// Delete any relationship rows with deleted_transaction <= the transaction ID.
+	removed.Relationships, err = sgc.batchDelete(ctx, tableTuple, sq.LtOrEq{colDeletedTxn: txID})
+	if err != nil {
+		return removed, err
+	}
+
+	// Delete all transaction rows with ID < the transaction ID.
+	//
+	// We don't delete the transaction itself to ensure there is always at least
+	// one transaction present.
+	removed.Transactions, err = sgc.batchDelete(ctx, tableTransaction, sq.Lt{colID: txID})
+	if err != nil {
+		return removed, err
+	}
+
+	// Delete any namespace rows with deleted_transaction <= the transaction ID.
+	removed.Namespaces, err = sgc.batchDelete(ctx, tableNamespace, sq.LtOrEq{colDeletedTxn: txID})
+	return removed, err
+}
+
+func (sgc *sqliteGarbageCollector) DeleteExpiredRels(ctx context.Context) (int64, error) {
+	if sgc.sds.schema.ExpirationDisabled {
+		return 0, nil
+	}
+
+	now, err := sgc.Now(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return sgc.batchDelete(
+		ctx,
+		tableTuple,
+		sq.Lt{colExpiration: now.Add(-1 * sgc.sds.gcWindow)},
+	)
+}
+
+// batchDelete deletes in chunks to bound the duration for which the write lock
+// is held. SQLite is not compiled with DELETE ... LIMIT support, so the limit
+// is applied through a rowid subquery.
+func (sgc *sqliteGarbageCollector) batchDelete(ctx context.Context, tableName string, filter sqlFilter) (int64, error) {
+	if sgc.isClosed {
+		return -1, spiceerrors.MustBugf("sqliteGarbageCollector is closed")
+	}
+
+	innerSQL, innerArgs, err := sb.Select("rowid").From(tableName).Where(filter).Limit(batchDeleteSize).ToSql()
+	if err != nil {
+		return -1, err
+	}
+
+	query, args, err := sb.Delete(tableName).Where(fmt.Sprintf("rowid IN (%s)", innerSQL), innerArgs...).ToSql()
+	if err != nil {
+		return -1, err
+	}
+
+	var deletedCount int64
+	for {
+		cr, err := sgc.sds.db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return deletedCount, err
+		}
+
+		rowsDeleted, err := cr.RowsAffected()
+		if err != nil {
+			return deletedCount, err
+		}
+		deletedCount += rowsDeleted
+		if rowsDeleted < batchDeleteSize {
+			break
+		}
+	}
+
+	return deletedCount, nil
+}
@@ -0,0 +1,250 @@
+package sqlite
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/authzed/spicedb/internal/datastore/common"
+	log "github.com/authzed/spicedb/internal/logging"
+)
+
+const (
+	errQuantizationTooLarge = "revision quantization interval (%s) must be less than GC window (%s)"
+
+	defaultGarbageCollectionWindow           = 24 * time.Hour
+	defaultGarbageCollectionInterval         = time.Minute * 3
+	defaultGarbageCollectionMaxOperationTime = time.Minute
+	defaultMaxOpenConns                      = 20
+	defaultConnMaxIdleTime                   = 30 * time.Minute
+	defaultConnMaxLifetime                   = 30 * time.Minute
+	defaultWatchBufferLength                 = 128
+	defaultWatchBufferWriteTimeout           = 1 * time.Second
+	defaultQuantization                      = 5 * time.Second
+	defaultMaxRevisionStalenessPercent       = 0.1
+	defaultMaxRetries                        = 8
+	defaultGCEnabled                         = true
+	defaultFilterMaximumIDCount              = 100
+	defaultColumnOptimizationOption          = common.ColumnOptimizationOptionStaticValues
+	defaultWatchDisabled                     = false
+
+	// defaultBusyTimeout bounds how long a writer waits on SQLite's single
+	// write lock before receiving SQLITE_BUSY and being retried client-side.
+	defaultBusyTimeout = 1 * time.Second
+)
+
+type sqliteOptions struct {
+	revisionQuantization        time.Duration
+	gcWindow                    time.Duration
+	gcInterval                  time.Duration
+	gcMaxOperationTime          time.Duration
+	maxRevisionStalenessPercent float64
+	watchBufferLength           uint16
+	watchBufferWriteTimeout     time.Duration
+	maxOpenConns                int
+	connMaxIdleTime             time.Duration
+	connMaxLifetime             time.Duration
+	maxRetries                  uint8
+	busyTimeout                 time.Duration
+	gcEnabled                   bool
+	filterMaximumIDCount        uint16
+	columnOptimizationOption    common.ColumnOptimizationOption
+	watchDisabled               bool
+}
+
+// Option provides the facility to configure how clients within the
+// SQLite datastore interact with the database file.
+type Option func(*sqliteOptions)
+
+func generateConfig(options []Option) (sqliteOptions, error) {
+	computed := sqliteOptions{
+		gcWindow:                    defaultGarbageCollectionWindow,
+		gcInterval:                  defaultGarbageCollectionInterval,
+		gcMaxOperationTime:          defaultGarbageCollectionMaxOperationTime,
+		watchBufferLength:           defaultWatchBufferLength,
+		watchBufferWriteTimeout:     defaultWatchBufferWriteTimeout,
+		maxOpenConns:                defaultMaxOpenConns,
+		connMaxIdleTime:             defaultConnMaxIdleTime,
+		connMaxLifetime:             defaultConnMaxLifetime,
+		revisionQuantization:        defaultQuantization,
+		maxRevisionStalenessPercent: defaultMaxRevisionStalenessPercent,
+		maxRetries:                  defaultMaxRetries,
+		busyTimeout:                 defaultBusyTimeout,
+		gcEnabled:                   defaultGCEnabled,
+		filterMaximumIDCount:        defaultFilterMaximumIDCount,
+		columnOptimizationOption:    defaultColumnOptimizationOption,
+		watchDisabled:               defaultWatchDisabled,
+	}
+
+	for _, option := range options {
+		option(&computed)
+	}
+
+	// Run any checks on the config that need to be done
+	if computed.revisionQuantization >= computed.gcWindow {
+		return computed, fmt.Errorf(
+			errQuantizationTooLarge,
+			computed.revisionQuantization,
+			computed.gcWindow,
+		)
+	}
+
+	if computed.filterMaximumIDCount == 0 {
+		computed.filterMaximumIDCount = 100
+		log.Warn().Msg("filterMaximumIDCount not set, defaulting to 100")
+	}
+
+	return computed, nil
+}
+
+// WatchBufferLength is the number of entries that can be stored in the watch
+// buffer while awaiting read by the client.
+//
+// This value defaults to 128.
+func WatchBufferLength(watchBufferLength uint16) Option {
+	return func(so *sqliteOptions) {
+		so.watchBufferLength = watchBufferLength
+	}
+}
+
+// WatchBufferWriteTimeout is the maximum timeout for writing to the watch buffer,
+// after which the caller to the watch will be disconnected.
+func WatchBufferWriteTimeout(watchBufferWriteTimeout time.Duration) Option {
+	return func(so *sqliteOptions) { so.watchBufferWriteTimeout = watchBufferWriteTimeout }
+}
+
+// RevisionQuantization is the time bucket size to which advertised
+// revisions will be rounded.
+//
+// This value defaults to 5 seconds.
+func RevisionQuantization(quantization time.Duration) Option {
+	return func(so *sqliteOptions) {
+		so.revisionQuantization = quantization
+	}
+}
+
+// MaxRevisionStalenessPercent is the amount of time, expressed as a percentage of
+// the revision quantization window, that a previously computed rounded revision
+// can still be advertised after the next rounded revision would otherwise be ready.
+//
+// This value defaults to 0.1 (10%).
+func MaxRevisionStalenessPercent(stalenessPercent float64) Option {
+	return func(so *sqliteOptions) {
+		so.maxRevisionStalenessPercent = stalenessPercent
+	}
+}
+
+// GCWindow is the maximum age of a passed revision that will be considered
+// valid.
+//
+// This value defaults to 24 hours.
+func GCWindow(window time.Duration) Option {
+	return func(so *sqliteOptions) {
+		so.gcWindow = window
+	}
+}
+
+// GCInterval is the interval at which garbage collection will occur.
+//
+// This value defaults to 3 minutes.
+func GCInterval(interval time.Duration) Option {
+	return func(so *sqliteOptions) {
+		so.gcInterval = interval
+	}
+}
+
+// MaxRetries is the maximum number of times a retriable transaction will be
+// client-side retried.
+//
+// This value defaults to 8.
+func MaxRetries(maxRetries uint8) Option {
+	return func(so *sqliteOptions) {
+		so.maxRetries = maxRetries
+	}
+}
+
+// BusyTimeout is the maximum amount of time a connection waits for SQLite's
+// write lock before the statement fails with SQLITE_BUSY and is retried
+// client-side.
+//
+// This value defaults to 1 second.
+func BusyTimeout(busyTimeout time.Duration) Option {
+	return func(so *sqliteOptions) {
+		so.busyTimeout = busyTimeout
+	}
+}
+
+// ConnMaxIdleTime is the duration after which an idle connection will be
+// automatically closed.
+// See https://pkg.go.dev/database/sql#DB.SetConnMaxIdleTime/
+//
+// This value defaults to 30 minutes.
+func ConnMaxIdleTime(idle time.Duration) Option {
+	return func(so *sqliteOptions) {
+		so.connMaxIdleTime = idle
+	}
+}
+
+// ConnMaxLifetime is the duration since creation after which a connection will
+// be automatically closed.
+// See https://pkg.go.dev/database/sql#DB.SetConnMaxLifetime
+//
+// This value defaults to 30 minutes.
+func ConnMaxLifetime(lifetime time.Duration) Option {
+	return func(so *sqliteOptions) {
+		so.connMaxLifetime = lifetime
+	}
+}
+
+// MaxOpenConns is the maximum size of the connection pool. Only a single
+// connection can be writing at any given time, but multiple readers are
+// supported by WAL journaling.
+// See https://pkg.go.dev/database/sql#DB.SetMaxOpenConns
+//
+// This value defaults to 20.
+func MaxOpenConns(conns int) Option {
+	return func(so *sqliteOptions) {
+		so.maxOpenConns = conns
+	}
+}
+
+// GCEnabled indicates whether garbage collection is enabled.
+//
+// GC is enabled by default.
+func GCEnabled(isGCEnabled bool) Option {
+	return func(so *sqliteOptions) {
+		so.gcEnabled = isGCEnabled
+	}
+}
+
+// GCMaxOperationTime is the maximum operation time of a garbage collection
+// pass before it times out.
+//
+// This value defaults to 1 minute.
+func GCMaxOperationTime(time time.Duration) Option {
+	return func(so *sqliteOptions) {
+		so.gcMaxOperationTime = time
+	}
+}
+
+// FilterMaximumIDCount is the maximum number of IDs that can be used to filter IDs in queries
+func FilterMaximumIDCount(filterMaximumIDCount uint16) Option {
+	return func(so *sqliteOptions) { so.filterMaximumIDCount = filterMaximumIDCount }
+}
+
+// WithColumnOptimization configures the column optimization strategy for the SQLite datastore.
+func WithColumnOptimization(isEnabled bool) Option {
+	return func(so *sqliteOptions) {
+		if isEnabled {
+			so.columnOptimizationOption = common.ColumnOptimizationOptionStaticValues
+		} else {
+			so.columnOptimizationOption = common.ColumnOptimizationOptionNone
+		}
+	}
+}
+
+// WithWatchDisabled disables the watch functionality in the SQLite datastore.
+func WithWatchDisabled(isDisabled bool) Option {
+	return func(so *sqliteOptions) {
+		so.watchDisabled = isDisabled
+	}
+}
@@ -0,0 +1,123 @@
+package sqlite
+
+import (
+	sq "github.com/Masterminds/squirrel"
+)
+
+// QueryBuilder captures all parameterizable queries used
+// by the SQLite datastore implementation.
+type QueryBuilder struct {
+	GetLastRevision   sq.SelectBuilder
+	LoadRevisionRange sq.SelectBuilder
+
+	WriteNamespaceQuery               sq.InsertBuilder
+	ReadNamespaceQuery                sq.SelectBuilder
+	DeleteNamespaceQuery              sq.UpdateBuilder
+	DeleteNamespaceRelationshipsQuery sq.UpdateBuilder
+
+	ReadCounterQuery   sq.SelectBuilder
+	InsertCounterQuery sq.InsertBuilder
+	DeleteCounterQuery sq.UpdateBuilder
+	UpdateCounterQuery sq.UpdateBuilder
+
+	QueryRelsWithIdsQuery sq.SelectBuilder
+	DeleteRelsQuery       sq.UpdateBuilder
+	WriteRelsQuery        sq.InsertBuilder
+	QueryChangedQuery     sq.SelectBuilder
+	CountRelsQuery        sq.SelectBuilder
+
+	WriteCaveatQuery  sq.InsertBuilder
+	ReadCaveatQuery   sq.SelectBuilder
+	ListCaveatsQuery  sq.SelectBuilder
+	DeleteCaveatQuery sq.UpdateBuilder
+}
+
+// NewQueryBuilder returns a new QueryBuilder instance.
+func NewQueryBuilder() *QueryBuilder {
+	builder := QueryBuilder{}
+
+	// transaction builders
+	builder.GetLastRevision = sb.Select("MAX(id)").From(tableTransaction).Limit(1)
+	builder.LoadRevisionRange = sb.Select(colID, colMetadata).From(tableTransaction)
+
+	// namespace builders
+	builder.WriteNamespaceQuery = sb.Insert(tableNamespace).Columns(
+		colNamespace,
+		colConfig,
+		colCreatedTxn,
+	)
+	builder.ReadNamespaceQuery = sb.Select(colConfig, colCreatedTxn).From(tableNamespace)
+	builder.DeleteNamespaceQuery = sb.Update(tableNamespace).Where(sq.Eq{colDeletedTxn: liveDeletedTxnID})
+
+	// counters builders
+	builder.ReadCounterQuery = sb.Select(
+		colCounterName,
+		colCounterSerializedFilter,
+		colCounterCurrentCount,
+		colCounterUpdatedAtRevision,
+	).From(tableRelationshipCounters)
+	builder.InsertCounterQuery = sb.Insert(tableRelationshipCounters).Columns(
+		colCounterName,
+		colCounterSerializedFilter,
+		colCounterCurrentCount,
+		colCounterUpdatedAtRevision,
+		colCreatedTxn,
+	)
+	builder.DeleteCounterQuery = sb.Update(tableRelationshipCounters).Where(sq.Eq{colDeletedTxn: liveDeletedTxnID})
+	builder.UpdateCounterQuery = sb.Update(tableRelationshipCounters).Where(sq.Eq{colDeletedTxn: liveDeletedTxnID})
+
+	// tuple builders
+	builder.QueryRelsWithIdsQuery = sb.Select(
+		colID,
+		colNamespace,
+		colObjectID,
+		colRelation,
+		colUsersetNamespace,
+		colUsersetObjectID,
+		colUsersetRelation,
+		colCaveatName,
+		colCaveatContext,
+		colExpiration,
+		colDeletedTxn,
+	).From(tableTuple)
+	builder.DeleteNamespaceRelationshipsQuery = sb.Update(tableTuple).Where(sq.Eq{colDeletedTxn: liveDeletedTxnID})
+	builder.DeleteRelsQuery = sb.Update(tableTuple).Where(sq.Eq{colDeletedTxn: liveDeletedTxnID})
+	builder.WriteRelsQuery = sb.Insert(tableTuple).Columns(
+		colNamespace,
+		colObjectID,
+		colRelation,
+		colUsersetNamespace,
+		colUsersetObjectID,
+		colUsersetRelation,
+		colCaveatName,
+		colCaveatContext,
+		colExpiration,
+		colCreatedTxn,
+	)
+	builder.QueryChangedQuery = sb.Select(
+		colNamespace,
+		colObjectID,
+		colRelation,
+		colUsersetNamespace,
+		colUsersetObjectID,
+		colUsersetRelation,
+		colCaveatName,
+		colCaveatContext,
+		colExpiration,
+		colCreatedTxn,
+		colDeletedTxn,
+	).From(tableTuple)
+	builder.CountRelsQuery = sb.Select("count(*)").From(tableTuple)
+
+	// caveat builders
+	builder.ReadCaveatQuery = sb.Select(colCaveatDefinition, colCreatedTxn).From(tableCaveat)
+	builder.ListCaveatsQuery = sb.Select(colCaveatDefinition, colCreatedTxn).From(tableCaveat).OrderBy(colName)
+	builder.WriteCaveatQuery = sb.Insert(tableCaveat).Columns(
+		colName,
+		colCaveatDefinition,
+		colCreatedTxn,
+	)
+	builder.DeleteCaveatQuery = sb.Update(tableCaveat).Where(sq.Eq{colDeletedTxn: liveDeletedTxnID})
+
+	return &builder
+}
@@ -0,0 +1,639 @@
+package sqlite
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/ccoveille/go-safecast"
+	sqlite "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	v1 "github.com/authzed/authzed-go/proto/authzed/api/v1"
+
+	"github.com/authzed/spicedb/internal/datastore/common"
+	"github.com/authzed/spicedb/internal/datastore/revisions"
+	"github.com/authzed/spicedb/pkg/datastore"
+	"github.com/authzed/spicedb/pkg/datastore/options"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/spiceerrors"
+	"github.com/authzed/spicedb/pkg/tuple"
+)
+
+const (
+	errUnableToWriteRelationships     = "unable to write relationships: %w"
+	errUnableToBulkWriteRelationships = "unable to bulk write relationships: %w"
+	errUnableToDeleteRelationships    = "unable to delete relationships: %w"
+	errUnableToWriteConfig            = "unable to write namespace config: %w"
+	errUnableToDeleteConfig           = "unable to delete namespace config: %w"
+
+	bulkInsertRowsLimit = 1_000
+
+	// lookupRelsChunkSize caps the number of OR-ed relationship clauses per
+	// query, keeping the statement within SQLite's maximum expression depth.
+	lookupRelsChunkSize = 100
+)
+
+type sqliteReadWriteTXN struct {
+	*sqliteReader
+
+	tx       *sql.Tx
+	newTxnID uint64
+}
+
+// structpbWrapper is used to marshal maps into JSON blobs.
+type structpbWrapper map[string]any
+
+func (cc *structpbWrapper) Scan(val any) error {
+	if val == nil {
+		clear(*cc)
+		*cc = nil
+		return nil
+	}
+
+	v, ok := val.([]byte)
+	if !ok {
+		return fmt.Errorf("unsupported type: %T", v)
+	}
+
+	clear(*cc)
+	return json.Unmarshal(v, cc)
+}
+
+func (cc *structpbWrapper) Value() (driver.Value, error) {
+	if *cc == nil {
+		return nil, nil
+	}
+	return json.Marshal(&cc)
+}
+
+// expirationToUTC normalizes an optional expiration for writing, so that the
+// driver's textual timestamp encoding is always in UTC and therefore directly
+// comparable against the registered now function.
+func expirationToUTC(expiration *time.Time) *time.Time {
+	if expiration == nil {
+		return nil
+	}
+	utc := expiration.UTC()
+	return &utc
+}
+
+func (rwt *sqliteReadWriteTXN) RegisterCounter(ctx context.Context, name string, filter *core.RelationshipFilter) error {
+	// Check if the counter already exists.
+	counters, err := rwt.lookupCounters(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if len(counters) > 0 {
+		return datastore.NewCounterAlreadyRegisteredErr(name, filter)
+	}
+
+	serializedFilter, err := filter.MarshalVT()
+	if err != nil {
+		return fmt.Errorf("unable to serialize filter: %w", err)
+	}
+
+	// Insert the counter.
+	query, args, err := rwt.InsertCounterQuery.
+		Values(
+			name,
+			serializedFilter,
+			0,
+			0,
+			rwt.newTxnID,
+		).ToSql()
+	if err != nil {
+		return fmt.Errorf("unable to register counter: %w", err)
+	}
+
+	_, err = rwt.tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		var sqliteErr *sqlite.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE {
+			return datastore.NewCounterAlreadyRegisteredErr(name, filter)
+		}
+
+		return fmt.Errorf("unable to register counter: %w", err)
+	}
+
+	return nil
+}
+
+func (rwt *sqliteReadWriteTXN) UnregisterCounter(ctx context.Context, name string) error {
+	// Ensure the counter exists.
+	counters, err := rwt.lookupCounters(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if len(counters) == 0 {
+		return datastore.NewCounterNotRegisteredErr(name)
+	}
+
+	// Delete the counter.
+	query, args, err := rwt.DeleteCounterQuery.
+		Where(sq.Eq{colName: name}).
+		Set(colDeletedTxn, rwt.newTxnID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("unable to unregister counter: %w", err)
+	}
+
+	_, err = rwt.tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("unable to unregister counter: %w", err)
+	}
+
+	return nil
+}
+
+func (rwt *sqliteReadWriteTXN) StoreCounterValue(ctx context.Context, name string, value int, computedAtRevision datastore.Revision) error {
+	// Ensure the counter exists.
+	counters, err := rwt.lookupCounters(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if len(counters) == 0 {
+		return datastore.NewCounterNotRegisteredErr(name)
+	}
+
+	updateRevisionID := computedAtRevision.(revisions.TransactionIDRevision).TransactionID()
+
+	// Update the counter.
+	query, args, err := rwt.UpdateCounterQuery.
+		Where(sq.Eq{colName: name}).
+		Set(colCounterCurrentCount, value).
+		Set(colCounterUpdatedAtRevision, updateRevisionID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("unable to store counter value: %w", err)
+	}
+
+	_, err = rwt.tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("unable to store counter value: %w", err)
+	}
+
+	return nil
+}
+
+// lookupLiveRels loads the live rows matching the given clauses, removing
+// TOUCH mutations that are no-ops, erroring on CREATE conflicts, and returning
+// the IDs of rows that must be marked deleted.
+func (rwt *sqliteReadWriteTXN) lookupLiveRels(ctx context.Context, clauses sq.Or, createAndTouchMutationsByRel map[string]tuple.RelationshipUpdate) ([]int64, error) {
+	query, args, err := rwt.QueryRelsWithIdsQuery.
+		Where(clauses).
+		Where(sq.GtOrEq{colDeletedTxn: rwt.newTxnID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(errUnableToWriteRelationships, err)
+	}
+
+	rows, err := rwt.tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf(errUnableToWriteRelationships, err)
+	}
+	defer common.LogOnError(ctx, rows.Close)
+
+	var resourceObjectType string
+	var resourceObjectID string
+	var relation string
+	var subjectObjectType string
+	var subjectObjectID string
+	var subjectRelation string
+	var caveatName string
+	var caveatContext structpbWrapper
+	var expiration *time.Time
+	var deletedTxn uint64
+
+	relIdsToDelete := make([]int64, 0, len(clauses))
+	for rows.Next() {
+		var relationshipID int64
+		if err := rows.Scan(
+			&relationshipID,
+			&resourceObjectType,
+			&resourceObjectID,
+			&relation,
+			&subjectObjectType,
+			&subjectObjectID,
+			&subjectRelation,
+			&caveatName,
+			&caveatContext,
+			&expiration,
+			&deletedTxn,
+		); err != nil {
+			return nil, fmt.Errorf(errUnableToWriteRelationships, err)
+		}
+
+		// Rows already deleted within this transaction are ignored; they neither
+		// conflict with a CREATE nor require deletion.
+		if deletedTxn != liveDeletedTxnID {
+			continue
+		}
+
+		foundRel := tuple.Relationship{
+			RelationshipReference: tuple.RelationshipReference{
+				Resource: tuple.ObjectAndRelation{
+					ObjectType: resourceObjectType,
+					ObjectID:   resourceObjectID,
+					Relation:   relation,
+				},
+				Subject: tuple.ObjectAndRelation{
+					ObjectType: subjectObjectType,
+					ObjectID:   subjectObjectID,
+					Relation:   subjectRelation,
+				},
+			},
+		}
+
+		tplString := tuple.StringWithoutCaveatOrExpiration(foundRel)
+		if mut, ok := createAndTouchMutationsByRel[tplString]; ok {
+			if mut.Operation == tuple.UpdateOperationCreate {
+				return nil, common.NewCreateRelationshipExistsError(&mut.Relationship)
+			}
+
+			// if the relationship to be deleted is for a TOUCH operation and the caveat
+			// name or context has not changed, then remove it from delete and create.
+			foundRel.OptionalCaveat, err = common.ContextualizedCaveatFrom(caveatName, caveatContext)
+			if err != nil {
+				return nil, fmt.Errorf(errUnableToQueryTuples, err)
+			}
+
+			// Ensure the tuples are the same.
+			if tuple.Equal(mut.Relationship, foundRel) {
+				delete(createAndTouchMutationsByRel, tplString)
+				continue
+			}
+		}
+
+		relIdsToDelete = append(relIdsToDelete, relationshipID)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf(errUnableToWriteRelationships, rows.Err())
+	}
+
+	return relIdsToDelete, nil
+}
+
+// WriteRelationships takes a list of tuple mutations and applies them to the datastore.
+func (rwt *sqliteReadWriteTXN) WriteRelationships(ctx context.Context, mutations []tuple.RelationshipUpdate) error {
+	bulkWrite := rwt.WriteRelsQuery
+	bulkWriteHasValues := false
+
+	clauses := sq.Or{}
+	createAndTouchMutationsByRel := make(map[string]tuple.RelationshipUpdate, len(mutations))
+
+	// Collect clauses for all mutations; existing rows must be consulted both to
+	// implement TOUCH/DELETE semantics and to report CREATE conflicts with the
+	// offending relationship.
+	for _, mut := range mutations {
+		rel := mut.Relationship
+		relString := tuple.StringWithoutCaveatOrExpiration(rel)
+
+		switch mut.Operation {
+		case tuple.UpdateOperationCreate, tuple.UpdateOperationTouch:
+			createAndTouchMutationsByRel[relString] = mut
+			clauses = append(clauses, exactRelationshipClause(rel))
+
+		case tuple.UpdateOperationDelete:
+			clauses = append(clauses, exactRelationshipClause(rel))
+
+		default:
+			return spiceerrors.MustBugf("unknown mutation operation")
+		}
+	}
+
+	if len(clauses) > 0 {
+		// The lookup is chunked to stay within SQLite's maximum expression
+		// tree depth.
+		relIdsToDelete := make([]int64, 0, len(clauses))
+		for chunk := range slices.Chunk(clauses, lookupRelsChunkSize) {
+			chunkIdsToDelete, err := rwt.lookupLiveRels(ctx, sq.Or(chunk), createAndTouchMutationsByRel)
+			if err != nil {
+				return err
+			}
+			relIdsToDelete = append(relIdsToDelete, chunkIdsToDelete...)
+		}
+
+		if len(relIdsToDelete) > 0 {
+			query, args, err := rwt.
+				DeleteRelsQuery.
+				Where(sq.Eq{colID: relIdsToDelete}).
+				Set(colDeletedTxn, rwt.newTxnID).
+				ToSql()
+			if err != nil {
+				return fmt.Errorf(errUnableToWriteRelationships, err)
+			}
+			if _, err := rwt.tx.ExecContext(ctx, query, args...); err != nil {
+				return fmt.Errorf(errUnableToWriteRelationships, err)
+			}
+		}
+	}
+
+	for _, mut := range createAndTouchMutationsByRel {
+		rel := mut.Relationship
+
+		var caveatName string
+		var caveatContext structpbWrapper
+		if rel.OptionalCaveat != nil {
+			caveatName = rel.OptionalCaveat.CaveatName
+			caveatContext = rel.OptionalCaveat.Context.AsMap()
+		}
+		bulkWrite = bulkWrite.Values(
+			rel.Resource.ObjectType,
+			rel.Resource.ObjectID,
+			rel.Resource.Relation,
+			rel.Subject.ObjectType,
+			rel.Subject.ObjectID,
+			rel.Subject.Relation,
+			caveatName,
+			&caveatContext,
+			expirationToUTC(rel.OptionalExpiration),
+			rwt.newTxnID,
+		)
+		bulkWriteHasValues = true
+	}
+
+	if bulkWriteHasValues {
+		query, args, err := bulkWrite.ToSql()
+		if err != nil {
+			return fmt.Errorf(errUnableToWriteRelationships, err)
+		}
+
+		_, err = rwt.tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf(errUnableToWriteRelationships, err)
+		}
+	}
+
+	return nil
+}
+
+func (rwt *sqliteReadWriteTXN) DeleteRelationships(ctx context.Context, filter *v1.RelationshipFilter, opts ...options.DeleteOptionsOption) (uint64, bool, error) {
+	// Collect the clauses for the filter, which are needed both for the delete
+	// and, if a limit is set, for the id subquery that implements it.
+	filterClauses := []sq.Sqlizer{}
+
+	// Add clauses for the ResourceFilter.
+	if filter.ResourceType != "" {
+		filterClauses = append(filterClauses, sq.Eq{colNamespace: filter.ResourceType})
+	}
+	if filter.OptionalResourceId != "" {
+		filterClauses = append(filterClauses, sq.Eq{colObjectID: filter.OptionalResourceId})
+	}
+	if filter.OptionalRelation != "" {
+		filterClauses = append(filterClauses, sq.Eq{colRelation: filter.OptionalRelation})
+	}
+	if filter.OptionalResourceIdPrefix != "" {
+		likeClause, err := common.BuildLikePrefixClause(colObjectID, filter.OptionalResourceIdPrefix)
+		if err != nil {
+			return 0, false, fmt.Errorf(errUnableToDeleteRelationships, err)
+		}
+		filterClauses = append(filterClauses, likeClause)
+	}
+
+	// Add clauses for the SubjectFilter.
+	if subjectFilter := filter.OptionalSubjectFilter; subjectFilter != nil {
+		filterClauses = append(filterClauses, sq.Eq{colUsersetNamespace: subjectFilter.SubjectType})
+		if subjectFilter.OptionalSubjectId != "" {
+			filterClauses = append(filterClauses, sq.Eq{colUsersetObjectID: subjectFilter.OptionalSubjectId})
+		}
+		if relationFilter := subjectFilter.OptionalRelation; relationFilter != nil {
+			filterClauses = append(filterClauses, sq.Eq{colUsersetRelation: cmp.Or(relationFilter.Relation, datastore.Ellipsis)})
+		}
+	}
+
+	query := rwt.DeleteRelsQuery.Set(colDeletedTxn, rwt.newTxnID)
+
+	// Add the limit, if any. SQLite does not support UPDATE ... LIMIT, so the
+	// limit is applied via a subquery over the row IDs instead.
+	delOpts := options.NewDeleteOptionsWithOptionsAndDefaults(opts...)
+	var delLimit uint64
+	if delOpts.DeleteLimit != nil && *delOpts.DeleteLimit > 0 {
+		delLimit = *delOpts.DeleteLimit
+	}
+
+	if delLimit > 0 {
+		idQuery := sb.Select(colID).From(tableTuple).Where(sq.Eq{colDeletedTxn: liveDeletedTxnID}).Limit(delLimit)
+		for _, clause := range filterClauses {
+			idQuery = idQuery.Where(clause)
+		}
+
+		idSQL, idArgs, err := idQuery.ToSql()
+		if err != nil {
+			return 0, false, fmt.Errorf(errUnableToDeleteRelationships, err)
+		}
+
+		query = query.Where(colID+" IN ("+idSQL+")", idArgs...)
+	} else {
+		for _, clause := range filterClauses {
+			query = query.Where(clause)
+		}
+	}
+
+	querySQL, args, err := query.ToSql()
+	if err != nil {
+		return 0, false, fmt.Errorf(errUnableToDeleteRelationships, err)
+	}
+
+	modified, err := rwt.tx.ExecContext(ctx, querySQL, args...)
+	if err != nil {
+		return 0, false, fmt.Errorf(errUnableToDeleteRelationships, err)
+	}
+
+	rowsAffected, err := modified.RowsAffected()
+	if err != nil {
+		return 0, false, fmt.Errorf(errUnableToDeleteRelationships, err)
+	}
+
+	uintRowsAffected, err := safecast.ToUint64(rowsAffected)
+	if err != nil {
+		return 0, false, spiceerrors.MustBugf("rowsAffected was negative: %v", err)
+	}
+
+	if delLimit > 0 && uintRowsAffected == delLimit {
+		return uintRowsAffected, true, nil
+	}
+
+	return uintRowsAffected, false, nil
+}
+
+func (rwt *sqliteReadWriteTXN) WriteNamespaces(ctx context.Context, newNamespaces ...*core.NamespaceDefinition) error {
+	deletedNamespaceClause := sq.Or{}
+	writeQuery := rwt.WriteNamespaceQuery
+
+	for _, newNamespace := range newNamespaces {
+		serialized, err := newNamespace.MarshalVT()
+		if err != nil {
+			return fmt.Errorf(errUnableToWriteConfig, err)
+		}
+
+		deletedNamespaceClause = append(deletedNamespaceClause, sq.Eq{colNamespace: newNamespace.Name})
+		writeQuery = writeQuery.Values(newNamespace.Name, serialized, rwt.newTxnID)
+	}
+
+	delSQL, delArgs, err := rwt.DeleteNamespaceQuery.
+		Set(colDeletedTxn, rwt.newTxnID).
+		Where(sq.And{sq.Eq{colDeletedTxn: liveDeletedTxnID}, deletedNamespaceClause}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf(errUnableToWriteConfig, err)
+	}
+
+	_, err = rwt.tx.ExecContext(ctx, delSQL, delArgs...)
+	if err != nil {
+		return fmt.Errorf(errUnableToWriteConfig, err)
+	}
+
+	query, args, err := writeQuery.ToSql()
+	if err != nil {
+		return fmt.Errorf(errUnableToWriteConfig, err)
+	}
+
+	_, err = rwt.tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf(errUnableToWriteConfig, err)
+	}
+
+	return nil
+}
+
+func (rwt *sqliteReadWriteTXN) DeleteNamespaces(ctx context.Context, nsNames ...string) error {
+	// For each namespace, check they exist and collect predicates for the
+	// "WHERE" clause to delete the namespaces and associated tuples.
+	nsClauses := make([]sq.Sqlizer, 0, len(nsNames))
+	tplClauses := make([]sq.Sqlizer, 0, len(nsNames))
+	for _, nsName := range nsNames {
+		baseQuery := rwt.ReadNamespaceQuery.Where(sq.Eq{colDeletedTxn: liveDeletedTxnID})
+		_, createdAt, err := loadNamespace(ctx, nsName, rwt.tx, baseQuery)
+		switch {
+		case errors.As(err, &datastore.NamespaceNotFoundError{}):
+			return err
+		case err == nil:
+			nsClauses = append(nsClauses, sq.Eq{colNamespace: nsName, colCreatedTxn: createdAt})
+			tplClauses = append(tplClauses, sq.Eq{colNamespace: nsName})
+		default:
+			return fmt.Errorf(errUnableToDeleteConfig, err)
+		}
+	}
+
+	delSQL, delArgs, err := rwt.DeleteNamespaceQuery.
+		Set(colDeletedTxn, rwt.newTxnID).
+		Where(sq.Or(nsClauses)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf(errUnableToDeleteConfig, err)
+	}
+
+	_, err = rwt.tx.ExecContext(ctx, delSQL, delArgs...)
+	if err != nil {
+		return fmt.Errorf(errUnableToDeleteConfig, err)
+	}
+
+	deleteTupleSQL, deleteTupleArgs, err := rwt.DeleteNamespaceRelationshipsQuery.
+		Set(colDeletedTxn, rwt.newTxnID).
+		Where(sq.Or(tplClauses)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf(errUnableToDeleteConfig, err)
+	}
+
+	_, err = rwt.tx.ExecContext(ctx, deleteTupleSQL, deleteTupleArgs...)
+	if err != nil {
+		return fmt.Errorf(errUnableToDeleteConfig, err)
+	}
+
+	return nil
+}
+
+func (rwt *sqliteReadWriteTXN) BulkLoad(ctx context.Context, iter datastore.BulkWriteRelationshipSource) (uint64, error) {
+	var sqlStmt bytes.Buffer
+
+	sql, _, err := rwt.WriteRelsQuery.Values(1, 2, 3, 4, 5, 6, 7, 8, 9, 10).ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var numWritten uint64
+	var rel *tuple.Relationship
+
+	// Bootstrap the loop
+	rel, err = iter.Next(ctx)
+
+	for rel != nil && err == nil {
+		sqlStmt.Reset()
+		sqlStmt.WriteString(sql)
+		var args []any
+		var batchLen uint64
+
+		for ; rel != nil && err == nil && batchLen < bulkInsertRowsLimit; rel, err = iter.Next(ctx) {
+			if rel.OptionalIntegrity != nil {
+				return 0, datastore.NewRelationshipIntegrityNotSupportedErr(tuple.StringWithoutCaveatOrExpiration(*rel))
+			}
+
+			if batchLen != 0 {
+				sqlStmt.WriteString(",(?,?,?,?,?,?,?,?,?,?)")
+			}
+
+			var caveatName string
+			var caveatContext structpbWrapper
+			if rel.OptionalCaveat != nil {
+				caveatName = rel.OptionalCaveat.CaveatName
+				caveatContext = rel.OptionalCaveat.Context.AsMap()
+			}
+			args = append(args,
+				rel.Resource.ObjectType,
+				rel.Resource.ObjectID,
+				rel.Resource.Relation,
+				rel.Subject.ObjectType,
+				rel.Subject.ObjectID,
+				rel.Subject.Relation,
+				caveatName,
+				&caveatContext,
+				expirationToUTC(rel.OptionalExpiration),
+				rwt.newTxnID,
+			)
+			batchLen++
+		}
+		if err != nil {
+			return 0, fmt.Errorf(errUnableToBulkWriteRelationships, err)
+		}
+
+		if batchLen > 0 {
+			if _, err := rwt.tx.ExecContext(ctx, sqlStmt.String(), args...); err != nil {
+				return 0, fmt.Errorf(errUnableToBulkWriteRelationships, wrapError(err))
+			}
+		}
+
+		numWritten += batchLen
+	}
+	if err != nil {
+		return 0, fmt.Errorf(errUnableToBulkWriteRelationships, err)
+	}
+
+	return numWritten, nil
+}
+
+func exactRelationshipClause(r tuple.Relationship) sq.Eq {
+	return sq.Eq{
+		colNamespace:        r.Resource.ObjectType,
+		colObjectID:         r.Resource.ObjectID,
+		colRelation:         r.Resource.Relation,
+		colUsersetNamespace: r.Subject.ObjectType,
+		colUsersetObjectID:  r.Subject.ObjectID,
+		colUsersetRelation:  r.Subject.Relation,
+	}
+}
+
+var _ datastore.ReadWriteTransaction = &sqliteReadWriteTXN{}
@@ -0,0 +1,157 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ccoveille/go-safecast"
+
+	"github.com/authzed/spicedb/internal/datastore/revisions"
+	"github.com/authzed/spicedb/pkg/datastore"
+	"github.com/authzed/spicedb/pkg/spiceerrors"
+)
+
+var ParseRevisionString = revisions.RevisionParser(revisions.TransactionID)
+
+const (
+	errRevision      = "unable to find revision: %w"
+	errCheckRevision = "unable to check revision: %w"
+
+	// querySelectRevision finds the first transaction at or after the given
+	// quantized timestamp (in unix nanoseconds). If there are no transactions
+	// newer than the quantization period, it just picks the latest transaction.
+	// The quantization arithmetic itself is performed in Go, since SQLite has
+	// no native nanosecond clock function.
+	//
+	//   %[1] Name of id column
+	//   %[2] Relationship tuple transaction table
+	//   %[3] Name of timestamp column
+	querySelectRevision = `SELECT COALESCE((
+			SELECT MIN(%[1]s)
+			FROM   %[2]s
+			WHERE  %[3]s >= ?
+		), (
+			SELECT MAX(%[1]s)
+			FROM   %[2]s
+		)) as revision;`
+
+	// queryValidTransaction returns a single row with three values, one boolean
+	// for whether the specified transaction ID is newer than the garbage
+	// collection window (taking the head transaction as always valid), one
+	// boolean for whether the transaction ID represents a transaction that will
+	// occur in the future, and the ID of the minimum valid (i.e. within the GC
+	// window) transaction.
+	//
+	//   %[1] Name of id column
+	//   %[2] Relationship tuple transaction table
+	//   %[3] Name of timestamp column
+	queryValidTransaction = `SELECT ? >= minvalid.id as fresh, ? > (
+			SELECT MAX(%[1]s)
+			FROM   %[2]s
+		) as unknown, minvalid.id as min_valid FROM (
+			SELECT COALESCE((
+				SELECT MIN(%[1]s)
+				FROM   %[2]s
+				WHERE  %[3]s >= ?
+			), (
+				SELECT MAX(%[1]s)
+				FROM   %[2]s
+			)) as id
+		) minvalid;`
+)
+
+func (sds *Datastore) optimizedRevisionFunc(ctx context.Context) (datastore.Revision, time.Duration, error) {
+	nowNanos := time.Now().UnixNano()
+	quantized := (nowNanos / sds.quantizationPeriodNanos) * sds.quantizationPeriodNanos
+	validForNanos := time.Duration(sds.quantizationPeriodNanos - (nowNanos - quantized))
+
+	var rev uint64
+	if err := sds.db.QueryRowContext(ctx, sds.optimizedRevisionQuery, quantized).
+		Scan(&rev); err != nil {
+		return datastore.NoRevision, 0, fmt.Errorf(errRevision, err)
+	}
+	return revisions.NewForTransactionID(rev), validForNanos, nil
+}
+
+func (sds *Datastore) HeadRevision(ctx context.Context) (datastore.Revision, error) {
+	revision, err := sds.loadRevision(ctx)
+	if err != nil {
+		return datastore.NoRevision, err
+	}
+	if revision == 0 {
+		return datastore.NoRevision, nil
+	}
+
+	return revisions.NewForTransactionID(revision), nil
+}
+
+func (sds *Datastore) CheckRevision(ctx context.Context, revision datastore.Revision) error {
+	if revision == datastore.NoRevision {
+		return datastore.NewInvalidRevisionErr(revision, datastore.CouldNotDetermineRevision)
+	}
+
+	rev, ok := revision.(revisions.TransactionIDRevision)
+	if !ok {
+		return fmt.Errorf("expected transaction revision, got %T", revision)
+	}
+
+	revisionTx := rev.TransactionID()
+	freshEnough, unknown, minValidTx, err := sds.checkValidTransaction(ctx, revisionTx)
+	if err != nil {
+		return fmt.Errorf(errCheckRevision, err)
+	}
+
+	if !freshEnough {
+		return datastore.NewStaleRevisionErr(revision, revisions.NewForTransactionID(minValidTx))
+	}
+	if unknown {
+		return datastore.NewInvalidRevisionErr(revision, datastore.CouldNotDetermineRevision)
+	}
+
+	return nil
+}
+
+func (sds *Datastore) loadRevision(ctx context.Context) (uint64, error) {
+	// slightly changed to support no revisions at all, needed for runtime seeding of first transaction
+	query, args, err := sds.GetLastRevision.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf(errRevision, err)
+	}
+
+	var revision *uint64
+	err = sds.db.QueryRowContext(ctx, query, args...).Scan(&revision)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf(errRevision, err)
+	}
+
+	if revision == nil {
+		return 0, nil
+	}
+
+	return *revision, nil
+}
+
+func (sds *Datastore) checkValidTransaction(ctx context.Context, revisionTx uint64) (bool, bool, uint64, error) {
+	var freshEnough, unknown sql.NullBool
+	var minValid sql.NullInt64
+
+	gcWindowStartNanos := time.Now().Add(-sds.gcWindow).UnixNano()
+	err := sds.db.QueryRowContext(ctx, sds.validTransactionQuery, revisionTx, revisionTx, gcWindowStartNanos).
+		Scan(&freshEnough, &unknown, &minValid)
+	if err != nil {
+		return false, false, 0, fmt.Errorf(errCheckRevision, err)
+	}
+
+	minValidTx, err := safecast.ToUint64(minValid.Int64)
+	if err != nil {
+		return false, false, 0, spiceerrors.MustBugf("minimum valid transaction ID was negative: %v", err)
+	}
+
+	return freshEnough.Bool, unknown.Bool, minValidTx, nil
+}
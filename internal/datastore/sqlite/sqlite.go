@@ -0,0 +1,751 @@
+// Package sqlite provides an embedded, file-backed implementation of the
+// datastore.Datastore interface built on modernc.org/sqlite, a cgo-free port
+// of SQLite. It follows the same transaction-ID MVCC model used by the MySQL
+// datastore: every mutation is tagged with the transaction that created it and
+// the transaction that deleted it, and revisions are transaction IDs.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+	sqlite "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	datastoreinternal "github.com/authzed/spicedb/internal/datastore"
+	"github.com/authzed/spicedb/internal/datastore/common"
+	"github.com/authzed/spicedb/internal/datastore/revisions"
+	log "github.com/authzed/spicedb/internal/logging"
+	"github.com/authzed/spicedb/pkg/datastore"
+	"github.com/authzed/spicedb/pkg/datastore/options"
+)
+
+const (
+	Engine = "sqlite"
+
+	tableNamespace            = "namespace_config"
+	tableTransaction          = "relation_tuple_transaction"
+	tableTuple                = "relation_tuple"
+	tableCaveat               = "caveat"
+	tableRelationshipCounters = "relationship_counters"
+	tableMetadata             = "metadata"
+
+	colID               = "id"
+	colTimestamp        = "timestamp"
+	colMetadata         = "metadata"
+	colNamespace        = "namespace"
+	colConfig           = "serialized_config"
+	colCreatedTxn       = "created_transaction"
+	colDeletedTxn       = "deleted_transaction"
+	colObjectID         = "object_id"
+	colRelation         = "relation"
+	colUsersetNamespace = "userset_namespace"
+	colUsersetObjectID  = "userset_object_id"
+	colUsersetRelation  = "userset_relation"
+	colName             = "name"
+	colCaveatDefinition = "definition"
+	colCaveatName       = "caveat_name"
+	colCaveatContext    = "caveat_context"
+	colExpiration       = "expiration"
+	colUniqueID         = "unique_id"
+
+	colCounterName              = "name"
+	colCounterSerializedFilter  = "serialized_filter"
+	colCounterCurrentCount      = "current_count"
+	colCounterUpdatedAtRevision = "count_updated_at_revision"
+
+	errUnableToInstantiate = "unable to instantiate datastore: %w"
+	liveDeletedTxnID       = uint64(math.MaxInt64)
+	batchDeleteSize        = 1000
+	seedingTimeout         = 10 * time.Second
+
+	// nowFunctionName is the name of the scalar function registered with the
+	// SQLite driver that returns the current UTC time in the same textual
+	// format the driver uses to bind time.Time parameters, making stored
+	// timestamps directly comparable server-side.
+	nowFunctionName = "spicedb_now"
+
+	// sqliteTimeFormat matches the driver's `_time_format=sqlite` write format.
+	sqliteTimeFormat = "2006-01-02 15:04:05.999999999-07:00"
+)
+
+var (
+	sb = sq.StatementBuilder.PlaceholderFormat(sq.Question)
+
+	registerFunctions sync.Once
+)
+
+func init() {
+	datastore.Engines = append(datastore.Engines, Engine)
+}
+
+type sqlFilter interface {
+	ToSql() (string, []any, error)
+}
+
+// NewSQLiteDatastore creates a new sqlite.Datastore backed by the database
+// file at the given path. The schema is created on first use, so no separate
+// migration step is required. Because SQLite is an embedded database, a given
+// database file must only be opened by a single Datastore at a time.
+func NewSQLiteDatastore(ctx context.Context, path string, options ...Option) (datastore.Datastore, error) {
+	ds, err := newSQLiteDatastore(ctx, path, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return datastoreinternal.NewSeparatingContextDatastoreProxy(ds), nil
+}
+
+func newSQLiteDatastore(ctx context.Context, path string, options ...Option) (*Datastore, error) {
+	config, err := generateConfig(options)
+	if err != nil {
+		return nil, fmt.Errorf(errUnableToInstantiate, err)
+	}
+
+	registerFunctions.Do(func() {
+		sqlite.MustRegisterDeterministicScalarFunction(nowFunctionName, 0, func(_ *sqlite.FunctionContext, _ []driver.Value) (driver.Value, error) {
+			return time.Now().UTC().Format(sqliteTimeFormat), nil
+		})
+
+		// SQLite's LIKE operator has no default escape character, but the
+		// shared query builders escape prefix filters with backslashes, so the
+		// built-in is replaced with an implementation that honors them.
+		sqlite.MustRegisterDeterministicScalarFunction("like", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			pattern, patternOK := valueAsString(args[0])
+			value, valueOK := valueAsString(args[1])
+			if !patternOK || !valueOK {
+				return nil, nil
+			}
+
+			if likeMatch([]rune(pattern), []rune(value)) {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		})
+	})
+
+	db, err := sql.Open("sqlite", dsnForPath(path, config.busyTimeout))
+	if err != nil {
+		return nil, fmt.Errorf(errUnableToInstantiate, err)
+	}
+
+	db.SetConnMaxLifetime(config.connMaxLifetime)
+	db.SetConnMaxIdleTime(config.connMaxIdleTime)
+	db.SetMaxOpenConns(config.maxOpenConns)
+	db.SetMaxIdleConns(config.maxOpenConns)
+
+	gcCtx, cancelGc := context.WithCancel(context.Background())
+
+	maxRevisionStaleness := time.Duration(float64(config.revisionQuantization.Nanoseconds())*
+		config.maxRevisionStalenessPercent) * time.Nanosecond
+
+	quantizationPeriodNanos := config.revisionQuantization.Nanoseconds()
+	if quantizationPeriodNanos < 1 {
+		quantizationPeriodNanos = 1
+	}
+
+	schema := common.NewSchemaInformationWithOptions(
+		common.WithRelationshipTableName(tableTuple),
+		common.WithColNamespace(colNamespace),
+		common.WithColObjectID(colObjectID),
+		common.WithColRelation(colRelation),
+		common.WithColUsersetNamespace(colUsersetNamespace),
+		common.WithColUsersetObjectID(colUsersetObjectID),
+		common.WithColUsersetRelation(colUsersetRelation),
+		common.WithColCaveatName(colCaveatName),
+		common.WithColCaveatContext(colCaveatContext),
+		common.WithColExpiration(colExpiration),
+		common.WithPaginationFilterType(common.TupleComparison),
+		common.WithPlaceholderFormat(sq.Question),
+		common.WithNowFunction(nowFunctionName),
+		common.WithColumnOptimization(config.columnOptimizationOption),
+		common.WithExpirationDisabled(false),
+	)
+
+	store := &Datastore{
+		db:                      db,
+		path:                    path,
+		revisionQuantization:    config.revisionQuantization,
+		quantizationPeriodNanos: quantizationPeriodNanos,
+		gcWindow:                config.gcWindow,
+		gcInterval:              config.gcInterval,
+		gcTimeout:               config.gcMaxOperationTime,
+		gcCtx:                   gcCtx,
+		cancelGc:                cancelGc,
+		watchEnabled:            !config.watchDisabled,
+		watchBufferLength:       config.watchBufferLength,
+		watchBufferWriteTimeout: config.watchBufferWriteTimeout,
+		QueryBuilder:            NewQueryBuilder(),
+		retryPolicy: common.RetryPolicy{
+			Backend:        Engine,
+			MaxRetries:     config.maxRetries,
+			BaseDelay:      25 * time.Millisecond,
+			JitterFraction: 0.5,
+			Classifier:     classifyRetryableError,
+		},
+		schema: *schema,
+		CachedOptimizedRevisions: revisions.NewCachedOptimizedRevisions(
+			maxRevisionStaleness,
+		),
+		CommonDecoder: revisions.CommonDecoder{
+			Kind: revisions.TransactionID,
+		},
+		filterMaximumIDCount: config.filterMaximumIDCount,
+		optimizedRevisionQuery: fmt.Sprintf(
+			querySelectRevision,
+			colID,
+			tableTransaction,
+			colTimestamp,
+		),
+		validTransactionQuery: fmt.Sprintf(
+			queryValidTransaction,
+			colID,
+			tableTransaction,
+			colTimestamp,
+		),
+	}
+
+	store.SetOptimizedRevisionFunc(store.optimizedRevisionFunc)
+
+	initCtx, cancelInit := context.WithTimeout(context.Background(), seedingTimeout)
+	defer cancelInit()
+	if err := store.initializeDatabase(initCtx); err != nil {
+		store.cancelGc()
+		common.LogOnError(ctx, db.Close)
+		return nil, fmt.Errorf(errUnableToInstantiate, err)
+	}
+
+	// Start a goroutine for garbage collection.
+	if store.gcInterval > 0*time.Minute && config.gcEnabled {
+		store.gcGroup, store.gcCtx = errgroup.WithContext(store.gcCtx)
+		store.gcGroup.Go(func() error {
+			return common.StartGarbageCollector(
+				store.gcCtx,
+				store,
+				store.gcInterval,
+				store.gcWindow,
+				store.gcTimeout,
+			)
+		})
+	} else {
+		log.Warn().Msg("datastore background garbage collection disabled")
+	}
+
+	return store, nil
+}
+
+func valueAsString(value driver.Value) (string, bool) {
+	switch typed := value.(type) {
+	case string:
+		return typed, true
+	case []byte:
+		return string(typed), true
+	default:
+		return "", false
+	}
+}
+
+// likeMatch implements SQLite's LIKE semantics (`%` and `_` wildcards,
+// ASCII-only case folding) with backslash as the escape character.
+func likeMatch(pattern, value []rune) bool {
+	var pi, vi int
+	starPi, starVi := -1, 0
+
+	for vi < len(value) {
+		if pi < len(pattern) {
+			switch pattern[pi] {
+			case '%':
+				starPi, starVi = pi, vi
+				pi++
+				continue
+
+			case '_':
+				pi++
+				vi++
+				continue
+
+			case '\\':
+				if pi+1 < len(pattern) && pattern[pi+1] == value[vi] {
+					pi += 2
+					vi++
+					continue
+				}
+
+			default:
+				if foldASCII(pattern[pi]) == foldASCII(value[vi]) {
+					pi++
+					vi++
+					continue
+				}
+			}
+		}
+
+		// Mismatch: backtrack to the last `%`, if any, and have it consume one
+		// additional character.
+		if starPi >= 0 {
+			starVi++
+			pi = starPi + 1
+			vi = starVi
+			continue
+		}
+
+		return false
+	}
+
+	for pi < len(pattern) && pattern[pi] == '%' {
+		pi++
+	}
+	return pi == len(pattern)
+}
+
+func foldASCII(r rune) rune {
+	if r >= 'A' && r <= 'Z' {
+		return r + ('a' - 'A')
+	}
+	return r
+}
+
+// dsnForPath builds the driver DSN for the given database path, enabling WAL
+// journaling and the sqlite time format used for expiration comparisons.
+func dsnForPath(path string, busyTimeout time.Duration) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+
+	return path + separator + strings.Join([]string{
+		"_pragma=journal_mode(WAL)",
+		"_pragma=synchronous(NORMAL)",
+		fmt.Sprintf("_pragma=busy_timeout(%d)", busyTimeout.Milliseconds()),
+		"_time_format=sqlite",
+	}, "&")
+}
+
+// Datastore is a SQLite-based implementation of the datastore.Datastore
+// interface.
+type Datastore struct {
+	db   *sql.DB
+	path string
+
+	revisionQuantization    time.Duration
+	quantizationPeriodNanos int64
+	gcWindow                time.Duration
+	gcInterval              time.Duration
+	gcTimeout               time.Duration
+	watchBufferLength       uint16
+	watchBufferWriteTimeout time.Duration
+	watchEnabled            bool
+	retryPolicy             common.RetryPolicy
+	filterMaximumIDCount    uint16
+	schema                  common.SchemaInformation
+	optimizedRevisionQuery  string
+	validTransactionQuery   string
+
+	gcGroup   *errgroup.Group
+	gcCtx     context.Context
+	cancelGc  context.CancelFunc
+	gcHasRun  atomic.Bool
+	gcRunning atomic.Bool
+
+	uniqueID atomic.Pointer[string]
+
+	// nextTransactionID hands out transaction IDs for read-write transactions.
+	// IDs are allocated before the transaction row is written, so that the
+	// database write lock is only held while mutations are applied; aborted
+	// transactions simply leave a gap in the sequence.
+	txnIDLock         sync.Mutex
+	nextTransactionID uint64
+
+	*QueryBuilder
+	*revisions.CachedOptimizedRevisions
+	revisions.CommonDecoder
+}
+
+// initializeDatabase creates the schema if necessary and seeds the first
+// transaction and the datastore's unique ID.
+func (sds *Datastore) initializeDatabase(ctx context.Context) error {
+	for _, stmt := range createTableStatements {
+		if _, err := sds.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("unable to create schema: %w", err)
+		}
+	}
+
+	// Seed the first transaction and unique ID idempotently.
+	createBaseTxn := fmt.Sprintf("INSERT OR IGNORE INTO %s (%s, %s) VALUES (1, 0)", tableTransaction, colID, colTimestamp)
+	if _, err := sds.db.ExecContext(ctx, createBaseTxn); err != nil {
+		return fmt.Errorf("unable to seed base transaction: %w", err)
+	}
+
+	uuidSQL, uuidArgs, err := sb.
+		Insert(tableMetadata).
+		Options("OR IGNORE").
+		Columns(colID, colUniqueID).
+		Values(0, uuid.NewString()).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("unable to seed unique ID: %w", err)
+	}
+
+	if _, err := sds.db.ExecContext(ctx, uuidSQL, uuidArgs...); err != nil {
+		return fmt.Errorf("unable to seed unique ID: %w", err)
+	}
+
+	headTxnID, err := sds.loadRevision(ctx)
+	if err != nil {
+		return err
+	}
+
+	sds.txnIDLock.Lock()
+	sds.nextTransactionID = headTxnID + 1
+	sds.txnIDLock.Unlock()
+
+	return nil
+}
+
+// allocateTransactionID reserves the next transaction ID. The matching
+// transaction row is only written when the transaction commits.
+func (sds *Datastore) allocateTransactionID() uint64 {
+	sds.txnIDLock.Lock()
+	defer sds.txnIDLock.Unlock()
+
+	allocated := sds.nextTransactionID
+	sds.nextTransactionID++
+	return allocated
+}
+
+func (sds *Datastore) MetricsID() (string, error) {
+	return sds.path, nil
+}
+
+func (sds *Datastore) SnapshotReader(rev datastore.Revision) datastore.Reader {
+	createTxFunc := func(ctx context.Context) (*sql.Tx, txCleanupFunc, error) {
+		tx, err := sds.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return tx, tx.Rollback, nil
+	}
+
+	executor := common.QueryRelationshipsExecutor{
+		Executor: newSQLiteExecutor(sds.db, sds),
+	}
+
+	return &sqliteReader{
+		sds.QueryBuilder,
+		createTxFunc,
+		executor,
+		buildLivingObjectFilterForRevision(rev),
+		sds.filterMaximumIDCount,
+		sds.schema,
+	}
+}
+
+func noCleanup() error { return nil }
+
+// ReadWriteTx starts a read/write transaction, which will be committed if no error is
+// returned and rolled back if an error is returned.
+func (sds *Datastore) ReadWriteTx(
+	ctx context.Context,
+	fn datastore.TxUserFunc,
+	opts ...options.RWTOptionsOption,
+) (datastore.Revision, error) {
+	config := options.NewRWTOptionsWithOptions(opts...)
+
+	var newTxnID uint64
+	// SQLITE_BUSY is returned immediately, without invoking the busy handler,
+	// when waiting could deadlock (e.g. upgrading a read transaction to a write
+	// transaction), so retries back off before the next attempt.
+	err := sds.retryPolicy.ExecuteWithRetries(ctx, config.DisableRetries, func(ctx context.Context) error {
+		newTxnID = sds.allocateTransactionID()
+		return beginTxFunc(ctx, sds.db, func(tx *sql.Tx) error {
+			longLivedTx := func(context.Context) (*sql.Tx, txCleanupFunc, error) {
+				return tx, noCleanup, nil
+			}
+
+			executor := common.QueryRelationshipsExecutor{
+				Executor: newSQLiteExecutor(tx, sds),
+			}
+
+			rwt := &sqliteReadWriteTXN{
+				&sqliteReader{
+					sds.QueryBuilder,
+					longLivedTx,
+					executor,
+					currentlyLivingObjects,
+					sds.filterMaximumIDCount,
+					sds.schema,
+				},
+				tx,
+				newTxnID,
+			}
+
+			if err := fn(ctx, rwt); err != nil {
+				return err
+			}
+
+			// The transaction row is written last so that aborted transactions
+			// leave no trace in the transaction log.
+			var metadata map[string]any
+			if config.Metadata != nil {
+				metadata = config.Metadata.AsMap()
+			}
+
+			return sds.createTransactionRow(ctx, tx, newTxnID, metadata)
+		})
+	})
+	if err != nil {
+		return datastore.NoRevision, wrapError(err)
+	}
+
+	return revisions.NewForTransactionID(newTxnID), nil
+}
+
+func (sds *Datastore) createTransactionRow(ctx context.Context, tx *sql.Tx, newTxnID uint64, metadata map[string]any) error {
+	var wrappedMetadata structpbWrapper
+	if len(metadata) > 0 {
+		wrappedMetadata = metadata
+	}
+
+	query, args, err := sb.Insert(tableTransaction).
+		Columns(colID, colTimestamp, colMetadata).
+		Values(newTxnID, time.Now().UnixNano(), &wrappedMetadata).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("createTransactionRow: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("createTransactionRow: %w", err)
+	}
+
+	return nil
+}
+
+// beginTxFunc runs the given function in a transaction, committing if the
+// function returns nil and rolling back otherwise.
+func beginTxFunc(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		if rerr := tx.Rollback(); rerr != nil && !errors.Is(rerr, sql.ErrTxDone) {
+			log.Ctx(ctx).Err(rerr).Msg("error rolling back transaction")
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// wrapError maps any sqlite internal error into a SpiceDB typed error or an error
+// that implements GRPCStatus().
+func wrapError(err error) error {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE {
+		return common.NewCreateRelationshipExistsError(nil)
+	}
+
+	// Busy and locked errors are retried above; if one escapes here, retries
+	// were disabled or exhausted, so surface it as a serialization failure for
+	// the services layer to map.
+	if isErrorRetryable(err) {
+		return common.NewSerializationError(err)
+	}
+
+	return err
+}
+
+// isErrorRetryable returns whether the transaction that produced the error can
+// be retried. SQLITE_BUSY covers lock contention with another writer, while
+// SQLITE_LOCKED covers conflicts within a shared cache.
+func isErrorRetryable(err error) bool {
+	_, retryable := classifyRetryableError(err)
+	return retryable
+}
+
+// classifyRetryableError determines whether the given error is retryable and, if so,
+// the error class label to record for the retry.
+func classifyRetryableError(err error) (string, bool) {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return "", false
+	}
+
+	switch sqliteErr.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY:
+		return "busy", true
+	case sqlite3.SQLITE_LOCKED:
+		return "locked", true
+	default:
+		return "", false
+	}
+}
+
+type querier interface {
+	QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+}
+
+type asQueryableTx struct {
+	tx querier
+}
+
+func (aqt asQueryableTx) QueryFunc(ctx context.Context, f func(context.Context, common.Rows) error, sql string, args ...any) error {
+	rows, err := aqt.tx.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return f(ctx, rows)
+}
+
+func newSQLiteExecutor(tx querier, explainable datastore.Explainable) common.ExecuteReadRelsQueryFunc {
+	// Single statements are implicitly transactional in SQLite, and WAL mode
+	// gives each statement a consistent snapshot, so no explicit transaction
+	// is needed for reads.
+	return func(ctx context.Context, builder common.RelationshipsQueryBuilder) (datastore.RelationshipIterator, error) {
+		return common.QueryRelationships[common.Rows, structpbWrapper](ctx, builder, asQueryableTx{tx}, explainable)
+	}
+}
+
+// Close closes the data store.
+func (sds *Datastore) Close() error {
+	sds.cancelGc()
+	if sds.gcGroup != nil {
+		if err := sds.gcGroup.Wait(); err != nil && !errors.Is(err, context.Canceled) {
+			log.Error().Err(err).Msg("error waiting for garbage collector to shutdown")
+		}
+	}
+	return sds.db.Close()
+}
+
+// ReadyState returns whether the datastore is ready to accept data. The SQLite
+// datastore creates its schema on instantiation, so it is ready as soon as the
+// database is reachable and seeded.
+func (sds *Datastore) ReadyState(ctx context.Context) (datastore.ReadyState, error) {
+	if err := sds.db.PingContext(ctx); err != nil {
+		return datastore.ReadyState{}, err
+	}
+
+	headRevision, err := sds.HeadRevision(ctx)
+	if err != nil {
+		return datastore.ReadyState{}, err
+	}
+	if headRevision == datastore.NoRevision {
+		return datastore.ReadyState{
+			Message: "datastore is not properly seeded",
+			IsReady: false,
+		}, nil
+	}
+
+	return datastore.ReadyState{IsReady: true}, nil
+}
+
+func (sds *Datastore) Features(_ context.Context) (*datastore.Features, error) {
+	return sds.OfflineFeatures()
+}
+
+func (sds *Datastore) OfflineFeatures() (*datastore.Features, error) {
+	watchSupported := datastore.FeatureUnsupported
+	if sds.watchEnabled {
+		watchSupported = datastore.FeatureSupported
+	}
+
+	return &datastore.Features{
+		Watch: datastore.Feature{
+			Status: watchSupported,
+		},
+		IntegrityData: datastore.Feature{
+			Status: datastore.FeatureUnsupported,
+		},
+		ContinuousCheckpointing: datastore.Feature{
+			Status: datastore.FeatureUnsupported,
+		},
+		WatchEmitsImmediately: datastore.Feature{
+			Status: datastore.FeatureUnsupported,
+		},
+	}, nil
+}
+
+func buildLivingObjectFilterForRevision(revision datastore.Revision) queryFilterer {
+	return func(original sq.SelectBuilder) sq.SelectBuilder {
+		return original.Where(sq.LtOrEq{colCreatedTxn: revision.(revisions.TransactionIDRevision).TransactionID()}).
+			Where(sq.Or{
+				sq.Eq{colDeletedTxn: liveDeletedTxnID},
+				sq.Gt{colDeletedTxn: revision},
+			})
+	}
+}
+
+func currentlyLivingObjects(original sq.SelectBuilder) sq.SelectBuilder {
+	return original.Where(sq.Eq{colDeletedTxn: liveDeletedTxnID})
+}
+
+// createTableStatements is the schema for the SQLite datastore, mirroring the
+// MySQL datastore's layout. The unique index over living rows both enforces
+// CREATE semantics and accelerates lookups of live relationships.
+var createTableStatements = []string{
+	fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		namespace TEXT NOT NULL,
+		serialized_config BLOB NOT NULL,
+		created_transaction INTEGER NOT NULL,
+		deleted_transaction INTEGER NOT NULL DEFAULT 9223372036854775807,
+		CONSTRAINT pk_namespace_config PRIMARY KEY (namespace, created_transaction),
+		CONSTRAINT uq_namespace_living UNIQUE (namespace, deleted_transaction))`, tableNamespace),
+	fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		namespace TEXT NOT NULL,
+		object_id TEXT NOT NULL,
+		relation TEXT NOT NULL,
+		userset_namespace TEXT NOT NULL,
+		userset_object_id TEXT NOT NULL,
+		userset_relation TEXT NOT NULL,
+		caveat_name TEXT NOT NULL DEFAULT '',
+		caveat_context BLOB,
+		expiration TIMESTAMP NULL DEFAULT NULL,
+		created_transaction INTEGER NOT NULL,
+		deleted_transaction INTEGER NOT NULL DEFAULT 9223372036854775807,
+		CONSTRAINT uq_relation_tuple_living UNIQUE (namespace, object_id, relation, userset_namespace, userset_object_id, userset_relation, deleted_transaction))`, tableTuple),
+	fmt.Sprintf(`CREATE INDEX IF NOT EXISTS ix_relation_tuple_by_subject ON %s (userset_object_id, userset_namespace, userset_relation, namespace, relation)`, tableTuple),
+	fmt.Sprintf(`CREATE INDEX IF NOT EXISTS ix_relation_tuple_by_deleted_transaction ON %s (deleted_transaction)`, tableTuple),
+	fmt.Sprintf(`CREATE INDEX IF NOT EXISTS ix_relation_tuple_expired ON %s (expiration)`, tableTuple),
+	fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		timestamp INTEGER NOT NULL,
+		metadata BLOB)`, tableTransaction),
+	fmt.Sprintf(`CREATE INDEX IF NOT EXISTS ix_relation_tuple_transaction_by_timestamp ON %s (timestamp)`, tableTransaction),
+	fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		name TEXT NOT NULL,
+		definition BLOB NOT NULL,
+		created_transaction INTEGER NOT NULL,
+		deleted_transaction INTEGER NOT NULL DEFAULT 9223372036854775807,
+		CONSTRAINT uq_caveat_living UNIQUE (name, deleted_transaction))`, tableCaveat),
+	fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		name TEXT NOT NULL,
+		serialized_filter BLOB NOT NULL,
+		current_count INTEGER NOT NULL DEFAULT 0,
+		count_updated_at_revision INTEGER NOT NULL DEFAULT 0,
+		created_transaction INTEGER NOT NULL,
+		deleted_transaction INTEGER NOT NULL DEFAULT 9223372036854775807,
+		CONSTRAINT uq_relationship_counter_living UNIQUE (name, deleted_transaction))`, tableRelationshipCounters),
+	fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id INTEGER PRIMARY KEY,
+		unique_id TEXT NOT NULL)`, tableMetadata),
+}
@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/authzed/spicedb/pkg/datastore"
+	test "github.com/authzed/spicedb/pkg/datastore/test"
+)
+
+// ExampleRetryableError implements the TestableDatastore interface.
+// modernc.org/sqlite provides no way to construct its error type directly, so
+// a real SQLITE_BUSY is produced by contending for the database's write lock.
+func (sds *Datastore) ExampleRetryableError() error {
+	db, err := sql.Open("sqlite", dsnForPath(sds.path, 0))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	conn1, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn1.Close()
+
+	if _, err := conn1.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return err
+	}
+	defer func() { _, _ = conn1.ExecContext(ctx, "ROLLBACK") }()
+
+	conn2, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn2.Close()
+
+	if _, err := conn2.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return err
+	}
+
+	_, _ = conn2.ExecContext(ctx, "ROLLBACK")
+	return errors.New("expected SQLITE_BUSY from write lock contention")
+}
+
+func createDatastore(t *testing.T) test.DatastoreTesterFunc {
+	return func(revisionQuantization, gcInterval, gcWindow time.Duration, watchBufferLength uint16) (datastore.Datastore, error) {
+		dir, err := os.MkdirTemp("", "spicedb-sqlite-test-")
+		if err != nil {
+			return nil, err
+		}
+		t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+		return NewSQLiteDatastore(
+			context.Background(),
+			filepath.Join(dir, "spicedb.db"),
+			RevisionQuantization(revisionQuantization),
+			GCInterval(gcInterval),
+			GCWindow(gcWindow),
+			WatchBufferLength(watchBufferLength),
+		)
+	}
+}
+
+func TestSQLiteDatastore(t *testing.T) {
+	t.Parallel()
+	test.AllWithExceptions(t, createDatastore(t), test.WithCategories(test.WatchSchemaCategory, test.WatchCheckpointsCategory), true)
+}
@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/ccoveille/go-safecast"
+
+	"github.com/authzed/spicedb/internal/datastore/common"
+	"github.com/authzed/spicedb/pkg/datastore"
+	"github.com/authzed/spicedb/pkg/spiceerrors"
+)
+
+func (sds *Datastore) Statistics(ctx context.Context) (datastore.Stats, error) {
+	uniqueID, err := sds.UniqueID(ctx)
+	if err != nil {
+		return datastore.Stats{}, err
+	}
+
+	// SQLite has no equivalent of an information schema row estimate, so a
+	// direct count is used instead.
+	query, args, err := sds.CountRelsQuery.ToSql()
+	if err != nil {
+		return datastore.Stats{}, err
+	}
+
+	var count sql.NullInt64
+	err = sds.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return datastore.Stats{}, err
+	}
+
+	nsQuery := sds.ReadNamespaceQuery.Where(sq.Eq{colDeletedTxn: liveDeletedTxnID})
+
+	tx, err := sds.db.BeginTx(ctx, nil)
+	if err != nil {
+		return datastore.Stats{}, err
+	}
+	defer common.LogOnError(ctx, tx.Rollback)
+
+	nsDefs, err := loadAllNamespaces(ctx, tx, nsQuery)
+	if err != nil {
+		return datastore.Stats{}, fmt.Errorf("unable to load namespaces: %w", err)
+	}
+
+	uintCount, err := safecast.ToUint64(count.Int64)
+	if err != nil {
+		return datastore.Stats{}, spiceerrors.MustBugf("could not cast count to uint64: %v", err)
+	}
+
+	return datastore.Stats{
+		UniqueID:                   uniqueID,
+		ObjectTypeStatistics:       datastore.ComputeObjectTypeStats(nsDefs),
+		EstimatedRelationshipCount: uintCount,
+	}, nil
+}
+
+func (sds *Datastore) UniqueID(ctx context.Context) (string, error) {
+	if sds.uniqueID.Load() == nil {
+		sql, args, err := sb.Select(colUniqueID).From(tableMetadata).ToSql()
+		if err != nil {
+			return "", fmt.Errorf("unable to generate query sql: %w", err)
+		}
+
+		var uniqueID string
+		if err := sds.db.QueryRowContext(ctx, sql, args...).Scan(&uniqueID); err != nil {
+			return "", fmt.Errorf("unable to query unique ID: %w", err)
+		}
+		sds.uniqueID.Store(&uniqueID)
+		return uniqueID, nil
+	}
+
+	return *sds.uniqueID.Load(), nil
+}
@@ -52,7 +52,7 @@ func (fs FoundSubject) Subject() tuple.ObjectAndRelation {
 
 // WildcardType returns the object type for the wildcard subject, if this is a wildcard subject.
 func (fs FoundSubject) WildcardType() (string, bool) {
-	if fs.subject.ObjectID == tuple.PublicWildcard {
+	if tuple.IsWildcardSubject(fs.subject) {
 		return fs.subject.ObjectType, true
 	}
 
@@ -62,7 +62,7 @@ func (fs FoundSubject) WildcardType() (string, bool) {
 // ExcludedSubjectsFromWildcard returns those subjects excluded from the wildcard subject.
 // If not a wildcard subject, returns false.
 func (fs FoundSubject) ExcludedSubjectsFromWildcard() ([]FoundSubject, bool) {
-	if fs.subject.ObjectID == tuple.PublicWildcard {
+	if tuple.IsWildcardSubject(fs.subject) {
 		return fs.excludedSubjects, true
 	}
 
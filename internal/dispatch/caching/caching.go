@@ -36,6 +36,7 @@ type Dispatcher struct {
 
 	checkTotalCounter               prometheus.Counter
 	checkFromCacheCounter           prometheus.Counter
+	checkCanonicalSharedHitCounter  prometheus.Counter
 	lookupResourcesTotalCounter     prometheus.Counter
 	lookupResourcesFromCacheCounter prometheus.Counter
 	lookupSubjectsTotalCounter      prometheus.Counter
@@ -68,6 +69,12 @@ func NewCachingDispatcher(cacheInst cache.Cache[keys.DispatchCacheKey, any], met
 		Subsystem: prometheusSubsystem,
 		Name:      "check_from_cache_total",
 	})
+	checkCanonicalSharedHitCounter := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: prometheusNamespace,
+		Subsystem: prometheusSubsystem,
+		Name:      "check_canonical_shared_hits_total",
+		Help:      "count of check cache hits found via a relation's canonical key that the relation name alone would have missed",
+	})
 
 	lookupResourcesTotalCounter := prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: prometheusNamespace,
@@ -100,6 +107,10 @@ func NewCachingDispatcher(cacheInst cache.Cache[keys.DispatchCacheKey, any], met
 		if err != nil {
 			return nil, fmt.Errorf(errCachingInitialization, err)
 		}
+		err = prometheus.Register(checkCanonicalSharedHitCounter)
+		if err != nil {
+			return nil, fmt.Errorf(errCachingInitialization, err)
+		}
 		err = prometheus.Register(lookupResourcesTotalCounter)
 		if err != nil {
 			return nil, fmt.Errorf(errCachingInitialization, err)
@@ -128,6 +139,7 @@ func NewCachingDispatcher(cacheInst cache.Cache[keys.DispatchCacheKey, any], met
 		keyHandler:                      keyHandler,
 		checkTotalCounter:               checkTotalCounter,
 		checkFromCacheCounter:           checkFromCacheCounter,
+		checkCanonicalSharedHitCounter:  checkCanonicalSharedHitCounter,
 		lookupResourcesTotalCounter:     lookupResourcesTotalCounter,
 		lookupResourcesFromCacheCounter: lookupResourcesFromCacheCounter,
 		lookupSubjectsTotalCounter:      lookupSubjectsTotalCounter,
@@ -159,6 +171,7 @@ func (cd *Dispatcher) DispatchCheck(ctx context.Context, req *v1.DispatchCheckRe
 
 		if req.Metadata.DepthRemaining >= response.Metadata.DepthRequired {
 			cd.checkFromCacheCounter.Inc()
+			cd.countCanonicalSharedHit(ctx, req, requestKey)
 			// If debugging is requested, add the req and the response to the trace.
 			if req.Debug == v1.DispatchCheckRequest_ENABLE_BASIC_DEBUGGING {
 				nodeID := nodeid.Get()
@@ -199,6 +212,20 @@ func (cd *Dispatcher) DispatchCheck(ctx context.Context, req *v1.DispatchCheckRe
 	return computed, err
 }
 
+// countCanonicalSharedHit increments the canonical shared hit counter if the given cache hit
+// was found via a relation's canonical key and the relation-name-based key has no cached entry,
+// meaning the hit would have been a miss without canonical key sharing.
+func (cd *Dispatcher) countCanonicalSharedHit(ctx context.Context, req *v1.DispatchCheckRequest, requestKey keys.DispatchCacheKey) {
+	relationKey, err := cd.keyHandler.CheckRelationCacheKey(ctx, req)
+	if err != nil || relationKey == requestKey {
+		return
+	}
+
+	if _, found := cd.c.Get(relationKey); !found {
+		cd.checkCanonicalSharedHitCounter.Inc()
+	}
+}
+
 // DispatchExpand implements dispatch.Expand interface and does not do any caching yet.
 func (cd *Dispatcher) DispatchExpand(ctx context.Context, req *v1.DispatchExpandRequest) (*v1.DispatchExpandResponse, error) {
 	resp, err := cd.d.DispatchExpand(ctx, req)
@@ -401,6 +428,7 @@ func (cd *Dispatcher) DispatchLookupSubjects(req *v1.DispatchLookupSubjectsReque
 func (cd *Dispatcher) Close() error {
 	prometheus.Unregister(cd.checkTotalCounter)
 	prometheus.Unregister(cd.checkFromCacheCounter)
+	prometheus.Unregister(cd.checkCanonicalSharedHitCounter)
 	prometheus.Unregister(cd.lookupResourcesTotalCounter)
 	prometheus.Unregister(cd.lookupResourcesFromCacheCounter)
 	prometheus.Unregister(cd.lookupSubjectsFromCacheCounter)
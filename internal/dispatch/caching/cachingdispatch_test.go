@@ -5,11 +5,17 @@ import (
 	"testing"
 	"time"
 
+	dto "github.com/prometheus/client_model/go"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/authzed/spicedb/internal/datastore/dsfortesting"
+	"github.com/authzed/spicedb/internal/datastore/memdb"
 	"github.com/authzed/spicedb/internal/dispatch"
+	"github.com/authzed/spicedb/internal/dispatch/keys"
+	datastoremw "github.com/authzed/spicedb/internal/middleware/datastore"
+	"github.com/authzed/spicedb/internal/testfixtures"
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 	v1 "github.com/authzed/spicedb/pkg/proto/dispatch/v1"
 	"github.com/authzed/spicedb/pkg/tuple"
@@ -149,6 +155,77 @@ func TestMaxDepthCaching(t *testing.T) {
 	}
 }
 
+func TestCanonicalKeyCacheSharing(t *testing.T) {
+	require := require.New(t)
+
+	rawDS, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ds, revision := testfixtures.DatastoreFromSchemaAndTestRelationships(rawDS, `
+		definition user {}
+
+		definition document {
+			relation viewer: user
+			permission edit = viewer
+			permission edit2 = viewer
+		}
+	`, nil, require)
+
+	ctx := datastoremw.ContextWithHandle(t.Context())
+	require.NoError(datastoremw.SetInContext(ctx, ds))
+
+	delegate := delegateDispatchMock{&mock.Mock{}}
+
+	buildRequest := func(relationName string) *v1.DispatchCheckRequest {
+		return &v1.DispatchCheckRequest{
+			ResourceRelation: RR("document", relationName),
+			ResourceIds:      []string{"somedoc"},
+			Subject:          tuple.MustParseSubjectONR("user:tom").ToCoreONR(),
+			Metadata: &v1.ResolverMeta{
+				AtRevision:     revision.String(),
+				DepthRemaining: 50,
+			},
+		}
+	}
+
+	// Only the first aliased permission should reach the delegate; the second
+	// shares its canonical key and must be served from the cache.
+	delegate.On("DispatchCheck", buildRequest("edit")).Return(&v1.DispatchCheckResponse{
+		ResultsByResourceId: map[string]*v1.ResourceCheckResult{
+			"somedoc": {
+				Membership: v1.ResourceCheckResult_MEMBER,
+			},
+		},
+		Metadata: &v1.ResponseMeta{
+			DispatchCount: 1,
+			DepthRequired: 1,
+		},
+	}, nil).Times(1)
+
+	dispatcher, err := NewCachingDispatcher(DispatchTestCache(t), false, "", &keys.CanonicalKeyHandler{})
+	require.NoError(err)
+	dispatcher.SetDelegate(delegate)
+	defer dispatcher.Close()
+
+	resp, err := dispatcher.DispatchCheck(ctx, buildRequest("edit"))
+	require.NoError(err)
+	require.Equal(v1.ResourceCheckResult_MEMBER, resp.ResultsByResourceId["somedoc"].Membership)
+
+	// We have to sleep a while to let the cache converge
+	time.Sleep(10 * time.Millisecond)
+
+	resp, err = dispatcher.DispatchCheck(ctx, buildRequest("edit2"))
+	require.NoError(err)
+	require.Equal(v1.ResourceCheckResult_MEMBER, resp.ResultsByResourceId["somedoc"].Membership)
+
+	delegate.AssertExpectations(t)
+
+	// The second check was a hit the relation name alone would have missed.
+	metric := &dto.Metric{}
+	require.NoError(dispatcher.checkCanonicalSharedHitCounter.Write(metric))
+	require.Equal(float64(1), metric.GetCounter().GetValue())
+}
+
 type delegateDispatchMock struct {
 	*mock.Mock
 }
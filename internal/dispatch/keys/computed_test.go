@@ -155,7 +155,7 @@ func TestStableCacheKeys(t *testing.T) {
 					},
 				}, computeBothHashes)
 			},
-			"f49fbafef9c489abe601",
+			"ac948ba58de9a59473",
 		},
 		{
 			"lookup resources 2 with zero limit",
@@ -171,7 +171,7 @@ func TestStableCacheKeys(t *testing.T) {
 					OptionalLimit: 0,
 				}, computeBothHashes)
 			},
-			"f49fbafef9c489abe601",
+			"ac948ba58de9a59473",
 		},
 		{
 			"lookup resources 2 with non-zero limit",
@@ -187,7 +187,7 @@ func TestStableCacheKeys(t *testing.T) {
 					OptionalLimit: 42,
 				}, computeBothHashes)
 			},
-			"ea88adb1c1dfa6ebab01",
+			"a7ffc7f591ebe6eb79",
 		},
 		{
 			"lookup resources 2 with nil context",
@@ -203,7 +203,7 @@ func TestStableCacheKeys(t *testing.T) {
 					Context: nil,
 				}, computeBothHashes)
 			},
-			"f49fbafef9c489abe601",
+			"ac948ba58de9a59473",
 		},
 		{
 			"lookup resources 2 with empty context",
@@ -222,7 +222,7 @@ func TestStableCacheKeys(t *testing.T) {
 					}(),
 				}, computeBothHashes)
 			},
-			"f49fbafef9c489abe601",
+			"ac948ba58de9a59473",
 		},
 		{
 			"lookup resources 2 with context",
@@ -244,7 +244,7 @@ func TestStableCacheKeys(t *testing.T) {
 					}(),
 				}, computeBothHashes)
 			},
-			"bbd78884eff9edbebd01",
+			"89858b99dfc1b8ccaf01",
 		},
 		{
 			"lookup resources 2 with different context",
@@ -266,7 +266,7 @@ func TestStableCacheKeys(t *testing.T) {
 					}(),
 				}, computeBothHashes)
 			},
-			"94d0faa5feaaa4dc17",
+			"e0d1f3deb1ce98e7d901",
 		},
 		{
 			"lookup resources 2 with escaped string",
@@ -287,7 +287,7 @@ func TestStableCacheKeys(t *testing.T) {
 					}(),
 				}, computeBothHashes)
 			},
-			"cdffc895cb9299b9da01",
+			"88d6aa9ae997d2eb20",
 		},
 		{
 			"lookup resources 2 with nested context",
@@ -312,7 +312,7 @@ func TestStableCacheKeys(t *testing.T) {
 					}(),
 				}, computeBothHashes)
 			},
-			"84d8d38ff9fadbb36d",
+			"acfa83dc99cceed22f",
 		},
 		{
 			"lookup resources 2 with empty cursor",
@@ -328,7 +328,7 @@ func TestStableCacheKeys(t *testing.T) {
 					OptionalCursor: &v1.Cursor{},
 				}, computeBothHashes)
 			},
-			"f49fbafef9c489abe601",
+			"ac948ba58de9a59473",
 		},
 		{
 			"lookup resources 2 with non-empty cursor",
@@ -346,7 +346,7 @@ func TestStableCacheKeys(t *testing.T) {
 					},
 				}, computeBothHashes)
 			},
-			"f09894c0d9a0b8ff7f",
+			"e898c09ec2fa8f9ce801",
 		},
 		{
 			"lookup resources 2 with different cursor",
@@ -364,7 +364,7 @@ func TestStableCacheKeys(t *testing.T) {
 					},
 				}, computeBothHashes)
 			},
-			"badfd7c59cdbcef671",
+			"90eab0bfa0fc8cac0e",
 		},
 		{
 			"lookup resources 2 with different terminal subject",
@@ -382,7 +382,7 @@ func TestStableCacheKeys(t *testing.T) {
 					},
 				}, computeBothHashes)
 			},
-			"8787b685e9cea993a901",
+			"838e80b0a793a69439",
 		},
 		{
 			"lookup resources 2 with different subject IDs",
@@ -400,7 +400,7 @@ func TestStableCacheKeys(t *testing.T) {
 					},
 				}, computeBothHashes)
 			},
-			"f4d6d884eaa5e4b4ed01",
+			"90b3d3e2eab5c7fbce01",
 		},
 	}
 
@@ -648,5 +648,5 @@ func TestComputeContextHash(t *testing.T) {
 		}(),
 	}, computeBothHashes)
 
-	require.Equal(t, "81aab1c790f0be947d", hex.EncodeToString(result.StableSumAsBytes()))
+	require.Equal(t, "90de81d3e5cfb0c3ea01", hex.EncodeToString(result.StableSumAsBytes()))
 }
@@ -13,6 +13,11 @@ type Handler interface {
 	// CheckCacheKey computes the caching key for a Check operation.
 	CheckCacheKey(ctx context.Context, req *v1.DispatchCheckRequest) (DispatchCacheKey, error)
 
+	// CheckRelationCacheKey computes the caching key for a Check operation using only the
+	// relation name, even if a canonical key is available for the relation. Used to measure
+	// how often canonical keys produce cache hits the relation name alone would have missed.
+	CheckRelationCacheKey(ctx context.Context, req *v1.DispatchCheckRequest) (DispatchCacheKey, error)
+
 	// LookupResources2CacheKey computes the caching key for a LookupResources2 operation.
 	LookupResources2CacheKey(ctx context.Context, req *v1.DispatchLookupResources2Request) (DispatchCacheKey, error)
 
@@ -59,6 +64,10 @@ func (b baseKeyHandler) ExpandCacheKey(_ context.Context, req *v1.DispatchExpand
 	return expandRequestToKey(req, computeBothHashes), nil
 }
 
+func (b baseKeyHandler) CheckRelationCacheKey(_ context.Context, req *v1.DispatchCheckRequest) (DispatchCacheKey, error) {
+	return checkRequestToKey(req, computeBothHashes), nil
+}
+
 func (b baseKeyHandler) CheckDispatchKey(_ context.Context, req *v1.DispatchCheckRequest) ([]byte, error) {
 	return checkRequestToKey(req, computeOnlyStableHash).StableSumAsBytes(), nil
 }
@@ -152,12 +152,8 @@ func computeCheck(ctx context.Context,
 			return false, err
 		}
 
-		for _, resourceID := range resourceIDsToCheck {
-			computed, err := computeCaveatedCheckResult(ctx, caveatRunner, params, resourceID, checkResult)
-			if err != nil {
-				return false, err
-			}
-			results[resourceID] = computed
+		if err := computeCaveatedCheckResults(ctx, caveatRunner, params, resourceIDsToCheck, checkResult, results); err != nil {
+			return false, err
 		}
 
 		return true, nil
@@ -165,42 +161,78 @@ func computeCheck(ctx context.Context,
 	return results, metadata, debugInfo, err
 }
 
-func computeCaveatedCheckResult(ctx context.Context, runner *cexpr.CaveatRunner, params CheckParameters, resourceID string, checkResult *v1.DispatchCheckResponse) (*v1.ResourceCheckResult, error) {
-	result, ok := checkResult.ResultsByResourceId[resourceID]
-	if !ok {
-		return &v1.ResourceCheckResult{
-			Membership: v1.ResourceCheckResult_NOT_MEMBER,
-		}, nil
+// caveatEvalConcurrency bounds the number of goroutines used to evaluate the caveats for a
+// single chunk of checked resources.
+const caveatEvalConcurrency = 8
+
+func computeCaveatedCheckResults(ctx context.Context, runner *cexpr.CaveatRunner, params CheckParameters, resourceIDs []string, checkResult *v1.DispatchCheckResponse, results map[string]*v1.ResourceCheckResult) error {
+	// Collect the resources whose results are conditioned on a caveat; the caveats for the
+	// chunk are evaluated as a single batch, since the items are independent.
+	batchItems := make([]cexpr.EvalItem, 0, len(resourceIDs))
+	batchedResourceIDs := make([]string, 0, len(resourceIDs))
+
+	for _, resourceID := range resourceIDs {
+		result, ok := checkResult.ResultsByResourceId[resourceID]
+		if !ok {
+			results[resourceID] = &v1.ResourceCheckResult{
+				Membership: v1.ResourceCheckResult_NOT_MEMBER,
+			}
+			continue
+		}
+
+		if result.Membership == v1.ResourceCheckResult_MEMBER {
+			results[resourceID] = result
+			continue
+		}
+
+		batchItems = append(batchItems, cexpr.EvalItem{
+			Expression: result.Expression,
+			Context:    params.CaveatContext,
+		})
+		batchedResourceIDs = append(batchedResourceIDs, resourceID)
 	}
 
-	if result.Membership == v1.ResourceCheckResult_MEMBER {
-		return result, nil
+	if len(batchItems) == 0 {
+		return nil
 	}
 
 	ds := datastoremw.MustFromContext(ctx)
 	reader := ds.SnapshotReader(params.AtRevision)
 
-	caveatResult, err := runner.RunCaveatExpression(ctx, result.Expression, params.CaveatContext, reader, cexpr.RunCaveatExpressionNoDebugging)
+	evalResults, err := runner.EvaluateBatch(ctx, batchItems, reader, caveatEvalConcurrency)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if caveatResult.IsPartial() {
-		missingFields, _ := caveatResult.MissingVarNames()
-		return &v1.ResourceCheckResult{
-			Membership:        v1.ResourceCheckResult_CAVEATED_MEMBER,
-			Expression:        result.Expression,
-			MissingExprFields: missingFields,
-		}, nil
-	}
+	for index, evalResult := range evalResults {
+		if evalResult.Err != nil {
+			return evalResult.Err
+		}
+
+		resourceID := batchedResourceIDs[index]
+		caveatResult := evalResult.Result
+
+		if caveatResult.IsPartial() {
+			missingFields, _ := caveatResult.MissingVarNames()
+			results[resourceID] = &v1.ResourceCheckResult{
+				Membership:        v1.ResourceCheckResult_CAVEATED_MEMBER,
+				Expression:        checkResult.ResultsByResourceId[resourceID].Expression,
+				MissingExprFields: missingFields,
+			}
+			continue
+		}
 
-	if caveatResult.Value() {
-		return &v1.ResourceCheckResult{
-			Membership: v1.ResourceCheckResult_MEMBER,
-		}, nil
+		if caveatResult.Value() {
+			results[resourceID] = &v1.ResourceCheckResult{
+				Membership: v1.ResourceCheckResult_MEMBER,
+			}
+			continue
+		}
+
+		results[resourceID] = &v1.ResourceCheckResult{
+			Membership: v1.ResourceCheckResult_NOT_MEMBER,
+		}
 	}
 
-	return &v1.ResourceCheckResult{
-		Membership: v1.ResourceCheckResult_NOT_MEMBER,
-	}, nil
+	return nil
 }
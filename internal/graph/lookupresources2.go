@@ -337,37 +337,64 @@ func (crr *CursoredLookupResources2) redispatchOrReportOverDatabaseQuery(
 				return nil, err
 			}
 
-			// Chunk based on the FilterMaximumIDCount, to ensure we never send more than that amount of
-			// results to a downstream dispatch.
-			rsm := newResourcesSubjectMap2WithCapacity(config.sourceResourceType, uint32(crr.dispatchChunkSize))
-			toBeHandled := make([]itemAndPostCursor[dispatchableResourcesSubjectMap2], 0)
-			currentCursor := queryCursor
-			caveatRunner := caveats.NewCaveatRunner(crr.caveatTypeSet)
+			// Drain the found relationships, collecting any caveats to be evaluated over the
+			// request's context as a single bounded-concurrency batch.
+			rels := make([]tuple.Relationship, 0, crr.dispatchChunkSize)
+			batchItems := make([]caveats.EvalItem, 0)
+			requestContext := config.parentRequest.Context.AsMap()
 
 			for rel, err := range it {
 				if err != nil {
 					return nil, err
 				}
 
+				rels = append(rels, rel)
+				if rel.OptionalCaveat != nil && rel.OptionalCaveat.CaveatName != "" {
+					batchItems = append(batchItems, caveats.EvalItem{
+						Expression: caveats.CaveatAsExpr(rel.OptionalCaveat),
+						Context:    requestContext,
+					})
+				}
+			}
+
+			var evalResults []caveats.EvalResult
+			if len(batchItems) > 0 {
+				caveatRunner := caveats.NewCaveatRunner(crr.caveatTypeSet)
+				results, err := caveatRunner.EvaluateBatch(ctx, batchItems, config.reader, int(config.concurrencyLimit))
+				if err != nil {
+					return nil, err
+				}
+				evalResults = results
+			}
+
+			// Chunk based on the FilterMaximumIDCount, to ensure we never send more than that amount of
+			// results to a downstream dispatch.
+			rsm := newResourcesSubjectMap2WithCapacity(config.sourceResourceType, uint32(crr.dispatchChunkSize))
+			toBeHandled := make([]itemAndPostCursor[dispatchableResourcesSubjectMap2], 0)
+			currentCursor := queryCursor
+			evalIndex := 0
+
+			for _, rel := range rels {
 				var missingContextParameters []string
 
-				// If a caveat exists on the relationship, run it and filter the results, marking those that have missing context.
+				// If a caveat exists on the relationship, use its evaluated result to filter the
+				// results, marking those that have missing context.
 				if rel.OptionalCaveat != nil && rel.OptionalCaveat.CaveatName != "" {
-					caveatExpr := caveats.CaveatAsExpr(rel.OptionalCaveat)
-					runResult, err := caveatRunner.RunCaveatExpression(ctx, caveatExpr, config.parentRequest.Context.AsMap(), config.reader, caveats.RunCaveatExpressionNoDebugging)
-					if err != nil {
-						return nil, err
+					evalResult := evalResults[evalIndex]
+					evalIndex++
+					if evalResult.Err != nil {
+						return nil, evalResult.Err
 					}
 
 					// If a partial result is returned, collect the missing context parameters.
-					if runResult.IsPartial() {
-						missingNames, err := runResult.MissingVarNames()
+					if evalResult.Result.IsPartial() {
+						missingNames, err := evalResult.Result.MissingVarNames()
 						if err != nil {
 							return nil, err
 						}
 
 						missingContextParameters = missingNames
-					} else if !runResult.Value() {
+					} else if !evalResult.Result.Value() {
 						// If the run result shows the caveat does not apply, skip. This shears the tree of results early.
 						continue
 					}
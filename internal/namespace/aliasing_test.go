@@ -72,7 +72,7 @@ func TestAliasing(t *testing.T) {
 					ns.ComputedUserset("edit"),
 				)),
 			),
-			"under definition `document`, there exists a cycle in permissions: edit, owner",
+			"under definition `document`, there exists a cycle in permissions: document#edit -> document#owner -> document#edit",
 			map[string]string{},
 		},
 		{
@@ -89,7 +89,7 @@ func TestAliasing(t *testing.T) {
 					ns.ComputedUserset("edit"),
 				)),
 			),
-			"under definition `document`, there exists a cycle in permissions: edit, foo, owner",
+			"under definition `document`, there exists a cycle in permissions: document#edit -> document#owner -> document#foo -> document#edit",
 			map[string]string{},
 		},
 		{
@@ -154,6 +154,7 @@ func TestAliasing(t *testing.T) {
 				ns.MustRelation("viewer", nil),
 				ns.MustRelation("edit", ns.Intersection(
 					ns.ComputedUserset("owner"),
+					ns.ComputedUserset("viewer"),
 				)),
 				ns.MustRelation("view", ns.Union(
 					ns.ComputedUserset("viewer"),
@@ -170,6 +171,49 @@ func TestAliasing(t *testing.T) {
 			"",
 			map[string]string{},
 		},
+		{
+			"idempotent intersection aliasing",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("viewer", nil),
+				ns.MustRelation("first", ns.Intersection(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("viewer"),
+				)),
+			),
+			"",
+			map[string]string{
+				"first": "viewer",
+			},
+		},
+		{
+			"idempotent union aliasing",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("viewer", nil),
+				ns.MustRelation("first", ns.Union(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("viewer"),
+				)),
+			),
+			"",
+			map[string]string{
+				"first": "viewer",
+			},
+		},
+		{
+			"exclusion is never an alias",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("viewer", nil),
+				ns.MustRelation("first", ns.Exclusion(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("viewer"),
+				)),
+			),
+			"",
+			map[string]string{},
+		},
 		{
 			"non-aliasing with nil",
 			ns.Namespace(
@@ -207,15 +251,16 @@ func TestAliasing(t *testing.T) {
 
 			ctx := t.Context()
 			vdef, terr := def.Validate(ctx)
+			if tc.expectedError != "" {
+				// Permission cycles are reported by validation, with the full cycle path.
+				require.Equal(tc.expectedError, terr.Error())
+				return
+			}
 			require.NoError(terr)
 
 			computed, aerr := computePermissionAliases(vdef)
-			if tc.expectedError != "" {
-				require.Equal(tc.expectedError, aerr.Error())
-			} else {
-				require.NoError(aerr)
-				require.Equal(tc.expectedAliasMap, computed)
-			}
+			require.NoError(aerr)
+			require.Equal(tc.expectedAliasMap, computed)
 		})
 	}
 }
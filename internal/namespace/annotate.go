@@ -3,14 +3,15 @@ package namespace
 import "github.com/authzed/spicedb/pkg/schema"
 
 // AnnotateNamespace annotates the namespace in the type system with computed aliasing and cache key
-// metadata for more efficient dispatching.
+// metadata for more efficient dispatching. The cache keys are namespace-qualified, as dispatch
+// caches span definitions.
 func AnnotateNamespace(def *schema.ValidatedDefinition) error {
 	aliases, aerr := computePermissionAliases(def)
 	if aerr != nil {
 		return aerr
 	}
 
-	cacheKeys, cerr := computeCanonicalCacheKeys(def, aliases)
+	cacheKeys, cerr := computeNamespaceQualifiedCacheKeys(def, aliases)
 	if cerr != nil {
 		return cerr
 	}
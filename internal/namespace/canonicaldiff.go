@@ -0,0 +1,141 @@
+package namespace
+
+import (
+	"sort"
+
+	"github.com/authzed/spicedb/pkg/schema"
+)
+
+// PermissionCanonicalStatus describes how a permission's canonical cache key
+// changed between two versions of a namespace.
+type PermissionCanonicalStatus string
+
+const (
+	// PermissionCanonicalUnchanged indicates the permission's canonical key is
+	// the same in both versions, i.e. the permission is semantically unchanged
+	// even if its expression was reformatted or reordered.
+	PermissionCanonicalUnchanged PermissionCanonicalStatus = "unchanged"
+
+	// PermissionCanonicalChanged indicates the permission exists in both
+	// versions but its canonical key differs, i.e. its behavior changed.
+	PermissionCanonicalChanged PermissionCanonicalStatus = "changed"
+
+	// PermissionCanonicalAdded indicates the permission only exists in the
+	// updated version.
+	PermissionCanonicalAdded PermissionCanonicalStatus = "added"
+
+	// PermissionCanonicalRemoved indicates the permission only exists in the
+	// existing version.
+	PermissionCanonicalRemoved PermissionCanonicalStatus = "removed"
+)
+
+// CanonicalDiff holds the semantic differences between two versions of a
+// namespace, as determined by comparing the canonical cache keys of their
+// permissions.
+type CanonicalDiff struct {
+	// Permissions maps each permission name found in either version to its
+	// canonical status.
+	Permissions map[string]PermissionCanonicalStatus
+
+	// NewlyAliased holds the names of permissions that are aliases of another
+	// permission in the updated version but were not in the existing version.
+	NewlyAliased []string
+
+	// NoLongerAliased holds the names of permissions that were aliases of
+	// another permission in the existing version but are not in the updated
+	// version.
+	NoLongerAliased []string
+}
+
+// DiffCanonicalKeys computes the semantic diff between two validated versions
+// of a namespace by comparing the canonical cache keys of their permissions.
+// Permissions whose expressions were merely reformatted or reordered report as
+// unchanged; only behavioral changes are surfaced.
+func DiffCanonicalKeys(existing *schema.ValidatedDefinition, updated *schema.ValidatedDefinition) (*CanonicalDiff, error) {
+	existingKeys, existingAliases, err := canonicalKeysAndAliases(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedKeys, updatedAliases, err := canonicalKeysAndAliases(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make(map[string]PermissionCanonicalStatus, len(updatedKeys))
+	for name, updatedKey := range updatedKeys {
+		existingKey, ok := existingKeys[name]
+		switch {
+		case !ok:
+			permissions[name] = PermissionCanonicalAdded
+
+		case existingKey != updatedKey:
+			permissions[name] = PermissionCanonicalChanged
+
+		default:
+			permissions[name] = PermissionCanonicalUnchanged
+		}
+	}
+
+	for name := range existingKeys {
+		if _, ok := updatedKeys[name]; !ok {
+			permissions[name] = PermissionCanonicalRemoved
+		}
+	}
+
+	newlyAliased := make([]string, 0)
+	for name := range updatedAliases {
+		if _, wasAliased := existingAliases[name]; wasAliased {
+			continue
+		}
+		if _, existed := existingKeys[name]; existed {
+			newlyAliased = append(newlyAliased, name)
+		}
+	}
+	sort.Strings(newlyAliased)
+
+	noLongerAliased := make([]string, 0)
+	for name := range existingAliases {
+		if _, isAliased := updatedAliases[name]; isAliased {
+			continue
+		}
+		if _, exists := updatedKeys[name]; exists {
+			noLongerAliased = append(noLongerAliased, name)
+		}
+	}
+	sort.Strings(noLongerAliased)
+
+	return &CanonicalDiff{
+		Permissions:     permissions,
+		NewlyAliased:    newlyAliased,
+		NoLongerAliased: noLongerAliased,
+	}, nil
+}
+
+// canonicalKeysAndAliases computes the canonical cache keys for the
+// permissions of the definition, along with its permission alias map.
+//
+// The keys are computed with an *empty* alias map: aliased permissions are
+// excluded from the BDD variable map, so computing with aliases applied would
+// shift the variable layout whenever a permission becomes (or stops being) an
+// alias, spuriously reporting every permission in the namespace as changed.
+func canonicalKeysAndAliases(def *schema.ValidatedDefinition) (map[string]string, map[string]string, error) {
+	aliases, err := computePermissionAliases(def)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cacheKeys, err := computeCanonicalCacheKeys(def, map[string]string{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	permissionKeys := make(map[string]string, len(cacheKeys))
+	for name, key := range cacheKeys {
+		if def.IsPermission(name) {
+			permissionKeys[name] = key
+		}
+	}
+
+	return permissionKeys, aliases, nil
+}
@@ -0,0 +1,210 @@
+package namespace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/internal/datastore/dsfortesting"
+	"github.com/authzed/spicedb/internal/datastore/memdb"
+	ns "github.com/authzed/spicedb/pkg/namespace"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/schema"
+)
+
+func TestDiffCanonicalKeys(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		existing                *core.NamespaceDefinition
+		updated                 *core.NamespaceDefinition
+		expectedPermissions     map[string]PermissionCanonicalStatus
+		expectedNewlyAliased    []string
+		expectedNoLongerAliased []string
+	}{
+		{
+			"pure reordering reports no changes",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("owner", nil),
+				ns.MustRelation("viewer", nil),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("owner"),
+				)),
+			),
+			ns.Namespace(
+				"document",
+				ns.MustRelation("owner", nil),
+				ns.MustRelation("viewer", nil),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("owner"),
+					ns.ComputedUserset("viewer"),
+				)),
+			),
+			map[string]PermissionCanonicalStatus{
+				"view": PermissionCanonicalUnchanged,
+			},
+			[]string{},
+			[]string{},
+		},
+		{
+			"operand swap in an exclusion reports a change",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("owner", nil),
+				ns.MustRelation("viewer", nil),
+				ns.MustRelation("view", ns.Exclusion(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("owner"),
+				)),
+			),
+			ns.Namespace(
+				"document",
+				ns.MustRelation("owner", nil),
+				ns.MustRelation("viewer", nil),
+				ns.MustRelation("view", ns.Exclusion(
+					ns.ComputedUserset("owner"),
+					ns.ComputedUserset("viewer"),
+				)),
+			),
+			map[string]PermissionCanonicalStatus{
+				"view": PermissionCanonicalChanged,
+			},
+			[]string{},
+			[]string{},
+		},
+		{
+			"added and removed permissions",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("owner", nil),
+				ns.MustRelation("viewer", nil),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+				)),
+				ns.MustRelation("old_edit", ns.Union(
+					ns.ComputedUserset("owner"),
+				)),
+			),
+			ns.Namespace(
+				"document",
+				ns.MustRelation("owner", nil),
+				ns.MustRelation("viewer", nil),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+				)),
+				ns.MustRelation("edit", ns.Union(
+					ns.ComputedUserset("owner"),
+				)),
+			),
+			map[string]PermissionCanonicalStatus{
+				"view":     PermissionCanonicalUnchanged,
+				"edit":     PermissionCanonicalAdded,
+				"old_edit": PermissionCanonicalRemoved,
+			},
+			[]string{},
+			[]string{},
+		},
+		{
+			"permission becoming an alias",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("owner", nil),
+				ns.MustRelation("viewer", nil),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("owner"),
+				)),
+				ns.MustRelation("can_view", ns.Union(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("owner"),
+				)),
+			),
+			ns.Namespace(
+				"document",
+				ns.MustRelation("owner", nil),
+				ns.MustRelation("viewer", nil),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("owner"),
+				)),
+				ns.MustRelation("can_view", ns.Union(
+					ns.ComputedUserset("view"),
+				)),
+			),
+			map[string]PermissionCanonicalStatus{
+				"view":     PermissionCanonicalUnchanged,
+				"can_view": PermissionCanonicalChanged,
+			},
+			[]string{"can_view"},
+			[]string{},
+		},
+		{
+			"permission no longer an alias",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("owner", nil),
+				ns.MustRelation("viewer", nil),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+				)),
+				ns.MustRelation("can_view", ns.Union(
+					ns.ComputedUserset("view"),
+				)),
+			),
+			ns.Namespace(
+				"document",
+				ns.MustRelation("owner", nil),
+				ns.MustRelation("viewer", nil),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+				)),
+				ns.MustRelation("can_view", ns.Union(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("owner"),
+				)),
+			),
+			map[string]PermissionCanonicalStatus{
+				"view":     PermissionCanonicalUnchanged,
+				"can_view": PermissionCanonicalChanged,
+			},
+			[]string{},
+			[]string{"can_view"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			ds, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+			require.NoError(err)
+
+			ctx := t.Context()
+
+			lastRevision, err := ds.HeadRevision(ctx)
+			require.NoError(err)
+
+			ts := schema.NewTypeSystem(schema.ResolverForDatastoreReader(ds.SnapshotReader(lastRevision)))
+
+			existingDef, err := schema.NewDefinition(ts, tc.existing)
+			require.NoError(err)
+
+			existingVDef, verr := existingDef.Validate(ctx)
+			require.NoError(verr)
+
+			updatedDef, err := schema.NewDefinition(ts, tc.updated)
+			require.NoError(err)
+
+			updatedVDef, verr := updatedDef.Validate(ctx)
+			require.NoError(verr)
+
+			diff, err := DiffCanonicalKeys(existingVDef, updatedVDef)
+			require.NoError(err)
+
+			require.Equal(tc.expectedPermissions, diff.Permissions)
+			require.Equal(tc.expectedNewlyAliased, diff.NewlyAliased)
+			require.Equal(tc.expectedNoLongerAliased, diff.NoLongerAliased)
+		})
+	}
+}
@@ -2,7 +2,12 @@ package namespace
 
 import (
 	"encoding/hex"
+	"fmt"
+	"hash"
 	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/dalzilio/rudd"
 
@@ -10,10 +15,39 @@ import (
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 	"github.com/authzed/spicedb/pkg/schema"
 	"github.com/authzed/spicedb/pkg/spiceerrors"
+	"github.com/authzed/spicedb/pkg/tuple"
 )
 
 const computedKeyPrefix = "%"
 
+// CanonicalKeyVersion is the version of the canonical cache key format. It is
+// folded into the hash input and embedded in each rendered key, so any
+// deliberate change to the hashing algorithm or variable assignment must bump
+// this constant, which changes every derived cache key at once rather than
+// silently colliding with entries computed under the old algorithm.
+const CanonicalKeyVersion = 1
+
+// ParseCanonicalKey splits a computed canonical cache key into its version and
+// hash portions. Canonical keys for pure relations are simply the relation's
+// name and carry no version; those are rejected.
+func ParseCanonicalKey(key string) (version int, hash string, err error) {
+	rest, ok := strings.CutPrefix(key, computedKeyPrefix)
+	if !ok {
+		return 0, "", fmt.Errorf("canonical key `%s` is not a computed key", key)
+	}
+
+	versionStr, hash, ok := strings.Cut(rest, ":")
+	if !ok {
+		return 0, "", fmt.Errorf("canonical key `%s` is missing a version", key)
+	}
+
+	version, err = strconv.Atoi(versionStr)
+	if err != nil {
+		return 0, "", fmt.Errorf("canonical key `%s` has a malformed version: %w", key, err)
+	}
+	return version, hash, nil
+}
+
 // computeCanonicalCacheKeys computes a map from permission name to associated canonicalized
 // cache key for each non-aliased permission in the given type system's namespace.
 //
@@ -55,7 +89,38 @@ const computedKeyPrefix = "%"
 // representing the same *logical* expressions for a permission, even if the relations have
 // different names.
 func computeCanonicalCacheKeys(typeDef *schema.ValidatedDefinition, aliasMap map[string]string) (map[string]string, error) {
-	varMap, err := buildBddVarMap(typeDef.Namespace().Relation, aliasMap)
+	return computeCanonicalCacheKeysForVersion(typeDef, aliasMap, CanonicalKeyVersion, unqualifiedCanonicalKeys)
+}
+
+// computeNamespaceQualifiedCacheKeys computes the canonical cache keys with the containing
+// namespace's name folded into the hash input. Structurally-identical permissions in different
+// definitions then receive distinct keys, making the result safe for caches keyed across
+// definitions; within-namespace comparisons (such as alias detection and canonical diffing)
+// should keep using the unqualified computeCanonicalCacheKeys so identical expressions continue
+// to compare equal.
+func computeNamespaceQualifiedCacheKeys(typeDef *schema.ValidatedDefinition, aliasMap map[string]string) (map[string]string, error) {
+	return computeCanonicalCacheKeysForVersion(typeDef, aliasMap, CanonicalKeyVersion, namespaceQualifiedCanonicalKeys)
+}
+
+// canonicalKeyQualification controls whether the containing namespace's name is folded into
+// computed canonical keys.
+type canonicalKeyQualification int
+
+const (
+	// unqualifiedCanonicalKeys hashes only the expression structure and relation traits, so
+	// structurally-identical permissions share a key across definitions.
+	unqualifiedCanonicalKeys canonicalKeyQualification = iota
+
+	// namespaceQualifiedCanonicalKeys additionally hashes the namespace name, so keys never
+	// collide across definitions.
+	namespaceQualifiedCanonicalKeys
+)
+
+// computeCanonicalCacheKeysForVersion computes the canonical cache keys with
+// the given version folded into the hash input; split out so tests can assert
+// that a version bump changes every derived key.
+func computeCanonicalCacheKeysForVersion(typeDef *schema.ValidatedDefinition, aliasMap map[string]string, version int, qualification canonicalKeyQualification) (map[string]string, error) {
+	varMap, err := buildBddVarMap(typeDef.Namespace(), aliasMap)
 	if err != nil {
 		return nil, err
 	}
@@ -69,8 +134,18 @@ func computeCanonicalCacheKeys(typeDef *schema.ValidatedDefinition, aliasMap map
 		return nil, err
 	}
 
-	// For each permission, build a canonicalized cache key based on its expression.
+	// Incorporate each relation's allowed subject types, including caveat and
+	// expiration traits, into the hash input: declaration order ties each
+	// digest entry to its BDD variable, and schema changes that only alter
+	// caveat bindings still produce new canonical keys.
+	traitDigest := relationTraitDigest(typeDef.Namespace().Relation)
+
+	// For each permission, build the canonical serialization of its expression.
+	// The serialization is kept (rather than hashed immediately) so that hash
+	// collisions between semantically different expressions can be detected.
+	memo := newCanonicalizationMemo()
 	cacheKeys := make(map[string]string, len(typeDef.Namespace().Relation))
+	serializations := make(map[string]string, len(typeDef.Namespace().Relation))
 	for _, rel := range typeDef.Namespace().Relation {
 		rewrite := rel.GetUsersetRewrite()
 		if rewrite == nil {
@@ -80,30 +155,249 @@ func computeCanonicalCacheKeys(typeDef *schema.ValidatedDefinition, aliasMap map
 			continue
 		}
 
-		hasher := fnv.New64a()
-		node, err := convertRewriteToBdd(rel, bdd, rewrite, varMap)
+		var serialized strings.Builder
+		if _, err := fmt.Fprintf(&serialized, "v%d:%s|", version, traitDigest); err != nil {
+			return nil, err
+		}
+
+		if qualification == namespaceQualifiedCanonicalKeys {
+			if _, err := fmt.Fprintf(&serialized, "ns:%s|", typeDef.Namespace().Name); err != nil {
+				return nil, err
+			}
+		}
+
+		node, err := convertRewriteToBdd(rel, bdd, rewrite, varMap, memo)
 		if err != nil {
 			return nil, err
 		}
 
-		bdd.Print(hasher, node)
-		cacheKeys[rel.Name] = computedKeyPrefix + hex.EncodeToString(hasher.Sum(nil))
+		bdd.Print(&serialized, node)
+		serializations[rel.Name] = serialized.String()
+	}
+
+	// Hash each serialization and detect collisions: two *different*
+	// serializations mapping to the same 64-bit sum. With tens of thousands of
+	// permissions the birthday bound makes this plausible, and a collision
+	// would cause cross-permission cache pollution.
+	sums := make(map[string]string, len(serializations))
+	serializationForSum := make(map[string]string, len(serializations))
+	collidedSums := make(map[string]struct{})
+	for relName, serialized := range serializations {
+		sum, ok := memo.sumsBySerialization[serialized]
+		if !ok {
+			hasher := newCanonicalKeyHasher()
+			if _, err := hasher.Write([]byte(serialized)); err != nil {
+				return nil, err
+			}
+
+			sum = hex.EncodeToString(hasher.Sum(nil))
+			memo.sumsBySerialization[serialized] = sum
+		}
+		sums[relName] = sum
+		if existing, ok := serializationForSum[sum]; ok && existing != serialized {
+			collidedSums[sum] = struct{}{}
+		} else {
+			serializationForSum[sum] = serialized
+		}
+	}
+
+	for relName, serialized := range serializations {
+		sum := sums[relName]
+		if _, collided := collidedSums[sum]; collided {
+			// Fall back to a 128-bit hash for the colliding entries.
+			hasher := fnv.New128a()
+			if _, err := hasher.Write([]byte(serialized)); err != nil {
+				return nil, err
+			}
+			sum = hex.EncodeToString(hasher.Sum(nil))
+		}
+		cacheKeys[relName] = computedKeyPrefix + strconv.Itoa(version) + ":" + sum
 	}
 
 	return cacheKeys, nil
 }
 
-func convertRewriteToBdd(relation *core.Relation, bdd *rudd.BDD, rewrite *core.UsersetRewrite, varMap bddVarMap) (rudd.Node, error) {
+// newCanonicalKeyHasher constructs the hasher used for canonical cache keys;
+// a variable so tests can inject a colliding hasher.
+var newCanonicalKeyHasher = func() hash.Hash {
+	return fnv.New64a()
+}
+
+// ExpressionIsEmpty returns true if the given relation's rewrite expression
+// normalizes to the empty set (for example `viewer - viewer` or `viewer & nil`),
+// meaning the permission can never match. Relations without a rewrite are
+// never empty.
+func ExpressionIsEmpty(nsDef *core.NamespaceDefinition, relation *core.Relation) (bool, error) {
+	rewrite := relation.GetUsersetRewrite()
+	if rewrite == nil {
+		return false, nil
+	}
+
+	varMap, err := buildBddVarMap(nsDef, map[string]string{})
+	if err != nil {
+		return false, err
+	}
+
+	bdd, err := rudd.New(varMap.Len())
+	if err != nil {
+		return false, err
+	}
+
+	node, err := convertRewriteToBdd(relation, bdd, rewrite, varMap, newCanonicalizationMemo())
+	if err != nil {
+		return false, err
+	}
+	return bdd.Equal(node, bdd.False()), nil
+}
+
+// canonicalizationMemo caches work shared across the permissions of a single
+// canonicalization invocation: the BDD nodes built for nested sub-expressions
+// and the hash sums computed for full serializations, each keyed by the
+// serialized form. A namespace whose permissions all reference the same large
+// nested rewrite therefore converts and hashes that subtree once, rather than
+// once per referencing permission. The memo never outlives the invocation, as
+// BDD nodes are only meaningful against the invocation's own rudd instance.
+type canonicalizationMemo struct {
+	nodesBySubExpression map[string]rudd.Node
+	sumsBySerialization  map[string]string
+}
+
+func newCanonicalizationMemo() *canonicalizationMemo {
+	return &canonicalizationMemo{
+		nodesBySubExpression: map[string]rudd.Node{},
+		sumsBySerialization:  map[string]string{},
+	}
+}
+
+// nodeForSubExpression returns the BDD node for a nested rewrite, building it
+// at most once per distinct sub-expression. Nodes are keyed by the rewrite's
+// serialized proto form, so structurally-identical subtrees repeated within or
+// across permissions convert a single time. Subtrees differing only in source
+// position metadata miss the memo, which costs speed but never correctness.
+func (memo *canonicalizationMemo) nodeForSubExpression(relation *core.Relation, bdd *rudd.BDD, rewrite *core.UsersetRewrite, varMap bddVarMap) (rudd.Node, error) {
+	key, err := rewrite.MarshalVT()
+	if err != nil {
+		return nil, err
+	}
+
+	if node, ok := memo.nodesBySubExpression[string(key)]; ok {
+		return node, nil
+	}
+
+	node, err := convertRewriteToBdd(relation, bdd, rewrite, varMap, memo)
+	if err != nil {
+		return nil, err
+	}
+
+	memo.nodesBySubExpression[string(key)] = node
+	return node, nil
+}
+
+// CanonicalCacheKeys computes the relation-name-to-canonical-key map for the
+// given validated definition, with permission aliases resolved so aliased
+// permissions share a key. This is the exported combination of
+// computePermissionAliases and computeCanonicalCacheKeys.
+func CanonicalCacheKeys(typeDef *schema.ValidatedDefinition) (map[string]string, error) {
+	aliases, err := computePermissionAliases(typeDef)
+	if err != nil {
+		return nil, err
+	}
+	return computeCanonicalCacheKeys(typeDef, aliases)
+}
+
+// computeGlobalCanonicalKeys computes a map from (namespace, permission) to a
+// canonical key usable across namespaces: structurally-identical permissions in
+// different definitions share a key when the namespaces referenced by their
+// relations also match. The within-namespace canonical key is incorporated
+// unchanged, so this is purely additive over computeCanonicalCacheKeys.
+func computeGlobalCanonicalKeys(typeDefs []*schema.ValidatedDefinition) (map[tuple.RelationReference]string, error) {
+	globalKeys := make(map[tuple.RelationReference]string)
+	for _, typeDef := range typeDefs {
+		nsName := typeDef.Namespace().Name
+
+		aliases, err := computePermissionAliases(typeDef)
+		if err != nil {
+			return nil, err
+		}
+
+		cacheKeys, err := computeCanonicalCacheKeys(typeDef, aliases)
+		if err != nil {
+			return nil, err
+		}
+
+		layout := relationTypeLayout(typeDef.Namespace())
+		for relName, cacheKey := range cacheKeys {
+			ref := tuple.RR(nsName, relName)
+			if !strings.HasPrefix(cacheKey, computedKeyPrefix) {
+				// Pure relations are keyed by bare name within their namespace;
+				// globally they must be namespace-qualified to avoid collisions.
+				globalKeys[ref] = nsName + "#" + cacheKey
+				continue
+			}
+
+			hasher := fnv.New64a()
+			if _, err := fmt.Fprintf(hasher, "%s|%s", cacheKey, layout); err != nil {
+				return nil, err
+			}
+			globalKeys[ref] = computedKeyPrefix + strconv.Itoa(CanonicalKeyVersion) + ":" + hex.EncodeToString(hasher.Sum(nil))
+		}
+	}
+	return globalKeys, nil
+}
+
+// relationTraitDigest serializes, in declaration order, each relation's
+// allowed subject types together with their caveat and expiration traits.
+func relationTraitDigest(relations []*core.Relation) string {
+	entries := make([]string, 0, len(relations))
+	for _, rel := range relations {
+		allowed := make([]string, 0, len(rel.GetTypeInformation().GetAllowedDirectRelations()))
+		for _, allowedRelation := range rel.GetTypeInformation().GetAllowedDirectRelations() {
+			allowed = append(allowed, schema.SourceForAllowedRelation(allowedRelation))
+		}
+		sort.Strings(allowed)
+		entries = append(entries, strings.Join(allowed, ","))
+	}
+	return strings.Join(entries, ";")
+}
+
+// relationTypeLayout fingerprints, in declaration order, the namespaces each
+// relation's allowed subject types reference. Declaration order is what assigns
+// BDD variable indexes, so two namespaces with the same layout give their
+// variables the same meaning, letting their structurally-identical permissions
+// share a global key.
+func relationTypeLayout(nsDef *core.NamespaceDefinition) string {
+	entries := make([]string, 0, len(nsDef.Relation))
+	for _, rel := range nsDef.Relation {
+		referenced := make(map[string]struct{})
+		for _, allowed := range rel.GetTypeInformation().GetAllowedDirectRelations() {
+			referenced[allowed.Namespace] = struct{}{}
+		}
+
+		names := make([]string, 0, len(referenced))
+		for name := range referenced {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		entries = append(entries, strings.Join(names, ","))
+	}
+	return strings.Join(entries, ";")
+}
+
+func convertRewriteToBdd(relation *core.Relation, bdd *rudd.BDD, rewrite *core.UsersetRewrite, varMap bddVarMap, memo *canonicalizationMemo) (rudd.Node, error) {
 	switch rw := rewrite.RewriteOperation.(type) {
 	case *core.UsersetRewrite_Union:
 		return convertToBdd(relation, bdd, rw.Union, bdd.Or, func(childIndex int, varIndex int) rudd.Node {
 			return bdd.Ithvar(varIndex)
-		}, varMap)
+		}, func(childIndex int) rudd.Node {
+			return bdd.False()
+		}, varMap, memo)
 
 	case *core.UsersetRewrite_Intersection:
 		return convertToBdd(relation, bdd, rw.Intersection, bdd.And, func(childIndex int, varIndex int) rudd.Node {
 			return bdd.Ithvar(varIndex)
-		}, varMap)
+		}, func(childIndex int) rudd.Node {
+			return bdd.False()
+		}, varMap, memo)
 
 	case *core.UsersetRewrite_Exclusion:
 		return convertToBdd(relation, bdd, rw.Exclusion, bdd.And, func(childIndex int, varIndex int) rudd.Node {
@@ -111,7 +405,12 @@ func convertRewriteToBdd(relation *core.Relation, bdd *rudd.BDD, rewrite *core.U
 				return bdd.Ithvar(varIndex)
 			}
 			return bdd.NIthvar(varIndex)
-		}, varMap)
+		}, func(childIndex int) rudd.Node {
+			if childIndex == 0 {
+				return bdd.False()
+			}
+			return bdd.True()
+		}, varMap, memo)
 
 	default:
 		return nil, spiceerrors.MustBugf("Unknown rewrite kind %v", rw)
@@ -121,9 +420,16 @@ func convertRewriteToBdd(relation *core.Relation, bdd *rudd.BDD, rewrite *core.U
 type (
 	combiner func(n ...rudd.Node) rudd.Node
 	builder  func(childIndex int, varIndex int) rudd.Node
+
+	// nilBuilder builds the node for a `nil` child: the empty set, i.e. the
+	// BDD `false` constant, negated in the same positions the builder negates.
+	// Mapping `nil` to a constant (rather than its own variable) normalizes
+	// trivially-empty expressions: `X - X` and `X & nil` reduce to `false`,
+	// and `X + nil` reduces to `X`, so equivalent spellings share a key.
+	nilBuilder func(childIndex int) rudd.Node
 )
 
-func convertToBdd(relation *core.Relation, bdd *rudd.BDD, so *core.SetOperation, combiner combiner, builder builder, varMap bddVarMap) (rudd.Node, error) {
+func convertToBdd(relation *core.Relation, bdd *rudd.BDD, so *core.SetOperation, combiner combiner, builder builder, nilBuilder nilBuilder, varMap bddVarMap, memo *canonicalizationMemo) (rudd.Node, error) {
 	values := make([]rudd.Node, 0, len(so.Child))
 	for index, childOneof := range so.Child {
 		switch child := childOneof.ChildType.(type) {
@@ -139,7 +445,7 @@ func convertToBdd(relation *core.Relation, bdd *rudd.BDD, so *core.SetOperation,
 			values = append(values, builder(index, cuIndex))
 
 		case *core.SetOperation_Child_UsersetRewrite:
-			node, err := convertRewriteToBdd(relation, bdd, child.UsersetRewrite, varMap)
+			node, err := memo.nodeForSubExpression(relation, bdd, child.UsersetRewrite, varMap)
 			if err != nil {
 				return nil, err
 			}
@@ -177,7 +483,7 @@ func convertToBdd(relation *core.Relation, bdd *rudd.BDD, so *core.SetOperation,
 			}
 
 		case *core.SetOperation_Child_XNil:
-			values = append(values, builder(index, varMap.Nil()))
+			values = append(values, nilBuilder(index))
 
 		default:
 			return nil, spiceerrors.MustBugf("unknown set operation child %T", child)
@@ -209,10 +515,6 @@ func (bvm bddVarMap) GetIntersectionArrow(tuplesetName string, relName string) (
 	return index, nil
 }
 
-func (bvm bddVarMap) Nil() int {
-	return len(bvm.varMap)
-}
-
 func (bvm bddVarMap) Get(relName string) (int, error) {
 	if alias, ok := bvm.aliasMap[relName]; ok {
 		return bvm.Get(alias)
@@ -226,12 +528,47 @@ func (bvm bddVarMap) Get(relName string) (int, error) {
 }
 
 func (bvm bddVarMap) Len() int {
-	return len(bvm.varMap) + 1 // +1 for `nil`
+	// The +1 is a reserved slot: `nil` historically had its own variable, and
+	// keeping the variable count stable keeps previously-computed keys for
+	// nil-free expressions stable.
+	return len(bvm.varMap) + 1
 }
 
-func buildBddVarMap(relations []*core.Relation, aliasMap map[string]string) (bddVarMap, error) {
+// buildBddVarMap assigns a BDD variable index to each non-aliased relation and each arrow found
+// in the namespace's expressions. Arrow references are resolved through the alias map before a
+// variable is assigned, so `parent_alias->view` and `parent->view` share a variable when
+// `parent_alias` aliases `parent`; the computed side of an arrow is only resolved when the
+// tupleset relation can hold subjects from this same namespace, as the alias map says nothing
+// about relations on other definitions. The raw arrow key is mapped to the same variable, so
+// lookups during conversion need no alias awareness.
+func buildBddVarMap(nsDef *core.NamespaceDefinition, aliasMap map[string]string) (bddVarMap, error) {
+	relationsByName := make(map[string]*core.Relation, len(nsDef.Relation))
+	for _, rel := range nsDef.Relation {
+		relationsByName[rel.Name] = rel
+	}
+
+	arrowKeys := func(tuplesetName string, computedName string, separator string) (string, string) {
+		rawKey := tuplesetName + separator + computedName
+		resolvedTupleset := resolveThroughAliases(aliasMap, tuplesetName)
+		if tuplesetAllowsSameNamespaceSubjects(nsDef.Name, relationsByName[resolvedTupleset]) {
+			computedName = resolveThroughAliases(aliasMap, computedName)
+		}
+		return rawKey, resolvedTupleset + separator + computedName
+	}
+
 	varMap := map[string]int{}
-	for _, rel := range relations {
+	addArrowKey := func(rawKey string, resolvedKey string) {
+		index, ok := varMap[resolvedKey]
+		if !ok {
+			index = len(varMap)
+			varMap[resolvedKey] = index
+		}
+		if rawKey != resolvedKey {
+			varMap[rawKey] = index
+		}
+	}
+
+	for _, rel := range nsDef.Relation {
 		if _, ok := aliasMap[rel.Name]; ok {
 			continue
 		}
@@ -246,27 +583,22 @@ func buildBddVarMap(relations []*core.Relation, aliasMap map[string]string) (bdd
 		_, err := graph.WalkRewrite(rewrite, func(childOneof *core.SetOperation_Child) (any, error) {
 			switch child := childOneof.ChildType.(type) {
 			case *core.SetOperation_Child_TupleToUserset:
-				key := child.TupleToUserset.Tupleset.Relation + "->" + child.TupleToUserset.ComputedUserset.Relation
-				if _, ok := varMap[key]; !ok {
-					varMap[key] = len(varMap)
-				}
+				addArrowKey(arrowKeys(child.TupleToUserset.Tupleset.Relation, child.TupleToUserset.ComputedUserset.Relation, "->"))
 			case *core.SetOperation_Child_FunctionedTupleToUserset:
-				key := child.FunctionedTupleToUserset.Tupleset.Relation + "->" + child.FunctionedTupleToUserset.ComputedUserset.Relation
+				separator := "->"
 
 				switch child.FunctionedTupleToUserset.Function {
 				case core.FunctionedTupleToUserset_FUNCTION_ANY:
-					// Use the key.
+					// Use the separator.
 
 				case core.FunctionedTupleToUserset_FUNCTION_ALL:
-					key = child.FunctionedTupleToUserset.Tupleset.Relation + "-(all)->" + child.FunctionedTupleToUserset.ComputedUserset.Relation
+					separator = "-(all)->"
 
 				default:
 					return nil, spiceerrors.MustBugf("unknown function %v", child.FunctionedTupleToUserset.Function)
 				}
 
-				if _, ok := varMap[key]; !ok {
-					varMap[key] = len(varMap)
-				}
+				addArrowKey(arrowKeys(child.FunctionedTupleToUserset.Tupleset.Relation, child.FunctionedTupleToUserset.ComputedUserset.Relation, separator))
 			}
 			return nil, nil
 		})
@@ -279,3 +611,27 @@ func buildBddVarMap(relations []*core.Relation, aliasMap map[string]string) (bdd
 		varMap:   varMap,
 	}, nil
 }
+
+// resolveThroughAliases follows the alias map until it reaches a relation that is not itself
+// an alias.
+func resolveThroughAliases(aliasMap map[string]string, relationName string) string {
+	for {
+		aliased, ok := aliasMap[relationName]
+		if !ok {
+			return relationName
+		}
+		relationName = aliased
+	}
+}
+
+// tuplesetAllowsSameNamespaceSubjects returns true if the given tupleset relation can hold
+// subjects from the namespace in which it is defined, making local relation names meaningful
+// on the computed side of arrows over it.
+func tuplesetAllowsSameNamespaceSubjects(namespaceName string, rel *core.Relation) bool {
+	for _, allowedRelation := range rel.GetTypeInformation().GetAllowedDirectRelations() {
+		if allowedRelation.Namespace == namespaceName {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,8 @@ package namespace
 
 import (
 	"fmt"
+	"hash"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -13,6 +15,7 @@ import (
 	"github.com/authzed/spicedb/pkg/schema"
 	"github.com/authzed/spicedb/pkg/schemadsl/compiler"
 	"github.com/authzed/spicedb/pkg/schemadsl/input"
+	"github.com/authzed/spicedb/pkg/tuple"
 )
 
 func TestCanonicalization(t *testing.T) {
@@ -48,8 +51,8 @@ func TestCanonicalization(t *testing.T) {
 			map[string]string{
 				"owner":  "owner",
 				"viewer": "viewer",
-				"edit":   computedKeyPrefix + "596a8660f9a0c085",
-				"view":   computedKeyPrefix + "0cb51da20fc9f20f",
+				"edit":   computedKeyPrefix + "1:20b5a05ec665b143",
+				"view":   computedKeyPrefix + "1:db13cd43aa39cda5",
 			},
 		},
 		{
@@ -69,8 +72,8 @@ func TestCanonicalization(t *testing.T) {
 			map[string]string{
 				"owner":      "owner",
 				"viewer":     "viewer",
-				"edit":       computedKeyPrefix + "596a8660f9a0c085",
-				"other_edit": computedKeyPrefix + "596a8660f9a0c085",
+				"edit":       computedKeyPrefix + "1:20b5a05ec665b143",
+				"other_edit": computedKeyPrefix + "1:20b5a05ec665b143",
 			},
 		},
 		{
@@ -90,8 +93,8 @@ func TestCanonicalization(t *testing.T) {
 			map[string]string{
 				"owner":      "owner",
 				"viewer":     "viewer",
-				"edit":       computedKeyPrefix + "596a8660f9a0c085",
-				"other_edit": computedKeyPrefix + "596a8660f9a0c085",
+				"edit":       computedKeyPrefix + "1:20b5a05ec665b143",
+				"other_edit": computedKeyPrefix + "1:20b5a05ec665b143",
 			},
 		},
 		{
@@ -113,8 +116,8 @@ func TestCanonicalization(t *testing.T) {
 			map[string]string{
 				"owner":  "owner",
 				"viewer": "viewer",
-				"first":  computedKeyPrefix + "62152badef526205",
-				"second": computedKeyPrefix + "62152badef526205",
+				"first":  computedKeyPrefix + "1:0c1853c7eefbdc2f",
+				"second": computedKeyPrefix + "1:0c1853c7eefbdc2f",
 			},
 		},
 		{
@@ -139,9 +142,9 @@ func TestCanonicalization(t *testing.T) {
 			map[string]string{
 				"owner":  "owner",
 				"viewer": "viewer",
-				"edit":   computedKeyPrefix + "596a8660f9a0c085",
-				"first":  computedKeyPrefix + "62152badef526205",
-				"second": computedKeyPrefix + "62152badef526205",
+				"edit":   computedKeyPrefix + "1:73b9dbb34401c73c",
+				"first":  computedKeyPrefix + "1:8ed3f82001ac420e",
+				"second": computedKeyPrefix + "1:8ed3f82001ac420e",
 			},
 		},
 		{
@@ -163,8 +166,8 @@ func TestCanonicalization(t *testing.T) {
 			map[string]string{
 				"owner":  "owner",
 				"viewer": "viewer",
-				"first":  computedKeyPrefix + "18cf8af8ff02bad0",
-				"second": computedKeyPrefix + "18cf8af8ff02bad0",
+				"first":  computedKeyPrefix + "1:c2d2bb12feac4292",
+				"second": computedKeyPrefix + "1:c2d2bb12feac4292",
 			},
 		},
 		{
@@ -186,8 +189,8 @@ func TestCanonicalization(t *testing.T) {
 			map[string]string{
 				"owner":  "owner",
 				"viewer": "viewer",
-				"first":  computedKeyPrefix + "2cd554a00f7f2d94",
-				"second": computedKeyPrefix + "69d4722141f74043",
+				"first":  computedKeyPrefix + "1:b2801d5dae2d5b16",
+				"second": computedKeyPrefix + "1:1230edaf4b91f895",
 			},
 		},
 		{
@@ -213,10 +216,48 @@ func TestCanonicalization(t *testing.T) {
 			map[string]string{
 				"owner":     "owner",
 				"viewer":    "viewer",
-				"first":     computedKeyPrefix + "9fd2b03cabeb2e42",
-				"second":    computedKeyPrefix + "9fd2b03cabeb2e42",
-				"diffrel":   computedKeyPrefix + "ab86f3a255f31908",
-				"difftuple": computedKeyPrefix + "dddc650e89a7bf1a",
+				"first":     computedKeyPrefix + "1:6b135b46322468c2",
+				"second":    computedKeyPrefix + "1:6b135b46322468c2",
+				"diffrel":   computedKeyPrefix + "1:43a414d169318088",
+				"difftuple": computedKeyPrefix + "1:75f9863d9ce6269a",
+			},
+		},
+		{
+			"canonicalization with arrows over aliased computed relations",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("parent", nil, ns.AllowedRelation("document", "...")),
+				ns.MustRelation("parentother", nil),
+				ns.MustRelation("viewer", nil),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+				)),
+				ns.MustRelation("first", ns.Union(
+					ns.TupleToUserset("parent", "view"),
+				)),
+				ns.MustRelation("second", ns.Union(
+					ns.TupleToUserset("parent", "viewer"),
+				)),
+				ns.MustRelation("third", ns.Union(
+					ns.TupleToUserset("parentother", "view"),
+				)),
+				ns.MustRelation("fourth", ns.Union(
+					ns.TupleToUserset("parentother", "viewer"),
+				)),
+			),
+			"",
+			map[string]string{
+				"parent":      "parent",
+				"parentother": "parentother",
+				"viewer":      "viewer",
+				"view":        computedKeyPrefix + "1:0e07d0dc1d75e192",
+				// `view` aliases `viewer` and `parent` can hold documents, so the
+				// arrows through `parent` share a key; `parentother` cannot hold
+				// documents, so its arrows keep distinct keys.
+				"first":  computedKeyPrefix + "1:4c10c3a739785187",
+				"second": computedKeyPrefix + "1:4c10c3a739785187",
+				"third":  computedKeyPrefix + "1:f8bd06f082345feb",
+				"fourth": computedKeyPrefix + "1:b7df324d0af6782d",
 			},
 		},
 		{
@@ -250,8 +291,8 @@ func TestCanonicalization(t *testing.T) {
 				"owner":  "owner",
 				"editor": "editor",
 				"viewer": "viewer",
-				"first":  computedKeyPrefix + "4c49627fbdbaf248",
-				"second": computedKeyPrefix + "4c49627fbdbaf248",
+				"first":  computedKeyPrefix + "1:fce07ae570d93881",
+				"second": computedKeyPrefix + "1:fce07ae570d93881",
 			},
 		},
 		{
@@ -285,8 +326,8 @@ func TestCanonicalization(t *testing.T) {
 				"owner":  "owner",
 				"editor": "editor",
 				"viewer": "viewer",
-				"first":  computedKeyPrefix + "7c52666bb7593f0a",
-				"second": computedKeyPrefix + "7c52666bb7593f0a",
+				"first":  computedKeyPrefix + "1:45b7649d19475287",
+				"second": computedKeyPrefix + "1:45b7649d19475287",
 			},
 		},
 		{
@@ -320,8 +361,8 @@ func TestCanonicalization(t *testing.T) {
 				"owner":  "owner",
 				"editor": "editor",
 				"viewer": "viewer",
-				"first":  computedKeyPrefix + "bb955307170373ae",
-				"second": computedKeyPrefix + "6ccf7bece2e540a1",
+				"first":  computedKeyPrefix + "1:4112b4a671c3cd6b",
+				"second": computedKeyPrefix + "1:77d497be0effbb48",
 			},
 		},
 		{
@@ -345,8 +386,8 @@ func TestCanonicalization(t *testing.T) {
 				"owner":  "owner",
 				"editor": "editor",
 				"viewer": "viewer",
-				"first":  computedKeyPrefix + "95f5633117d42867",
-				"second": computedKeyPrefix + "f786018d066f37b4",
+				"first":  computedKeyPrefix + "1:73b9dbb34401c73c",
+				"second": computedKeyPrefix + "1:81cb8e493593c86a",
 			},
 		},
 		{
@@ -370,8 +411,8 @@ func TestCanonicalization(t *testing.T) {
 				"owner":  "owner",
 				"editor": "editor",
 				"viewer": "viewer",
-				"first":  computedKeyPrefix + "bfc8d945d7030961",
-				"second": computedKeyPrefix + "bfc8d945d7030961",
+				"first":  computedKeyPrefix + "1:81cb8e493593c86a",
+				"second": computedKeyPrefix + "1:81cb8e493593c86a",
 			},
 		},
 		{
@@ -408,13 +449,13 @@ func TestCanonicalization(t *testing.T) {
 			map[string]string{
 				"owner":          "owner",
 				"viewer":         "viewer",
-				"first":          computedKeyPrefix + "9fd2b03cabeb2e42",
-				"second":         computedKeyPrefix + "9fd2b03cabeb2e42",
-				"third":          computedKeyPrefix + "9fd2b03cabeb2e42",
-				"thirdwithall":   computedKeyPrefix + "eafa2f3f2d970680",
-				"difftuple":      computedKeyPrefix + "dddc650e89a7bf1a",
-				"allplusanother": computedKeyPrefix + "8b68ba1711b32ca4",
-				"anotherplusall": computedKeyPrefix + "8b68ba1711b32ca4",
+				"first":          computedKeyPrefix + "1:65984a294e63084b",
+				"second":         computedKeyPrefix + "1:65984a294e63084b",
+				"third":          computedKeyPrefix + "1:65984a294e63084b",
+				"thirdwithall":   computedKeyPrefix + "1:0e4b9801b8943db7",
+				"difftuple":      computedKeyPrefix + "1:b9d980dbc2bd3121",
+				"allplusanother": computedKeyPrefix + "1:72a151702b8cd8a7",
+				"anotherplusall": computedKeyPrefix + "1:72a151702b8cd8a7",
 			},
 		},
 	}
@@ -492,6 +533,24 @@ func TestCanonicalizationComparison(t *testing.T) {
 			"owner & viewer",
 			true,
 		},
+		{
+			"idempotent intersection",
+			"viewer & viewer",
+			"viewer",
+			true,
+		},
+		{
+			"idempotent union",
+			"viewer + viewer",
+			"viewer",
+			true,
+		},
+		{
+			"reordered operands under intersection",
+			"(viewer + editor) & (editor + viewer)",
+			"viewer + editor",
+			true,
+		},
 		{
 			"exclusion non-associativity",
 			"viewer - owner",
@@ -546,6 +605,66 @@ func TestCanonicalizationComparison(t *testing.T) {
 			"(owner & nil) & editor",
 			true,
 		},
+		{
+			"self-subtraction normalizes to nil",
+			"viewer - viewer",
+			"nil",
+			true,
+		},
+		{
+			"intersection with nil normalizes to nil",
+			"viewer & nil",
+			"nil",
+			true,
+		},
+		{
+			"union with nil normalizes to the other branch",
+			"viewer + nil",
+			"viewer",
+			true,
+		},
+		{
+			"subtracting nil normalizes to the main branch",
+			"viewer - nil",
+			"viewer",
+			true,
+		},
+		{
+			"differently-written always-empty expressions share a key",
+			"viewer - viewer",
+			"owner & nil",
+			true,
+		},
+		{
+			"arrow and any function are semantically the same",
+			"owner->editor",
+			"owner.any(editor)",
+			true,
+		},
+		{
+			"any and all functions differ",
+			"owner.any(editor)",
+			"owner.all(editor)",
+			false,
+		},
+		{
+			"arrow and all function differ",
+			"owner->editor",
+			"owner.all(editor)",
+			false,
+		},
+		{
+			"all function matches itself",
+			"owner.all(editor)",
+			"owner.all(editor)",
+			true,
+		},
+		{
+			"all functions over different tuplesets differ",
+			"owner.all(editor)",
+			"viewer.all(editor)",
+			false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -584,3 +703,393 @@ func TestCanonicalizationComparison(t *testing.T) {
 		})
 	}
 }
+
+func TestCanonicalKeyVersioning(t *testing.T) {
+	require := require.New(t)
+
+	ds, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ctx := t.Context()
+
+	toCheck := ns.Namespace(
+		"document",
+		ns.MustRelation("owner", nil),
+		ns.MustRelation("viewer", nil),
+		ns.MustRelation("view", ns.Union(
+			ns.ComputedUserset("viewer"),
+			ns.ComputedUserset("owner"),
+		)),
+	)
+
+	lastRevision, err := ds.HeadRevision(t.Context())
+	require.NoError(err)
+
+	ts := schema.NewTypeSystem(schema.ResolverForDatastoreReader(ds.SnapshotReader(lastRevision)))
+	def, err := schema.NewDefinition(ts, toCheck)
+	require.NoError(err)
+
+	vdef, derr := def.Validate(ctx)
+	require.NoError(derr)
+
+	aliases, aerr := computePermissionAliases(vdef)
+	require.NoError(aerr)
+
+	currentKeys, err := computeCanonicalCacheKeysForVersion(vdef, aliases, CanonicalKeyVersion, unqualifiedCanonicalKeys)
+	require.NoError(err)
+
+	recomputedKeys, err := computeCanonicalCacheKeysForVersion(vdef, aliases, CanonicalKeyVersion, unqualifiedCanonicalKeys)
+	require.NoError(err)
+	require.Equal(currentKeys, recomputedKeys, "the same version must produce the same keys")
+
+	bumpedKeys, err := computeCanonicalCacheKeysForVersion(vdef, aliases, CanonicalKeyVersion+1, unqualifiedCanonicalKeys)
+	require.NoError(err)
+
+	for relName, currentKey := range currentKeys {
+		if !strings.HasPrefix(currentKey, computedKeyPrefix) {
+			// Pure relations are keyed by name and carry no version.
+			require.Equal(currentKey, bumpedKeys[relName])
+			continue
+		}
+		require.NotEqual(currentKey, bumpedKeys[relName],
+			"bumping the version must change the computed key for %s", relName)
+	}
+}
+
+func TestNamespaceQualifiedCanonicalKeys(t *testing.T) {
+	require := require.New(t)
+
+	ds, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ctx := t.Context()
+
+	namespaceWithName := func(name string) *core.NamespaceDefinition {
+		return ns.Namespace(
+			name,
+			ns.MustRelation("owner", nil),
+			ns.MustRelation("viewer", nil),
+			ns.MustRelation("view", ns.Union(
+				ns.ComputedUserset("viewer"),
+				ns.ComputedUserset("owner"),
+			)),
+		)
+	}
+
+	lastRevision, err := ds.HeadRevision(t.Context())
+	require.NoError(err)
+
+	ts := schema.NewTypeSystem(schema.ResolverForDatastoreReader(ds.SnapshotReader(lastRevision)))
+
+	keysFor := func(nsDef *core.NamespaceDefinition, qualification canonicalKeyQualification) map[string]string {
+		def, err := schema.NewDefinition(ts, nsDef)
+		require.NoError(err)
+
+		vdef, derr := def.Validate(ctx)
+		require.NoError(derr)
+
+		aliases, aerr := computePermissionAliases(vdef)
+		require.NoError(aerr)
+
+		cacheKeys, cerr := computeCanonicalCacheKeysForVersion(vdef, aliases, CanonicalKeyVersion, qualification)
+		require.NoError(cerr)
+		return cacheKeys
+	}
+
+	documentKeys := keysFor(namespaceWithName("document"), unqualifiedCanonicalKeys)
+	ticketKeys := keysFor(namespaceWithName("ticket"), unqualifiedCanonicalKeys)
+	require.Equal(documentKeys["view"], ticketKeys["view"],
+		"unqualified keys for structurally-identical permissions must match across definitions")
+
+	qualifiedDocumentKeys := keysFor(namespaceWithName("document"), namespaceQualifiedCanonicalKeys)
+	qualifiedTicketKeys := keysFor(namespaceWithName("ticket"), namespaceQualifiedCanonicalKeys)
+	require.NotEqual(qualifiedDocumentKeys["view"], qualifiedTicketKeys["view"],
+		"namespace-qualified keys must differ across definitions")
+
+	require.NotEqual(documentKeys["view"], qualifiedDocumentKeys["view"],
+		"qualification must change the computed key")
+}
+
+func TestParseCanonicalKey(t *testing.T) {
+	require := require.New(t)
+
+	ds, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ctx := t.Context()
+
+	toCheck := ns.Namespace(
+		"document",
+		ns.MustRelation("owner", nil),
+		ns.MustRelation("view", ns.Union(
+			ns.ComputedUserset("owner"),
+		)),
+	)
+
+	lastRevision, err := ds.HeadRevision(t.Context())
+	require.NoError(err)
+
+	ts := schema.NewTypeSystem(schema.ResolverForDatastoreReader(ds.SnapshotReader(lastRevision)))
+	def, err := schema.NewDefinition(ts, toCheck)
+	require.NoError(err)
+
+	vdef, derr := def.Validate(ctx)
+	require.NoError(derr)
+
+	aliases, aerr := computePermissionAliases(vdef)
+	require.NoError(aerr)
+
+	cacheKeys, cerr := computeCanonicalCacheKeys(vdef, aliases)
+	require.NoError(cerr)
+
+	version, hash, err := ParseCanonicalKey(cacheKeys["view"])
+	require.NoError(err)
+	require.Equal(CanonicalKeyVersion, version)
+	require.Len(hash, 16, "the hash portion is the hex-encoded 64-bit FNV sum")
+
+	_, _, err = ParseCanonicalKey("owner")
+	require.ErrorContains(err, "not a computed key")
+
+	_, _, err = ParseCanonicalKey(computedKeyPrefix + "deadbeef")
+	require.ErrorContains(err, "missing a version")
+
+	_, _, err = ParseCanonicalKey(computedKeyPrefix + "x:deadbeef")
+	require.ErrorContains(err, "malformed version")
+}
+
+func TestComputeGlobalCanonicalKeys(t *testing.T) {
+	require := require.New(t)
+
+	ctx := t.Context()
+
+	compiled, err := compiler.Compile(compiler.InputSchema{
+		Source: input.Source("schema"),
+		SchemaString: `
+			definition user {}
+
+			definition organization {
+				relation member: user
+			}
+
+			definition team {
+				relation member: user
+			}
+
+			definition document {
+				relation org: organization
+				permission view = org->member
+			}
+
+			definition project {
+				relation org: organization
+				permission view = org->member
+			}
+
+			definition ticket {
+				relation org: team
+				permission view = org->member
+			}
+		`,
+	}, compiler.AllowUnprefixedObjectType())
+	require.NoError(err)
+
+	ts := schema.NewTypeSystem(schema.ResolverForSchema(*compiled))
+	validated := make([]*schema.ValidatedDefinition, 0, len(compiled.ObjectDefinitions))
+	for _, objectDef := range compiled.ObjectDefinitions {
+		def, err := schema.NewDefinition(ts, objectDef)
+		require.NoError(err)
+
+		vdef, derr := def.Validate(ctx)
+		require.NoError(derr)
+		validated = append(validated, vdef)
+	}
+
+	globalKeys, err := computeGlobalCanonicalKeys(validated)
+	require.NoError(err)
+
+	require.Equal(globalKeys[tuple.RR("document", "view")], globalKeys[tuple.RR("project", "view")],
+		"structurally-identical permissions referencing the same namespaces must share a key")
+	require.NotEqual(globalKeys[tuple.RR("document", "view")], globalKeys[tuple.RR("ticket", "view")],
+		"referencing a different namespace must change the key")
+
+	require.Equal("organization#member", globalKeys[tuple.RR("organization", "member")])
+	require.NotEqual(globalKeys[tuple.RR("organization", "member")], globalKeys[tuple.RR("team", "member")],
+		"pure relations must be namespace-qualified globally")
+}
+
+func TestCanonicalKeysIncludeCaveatTraits(t *testing.T) {
+	require := require.New(t)
+
+	ctx := t.Context()
+
+	keysForSchema := func(schemaString string) map[string]string {
+		compiled, err := compiler.Compile(compiler.InputSchema{
+			Source:       input.Source("schema"),
+			SchemaString: schemaString,
+		}, compiler.AllowUnprefixedObjectType())
+		require.NoError(err)
+
+		ts := schema.NewTypeSystem(schema.ResolverForSchema(*compiled))
+		for _, objectDef := range compiled.ObjectDefinitions {
+			if objectDef.Name != "document" {
+				continue
+			}
+
+			def, err := schema.NewDefinition(ts, objectDef)
+			require.NoError(err)
+
+			vdef, derr := def.Validate(ctx)
+			require.NoError(derr)
+
+			aliases, aerr := computePermissionAliases(vdef)
+			require.NoError(aerr)
+
+			cacheKeys, cerr := computeCanonicalCacheKeys(vdef, aliases)
+			require.NoError(cerr)
+			return cacheKeys
+		}
+
+		require.Fail("missing document definition")
+		return nil
+	}
+
+	uncaveatedKeys := keysForSchema(`
+		definition user {}
+
+		definition document {
+			relation viewer: user
+			permission view = viewer
+		}
+	`)
+
+	caveatedKeys := keysForSchema(`
+		definition user {}
+
+		caveat somecaveat(somevalue int) {
+			somevalue == 1
+		}
+
+		definition document {
+			relation viewer: user with somecaveat
+			permission view = viewer
+		}
+	`)
+
+	require.NotEqual(uncaveatedKeys["view"], caveatedKeys["view"],
+		"adding a caveat to a referenced relation must change the permission's key")
+}
+
+// collidingHasher returns the same sum regardless of input, forcing every
+// computed key into the collision fallback path.
+type collidingHasher struct{}
+
+func (collidingHasher) Write(p []byte) (int, error) { return len(p), nil }
+func (collidingHasher) Sum(b []byte) []byte {
+	return append(b, 0xde, 0xad, 0xbe, 0xef, 0x00, 0x00, 0x00, 0x00)
+}
+func (collidingHasher) Reset()         {}
+func (collidingHasher) Size() int      { return 8 }
+func (collidingHasher) BlockSize() int { return 1 }
+
+func TestCanonicalKeyCollisionFallback(t *testing.T) {
+	require := require.New(t)
+
+	originalHasher := newCanonicalKeyHasher
+	newCanonicalKeyHasher = func() hash.Hash { return collidingHasher{} }
+	defer func() { newCanonicalKeyHasher = originalHasher }()
+
+	ds, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ctx := t.Context()
+
+	toCheck := ns.Namespace(
+		"document",
+		ns.MustRelation("owner", nil),
+		ns.MustRelation("viewer", nil),
+		ns.MustRelation("first", ns.Union(
+			ns.ComputedUserset("owner"),
+			ns.ComputedUserset("viewer"),
+		)),
+		ns.MustRelation("second", ns.Intersection(
+			ns.ComputedUserset("owner"),
+			ns.ComputedUserset("viewer"),
+		)),
+		ns.MustRelation("third", ns.Union(
+			ns.ComputedUserset("owner"),
+			ns.ComputedUserset("viewer"),
+		)),
+	)
+
+	lastRevision, err := ds.HeadRevision(t.Context())
+	require.NoError(err)
+
+	ts := schema.NewTypeSystem(schema.ResolverForDatastoreReader(ds.SnapshotReader(lastRevision)))
+	def, err := schema.NewDefinition(ts, toCheck)
+	require.NoError(err)
+
+	vdef, derr := def.Validate(ctx)
+	require.NoError(derr)
+
+	aliases, aerr := computePermissionAliases(vdef)
+	require.NoError(aerr)
+
+	cacheKeys, cerr := computeCanonicalCacheKeys(vdef, aliases)
+	require.NoError(cerr)
+
+	require.NotEqual(cacheKeys["first"], cacheKeys["second"],
+		"semantically different permissions must receive distinct keys despite the hash collision")
+	require.Equal(cacheKeys["first"], cacheKeys["third"],
+		"identical expressions still share a key through the fallback")
+
+	_, hashPortion, err := ParseCanonicalKey(cacheKeys["first"])
+	require.NoError(err)
+	require.Len(hashPortion, 32, "colliding entries fall back to a 128-bit hash")
+}
+
+func BenchmarkComputeCanonicalCacheKeys(b *testing.B) {
+	require := require.New(b)
+
+	// Generate a namespace where thousands of permissions all reference the
+	// same nested sub-expression, the worst case for repeated sub-expression
+	// hashing.
+	sharedExpression := ns.Rewrite(ns.Exclusion(
+		ns.ComputedUserset("viewer"),
+		ns.ComputedUserset("banned"),
+		ns.TupleToUserset("parent", "view"),
+	))
+
+	relations := []*core.Relation{
+		ns.MustRelation("viewer", nil),
+		ns.MustRelation("banned", nil),
+		ns.MustRelation("parent", nil),
+		ns.MustRelation("owner", nil),
+	}
+	for i := 0; i < 2000; i++ {
+		relations = append(relations, ns.MustRelation(fmt.Sprintf("perm%d", i), ns.Union(
+			ns.ComputedUserset("owner"),
+			sharedExpression,
+		)))
+	}
+
+	ds, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	lastRevision, err := ds.HeadRevision(b.Context())
+	require.NoError(err)
+
+	ts := schema.NewTypeSystem(schema.ResolverForDatastoreReader(ds.SnapshotReader(lastRevision)))
+
+	def, err := schema.NewDefinition(ts, ns.Namespace("document", relations...))
+	require.NoError(err)
+
+	vdef, derr := def.Validate(b.Context())
+	require.NoError(derr)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := computeCanonicalCacheKeys(vdef, map[string]string{})
+		require.NoError(err)
+	}
+}
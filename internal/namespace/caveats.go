@@ -11,10 +11,22 @@ import (
 	"github.com/authzed/spicedb/pkg/schema"
 )
 
+// WellKnownCaveatContextKeys are the context key names the server reserves for injecting
+// values into caveat context at evaluation time. A caveat parameter sharing one of these
+// names would be silently shadowed by the injected value, so development tooling reports
+// such parameters as warnings.
+var WellKnownCaveatContextKeys = []string{
+	// now is reserved for the server-injected evaluation timestamp.
+	"now",
+
+	// request_time is reserved for the server-injected time at which the request was received.
+	"request_time",
+}
+
 // ValidateCaveatDefinition validates the parameters and types within the given caveat
-// definition, including usage of the parameters.
+// definition. Unused parameters are not an error; they are reported as warnings by the
+// development tooling via UnusedCaveatParameters.
 func ValidateCaveatDefinition(ts *caveattypes.TypeSet, caveat *core.CaveatDefinition) error {
-	// Ensure all parameters are used by the caveat expression itself.
 	parameterTypes, err := caveattypes.DecodeParameterTypes(ts, caveat.ParameterTypes)
 	if err != nil {
 		return schema.NewTypeWithSourceError(
@@ -24,7 +36,7 @@ func ValidateCaveatDefinition(ts *caveattypes.TypeSet, caveat *core.CaveatDefini
 		)
 	}
 
-	deserialized, err := caveats.DeserializeCaveatWithTypeSet(ts, caveat.SerializedExpression, parameterTypes)
+	_, err = caveats.DeserializeCaveatWithTypeSet(ts, caveat.SerializedExpression, parameterTypes)
 	if err != nil {
 		return schema.NewTypeWithSourceError(
 			fmt.Errorf("could not decode caveat `%s`: %w", caveat.Name, err),
@@ -41,11 +53,6 @@ func ValidateCaveatDefinition(ts *caveattypes.TypeSet, caveat *core.CaveatDefini
 		)
 	}
 
-	referencedNames, err := deserialized.ReferencedParameters(slices.Collect(maps.Keys(caveat.ParameterTypes)))
-	if err != nil {
-		return err
-	}
-
 	for paramName, paramType := range caveat.ParameterTypes {
 		_, err := caveattypes.DecodeParameterType(ts, paramType)
 		if err != nil {
@@ -55,15 +62,36 @@ func ValidateCaveatDefinition(ts *caveattypes.TypeSet, caveat *core.CaveatDefini
 				paramName,
 			)
 		}
+	}
+
+	return nil
+}
 
+// UnusedCaveatParameters returns the names, in sorted order, of the parameters declared on
+// the given caveat definition that its expression never references.
+func UnusedCaveatParameters(ts *caveattypes.TypeSet, caveat *core.CaveatDefinition) ([]string, error) {
+	parameterTypes, err := caveattypes.DecodeParameterTypes(ts, caveat.ParameterTypes)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode caveat parameters `%s`: %w", caveat.Name, err)
+	}
+
+	deserialized, err := caveats.DeserializeCaveatWithTypeSet(ts, caveat.SerializedExpression, parameterTypes)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode caveat `%s`: %w", caveat.Name, err)
+	}
+
+	referencedNames, err := deserialized.ReferencedParameters(slices.Collect(maps.Keys(caveat.ParameterTypes)))
+	if err != nil {
+		return nil, err
+	}
+
+	unused := make([]string, 0, len(caveat.ParameterTypes))
+	for paramName := range caveat.ParameterTypes {
 		if !referencedNames.Has(paramName) {
-			return schema.NewTypeWithSourceError(
-				NewUnusedCaveatParameterErr(caveat.Name, paramName),
-				caveat,
-				paramName,
-			)
+			unused = append(unused, paramName)
 		}
 	}
 
-	return nil
+	slices.Sort(unused)
+	return unused, nil
 }
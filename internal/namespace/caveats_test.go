@@ -29,8 +29,8 @@ func TestValidateCaveatDefinition(t *testing.T) {
 				map[string]caveattypes.VariableType{
 					"someCondition": caveattypes.Default.IntType,
 				},
-			), "test", "true"),
-			"parameter `someCondition` for caveat `test` is unused",
+			), "unusedParameter", "true"),
+			"",
 		},
 		{
 			ns.MustCaveatDefinition(caveats.MustEnvForVariablesWithDefaultTypeSet(
@@ -59,3 +59,51 @@ func TestValidateCaveatDefinition(t *testing.T) {
 		})
 	}
 }
+
+func TestUnusedCaveatParameters(t *testing.T) {
+	tcs := []struct {
+		name           string
+		caveat         *core.CaveatDefinition
+		expectedUnused []string
+	}{
+		{
+			"no unused parameters",
+			ns.MustCaveatDefinition(caveats.MustEnvForVariablesWithDefaultTypeSet(
+				map[string]caveattypes.VariableType{
+					"someCondition": caveattypes.Default.IntType,
+					"anotherValue":  caveattypes.Default.IntType,
+				},
+			), "allused", "someCondition == anotherValue"),
+			[]string{},
+		},
+		{
+			"some unused parameters",
+			ns.MustCaveatDefinition(caveats.MustEnvForVariablesWithDefaultTypeSet(
+				map[string]caveattypes.VariableType{
+					"someCondition": caveattypes.Default.IntType,
+					"anotherValue":  caveattypes.Default.IntType,
+				},
+			), "someunused", "someCondition == 42"),
+			[]string{"anotherValue"},
+		},
+		{
+			"all unused parameters",
+			ns.MustCaveatDefinition(caveats.MustEnvForVariablesWithDefaultTypeSet(
+				map[string]caveattypes.VariableType{
+					"someCondition": caveattypes.Default.IntType,
+					"anotherValue":  caveattypes.Default.IntType,
+				},
+			), "allunused", "true"),
+			[]string{"anotherValue", "someCondition"},
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			unused, err := UnusedCaveatParameters(caveattypes.Default.TypeSet, tc.caveat)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedUnused, unused)
+		})
+	}
+}
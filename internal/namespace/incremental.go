@@ -0,0 +1,159 @@
+package namespace
+
+import (
+	"context"
+	"encoding/hex"
+	"hash/fnv"
+	"sort"
+	"sync"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/schema"
+)
+
+// DefinitionCanonicalization holds the alias and canonical cache key maps computed for a single
+// definition, as written by AnnotateNamespace.
+type DefinitionCanonicalization struct {
+	// Aliases maps each aliased permission to its terminal target.
+	Aliases map[string]string
+
+	// CacheKeys maps each relation to its namespace-qualified canonical cache key.
+	CacheKeys map[string]string
+}
+
+// CanonicalizationCache memoizes per-definition validation and canonicalization work across
+// schema writes, so that writing a small change to a large schema only recomputes the changed
+// definitions. Each cache entry records a content hash of the definition it was computed from,
+// along with the content hashes of every definition it references via its allowed subject
+// types; an entry is reused only when all of those hashes are unchanged, so the dirty set plus
+// its reverse dependencies are recomputed while everything else is served from the cache.
+type CanonicalizationCache struct {
+	mu      sync.Mutex
+	entries map[string]*canonicalizationCacheEntry
+
+	// lastRecomputed holds the sorted names of the definitions recomputed by the most recent
+	// Compute call, for tests and observability.
+	lastRecomputed []string
+}
+
+type canonicalizationCacheEntry struct {
+	contentHash      string
+	referencedHashes map[string]string
+	result           DefinitionCanonicalization
+}
+
+// NewCanonicalizationCache constructs an empty cache.
+func NewCanonicalizationCache() *CanonicalizationCache {
+	return &CanonicalizationCache{
+		entries: map[string]*canonicalizationCacheEntry{},
+	}
+}
+
+// Compute returns the alias and canonical key maps for every given definition, validating and
+// recomputing only those definitions whose content hash, or whose referenced definitions'
+// content hashes, changed since the previous call. The given type system must resolve the same
+// definitions passed in. The result is equal to computing each definition from scratch.
+func (cc *CanonicalizationCache) Compute(ctx context.Context, ts *schema.TypeSystem, objectDefs []*core.NamespaceDefinition) (map[string]DefinitionCanonicalization, error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	contentHashes := make(map[string]string, len(objectDefs))
+	for _, nsDef := range objectDefs {
+		contentHash, err := definitionContentHash(nsDef)
+		if err != nil {
+			return nil, err
+		}
+		contentHashes[nsDef.Name] = contentHash
+	}
+
+	results := make(map[string]DefinitionCanonicalization, len(objectDefs))
+	recomputed := make([]string, 0)
+	updatedEntries := make(map[string]*canonicalizationCacheEntry, len(objectDefs))
+	for _, nsDef := range objectDefs {
+		if entry, ok := cc.entries[nsDef.Name]; ok && entry.isFresh(contentHashes[nsDef.Name], contentHashes) {
+			updatedEntries[nsDef.Name] = entry
+			results[nsDef.Name] = entry.result
+			continue
+		}
+
+		vdef, err := ts.GetValidatedDefinition(ctx, nsDef.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		aliases, err := computePermissionAliases(vdef)
+		if err != nil {
+			return nil, err
+		}
+
+		cacheKeys, err := computeNamespaceQualifiedCacheKeys(vdef, aliases)
+		if err != nil {
+			return nil, err
+		}
+
+		result := DefinitionCanonicalization{Aliases: aliases, CacheKeys: cacheKeys}
+		updatedEntries[nsDef.Name] = &canonicalizationCacheEntry{
+			contentHash:      contentHashes[nsDef.Name],
+			referencedHashes: referencedDefinitionHashes(nsDef, contentHashes),
+			result:           result,
+		}
+		results[nsDef.Name] = result
+		recomputed = append(recomputed, nsDef.Name)
+	}
+
+	// Entries for definitions no longer present are dropped, keeping the cache bounded by the
+	// current schema's size.
+	cc.entries = updatedEntries
+	sort.Strings(recomputed)
+	cc.lastRecomputed = recomputed
+	return results, nil
+}
+
+// isFresh returns true if the entry was computed from a definition with the given content hash
+// and none of the definitions it references have changed or been removed.
+func (entry *canonicalizationCacheEntry) isFresh(contentHash string, contentHashes map[string]string) bool {
+	if entry.contentHash != contentHash {
+		return false
+	}
+
+	for referencedName, referencedHash := range entry.referencedHashes {
+		currentHash, ok := contentHashes[referencedName]
+		if !ok || currentHash != referencedHash {
+			return false
+		}
+	}
+	return true
+}
+
+// referencedDefinitionHashes returns the content hashes of the other definitions referenced by
+// the given definition via its allowed subject types. Arrows reach other definitions only
+// through a tupleset relation's allowed subject types, so these edges cover the full
+// cross-namespace reference graph.
+func referencedDefinitionHashes(nsDef *core.NamespaceDefinition, contentHashes map[string]string) map[string]string {
+	referenced := map[string]string{}
+	for _, rel := range nsDef.Relation {
+		for _, allowedRelation := range rel.GetTypeInformation().GetAllowedDirectRelations() {
+			if allowedRelation.Namespace == nsDef.Name {
+				continue
+			}
+
+			if contentHash, ok := contentHashes[allowedRelation.Namespace]; ok {
+				referenced[allowedRelation.Namespace] = contentHash
+			}
+		}
+	}
+	return referenced
+}
+
+func definitionContentHash(nsDef *core.NamespaceDefinition) (string, error) {
+	serialized, err := nsDef.MarshalVT()
+	if err != nil {
+		return "", err
+	}
+
+	hasher := fnv.New64a()
+	if _, err := hasher.Write(serialized); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
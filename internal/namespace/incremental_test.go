@@ -0,0 +1,170 @@
+package namespace
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ns "github.com/authzed/spicedb/pkg/namespace"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/schema"
+)
+
+func typeSystemForDefs(defs []*core.NamespaceDefinition) *schema.TypeSystem {
+	return schema.NewTypeSystem(schema.ResolverForPredefinedDefinitions(schema.PredefinedElements{
+		Definitions: defs,
+	}))
+}
+
+func TestCanonicalizationCacheIncrementalCompute(t *testing.T) {
+	require := require.New(t)
+	ctx := t.Context()
+
+	buildDefs := func(folderViewerRelation string, documentExtraPermission bool) []*core.NamespaceDefinition {
+		folderRelations := []*core.Relation{
+			ns.MustRelation(folderViewerRelation, nil, ns.AllowedRelation("user", "...")),
+			ns.MustRelation("view", ns.Union(
+				ns.ComputedUserset(folderViewerRelation),
+			)),
+		}
+
+		documentRelations := []*core.Relation{
+			ns.MustRelation("folder", nil, ns.AllowedRelation("folder", "...")),
+			ns.MustRelation("owner", nil, ns.AllowedRelation("user", "...")),
+			ns.MustRelation("edit", ns.Union(
+				ns.ComputedUserset("owner"),
+			)),
+			ns.MustRelation("view", ns.Union(
+				ns.ComputedUserset("edit"),
+				ns.TupleToUserset("folder", "view"),
+			)),
+		}
+		if documentExtraPermission {
+			documentRelations = append(documentRelations, ns.MustRelation("extra", ns.Union(
+				ns.ComputedUserset("owner"),
+			)))
+		}
+
+		return []*core.NamespaceDefinition{
+			ns.Namespace("user"),
+			ns.Namespace("folder", folderRelations...),
+			ns.Namespace("document", documentRelations...),
+		}
+	}
+
+	cache := NewCanonicalizationCache()
+
+	initialDefs := buildDefs("viewer", false)
+	initialResults, err := cache.Compute(ctx, typeSystemForDefs(initialDefs), initialDefs)
+	require.NoError(err)
+	require.Equal([]string{"document", "folder", "user"}, cache.lastRecomputed)
+
+	// An unchanged schema recomputes nothing and returns the same results.
+	unchangedDefs := buildDefs("viewer", false)
+	unchangedResults, err := cache.Compute(ctx, typeSystemForDefs(unchangedDefs), unchangedDefs)
+	require.NoError(err)
+	require.Empty(cache.lastRecomputed)
+	require.Equal(initialResults, unchangedResults)
+
+	// Changing only `document` leaves `folder` and `user` cached.
+	changedDocumentDefs := buildDefs("viewer", true)
+	changedDocumentResults, err := cache.Compute(ctx, typeSystemForDefs(changedDocumentDefs), changedDocumentDefs)
+	require.NoError(err)
+	require.Equal([]string{"document"}, cache.lastRecomputed)
+
+	// Changing `folder` also recomputes `document`, which references it, but not `user`.
+	changedFolderDefs := buildDefs("reader", true)
+	changedFolderResults, err := cache.Compute(ctx, typeSystemForDefs(changedFolderDefs), changedFolderDefs)
+	require.NoError(err)
+	require.Equal([]string{"document", "folder"}, cache.lastRecomputed)
+
+	// The incremental results must equal computing each schema from scratch.
+	for _, tc := range []struct {
+		defs    []*core.NamespaceDefinition
+		results map[string]DefinitionCanonicalization
+	}{
+		{changedDocumentDefs, changedDocumentResults},
+		{changedFolderDefs, changedFolderResults},
+	} {
+		fromScratch, err := NewCanonicalizationCache().Compute(ctx, typeSystemForDefs(tc.defs), tc.defs)
+		require.NoError(err)
+		require.Equal(fromScratch, tc.results)
+	}
+}
+
+func TestCanonicalizationCacheDropsRemovedDefinitions(t *testing.T) {
+	require := require.New(t)
+	ctx := t.Context()
+
+	withDocument := []*core.NamespaceDefinition{
+		ns.Namespace("user"),
+		ns.Namespace(
+			"document",
+			ns.MustRelation("owner", nil, ns.AllowedRelation("user", "...")),
+		),
+	}
+
+	cache := NewCanonicalizationCache()
+	_, err := cache.Compute(ctx, typeSystemForDefs(withDocument), withDocument)
+	require.NoError(err)
+	require.Len(cache.entries, 2)
+
+	withoutDocument := []*core.NamespaceDefinition{ns.Namespace("user")}
+	_, err = cache.Compute(ctx, typeSystemForDefs(withoutDocument), withoutDocument)
+	require.NoError(err)
+	require.Len(cache.entries, 1)
+}
+
+func BenchmarkCanonicalizationCacheSingleDefinitionChange(b *testing.B) {
+	require := require.New(b)
+	ctx := b.Context()
+
+	// A chain of definitions, each allowing subjects from the previous, with a handful of
+	// permissions apiece.
+	buildDefs := func(changedSuffix string) []*core.NamespaceDefinition {
+		defs := make([]*core.NamespaceDefinition, 0, 401)
+		defs = append(defs, ns.Namespace("user"))
+		for i := 0; i < 400; i++ {
+			subjectNamespace := "user"
+			if i > 0 {
+				subjectNamespace = fmt.Sprintf("definition%d", i-1)
+			}
+
+			ownerName := "owner"
+			if i == 399 {
+				ownerName = "owner" + changedSuffix
+			}
+
+			defs = append(defs, ns.Namespace(
+				fmt.Sprintf("definition%d", i),
+				ns.MustRelation(ownerName, nil, ns.AllowedRelation(subjectNamespace, "...")),
+				ns.MustRelation("viewer", nil, ns.AllowedRelation(subjectNamespace, "...")),
+				ns.MustRelation("edit", ns.Union(
+					ns.ComputedUserset(ownerName),
+				)),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("edit"),
+				)),
+			))
+		}
+		return defs
+	}
+
+	cache := NewCanonicalizationCache()
+	initialDefs := buildDefs("")
+	_, err := cache.Compute(ctx, typeSystemForDefs(initialDefs), initialDefs)
+	require.NoError(err)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Alternate the final definition's content so exactly one definition is dirty per
+		// iteration.
+		defs := buildDefs(fmt.Sprintf("%d", i%2))
+		_, err := cache.Compute(ctx, typeSystemForDefs(defs), defs)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package namespace
+
+import (
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/schema"
+	"github.com/authzed/spicedb/pkg/spiceerrors"
+)
+
+// StructuralCanonicalKeys computes a map from relation name to a *structural* canonical key for
+// the given validated definition. Unlike the name-based keys from CanonicalCacheKeys, where a
+// plain relation's key is its own name, structural keys never embed the relation's name: each
+// reference to a relation is replaced by a stable identifier derived from the referenced
+// relation's own structural key, computed in dependency order. A pure rename of a relation or
+// intermediate permission therefore leaves the structural keys of everything referencing it
+// unchanged.
+//
+// The trade-off is that two relations with identical allowed subject types share a structural
+// identifier, so structural keys are coarser than name-based keys; they are intended for
+// rename-tolerant caching, not for distinguishing every relation. Arrow targets live on other
+// definitions and are referenced by name, so renaming a relation referenced through an arrow
+// still changes the keys of the arrows' containing permissions.
+func StructuralCanonicalKeys(typeDef *schema.ValidatedDefinition) (map[string]string, error) {
+	computation := &structuralKeyComputation{
+		nsDef:    typeDef.Namespace(),
+		typeDef:  typeDef,
+		ids:      make(map[string]string, len(typeDef.Namespace().Relation)),
+		visiting: map[string]struct{}{},
+	}
+
+	keys := make(map[string]string, len(typeDef.Namespace().Relation))
+	for _, rel := range typeDef.Namespace().Relation {
+		id, err := computation.structuralID(rel.Name)
+		if err != nil {
+			return nil, err
+		}
+		keys[rel.Name] = computedKeyPrefix + strconv.Itoa(CanonicalKeyVersion) + ":" + id
+	}
+	return keys, nil
+}
+
+type structuralKeyComputation struct {
+	nsDef    *core.NamespaceDefinition
+	typeDef  *schema.ValidatedDefinition
+	ids      map[string]string
+	visiting map[string]struct{}
+}
+
+// structuralID returns the stable identifier for the given relation, computing it (and any
+// identifiers it depends upon) on first use.
+func (skc *structuralKeyComputation) structuralID(relationName string) (string, error) {
+	if id, ok := skc.ids[relationName]; ok {
+		return id, nil
+	}
+
+	if _, ok := skc.visiting[relationName]; ok {
+		// Validation rejects multi-permission cycles, but a permission directly referencing
+		// itself still parses; there is no name-free form for it.
+		return "", NewPermissionsCycleErr(skc.nsDef.Name, []string{relationName})
+	}
+	skc.visiting[relationName] = struct{}{}
+	defer delete(skc.visiting, relationName)
+
+	rel, ok := skc.typeDef.GetRelation(relationName)
+	if !ok {
+		return "", spiceerrors.MustBugf("missing relation %s in structural key computation", relationName)
+	}
+
+	var form string
+	if rewrite := rel.GetUsersetRewrite(); rewrite != nil {
+		rewriteForm, err := skc.rewriteStructuralForm(rewrite)
+		if err != nil {
+			return "", err
+		}
+		form = rewriteForm
+	} else {
+		// A plain relation is identified by a digest of its allowed subject types, which is
+		// what gives meaning to the relation independently of its name.
+		allowed := make([]string, 0, len(rel.GetTypeInformation().GetAllowedDirectRelations()))
+		for _, allowedRelation := range rel.GetTypeInformation().GetAllowedDirectRelations() {
+			allowed = append(allowed, schema.SourceForAllowedRelation(allowedRelation))
+		}
+		sort.Strings(allowed)
+		form = "relation(" + strings.Join(allowed, ",") + ")"
+	}
+
+	hasher := fnv.New64a()
+	if _, err := fmt.Fprintf(hasher, "v%d:%s", CanonicalKeyVersion, form); err != nil {
+		return "", err
+	}
+
+	id := hex.EncodeToString(hasher.Sum(nil))
+	skc.ids[relationName] = id
+	return id, nil
+}
+
+// rewriteStructuralForm serializes a rewrite with relation references replaced by structural
+// identifiers. Union and intersection operands are deduplicated and sorted, matching the set
+// semantics the name-based canonicalization gets from the BDD; exclusion operands keep their
+// order.
+func (skc *structuralKeyComputation) rewriteStructuralForm(rewrite *core.UsersetRewrite) (string, error) {
+	switch rw := rewrite.RewriteOperation.(type) {
+	case *core.UsersetRewrite_Union:
+		forms, err := skc.operandStructuralForms(rw.Union)
+		if err != nil {
+			return "", err
+		}
+		sort.Strings(forms)
+		return "union(" + strings.Join(dedupeSortedForms(forms), ",") + ")", nil
+
+	case *core.UsersetRewrite_Intersection:
+		forms, err := skc.operandStructuralForms(rw.Intersection)
+		if err != nil {
+			return "", err
+		}
+		sort.Strings(forms)
+		return "intersection(" + strings.Join(dedupeSortedForms(forms), ",") + ")", nil
+
+	case *core.UsersetRewrite_Exclusion:
+		forms, err := skc.operandStructuralForms(rw.Exclusion)
+		if err != nil {
+			return "", err
+		}
+		return "exclusion(" + strings.Join(forms, ",") + ")", nil
+
+	default:
+		return "", spiceerrors.MustBugf("unknown rewrite kind %T in structural key computation", rw)
+	}
+}
+
+func (skc *structuralKeyComputation) operandStructuralForms(so *core.SetOperation) ([]string, error) {
+	forms := make([]string, 0, len(so.Child))
+	for _, childOneof := range so.Child {
+		switch child := childOneof.ChildType.(type) {
+		case *core.SetOperation_Child_ComputedUserset:
+			id, err := skc.structuralID(child.ComputedUserset.Relation)
+			if err != nil {
+				return nil, err
+			}
+			forms = append(forms, "ref:"+id)
+
+		case *core.SetOperation_Child_UsersetRewrite:
+			form, err := skc.rewriteStructuralForm(child.UsersetRewrite)
+			if err != nil {
+				return nil, err
+			}
+			forms = append(forms, form)
+
+		case *core.SetOperation_Child_TupleToUserset:
+			id, err := skc.structuralID(child.TupleToUserset.Tupleset.Relation)
+			if err != nil {
+				return nil, err
+			}
+			forms = append(forms, "arrow:"+id+"->"+child.TupleToUserset.ComputedUserset.Relation)
+
+		case *core.SetOperation_Child_FunctionedTupleToUserset:
+			id, err := skc.structuralID(child.FunctionedTupleToUserset.Tupleset.Relation)
+			if err != nil {
+				return nil, err
+			}
+
+			switch child.FunctionedTupleToUserset.Function {
+			case core.FunctionedTupleToUserset_FUNCTION_ANY:
+				forms = append(forms, "arrow:"+id+"->"+child.FunctionedTupleToUserset.ComputedUserset.Relation)
+
+			case core.FunctionedTupleToUserset_FUNCTION_ALL:
+				forms = append(forms, "arrow:"+id+"-(all)->"+child.FunctionedTupleToUserset.ComputedUserset.Relation)
+
+			default:
+				return nil, spiceerrors.MustBugf("unknown function %v", child.FunctionedTupleToUserset.Function)
+			}
+
+		case *core.SetOperation_Child_XNil:
+			forms = append(forms, "nil")
+
+		case *core.SetOperation_Child_XThis:
+			return nil, spiceerrors.MustBugf("use of _this is disallowed")
+
+		default:
+			return nil, spiceerrors.MustBugf("unknown set operation child %T", child)
+		}
+	}
+	return forms, nil
+}
+
+// dedupeSortedForms removes adjacent duplicates from an already-sorted slice of operand forms.
+func dedupeSortedForms(forms []string) []string {
+	deduped := forms[:0]
+	for index, form := range forms {
+		if index > 0 && forms[index-1] == form {
+			continue
+		}
+		deduped = append(deduped, form)
+	}
+	return deduped
+}
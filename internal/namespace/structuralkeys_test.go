@@ -0,0 +1,144 @@
+package namespace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ns "github.com/authzed/spicedb/pkg/namespace"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/schema"
+)
+
+func structuralKeysForNamespace(t *testing.T, nsDef *core.NamespaceDefinition) (map[string]string, error) {
+	t.Helper()
+
+	ts := schema.NewTypeSystem(schema.ResolverForPredefinedDefinitions(schema.PredefinedElements{
+		Definitions: []*core.NamespaceDefinition{ns.Namespace("user"), nsDef},
+	}))
+
+	def, err := schema.NewDefinition(ts, nsDef)
+	require.NoError(t, err)
+
+	vdef, err := def.Validate(t.Context())
+	require.NoError(t, err)
+
+	return StructuralCanonicalKeys(vdef)
+}
+
+func TestStructuralCanonicalKeys(t *testing.T) {
+	baseNamespace := ns.Namespace(
+		"document",
+		ns.MustRelation("owner", nil, ns.AllowedRelation("user", "...")),
+		ns.MustRelation("viewer", nil,
+			ns.AllowedRelation("user", "..."),
+			ns.AllowedPublicNamespace("user"),
+		),
+		ns.MustRelation("edit", ns.Union(
+			ns.ComputedUserset("owner"),
+		)),
+		ns.MustRelation("view", ns.Union(
+			ns.ComputedUserset("viewer"),
+			ns.ComputedUserset("edit"),
+		)),
+	)
+	baseKeys, err := structuralKeysForNamespace(t, baseNamespace)
+	require.NoError(t, err)
+
+	t.Run("renaming an intermediate permission leaves downstream keys unchanged", func(t *testing.T) {
+		renamedKeys, err := structuralKeysForNamespace(t, ns.Namespace(
+			"document",
+			ns.MustRelation("owner", nil, ns.AllowedRelation("user", "...")),
+			ns.MustRelation("viewer", nil,
+				ns.AllowedRelation("user", "..."),
+				ns.AllowedPublicNamespace("user"),
+			),
+			ns.MustRelation("editable", ns.Union(
+				ns.ComputedUserset("owner"),
+			)),
+			ns.MustRelation("view", ns.Union(
+				ns.ComputedUserset("viewer"),
+				ns.ComputedUserset("editable"),
+			)),
+		))
+		require.NoError(t, err)
+
+		require.Equal(t, baseKeys["edit"], renamedKeys["editable"])
+		require.Equal(t, baseKeys["view"], renamedKeys["view"])
+	})
+
+	t.Run("renaming a plain relation leaves downstream keys unchanged", func(t *testing.T) {
+		renamedKeys, err := structuralKeysForNamespace(t, ns.Namespace(
+			"document",
+			ns.MustRelation("owner", nil, ns.AllowedRelation("user", "...")),
+			ns.MustRelation("reader", nil,
+				ns.AllowedRelation("user", "..."),
+				ns.AllowedPublicNamespace("user"),
+			),
+			ns.MustRelation("edit", ns.Union(
+				ns.ComputedUserset("owner"),
+			)),
+			ns.MustRelation("view", ns.Union(
+				ns.ComputedUserset("reader"),
+				ns.ComputedUserset("edit"),
+			)),
+		))
+		require.NoError(t, err)
+
+		require.Equal(t, baseKeys["viewer"], renamedKeys["reader"])
+		require.Equal(t, baseKeys["view"], renamedKeys["view"])
+	})
+
+	t.Run("structural changes produce different keys", func(t *testing.T) {
+		changedKeys, err := structuralKeysForNamespace(t, ns.Namespace(
+			"document",
+			ns.MustRelation("owner", nil, ns.AllowedRelation("user", "...")),
+			ns.MustRelation("viewer", nil,
+				ns.AllowedRelation("user", "..."),
+				ns.AllowedPublicNamespace("user"),
+			),
+			ns.MustRelation("edit", ns.Union(
+				ns.ComputedUserset("owner"),
+			)),
+			ns.MustRelation("view", ns.Exclusion(
+				ns.ComputedUserset("viewer"),
+				ns.ComputedUserset("edit"),
+			)),
+		))
+		require.NoError(t, err)
+
+		require.Equal(t, baseKeys["edit"], changedKeys["edit"])
+		require.NotEqual(t, baseKeys["view"], changedKeys["view"])
+	})
+
+	t.Run("changing a plain relation's subject types changes dependents", func(t *testing.T) {
+		changedKeys, err := structuralKeysForNamespace(t, ns.Namespace(
+			"document",
+			ns.MustRelation("owner", nil, ns.AllowedRelation("user", "...")),
+			ns.MustRelation("viewer", nil,
+				ns.AllowedRelation("user", "..."),
+			),
+			ns.MustRelation("edit", ns.Union(
+				ns.ComputedUserset("owner"),
+			)),
+			ns.MustRelation("view", ns.Union(
+				ns.ComputedUserset("viewer"),
+				ns.ComputedUserset("edit"),
+			)),
+		))
+		require.NoError(t, err)
+
+		require.NotEqual(t, baseKeys["viewer"], changedKeys["viewer"])
+		require.NotEqual(t, baseKeys["view"], changedKeys["view"])
+	})
+
+	t.Run("self-referencing permission is rejected", func(t *testing.T) {
+		_, err := structuralKeysForNamespace(t, ns.Namespace(
+			"document",
+			ns.MustRelation("view", ns.Union(
+				ns.ComputedUserset("view"),
+			)),
+		))
+		require.ErrorContains(t, err, "cycle in permissions")
+	})
+}
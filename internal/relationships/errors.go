@@ -162,6 +162,88 @@ func (err CannotWriteToPermissionError) GRPCStatus() *status.Status {
 	)
 }
 
+// CaveatContextParameterTypeError indicates that a caveat context value on a written relationship
+// does not type-check against the parameter defined on the caveat.
+type CaveatContextParameterTypeError struct {
+	error
+	relationship  tuple.Relationship
+	parameterName string
+	expectedType  string
+}
+
+// NewCaveatContextParameterTypeError constructs a new error for a caveat context value that failed
+// type-checking against the caveat definition.
+func NewCaveatContextParameterTypeError(relationship tuple.Relationship, parameterName string, expectedType string, baseErr error) CaveatContextParameterTypeError {
+	return CaveatContextParameterTypeError{
+		error: fmt.Errorf(
+			"invalid caveat context for relationship `%s`: the value for parameter `%s` must be of type `%s`: %w",
+			tuple.MustString(relationship),
+			parameterName,
+			expectedType,
+			baseErr,
+		),
+		relationship:  relationship,
+		parameterName: parameterName,
+		expectedType:  expectedType,
+	}
+}
+
+// GRPCStatus implements retrieving the gRPC status for the error.
+func (err CaveatContextParameterTypeError) GRPCStatus() *status.Status {
+	return spiceerrors.WithCodeAndDetails(
+		err,
+		codes.InvalidArgument,
+		spiceerrors.ForReason(
+			v1.ErrorReason_ERROR_REASON_CAVEAT_PARAMETER_TYPE_ERROR,
+			map[string]string{
+				"relationship":   tuple.MustString(err.relationship),
+				"caveat_name":    err.relationship.OptionalCaveat.CaveatName,
+				"parameter_name": err.parameterName,
+				"expected_type":  err.expectedType,
+			},
+		),
+	)
+}
+
+// UnknownCaveatContextKeyError indicates that a caveat context on a written relationship contains
+// a key which is not defined as a parameter on the caveat.
+type UnknownCaveatContextKeyError struct {
+	error
+	relationship tuple.Relationship
+	key          string
+}
+
+// NewUnknownCaveatContextKeyError constructs a new error for a caveat context key that does not
+// match any parameter defined on the caveat.
+func NewUnknownCaveatContextKeyError(relationship tuple.Relationship, key string) UnknownCaveatContextKeyError {
+	return UnknownCaveatContextKeyError{
+		error: fmt.Errorf(
+			"invalid caveat context for relationship `%s`: the caveat `%s` has no parameter named `%s`",
+			tuple.MustString(relationship),
+			relationship.OptionalCaveat.CaveatName,
+			key,
+		),
+		relationship: relationship,
+		key:          key,
+	}
+}
+
+// GRPCStatus implements retrieving the gRPC status for the error.
+func (err UnknownCaveatContextKeyError) GRPCStatus() *status.Status {
+	return spiceerrors.WithCodeAndDetails(
+		err,
+		codes.InvalidArgument,
+		spiceerrors.ForReason(
+			v1.ErrorReason_ERROR_REASON_CAVEAT_PARAMETER_TYPE_ERROR,
+			map[string]string{
+				"relationship": tuple.MustString(err.relationship),
+				"caveat_name":  err.relationship.OptionalCaveat.CaveatName,
+				"unknown_key":  err.key,
+			},
+		),
+	)
+}
+
 // CaveatNotFoundError indicates that a caveat referenced in a relationship update was not found.
 type CaveatNotFoundError struct {
 	error
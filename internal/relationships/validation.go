@@ -2,6 +2,7 @@ package relationships
 
 import (
 	"context"
+	"errors"
 
 	"github.com/authzed/spicedb/internal/namespace"
 	"github.com/authzed/spicedb/pkg/caveats"
@@ -16,13 +17,40 @@ import (
 	"github.com/authzed/spicedb/pkg/tuple"
 )
 
+// UnknownCaveatContextKeyOption is the option around handling of caveat context keys which do
+// not match any parameter defined on the caveat.
+type UnknownCaveatContextKeyOption int
+
+const (
+	// ErrorForUnknownCaveatContextKeys indicates that unknown caveat context keys should reject
+	// the write. This is the default.
+	ErrorForUnknownCaveatContextKeys UnknownCaveatContextKeyOption = 0
+
+	// AllowAndStripUnknownCaveatContextKeys indicates that unknown caveat context keys should be
+	// stripped from the context before the relationship is written.
+	AllowAndStripUnknownCaveatContextKeys UnknownCaveatContextKeyOption = 1
+)
+
 // ValidateRelationshipUpdates performs validation on the given relationship updates, ensuring that
-// they can be applied against the datastore.
+// they can be applied against the datastore. Unknown caveat context keys reject the write.
 func ValidateRelationshipUpdates(
 	ctx context.Context,
 	reader datastore.Reader,
 	caveatTypeSet *caveattypes.TypeSet,
 	updates []tuple.RelationshipUpdate,
+) error {
+	return ValidateRelationshipUpdatesWithOptions(ctx, reader, caveatTypeSet, ErrorForUnknownCaveatContextKeys, updates)
+}
+
+// ValidateRelationshipUpdatesWithOptions performs validation on the given relationship updates,
+// ensuring that they can be applied against the datastore, with the given handling for unknown
+// caveat context keys.
+func ValidateRelationshipUpdatesWithOptions(
+	ctx context.Context,
+	reader datastore.Reader,
+	caveatTypeSet *caveattypes.TypeSet,
+	unknownKeyOption UnknownCaveatContextKeyOption,
+	updates []tuple.RelationshipUpdate,
 ) error {
 	rels := slicez.Map(updates, func(item tuple.RelationshipUpdate) tuple.Relationship {
 		return item.Relationship
@@ -41,12 +69,13 @@ func ValidateRelationshipUpdates(
 			option = ValidateRelationshipForDeletion
 		}
 
-		if err := ValidateOneRelationship(
+		if err := ValidateOneRelationshipWithOptions(
 			referencedNamespaceMap,
 			referencedCaveatMap,
 			caveatTypeSet,
 			update.Relationship,
 			option,
+			unknownKeyOption,
 		); err != nil {
 			return err
 		}
@@ -144,13 +173,27 @@ const (
 	ValidateRelationshipForDeletion ValidationRelationshipRule = 1
 )
 
-// ValidateOneRelationship validates a single relationship for CREATE/TOUCH or DELETE.
+// ValidateOneRelationship validates a single relationship for CREATE/TOUCH or DELETE. Unknown
+// caveat context keys reject the relationship.
 func ValidateOneRelationship(
 	namespaceMap map[string]*schema.Definition,
 	caveatMap map[string]*core.CaveatDefinition,
 	caveatTypeSet *caveattypes.TypeSet,
 	rel tuple.Relationship,
 	rule ValidationRelationshipRule,
+) error {
+	return ValidateOneRelationshipWithOptions(namespaceMap, caveatMap, caveatTypeSet, rel, rule, ErrorForUnknownCaveatContextKeys)
+}
+
+// ValidateOneRelationshipWithOptions validates a single relationship for CREATE/TOUCH or DELETE,
+// with the given handling for unknown caveat context keys.
+func ValidateOneRelationshipWithOptions(
+	namespaceMap map[string]*schema.Definition,
+	caveatMap map[string]*core.CaveatDefinition,
+	caveatTypeSet *caveattypes.TypeSet,
+	rel tuple.Relationship,
+	rule ValidationRelationshipRule,
+	unknownKeyOption UnknownCaveatContextKeyOption,
 ) error {
 	// Validate the IDs of the resource and subject.
 	if err := tuple.ValidateResourceID(rel.Resource.ObjectID); err != nil {
@@ -256,6 +299,20 @@ func ValidateOneRelationship(
 			return NewCaveatNotFoundError(rel)
 		}
 
+		// Handle any context keys which do not match a parameter defined on the caveat.
+		for key := range rel.OptionalCaveat.Context.GetFields() {
+			if _, ok := caveat.ParameterTypes[key]; ok {
+				continue
+			}
+
+			if unknownKeyOption == AllowAndStripUnknownCaveatContextKeys {
+				delete(rel.OptionalCaveat.Context.Fields, key)
+				continue
+			}
+
+			return NewUnknownCaveatContextKeyError(rel, key)
+		}
+
 		// Verify that the provided context information matches the types of the parameters defined.
 		_, err := caveats.ConvertContextToParameters(
 			caveatTypeSet,
@@ -264,6 +321,12 @@ func ValidateOneRelationship(
 			caveats.ErrorForUnknownParameters,
 		)
 		if err != nil {
+			var conversionErr caveats.ParameterConversionError
+			if errors.As(err, &conversionErr) {
+				expectedType := caveats.ParameterTypeString(caveat.ParameterTypes[conversionErr.ParameterName()])
+				return NewCaveatContextParameterTypeError(rel, conversionErr.ParameterName(), expectedType, err)
+			}
+
 			return err
 		}
 	}
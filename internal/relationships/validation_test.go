@@ -34,6 +34,16 @@ definition resource {
 	permission view = viewer
 }`
 
+const mapCaveatSchema = `definition user {}
+
+caveat mapcaveat(somemap map<int>) {
+	somemap.first == 42
+}
+
+definition resource {
+	relation viewer: user | user with mapcaveat
+}`
+
 func TestValidateRelationshipOperations(t *testing.T) {
 	t.Parallel()
 
@@ -306,6 +316,34 @@ func TestValidateRelationshipOperations(t *testing.T) {
 			core.RelationTupleUpdate_CREATE,
 			"subjects of type `user with somecaveat and expiration` are not allowed on relation `resource#viewer`",
 		},
+		{
+			"create with caveat context of the wrong type",
+			basicSchema,
+			`resource:fo#viewer@user:tom[somecaveat:{"somecondition": "fortytwo"}]`,
+			core.RelationTupleUpdate_CREATE,
+			"the value for parameter `somecondition` must be of type `int`",
+		},
+		{
+			"create with unknown caveat context key",
+			basicSchema,
+			`resource:fo#viewer@user:tom[somecaveat:{"somecondition": 42, "unknownkey": true}]`,
+			core.RelationTupleUpdate_CREATE,
+			"the caveat `somecaveat` has no parameter named `unknownkey`",
+		},
+		{
+			"create with valid nested struct caveat context",
+			mapCaveatSchema,
+			`resource:fo#viewer@user:tom[mapcaveat:{"somemap": {"first": 42}}]`,
+			core.RelationTupleUpdate_CREATE,
+			"",
+		},
+		{
+			"create with invalid nested struct caveat context",
+			mapCaveatSchema,
+			`resource:fo#viewer@user:tom[mapcaveat:{"somemap": {"first": "fortytwo"}}]`,
+			core.RelationTupleUpdate_CREATE,
+			"the value for parameter `somemap` must be of type `map<int>`",
+		},
 	}
 
 	for _, tc := range tcs {
@@ -347,3 +385,29 @@ func TestValidateRelationshipOperations(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRelationshipUpdatesAllowAndStripUnknownKeys(t *testing.T) {
+	t.Parallel()
+	req := require.New(t)
+
+	ds, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	req.NoError(err)
+
+	uds, rev := testfixtures.DatastoreFromSchemaAndTestRelationships(ds, basicSchema, nil, req)
+	reader := uds.SnapshotReader(rev)
+
+	update := tuple.Create(tuple.MustParse(`resource:fo#viewer@user:tom[somecaveat:{"somecondition": 42, "unknownkey": true}]`))
+	err = ValidateRelationshipUpdatesWithOptions(t.Context(), reader, caveattypes.Default.TypeSet, AllowAndStripUnknownCaveatContextKeys, []tuple.RelationshipUpdate{update})
+	req.NoError(err)
+
+	// The unknown key is stripped from the context, leaving the known parameters intact.
+	fields := update.Relationship.OptionalCaveat.Context.GetFields()
+	req.NotContains(fields, "unknownkey")
+	req.Contains(fields, "somecondition")
+
+	// Values of the wrong type are still rejected when stripping unknown keys.
+	err = ValidateRelationshipUpdatesWithOptions(t.Context(), reader, caveattypes.Default.TypeSet, AllowAndStripUnknownCaveatContextKeys, []tuple.RelationshipUpdate{
+		tuple.Create(tuple.MustParse(`resource:fo#viewer@user:tom[somecaveat:{"somecondition": "fortytwo"}]`)),
+	})
+	req.ErrorContains(err, "the value for parameter `somecondition` must be of type `int`")
+}
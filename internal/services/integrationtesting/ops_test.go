@@ -534,7 +534,7 @@ func TestSchemaAndRelationshipsOperations(t *testing.T) {
 							"someUnknownParam": "",
 						},
 					},
-					"unknown parameter `someUnknownParam`",
+					"the caveat `somecaveat` has no parameter named `someUnknownParam`",
 				},
 			},
 		},
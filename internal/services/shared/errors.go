@@ -22,6 +22,7 @@ import (
 	"github.com/authzed/spicedb/pkg/schema"
 	"github.com/authzed/spicedb/pkg/schemadsl/compiler"
 	"github.com/authzed/spicedb/pkg/spiceerrors"
+	"github.com/authzed/spicedb/pkg/tuple"
 )
 
 // ErrServiceReadOnly is an extended GRPC error returned when a service is in read-only mode.
@@ -110,6 +111,25 @@ func NewMaxDepthExceededError(allowedMaximumDepth uint32, isCheckRequest bool) e
 	}
 }
 
+// fieldPathForParseSegment maps a relationship parse segment onto the corresponding field
+// path of the v1 Relationship message.
+func fieldPathForParseSegment(segment tuple.ParseSegment) string {
+	switch segment {
+	case tuple.ParseSegmentResource:
+		return "relationship.resource"
+	case tuple.ParseSegmentRelation:
+		return "relationship.relation"
+	case tuple.ParseSegmentSubject:
+		return "relationship.subject"
+	case tuple.ParseSegmentCaveat:
+		return "relationship.optional_caveat"
+	case tuple.ParseSegmentExpiration:
+		return "relationship.optional_expires_at"
+	default:
+		return "relationship"
+	}
+}
+
 func AsValidationError(err error) *SchemaWriteDataValidationError {
 	var validationErr SchemaWriteDataValidationError
 	if errors.As(err, &validationErr) {
@@ -148,17 +168,38 @@ func rewriteError(ctx context.Context, err error, config *ConfigForErrors) error
 	var compilerError compiler.BaseCompilerError
 	var sourceError *spiceerrors.WithSourceError
 	var typeError schema.TypeError
+	var parseError tuple.ParseError
 
 	switch {
 	case errors.As(err, &typeError):
+		// Validation can report multiple independent type errors joined into a single error;
+		// surface each as its own metadata entry so callers see every problem found.
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			metadata := map[string]string{}
+			for index, validationErr := range joined.Unwrap() {
+				metadata["validation_error_"+strconv.Itoa(index)] = validationErr.Error()
+			}
+			return spiceerrors.WithCodeAndDetailsAsError(err, codes.FailedPrecondition,
+				spiceerrors.ForReason(v1.ErrorReason_ERROR_REASON_SCHEMA_TYPE_ERROR, metadata))
+		}
+
 		return spiceerrors.WithCodeAndReason(err, codes.FailedPrecondition, v1.ErrorReason_ERROR_REASON_SCHEMA_TYPE_ERROR)
 	case errors.As(err, &compilerError):
 		return spiceerrors.WithCodeAndReason(err, codes.InvalidArgument, v1.ErrorReason_ERROR_REASON_SCHEMA_PARSE_ERROR)
 	case errors.As(err, &sourceError):
 		return spiceerrors.WithCodeAndReason(err, codes.InvalidArgument, v1.ErrorReason_ERROR_REASON_SCHEMA_PARSE_ERROR)
+	case errors.As(err, &parseError):
+		return spiceerrors.WithCodeAndDetailsAsError(err, codes.InvalidArgument,
+			spiceerrors.ForReason(v1.ErrorReason_ERROR_REASON_UNSPECIFIED, map[string]string{
+				"field_path":  fieldPathForParseSegment(parseError.Segment),
+				"byte_offset": strconv.Itoa(parseError.ByteOffset),
+				"expectation": parseError.Expectation,
+			}))
 
 	case errors.Is(err, cursor.ErrHashMismatch):
 		return spiceerrors.WithCodeAndReason(err, codes.FailedPrecondition, v1.ErrorReason_ERROR_REASON_INVALID_CURSOR)
+	case errors.As(err, &datastore.CursorRevisionMismatchError{}):
+		return spiceerrors.WithCodeAndReason(err, codes.FailedPrecondition, v1.ErrorReason_ERROR_REASON_INVALID_CURSOR)
 
 	case errors.As(err, &nsNotFoundError):
 		return spiceerrors.WithCodeAndReason(err, codes.FailedPrecondition, v1.ErrorReason_ERROR_REASON_UNKNOWN_DEFINITION)
@@ -168,7 +209,9 @@ func rewriteError(ctx context.Context, err error, config *ConfigForErrors) error
 	case errors.As(err, &datastore.ReadOnlyError{}):
 		return ErrServiceReadOnly
 	case errors.As(err, &datastore.InvalidRevisionError{}):
-		return status.Errorf(codes.OutOfRange, "invalid zedtoken: %s", err)
+		// NOTE: WithCodeAndReason attaches the error's metadata, which includes the
+		// recommended minimum revision for stale revisions, as an error detail.
+		return spiceerrors.WithCodeAndReason(fmt.Errorf("invalid zedtoken: %w", err), codes.OutOfRange, v1.ErrorReason_ERROR_REASON_UNSPECIFIED)
 	case errors.As(err, &datastore.CaveatNameNotFoundError{}):
 		return spiceerrors.WithCodeAndReason(err, codes.FailedPrecondition, v1.ErrorReason_ERROR_REASON_UNKNOWN_CAVEAT)
 	case errors.As(err, &datastore.WatchDisabledError{}):
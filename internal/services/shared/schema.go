@@ -3,6 +3,7 @@ package shared
 import (
 	"context"
 	"maps"
+	"strconv"
 
 	log "github.com/authzed/spicedb/internal/logging"
 	"github.com/authzed/spicedb/internal/namespace"
@@ -22,16 +23,18 @@ import (
 
 // ValidatedSchemaChanges is a set of validated schema changes that can be applied to the datastore.
 type ValidatedSchemaChanges struct {
-	compiled             *compiler.CompiledSchema
-	validatedTypeSystems map[string]*schema.ValidatedDefinition
-	newCaveatDefNames    *mapz.Set[string]
-	newObjectDefNames    *mapz.Set[string]
-	additiveOnly         bool
+	compiled                *compiler.CompiledSchema
+	validatedTypeSystems    map[string]*schema.ValidatedDefinition
+	newCaveatDefNames       *mapz.Set[string]
+	newObjectDefNames       *mapz.Set[string]
+	additiveOnly            bool
+	forceAllowedTypeRemoval bool
 }
 
 // ValidateSchemaChanges validates the schema found in the compiled schema and returns a
-// ValidatedSchemaChanges, if fully validated.
-func ValidateSchemaChanges(ctx context.Context, compiled *compiler.CompiledSchema, caveatTypeSet *caveattypes.TypeSet, additiveOnly bool) (*ValidatedSchemaChanges, error) {
+// ValidatedSchemaChanges, if fully validated. If forceAllowedTypeRemoval is true, allowed
+// subject types may be removed from relations even when relationships using them exist.
+func ValidateSchemaChanges(ctx context.Context, compiled *compiler.CompiledSchema, caveatTypeSet *caveattypes.TypeSet, additiveOnly bool, forceAllowedTypeRemoval bool) (*ValidatedSchemaChanges, error) {
 	// 1) Validate the caveats defined.
 	newCaveatDefNames := mapz.NewSet[string]()
 	for _, caveatDef := range compiled.CaveatDefinitions {
@@ -62,11 +65,12 @@ func ValidateSchemaChanges(ctx context.Context, compiled *compiler.CompiledSchem
 	}
 
 	return &ValidatedSchemaChanges{
-		compiled:             compiled,
-		validatedTypeSystems: validatedTypeSystems,
-		newCaveatDefNames:    newCaveatDefNames,
-		newObjectDefNames:    newObjectDefNames,
-		additiveOnly:         additiveOnly,
+		compiled:                compiled,
+		validatedTypeSystems:    validatedTypeSystems,
+		newCaveatDefNames:       newCaveatDefNames,
+		newObjectDefNames:       newObjectDefNames,
+		additiveOnly:            additiveOnly,
+		forceAllowedTypeRemoval: forceAllowedTypeRemoval,
 	}, nil
 }
 
@@ -151,7 +155,7 @@ func ApplySchemaChangesOverExisting(
 	// breaking changes.
 	objectDefsWithChanges := make([]*core.NamespaceDefinition, 0, len(validated.compiled.ObjectDefinitions))
 	for _, nsdef := range validated.compiled.ObjectDefinitions {
-		diff, err := sanityCheckNamespaceChanges(ctx, rwt, nsdef, existingObjectDefMap)
+		diff, err := sanityCheckNamespaceChanges(ctx, rwt, nsdef, existingObjectDefMap, validated.forceAllowedTypeRemoval)
 		if err != nil {
 			return nil, err
 		}
@@ -306,6 +310,7 @@ func sanityCheckNamespaceChanges(
 	rwt datastore.ReadWriteTransaction,
 	nsdef *core.NamespaceDefinition,
 	existingDefs map[string]*core.NamespaceDefinition,
+	forceAllowedTypeRemoval bool,
 ) (*nsdiff.Diff, error) {
 	// Ensure that the updated namespace does not break the existing tuple data.
 	existing := existingDefs[nsdef.Name]
@@ -401,6 +406,10 @@ func sanityCheckNamespaceChanges(
 			}
 
 		case nsdiff.RelationAllowedTypeRemoved:
+			if forceAllowedTypeRemoval {
+				continue
+			}
+
 			var optionalSubjectIds []string
 			var optionalCaveatNameFilter datastore.CaveatNameFilter
 			if delta.AllowedType.GetPublicWildcard() != nil {
@@ -433,10 +442,10 @@ func sanityCheckNamespaceChanges(
 					OptionalCaveatNameFilter: optionalCaveatNameFilter,
 					OptionalExpirationOption: expirationOption,
 				},
-				options.WithLimit(options.LimitOne),
+				options.WithLimit(&allowedTypeRemovalSampleLimit),
 				options.WithQueryShape(queryshape.FindResourceRelationForSubjectRelation),
 			)
-			err = errorIfTupleIteratorReturnsTuples(
+			err = errorIfTupleIteratorReturnsTuplesWithSampleCount(
 				ctx,
 				qyr,
 				qyrErr,
@@ -466,6 +475,50 @@ func subjectRelationFilterForAllowedType(allowedType *core.AllowedRelation) data
 	return datastore.SubjectRelationFilter{}.WithRelation(rel)
 }
 
+// allowedTypeRemovalSampleLimit caps the number of relationships counted when reporting that an
+// allowed type cannot be removed because relationships using it exist.
+var allowedTypeRemovalSampleLimit uint64 = 100
+
+// errorIfTupleIteratorReturnsTuplesWithSampleCount is like errorIfTupleIteratorReturnsTuples, but
+// consumes the iterator, up to the limit placed on the query, and includes the number of
+// relationships found in the returned error's message and metadata.
+func errorIfTupleIteratorReturnsTuplesWithSampleCount(_ context.Context, qy datastore.RelationshipIterator, qyErr error, message string, args []any, metadata map[string]string) error {
+	if qyErr != nil {
+		return qyErr
+	}
+
+	foundCount := 0
+	var sampleRelationship string
+	for rel, err := range qy {
+		if err != nil {
+			return err
+		}
+
+		if foundCount == 0 {
+			strValue, err := tuple.String(rel)
+			if err != nil {
+				return err
+			}
+
+			sampleRelationship = strValue
+		}
+		foundCount++
+	}
+
+	if foundCount == 0 {
+		return nil
+	}
+
+	fullMetadata := maps.Clone(metadata)
+	if fullMetadata == nil {
+		fullMetadata = make(map[string]string)
+	}
+	fullMetadata["relationship"] = sampleRelationship
+	fullMetadata["sampled_relationship_count"] = strconv.Itoa(foundCount)
+	newArgs := append(args, foundCount, sampleRelationship)
+	return NewSchemaWriteDataValidationError(message+" (sampled count: %d): %s", newArgs, fullMetadata)
+}
+
 // errorIfTupleIteratorReturnsTuples takes a tuple iterator and any error that was generated
 // when the original iterator was created, and returns an error if iterator contains any tuples.
 func errorIfTupleIteratorReturnsTuples(_ context.Context, qy datastore.RelationshipIterator, qyErr error, message string, args []any, metadata map[string]string) error {
@@ -22,6 +22,7 @@ func TestApplySchemaChanges(t *testing.T) {
 		startingSchema               string
 		relationships                []string
 		endingSchema                 string
+		forceAllowedTypeRemoval      bool
 		expectedAppliedSchemaChanges AppliedSchemaChanges
 		expectedError                string
 	}{
@@ -287,7 +288,7 @@ func TestApplySchemaChanges(t *testing.T) {
 					permission view = viewer
 				}
 			`,
-			expectedError: "cannot remove allowed type `group#member` from relation `viewer` in object definition `document`, as a relationship exists with it: document:somedoc#viewer@group:somegroup#member",
+			expectedError: "cannot remove allowed type `group#member` from relation `viewer` in object definition `document`, as a relationship exists with it (sampled count: 1): document:somedoc#viewer@group:somegroup#member",
 		},
 		{
 			name: "attempt to remove non-caveated type when only caveated relationship exists",
@@ -403,7 +404,7 @@ func TestApplySchemaChanges(t *testing.T) {
 					permission view = reader
 				}
 			`,
-			expectedError: "cannot remove allowed type `user` from relation `reader` in object definition `document`, as a relationship exists with it: document:firstdoc#reader@user:tom",
+			expectedError: "cannot remove allowed type `user` from relation `reader` in object definition `document`, as a relationship exists with it (sampled count: 1): document:firstdoc#reader@user:tom",
 		},
 		{
 			name: "delete a subject type while adding a replacement",
@@ -480,7 +481,7 @@ func TestApplySchemaChanges(t *testing.T) {
 					permission view = reader
 				}
 			`,
-			expectedError: "cannot remove allowed type `user` from relation `reader` in object definition `document`, as a relationship exists with it: document:firstdoc#reader@user:tom",
+			expectedError: "cannot remove allowed type `user` from relation `reader` in object definition `document`, as a relationship exists with it (sampled count: 1): document:firstdoc#reader@user:tom",
 		},
 		{
 			name: "attempt to delete an indirect subject type while direct remains",
@@ -505,7 +506,7 @@ func TestApplySchemaChanges(t *testing.T) {
 					permission view = reader
 				}
 			`,
-			expectedError: "cannot remove allowed type `user#foo` from relation `reader` in object definition `document`, as a relationship exists with it: document:firstdoc#reader@user:tom#foo",
+			expectedError: "cannot remove allowed type `user#foo` from relation `reader` in object definition `document`, as a relationship exists with it (sampled count: 1): document:firstdoc#reader@user:tom#foo",
 		},
 		{
 			name: "delete an indirect subject type while direct remains",
@@ -585,7 +586,56 @@ definition resource {
 				relation foo2: user
 			}
 			`,
-			expectedError: "cannot remove allowed type `user#foo` from relation `viewer` in object definition `document`, as a relationship exists with it: document:firstdoc#viewer@user:tom#foo",
+			expectedError: "cannot remove allowed type `user#foo` from relation `viewer` in object definition `document`, as a relationship exists with it (sampled count: 1): document:firstdoc#viewer@user:tom#foo",
+		},
+		{
+			name: "attempt to delete a subject type with multiple relationships reports the sampled count",
+			startingSchema: `
+				definition user {}
+
+				definition document {
+					relation reader: user
+					permission view = reader
+				}
+			`,
+			relationships: []string{
+				"document:firstdoc#reader@user:fred",
+				"document:firstdoc#reader@user:tom",
+				"document:seconddoc#reader@user:sarah",
+			},
+			endingSchema: `
+				definition user {}
+
+				definition document {
+					relation reader: user:*
+					permission view = reader
+				}
+			`,
+			expectedError: "cannot remove allowed type `user` from relation `reader` in object definition `document`, as a relationship exists with it (sampled count: 3): document:firstdoc#reader@user:fred",
+		},
+		{
+			name: "forced deletion of a subject type with existing data",
+			startingSchema: `
+				definition user {}
+
+				definition document {
+					relation reader: user
+					permission view = reader
+				}
+			`,
+			relationships:           []string{"document:firstdoc#reader@user:tom"},
+			forceAllowedTypeRemoval: true,
+			endingSchema: `
+				definition user {}
+
+				definition document {
+					relation reader: user:*
+					permission view = reader
+				}
+			`,
+			expectedAppliedSchemaChanges: AppliedSchemaChanges{
+				TotalOperationCount: 2,
+			},
 		},
 	}
 
@@ -610,7 +660,7 @@ definition resource {
 			}, compiler.AllowUnprefixedObjectType())
 			require.NoError(err)
 
-			validated, err := ValidateSchemaChanges(t.Context(), compiled, caveattypes.Default.TypeSet, false)
+			validated, err := ValidateSchemaChanges(t.Context(), compiled, caveattypes.Default.TypeSet, false, tc.forceAllowedTypeRemoval)
 			if tc.expectedError != "" && err != nil && tc.expectedError == err.Error() {
 				return
 			}
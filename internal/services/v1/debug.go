@@ -125,7 +125,8 @@ func convertCheckTrace(ctx context.Context, caveatContext map[string]any, ct *di
 		partialCheckResult := partialResults[0]
 		spiceerrors.DebugAssertNotNilf(partialCheckResult.Expression, "got nil caveat expression")
 
-		computedResult, err := cexpr.RunSingleCaveatExpression(ctx, caveatTypeSet, partialCheckResult.Expression, caveatContext, reader, cexpr.RunCaveatExpressionWithDebugInformation)
+		runner := cexpr.NewCaveatRunner(caveatTypeSet)
+		computedResult, err := runner.RunCaveatExpression(ctx, partialCheckResult.Expression, caveatContext, reader, cexpr.RunCaveatExpressionWithDebugInformation)
 		if err != nil {
 			return nil, err
 		}
@@ -147,6 +148,12 @@ func convertCheckTrace(ctx context.Context, caveatContext map[string]any, ct *di
 			return nil, err
 		}
 
+		// Redact the values of any context parameters designated as sensitive before they are
+		// returned to the caller.
+		if err := runner.RedactSensitiveContext(partialCheckResult.Expression, contextStruct); err != nil {
+			return nil, err
+		}
+
 		caveatName := ""
 		if partialCheckResult.Expression.GetCaveat() != nil {
 			caveatName = partialCheckResult.Expression.GetCaveat().CaveatName
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc/codes"
@@ -412,6 +413,57 @@ func NewInvalidFilterErr(reason string, filter string) InvalidFilterError {
 	}
 }
 
+// InvalidBulkImportRelationshipError occurs when a relationship in a bulk import stream
+// fails validation. It carries every field-level violation found on the relationship.
+type InvalidBulkImportRelationshipError struct {
+	error
+	lineNumber  uint64
+	fieldErrors []tuple.FieldError
+}
+
+// MarshalZerologObject implements zerolog object marshalling.
+func (err InvalidBulkImportRelationshipError) MarshalZerologObject(e *zerolog.Event) {
+	e.Err(err.error).Uint64("lineNumber", err.lineNumber)
+}
+
+// GRPCStatus implements retrieving the gRPC status for the error.
+func (err InvalidBulkImportRelationshipError) GRPCStatus() *status.Status {
+	details := make(map[string]string, len(err.fieldErrors)+1)
+	details["line_number"] = strconv.FormatUint(err.lineNumber, 10)
+	for _, fieldError := range err.fieldErrors {
+		details[fieldError.FieldPath] = fieldError.Message
+	}
+
+	return spiceerrors.WithCodeAndDetails(
+		err,
+		codes.InvalidArgument,
+		spiceerrors.ForReason(
+			v1.ErrorReason_ERROR_REASON_UNSPECIFIED,
+			details,
+		),
+	)
+}
+
+// NewInvalidBulkImportRelationshipError constructs an error for a relationship that failed
+// detailed validation during a bulk import, aggregating every field-level violation.
+func NewInvalidBulkImportRelationshipError(rel tuple.Relationship, lineNumber uint64, fieldErrors []tuple.FieldError) InvalidBulkImportRelationshipError {
+	messages := make([]string, 0, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		messages = append(messages, fieldError.Error())
+	}
+
+	return InvalidBulkImportRelationshipError{
+		error: fmt.Errorf(
+			"invalid relationship `%s` on line %d: %s",
+			rel.RelationshipReference.String(),
+			lineNumber,
+			strings.Join(messages, "; "),
+		),
+		lineNumber:  lineNumber,
+		fieldErrors: fieldErrors,
+	}
+}
+
 // NewEmptyPreconditionErr constructs a new empty precondition error.
 func NewEmptyPreconditionErr() EmptyPreconditionError {
 	return EmptyPreconditionError{
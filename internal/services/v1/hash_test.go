@@ -227,7 +227,7 @@ func TestLRHashStability(t *testing.T) {
 				},
 				OptionalLimit: 1000,
 			},
-			"f5c7ca6296253717",
+			"7c79ed0abcff4def",
 		},
 		{
 			"different LR subject",
@@ -247,7 +247,7 @@ func TestLRHashStability(t *testing.T) {
 				},
 				OptionalLimit: 1000,
 			},
-			"aa8b67b886ecf3fd",
+			"2ce5f8e749f6673a",
 		},
 		{
 			"different LR resource",
@@ -267,7 +267,7 @@ func TestLRHashStability(t *testing.T) {
 				},
 				OptionalLimit: 1000,
 			},
-			"fb16e4dd9395864a",
+			"f1ab205f0b993435",
 		},
 		{
 			"different LR resource permission",
@@ -287,7 +287,7 @@ func TestLRHashStability(t *testing.T) {
 				},
 				OptionalLimit: 1000,
 			},
-			"593e60bf77f8bdb4",
+			"4f5d7755c841a2f0",
 		},
 		{
 			"different limit LR",
@@ -307,7 +307,7 @@ func TestLRHashStability(t *testing.T) {
 				},
 				OptionalLimit: 999,
 			},
-			"0097cf2ee303ec31",
+			"08de61152a99c61b",
 		},
 		{
 			"LR with different consistency",
@@ -327,7 +327,7 @@ func TestLRHashStability(t *testing.T) {
 				},
 				OptionalLimit: 1000,
 			},
-			"d8b707db35cb7043",
+			"dd29d9de572d805a",
 		},
 		{
 			"basic LR with caveat context",
@@ -354,7 +354,7 @@ func TestLRHashStability(t *testing.T) {
 					return s
 				}(),
 			},
-			"d40193c84ec59e6f",
+			"e6dbc5ff221ce26e",
 		},
 		{
 			"basic LR with different caveat context",
@@ -381,7 +381,7 @@ func TestLRHashStability(t *testing.T) {
 					return s
 				}(),
 			},
-			"a5af756163998c88",
+			"b51403fc96a10267",
 		},
 	}
 
@@ -419,7 +419,7 @@ func TestCheckBulkPermissionsItemWithoutResourceIDHashStability(t *testing.T) {
 					},
 				},
 			},
-			"f518629690bd9dc0",
+			"bf3f747f39ea0500",
 		},
 		{
 			"different resource ID, should still be the same hash",
@@ -436,7 +436,7 @@ func TestCheckBulkPermissionsItemWithoutResourceIDHashStability(t *testing.T) {
 					},
 				},
 			},
-			"f518629690bd9dc0",
+			"bf3f747f39ea0500",
 		},
 		{
 			"basic bulk check item - transcribed letter",
@@ -453,7 +453,7 @@ func TestCheckBulkPermissionsItemWithoutResourceIDHashStability(t *testing.T) {
 					},
 				},
 			},
-			"60f1e177297e915e",
+			"d8a33487ac996d07",
 		},
 		{
 			"different resource type",
@@ -470,7 +470,7 @@ func TestCheckBulkPermissionsItemWithoutResourceIDHashStability(t *testing.T) {
 					},
 				},
 			},
-			"5117abaee3adf638",
+			"8a65a108a2caf317",
 		},
 		{
 			"different permission",
@@ -487,7 +487,7 @@ func TestCheckBulkPermissionsItemWithoutResourceIDHashStability(t *testing.T) {
 					},
 				},
 			},
-			"716f7be27e600292",
+			"e72def01138bcbad",
 		},
 		{
 			"different subject type",
@@ -504,7 +504,7 @@ func TestCheckBulkPermissionsItemWithoutResourceIDHashStability(t *testing.T) {
 					},
 				},
 			},
-			"7cb5945314ccbdce",
+			"3be7d35778902971",
 		},
 		{
 			"different subject id",
@@ -521,7 +521,7 @@ func TestCheckBulkPermissionsItemWithoutResourceIDHashStability(t *testing.T) {
 					},
 				},
 			},
-			"b24ecacf87fd0bb8",
+			"d2ef0078cd7f9f4e",
 		},
 		{
 			"different subject relation",
@@ -539,7 +539,7 @@ func TestCheckBulkPermissionsItemWithoutResourceIDHashStability(t *testing.T) {
 					OptionalRelation: "foo",
 				},
 			},
-			"ee8c34ab206c80d7",
+			"23d2d545ce776e62",
 		},
 		{
 			"with context",
@@ -564,7 +564,7 @@ func TestCheckBulkPermissionsItemWithoutResourceIDHashStability(t *testing.T) {
 					return s
 				}(),
 			},
-			"7a5b1fec3cbed446",
+			"be6e5f0e045f0a72",
 		},
 		{
 			"with different context",
@@ -589,7 +589,7 @@ func TestCheckBulkPermissionsItemWithoutResourceIDHashStability(t *testing.T) {
 					return s
 				}(),
 			},
-			"f17da513a6207c30",
+			"0b70fab1f5457522",
 		},
 	}
 
@@ -627,7 +627,7 @@ func TestCheckBulkPermissionsItemWIDHashStability(t *testing.T) {
 					},
 				},
 			},
-			"5edbb3bbb8079754",
+			"48df1ca41b086e3c",
 		},
 		{
 			"different resource ID, should be a different hash",
@@ -644,7 +644,7 @@ func TestCheckBulkPermissionsItemWIDHashStability(t *testing.T) {
 					},
 				},
 			},
-			"e6711064500e65ba",
+			"771d2745776aec6a",
 		},
 		{
 			"basic bulk check item - transcribed letter",
@@ -661,7 +661,7 @@ func TestCheckBulkPermissionsItemWIDHashStability(t *testing.T) {
 					},
 				},
 			},
-			"8cda00b7188572b7",
+			"1fef61e330984141",
 		},
 		{
 			"different resource type",
@@ -678,7 +678,7 @@ func TestCheckBulkPermissionsItemWIDHashStability(t *testing.T) {
 					},
 				},
 			},
-			"51df43a69e51d3b0",
+			"730ffc266baca7c1",
 		},
 		{
 			"different permission",
@@ -695,7 +695,7 @@ func TestCheckBulkPermissionsItemWIDHashStability(t *testing.T) {
 					},
 				},
 			},
-			"62aaa50b2821130d",
+			"cd20d4319797d36a",
 		},
 		{
 			"different subject type",
@@ -712,7 +712,7 @@ func TestCheckBulkPermissionsItemWIDHashStability(t *testing.T) {
 					},
 				},
 			},
-			"82a445d3ffc0823a",
+			"081661c998296881",
 		},
 		{
 			"different subject id",
@@ -729,7 +729,7 @@ func TestCheckBulkPermissionsItemWIDHashStability(t *testing.T) {
 					},
 				},
 			},
-			"d3d624a310fa7781",
+			"5db67c053f3db501",
 		},
 		{
 			"different subject relation",
@@ -747,7 +747,7 @@ func TestCheckBulkPermissionsItemWIDHashStability(t *testing.T) {
 					OptionalRelation: "foo",
 				},
 			},
-			"a9d96f0572caef89",
+			"ff54e2d8e7d0f6c6",
 		},
 		{
 			"with context",
@@ -772,7 +772,7 @@ func TestCheckBulkPermissionsItemWIDHashStability(t *testing.T) {
 					return s
 				}(),
 			},
-			"94dea3fccff039ed",
+			"543b3efea2c90152",
 		},
 		{
 			"with different context",
@@ -797,7 +797,7 @@ func TestCheckBulkPermissionsItemWIDHashStability(t *testing.T) {
 					return s
 				}(),
 			},
-			"7ffdedbe12d578ee",
+			"0f833b969cb8c4c1",
 		},
 	}
 
@@ -978,6 +978,7 @@ type loadBulkAdapter struct {
 
 	currentBatch []*v1.Relationship
 	numSent      int
+	lineNumber   uint64
 	err          error
 }
 
@@ -1032,6 +1033,12 @@ func (a *loadBulkAdapter) Next(_ context.Context) (*tuple.Relationship, error) {
 
 	a.current.OptionalIntegrity = nil
 
+	// Report every structural violation on the relationship at once, so that callers fixing
+	// up a bulk import see all of the problems for the line in a single pass.
+	if fieldErrors := a.current.ValidateDetailed(); len(fieldErrors) > 0 {
+		return nil, NewInvalidBulkImportRelationshipError(a.current, a.lineNumber, fieldErrors)
+	}
+
 	if err := relationships.ValidateOneRelationship(
 		a.referencedNamespaceMap,
 		a.referencedCaveatMap,
@@ -1043,6 +1050,7 @@ func (a *loadBulkAdapter) Next(_ context.Context) (*tuple.Relationship, error) {
 	}
 
 	a.numSent++
+	a.lineNumber++
 	return &a.current, nil
 }
 
@@ -50,7 +50,9 @@ var writeUpdateCounter = promauto.NewHistogramVec(prometheus.HistogramOpts{
 	Buckets:   []float64{0, 1, 2, 5, 10, 15, 25, 50, 100, 250, 500, 1000},
 }, []string{"kind"})
 
-const MaximumTransactionMetadataSize = 65000 // bytes. Limited by the BLOB size used in MySQL driver
+// MaximumTransactionMetadataSize is the default maximum size of transaction metadata,
+// in bytes (~64KiB). Bounded above by the BLOB size used in the MySQL driver.
+const MaximumTransactionMetadataSize = 65000
 
 // PermissionsServerConfig is configuration for the permissions server.
 type PermissionsServerConfig struct {
@@ -79,6 +81,10 @@ type PermissionsServerConfig struct {
 	// MaxRelationshipContextSize defines the maximum length of a relationship's context in bytes
 	MaxRelationshipContextSize int
 
+	// MaxTransactionMetadataSize defines the maximum length of the optional transaction
+	// metadata in bytes, as serialized to JSON.
+	MaxTransactionMetadataSize int
+
 	// MaxDatastoreReadPageSize defines the maximum number of relationships loaded from the
 	// datastore in one query.
 	MaxDatastoreReadPageSize uint64
@@ -132,6 +138,7 @@ func NewPermissionsServer(
 		StreamingAPITimeout:                defaultIfZero(config.StreamingAPITimeout, 30*time.Second),
 		MaxCaveatContextSize:               defaultIfZero(config.MaxCaveatContextSize, 4096),
 		MaxRelationshipContextSize:         defaultIfZero(config.MaxRelationshipContextSize, 25_000),
+		MaxTransactionMetadataSize:         defaultIfZero(config.MaxTransactionMetadataSize, MaximumTransactionMetadataSize),
 		MaxDatastoreReadPageSize:           defaultIfZero(config.MaxDatastoreReadPageSize, 1_000),
 		MaxReadRelationshipsLimit:          defaultIfZero(config.MaxReadRelationshipsLimit, 1_000),
 		MaxDeleteRelationshipsLimit:        defaultIfZero(config.MaxDeleteRelationshipsLimit, 1_000),
@@ -257,6 +264,17 @@ func (ps *permissionServer) ReadRelationships(req *v1.ReadRelationshipsRequest,
 		}
 	}
 
+	queryOpts := []options.QueryOptionsOption{
+		options.WithSkipCaveats(!traits.AllowsCaveats),
+		options.WithSkipExpiration(!traits.AllowsExpiration),
+	}
+	if startCursor != nil {
+		// The consistency middleware always runs cursored calls at the revision encoded
+		// in the cursor; pass that revision along so that the datastore can reject
+		// resumption if the invariant is ever violated.
+		queryOpts = append(queryOpts, options.WithAfterRevision(atRevision.String()))
+	}
+
 	it, err := pagination.NewPaginatedIterator(
 		ctx,
 		ds,
@@ -265,8 +283,7 @@ func (ps *permissionServer) ReadRelationships(req *v1.ReadRelationshipsRequest,
 		options.ByResource,
 		startCursor,
 		queryshape.Varying,
-		options.WithSkipCaveats(!traits.AllowsCaveats),
-		options.WithSkipExpiration(!traits.AllowsExpiration),
+		queryOpts...,
 	)
 	if err != nil {
 		return ps.rewriteError(ctx, err)
@@ -449,8 +466,8 @@ func (ps *permissionServer) validateTransactionMetadata(metadata *structpb.Struc
 		return err
 	}
 
-	if len(b) > MaximumTransactionMetadataSize {
-		return NewTransactionMetadataTooLargeErr(len(b), MaximumTransactionMetadataSize)
+	if len(b) > ps.config.MaxTransactionMetadataSize {
+		return NewTransactionMetadataTooLargeErr(len(b), ps.config.MaxTransactionMetadataSize)
 	}
 
 	return nil
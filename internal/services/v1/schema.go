@@ -41,6 +41,11 @@ type SchemaServerConfig struct {
 	// ExpiringRelsEnabled indicates whether expiring relationships are enabled.
 	ExpiringRelsEnabled bool
 
+	// ForceAllowedTypeRemoval indicates whether schema writes may remove allowed subject types
+	// from relations even when relationships using them exist. The v1 API carries no per-request
+	// override, so this applies to all WriteSchema requests served by this server.
+	ForceAllowedTypeRemoval bool
+
 	// PerformanceInsightMetricsEnabled indicates whether performance insight metrics are enabled.
 	PerformanceInsightMetricsEnabled bool
 }
@@ -61,9 +66,10 @@ func NewSchemaServer(config SchemaServerConfig) v1.SchemaServiceServer {
 				perfinsights.StreamServerInterceptor(config.PerformanceInsightMetricsEnabled),
 			),
 		},
-		additiveOnly:        config.AdditiveOnly,
-		expiringRelsEnabled: config.ExpiringRelsEnabled,
-		caveatTypeSet:       cts,
+		additiveOnly:            config.AdditiveOnly,
+		expiringRelsEnabled:     config.ExpiringRelsEnabled,
+		forceAllowedTypeRemoval: config.ForceAllowedTypeRemoval,
+		caveatTypeSet:           cts,
 	}
 }
 
@@ -71,9 +77,10 @@ type schemaServer struct {
 	v1.UnimplementedSchemaServiceServer
 	shared.WithServiceSpecificInterceptors
 
-	caveatTypeSet       *caveattypes.TypeSet
-	additiveOnly        bool
-	expiringRelsEnabled bool
+	caveatTypeSet           *caveattypes.TypeSet
+	additiveOnly            bool
+	expiringRelsEnabled     bool
+	forceAllowedTypeRemoval bool
 }
 
 func (ss *schemaServer) rewriteError(ctx context.Context, err error) error {
@@ -165,7 +172,7 @@ func (ss *schemaServer) WriteSchema(ctx context.Context, in *v1.WriteSchemaReque
 	log.Ctx(ctx).Trace().Int("objectDefinitions", len(compiled.ObjectDefinitions)).Int("caveatDefinitions", len(compiled.CaveatDefinitions)).Msg("compiled namespace definitions")
 
 	// Do as much validation as we can before talking to the datastore.
-	validated, err := shared.ValidateSchemaChanges(ctx, compiled, ss.caveatTypeSet, ss.additiveOnly)
+	validated, err := shared.ValidateSchemaChanges(ctx, compiled, ss.caveatTypeSet, ss.additiveOnly, ss.forceAllowedTypeRemoval)
 	if err != nil {
 		return nil, ss.rewriteError(ctx, err)
 	}
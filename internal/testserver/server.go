@@ -125,7 +125,7 @@ func NewTestServerWithConfigAndDatastore(require *require.Assertions,
 					},
 					{
 						Name:       "consistency",
-						Middleware: consistency.UnaryServerInterceptor("testserver", consistency.TreatMismatchingTokensAsError),
+						Middleware: consistency.UnaryServerInterceptor("testserver", consistency.TreatMismatchingTokensAsError, 0),
 					},
 					{
 						Name:       "servicespecific",
@@ -148,7 +148,7 @@ func NewTestServerWithConfigAndDatastore(require *require.Assertions,
 					},
 					{
 						Name:       "consistency",
-						Middleware: consistency.StreamServerInterceptor("testserver", consistency.TreatMismatchingTokensAsError),
+						Middleware: consistency.StreamServerInterceptor("testserver", consistency.TreatMismatchingTokensAsError, 0),
 					},
 					{
 						Name:       "servicespecific",
@@ -245,38 +245,36 @@ func executeCaveatExprForTesting(expr *core.CaveatExpression, values map[string]
 
 	switch expr.GetOperation().Op {
 	case core.CaveatOperation_AND:
-		if len(expr.GetOperation().Children) != 2 {
+		if len(expr.GetOperation().Children) < 2 {
 			return false, spiceerrors.MustBugf("found invalid child count for AND")
 		}
 
-		left, err := executeCaveatExprForTesting(expr.GetOperation().Children[0], values)
-		if err != nil {
-			return false, err
-		}
-
-		right, err := executeCaveatExprForTesting(expr.GetOperation().Children[1], values)
-		if err != nil {
-			return false, err
+		result := true
+		for _, child := range expr.GetOperation().Children {
+			childResult, err := executeCaveatExprForTesting(child, values)
+			if err != nil {
+				return false, err
+			}
+			result = result && childResult
 		}
 
-		return left && right, nil
+		return result, nil
 
 	case core.CaveatOperation_OR:
-		if len(expr.GetOperation().Children) != 2 {
+		if len(expr.GetOperation().Children) < 2 {
 			return false, spiceerrors.MustBugf("found invalid child count for OR")
 		}
 
-		left, err := executeCaveatExprForTesting(expr.GetOperation().Children[0], values)
-		if err != nil {
-			return false, err
-		}
-
-		right, err := executeCaveatExprForTesting(expr.GetOperation().Children[1], values)
-		if err != nil {
-			return false, err
+		result := false
+		for _, child := range expr.GetOperation().Children {
+			childResult, err := executeCaveatExprForTesting(child, values)
+			if err != nil {
+				return false, err
+			}
+			result = result || childResult
 		}
 
-		return left || right, nil
+		return result, nil
 
 	case core.CaveatOperation_NOT:
 		if len(expr.GetOperation().Children) != 1 {
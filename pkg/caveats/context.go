@@ -1,19 +1,179 @@
 package caveats
 
 import (
+	"fmt"
 	"maps"
+	"strconv"
+	"strings"
 	"time"
 
 	"google.golang.org/protobuf/types/known/structpb"
 
 	"github.com/authzed/spicedb/pkg/caveats/types"
+	"github.com/authzed/spicedb/pkg/spiceerrors"
 )
 
+// LargeIntegerPolicy configures how integral context values whose magnitude exceeds 2^53 —
+// the largest integer a float64, and therefore a structpb number, can represent exactly —
+// are handled when converting context values to and from their struct form.
+type LargeIntegerPolicy int
+
+const (
+	// LargeIntegerPolicyLossy converts large integers to float64, silently losing
+	// precision. This matches the historical behavior.
+	LargeIntegerPolicyLossy LargeIntegerPolicy = iota
+
+	// LargeIntegerPolicyStringify preserves large integers as strings prefixed with
+	// LargeIntegerMarker, so that ConvertStructToContext can restore them exactly.
+	LargeIntegerPolicyStringify
+
+	// LargeIntegerPolicyError rejects context values containing large integers.
+	LargeIntegerPolicyError
+)
+
+// LargeIntegerMarker is the prefix placed on stringified large integers by
+// LargeIntegerPolicyStringify.
+const LargeIntegerMarker = "$integer:"
+
+// maxExactInteger is the largest integer magnitude exactly representable as a float64.
+const maxExactInteger = uint64(1) << 53
+
 // ConvertContextToStruct converts the given context values into a context struct.
 func ConvertContextToStruct(contextValues map[string]any) (*structpb.Struct, error) {
+	return ConvertContextToStructWithPolicy(contextValues, LargeIntegerPolicyLossy)
+}
+
+// ConvertContextToStructWithPolicy converts the given context values into a context struct,
+// applying the given policy to integers too large to survive the conversion to float64.
+func ConvertContextToStructWithPolicy(contextValues map[string]any, policy LargeIntegerPolicy) (*structpb.Struct, error) {
 	cloned := maps.Clone(contextValues)
-	cloned = convertCustomValues(cloned).(map[string]any)
-	return structpb.NewStruct(cloned)
+	converted := convertCustomValues(cloned).(map[string]any)
+	if policy != LargeIntegerPolicyLossy {
+		applied, err := applyLargeIntegerPolicy(converted, policy)
+		if err != nil {
+			return nil, err
+		}
+		converted = applied.(map[string]any)
+	}
+	return structpb.NewStruct(converted)
+}
+
+// ConvertStructToContext converts a context struct back into context values, applying the
+// given policy: under LargeIntegerPolicyStringify, string values carrying the
+// LargeIntegerMarker are restored to integers, making round trips through the struct form
+// lossless.
+func ConvertStructToContext(context *structpb.Struct, policy LargeIntegerPolicy) (map[string]any, error) {
+	contextValues := context.AsMap()
+	if policy != LargeIntegerPolicyStringify {
+		return contextValues, nil
+	}
+
+	restored, err := restoreLargeIntegers(contextValues)
+	if err != nil {
+		return nil, err
+	}
+	return restored.(map[string]any), nil
+}
+
+func applyLargeIntegerPolicy(value any, policy LargeIntegerPolicy) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, value := range v {
+			converted, err := applyLargeIntegerPolicy(value, policy)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = converted
+		}
+		return v, nil
+
+	case []any:
+		for index, current := range v {
+			converted, err := applyLargeIntegerPolicy(current, policy)
+			if err != nil {
+				return nil, err
+			}
+			v[index] = converted
+		}
+		return v, nil
+
+	case int:
+		return applyLargeIntegerPolicy(int64(v), policy)
+
+	case int64:
+		if v > int64(maxExactInteger) || v < -int64(maxExactInteger) {
+			return markLargeInteger(strconv.FormatInt(v, 10), policy)
+		}
+		return v, nil
+
+	case uint:
+		return applyLargeIntegerPolicy(uint64(v), policy)
+
+	case uint64:
+		if v > maxExactInteger {
+			return markLargeInteger(strconv.FormatUint(v, 10), policy)
+		}
+		return v, nil
+
+	default:
+		return v, nil
+	}
+}
+
+func markLargeInteger(digits string, policy LargeIntegerPolicy) (any, error) {
+	switch policy {
+	case LargeIntegerPolicyStringify:
+		return LargeIntegerMarker + digits, nil
+
+	case LargeIntegerPolicyError:
+		return nil, fmt.Errorf("integer context value %s exceeds 2^53 and cannot be represented exactly in a caveat context", digits)
+
+	default:
+		return nil, spiceerrors.MustBugf("unknown large integer policy: %v", policy)
+	}
+}
+
+func restoreLargeIntegers(value any) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, value := range v {
+			restored, err := restoreLargeIntegers(value)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = restored
+		}
+		return v, nil
+
+	case []any:
+		for index, current := range v {
+			restored, err := restoreLargeIntegers(current)
+			if err != nil {
+				return nil, err
+			}
+			v[index] = restored
+		}
+		return v, nil
+
+	case string:
+		digits, ok := strings.CutPrefix(v, LargeIntegerMarker)
+		if !ok {
+			return v, nil
+		}
+
+		if parsed, err := strconv.ParseInt(digits, 10, 64); err == nil {
+			return parsed, nil
+		}
+
+		if parsed, err := strconv.ParseUint(digits, 10, 64); err == nil {
+			return parsed, nil
+		}
+
+		return nil, fmt.Errorf("malformed large integer context value `%s`", v)
+
+	default:
+		return v, nil
+	}
 }
 
 func convertCustomValues(value any) any {
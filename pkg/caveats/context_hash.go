@@ -4,9 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"maps"
+	"math"
 	"net/url"
 	"slices"
-	"sort"
 	"strconv"
 
 	"google.golang.org/protobuf/types/known/structpb"
@@ -19,73 +19,95 @@ type HasherInterface interface {
 
 // StableContextStringForHashing returns a stable string version of the context, for use in hashing.
 func StableContextStringForHashing(context *structpb.Struct) string {
-	b := bytes.NewBufferString("")
-	hc := HashableContext{context}
-	hc.AppendToHash(wrappedBuffer{b})
-	return b.String()
+	serialized, err := CanonicalContextBytes(context)
+	if err != nil {
+		panic(err)
+	}
+	return string(serialized)
 }
 
-type wrappedBuffer struct{ *bytes.Buffer }
-
-func (wb wrappedBuffer) WriteString(value string) {
-	wb.Buffer.WriteString(value)
+// CanonicalContextBytes returns a deterministic, canonical serialization of the given context
+// struct, for use in cache keys. Keys are serialized in sorted order, integral doubles are
+// serialized distinctly from fractional doubles, and nested lists and structs are handled
+// recursively. A nil struct serializes identically to an empty one.
+func CanonicalContextBytes(context *structpb.Struct) ([]byte, error) {
+	var b bytes.Buffer
+	if err := appendCanonicalStruct(&b, context); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
 }
 
-// HashableContext is a wrapper around a context Struct that provides hashing.
-type HashableContext struct{ *structpb.Struct }
-
-func (hc HashableContext) AppendToHash(hasher HasherInterface) {
+func appendCanonicalStruct(b *bytes.Buffer, context *structpb.Struct) error {
 	// NOTE: the order of keys in the Struct and its resulting JSON output are *unspecified*,
 	// as the go runtime randomizes iterator order to ensure that if relied upon, a sort is used.
-	// Therefore, we sort the keys here before adding them to the hash.
-	if hc.Struct == nil {
-		return
-	}
-
-	fields := hc.Fields
-	keys := slices.Collect(maps.Keys(fields))
-	sort.Strings(keys)
-
-	for _, key := range keys {
-		hasher.WriteString("`")
-		hasher.WriteString(key)
-		hasher.WriteString("`:")
-		hashableStructValue{fields[key]}.AppendToHash(hasher)
-		hasher.WriteString(",\n")
+	// Therefore, we sort the keys here before serializing them.
+	b.WriteString("{")
+	if context != nil {
+		for _, key := range slices.Sorted(maps.Keys(context.Fields)) {
+			b.WriteString("`")
+			b.WriteString(url.PathEscape(key))
+			b.WriteString("`:")
+			if err := appendCanonicalValue(b, context.Fields[key]); err != nil {
+				return err
+			}
+			b.WriteString(",")
+		}
 	}
+	b.WriteString("}")
+	return nil
 }
 
-type hashableStructValue struct{ *structpb.Value }
-
-func (hsv hashableStructValue) AppendToHash(hasher HasherInterface) {
-	switch t := hsv.Kind.(type) {
+func appendCanonicalValue(b *bytes.Buffer, value *structpb.Value) error {
+	switch t := value.GetKind().(type) {
 	case *structpb.Value_BoolValue:
-		hasher.WriteString(strconv.FormatBool(t.BoolValue))
+		b.WriteString("b:")
+		b.WriteString(strconv.FormatBool(t.BoolValue))
 
 	case *structpb.Value_ListValue:
-		for _, value := range t.ListValue.Values {
-			hashableStructValue{value}.AppendToHash(hasher)
-			hasher.WriteString(",")
+		b.WriteString("[")
+		for _, item := range t.ListValue.Values {
+			if err := appendCanonicalValue(b, item); err != nil {
+				return err
+			}
+			b.WriteString(",")
 		}
+		b.WriteString("]")
 
 	case *structpb.Value_NullValue:
-		hasher.WriteString("null")
+		b.WriteString("null")
 
 	case *structpb.Value_NumberValue:
-		// AFAICT, this is how Sprintf-style formats float64s
-		hasher.WriteString(strconv.FormatFloat(t.NumberValue, 'f', 6, 64))
+		// Integral doubles are prefixed distinctly from fractional doubles, and both are
+		// formatted with the minimal round-tripping representation, so that no two distinct
+		// values share a serialization.
+		if v := t.NumberValue; v == math.Trunc(v) && !math.IsInf(v, 0) {
+			b.WriteString("i:")
+			b.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+		} else {
+			b.WriteString("d:")
+			b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		}
 
 	case *structpb.Value_StringValue:
-		// NOTE: we escape the string value here to prevent accidental overlap in keys for string
-		// values that may themselves contain backticks.
-		hasher.WriteString("`" + url.PathEscape(t.StringValue) + "`")
+		// NOTE: we escape the string value here to prevent accidental overlap with the
+		// delimiters used by the other kinds.
+		b.WriteString("`")
+		b.WriteString(url.PathEscape(t.StringValue))
+		b.WriteString("`")
 
 	case *structpb.Value_StructValue:
-		hasher.WriteString("{")
-		HashableContext{t.StructValue}.AppendToHash(hasher)
-		hasher.WriteString("}")
+		return appendCanonicalStruct(b, t.StructValue)
 
 	default:
-		panic(fmt.Sprintf("unknown struct value type: %T", t))
+		return fmt.Errorf("unknown struct value type: %T", t)
 	}
+	return nil
+}
+
+// HashableContext is a wrapper around a context Struct that provides hashing.
+type HashableContext struct{ *structpb.Struct }
+
+func (hc HashableContext) AppendToHash(hasher HasherInterface) {
+	hasher.WriteString(StableContextStringForHashing(hc.Struct))
 }
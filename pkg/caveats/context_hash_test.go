@@ -0,0 +1,148 @@
+package caveats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestCanonicalContextBytesIsOrderIndependent(t *testing.T) {
+	firstFields := map[string]*structpb.Value{}
+	firstFields["a"] = structpb.NewStringValue("1")
+	firstFields["b"] = structpb.NewNumberValue(2)
+	firstFields["c"] = structpb.NewBoolValue(true)
+
+	secondFields := map[string]*structpb.Value{}
+	secondFields["c"] = structpb.NewBoolValue(true)
+	secondFields["b"] = structpb.NewNumberValue(2)
+	secondFields["a"] = structpb.NewStringValue("1")
+
+	first, err := CanonicalContextBytes(&structpb.Struct{Fields: firstFields})
+	require.NoError(t, err)
+
+	second, err := CanonicalContextBytes(&structpb.Struct{Fields: secondFields})
+	require.NoError(t, err)
+
+	require.Equal(t, first, second)
+}
+
+func TestCanonicalContextBytesMatchesForEquivalentNestedStructs(t *testing.T) {
+	first, err := structpb.NewStruct(map[string]any{
+		"list": []any{1, "two", true, nil},
+		"nested": map[string]any{
+			"a": 1,
+			"b": []any{[]any{2.5}},
+		},
+	})
+	require.NoError(t, err)
+
+	second, err := structpb.NewStruct(map[string]any{
+		"nested": map[string]any{
+			"b": []any{[]any{2.5}},
+			"a": float64(1),
+		},
+		"list": []any{float64(1), "two", true, nil},
+	})
+	require.NoError(t, err)
+
+	firstBytes, err := CanonicalContextBytes(first)
+	require.NoError(t, err)
+
+	secondBytes, err := CanonicalContextBytes(second)
+	require.NoError(t, err)
+
+	require.Equal(t, firstBytes, secondBytes)
+}
+
+func TestCanonicalContextBytesTreatsNilAsEmpty(t *testing.T) {
+	nilBytes, err := CanonicalContextBytes(nil)
+	require.NoError(t, err)
+
+	emptyBytes, err := CanonicalContextBytes(&structpb.Struct{})
+	require.NoError(t, err)
+
+	require.Equal(t, nilBytes, emptyBytes)
+}
+
+func TestCanonicalContextBytesDistinguishesValues(t *testing.T) {
+	tcs := []struct {
+		name   string
+		first  map[string]any
+		second map[string]any
+	}{
+		{
+			"integral double vs fractional double",
+			map[string]any{"value": 1},
+			map[string]any{"value": 1.5},
+		},
+		{
+			"nearly equal doubles",
+			map[string]any{"value": 1.0000001},
+			map[string]any{"value": 1.0000002},
+		},
+		{
+			"number vs its string form",
+			map[string]any{"value": 1},
+			map[string]any{"value": "1"},
+		},
+		{
+			"bool vs its string form",
+			map[string]any{"value": true},
+			map[string]any{"value": "true"},
+		},
+		{
+			"null vs its string form",
+			map[string]any{"value": nil},
+			map[string]any{"value": "null"},
+		},
+		{
+			"flat list vs nested lists",
+			map[string]any{"value": []any{1, 2}},
+			map[string]any{"value": []any{[]any{1}, []any{2}}},
+		},
+		{
+			"empty list vs empty struct",
+			map[string]any{"value": []any{}},
+			map[string]any{"value": map[string]any{}},
+		},
+		{
+			"single string vs list holding it",
+			map[string]any{"value": "hi"},
+			map[string]any{"value": []any{"hi"}},
+		},
+		{
+			"differing nested struct field",
+			map[string]any{"value": map[string]any{"a": 1}},
+			map[string]any{"value": map[string]any{"a": 2}},
+		},
+		{
+			"missing key",
+			map[string]any{"a": 1, "b": 2},
+			map[string]any{"a": 1},
+		},
+		{
+			"key holding delimiter characters",
+			map[string]any{"a`:b:1": 1},
+			map[string]any{"a": map[string]any{"b": 1}},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			first, err := structpb.NewStruct(tc.first)
+			require.NoError(t, err)
+
+			second, err := structpb.NewStruct(tc.second)
+			require.NoError(t, err)
+
+			firstBytes, err := CanonicalContextBytes(first)
+			require.NoError(t, err)
+
+			secondBytes, err := CanonicalContextBytes(second)
+			require.NoError(t, err)
+
+			require.NotEqual(t, firstBytes, secondBytes)
+		})
+	}
+}
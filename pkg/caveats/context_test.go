@@ -107,6 +107,15 @@ func TestConvertContextToStruct(t *testing.T) {
 				"user_ip": "192.168.1.100",
 			}),
 		},
+		{
+			"converts decimal",
+			map[string]any{
+				"amount": types.MustParseDecimal("10.25"),
+			},
+			mustNewStruct(map[string]any{
+				"amount": "10.25",
+			}),
+		},
 	}
 
 	for _, tc := range tcs {
@@ -118,3 +127,124 @@ func TestConvertContextToStruct(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertContextToStructWithStringifyPolicy(t *testing.T) {
+	tcs := []struct {
+		name     string
+		input    map[string]any
+		expected *structpb.Struct
+	}{
+		{
+			"preserves large int64",
+			map[string]any{
+				"account_id": int64(9007199254740993), // 2^53 + 1
+			},
+			mustNewStruct(map[string]any{
+				"account_id": "$integer:9007199254740993",
+			}),
+		},
+		{
+			"preserves large negative int64",
+			map[string]any{
+				"account_id": int64(-9007199254740993),
+			},
+			mustNewStruct(map[string]any{
+				"account_id": "$integer:-9007199254740993",
+			}),
+		},
+		{
+			"preserves large uint64",
+			map[string]any{
+				"account_id": uint64(18446744073709551615),
+			},
+			mustNewStruct(map[string]any{
+				"account_id": "$integer:18446744073709551615",
+			}),
+		},
+		{
+			"leaves exactly representable integers as numbers",
+			map[string]any{
+				"below": int64(9007199254740991), // 2^53 - 1
+				"at":    int64(9007199254740992), // 2^53
+			},
+			mustNewStruct(map[string]any{
+				"below": 9007199254740991,
+				"at":    9007199254740992,
+			}),
+		},
+		{
+			"preserves large integers in nested structs and lists",
+			map[string]any{
+				"nested": map[string]any{
+					"ids": []any{int64(9007199254740993), int64(42)},
+				},
+			},
+			mustNewStruct(map[string]any{
+				"nested": map[string]any{
+					"ids": []any{"$integer:9007199254740993", 42},
+				},
+			}),
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ConvertContextToStructWithPolicy(tc.input, LargeIntegerPolicyStringify)
+			require.NoError(t, err)
+			testutil.RequireProtoEqual(t, tc.expected, result, "mismatch in converted context")
+		})
+	}
+}
+
+func TestConvertContextToStructWithErrorPolicy(t *testing.T) {
+	_, err := ConvertContextToStructWithPolicy(map[string]any{
+		"account_id": int64(9007199254740993),
+	}, LargeIntegerPolicyError)
+	require.ErrorContains(t, err, "integer context value 9007199254740993 exceeds 2^53")
+
+	result, err := ConvertContextToStructWithPolicy(map[string]any{
+		"account_id": int64(9007199254740991),
+	}, LargeIntegerPolicyError)
+	require.NoError(t, err)
+	testutil.RequireProtoEqual(t, mustNewStruct(map[string]any{
+		"account_id": 9007199254740991,
+	}), result, "mismatch in converted context")
+}
+
+func TestConvertStructToContextRoundTrip(t *testing.T) {
+	input := map[string]any{
+		"account_id": int64(9007199254740993),
+		"nested": map[string]any{
+			"ids": []any{int64(-9007199254740993), int64(42)},
+		},
+	}
+
+	converted, err := ConvertContextToStructWithPolicy(input, LargeIntegerPolicyStringify)
+	require.NoError(t, err)
+
+	restored, err := ConvertStructToContext(converted, LargeIntegerPolicyStringify)
+	require.NoError(t, err)
+
+	// NOTE: exactly representable integers round trip through structpb as float64.
+	require.Equal(t, map[string]any{
+		"account_id": int64(9007199254740993),
+		"nested": map[string]any{
+			"ids": []any{int64(-9007199254740993), float64(42)},
+		},
+	}, restored)
+}
+
+func TestConvertStructToContextMalformedMarker(t *testing.T) {
+	_, err := ConvertStructToContext(mustNewStruct(map[string]any{
+		"account_id": "$integer:not-digits",
+	}), LargeIntegerPolicyStringify)
+	require.ErrorContains(t, err, "malformed large integer context value")
+
+	// Under the lossy policy, marked strings are passed through untouched.
+	restored, err := ConvertStructToContext(mustNewStruct(map[string]any{
+		"account_id": "$integer:not-digits",
+	}), LargeIntegerPolicyLossy)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"account_id": "$integer:not-digits"}, restored)
+}
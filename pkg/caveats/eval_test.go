@@ -321,6 +321,38 @@ func TestEvaluateCaveat(t *testing.T) {
 			"",
 			noMissingVars,
 		},
+		{
+			"decimal addition preserves precision",
+			MustEnvForVariablesWithDefaultTypeSet(map[string]types.VariableType{
+				"a": types.Default.DecimalType,
+				"b": types.Default.DecimalType,
+			}),
+			`a + b == decimal("0.3")`,
+			map[string]any{
+				"a": types.MustParseDecimal("0.1"),
+				"b": types.MustParseDecimal("0.2"),
+			},
+			"",
+			true,
+			"",
+			noMissingVars,
+		},
+		{
+			"decimal comparison",
+			MustEnvForVariablesWithDefaultTypeSet(map[string]types.VariableType{
+				"amount": types.Default.DecimalType,
+				"limit":  types.Default.DecimalType,
+			}),
+			"amount <= limit",
+			map[string]any{
+				"amount": types.MustParseDecimal("100.50"),
+				"limit":  types.MustParseDecimal("100.049"),
+			},
+			"",
+			false,
+			"",
+			noMissingVars,
+		},
 		{
 			"optional types not found",
 			MustEnvForVariablesWithDefaultTypeSet(map[string]types.VariableType{
@@ -21,13 +21,43 @@ const (
 	ErrorForUnknownParameters UnknownParameterOption = 1
 )
 
-// ConvertContextToParameters converts the given context into parameters of the types specified.
-// Returns a type error if type conversion failed.
+// TypeCoercionOption is the option to ConvertContextToParametersWithCoercion around how
+// context values are coerced into the declared parameter types.
+type TypeCoercionOption int
+
+const (
+	// LenientTypeCoercion indicates that safe conversions should be performed: numeric
+	// strings become ints, uints or doubles when lossless, integers become doubles, and
+	// RFC 3339 strings become timestamps. This matches the historical behavior of
+	// ConvertContextToParameters and is the default.
+	LenientTypeCoercion TypeCoercionOption = 0
+
+	// StrictTypeCoercion indicates that string values for numeric parameters should be
+	// rejected rather than coerced. Types whose only JSON representation is a string
+	// (bytes, duration, timestamp, ipaddress) still accept their string forms.
+	StrictTypeCoercion TypeCoercionOption = 1
+)
+
+// ConvertContextToParameters converts the given context into parameters of the types specified,
+// applying lenient type coercion. Returns a type error if type conversion failed.
 func ConvertContextToParameters(
 	ts *types.TypeSet,
 	contextMap map[string]any,
 	parameterTypes map[string]*core.CaveatTypeReference,
 	unknownParametersOption UnknownParameterOption,
+) (map[string]any, error) {
+	return ConvertContextToParametersWithCoercion(ts, contextMap, parameterTypes, unknownParametersOption, LenientTypeCoercion)
+}
+
+// ConvertContextToParametersWithCoercion converts the given context into parameters of the
+// types specified, applying the given type coercion option. Returns a type error if type
+// conversion failed.
+func ConvertContextToParametersWithCoercion(
+	ts *types.TypeSet,
+	contextMap map[string]any,
+	parameterTypes map[string]*core.CaveatTypeReference,
+	unknownParametersOption UnknownParameterOption,
+	typeCoercionOption TypeCoercionOption,
 ) (map[string]any, error) {
 	if len(contextMap) == 0 {
 		return nil, nil
@@ -54,6 +84,12 @@ func ConvertContextToParameters(
 			return nil, err
 		}
 
+		if typeCoercionOption == StrictTypeCoercion {
+			if err := checkValueIsStrictlyTyped(paramType, value); err != nil {
+				return nil, ParameterConversionError{fmt.Errorf("could not convert context parameter `%s`: %w", key, err), key}
+			}
+		}
+
 		convertedParam, err := varType.ConvertValue(value)
 		if err != nil {
 			return nil, ParameterConversionError{fmt.Errorf("could not convert context parameter `%s`: %w", key, err), key}
@@ -64,6 +100,64 @@ func ConvertContextToParameters(
 	return converted, nil
 }
 
+// checkValueIsStrictlyTyped returns an error if the given value is not of the Go kind
+// matching the declared parameter type, i.e. if converting it would require a coercion
+// beyond decoding the type's canonical JSON representation.
+func checkValueIsStrictlyTyped(paramType *core.CaveatTypeReference, value any) error {
+	switch paramType.TypeName {
+	case "int", "uint", "double":
+		switch value.(type) {
+		case int, int32, int64, uint, uint32, uint64, float32, float64:
+			return nil
+		default:
+			return strictTypeError(paramType, value)
+		}
+
+	case "bool":
+		if _, ok := value.(bool); !ok {
+			return strictTypeError(paramType, value)
+		}
+
+	case "string", "bytes", "duration", "timestamp", "ipaddress", "decimal":
+		// bytes, duration, timestamp, ipaddress and decimal values have no JSON
+		// representation other than a string, so strict coercion accepts their string forms.
+		if _, ok := value.(string); !ok {
+			return strictTypeError(paramType, value)
+		}
+
+	case "list":
+		items, ok := value.([]any)
+		if !ok {
+			return strictTypeError(paramType, value)
+		}
+
+		for index, item := range items {
+			if err := checkValueIsStrictlyTyped(paramType.ChildTypes[0], item); err != nil {
+				return fmt.Errorf("found an invalid value for item at index %d: %w", index, err)
+			}
+		}
+
+	case "map":
+		entries, ok := value.(map[string]any)
+		if !ok {
+			return strictTypeError(paramType, value)
+		}
+
+		for entryKey, item := range entries {
+			if err := checkValueIsStrictlyTyped(paramType.ChildTypes[0], item); err != nil {
+				return fmt.Errorf("found an invalid value for key `%s`: %w", entryKey, err)
+			}
+		}
+	}
+
+	// `any` and custom types are left to their converters.
+	return nil
+}
+
+func strictTypeError(paramType *core.CaveatTypeReference, value any) error {
+	return fmt.Errorf("expected a value of type `%s`, but found %T `%v`", ParameterTypeString(paramType), value, value)
+}
+
 // ParameterTypeString returns the string form of the type reference.
 func ParameterTypeString(typeRef *core.CaveatTypeReference) string {
 	var sb strings.Builder
@@ -0,0 +1,282 @@
+package caveats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/pkg/caveats/types"
+)
+
+func TestConvertContextToParametersWithCoercion(t *testing.T) {
+	tcs := []struct {
+		name               string
+		paramType          types.VariableType
+		value              any
+		expectedValue      any
+		expectedLenientErr string
+		expectedStrictErr  string
+	}{
+		{
+			name:          "any accepts any value",
+			paramType:     types.Default.AnyType,
+			value:         "anything",
+			expectedValue: "anything",
+		},
+		{
+			name:          "bool accepts a bool",
+			paramType:     types.Default.BooleanType,
+			value:         true,
+			expectedValue: true,
+		},
+		{
+			name:               "bool rejects a string in both modes",
+			paramType:          types.Default.BooleanType,
+			value:              "true",
+			expectedLenientErr: "a bool value is required",
+			expectedStrictErr:  "expected a value of type `bool`, but found string `true`",
+		},
+		{
+			name:          "string accepts a string",
+			paramType:     types.Default.StringType,
+			value:         "hello",
+			expectedValue: "hello",
+		},
+		{
+			name:               "string rejects a number in both modes",
+			paramType:          types.Default.StringType,
+			value:              float64(42),
+			expectedLenientErr: "a string value is required",
+			expectedStrictErr:  "expected a value of type `string`, but found float64 `42`",
+		},
+		{
+			name:          "int accepts an integral double",
+			paramType:     types.Default.IntType,
+			value:         float64(42),
+			expectedValue: int64(42),
+		},
+		{
+			name:              "int coerces a numeric string only leniently",
+			paramType:         types.Default.IntType,
+			value:             "42",
+			expectedValue:     int64(42),
+			expectedStrictErr: "expected a value of type `int`, but found string `42`",
+		},
+		{
+			name:               "int rejects a fractional double in both modes",
+			paramType:          types.Default.IntType,
+			value:              float64(42.5),
+			expectedLenientErr: "a int value is required, but found numeric value `42.5`",
+			expectedStrictErr:  "a int value is required, but found numeric value `42.5`",
+		},
+		{
+			name:               "int rejects a non-numeric string in both modes",
+			paramType:          types.Default.IntType,
+			value:              "forty-two",
+			expectedLenientErr: "a int64 value is required, but found invalid string value `forty-two`",
+			expectedStrictErr:  "expected a value of type `int`, but found string `forty-two`",
+		},
+		{
+			name:          "uint coerces a numeric string only leniently",
+			paramType:     types.Default.UIntType,
+			value:         "42",
+			expectedValue: uint64(42),
+
+			expectedStrictErr: "expected a value of type `uint`, but found string `42`",
+		},
+		{
+			name:               "uint rejects a negative value in both modes",
+			paramType:          types.Default.UIntType,
+			value:              float64(-1),
+			expectedLenientErr: "a uint value is required, but found int64 value `-1`",
+			expectedStrictErr:  "a uint value is required, but found int64 value `-1`",
+		},
+		{
+			name:          "double accepts a double",
+			paramType:     types.Default.DoubleType,
+			value:         float64(3.5),
+			expectedValue: float64(3.5),
+		},
+		{
+			name:              "double coerces a numeric string only leniently",
+			paramType:         types.Default.DoubleType,
+			value:             "3.5",
+			expectedValue:     float64(3.5),
+			expectedStrictErr: "expected a value of type `double`, but found string `3.5`",
+		},
+		{
+			name:          "double accepts an int64",
+			paramType:     types.Default.DoubleType,
+			value:         int64(42),
+			expectedValue: float64(42),
+		},
+		{
+			name:          "double accepts a uint64",
+			paramType:     types.Default.DoubleType,
+			value:         uint64(7),
+			expectedValue: float64(7),
+		},
+		{
+			name:               "double rejects an int64 that cannot be converted losslessly",
+			paramType:          types.Default.DoubleType,
+			value:              int64(9007199254740993), // 2^53 + 1
+			expectedLenientErr: "which cannot be converted losslessly",
+			expectedStrictErr:  "which cannot be converted losslessly",
+		},
+		{
+			name:          "bytes accepts a base64 string in both modes",
+			paramType:     types.Default.BytesType,
+			value:         "aGVsbG8=",
+			expectedValue: []byte("hello"),
+		},
+		{
+			name:               "bytes rejects a number in both modes",
+			paramType:          types.Default.BytesType,
+			value:              float64(42),
+			expectedLenientErr: "bytes requires a base64 unicode string",
+			expectedStrictErr:  "expected a value of type `bytes`, but found float64 `42`",
+		},
+		{
+			name:          "duration accepts a duration string in both modes",
+			paramType:     types.Default.DurationType,
+			value:         "90s",
+			expectedValue: 90 * time.Second,
+		},
+		{
+			name:               "duration rejects a number in both modes",
+			paramType:          types.Default.DurationType,
+			value:              float64(90),
+			expectedLenientErr: "durations requires a duration string",
+			expectedStrictErr:  "expected a value of type `duration`, but found float64 `90`",
+		},
+		{
+			name:          "timestamp accepts an RFC 3339 string in both modes",
+			paramType:     types.Default.TimestampType,
+			value:         "2024-01-01T12:00:00Z",
+			expectedValue: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:               "timestamp rejects a number in both modes",
+			paramType:          types.Default.TimestampType,
+			value:              float64(1704110400),
+			expectedLenientErr: "timestamps requires a RFC 3339 formatted timestamp string",
+			expectedStrictErr:  "expected a value of type `timestamp`, but found float64 `1.7041104e+09`",
+		},
+		{
+			name:          "ipaddress accepts an address string in both modes",
+			paramType:     types.Default.IPAddressType,
+			value:         "192.168.1.1",
+			expectedValue: types.MustParseIPAddress("192.168.1.1"),
+		},
+		{
+			name:               "ipaddress rejects a number in both modes",
+			paramType:          types.Default.IPAddressType,
+			value:              float64(42),
+			expectedLenientErr: "ipaddress",
+			expectedStrictErr:  "expected a value of type `ipaddress`, but found float64 `42`",
+		},
+		{
+			name:              "list of int coerces item strings only leniently",
+			paramType:         types.Default.MustListType(types.Default.IntType),
+			value:             []any{"1", float64(2)},
+			expectedValue:     []any{int64(1), int64(2)},
+			expectedStrictErr: "found an invalid value for item at index 0: expected a value of type `int`, but found string `1`",
+		},
+		{
+			name:               "list rejects a non-list in both modes",
+			paramType:          types.Default.MustListType(types.Default.IntType),
+			value:              float64(42),
+			expectedLenientErr: "list requires a list",
+			expectedStrictErr:  "expected a value of type `list<int>`, but found float64 `42`",
+		},
+		{
+			name:              "map of int coerces value strings only leniently",
+			paramType:         types.Default.MustMapType(types.Default.IntType),
+			value:             map[string]any{"a": "1"},
+			expectedValue:     map[string]any{"a": int64(1)},
+			expectedStrictErr: "found an invalid value for key `a`: expected a value of type `int`, but found string `1`",
+		},
+		{
+			name:               "map rejects a non-map in both modes",
+			paramType:          types.Default.MustMapType(types.Default.IntType),
+			value:              []any{"1"},
+			expectedLenientErr: "map requires a map",
+			expectedStrictErr:  "expected a value of type `map<int>`, but found []interface {} `[1]`",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			parameterTypes := types.EncodeParameterTypes(map[string]types.VariableType{
+				"someparam": tc.paramType,
+			})
+			contextMap := map[string]any{"someparam": tc.value}
+
+			t.Run("lenient", func(t *testing.T) {
+				converted, err := ConvertContextToParametersWithCoercion(
+					types.Default.TypeSet, contextMap, parameterTypes,
+					ErrorForUnknownParameters, LenientTypeCoercion,
+				)
+				if tc.expectedLenientErr != "" {
+					require.ErrorContains(t, err, tc.expectedLenientErr)
+					require.ErrorContains(t, err, "could not convert context parameter `someparam`")
+					return
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedValue, converted["someparam"])
+			})
+
+			t.Run("strict", func(t *testing.T) {
+				converted, err := ConvertContextToParametersWithCoercion(
+					types.Default.TypeSet, contextMap, parameterTypes,
+					ErrorForUnknownParameters, StrictTypeCoercion,
+				)
+				if tc.expectedStrictErr != "" {
+					require.ErrorContains(t, err, tc.expectedStrictErr)
+					require.ErrorContains(t, err, "could not convert context parameter `someparam`")
+					return
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedValue, converted["someparam"])
+			})
+		})
+	}
+}
+
+func TestConvertContextToParametersIsLenient(t *testing.T) {
+	parameterTypes := types.EncodeParameterTypes(map[string]types.VariableType{
+		"someparam": types.Default.IntType,
+	})
+
+	converted, err := ConvertContextToParameters(
+		types.Default.TypeSet,
+		map[string]any{"someparam": "42"},
+		parameterTypes,
+		ErrorForUnknownParameters,
+	)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), converted["someparam"])
+}
+
+func TestConvertContextToParametersWithCoercionUnknownParameters(t *testing.T) {
+	parameterTypes := types.EncodeParameterTypes(map[string]types.VariableType{
+		"someparam": types.Default.IntType,
+	})
+	contextMap := map[string]any{"unknownparam": "42"}
+
+	_, err := ConvertContextToParametersWithCoercion(
+		types.Default.TypeSet, contextMap, parameterTypes,
+		ErrorForUnknownParameters, StrictTypeCoercion,
+	)
+	require.ErrorContains(t, err, "unknown parameter `unknownparam`")
+
+	converted, err := ConvertContextToParametersWithCoercion(
+		types.Default.TypeSet, contextMap, parameterTypes,
+		SkipUnknownParameters, StrictTypeCoercion,
+	)
+	require.NoError(t, err)
+	require.NotContains(t, converted, "unknownparam")
+}
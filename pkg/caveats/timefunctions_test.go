@@ -0,0 +1,128 @@
+package caveats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/pkg/caveats/types"
+)
+
+func TestTimestampInWindow(t *testing.T) {
+	compiled, err := compileCaveat(MustEnvForVariablesWithDefaultTypeSet(map[string]types.VariableType{
+		"now":          types.Default.TimestampType,
+		"window_start": types.Default.TimestampType,
+		"window_end":   types.Default.TimestampType,
+	}), "now.inWindow(window_start, window_end)")
+	require.NoError(t, err)
+
+	windowStart := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	windowEnd := time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name     string
+		now      time.Time
+		expected bool
+	}{
+		{"within the window", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), true},
+		{"at the window start", windowStart, true},
+		{"at the window end", windowEnd, false},
+		{"before the window", time.Date(2024, 1, 1, 8, 59, 59, 0, time.UTC), false},
+		{"after the window", time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := EvaluateCaveat(compiled, map[string]any{
+				"now":          tc.now,
+				"window_start": windowStart,
+				"window_end":   windowEnd,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result.Value())
+		})
+	}
+}
+
+func TestTimestampDayOfWeek(t *testing.T) {
+	compiled, err := compileCaveat(MustEnvForVariablesWithDefaultTypeSet(map[string]types.VariableType{
+		"now": types.Default.TimestampType,
+	}), "now.dayOfWeek() >= 1 && now.dayOfWeek() <= 5")
+	require.NoError(t, err)
+
+	// 2024-01-01 was a Monday; 2024-01-06 a Saturday.
+	result, err := EvaluateCaveat(compiled, map[string]any{
+		"now": time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.True(t, result.Value())
+
+	result, err = EvaluateCaveat(compiled, map[string]any{
+		"now": time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.False(t, result.Value())
+}
+
+func TestWithinDuration(t *testing.T) {
+	compiled, err := compileCaveat(MustEnvForVariablesWithDefaultTypeSet(map[string]types.VariableType{
+		"now":        types.Default.TimestampType,
+		"grant_time": types.Default.TimestampType,
+	}), "within(now, grant_time, duration('1h'))")
+	require.NoError(t, err)
+
+	grantTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name     string
+		now      time.Time
+		expected bool
+	}{
+		{"just after the grant", grantTime.Add(5 * time.Minute), true},
+		{"exactly at the duration boundary", grantTime.Add(time.Hour), true},
+		{"past the duration", grantTime.Add(time.Hour + time.Second), false},
+		{"before the grant", grantTime.Add(-time.Minute), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := EvaluateCaveat(compiled, map[string]any{
+				"now":        tc.now,
+				"grant_time": grantTime,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result.Value())
+		})
+	}
+}
+
+func TestTimeWindowPartialEvaluation(t *testing.T) {
+	compiled, err := compileCaveat(MustEnvForVariablesWithDefaultTypeSet(map[string]types.VariableType{
+		"now":          types.Default.TimestampType,
+		"window_start": types.Default.TimestampType,
+		"window_end":   types.Default.TimestampType,
+	}), "now.inWindow(window_start, window_end)")
+	require.NoError(t, err)
+
+	result, err := EvaluateCaveat(compiled, map[string]any{
+		"window_start": time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		"window_end":   time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.True(t, result.IsPartial())
+
+	missingVarNames, err := result.MissingVarNames()
+	require.NoError(t, err)
+	require.Equal(t, []string{"now"}, missingVarNames)
+
+	// Custom function calls are not folded into the residual expression, so evaluating the
+	// partial value requires the full context.
+	partialValue, err := result.PartialValue()
+	require.NoError(t, err)
+
+	fullResult, err := EvaluateCaveat(partialValue, map[string]any{
+		"now":          time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		"window_start": time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		"window_end":   time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.True(t, fullResult.Value())
+	require.False(t, fullResult.IsPartial())
+}
@@ -197,6 +197,16 @@ func RegisterBasicTypes(sts *StandardTypeSet) error {
 	}
 	sts.IPAddressType = ipAddressType
 
+	decimalType, err := RegisterDecimalType(ts)
+	if err != nil {
+		return err
+	}
+	sts.DecimalType = decimalType
+
+	if err := RegisterTimeFunctions(ts); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -216,18 +226,28 @@ func convertNumericType[T int64 | uint64 | float64](value any) (any, error) {
 
 	floatValue, ok := value.(float64)
 	bigFloat := big.NewFloat(floatValue)
+	fromInteger := false
 	if !ok {
-		stringValue, ok := value.(string)
-		if !ok {
-			return nil, fmt.Errorf("a %T value is required, but found %T `%v`", *new(T), value, value)
-		}
+		switch typed := value.(type) {
+		case int64:
+			bigFloat = new(big.Float).SetInt64(typed)
+			fromInteger = true
+
+		case uint64:
+			bigFloat = new(big.Float).SetUint64(typed)
+			fromInteger = true
+
+		case string:
+			f, _, err := big.ParseFloat(typed, 10, 64, 0)
+			if err != nil {
+				return nil, fmt.Errorf("a %T value is required, but found invalid string value `%v`", *new(T), value)
+			}
 
-		f, _, err := big.ParseFloat(stringValue, 10, 64, 0)
-		if err != nil {
-			return nil, fmt.Errorf("a %T value is required, but found invalid string value `%v`", *new(T), value)
-		}
+			bigFloat = f
 
-		bigFloat = f
+		default:
+			return nil, fmt.Errorf("a %T value is required, but found %T `%v`", *new(T), value, value)
+		}
 	}
 
 	// Convert the float to the int or uint if necessary.
@@ -238,7 +258,10 @@ func convertNumericType[T int64 | uint64 | float64](value any) (any, error) {
 			return nil, fmt.Errorf("a int value is required, but found numeric value `%s`", bigFloat.String())
 		}
 
-		numericValue, _ := bigFloat.Int64()
+		numericValue, accuracy := bigFloat.Int64()
+		if fromInteger && accuracy != big.Exact {
+			return nil, fmt.Errorf("a int value is required, but found out-of-range numeric value `%s`", bigFloat.String())
+		}
 		return numericValue, nil
 
 	case uint64:
@@ -253,7 +276,10 @@ func convertNumericType[T int64 | uint64 | float64](value any) (any, error) {
 		return uint64(numericValue), nil
 
 	case float64:
-		numericValue, _ := bigFloat.Float64()
+		numericValue, accuracy := bigFloat.Float64()
+		if fromInteger && accuracy != big.Exact {
+			return nil, fmt.Errorf("a double value is required, but found integer value `%s` which cannot be converted losslessly", bigFloat.String())
+		}
 		return numericValue, nil
 
 	default:
@@ -0,0 +1,232 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/authzed/cel-go/cel"
+	"github.com/authzed/cel-go/common/operators"
+	"github.com/authzed/cel-go/common/types"
+	"github.com/authzed/cel-go/common/types/ref"
+	"github.com/authzed/cel-go/common/types/traits"
+	"github.com/shopspring/decimal"
+)
+
+// ParseDecimal parses the string form of an exact decimal number into a Decimal object type.
+func ParseDecimal(value string) (Decimal, error) {
+	parsed, err := decimal.NewFromString(value)
+	return Decimal{parsed}, err
+}
+
+// MustParseDecimal parses the string form of an exact decimal number into a Decimal object type.
+func MustParseDecimal(value string) Decimal {
+	d, err := ParseDecimal(value)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+var decimalCelType = cel.ObjectType("Decimal",
+	traits.ComparerType,
+	traits.AdderType,
+	traits.SubtractorType,
+	traits.MultiplierType,
+	traits.DividerType,
+	traits.NegatorType,
+)
+
+// Decimal defines a custom type for representing an exact (arbitrary-precision) decimal number
+// in caveats, for use cases such as monetary amounts where binary floating point is unacceptable.
+type Decimal struct {
+	value decimal.Decimal
+}
+
+func (d Decimal) SerializedString() string {
+	return d.value.String()
+}
+
+func (d Decimal) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	if typeDesc == reflect.TypeOf("") {
+		return d.value.String(), nil
+	}
+	return nil, fmt.Errorf("type conversion error from 'Decimal' to '%v'", typeDesc)
+}
+
+func (d Decimal) ConvertToType(typeVal ref.Type) ref.Val {
+	switch typeVal {
+	case types.StringType:
+		return types.String(d.value.String())
+	case types.TypeType:
+		return decimalCelType
+	}
+	return types.NewErr("type conversion error from '%s' to '%s'", decimalCelType, typeVal)
+}
+
+func (d Decimal) Equal(other ref.Val) ref.Val {
+	o2, ok := other.(Decimal)
+	if !ok {
+		return types.ValOrErr(other, "no such overload")
+	}
+	return types.Bool(d.value.Equal(o2.value))
+}
+
+// Compare implements traits.Comparer, ordering decimals numerically.
+func (d Decimal) Compare(other ref.Val) ref.Val {
+	o2, ok := other.(Decimal)
+	if !ok {
+		return types.ValOrErr(other, "no such overload")
+	}
+	return types.Int(d.value.Cmp(o2.value))
+}
+
+// Add implements traits.Adder with exact decimal addition.
+func (d Decimal) Add(other ref.Val) ref.Val {
+	o2, ok := other.(Decimal)
+	if !ok {
+		return types.ValOrErr(other, "no such overload")
+	}
+	return Decimal{d.value.Add(o2.value)}
+}
+
+// Subtract implements traits.Subtractor with exact decimal subtraction.
+func (d Decimal) Subtract(other ref.Val) ref.Val {
+	o2, ok := other.(Decimal)
+	if !ok {
+		return types.ValOrErr(other, "no such overload")
+	}
+	return Decimal{d.value.Sub(o2.value)}
+}
+
+// Multiply implements traits.Multiplier with exact decimal multiplication.
+func (d Decimal) Multiply(other ref.Val) ref.Val {
+	o2, ok := other.(Decimal)
+	if !ok {
+		return types.ValOrErr(other, "no such overload")
+	}
+	return Decimal{d.value.Mul(o2.value)}
+}
+
+// Divide implements traits.Divider with decimal division.
+func (d Decimal) Divide(other ref.Val) ref.Val {
+	o2, ok := other.(Decimal)
+	if !ok {
+		return types.ValOrErr(other, "no such overload")
+	}
+	if o2.value.IsZero() {
+		return types.NewErr("division by zero")
+	}
+	return Decimal{d.value.Div(o2.value)}
+}
+
+// Negate implements traits.Negater, returning the decimal with its sign inverted.
+func (d Decimal) Negate() ref.Val {
+	return Decimal{d.value.Neg()}
+}
+
+func (d Decimal) Type() ref.Type {
+	return decimalCelType
+}
+
+func (d Decimal) Value() any {
+	return d
+}
+
+func RegisterDecimalType(ts *TypeSet) (VariableType, error) {
+	return RegisterCustomType[Decimal](ts,
+		"decimal",
+		cel.ObjectType("Decimal"),
+		func(value any) (any, error) {
+			decimalValue, ok := value.(Decimal)
+			if ok {
+				return decimalValue, nil
+			}
+
+			vle, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("decimal requires a decimal string, found: %T `%v`", value, value)
+			}
+
+			d, err := ParseDecimal(vle)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse decimal string `%s`: %w", vle, err)
+			}
+
+			return d, nil
+		},
+		cel.Function("decimal",
+			cel.Overload("string_to_decimal",
+				[]*cel.Type{cel.StringType},
+				cel.ObjectType("Decimal"),
+				cel.UnaryBinding(func(value ref.Val) ref.Val {
+					str, ok := value.Value().(string)
+					if !ok {
+						return types.NewErr("expected decimal string")
+					}
+
+					parsed, err := ParseDecimal(str)
+					if err != nil {
+						return types.NewErr("could not parse decimal string `%s`: %v", str, err)
+					}
+
+					return parsed
+				}),
+			),
+		),
+		// Declaration-only overloads for the comparison and arithmetic operators; dispatch at
+		// evaluation time goes through the trait methods above.
+		cel.Function(operators.Less,
+			cel.Overload("decimal_less_than",
+				[]*cel.Type{cel.ObjectType("Decimal"), cel.ObjectType("Decimal")},
+				cel.BoolType,
+			),
+		),
+		cel.Function(operators.LessEquals,
+			cel.Overload("decimal_less_than_or_equal",
+				[]*cel.Type{cel.ObjectType("Decimal"), cel.ObjectType("Decimal")},
+				cel.BoolType,
+			),
+		),
+		cel.Function(operators.Greater,
+			cel.Overload("decimal_greater_than",
+				[]*cel.Type{cel.ObjectType("Decimal"), cel.ObjectType("Decimal")},
+				cel.BoolType,
+			),
+		),
+		cel.Function(operators.GreaterEquals,
+			cel.Overload("decimal_greater_than_or_equal",
+				[]*cel.Type{cel.ObjectType("Decimal"), cel.ObjectType("Decimal")},
+				cel.BoolType,
+			),
+		),
+		cel.Function(operators.Add,
+			cel.Overload("decimal_add",
+				[]*cel.Type{cel.ObjectType("Decimal"), cel.ObjectType("Decimal")},
+				cel.ObjectType("Decimal"),
+			),
+		),
+		cel.Function(operators.Subtract,
+			cel.Overload("decimal_subtract",
+				[]*cel.Type{cel.ObjectType("Decimal"), cel.ObjectType("Decimal")},
+				cel.ObjectType("Decimal"),
+			),
+		),
+		cel.Function(operators.Multiply,
+			cel.Overload("decimal_multiply",
+				[]*cel.Type{cel.ObjectType("Decimal"), cel.ObjectType("Decimal")},
+				cel.ObjectType("Decimal"),
+			),
+		),
+		cel.Function(operators.Divide,
+			cel.Overload("decimal_divide",
+				[]*cel.Type{cel.ObjectType("Decimal"), cel.ObjectType("Decimal")},
+				cel.ObjectType("Decimal"),
+			),
+		),
+		cel.Function(operators.Negate,
+			cel.Overload("decimal_negate",
+				[]*cel.Type{cel.ObjectType("Decimal")},
+				cel.ObjectType("Decimal"),
+			),
+		))
+}
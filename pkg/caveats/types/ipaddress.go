@@ -6,8 +6,10 @@ import (
 	"reflect"
 
 	"github.com/authzed/cel-go/cel"
+	"github.com/authzed/cel-go/common/operators"
 	"github.com/authzed/cel-go/common/types"
 	"github.com/authzed/cel-go/common/types/ref"
+	"github.com/authzed/cel-go/common/types/traits"
 )
 
 // ParseIPAddress parses the string form of an IP Address into an IPAddress object type.
@@ -25,7 +27,7 @@ func MustParseIPAddress(ip string) IPAddress {
 	return ipAddress
 }
 
-var ipaddressCelType = cel.OpaqueType("IPAddress")
+var ipaddressCelType = cel.ObjectType("IPAddress", traits.ComparerType)
 
 // IPAddress defines a custom type for representing an IP Address in caveats.
 type IPAddress struct {
@@ -61,6 +63,16 @@ func (ipa IPAddress) Equal(other ref.Val) ref.Val {
 	return types.Bool(ipa == o2)
 }
 
+// Compare implements traits.Comparer, ordering IP addresses per netip.Addr.Compare: IPv4
+// addresses sort before IPv6 addresses, each in numeric order.
+func (ipa IPAddress) Compare(other ref.Val) ref.Val {
+	o2, ok := other.(IPAddress)
+	if !ok {
+		return types.ValOrErr(other, "no such overload")
+	}
+	return types.Int(ipa.ip.Compare(o2.ip))
+}
+
 func (ipa IPAddress) Type() ref.Type {
 	return ipaddressCelType
 }
@@ -109,5 +121,50 @@ func RegisterIPAddressType(ts *TypeSet) (VariableType, error) {
 					return types.Bool(network.Contains(lhs.(IPAddress).ip))
 				}),
 			),
+		),
+		cel.Function("ipaddress",
+			cel.Overload("string_to_ipaddress",
+				[]*cel.Type{cel.StringType},
+				cel.ObjectType("IPAddress"),
+				cel.UnaryBinding(func(value ref.Val) ref.Val {
+					str, ok := value.Value().(string)
+					if !ok {
+						return types.NewErr("expected ip address string")
+					}
+
+					parsed, err := ParseIPAddress(str)
+					if err != nil {
+						return types.NewErr("could not parse ip address string `%s`: %v", str, err)
+					}
+
+					return parsed
+				}),
+			),
+		),
+		// Declaration-only overloads for the comparison operators; dispatch at evaluation
+		// time goes through the Compare method above.
+		cel.Function(operators.Less,
+			cel.Overload("ipaddress_less_than",
+				[]*cel.Type{cel.ObjectType("IPAddress"), cel.ObjectType("IPAddress")},
+				cel.BoolType,
+			),
+		),
+		cel.Function(operators.LessEquals,
+			cel.Overload("ipaddress_less_than_or_equal",
+				[]*cel.Type{cel.ObjectType("IPAddress"), cel.ObjectType("IPAddress")},
+				cel.BoolType,
+			),
+		),
+		cel.Function(operators.Greater,
+			cel.Overload("ipaddress_greater_than",
+				[]*cel.Type{cel.ObjectType("IPAddress"), cel.ObjectType("IPAddress")},
+				cel.BoolType,
+			),
+		),
+		cel.Function(operators.GreaterEquals,
+			cel.Overload("ipaddress_greater_than_or_equal",
+				[]*cel.Type{cel.ObjectType("IPAddress"), cel.ObjectType("IPAddress")},
+				cel.BoolType,
+			),
 		))
 }
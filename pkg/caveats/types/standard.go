@@ -54,6 +54,7 @@ type StandardTypeSet struct {
 	DurationType  VariableType
 	TimestampType VariableType
 	IPAddressType VariableType
+	DecimalType   VariableType
 
 	listTypeBuilder GenericTypeBuilder
 	mapTypeBuilder  GenericTypeBuilder
@@ -0,0 +1,82 @@
+package types
+
+import (
+	"time"
+
+	"github.com/authzed/cel-go/cel"
+	"github.com/authzed/cel-go/common/types"
+	"github.com/authzed/cel-go/common/types/ref"
+)
+
+// RegisterTimeFunctions registers helper functions for time-window caveats: `inWindow` and
+// `dayOfWeek` as methods on timestamps, and a global `within` over two timestamps and a
+// duration. All of them operate purely on their arguments, so evaluation only ever sees
+// timestamps supplied via the caveat context, never the server wall clock.
+func RegisterTimeFunctions(ts *TypeSet) error {
+	return RegisterCustomCELOptions(ts,
+		cel.Function("inWindow",
+			cel.MemberOverload("timestamp_in_window",
+				[]*cel.Type{cel.TimestampType, cel.TimestampType, cel.TimestampType},
+				cel.BoolType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					ts, ok := args[0].Value().(time.Time)
+					if !ok {
+						return types.NewErr("expected timestamp")
+					}
+
+					start, ok := args[1].Value().(time.Time)
+					if !ok {
+						return types.NewErr("expected start timestamp")
+					}
+
+					end, ok := args[2].Value().(time.Time)
+					if !ok {
+						return types.NewErr("expected end timestamp")
+					}
+
+					// The window is inclusive of its start and exclusive of its end, so
+					// adjacent windows neither overlap nor leave gaps.
+					return types.Bool(!ts.Before(start) && ts.Before(end))
+				}),
+			),
+		),
+		cel.Function("dayOfWeek",
+			cel.MemberOverload("timestamp_day_of_week",
+				[]*cel.Type{cel.TimestampType},
+				cel.IntType,
+				cel.UnaryBinding(func(value ref.Val) ref.Val {
+					ts, ok := value.Value().(time.Time)
+					if !ok {
+						return types.NewErr("expected timestamp")
+					}
+
+					// Matches time.Weekday: Sunday is 0, Saturday is 6.
+					return types.Int(ts.UTC().Weekday())
+				}),
+			),
+		),
+		cel.Function("within",
+			cel.Overload("timestamps_within_duration",
+				[]*cel.Type{cel.TimestampType, cel.TimestampType, cel.DurationType},
+				cel.BoolType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					now, ok := args[0].Value().(time.Time)
+					if !ok {
+						return types.NewErr("expected timestamp")
+					}
+
+					grantTime, ok := args[1].Value().(time.Time)
+					if !ok {
+						return types.NewErr("expected grant timestamp")
+					}
+
+					duration, ok := args[2].Value().(time.Duration)
+					if !ok {
+						return types.NewErr("expected duration")
+					}
+
+					return types.Bool(!now.Before(grantTime) && now.Sub(grantTime) <= duration)
+				}),
+			),
+		))
+}
@@ -278,6 +278,27 @@ func TestConversion(t *testing.T) {
 			expectedValue: []any{MustParseIPAddress("1.2.3.4"), MustParseIPAddress("4.5.6.7")},
 			expectedErr:   "",
 		},
+		{
+			name:          "valid decimal",
+			vtype:         Default.DecimalType,
+			inputValue:    "1.25",
+			expectedValue: MustParseDecimal("1.25"),
+			expectedErr:   "",
+		},
+		{
+			name:          "invalid decimal",
+			vtype:         Default.DecimalType,
+			inputValue:    "1.2.5",
+			expectedValue: nil,
+			expectedErr:   "for decimal: could not parse decimal string `1.2.5`: can't convert 1.2.5 to decimal: too many .s",
+		},
+		{
+			name:          "double to decimal",
+			vtype:         Default.DecimalType,
+			inputValue:    1.25,
+			expectedValue: nil,
+			expectedErr:   "for decimal: decimal requires a decimal string, found: float64 `1.25`",
+		},
 	}
 
 	for _, tc := range tcs {
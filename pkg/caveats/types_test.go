@@ -29,6 +29,89 @@ func TestIPAddress(t *testing.T) {
 	require.False(t, result.Value())
 }
 
+func TestIPAddressConstructor(t *testing.T) {
+	compiled, err := compileCaveat(MustEnvForVariablesWithDefaultTypeSet(map[string]types.VariableType{
+		"user_ip": types.Default.StringType,
+	}), "ipaddress(user_ip).in_cidr('10.0.0.0/8')")
+	require.NoError(t, err)
+
+	result, err := EvaluateCaveat(compiled, map[string]any{
+		"user_ip": "10.20.30.40",
+	})
+	require.NoError(t, err)
+	require.True(t, result.Value())
+
+	result, err = EvaluateCaveat(compiled, map[string]any{
+		"user_ip": "192.168.10.10",
+	})
+	require.NoError(t, err)
+	require.False(t, result.Value())
+}
+
+func TestIPAddressConstructorV6(t *testing.T) {
+	compiled, err := compileCaveat(MustEnvForVariablesWithDefaultTypeSet(map[string]types.VariableType{
+		"user_ip": types.Default.StringType,
+	}), "ipaddress(user_ip).in_cidr('2001:db8::/32')")
+	require.NoError(t, err)
+
+	result, err := EvaluateCaveat(compiled, map[string]any{
+		"user_ip": "2001:db8::1",
+	})
+	require.NoError(t, err)
+	require.True(t, result.Value())
+
+	result, err = EvaluateCaveat(compiled, map[string]any{
+		"user_ip": "2001:db9::1",
+	})
+	require.NoError(t, err)
+	require.False(t, result.Value())
+}
+
+func TestIPAddressConstructorMalformedInput(t *testing.T) {
+	compiled, err := compileCaveat(MustEnvForVariablesWithDefaultTypeSet(map[string]types.VariableType{
+		"user_ip": types.Default.StringType,
+	}), "ipaddress(user_ip).in_cidr('10.0.0.0/8')")
+	require.NoError(t, err)
+
+	_, err = EvaluateCaveat(compiled, map[string]any{
+		"user_ip": "not an ip",
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "could not parse ip address string `not an ip`")
+}
+
+func TestIPAddressComparison(t *testing.T) {
+	for _, tc := range []struct {
+		expr     string
+		userIP   string
+		expected bool
+	}{
+		{"user_ip < ipaddress('10.0.0.50')", "10.0.0.49", true},
+		{"user_ip < ipaddress('10.0.0.50')", "10.0.0.50", false},
+		{"user_ip <= ipaddress('10.0.0.50')", "10.0.0.50", true},
+		{"user_ip > ipaddress('10.0.0.50')", "10.0.0.51", true},
+		{"user_ip >= ipaddress('10.0.0.50')", "10.0.0.49", false},
+		{"user_ip == ipaddress('10.0.0.50')", "10.0.0.50", true},
+		{"user_ip >= ipaddress('::1')", "2001:db8::1", true},
+	} {
+		t.Run(tc.expr+"/"+tc.userIP, func(t *testing.T) {
+			compiled, err := compileCaveat(MustEnvForVariablesWithDefaultTypeSet(map[string]types.VariableType{
+				"user_ip": types.Default.IPAddressType,
+			}), tc.expr)
+			require.NoError(t, err)
+
+			parsed, err := types.ParseIPAddress(tc.userIP)
+			require.NoError(t, err)
+
+			result, err := EvaluateCaveat(compiled, map[string]any{
+				"user_ip": parsed,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result.Value())
+		})
+	}
+}
+
 func TestIPAddressInvalidCIDR(t *testing.T) {
 	compiled, err := compileCaveat(MustEnvForVariablesWithDefaultTypeSet(map[string]types.VariableType{
 		"user_ip": types.Default.IPAddressType,
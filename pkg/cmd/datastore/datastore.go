@@ -139,6 +139,11 @@ type Config struct {
 	RequestHedgingMaxRequests      uint64        `debugmap:"visible"`
 	RequestHedgingQuantile         float64       `debugmap:"visible"`
 
+	// Head Revision Caching
+	HeadRevisionCacheEnabled         bool          `debugmap:"visible"`
+	HeadRevisionCacheRefreshInterval time.Duration `debugmap:"visible"`
+	HeadRevisionCacheMaxStaleness    time.Duration `debugmap:"visible"`
+
 	// CRDB
 	FollowerReadDelay         time.Duration `debugmap:"visible"`
 	MaxRetries                int           `debugmap:"visible"`
@@ -261,6 +266,9 @@ func RegisterDatastoreFlagsWithPrefix(flagSet *pflag.FlagSet, prefix string, opt
 	flagSet.DurationVar(&opts.RequestHedgingInitialSlowValue, flagName("datastore-request-hedging-initial-slow-value"), defaults.RequestHedgingInitialSlowValue, "initial value to use for slow datastore requests, before statistics have been collected")
 	flagSet.Uint64Var(&opts.RequestHedgingMaxRequests, flagName("datastore-request-hedging-max-requests"), defaults.RequestHedgingMaxRequests, "maximum number of historical requests to consider")
 	flagSet.Float64Var(&opts.RequestHedgingQuantile, flagName("datastore-request-hedging-quantile"), defaults.RequestHedgingQuantile, "quantile of historical datastore request time over which a request will be considered slow")
+	flagSet.BoolVar(&opts.HeadRevisionCacheEnabled, flagName("datastore-head-revision-cache"), defaults.HeadRevisionCacheEnabled, "enable caching of the datastore head revision for minimize-latency requests")
+	flagSet.DurationVar(&opts.HeadRevisionCacheRefreshInterval, flagName("datastore-head-revision-cache-refresh-interval"), defaults.HeadRevisionCacheRefreshInterval, "interval at which the cached head revision is refreshed in the background")
+	flagSet.DurationVar(&opts.HeadRevisionCacheMaxStaleness, flagName("datastore-head-revision-cache-max-staleness"), defaults.HeadRevisionCacheMaxStaleness, "maximum age of the cached head revision before minimize-latency requests fall back to the datastore")
 	flagSet.BoolVar(&opts.EnableDatastoreMetrics, flagName("datastore-prometheus-metrics"), defaults.EnableDatastoreMetrics, "set to false to disabled metrics from the datastore (do not use for Spanner; setting to false will disable metrics to the configured metrics store in Spanner)")
 	// See crdb doc for info about follower reads and how it is configured: https://www.cockroachlabs.com/docs/stable/follower-reads.html
 	flagSet.DurationVar(&opts.FollowerReadDelay, flagName("datastore-follower-read-delay-duration"), DefaultFollowerReadDelay, "amount of time to subtract from non-sync revision timestamps to ensure they are sufficiently in the past to enable follower reads (cockroach and spanner drivers only) or read replicas (postgres and mysql drivers only)")
@@ -348,6 +356,9 @@ func DefaultDatastoreConfig() *Config {
 		RequestHedgingInitialSlowValue:   10000000,
 		RequestHedgingMaxRequests:        1_000_000,
 		RequestHedgingQuantile:           0.95,
+		HeadRevisionCacheEnabled:         false,
+		HeadRevisionCacheRefreshInterval: 1 * time.Second,
+		HeadRevisionCacheMaxStaleness:    5 * time.Second,
 		SpannerCredentialsFile:           "",
 		SpannerEmulatorHost:              "",
 		TablePrefix:                      "",
@@ -457,6 +468,15 @@ func NewDatastore(ctx context.Context, options ...ConfigOption) (datastore.Datas
 		ds = hds
 	}
 
+	if opts.HeadRevisionCacheEnabled {
+		log.Ctx(ctx).Info().
+			Stringer("refreshInterval", opts.HeadRevisionCacheRefreshInterval).
+			Stringer("maxStaleness", opts.HeadRevisionCacheMaxStaleness).
+			Msg("head revision caching enabled")
+
+		ds = proxy.NewCachedHeadRevisionProxy(ds, opts.HeadRevisionCacheRefreshInterval, opts.HeadRevisionCacheMaxStaleness)
+	}
+
 	if opts.ReadOnly {
 		log.Ctx(ctx).Info().Msg("setting the datastore to read-only")
 		ds = proxy.NewReadonlyDatastore(ds)
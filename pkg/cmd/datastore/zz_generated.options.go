@@ -59,6 +59,9 @@ func (c *Config) ToOption() ConfigOption {
 		to.RequestHedgingInitialSlowValue = c.RequestHedgingInitialSlowValue
 		to.RequestHedgingMaxRequests = c.RequestHedgingMaxRequests
 		to.RequestHedgingQuantile = c.RequestHedgingQuantile
+		to.HeadRevisionCacheEnabled = c.HeadRevisionCacheEnabled
+		to.HeadRevisionCacheRefreshInterval = c.HeadRevisionCacheRefreshInterval
+		to.HeadRevisionCacheMaxStaleness = c.HeadRevisionCacheMaxStaleness
 		to.FollowerReadDelay = c.FollowerReadDelay
 		to.MaxRetries = c.MaxRetries
 		to.OverlapKey = c.OverlapKey
@@ -117,6 +120,9 @@ func (c Config) DebugMap() map[string]any {
 	debugMap["RequestHedgingInitialSlowValue"] = helpers.DebugValue(c.RequestHedgingInitialSlowValue, false)
 	debugMap["RequestHedgingMaxRequests"] = helpers.DebugValue(c.RequestHedgingMaxRequests, false)
 	debugMap["RequestHedgingQuantile"] = helpers.DebugValue(c.RequestHedgingQuantile, false)
+	debugMap["HeadRevisionCacheEnabled"] = helpers.DebugValue(c.HeadRevisionCacheEnabled, false)
+	debugMap["HeadRevisionCacheRefreshInterval"] = helpers.DebugValue(c.HeadRevisionCacheRefreshInterval, false)
+	debugMap["HeadRevisionCacheMaxStaleness"] = helpers.DebugValue(c.HeadRevisionCacheMaxStaleness, false)
 	debugMap["FollowerReadDelay"] = helpers.DebugValue(c.FollowerReadDelay, false)
 	debugMap["MaxRetries"] = helpers.DebugValue(c.MaxRetries, false)
 	debugMap["OverlapKey"] = helpers.DebugValue(c.OverlapKey, false)
@@ -373,6 +379,27 @@ func WithRequestHedgingQuantile(requestHedgingQuantile float64) ConfigOption {
 	}
 }
 
+// WithHeadRevisionCacheEnabled returns an option that can set HeadRevisionCacheEnabled on a Config
+func WithHeadRevisionCacheEnabled(headRevisionCacheEnabled bool) ConfigOption {
+	return func(c *Config) {
+		c.HeadRevisionCacheEnabled = headRevisionCacheEnabled
+	}
+}
+
+// WithHeadRevisionCacheRefreshInterval returns an option that can set HeadRevisionCacheRefreshInterval on a Config
+func WithHeadRevisionCacheRefreshInterval(headRevisionCacheRefreshInterval time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.HeadRevisionCacheRefreshInterval = headRevisionCacheRefreshInterval
+	}
+}
+
+// WithHeadRevisionCacheMaxStaleness returns an option that can set HeadRevisionCacheMaxStaleness on a Config
+func WithHeadRevisionCacheMaxStaleness(headRevisionCacheMaxStaleness time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.HeadRevisionCacheMaxStaleness = headRevisionCacheMaxStaleness
+	}
+}
+
 // WithFollowerReadDelay returns an option that can set FollowerReadDelay on a Config
 func WithFollowerReadDelay(followerReadDelay time.Duration) ConfigOption {
 	return func(c *Config) {
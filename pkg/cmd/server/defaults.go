@@ -90,8 +90,8 @@ func DefaultPreRunE(programName string) cobrautil.CobraRunFunc {
 }
 
 // MetricsHandler sets up an HTTP server that handles serving Prometheus
-// metrics and pprof endpoints.
-func MetricsHandler(telemetryRegistry *prometheus.Registry, c *Config) http.Handler {
+// metrics, pprof and debug endpoints.
+func MetricsHandler(telemetryRegistry *prometheus.Registry, c *Config, ds datastore.Datastore) http.Handler {
 	mux := http.NewServeMux()
 
 	mux.Handle("/metrics", promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{
@@ -125,6 +125,7 @@ func MetricsHandler(telemetryRegistry *prometheus.Registry, c *Config) http.Hand
 
 		fmt.Fprintf(w, "%s", string(json))
 	})
+	mux.Handle("/debug/gc", newGCHandler(ds))
 
 	return mux
 }
@@ -189,6 +190,7 @@ type MiddlewareOption struct {
 	DisableGRPCHistogram      bool                                 `debugmap:"visible"`
 	MiddlewareServiceLabel    string                               `debugmap:"visible"`
 	MismatchingZedTokenOption consistencymw.MismatchingTokenOption `debugmap:"visible"`
+	StalenessOverrideCeiling  time.Duration                        `debugmap:"visible"`
 
 	unaryDatastoreMiddleware  *ReferenceableMiddleware[grpc.UnaryServerInterceptor]  `debugmap:"hidden"`
 	streamDatastoreMiddleware *ReferenceableMiddleware[grpc.StreamServerInterceptor] `debugmap:"hidden"`
@@ -222,6 +224,7 @@ func (m MiddlewareOption) WithDatastoreMiddleware(middleware Middleware) Middlew
 		DisableGRPCHistogram:      m.DisableGRPCHistogram,
 		MiddlewareServiceLabel:    m.MiddlewareServiceLabel,
 		MismatchingZedTokenOption: m.MismatchingZedTokenOption,
+		StalenessOverrideCeiling:  m.StalenessOverrideCeiling,
 		unaryDatastoreMiddleware:  &unary,
 		streamDatastoreMiddleware: &stream,
 	}
@@ -250,6 +253,7 @@ func (m MiddlewareOption) WithDatastore(ds datastore.Datastore) MiddlewareOption
 		DisableGRPCHistogram:      m.DisableGRPCHistogram,
 		MiddlewareServiceLabel:    m.MiddlewareServiceLabel,
 		MismatchingZedTokenOption: m.MismatchingZedTokenOption,
+		StalenessOverrideCeiling:  m.StalenessOverrideCeiling,
 		unaryDatastoreMiddleware:  &unary,
 		streamDatastoreMiddleware: &stream,
 	}
@@ -340,7 +344,7 @@ func DefaultUnaryMiddleware(opts MiddlewareOption) (*MiddlewareChain[grpc.UnaryS
 
 		NewUnaryMiddleware().
 			WithName(DefaultInternalMiddlewareConsistency).
-			WithInterceptor(consistencymw.UnaryServerInterceptor(opts.MiddlewareServiceLabel, opts.MismatchingZedTokenOption)).
+			WithInterceptor(consistencymw.UnaryServerInterceptor(opts.MiddlewareServiceLabel, opts.MismatchingZedTokenOption, opts.StalenessOverrideCeiling)).
 			Done(),
 
 		NewUnaryMiddleware().
@@ -406,7 +410,7 @@ func DefaultStreamingMiddleware(opts MiddlewareOption) (*MiddlewareChain[grpc.St
 
 		NewStreamMiddleware().
 			WithName(DefaultInternalMiddlewareConsistency).
-			WithInterceptor(consistencymw.StreamServerInterceptor(opts.MiddlewareServiceLabel, opts.MismatchingZedTokenOption)).
+			WithInterceptor(consistencymw.StreamServerInterceptor(opts.MiddlewareServiceLabel, opts.MismatchingZedTokenOption, opts.StalenessOverrideCeiling)).
 			Done(),
 
 		NewStreamMiddleware().
@@ -33,6 +33,7 @@ func TestWithDatastore(t *testing.T) {
 		false,
 		"service",
 		consistency.TreatMismatchingTokensAsError,
+		0,
 		nil,
 		nil,
 	}
@@ -75,6 +76,7 @@ func TestWithDatastoreMiddleware(t *testing.T) {
 		false,
 		"anotherservice",
 		consistency.TreatMismatchingTokensAsError,
+		0,
 		nil,
 		nil,
 	}
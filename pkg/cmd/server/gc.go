@@ -0,0 +1,56 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/authzed/spicedb/internal/datastore/common"
+	log "github.com/authzed/spicedb/internal/logging"
+	"github.com/authzed/spicedb/pkg/datastore"
+)
+
+// gcHandler is a debug-only HTTP handler which triggers a garbage collection
+// pass on the underlying datastore and returns the statistics of the pass.
+//
+// Only a single pass may run at a time; concurrent requests receive a
+// conflict response.
+type gcHandler struct {
+	gc      common.ManualGCDatastore
+	running atomic.Bool
+}
+
+// newGCHandler creates a handler for the given datastore, unwrapping any
+// proxies to find manual garbage collection support.
+func newGCHandler(ds datastore.Datastore) *gcHandler {
+	return &gcHandler{gc: datastore.UnwrapAs[common.ManualGCDatastore](ds)}
+}
+
+func (h *gcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.gc == nil {
+		http.Error(w, "the configured datastore does not support manually triggered garbage collection", http.StatusNotImplemented)
+		return
+	}
+
+	if !h.running.CompareAndSwap(false, true) {
+		http.Error(w, "a garbage collection pass is already in progress", http.StatusConflict)
+		return
+	}
+	defer h.running.Store(false)
+
+	stats, err := h.gc.RunGC(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		log.Ctx(r.Context()).Warn().Err(err).Msg("error writing garbage collection stats response")
+	}
+}
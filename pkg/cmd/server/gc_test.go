@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/internal/datastore/common"
+)
+
+// blockingGC blocks each RunGC call until released, to allow testing the
+// handler's concurrency guard.
+type blockingGC struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingGC) RunGC(_ context.Context) (common.GCStats, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return common.GCStats{ExpiredRelationships: 3}, nil
+}
+
+func TestGCHandlerConcurrencyGuard(t *testing.T) {
+	gc := &blockingGC{started: make(chan struct{}, 2), release: make(chan struct{})}
+	handler := &gcHandler{gc: gc}
+
+	firstDone := make(chan *httptest.ResponseRecorder)
+	go func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/gc", nil))
+		firstDone <- rec
+	}()
+
+	<-gc.started
+
+	// A concurrent trigger while a pass is running is rejected.
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/gc", nil))
+	require.Equal(t, http.StatusConflict, rec.Code)
+
+	close(gc.release)
+	first := <-firstDone
+	require.Equal(t, http.StatusOK, first.Code)
+	require.Contains(t, first.Body.String(), `"expired_relationships":3`)
+
+	// Once the pass completes, the guard is released.
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/gc", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestGCHandlerRejectsNonPost(t *testing.T) {
+	handler := &gcHandler{gc: &blockingGC{}}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/gc", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestGCHandlerWithoutSupport(t *testing.T) {
+	handler := &gcHandler{}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/gc", nil))
+	require.Equal(t, http.StatusNotImplemented, rec.Code)
+}
@@ -79,6 +79,7 @@ type Config struct {
 	// Datastore usage
 	MaxCaveatContextSize       int `debugmap:"visible" default:"4096"`
 	MaxRelationshipContextSize int `debugmap:"visible" default:"25_000"`
+	MaxTransactionMetadataSize int `debugmap:"visible" default:"65_000"`
 
 	// Namespace cache
 	EnableExperimentalWatchableSchemaCache bool          `debugmap:"visible"`
@@ -133,6 +134,7 @@ type Config struct {
 	EnableRevisionHeartbeat            bool          `debugmap:"visible"`
 	EnablePerformanceInsightMetrics    bool          `debugmap:"visible"`
 	MismatchZedTokenBehavior           string        `debugmap:"visible"`
+	MaxStalenessOverride               time.Duration `debugmap:"visible"`
 
 	// Additional Services
 	MetricsAPI util.HTTPServerConfig `debugmap:"visible"`
@@ -442,6 +444,7 @@ func (c *Config) Complete(ctx context.Context) (RunnableServer, error) {
 		c.DisableGRPCLatencyHistogram,
 		serverName,
 		mismatchZedTokenOption,
+		c.MaxStalenessOverride,
 		nil,
 		nil,
 	}
@@ -488,6 +491,7 @@ func (c *Config) Complete(ctx context.Context) (RunnableServer, error) {
 		MaximumAPIDepth:                    c.DispatchMaxDepth,
 		MaxCaveatContextSize:               c.MaxCaveatContextSize,
 		MaxRelationshipContextSize:         c.MaxRelationshipContextSize,
+		MaxTransactionMetadataSize:         c.MaxTransactionMetadataSize,
 		MaxDatastoreReadPageSize:           c.MaxDatastoreReadPageSize,
 		StreamingAPITimeout:                c.StreamingAPITimeout,
 		MaxReadRelationshipsLimit:          c.MaxReadRelationshipsLimit,
@@ -563,7 +567,7 @@ func (c *Config) Complete(ctx context.Context) (RunnableServer, error) {
 		}
 	}
 
-	metricsServer, err := c.MetricsAPI.Complete(zerolog.InfoLevel, MetricsHandler(telemetryRegistry, c))
+	metricsServer, err := c.MetricsAPI.Complete(zerolog.InfoLevel, MetricsHandler(telemetryRegistry, c, ds))
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize metrics server: %w", err)
 	}
@@ -2,6 +2,8 @@
 package server
 
 import (
+	time "time"
+
 	dispatch "github.com/authzed/spicedb/internal/dispatch"
 	consistency "github.com/authzed/spicedb/pkg/middleware/consistency"
 	defaults "github.com/creasty/defaults"
@@ -43,6 +45,7 @@ func (m *MiddlewareOption) ToOption() MiddlewareOptionOption {
 		to.DisableGRPCHistogram = m.DisableGRPCHistogram
 		to.MiddlewareServiceLabel = m.MiddlewareServiceLabel
 		to.MismatchingZedTokenOption = m.MismatchingZedTokenOption
+		to.StalenessOverrideCeiling = m.StalenessOverrideCeiling
 		to.unaryDatastoreMiddleware = m.unaryDatastoreMiddleware
 		to.streamDatastoreMiddleware = m.streamDatastoreMiddleware
 	}
@@ -57,6 +60,7 @@ func (m MiddlewareOption) DebugMap() map[string]any {
 	debugMap["DisableGRPCHistogram"] = helpers.DebugValue(m.DisableGRPCHistogram, false)
 	debugMap["MiddlewareServiceLabel"] = helpers.DebugValue(m.MiddlewareServiceLabel, false)
 	debugMap["MismatchingZedTokenOption"] = helpers.DebugValue(m.MismatchingZedTokenOption, false)
+	debugMap["StalenessOverrideCeiling"] = helpers.DebugValue(m.StalenessOverrideCeiling, false)
 	return debugMap
 }
 
@@ -138,3 +142,10 @@ func WithMismatchingZedTokenOption(mismatchingZedTokenOption consistency.Mismatc
 		m.MismatchingZedTokenOption = mismatchingZedTokenOption
 	}
 }
+
+// WithStalenessOverrideCeiling returns an option that can set StalenessOverrideCeiling on a MiddlewareOption
+func WithStalenessOverrideCeiling(stalenessOverrideCeiling time.Duration) MiddlewareOptionOption {
+	return func(m *MiddlewareOption) {
+		m.StalenessOverrideCeiling = stalenessOverrideCeiling
+	}
+}
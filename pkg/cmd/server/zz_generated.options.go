@@ -53,6 +53,7 @@ func (c *Config) ToOption() ConfigOption {
 		to.Datastore = c.Datastore
 		to.MaxCaveatContextSize = c.MaxCaveatContextSize
 		to.MaxRelationshipContextSize = c.MaxRelationshipContextSize
+		to.MaxTransactionMetadataSize = c.MaxTransactionMetadataSize
 		to.EnableExperimentalWatchableSchemaCache = c.EnableExperimentalWatchableSchemaCache
 		to.SchemaWatchHeartbeat = c.SchemaWatchHeartbeat
 		to.NamespaceCacheConfig = c.NamespaceCacheConfig
@@ -97,6 +98,7 @@ func (c *Config) ToOption() ConfigOption {
 		to.EnableRevisionHeartbeat = c.EnableRevisionHeartbeat
 		to.EnablePerformanceInsightMetrics = c.EnablePerformanceInsightMetrics
 		to.MismatchZedTokenBehavior = c.MismatchZedTokenBehavior
+		to.MaxStalenessOverride = c.MaxStalenessOverride
 		to.MetricsAPI = c.MetricsAPI
 		to.UnaryMiddlewareModification = c.UnaryMiddlewareModification
 		to.StreamingMiddlewareModification = c.StreamingMiddlewareModification
@@ -130,6 +132,7 @@ func (c Config) DebugMap() map[string]any {
 	debugMap["Datastore"] = helpers.DebugValue(c.Datastore, false)
 	debugMap["MaxCaveatContextSize"] = helpers.DebugValue(c.MaxCaveatContextSize, false)
 	debugMap["MaxRelationshipContextSize"] = helpers.DebugValue(c.MaxRelationshipContextSize, false)
+	debugMap["MaxTransactionMetadataSize"] = helpers.DebugValue(c.MaxTransactionMetadataSize, false)
 	debugMap["EnableExperimentalWatchableSchemaCache"] = helpers.DebugValue(c.EnableExperimentalWatchableSchemaCache, false)
 	debugMap["SchemaWatchHeartbeat"] = helpers.DebugValue(c.SchemaWatchHeartbeat, false)
 	debugMap["NamespaceCacheConfig"] = helpers.DebugValue(c.NamespaceCacheConfig, false)
@@ -173,6 +176,7 @@ func (c Config) DebugMap() map[string]any {
 	debugMap["EnableRevisionHeartbeat"] = helpers.DebugValue(c.EnableRevisionHeartbeat, false)
 	debugMap["EnablePerformanceInsightMetrics"] = helpers.DebugValue(c.EnablePerformanceInsightMetrics, false)
 	debugMap["MismatchZedTokenBehavior"] = helpers.DebugValue(c.MismatchZedTokenBehavior, false)
+	debugMap["MaxStalenessOverride"] = helpers.DebugValue(c.MaxStalenessOverride, false)
 	debugMap["MetricsAPI"] = helpers.DebugValue(c.MetricsAPI, false)
 	debugMap["SilentlyDisableTelemetry"] = helpers.DebugValue(c.SilentlyDisableTelemetry, false)
 	debugMap["TelemetryCAOverridePath"] = helpers.DebugValue(c.TelemetryCAOverridePath, false)
@@ -319,6 +323,13 @@ func WithMaxRelationshipContextSize(maxRelationshipContextSize int) ConfigOption
 	}
 }
 
+// WithMaxTransactionMetadataSize returns an option that can set MaxTransactionMetadataSize on a Config
+func WithMaxTransactionMetadataSize(maxTransactionMetadataSize int) ConfigOption {
+	return func(c *Config) {
+		c.MaxTransactionMetadataSize = maxTransactionMetadataSize
+	}
+}
+
 // WithEnableExperimentalWatchableSchemaCache returns an option that can set EnableExperimentalWatchableSchemaCache on a Config
 func WithEnableExperimentalWatchableSchemaCache(enableExperimentalWatchableSchemaCache bool) ConfigOption {
 	return func(c *Config) {
@@ -648,6 +659,13 @@ func WithMismatchZedTokenBehavior(mismatchZedTokenBehavior string) ConfigOption
 	}
 }
 
+// WithMaxStalenessOverride returns an option that can set MaxStalenessOverride on a Config
+func WithMaxStalenessOverride(maxStalenessOverride time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.MaxStalenessOverride = maxStalenessOverride
+	}
+}
+
 // WithMetricsAPI returns an option that can set MetricsAPI on a Config
 func WithMetricsAPI(metricsAPI util.HTTPServerConfig) ConfigOption {
 	return func(c *Config) {
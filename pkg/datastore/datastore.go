@@ -803,6 +803,18 @@ type StartableDatastore interface {
 	Start(ctx context.Context) error
 }
 
+// BoundedStalenessDatastore is an optional extension to the datastore interface that, when
+// implemented, provides the ability for callers to request an optimized revision with an
+// explicit maximum staleness, overriding the configured revision quantization.
+type BoundedStalenessDatastore interface {
+	Datastore
+
+	// OptimizedRevisionWithStaleness returns a revision that is no older than the given maximum
+	// staleness. If the maximum staleness is smaller than the configured revision quantization
+	// window, the standard optimized revision is returned.
+	OptimizedRevisionWithStaleness(ctx context.Context, maxStaleness time.Duration) (Revision, error)
+}
+
 // RepairOperation represents a single kind of repair operation that can be run in a repairable
 // datastore.
 type RepairOperation struct {
@@ -897,6 +909,12 @@ type Features struct {
 	// IntegrityData is enabled if the underlying datastore supports retrieving and storing
 	// integrity information.
 	IntegrityData Feature
+
+	// StrictCursorResumption is enabled if the datastore validates that pagination cursors
+	// are resumed at the same revision at which they were created, rejecting resumption at
+	// any other revision with a CursorRevisionMismatchError unless the caller opts into
+	// best-effort resumption.
+	StrictCursorResumption Feature
 }
 
 // ObjectTypeStat represents statistics for a single object type (namespace).
@@ -3,6 +3,7 @@ package datastore
 import (
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/rs/zerolog"
 
@@ -77,8 +78,9 @@ const (
 // InvalidRevisionError occurs when a revision specified to a call was invalid.
 type InvalidRevisionError struct {
 	error
-	revision Revision
-	reason   InvalidRevisionReason
+	revision           Revision
+	reason             InvalidRevisionReason
+	recommendedMinimum Revision
 }
 
 // InvalidRevision is the revision that failed.
@@ -91,6 +93,12 @@ func (err InvalidRevisionError) Reason() InvalidRevisionReason {
 	return err.reason
 }
 
+// RecommendedMinimumRevision is the oldest revision the datastore can still serve, if
+// the datastore was able to determine it. Returns NoRevision otherwise.
+func (err InvalidRevisionError) RecommendedMinimumRevision() Revision {
+	return err.recommendedMinimum
+}
+
 // MarshalZerologObject implements zerolog object marshalling.
 func (err InvalidRevisionError) MarshalZerologObject(e *zerolog.Event) {
 	switch err.reason {
@@ -103,6 +111,23 @@ func (err InvalidRevisionError) MarshalZerologObject(e *zerolog.Event) {
 	}
 }
 
+// DetailsMetadata returns the metadata for details for this error.
+func (err InvalidRevisionError) DetailsMetadata() map[string]string {
+	metadata := map[string]string{}
+	switch err.reason {
+	case RevisionStale:
+		metadata["reason"] = "stale"
+	case CouldNotDetermineRevision:
+		metadata["reason"] = "indeterminate"
+	}
+
+	if err.recommendedMinimum != nil {
+		metadata["recommended_minimum_revision"] = err.recommendedMinimum.String()
+	}
+
+	return metadata
+}
+
 // NewNamespaceNotFoundErr constructs a new namespace not found error.
 func NewNamespaceNotFoundErr(nsName string) error {
 	return NamespaceNotFoundError{
@@ -167,6 +192,18 @@ func NewInvalidRevisionErr(revision Revision, reason InvalidRevisionReason) erro
 	}
 }
 
+// NewStaleRevisionErr constructs a new invalid revision error for a revision which has
+// fallen outside of the garbage collection window, including the oldest revision the
+// datastore can still serve so that callers know how to correct their request.
+func NewStaleRevisionErr(revision Revision, recommendedMinimum Revision) error {
+	return InvalidRevisionError{
+		error:              errors.New("revision has expired"),
+		revision:           revision,
+		reason:             RevisionStale,
+		recommendedMinimum: recommendedMinimum,
+	}
+}
+
 // CaveatNameNotFoundError is the error returned when a caveat is not found by its name
 type CaveatNameNotFoundError struct {
 	error
@@ -274,6 +311,87 @@ func NewMaximumChangesSizeExceededError(maxSize uint64) error {
 	return MaximumChangesSizeExceededError{fmt.Errorf("maximum changes byte size of %d exceeded", maxSize), maxSize}
 }
 
+// WriteTooLargeError is returned when a transactional write would exceed the
+// backing datastore's per-commit mutation limit and cannot be split across
+// multiple commits without sacrificing atomicity.
+type WriteTooLargeError struct {
+	error
+	estimatedMutationCount uint64
+	mutationLimit          uint64
+}
+
+// NewWriteTooLargeErr constructs a new write too large error.
+func NewWriteTooLargeErr(estimatedMutationCount uint64, mutationLimit uint64) error {
+	return WriteTooLargeError{
+		error: fmt.Errorf(
+			"write of an estimated %d mutations exceeds the datastore's limit of %d mutations per commit; split the write into smaller batches",
+			estimatedMutationCount,
+			mutationLimit,
+		),
+		estimatedMutationCount: estimatedMutationCount,
+		mutationLimit:          mutationLimit,
+	}
+}
+
+// EstimatedMutationCount returns the estimated number of mutations for the
+// rejected write.
+func (err WriteTooLargeError) EstimatedMutationCount() uint64 {
+	return err.estimatedMutationCount
+}
+
+// DetailsMetadata returns the metadata for details for this error.
+func (err WriteTooLargeError) DetailsMetadata() map[string]string {
+	return map[string]string{
+		"estimated_mutation_count": strconv.FormatUint(err.estimatedMutationCount, 10),
+		"mutation_limit":           strconv.FormatUint(err.mutationLimit, 10),
+	}
+}
+
+// CursorRevisionMismatchError is returned when a pagination cursor created at one
+// revision is used to resume a query at a different revision without opting into
+// best-effort resumption.
+type CursorRevisionMismatchError struct {
+	error
+	cursorRevision  string
+	queriedRevision string
+}
+
+// NewCursorRevisionMismatchErr constructs a new cursor revision mismatch error.
+func NewCursorRevisionMismatchErr(cursorRevision string, queriedRevision string) error {
+	return CursorRevisionMismatchError{
+		error: fmt.Errorf(
+			"cursor was created at revision `%s` but the query is running at revision `%s`; results may be skipped or duplicated",
+			cursorRevision,
+			queriedRevision,
+		),
+		cursorRevision:  cursorRevision,
+		queriedRevision: queriedRevision,
+	}
+}
+
+// DetailsMetadata returns the metadata for details for this error.
+func (err CursorRevisionMismatchError) DetailsMetadata() map[string]string {
+	return map[string]string{
+		"cursor_revision":  err.cursorRevision,
+		"queried_revision": err.queriedRevision,
+	}
+}
+
+// RelationshipIntegrityNotSupportedError is returned when a relationship carrying
+// integrity data is written to a datastore which does not support relationship
+// integrity.
+type RelationshipIntegrityNotSupportedError struct {
+	error
+}
+
+// NewRelationshipIntegrityNotSupportedErr constructs a new relationship integrity not
+// supported error for the given relationship (in string form).
+func NewRelationshipIntegrityNotSupportedErr(relString string) error {
+	return RelationshipIntegrityNotSupportedError{
+		error: fmt.Errorf("cannot write relationship `%s`: the datastore does not support relationship integrity", relString),
+	}
+}
+
 var (
 	ErrClosedIterator        = errors.New("unable to iterate: iterator closed")
 	ErrCursorsWithoutSorting = errors.New("cursors are disabled on unsorted results")
@@ -30,6 +30,25 @@ const (
 	BySubject
 )
 
+// ExpirationBehavior is an enum which defines how a query treats relationships whose
+// expiration has passed but which have not yet been garbage collected.
+type ExpirationBehavior int8
+
+const (
+	// ExcludeExpiredRelationships filters out relationships whose expiration has passed.
+	// This is the default behavior.
+	ExcludeExpiredRelationships ExpirationBehavior = iota
+
+	// IncludeExpiredRelationships returns relationships whose expiration has passed
+	// alongside live relationships. Must only be used by export and debug tooling;
+	// permission-checking code paths must use ExcludeExpiredRelationships.
+	IncludeExpiredRelationships
+
+	// OnlyExpiredRelationships returns only relationships whose expiration has passed.
+	// Must only be used by export and debug tooling.
+	OnlyExpiredRelationships
+)
+
 type Cursor *tuple.Relationship
 
 func ToCursor(r tuple.Relationship) Cursor {
@@ -66,6 +85,26 @@ type QueryOptions struct {
 	SkipCaveats    bool      `debugmap:"visible"`
 	SkipExpiration bool      `debugmap:"visible"`
 
+	// AfterRevision, if non-empty, is the stringified revision at which the After cursor
+	// was created. Datastores supporting strict cursor resumption reject queries running
+	// at a different revision with a CursorRevisionMismatchError, as resumption at another
+	// revision may skip or duplicate results. Within the same revision, resumption is
+	// guaranteed to return no duplicates.
+	AfterRevision string `debugmap:"visible"`
+
+	// BestEffortResumption, if true, allows the After cursor to resume a query at a
+	// revision other than AfterRevision, with no guarantees against skipped or
+	// duplicated results.
+	BestEffortResumption bool `debugmap:"visible"`
+
+	// ExpirationBehavior defines how the query treats relationships whose expiration
+	// has passed but which have not yet been garbage collected.
+	ExpirationBehavior ExpirationBehavior `debugmap:"visible"`
+
+	// InternONRs, if true, interns the object type and relation strings of decoded
+	// relationships, deduplicating them across large result sets.
+	InternONRs bool `debugmap:"visible"`
+
 	// SQLCheckAssertionForTest is a function that can be used to assert a condition on the SQL query string.
 	// For testing and validation only.
 	SQLCheckAssertionForTest SQLCheckAssertionForTest `debugmap:"visible"`
@@ -89,6 +128,10 @@ type ReverseQueryOptions struct {
 	SkipCaveatsForReverse    bool      `debugmap:"visible"`
 	SkipExpirationForReverse bool      `debugmap:"visible"`
 
+	// ExpirationBehaviorForReverse defines how the query treats relationships whose
+	// expiration has passed but which have not yet been garbage collected.
+	ExpirationBehaviorForReverse ExpirationBehavior `debugmap:"visible"`
+
 	// SQLExplainCallbackForTestForReverse is a callback invoked with the explain plan of the SQL query string.
 	// For testing and validation only.
 	SQLExplainCallbackForTestForReverse SQLExplainCallbackForTest `debugmap:"visible"`
@@ -37,6 +37,10 @@ func (q *QueryOptions) ToOption() QueryOptionsOption {
 		to.After = q.After
 		to.SkipCaveats = q.SkipCaveats
 		to.SkipExpiration = q.SkipExpiration
+		to.AfterRevision = q.AfterRevision
+		to.BestEffortResumption = q.BestEffortResumption
+		to.ExpirationBehavior = q.ExpirationBehavior
+		to.InternONRs = q.InternONRs
 		to.SQLCheckAssertionForTest = q.SQLCheckAssertionForTest
 		to.SQLExplainCallbackForTest = q.SQLExplainCallbackForTest
 		to.QueryShape = q.QueryShape
@@ -51,6 +55,10 @@ func (q QueryOptions) DebugMap() map[string]any {
 	debugMap["After"] = helpers.DebugValue(q.After, false)
 	debugMap["SkipCaveats"] = helpers.DebugValue(q.SkipCaveats, false)
 	debugMap["SkipExpiration"] = helpers.DebugValue(q.SkipExpiration, false)
+	debugMap["AfterRevision"] = helpers.DebugValue(q.AfterRevision, false)
+	debugMap["BestEffortResumption"] = helpers.DebugValue(q.BestEffortResumption, false)
+	debugMap["ExpirationBehavior"] = helpers.DebugValue(q.ExpirationBehavior, false)
+	debugMap["InternONRs"] = helpers.DebugValue(q.InternONRs, false)
 	debugMap["SQLCheckAssertionForTest"] = helpers.DebugValue(q.SQLCheckAssertionForTest, false)
 	debugMap["SQLExplainCallbackForTest"] = helpers.DebugValue(q.SQLExplainCallbackForTest, false)
 	debugMap["QueryShape"] = helpers.DebugValue(q.QueryShape, false)
@@ -108,6 +116,34 @@ func WithSkipExpiration(skipExpiration bool) QueryOptionsOption {
 	}
 }
 
+// WithAfterRevision returns an option that can set AfterRevision on a QueryOptions
+func WithAfterRevision(afterRevision string) QueryOptionsOption {
+	return func(q *QueryOptions) {
+		q.AfterRevision = afterRevision
+	}
+}
+
+// WithBestEffortResumption returns an option that can set BestEffortResumption on a QueryOptions
+func WithBestEffortResumption(bestEffortResumption bool) QueryOptionsOption {
+	return func(q *QueryOptions) {
+		q.BestEffortResumption = bestEffortResumption
+	}
+}
+
+// WithExpirationBehavior returns an option that can set ExpirationBehavior on a QueryOptions
+func WithExpirationBehavior(expirationBehavior ExpirationBehavior) QueryOptionsOption {
+	return func(q *QueryOptions) {
+		q.ExpirationBehavior = expirationBehavior
+	}
+}
+
+// WithInternONRs returns an option that can set InternONRs on a QueryOptions
+func WithInternONRs(internONRs bool) QueryOptionsOption {
+	return func(q *QueryOptions) {
+		q.InternONRs = internONRs
+	}
+}
+
 // WithSQLCheckAssertionForTest returns an option that can set SQLCheckAssertionForTest on a QueryOptions
 func WithSQLCheckAssertionForTest(sQLCheckAssertionForTest SQLCheckAssertionForTest) QueryOptionsOption {
 	return func(q *QueryOptions) {
@@ -159,6 +195,7 @@ func (r *ReverseQueryOptions) ToOption() ReverseQueryOptionsOption {
 		to.AfterForReverse = r.AfterForReverse
 		to.SkipCaveatsForReverse = r.SkipCaveatsForReverse
 		to.SkipExpirationForReverse = r.SkipExpirationForReverse
+		to.ExpirationBehaviorForReverse = r.ExpirationBehaviorForReverse
 		to.SQLExplainCallbackForTestForReverse = r.SQLExplainCallbackForTestForReverse
 		to.QueryShapeForReverse = r.QueryShapeForReverse
 	}
@@ -173,6 +210,7 @@ func (r ReverseQueryOptions) DebugMap() map[string]any {
 	debugMap["AfterForReverse"] = helpers.DebugValue(r.AfterForReverse, false)
 	debugMap["SkipCaveatsForReverse"] = helpers.DebugValue(r.SkipCaveatsForReverse, false)
 	debugMap["SkipExpirationForReverse"] = helpers.DebugValue(r.SkipExpirationForReverse, false)
+	debugMap["ExpirationBehaviorForReverse"] = helpers.DebugValue(r.ExpirationBehaviorForReverse, false)
 	debugMap["SQLExplainCallbackForTestForReverse"] = helpers.DebugValue(r.SQLExplainCallbackForTestForReverse, false)
 	debugMap["QueryShapeForReverse"] = helpers.DebugValue(r.QueryShapeForReverse, false)
 	return debugMap
@@ -236,6 +274,13 @@ func WithSkipExpirationForReverse(skipExpirationForReverse bool) ReverseQueryOpt
 	}
 }
 
+// WithExpirationBehaviorForReverse returns an option that can set ExpirationBehaviorForReverse on a ReverseQueryOptions
+func WithExpirationBehaviorForReverse(expirationBehaviorForReverse ExpirationBehavior) ReverseQueryOptionsOption {
+	return func(r *ReverseQueryOptions) {
+		r.ExpirationBehaviorForReverse = expirationBehaviorForReverse
+	}
+}
+
 // WithSQLExplainCallbackForTestForReverse returns an option that can set SQLExplainCallbackForTestForReverse on a ReverseQueryOptions
 func WithSQLExplainCallbackForTestForReverse(sQLExplainCallbackForTestForReverse SQLExplainCallbackForTest) ReverseQueryOptionsOption {
 	return func(r *ReverseQueryOptions) {
@@ -10,6 +10,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 
+	"google.golang.org/protobuf/types/known/timestamppb"
+
 	"github.com/authzed/grpcutil"
 
 	"github.com/authzed/spicedb/internal/testfixtures"
@@ -317,6 +319,73 @@ func BulkUploadAlreadyExistsErrorTest(t *testing.T, tester DatastoreTester) {
 	grpcutil.RequireStatus(t, codes.AlreadyExists, err)
 }
 
+// BulkUploadIntegrityTest tests that bulk loading a relationship carrying integrity
+// data stores the integrity block on datastores which support it and rejects the
+// write with a typed error on those which do not.
+func BulkUploadIntegrityTest(t *testing.T, tester DatastoreTester) {
+	require := require.New(t)
+	ctx := t.Context()
+
+	rawDS, err := tester.New(0, veryLargeGCInterval, veryLargeGCWindow, 1)
+	require.NoError(err)
+
+	ds, _ := testfixtures.StandardDatastoreWithSchema(rawDS, require)
+
+	features, err := ds.OfflineFeatures()
+	require.NoError(err)
+
+	if features.IntegrityData.Status == datastore.FeatureStatusUnknown {
+		t.Skip("integrity data support is unknown")
+	}
+
+	rel := tuple.MustParse("document:somedoc#viewer@user:tom")
+	rel.OptionalIntegrity = &core.RelationshipIntegrity{
+		KeyId:    "somekey",
+		Hash:     []byte{0x01, 0x02, 0x03},
+		HashedAt: timestamppb.Now(),
+	}
+
+	_, err = ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		_, lerr := rwt.BulkLoad(ctx, &singleRelationshipSource{rel: &rel})
+		return lerr
+	}, options.WithDisableRetries(true))
+
+	if features.IntegrityData.Status == datastore.FeatureUnsupported {
+		require.Error(err)
+		require.ErrorAs(err, &datastore.RelationshipIntegrityNotSupportedError{})
+		return
+	}
+
+	require.NoError(err)
+
+	headRev, err := ds.HeadRevision(ctx)
+	require.NoError(err)
+
+	iter, err := ds.SnapshotReader(headRev).QueryRelationships(ctx, datastore.RelationshipsFilter{
+		OptionalResourceType: testfixtures.DocumentNS.Name,
+	}, options.WithQueryShape(queryshape.FindResourceOfType))
+	require.NoError(err)
+
+	for found, err := range iter {
+		require.NoError(err)
+		require.NotNil(found.OptionalIntegrity)
+		require.Equal("somekey", found.OptionalIntegrity.KeyId)
+		require.Equal([]byte{0x01, 0x02, 0x03}, found.OptionalIntegrity.Hash)
+	}
+}
+
+type singleRelationshipSource struct {
+	rel *tuple.Relationship
+}
+
+func (srs *singleRelationshipSource) Next(_ context.Context) (*tuple.Relationship, error) {
+	rel := srs.rel
+	srs.rel = nil
+	return rel, nil
+}
+
+var _ datastore.BulkWriteRelationshipSource = &singleRelationshipSource{}
+
 type onlyErrorSource struct{}
 
 var errOnlyError = errors.New("source iterator error")
@@ -135,6 +135,7 @@ func AllWithExceptions(t *testing.T, tester DatastoreTester, except Categories,
 	t.Run("TestCreateTouchDeleteTouch", runner(tester, CreateTouchDeleteTouchTest))
 	t.Run("TestTouchAlreadyExistingCaveated", runner(tester, TouchAlreadyExistingCaveatedTest))
 	t.Run("TestBulkDeleteRelationships", runner(tester, BulkDeleteRelationshipsTest))
+	t.Run("TestDeleteRelationshipsWithProgress", runner(tester, DeleteRelationshipsWithProgressTest))
 	t.Run("TestDeleteCaveatedTuple", runner(tester, DeleteCaveatedTupleTest))
 	t.Run("TestDeleteWithLimit", runner(tester, DeleteWithLimitTest))
 	t.Run("TestDeleteWithInvalidPrefix", runner(tester, DeleteWithInvalidPrefixTest))
@@ -144,6 +145,7 @@ func AllWithExceptions(t *testing.T, tester DatastoreTester, except Categories,
 	t.Run("TestTouchTypedAlreadyExistingWithoutCaveat", runner(tester, TypedTouchAlreadyExistingTest))
 	t.Run("TestTouchTypedAlreadyExistingWithCaveat", runner(tester, TypedTouchAlreadyExistingWithCaveatTest))
 	t.Run("TestRelationshipExpiration", runner(tester, RelationshipExpirationTest))
+	t.Run("TestRelationshipExpirationBehavior", runner(tester, RelationshipExpirationBehaviorTest))
 	t.Run("TestMixedWriteOperations", runner(tester, MixedWriteOperationsTest))
 	t.Run("TestRelationshipCaveatFiltering", runner(tester, RelationshipCaveatFilteringTest))
 
@@ -154,14 +156,17 @@ func AllWithExceptions(t *testing.T, tester DatastoreTester, except Categories,
 	t.Run("TestLimit", runner(tester, LimitTest))
 	t.Run("TestOrderedLimit", runner(tester, OrderedLimitTest))
 	t.Run("TestResume", runner(tester, ResumeTest))
+	t.Run("TestCursorResumptionRevisionMismatch", runner(tester, CursorResumptionRevisionMismatchTest))
 	t.Run("TestReverseQueryCursor", runner(tester, ReverseQueryCursorTest))
 	t.Run("TestReverseQueryFilteredCursor", runner(tester, ReverseQueryFilteredOverMultipleValuesCursorTest))
+	t.Run("TestReverseQueryCursorEdgeCases", runner(tester, ReverseQueryCursorEdgeCasesTest))
 
 	t.Run("TestRevisionQuantization", runner(tester, RevisionQuantizationTest))
 	t.Run("TestRevisionSerialization", runner(tester, RevisionSerializationTest))
 	t.Run("TestSequentialRevisions", runner(tester, SequentialRevisionsTest))
 	t.Run("TestConcurrentRevisions", runner(tester, ConcurrentRevisionsTest))
 	t.Run("TestCheckRevisions", runner(tester, CheckRevisionsTest))
+	t.Run("TestFabricatedFutureRevision", runner(tester, FabricatedFutureRevisionTest))
 
 	if !except.GC() {
 		OnlyGCTests(t, tester, concurrent)
@@ -174,6 +179,7 @@ func AllWithExceptions(t *testing.T, tester DatastoreTester, except Categories,
 	t.Run("TestBulkUploadEditCaveat", runner(tester, BulkUploadEditCaveat))
 	t.Run("TestBulkUploadWithCaveats", runner(tester, BulkUploadWithCaveats))
 	t.Run("TestBulkUploadWithExpiration", runner(tester, BulkUploadWithExpiration))
+	t.Run("TestBulkUploadIntegrity", runner(tester, BulkUploadIntegrityTest))
 
 	if !except.Stats() {
 		t.Run("TestStats", runner(tester, StatsTest))
@@ -205,6 +211,7 @@ func AllWithExceptions(t *testing.T, tester DatastoreTester, except Categories,
 
 	if !except.Watch() && !except.WatchCheckpoints() {
 		t.Run("TestWatchCheckpoints", runner(tester, WatchCheckpointsTest))
+		t.Run("TestWatchCheckpointContract", runner(tester, WatchCheckpointContractTest))
 	}
 
 	if !except.Transaction() {
@@ -226,6 +233,7 @@ func OnlyGCTests(t *testing.T, tester DatastoreTester, concurrent bool) {
 	}
 
 	t.Run("TestRevisionGC", runner(tester, RevisionGCTest))
+	t.Run("TestStaleRevisionRejection", runner(tester, StaleRevisionRejectionTest))
 	t.Run("TestInvalidReads", runner(tester, InvalidReadsTest))
 	t.Run("TestGCProcessRuns", runner(tester, GCProcessRunTest))
 }
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"testing"
 
+	v1 "github.com/authzed/authzed-go/proto/authzed/api/v1"
 	"github.com/ccoveille/go-safecast"
 	"github.com/stretchr/testify/require"
 
@@ -261,6 +262,84 @@ func ResumeTest(t *testing.T, tester DatastoreTester) {
 	}
 }
 
+// CursorResumptionRevisionMismatchTest tests that a cursor created at one revision cannot
+// be used to resume a query at a different revision unless best-effort resumption is
+// requested, and that resumption at the original revision remains exact even when the
+// relation's relationships are deleted mid-pagination.
+func CursorResumptionRevisionMismatchTest(t *testing.T, tester DatastoreTester) {
+	rawDS, err := tester.New(0, veryLargeGCInterval, veryLargeGCWindow, 1)
+	require.NoError(t, err)
+
+	ds, rev := testfixtures.StandardDatastoreWithData(rawDS, require.New(t))
+
+	features, err := ds.Features(t.Context())
+	require.NoError(t, err)
+	if features.StrictCursorResumption.Status != datastore.FeatureSupported {
+		t.Skip("strict cursor resumption is not supported by the datastore")
+	}
+
+	require := require.New(t)
+	ctx := t.Context()
+	tRequire := testfixtures.RelationshipChecker{Require: require, DS: ds}
+
+	expected := sortedStandardData(testfixtures.DocumentNS.Name, options.ByResource)
+	require.Greater(len(expected), 2)
+
+	// Read the first page of results at the head revision.
+	pageSize := uint64(2)
+	iter, err := ds.SnapshotReader(rev).QueryRelationships(ctx, datastore.RelationshipsFilter{
+		OptionalResourceType: testfixtures.DocumentNS.Name,
+	},
+		options.WithSort(options.ByResource),
+		options.WithLimit(&pageSize),
+		options.WithQueryShape(queryshape.Varying),
+	)
+	require.NoError(err)
+	cursor := tRequire.VerifyOrderedIteratorResults(iter, expected[0:2]...)
+
+	// Delete the viewer relation's relationships mid-pagination.
+	newRev, err := ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		_, _, err := rwt.DeleteRelationships(ctx, &v1.RelationshipFilter{
+			ResourceType:     testfixtures.DocumentNS.Name,
+			OptionalRelation: "viewer",
+		})
+		return err
+	})
+	require.NoError(err)
+
+	resumeAt := func(resumeRev datastore.Revision, opts ...options.QueryOptionsOption) (datastore.RelationshipIterator, error) {
+		return ds.SnapshotReader(resumeRev).QueryRelationships(ctx, datastore.RelationshipsFilter{
+			OptionalResourceType: testfixtures.DocumentNS.Name,
+		},
+			append(opts,
+				options.WithSort(options.ByResource),
+				options.WithAfter(cursor),
+				options.WithAfterRevision(rev.String()),
+				options.WithQueryShape(queryshape.Varying),
+			)...,
+		)
+	}
+
+	// Resuming at the new revision must be rejected with a typed error.
+	_, err = resumeAt(newRev)
+	mismatchErr := datastore.CursorRevisionMismatchError{}
+	require.ErrorAs(err, &mismatchErr)
+
+	// Resuming at the new revision with best-effort resumption is allowed, though
+	// no guarantees are made about skipped or duplicated results.
+	iter, err = resumeAt(newRev, options.WithBestEffortResumption(true))
+	require.NoError(err)
+	for _, err := range iter {
+		require.NoError(err)
+	}
+
+	// Resuming at the original revision returns the remaining results exactly,
+	// with no duplicates or skips.
+	iter, err = resumeAt(rev)
+	require.NoError(err)
+	tRequire.VerifyOrderedIteratorResults(iter, expected[2:]...)
+}
+
 func ReverseQueryFilteredOverMultipleValuesCursorTest(t *testing.T, tester DatastoreTester) {
 	rawDS, err := tester.New(0, veryLargeGCInterval, veryLargeGCWindow, 1)
 	require.NoError(t, err)
@@ -390,6 +469,83 @@ func ReverseQueryCursorTest(t *testing.T, tester DatastoreTester) {
 	}
 }
 
+// ReverseQueryCursorEdgeCasesTest tests reverse-query pagination over subjects both with
+// and without subject relations interleaved, and ensures that a cursor pointing past the
+// final row yields an empty, non-erroring iterator.
+func ReverseQueryCursorEdgeCasesTest(t *testing.T, tester DatastoreTester) {
+	rawDS, err := tester.New(0, veryLargeGCInterval, veryLargeGCWindow, 1)
+	require.NoError(t, err)
+
+	// Create a datastore with the standard schema but no data.
+	ds, _ := testfixtures.StandardDatastoreWithSchema(rawDS, require.New(t))
+
+	// Add test relationships with folder subjects both with and without relations.
+	rev, err := ds.ReadWriteTx(t.Context(), func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.WriteRelationships(ctx, []tuple.RelationshipUpdate{
+			tuple.Create(tuple.MustParse("document:firstdoc#parent@folder:alpha")),
+			tuple.Create(tuple.MustParse("folder:root#viewer@folder:alpha#viewer")),
+			tuple.Create(tuple.MustParse("document:seconddoc#parent@folder:beta")),
+			tuple.Create(tuple.MustParse("folder:root#viewer@folder:delta#viewer")),
+			tuple.Create(tuple.MustParse("document:thirddoc#parent@folder:gamma")),
+		})
+	})
+	require.NoError(t, err)
+
+	for _, sortBy := range []options.SortOrder{options.ByResource, options.BySubject} {
+		t.Run(fmt.Sprintf("SortBy-%d", sortBy), func(t *testing.T) {
+			require := require.New(t)
+			reader := ds.SnapshotReader(rev)
+
+			var limit uint64 = 1
+			var cursor options.Cursor
+
+			// Page through one result at a time, ensuring each relationship is
+			// encountered exactly once.
+			foundTuples := mapz.NewSet[string]()
+			for i := 0; i < 5; i++ {
+				iter, err := reader.ReverseQueryRelationships(
+					t.Context(),
+					datastore.SubjectsFilter{
+						SubjectType: testfixtures.FolderNS.Name,
+					},
+					options.WithSortForReverse(sortBy),
+					options.WithLimitForReverse(&limit),
+					options.WithAfterForReverse(cursor),
+					options.WithQueryShapeForReverse(queryshape.Varying),
+				)
+				require.NoError(err)
+
+				for rel, err := range iter {
+					require.NoError(err)
+					require.True(foundTuples.Add(tuple.MustString(rel)))
+					cursor = options.ToCursor(rel)
+				}
+			}
+
+			require.Equal(5, foundTuples.Len())
+
+			// The cursor now points at the final row; a query after it must yield an
+			// empty, non-erroring iterator.
+			iter, err := reader.ReverseQueryRelationships(
+				t.Context(),
+				datastore.SubjectsFilter{
+					SubjectType: testfixtures.FolderNS.Name,
+				},
+				options.WithSortForReverse(sortBy),
+				options.WithLimitForReverse(&limit),
+				options.WithAfterForReverse(cursor),
+				options.WithQueryShapeForReverse(queryshape.Varying),
+			)
+			require.NoError(err)
+
+			for _, err := range iter {
+				require.NoError(err)
+				require.Fail("expected no results after the final row")
+			}
+		})
+	}
+}
+
 func foreachTxType(
 	ctx context.Context,
 	ds datastore.Datastore,
@@ -2,6 +2,7 @@ package test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"sync"
@@ -594,6 +595,7 @@ func CreateAlreadyExistingTest(t *testing.T, tester DatastoreTester) {
 	_, _ = ds.ReadWriteTx(ctx, f)
 	_, err = ds.ReadWriteTx(ctx, f)
 	require.Error(err)
+	require.ErrorAs(err, &common.CreateRelationshipExistsError{})
 	grpcutil.RequireStatus(t, codes.AlreadyExists, err)
 }
 
@@ -1918,6 +1920,71 @@ func RelationshipExpirationTest(t *testing.T, tester DatastoreTester) {
 	ensureReverseRelationships(ctx, require, ds, rel4)
 }
 
+// RelationshipExpirationBehaviorTest tests the expiration behavior query option over a
+// mix of live and expired-but-uncollected relationships.
+func RelationshipExpirationBehaviorTest(t *testing.T, tester DatastoreTester) {
+	require := require.New(t)
+
+	rawDS, err := tester.New(0, veryLargeGCInterval, veryLargeGCWindow, 1)
+	require.NoError(err)
+
+	ds, _ := testfixtures.StandardDatastoreWithData(rawDS, require)
+	ctx := t.Context()
+	tRequire := testfixtures.RelationshipChecker{Require: require, DS: ds}
+
+	// Create a live relationship, along with a relationship whose expiration is then
+	// touched into the past, leaving it expired but not yet garbage collected.
+	liveRel, err := tuple.Parse("document:livedoc#expiring_viewer@user:tom[expiration:2900-01-01T00:00:00Z]")
+	require.NoError(err)
+
+	expiredRel, err := tuple.Parse("document:expireddoc#expiring_viewer@user:fred[expiration:2900-01-01T00:00:00Z]")
+	require.NoError(err)
+
+	_, err = common.WriteRelationships(ctx, ds, tuple.UpdateOperationCreate, liveRel, expiredRel)
+	require.NoError(err)
+
+	expiredRel, err = tuple.Parse("document:expireddoc#expiring_viewer@user:fred[expiration:2000-01-01T00:00:00Z]")
+	require.NoError(err)
+
+	rev, err := common.WriteRelationships(ctx, ds, tuple.UpdateOperationTouch, expiredRel)
+	require.NoError(err)
+
+	queryWithBehavior := func(behavior options.ExpirationBehavior) datastore.RelationshipIterator {
+		iter, err := ds.SnapshotReader(rev).QueryRelationships(ctx, datastore.RelationshipsFilter{
+			OptionalResourceType:     "document",
+			OptionalResourceRelation: "expiring_viewer",
+		}, options.WithExpirationBehavior(behavior), options.WithQueryShape(queryshape.Varying))
+		require.NoError(err)
+		return iter
+	}
+
+	// By default, only the live relationship is returned.
+	tRequire.VerifyIteratorResults(queryWithBehavior(options.ExcludeExpiredRelationships), liveRel)
+
+	// Including expired relationships returns both.
+	tRequire.VerifyIteratorResults(queryWithBehavior(options.IncludeExpiredRelationships), liveRel, expiredRel)
+
+	// Requesting only expired relationships returns just the expired one.
+	tRequire.VerifyIteratorResults(queryWithBehavior(options.OnlyExpiredRelationships), expiredRel)
+
+	// Reverse queries must honor the behavior as well.
+	reverseWithBehavior := func(behavior options.ExpirationBehavior) datastore.RelationshipIterator {
+		iter, err := ds.SnapshotReader(rev).ReverseQueryRelationships(ctx, datastore.SubjectsFilter{
+			SubjectType: "user",
+		},
+			options.WithResRelation(&options.ResourceRelation{Namespace: "document", Relation: "expiring_viewer"}),
+			options.WithExpirationBehaviorForReverse(behavior),
+			options.WithQueryShapeForReverse(queryshape.Varying),
+		)
+		require.NoError(err)
+		return iter
+	}
+
+	tRequire.VerifyIteratorResults(reverseWithBehavior(options.ExcludeExpiredRelationships), liveRel)
+	tRequire.VerifyIteratorResults(reverseWithBehavior(options.IncludeExpiredRelationships), liveRel, expiredRel)
+	tRequire.VerifyIteratorResults(reverseWithBehavior(options.OnlyExpiredRelationships), expiredRel)
+}
+
 // TypedTouchAlreadyExistingTest tests touching a relationship twice, when valid type information is provided.
 func TypedTouchAlreadyExistingTest(t *testing.T, tester DatastoreTester) {
 	require := require.New(t)
@@ -2206,6 +2273,107 @@ func BulkDeleteRelationshipsTest(t *testing.T, tester DatastoreTester) {
 	require.Empty(found)
 }
 
+// DeleteRelationshipsWithProgressTest tests chunked deletion of relationships with
+// progress reporting and mid-way resumption.
+func DeleteRelationshipsWithProgressTest(t *testing.T, tester DatastoreTester) {
+	filter := &v1.RelationshipFilter{
+		ResourceType: testResourceNamespace,
+	}
+
+	writeTestRels := func(ctx context.Context, require *require.Assertions, ds datastore.Datastore) {
+		toTouch := make([]tuple.RelationshipUpdate, 0, 7)
+		for i := 0; i < 7; i++ {
+			toTouch = append(toTouch, tuple.Touch(makeTestRel(fmt.Sprintf("resource%d", i), "user0")))
+		}
+
+		_, err := ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+			return rwt.WriteRelationships(ctx, toTouch)
+		})
+		require.NoError(err)
+	}
+
+	requireNoneRemaining := func(ctx context.Context, require *require.Assertions, ds datastore.Datastore) {
+		headRev, err := ds.HeadRevision(ctx)
+		require.NoError(err)
+
+		iter, err := ds.SnapshotReader(headRev).QueryRelationships(ctx, datastore.RelationshipsFilter{
+			OptionalResourceType: testResourceNamespace,
+		}, options.WithQueryShape(queryshape.Varying))
+		require.NoError(err)
+
+		found, err := datastore.IteratorToSlice(iter)
+		require.NoError(err)
+		require.Empty(found)
+	}
+
+	t.Run("progress", func(t *testing.T) {
+		require := require.New(t)
+		ctx := t.Context()
+
+		ds, err := tester.New(0, veryLargeGCInterval, veryLargeGCWindow, 1)
+		require.NoError(err)
+		defer ds.Close()
+
+		setupDatastore(ds, require)
+		writeTestRels(ctx, require, ds)
+
+		// Delete in chunks of 2 over 7 rows, expecting progress after each chunk.
+		var counts []uint64
+		deleted, err := datastore.DeleteRelationshipsWithProgress(ctx, ds, filter, datastore.DeleteWithProgressOptions{
+			ChunkSize: 2,
+			ProgressCallback: func(deletedSoFar uint64, _ tuple.Relationship) error {
+				counts = append(counts, deletedSoFar)
+				return nil
+			},
+		})
+		require.NoError(err)
+		require.Equal(uint64(7), deleted)
+		require.Equal([]uint64{2, 4, 6, 7}, counts)
+
+		requireNoneRemaining(ctx, require, ds)
+	})
+
+	t.Run("resumption", func(t *testing.T) {
+		require := require.New(t)
+		ctx := t.Context()
+
+		ds, err := tester.New(0, veryLargeGCInterval, veryLargeGCWindow, 1)
+		require.NoError(err)
+		defer ds.Close()
+
+		setupDatastore(ds, require)
+		writeTestRels(ctx, require, ds)
+
+		// Interrupt the delete after the second chunk.
+		errInterrupted := errors.New("interrupted")
+		var lastDeleted tuple.Relationship
+		deleted, err := datastore.DeleteRelationshipsWithProgress(ctx, ds, filter, datastore.DeleteWithProgressOptions{
+			ChunkSize: 2,
+			ProgressCallback: func(deletedSoFar uint64, last tuple.Relationship) error {
+				lastDeleted = last
+				if deletedSoFar >= 4 {
+					return errInterrupted
+				}
+				return nil
+			},
+		})
+		require.ErrorIs(err, errInterrupted)
+		require.Equal(uint64(4), deleted)
+
+		// Resume from the reported cursor and ensure the final count covers the
+		// entire operation.
+		resumed, err := datastore.DeleteRelationshipsWithProgress(ctx, ds, filter, datastore.DeleteWithProgressOptions{
+			ChunkSize:    2,
+			ResumeAfter:  options.Cursor(&lastDeleted),
+			DeletedSoFar: deleted,
+		})
+		require.NoError(err)
+		require.Equal(uint64(7), resumed)
+
+		requireNoneRemaining(ctx, require, ds)
+	})
+}
+
 func onrToSubjectsFilter(onr tuple.ObjectAndRelation) datastore.SubjectsFilter {
 	return datastore.SubjectsFilter{
 		SubjectType:        onr.ObjectType,
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/authzed/spicedb/internal/datastore/common"
+	"github.com/authzed/spicedb/internal/datastore/revisions"
 	"github.com/authzed/spicedb/pkg/datastore"
 	ns "github.com/authzed/spicedb/pkg/namespace"
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
@@ -263,6 +264,94 @@ func CheckRevisionsTest(t *testing.T, tester DatastoreTester) {
 	require.NoError(ds.CheckRevision(ctx, head), "expected head revision to be valid in GC Window")
 }
 
+// StaleRevisionRejectionTest ensures that an exact revision which has fallen outside of
+// the GC window is rejected by CheckRevision with a typed error carrying the minimum
+// revision the datastore can still serve.
+func StaleRevisionRejectionTest(t *testing.T, tester DatastoreTester) {
+	require := require.New(t)
+	gcWindow := 1 * time.Second
+
+	// NOTE: we disable the background GC process here and instead manually run it below.
+	ds, err := tester.New(0, veryLargeGCInterval, gcWindow, 1)
+	require.NoError(err)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	staleRev, err := ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.WriteNamespaces(ctx, testNamespace)
+	})
+	require.NoError(err)
+	require.NoError(ds.CheckRevision(ctx, staleRev), "expected written revision to be valid in GC Window")
+
+	// Sleep to ensure the written revision falls outside of the GC window.
+	time.Sleep(gcWindow + 100*time.Millisecond)
+
+	if gcable, ok := ds.(common.GarbageCollectableDatastore); ok {
+		gcable.ResetGCCompleted()
+		require.NoError(common.RunGarbageCollection(gcable, gcWindow, 10*time.Second))
+	}
+
+	// Write a newer revision so that the datastore has a transaction within the GC window.
+	_, err = ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.WriteNamespaces(ctx, testNamespace)
+	})
+	require.NoError(err)
+
+	err = ds.CheckRevision(ctx, staleRev)
+	require.Error(err, "expected GC'd revision to be rejected")
+
+	var invalidRevErr datastore.InvalidRevisionError
+	require.ErrorAs(err, &invalidRevErr)
+	require.Equal(datastore.RevisionStale, invalidRevErr.Reason())
+
+	// The error must recommend a minimum revision newer than the rejected one.
+	minimum := invalidRevErr.RecommendedMinimumRevision()
+	require.NotNil(minimum)
+	require.True(minimum.GreaterThan(staleRev), "expected the recommended minimum revision to be newer than the rejected revision")
+}
+
+// FabricatedFutureRevisionTest ensures that an exact revision which was never produced
+// by the datastore (e.g. one fabricated by a caller or minted by another cluster) is
+// rejected by CheckRevision rather than silently served.
+func FabricatedFutureRevisionTest(t *testing.T, tester DatastoreTester) {
+	require := require.New(t)
+
+	ds, err := tester.New(0, veryLargeGCInterval, veryLargeGCWindow, 1)
+	require.NoError(err)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	// Write a revision so that the datastore has a head transaction.
+	_, err = ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.WriteNamespaces(ctx, testNamespace)
+	})
+	require.NoError(err)
+
+	head, err := ds.HeadRevision(ctx)
+	require.NoError(err)
+
+	var fabricated datastore.Revision
+	switch rev := head.(type) {
+	case revisions.TransactionIDRevision:
+		fabricated = revisions.NewForTransactionID(rev.TransactionID() + 1_000_000)
+	case revisions.WithTimestampRevision:
+		fabricated = rev.ConstructForTimestamp(rev.TimestampNanoSec() + time.Hour.Nanoseconds())
+	default:
+		// Snapshot-based revisions (e.g. Postgres) support parsing a bare transaction ID.
+		fabricated, err = ds.RevisionFromString("9999999999")
+		require.NoError(err)
+	}
+
+	err = ds.CheckRevision(ctx, fabricated)
+	require.Error(err, "expected fabricated future revision to be rejected")
+
+	var invalidRevErr datastore.InvalidRevisionError
+	require.ErrorAs(err, &invalidRevErr)
+	require.Equal(datastore.CouldNotDetermineRevision, invalidRevErr.Reason())
+}
+
 func SequentialRevisionsTest(t *testing.T, tester DatastoreTester) {
 	require := require.New(t)
 
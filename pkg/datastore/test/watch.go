@@ -819,6 +819,56 @@ func WatchCheckpointsTest(t *testing.T, tester DatastoreTester) {
 	verifyCheckpointUpdate(require, afterTouchRevision, changes)
 }
 
+// WatchCheckpointContractTest verifies the checkpoint contract shared by all backends:
+// when checkpoints are the only requested content, a checkpoint covering each write is
+// still emitted within the checkpoint interval, and checkpoint revisions never regress.
+func WatchCheckpointContractTest(t *testing.T, tester DatastoreTester) {
+	require := require.New(t)
+
+	ds, err := tester.New(0, veryLargeGCInterval, veryLargeGCWindow, 16)
+	require.NoError(err)
+
+	setupDatastore(ds, require)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	lowestRevision, err := ds.HeadRevision(ctx)
+	require.NoError(err)
+
+	changes, errchan := ds.Watch(ctx, lowestRevision, datastore.WatchOptions{
+		Content:            datastore.WatchCheckpoints,
+		CheckpointInterval: 100 * time.Millisecond,
+	})
+	require.Empty(errchan)
+
+	afterTouchRevision, err := common.WriteRelationships(ctx, ds, tuple.UpdateOperationTouch,
+		tuple.MustParse("document:checkpointed#viewer@user:tom"),
+	)
+	require.NoError(err)
+
+	var lastCheckpointRevision datastore.Revision
+	changeWait := time.NewTimer(waitForChangesTimeout)
+	for {
+		select {
+		case change, ok := <-changes:
+			require.True(ok)
+			require.True(change.IsCheckpoint, "expected only checkpoints, found: %v", change)
+
+			if lastCheckpointRevision != nil {
+				require.False(change.Revision.LessThan(lastCheckpointRevision), "checkpoint revisions must be monotonic; found %v after %v", change.Revision, lastCheckpointRevision)
+			}
+			lastCheckpointRevision = change.Revision
+
+			if change.Revision.Equal(afterTouchRevision) || change.Revision.GreaterThan(afterTouchRevision) {
+				return
+			}
+		case <-changeWait.C:
+			require.Fail("Timed out", "waited for checkpoint covering revision %v", afterTouchRevision)
+		}
+	}
+}
+
 func WatchEmissionStrategyTest(t *testing.T, tester DatastoreTester) {
 	require := require.New(t)
 
@@ -4,6 +4,10 @@ import (
 	"context"
 
 	v1 "github.com/authzed/authzed-go/proto/authzed/api/v1"
+
+	"github.com/authzed/spicedb/pkg/datastore/options"
+	"github.com/authzed/spicedb/pkg/datastore/queryshape"
+	"github.com/authzed/spicedb/pkg/tuple"
 )
 
 // DefinitionsOf returns just the schema definitions found in the list of revisioned
@@ -16,6 +20,106 @@ func DefinitionsOf[T SchemaDefinition](revisionedDefinitions []RevisionedDefinit
 	return definitions
 }
 
+// defaultDeleteChunkSize is the number of relationships deleted per transaction by
+// DeleteRelationshipsWithProgress when no chunk size is specified.
+const defaultDeleteChunkSize = 1000
+
+// DeleteWithProgressOptions are options for a DeleteRelationshipsWithProgress call.
+type DeleteWithProgressOptions struct {
+	// ChunkSize is the maximum number of relationships deleted in a single
+	// transaction. If given the zero value, defaultDeleteChunkSize is used.
+	ChunkSize uint64
+
+	// ProgressCallback, if non-nil, is invoked after each chunk commits with the
+	// total number of relationships deleted so far and the last relationship
+	// deleted. Returning a non-nil error halts the operation; the last
+	// relationship can be handed back via ResumeAfter to resume later.
+	ProgressCallback func(deletedSoFar uint64, lastDeleted tuple.Relationship) error
+
+	// ResumeAfter, if non-nil, resumes a previously interrupted delete from after
+	// the given relationship.
+	ResumeAfter options.Cursor
+
+	// DeletedSoFar seeds the running count when resuming, so that the final
+	// returned count covers the entire operation.
+	DeletedSoFar uint64
+}
+
+// DeleteRelationshipsWithProgress deletes all relationships matching the given filter in
+// chunks, each committed in its own transaction, to avoid blowing past transaction size
+// limits or holding long-lived locks when the filter matches a very large number of
+// relationships. Returns the total number of relationships deleted, including any count
+// seeded via DeletedSoFar.
+//
+// Because chunks commit independently, the operation is not atomic with respect to
+// concurrent writes: relationships matching the filter that are written while the delete
+// is in progress may or may not be deleted, and those sorting before the current progress
+// cursor will survive. On error, the count deleted so far is returned alongside the error
+// and the operation can be resumed via ResumeAfter.
+func DeleteRelationshipsWithProgress(ctx context.Context, ds Datastore, filter *v1.RelationshipFilter, opts DeleteWithProgressOptions) (uint64, error) {
+	chunkSize := opts.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = defaultDeleteChunkSize
+	}
+
+	dsFilter, err := RelationshipsFilterFromPublicFilter(filter)
+	if err != nil {
+		return opts.DeletedSoFar, err
+	}
+
+	deletedSoFar := opts.DeletedSoFar
+	cursor := opts.ResumeAfter
+	for {
+		var chunk []tuple.Relationship
+		_, err := ds.ReadWriteTx(ctx, func(ctx context.Context, rwt ReadWriteTransaction) error {
+			chunk = chunk[:0]
+			iter, err := rwt.QueryRelationships(
+				ctx,
+				dsFilter,
+				options.WithSort(options.ByResource),
+				options.WithLimit(&chunkSize),
+				options.WithAfter(cursor),
+				options.WithQueryShape(queryshape.Varying),
+			)
+			if err != nil {
+				return err
+			}
+
+			updates := make([]tuple.RelationshipUpdate, 0, chunkSize)
+			for rel, err := range iter {
+				if err != nil {
+					return err
+				}
+
+				chunk = append(chunk, rel)
+				updates = append(updates, tuple.Delete(rel))
+			}
+
+			if len(updates) == 0 {
+				return nil
+			}
+
+			return rwt.WriteRelationships(ctx, updates)
+		})
+		if err != nil {
+			return deletedSoFar, err
+		}
+
+		if len(chunk) == 0 {
+			return deletedSoFar, nil
+		}
+
+		deletedSoFar += uint64(len(chunk))
+		lastDeleted := chunk[len(chunk)-1]
+		cursor = options.Cursor(&lastDeleted)
+		if opts.ProgressCallback != nil {
+			if err := opts.ProgressCallback(deletedSoFar, lastDeleted); err != nil {
+				return deletedSoFar, err
+			}
+		}
+	}
+}
+
 // DeleteAllData deletes all data from the datastore. Should only be used when explicitly requested.
 // The data is transactionally deleted, which means it may time out.
 func DeleteAllData(ctx context.Context, ds Datastore) error {
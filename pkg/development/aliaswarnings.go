@@ -0,0 +1,67 @@
+package development
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/authzed/spicedb/internal/namespace"
+	devinterface "github.com/authzed/spicedb/pkg/proto/developer/v1"
+	"github.com/authzed/spicedb/pkg/schema"
+)
+
+// aliasedPermissionWarningName is the warning name under which aliased permissions are
+// reported, and via which the warning can be suppressed.
+const aliasedPermissionWarningName = "permission-aliases-another"
+
+// GetAliasedPermissionWarnings returns a warning for each permission that is a pure alias of
+// another relation or permission in its definition, i.e. the same permission defined twice
+// under two names. Each warning includes the alias target's canonical key, so tooling can group
+// the members of an alias group. Deliberate aliases are common, so the warning is suppressible
+// per permission with the standard pragma comment:
+//
+//	// spicedb-ignore-warning: permission-aliases-another
+func GetAliasedPermissionWarnings(ctx context.Context, devCtx *DevContext) ([]*devinterface.DeveloperWarning, error) {
+	warnings := []*devinterface.DeveloperWarning{}
+	res := schema.ResolverForCompiledSchema(*devCtx.CompiledSchema)
+	ts := schema.NewTypeSystem(res)
+
+	for _, nsDef := range devCtx.CompiledSchema.ObjectDefinitions {
+		vdef, err := ts.GetValidatedDefinition(ctx, nsDef.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		aliases, err := vdef.Aliases()
+		if err != nil {
+			return nil, err
+		}
+		if len(aliases) == 0 {
+			continue
+		}
+
+		cacheKeys, err := namespace.CanonicalCacheKeys(vdef)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rel := range nsDef.Relation {
+			aliasTarget, ok := aliases[rel.Name]
+			if !ok {
+				continue
+			}
+
+			if shouldSkipCheck(rel.Metadata, aliasedPermissionWarningName) {
+				continue
+			}
+
+			warnings = append(warnings, warningForMetadata(
+				aliasedPermissionWarningName,
+				fmt.Sprintf("Permission %q is identical to %q (canonical key %q); consider consolidating them or documenting the intent", rel.Name, aliasTarget, cacheKeys[aliasTarget]),
+				rel.Name,
+				rel,
+			))
+		}
+	}
+
+	return warnings, nil
+}
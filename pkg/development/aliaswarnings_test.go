@@ -0,0 +1,102 @@
+package development
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	developerv1 "github.com/authzed/spicedb/pkg/proto/developer/v1"
+)
+
+func TestAliasedPermissionWarnings(t *testing.T) {
+	tcs := []struct {
+		name             string
+		schema           string
+		expectedWarnings []*developerv1.DeveloperWarning
+	}{
+		{
+			name: "no aliases",
+			schema: `definition user {}
+
+			definition document {
+				relation owner: user
+				relation viewer: user
+				permission view = viewer + owner
+			}
+			`,
+		},
+		{
+			name: "aliased permission",
+			schema: `definition user {}
+
+			definition document {
+				relation owner: user
+				permission edit = owner
+			}
+			`,
+			expectedWarnings: []*developerv1.DeveloperWarning{
+				{
+					Message:    "Permission \"edit\" is identical to \"owner\" (canonical key \"owner\"); consider consolidating them or documenting the intent (permission-aliases-another)",
+					Line:       5,
+					Column:     5,
+					SourceCode: "edit",
+				},
+			},
+		},
+		{
+			name: "chained aliases resolve to the terminal target",
+			schema: `definition user {}
+
+			definition document {
+				relation owner: user
+				permission edit = owner
+				permission edit2 = edit
+			}
+			`,
+			expectedWarnings: []*developerv1.DeveloperWarning{
+				{
+					Message:    "Permission \"edit\" is identical to \"owner\" (canonical key \"owner\"); consider consolidating them or documenting the intent (permission-aliases-another)",
+					Line:       5,
+					Column:     5,
+					SourceCode: "edit",
+				},
+				{
+					Message:    "Permission \"edit2\" is identical to \"owner\" (canonical key \"owner\"); consider consolidating them or documenting the intent (permission-aliases-another)",
+					Line:       6,
+					Column:     5,
+					SourceCode: "edit2",
+				},
+			},
+		},
+		{
+			name: "suppressed via pragma",
+			schema: `definition user {}
+
+			definition document {
+				relation owner: user
+				// spicedb-ignore-warning: permission-aliases-another
+				permission edit = owner
+			}
+			`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			devCtx, devErr, err := NewDevContext(t.Context(), &developerv1.RequestContext{
+				Schema: tc.schema,
+			})
+			require.NoError(t, err)
+			require.Empty(t, devErr)
+
+			warnings, err := GetAliasedPermissionWarnings(t.Context(), devCtx)
+			require.NoError(t, err)
+
+			if len(tc.expectedWarnings) == 0 {
+				require.Empty(t, warnings)
+			} else {
+				require.Equal(t, tc.expectedWarnings, warnings)
+			}
+		})
+	}
+}
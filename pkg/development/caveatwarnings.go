@@ -0,0 +1,64 @@
+package development
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/authzed/spicedb/internal/namespace"
+	caveattypes "github.com/authzed/spicedb/pkg/caveats/types"
+	corev1 "github.com/authzed/spicedb/pkg/proto/core/v1"
+	devinterface "github.com/authzed/spicedb/pkg/proto/developer/v1"
+)
+
+const (
+	// unusedCaveatParameterWarningName is the warning name under which unused caveat
+	// parameters are reported, and via which the warning can be suppressed.
+	unusedCaveatParameterWarningName = "unused-caveat-parameter"
+
+	// shadowedContextKeyWarningName is the warning name under which caveat parameters that
+	// shadow well-known server-injected context keys are reported, and via which the warning
+	// can be suppressed.
+	shadowedContextKeyWarningName = "caveat-parameter-shadows-context"
+)
+
+// addCaveatDefinitionWarnings returns warnings for the given caveat definition: one for each
+// parameter that its expression never references, and one for each parameter that shadows a
+// well-known context key injected by the server at evaluation time.
+func addCaveatDefinitionWarnings(_ context.Context, caveatDef *corev1.CaveatDefinition) ([]*devinterface.DeveloperWarning, error) {
+	warnings := []*devinterface.DeveloperWarning{}
+
+	if !shouldSkipCheck(caveatDef.Metadata, unusedCaveatParameterWarningName) {
+		unused, err := namespace.UnusedCaveatParameters(caveattypes.Default.TypeSet, caveatDef)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, paramName := range unused {
+			warnings = append(warnings, warningForMetadata(
+				unusedCaveatParameterWarningName,
+				fmt.Sprintf("Parameter %q for caveat %q is unused; it can be removed", paramName, caveatDef.Name),
+				paramName,
+				caveatDef,
+			))
+		}
+	}
+
+	if !shouldSkipCheck(caveatDef.Metadata, shadowedContextKeyWarningName) {
+		for _, paramName := range slices.Sorted(maps.Keys(caveatDef.ParameterTypes)) {
+			if !slices.Contains(namespace.WellKnownCaveatContextKeys, paramName) {
+				continue
+			}
+
+			warnings = append(warnings, warningForMetadata(
+				shadowedContextKeyWarningName,
+				fmt.Sprintf("Parameter %q for caveat %q shadows the well-known context key of the same name injected by the server; it is recommended to rename the parameter", paramName, caveatDef.Name),
+				paramName,
+				caveatDef,
+			))
+		}
+	}
+
+	return warnings, nil
+}
@@ -0,0 +1,113 @@
+package development
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	developerv1 "github.com/authzed/spicedb/pkg/proto/developer/v1"
+)
+
+func TestCaveatWarnings(t *testing.T) {
+	tcs := []struct {
+		name             string
+		schema           string
+		expectedWarnings []*developerv1.DeveloperWarning
+	}{
+		{
+			name: "no unused parameters",
+			schema: `definition user {}
+
+			caveat some_caveat(somecondition int, anothervalue int) {
+				somecondition == anothervalue
+			}
+			`,
+		},
+		{
+			name: "some unused parameters",
+			schema: `definition user {}
+
+			caveat some_caveat(somecondition int, unusedparam int) {
+				somecondition == 42
+			}
+			`,
+			expectedWarnings: []*developerv1.DeveloperWarning{
+				{
+					Message:    "Parameter \"unusedparam\" for caveat \"some_caveat\" is unused; it can be removed (unused-caveat-parameter)",
+					Line:       3,
+					Column:     4,
+					SourceCode: "unusedparam",
+				},
+			},
+		},
+		{
+			name: "all unused parameters",
+			schema: `definition user {}
+
+			caveat some_caveat(somecondition int, unusedparam int) {
+				1 == 1
+			}
+			`,
+			expectedWarnings: []*developerv1.DeveloperWarning{
+				{
+					Message:    "Parameter \"somecondition\" for caveat \"some_caveat\" is unused; it can be removed (unused-caveat-parameter)",
+					Line:       3,
+					Column:     4,
+					SourceCode: "somecondition",
+				},
+				{
+					Message:    "Parameter \"unusedparam\" for caveat \"some_caveat\" is unused; it can be removed (unused-caveat-parameter)",
+					Line:       3,
+					Column:     4,
+					SourceCode: "unusedparam",
+				},
+			},
+		},
+		{
+			name: "parameter shadowing well-known context key",
+			schema: `definition user {}
+
+			caveat not_expired(now timestamp, deadline timestamp) {
+				now < deadline
+			}
+			`,
+			expectedWarnings: []*developerv1.DeveloperWarning{
+				{
+					Message:    "Parameter \"now\" for caveat \"not_expired\" shadows the well-known context key of the same name injected by the server; it is recommended to rename the parameter (caveat-parameter-shadows-context)",
+					Line:       3,
+					Column:     4,
+					SourceCode: "now",
+				},
+			},
+		},
+		{
+			name: "unused parameter warning suppressed via comment",
+			schema: `definition user {}
+
+			// spicedb-ignore-warning: unused-caveat-parameter
+			caveat some_caveat(somecondition int, unusedparam int) {
+				somecondition == 42
+			}
+			`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			devCtx, devErr, err := NewDevContext(t.Context(), &developerv1.RequestContext{
+				Schema: tc.schema,
+			})
+			require.NoError(t, err)
+			require.Empty(t, devErr)
+
+			warnings, err := GetWarnings(t.Context(), devCtx)
+			require.NoError(t, err)
+
+			if len(tc.expectedWarnings) == 0 {
+				require.Empty(t, warnings)
+			} else {
+				require.Equal(t, tc.expectedWarnings, warnings)
+			}
+		})
+	}
+}
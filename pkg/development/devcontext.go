@@ -167,11 +167,11 @@ func (dc *DevContext) RunV1InMemoryService() (*grpc.ClientConn, func(), error) {
 	s := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			datastoremw.UnaryServerInterceptor(dc.Datastore),
-			consistency.UnaryServerInterceptor("development", consistency.TreatMismatchingTokensAsError),
+			consistency.UnaryServerInterceptor("development", consistency.TreatMismatchingTokensAsError, 0),
 		),
 		grpc.ChainStreamInterceptor(
 			datastoremw.StreamServerInterceptor(dc.Datastore),
-			consistency.StreamServerInterceptor("development", consistency.TreatMismatchingTokensAsError),
+			consistency.StreamServerInterceptor("development", consistency.TreatMismatchingTokensAsError, 0),
 		),
 	)
 	ps := v1svc.NewPermissionsServer(dc.Dispatcher, v1svc.PermissionsServerConfig{
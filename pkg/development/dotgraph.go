@@ -0,0 +1,275 @@
+package development
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/schema"
+	"github.com/authzed/spicedb/pkg/spiceerrors"
+	"github.com/authzed/spicedb/pkg/tuple"
+)
+
+// DotGraphOption configures the graph produced by GenerateDotGraph.
+type DotGraphOption func(*dotGraphConfig)
+
+type dotGraphConfig struct {
+	rootRelation string
+}
+
+// DotGraphRootedAt restricts the generated graph to the given relation or permission and
+// everything it transitively references.
+func DotGraphRootedAt(relationName string) DotGraphOption {
+	return func(cfg *dotGraphConfig) {
+		cfg.rootRelation = relationName
+	}
+}
+
+// GenerateDotGraph renders the dependency graph of the given validated definition in Graphviz
+// DOT form, for visualization when onboarding onto or debugging a schema. Permissions render as
+// boxes and relations as ellipses; computed userset references are solid edges; arrows are edges
+// labeled with their tupleset relation (and function, for functioned arrows); references to
+// other definitions discovered via allowed subject types are dashed; and `nil` and `_this`
+// leaves render as diamond nodes. Node and edge lines are emitted sorted, so output is stable
+// for golden tests.
+func GenerateDotGraph(ctx context.Context, vdef *schema.ValidatedDefinition, opts ...DotGraphOption) (string, error) {
+	cfg := &dotGraphConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	builder := &dotGraphBuilder{
+		vdef:  vdef,
+		nodes: map[string]string{},
+		edges: map[string]dotGraphEdge{},
+	}
+
+	nsName := vdef.Namespace().Name
+	for _, rel := range vdef.Namespace().Relation {
+		if err := builder.addRelation(ctx, rel); err != nil {
+			return "", err
+		}
+	}
+
+	if cfg.rootRelation != "" {
+		if _, ok := vdef.GetRelation(cfg.rootRelation); !ok {
+			return "", schema.NewRelationNotFoundErr(nsName, cfg.rootRelation)
+		}
+		builder.restrictTo(tuple.JoinRelRef(nsName, cfg.rootRelation))
+	}
+
+	return builder.render(nsName), nil
+}
+
+type dotGraphEdge struct {
+	from  string
+	to    string
+	attrs []string
+}
+
+type dotGraphBuilder struct {
+	vdef  *schema.ValidatedDefinition
+	nodes map[string]string
+	edges map[string]dotGraphEdge
+}
+
+// addRelation adds the node for the given relation or permission, along with the edges for its
+// rewrite expression or allowed subject types.
+func (dgb *dotGraphBuilder) addRelation(ctx context.Context, rel *core.Relation) error {
+	nsName := dgb.vdef.Namespace().Name
+	nodeID := tuple.JoinRelRef(nsName, rel.Name)
+
+	rewrite := rel.GetUsersetRewrite()
+	if rewrite != nil {
+		dgb.nodes[nodeID] = "[shape=box]"
+		return dgb.addRewriteEdges(ctx, nodeID, rewrite)
+	}
+
+	dgb.nodes[nodeID] = "[shape=ellipse]"
+	for _, allowedRelation := range rel.GetTypeInformation().GetAllowedDirectRelations() {
+		subjectID := allowedRelation.GetNamespace()
+		if allowedRelation.GetPublicWildcard() != nil {
+			subjectID += ":*"
+		} else if allowedRelation.GetRelation() != tuple.Ellipsis {
+			subjectID = tuple.JoinRelRef(subjectID, allowedRelation.GetRelation())
+		}
+
+		if _, ok := dgb.nodes[subjectID]; !ok {
+			dgb.nodes[subjectID] = "[shape=ellipse, style=dashed]"
+		}
+		dgb.addEdge(nodeID, subjectID, "style=dashed")
+	}
+	return nil
+}
+
+func (dgb *dotGraphBuilder) addRewriteEdges(ctx context.Context, fromID string, rewrite *core.UsersetRewrite) error {
+	var children []*core.SetOperation_Child
+	switch rw := rewrite.RewriteOperation.(type) {
+	case *core.UsersetRewrite_Union:
+		children = rw.Union.Child
+	case *core.UsersetRewrite_Intersection:
+		children = rw.Intersection.Child
+	case *core.UsersetRewrite_Exclusion:
+		children = rw.Exclusion.Child
+	default:
+		return spiceerrors.MustBugf("unknown rewrite kind %T", rw)
+	}
+
+	nsName := dgb.vdef.Namespace().Name
+	for _, childOneof := range children {
+		switch child := childOneof.ChildType.(type) {
+		case *core.SetOperation_Child_ComputedUserset:
+			dgb.addEdge(fromID, tuple.JoinRelRef(nsName, child.ComputedUserset.Relation))
+
+		case *core.SetOperation_Child_UsersetRewrite:
+			if err := dgb.addRewriteEdges(ctx, fromID, child.UsersetRewrite); err != nil {
+				return err
+			}
+
+		case *core.SetOperation_Child_TupleToUserset:
+			ttu := child.TupleToUserset
+			if err := dgb.addArrowEdges(ctx, fromID, ttu.Tupleset.Relation, ttu.ComputedUserset.Relation, ttu.Tupleset.Relation); err != nil {
+				return err
+			}
+
+		case *core.SetOperation_Child_FunctionedTupleToUserset:
+			fttu := child.FunctionedTupleToUserset
+			label := fttu.Tupleset.Relation
+			switch fttu.Function {
+			case core.FunctionedTupleToUserset_FUNCTION_ANY:
+				label += " (any)"
+			case core.FunctionedTupleToUserset_FUNCTION_ALL:
+				label += " (all)"
+			default:
+				return spiceerrors.MustBugf("unknown function %v", fttu.Function)
+			}
+
+			if err := dgb.addArrowEdges(ctx, fromID, fttu.Tupleset.Relation, fttu.ComputedUserset.Relation, label); err != nil {
+				return err
+			}
+
+		case *core.SetOperation_Child_XNil:
+			dgb.nodes["nil"] = "[shape=diamond]"
+			dgb.addEdge(fromID, "nil")
+
+		case *core.SetOperation_Child_XThis:
+			dgb.nodes["this"] = "[shape=diamond]"
+			dgb.addEdge(fromID, "this")
+
+		default:
+			return spiceerrors.MustBugf("unknown set operation child %T", child)
+		}
+	}
+	return nil
+}
+
+// addArrowEdges adds an edge for the arrow to the computed relation on each of the tupleset
+// relation's allowed subject definitions that define it. Edges into other definitions are
+// dashed.
+func (dgb *dotGraphBuilder) addArrowEdges(ctx context.Context, fromID string, tuplesetRelation string, computedRelationName string, label string) error {
+	nsName := dgb.vdef.Namespace().Name
+	allowedRelations, err := dgb.vdef.AllowedDirectRelationsAndWildcards(tuplesetRelation)
+	if err != nil {
+		return err
+	}
+
+	for _, allowedRelation := range allowedRelations {
+		if allowedRelation.GetPublicWildcard() != nil {
+			continue
+		}
+
+		targetDef, err := dgb.vdef.TypeSystem().GetDefinition(ctx, allowedRelation.GetNamespace())
+		if err != nil {
+			return err
+		}
+
+		if !targetDef.HasRelation(computedRelationName) {
+			continue
+		}
+
+		targetID := tuple.JoinRelRef(allowedRelation.GetNamespace(), computedRelationName)
+		attrs := []string{fmt.Sprintf("label=%q", label)}
+		if allowedRelation.GetNamespace() != nsName {
+			attrs = append(attrs, "style=dashed")
+
+			if _, ok := dgb.nodes[targetID]; !ok {
+				shape := "ellipse"
+				if targetRel, ok := targetDef.GetRelation(computedRelationName); ok && targetRel.GetUsersetRewrite() != nil {
+					shape = "box"
+				}
+				dgb.nodes[targetID] = fmt.Sprintf("[shape=%s, style=dashed]", shape)
+			}
+		}
+
+		dgb.addEdge(fromID, targetID, attrs...)
+	}
+	return nil
+}
+
+func (dgb *dotGraphBuilder) addEdge(fromID string, toID string, attrs ...string) {
+	edge := dotGraphEdge{from: fromID, to: toID, attrs: attrs}
+	dgb.edges[fromID+"|"+toID+"|"+strings.Join(attrs, ",")] = edge
+}
+
+// restrictTo drops every node and edge not reachable from the given node.
+func (dgb *dotGraphBuilder) restrictTo(rootID string) {
+	reachable := map[string]struct{}{rootID: {}}
+	queue := []string{rootID}
+	for len(queue) > 0 {
+		currentID := queue[0]
+		queue = queue[1:]
+
+		for _, edge := range dgb.edges {
+			if edge.from != currentID {
+				continue
+			}
+			if _, ok := reachable[edge.to]; !ok {
+				reachable[edge.to] = struct{}{}
+				queue = append(queue, edge.to)
+			}
+		}
+	}
+
+	for nodeID := range dgb.nodes {
+		if _, ok := reachable[nodeID]; !ok {
+			delete(dgb.nodes, nodeID)
+		}
+	}
+	for key, edge := range dgb.edges {
+		if _, ok := reachable[edge.from]; !ok {
+			delete(dgb.edges, key)
+		}
+	}
+}
+
+func (dgb *dotGraphBuilder) render(graphName string) string {
+	var rendered strings.Builder
+	fmt.Fprintf(&rendered, "digraph %q {\n", graphName)
+
+	nodeIDs := make([]string, 0, len(dgb.nodes))
+	for nodeID := range dgb.nodes {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Strings(nodeIDs)
+	for _, nodeID := range nodeIDs {
+		fmt.Fprintf(&rendered, "\t%q %s;\n", nodeID, dgb.nodes[nodeID])
+	}
+
+	edgeLines := make([]string, 0, len(dgb.edges))
+	for _, edge := range dgb.edges {
+		line := fmt.Sprintf("\t%q -> %q", edge.from, edge.to)
+		if len(edge.attrs) > 0 {
+			line += " [" + strings.Join(edge.attrs, ", ") + "]"
+		}
+		edgeLines = append(edgeLines, line+";\n")
+	}
+	sort.Strings(edgeLines)
+	for _, line := range edgeLines {
+		rendered.WriteString(line)
+	}
+
+	rendered.WriteString("}\n")
+	return rendered.String()
+}
@@ -0,0 +1,106 @@
+package development
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/pkg/schema"
+)
+
+func TestGenerateDotGraph(t *testing.T) {
+	compiled, devErr, err := CompileSchema(`definition user {}
+
+definition folder {
+	relation parent: folder
+	relation viewer: user | user:*
+	permission view = viewer + parent->view
+}
+
+definition document {
+	relation folder: folder
+	relation viewer: user
+	relation banned: user
+	permission view = (viewer - banned) + folder->view
+}`)
+	require.NoError(t, err)
+	require.Nil(t, devErr)
+
+	ctx := t.Context()
+	ts := schema.NewTypeSystem(schema.ResolverForSchema(*compiled))
+
+	t.Run("full graph", func(t *testing.T) {
+		vdef, err := ts.GetValidatedDefinition(ctx, "document")
+		require.NoError(t, err)
+
+		dot, err := GenerateDotGraph(ctx, vdef)
+		require.NoError(t, err)
+		require.Equal(t, `digraph "document" {
+	"document#banned" [shape=ellipse];
+	"document#folder" [shape=ellipse];
+	"document#view" [shape=box];
+	"document#viewer" [shape=ellipse];
+	"folder" [shape=ellipse, style=dashed];
+	"folder#view" [shape=box, style=dashed];
+	"user" [shape=ellipse, style=dashed];
+	"document#banned" -> "user" [style=dashed];
+	"document#folder" -> "folder" [style=dashed];
+	"document#view" -> "document#banned";
+	"document#view" -> "document#viewer";
+	"document#view" -> "folder#view" [label="folder", style=dashed];
+	"document#viewer" -> "user" [style=dashed];
+}
+`, dot)
+	})
+
+	t.Run("self-referential arrow", func(t *testing.T) {
+		vdef, err := ts.GetValidatedDefinition(ctx, "folder")
+		require.NoError(t, err)
+
+		dot, err := GenerateDotGraph(ctx, vdef)
+		require.NoError(t, err)
+		require.Equal(t, `digraph "folder" {
+	"folder" [shape=ellipse, style=dashed];
+	"folder#parent" [shape=ellipse];
+	"folder#view" [shape=box];
+	"folder#viewer" [shape=ellipse];
+	"user" [shape=ellipse, style=dashed];
+	"user:*" [shape=ellipse, style=dashed];
+	"folder#parent" -> "folder" [style=dashed];
+	"folder#view" -> "folder#view" [label="parent"];
+	"folder#view" -> "folder#viewer";
+	"folder#viewer" -> "user" [style=dashed];
+	"folder#viewer" -> "user:*" [style=dashed];
+}
+`, dot)
+	})
+
+	t.Run("rooted at a permission", func(t *testing.T) {
+		vdef, err := ts.GetValidatedDefinition(ctx, "document")
+		require.NoError(t, err)
+
+		dot, err := GenerateDotGraph(ctx, vdef, DotGraphRootedAt("view"))
+		require.NoError(t, err)
+		require.Equal(t, `digraph "document" {
+	"document#banned" [shape=ellipse];
+	"document#view" [shape=box];
+	"document#viewer" [shape=ellipse];
+	"folder#view" [shape=box, style=dashed];
+	"user" [shape=ellipse, style=dashed];
+	"document#banned" -> "user" [style=dashed];
+	"document#view" -> "document#banned";
+	"document#view" -> "document#viewer";
+	"document#view" -> "folder#view" [label="folder", style=dashed];
+	"document#viewer" -> "user" [style=dashed];
+}
+`, dot)
+	})
+
+	t.Run("rooted at an unknown relation", func(t *testing.T) {
+		vdef, err := ts.GetValidatedDefinition(ctx, "document")
+		require.NoError(t, err)
+
+		_, err = GenerateDotGraph(ctx, vdef, DotGraphRootedAt("unknown"))
+		require.ErrorContains(t, err, "relation/permission `unknown` not found")
+	})
+}
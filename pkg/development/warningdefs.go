@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/authzed/spicedb/internal/namespace"
 	corev1 "github.com/authzed/spicedb/pkg/proto/core/v1"
 	devinterface "github.com/authzed/spicedb/pkg/proto/developer/v1"
 	"github.com/authzed/spicedb/pkg/schema"
@@ -41,6 +42,31 @@ var lintRelationReferencesParentType = relationCheck{
 	},
 }
 
+var lintPermissionNormalizesToNil = relationCheck{
+	"permission-normalizes-to-nil",
+	func(
+		ctx context.Context,
+		relation *corev1.Relation,
+		def *schema.Definition,
+	) (*devinterface.DeveloperWarning, error) {
+		isEmpty, err := namespace.ExpressionIsEmpty(def.Namespace(), relation)
+		if err != nil {
+			return nil, err
+		}
+
+		if isEmpty {
+			return warningForMetadata(
+				"permission-normalizes-to-nil",
+				fmt.Sprintf("Permission %q is always empty; its expression reduces to `nil`", relation.Name),
+				relation.Name,
+				relation,
+			), nil
+		}
+
+		return nil, nil
+	},
+}
+
 var lintPermissionReferencingItself = computedUsersetCheck{
 	"permission-references-itself",
 	func(
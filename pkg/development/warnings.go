@@ -17,6 +17,7 @@ import (
 var allChecks = checks{
 	relationChecks: []relationCheck{
 		lintRelationReferencesParentType,
+		lintPermissionNormalizesToNil,
 	},
 	computedUsersetChecks: []computedUsersetCheck{
 		lintPermissionReferencingItself,
@@ -72,6 +73,66 @@ func GetWarnings(ctx context.Context, devCtx *DevContext) ([]*devinterface.Devel
 		warnings = append(warnings, found...)
 	}
 
+	for _, caveatDef := range devCtx.CompiledSchema.CaveatDefinitions {
+		found, err := addCaveatDefinitionWarnings(ctx, caveatDef)
+		if err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, found...)
+	}
+
+	return warnings, nil
+}
+
+// GetUnusedRelationWarnings returns warnings for relations in the schema that no permission in
+// their own definition references, and for relations that have no allowed subject types. These
+// are reported separately from GetWarnings because a relation may be intentionally referenced
+// only from *other* definitions (e.g. as the target of an arrow).
+func GetUnusedRelationWarnings(ctx context.Context, devCtx *DevContext) ([]*devinterface.DeveloperWarning, error) {
+	warnings := []*devinterface.DeveloperWarning{}
+	res := schema.ResolverForCompiledSchema(*devCtx.CompiledSchema)
+	ts := schema.NewTypeSystem(res)
+
+	for _, nsDef := range devCtx.CompiledSchema.ObjectDefinitions {
+		def, err := schema.NewDefinition(ts, nsDef)
+		if err != nil {
+			return nil, err
+		}
+
+		unreferenced, err := def.UnreferencedRelations()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, relationName := range unreferenced {
+			relation, ok := def.GetRelation(relationName)
+			if !ok {
+				return nil, spiceerrors.MustBugf("unreferenced relation %q not found in definition %q", relationName, nsDef.Name)
+			}
+
+			warnings = append(warnings, warningForMetadata(
+				"unreferenced-relation",
+				fmt.Sprintf("Relation %q is not referenced by any permission in definition %q", relationName, nsDef.Name),
+				relationName,
+				relation,
+			))
+		}
+
+		for _, relationName := range def.RelationsWithoutAllowedSubjectTypes() {
+			relation, ok := def.GetRelation(relationName)
+			if !ok {
+				return nil, spiceerrors.MustBugf("relation %q not found in definition %q", relationName, nsDef.Name)
+			}
+
+			warnings = append(warnings, warningForMetadata(
+				"relation-missing-allowed-types",
+				fmt.Sprintf("Relation %q has no allowed subject types, so no relationship can ever be written for it", relationName),
+				relationName,
+				relation,
+			))
+		}
+	}
+
 	return warnings, nil
 }
 
@@ -240,7 +240,7 @@ func TestWarnings(t *testing.T) {
 		{
 			name: "exclusion operation",
 			schema: `definition user {}
-			
+
 			definition document {
 				relation viewer: user
 				relation editor: user
@@ -249,6 +249,22 @@ func TestWarnings(t *testing.T) {
 			`,
 			expectedWarning: nil,
 		},
+		{
+			name: "permission normalizing to nil",
+			schema: `definition user {}
+
+			definition document {
+				relation viewer: user
+				permission view = viewer - viewer
+			}
+			`,
+			expectedWarning: &developerv1.DeveloperWarning{
+				Message:    "Permission \"view\" is always empty; its expression reduces to `nil` (permission-normalizes-to-nil)",
+				Line:       5,
+				Column:     5,
+				SourceCode: "view",
+			},
+		},
 	}
 
 	for _, tc := range tcs {
@@ -271,3 +287,76 @@ func TestWarnings(t *testing.T) {
 		})
 	}
 }
+
+func TestUnusedRelationWarnings(t *testing.T) {
+	tcs := []struct {
+		name             string
+		schema           string
+		expectedWarnings []*developerv1.DeveloperWarning
+	}{
+		{
+			name: "no unused relations",
+			schema: `definition user {}
+
+			definition document {
+				relation viewer: user
+				permission view = viewer
+			}
+			`,
+		},
+		{
+			name: "unreferenced relation",
+			schema: `definition user {}
+
+			definition document {
+				relation unused: user
+				relation viewer: user
+				permission view = viewer
+			}
+			`,
+			expectedWarnings: []*developerv1.DeveloperWarning{
+				{
+					Message:    "Relation \"unused\" is not referenced by any permission in definition \"document\" (unreferenced-relation)",
+					Line:       4,
+					Column:     5,
+					SourceCode: "unused",
+				},
+			},
+		},
+		{
+			name: "relation referenced via an arrow is not reported",
+			schema: `definition user {}
+
+			definition folder {
+				relation viewer: user
+				permission view = viewer
+			}
+
+			definition document {
+				relation parent: folder
+				relation viewer: user
+				permission view = viewer + parent->view
+			}
+			`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			devCtx, devErr, err := NewDevContext(t.Context(), &developerv1.RequestContext{
+				Schema: tc.schema,
+			})
+			require.NoError(t, err)
+			require.Empty(t, devErr)
+
+			warnings, err := GetUnusedRelationWarnings(t.Context(), devCtx)
+			require.NoError(t, err)
+
+			if len(tc.expectedWarnings) == 0 {
+				require.Empty(t, warnings)
+			} else {
+				require.Equal(t, tc.expectedWarnings, warnings)
+			}
+		})
+	}
+}
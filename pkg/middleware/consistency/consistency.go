@@ -3,13 +3,16 @@ package consistency
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	v1 "github.com/authzed/authzed-go/proto/authzed/api/v1"
@@ -50,6 +53,12 @@ const (
 	TreatMismatchingTokensAsError
 )
 
+// MaxStalenessHeader is the gRPC metadata header by which minimize-latency requests may
+// request a maximum staleness larger than the configured revision quantization window.
+// The value is parsed as a Go duration string and is clamped to the server-configured
+// staleness override ceiling.
+const MaxStalenessHeader = "x-spicedb-max-staleness"
+
 type hasConsistency interface{ GetConsistency() *v1.Consistency }
 
 type hasOptionalCursor interface{ GetOptionalCursor() *v1.Cursor }
@@ -97,9 +106,16 @@ func RevisionFromContext(ctx context.Context) (datastore.Revision, *v1.ZedToken,
 // AddRevisionToContext adds a revision to the given context, based on the consistency block found
 // in the given request (if applicable).
 func AddRevisionToContext(ctx context.Context, req any, ds datastore.Datastore, serviceLabel string, option MismatchingTokenOption) error {
+	return addRevisionToContext(ctx, req, ds, serviceLabel, option, 0)
+}
+
+// addRevisionToContext adds a revision to the given context, based on the consistency block found
+// in the given request (if applicable), allowing per-request staleness overrides up to the given
+// ceiling (0 disables overrides).
+func addRevisionToContext(ctx context.Context, req any, ds datastore.Datastore, serviceLabel string, option MismatchingTokenOption, stalenessOverrideCeiling time.Duration) error {
 	switch req := req.(type) {
 	case hasConsistency:
-		return addRevisionToContextFromConsistency(ctx, req, ds, serviceLabel, option)
+		return addRevisionToContextFromConsistency(ctx, req, ds, serviceLabel, option, stalenessOverrideCeiling)
 	default:
 		return nil
 	}
@@ -107,7 +123,7 @@ func AddRevisionToContext(ctx context.Context, req any, ds datastore.Datastore,
 
 // addRevisionToContextFromConsistency adds a revision to the given context, based on the consistency block found
 // in the given request (if applicable).
-func addRevisionToContextFromConsistency(ctx context.Context, req hasConsistency, ds datastore.Datastore, serviceLabel string, option MismatchingTokenOption) error {
+func addRevisionToContextFromConsistency(ctx context.Context, req hasConsistency, ds datastore.Datastore, serviceLabel string, option MismatchingTokenOption, stalenessOverrideCeiling time.Duration) error {
 	handle := ctx.Value(revisionKey)
 	if handle == nil {
 		return nil
@@ -148,7 +164,7 @@ func addRevisionToContextFromConsistency(ctx context.Context, req hasConsistency
 			ConsistencyCounter.WithLabelValues("minlatency", source, serviceLabel).Inc()
 		}
 
-		databaseRev, err := ds.OptimizedRevision(ctx)
+		databaseRev, err := minimizeLatencyRevision(ctx, ds, stalenessOverrideCeiling)
 		if err != nil {
 			return rewriteDatastoreError(err)
 		}
@@ -215,6 +231,47 @@ func addRevisionToContextFromConsistency(ctx context.Context, req hasConsistency
 	return nil
 }
 
+// minimizeLatencyRevision computes the revision for a minimize-latency request, honoring any
+// per-request staleness override carried in the request metadata, clamped to the given ceiling.
+func minimizeLatencyRevision(ctx context.Context, ds datastore.Datastore, stalenessOverrideCeiling time.Duration) (datastore.Revision, error) {
+	if stalenessOverrideCeiling > 0 {
+		requested, err := requestedMaxStaleness(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if requested > 0 {
+			if requested > stalenessOverrideCeiling {
+				requested = stalenessOverrideCeiling
+			}
+
+			// NOTE: the datastore enforces the floor: a requested staleness below the
+			// configured quantization window falls back to the standard optimized revision.
+			if bounded := datastore.UnwrapAs[datastore.BoundedStalenessDatastore](ds); bounded != nil {
+				return bounded.OptimizedRevisionWithStaleness(ctx, requested)
+			}
+		}
+	}
+
+	return ds.OptimizedRevision(ctx)
+}
+
+// requestedMaxStaleness returns the maximum staleness requested via the MaxStalenessHeader
+// metadata header, if any.
+func requestedMaxStaleness(ctx context.Context) (time.Duration, error) {
+	values := metadata.ValueFromIncomingContext(ctx, MaxStalenessHeader)
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	staleness, err := time.ParseDuration(values[0])
+	if err != nil || staleness < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid %s header value: %s", MaxStalenessHeader, values[0])
+	}
+
+	return staleness, nil
+}
+
 var bypassServiceWhitelist = map[string]struct{}{
 	"/grpc.reflection.v1alpha.ServerReflection/": {},
 	"/grpc.reflection.v1.ServerReflection/":      {},
@@ -222,8 +279,10 @@ var bypassServiceWhitelist = map[string]struct{}{
 }
 
 // UnaryServerInterceptor returns a new unary server interceptor that performs per-request exchange of
-// the specified consistency configuration for the revision at which to perform the request.
-func UnaryServerInterceptor(serviceLabel string, option MismatchingTokenOption) grpc.UnaryServerInterceptor {
+// the specified consistency configuration for the revision at which to perform the request. The
+// staleness override ceiling bounds any per-request staleness override requested via the
+// MaxStalenessHeader metadata header; 0 disables overrides.
+func UnaryServerInterceptor(serviceLabel string, option MismatchingTokenOption, stalenessOverrideCeiling time.Duration) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		for bypass := range bypassServiceWhitelist {
 			if strings.HasPrefix(info.FullMethod, bypass) {
@@ -232,7 +291,7 @@ func UnaryServerInterceptor(serviceLabel string, option MismatchingTokenOption)
 		}
 		ds := datastoremw.MustFromContext(ctx)
 		newCtx := ContextWithHandle(ctx)
-		if err := AddRevisionToContext(newCtx, req, ds, serviceLabel, option); err != nil {
+		if err := addRevisionToContext(newCtx, req, ds, serviceLabel, option, stalenessOverrideCeiling); err != nil {
 			return nil, err
 		}
 
@@ -241,25 +300,28 @@ func UnaryServerInterceptor(serviceLabel string, option MismatchingTokenOption)
 }
 
 // StreamServerInterceptor returns a new stream server interceptor that performs per-request exchange of
-// the specified consistency configuration for the revision at which to perform the request.
-func StreamServerInterceptor(serviceLabel string, option MismatchingTokenOption) grpc.StreamServerInterceptor {
+// the specified consistency configuration for the revision at which to perform the request. The
+// staleness override ceiling bounds any per-request staleness override requested via the
+// MaxStalenessHeader metadata header; 0 disables overrides.
+func StreamServerInterceptor(serviceLabel string, option MismatchingTokenOption, stalenessOverrideCeiling time.Duration) grpc.StreamServerInterceptor {
 	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		for bypass := range bypassServiceWhitelist {
 			if strings.HasPrefix(info.FullMethod, bypass) {
 				return handler(srv, stream)
 			}
 		}
-		wrapper := &recvWrapper{stream, ContextWithHandle(stream.Context()), serviceLabel, option, AddRevisionToContext}
+		wrapper := &recvWrapper{stream, ContextWithHandle(stream.Context()), serviceLabel, option, stalenessOverrideCeiling, addRevisionToContext}
 		return handler(srv, wrapper)
 	}
 }
 
 type recvWrapper struct {
 	grpc.ServerStream
-	ctx          context.Context
-	serviceLabel string
-	option       MismatchingTokenOption
-	handler      func(context.Context, any, datastore.Datastore, string, MismatchingTokenOption) error
+	ctx                      context.Context
+	serviceLabel             string
+	option                   MismatchingTokenOption
+	stalenessOverrideCeiling time.Duration
+	handler                  func(context.Context, any, datastore.Datastore, string, MismatchingTokenOption, time.Duration) error
 }
 
 func (s *recvWrapper) Context() context.Context { return s.ctx }
@@ -269,7 +331,7 @@ func (s *recvWrapper) RecvMsg(m any) error {
 		return err
 	}
 	ds := datastoremw.MustFromContext(s.ctx)
-	return s.handler(s.ctx, m, ds, s.serviceLabel, s.option)
+	return s.handler(s.ctx, m, ds, s.serviceLabel, s.option, s.stalenessOverrideCeiling)
 }
 
 // pickBestRevision compares the provided ZedToken with the optimized revision of the datastore, and returns the most
@@ -329,7 +391,9 @@ func rewriteDatastoreError(err error) error {
 
 	switch {
 	case errors.As(err, &datastore.InvalidRevisionError{}):
-		return status.Errorf(codes.OutOfRange, "invalid revision: %s", err)
+		// NOTE: WithCodeAndReason attaches the error's metadata, which includes the
+		// recommended minimum revision for stale revisions, as an error detail.
+		return spiceerrors.WithCodeAndReason(fmt.Errorf("invalid revision: %w", err), codes.OutOfRange, v1.ErrorReason_ERROR_REASON_UNSPECIFIED)
 
 	case errors.As(err, &datastore.ReadOnlyError{}):
 		return shared.ErrServiceReadOnly
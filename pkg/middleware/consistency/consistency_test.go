@@ -4,13 +4,16 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 
 	v1 "github.com/authzed/authzed-go/proto/authzed/api/v1"
 	"github.com/authzed/grpcutil"
 
+	"github.com/authzed/spicedb/internal/datastore/memdb"
 	"github.com/authzed/spicedb/internal/datastore/proxy/proxy_test"
 	"github.com/authzed/spicedb/internal/datastore/revisions"
 	datastoremw "github.com/authzed/spicedb/internal/middleware/datastore"
@@ -418,6 +421,82 @@ func TestAtLeastAsFreshWithMismatchedTokenExpectFullConsistency(t *testing.T) {
 	ds.AssertExpectations(t)
 }
 
+func TestAddRevisionToContextMinimizeLatencyWithStalenessOverride(t *testing.T) {
+	require := require.New(t)
+
+	ds, err := memdb.NewMemdbDatastore(0, 10*time.Millisecond, 1*time.Hour)
+	require.NoError(err)
+
+	// Request a staleness above the ceiling; the ceiling should win.
+	ctx := metadata.NewIncomingContext(t.Context(), metadata.Pairs(MaxStalenessHeader, "30m"))
+	updated := ContextWithHandle(ctx)
+	updated = datastoremw.ContextWithDatastore(updated, ds)
+
+	ceiling := 7 * time.Minute
+	err = addRevisionToContext(updated, &v1.ReadRelationshipsRequest{
+		Consistency: &v1.Consistency{
+			Requirement: &v1.Consistency_MinimizeLatency{
+				MinimizeLatency: true,
+			},
+		},
+	}, ds, "somelabel", TreatMismatchingTokensAsError, ceiling)
+	require.NoError(err)
+
+	rev, _, err := RevisionFromContext(updated)
+	require.NoError(err)
+
+	revTime := time.Unix(0, rev.(revisions.TimestampRevision).TimestampNanoSec())
+	require.Zero(revTime.UnixNano()%ceiling.Nanoseconds(), "revision should be quantized to the ceiling-clamped staleness")
+	require.LessOrEqual(time.Since(revTime), ceiling)
+}
+
+func TestAddRevisionToContextStalenessOverrideDisabled(t *testing.T) {
+	require := require.New(t)
+
+	ds := &proxy_test.MockDatastore{}
+	ds.On("OptimizedRevision").Return(optimized, nil).Once()
+
+	// A ceiling of zero disables the override entirely; the header is ignored.
+	ctx := metadata.NewIncomingContext(t.Context(), metadata.Pairs(MaxStalenessHeader, "30m"))
+	updated := ContextWithHandle(ctx)
+	updated = datastoremw.ContextWithDatastore(updated, ds)
+
+	err := addRevisionToContext(updated, &v1.ReadRelationshipsRequest{
+		Consistency: &v1.Consistency{
+			Requirement: &v1.Consistency_MinimizeLatency{
+				MinimizeLatency: true,
+			},
+		},
+	}, ds, "somelabel", TreatMismatchingTokensAsError, 0)
+	require.NoError(err)
+
+	rev, _, err := RevisionFromContext(updated)
+	require.NoError(err)
+
+	require.True(optimized.Equal(rev))
+	ds.AssertExpectations(t)
+}
+
+func TestAddRevisionToContextInvalidStalenessOverride(t *testing.T) {
+	require := require.New(t)
+
+	ds := &proxy_test.MockDatastore{}
+
+	ctx := metadata.NewIncomingContext(t.Context(), metadata.Pairs(MaxStalenessHeader, "notaduration"))
+	updated := ContextWithHandle(ctx)
+	updated = datastoremw.ContextWithDatastore(updated, ds)
+
+	err := addRevisionToContext(updated, &v1.ReadRelationshipsRequest{
+		Consistency: &v1.Consistency{
+			Requirement: &v1.Consistency_MinimizeLatency{
+				MinimizeLatency: true,
+			},
+		},
+	}, ds, "somelabel", TreatMismatchingTokensAsError, 7*time.Minute)
+	require.Error(err)
+	grpcutil.RequireStatus(t, codes.InvalidArgument, err)
+}
+
 func TestAddRevisionToContextAtLeastAsFreshMatchingIDs(t *testing.T) {
 	require := require.New(t)
 
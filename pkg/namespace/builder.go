@@ -301,6 +301,17 @@ func TupleToUserset(tuplesetRelation, usersetRelation string) *core.SetOperation
 // and then applies the function to all children on the usersets found by following a relation on those loaded
 // tuples.
 func MustFunctionedTupleToUserset(tuplesetRelation, functionName, usersetRelation string) *core.SetOperation_Child {
+	child, err := FunctionedTupleToUserset(tuplesetRelation, functionName, usersetRelation)
+	if err != nil {
+		panic(err)
+	}
+	return child
+}
+
+// FunctionedTupleToUserset creates a child which first loads all tuples with the specific relation,
+// and then applies the function to all children on the usersets found by following a relation on those loaded
+// tuples. The function name must be one of `any` or `all`.
+func FunctionedTupleToUserset(tuplesetRelation, functionName, usersetRelation string) (*core.SetOperation_Child, error) {
 	function := core.FunctionedTupleToUserset_FUNCTION_ANY
 
 	switch functionName {
@@ -311,7 +322,7 @@ func MustFunctionedTupleToUserset(tuplesetRelation, functionName, usersetRelatio
 		function = core.FunctionedTupleToUserset_FUNCTION_ALL
 
 	default:
-		panic(spiceerrors.MustBugf("unknown function name: %s", functionName))
+		return nil, spiceerrors.MustBugf("unknown function name: %s", functionName)
 	}
 
 	return &core.SetOperation_Child{
@@ -327,7 +338,7 @@ func MustFunctionedTupleToUserset(tuplesetRelation, functionName, usersetRelatio
 				},
 			},
 		},
-	}
+	}, nil
 }
 
 // Rewrite wraps a rewrite as a set operation child of another rewrite.
@@ -2,6 +2,7 @@ package namespace
 
 import (
 	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 	iv1 "github.com/authzed/spicedb/pkg/proto/impl/v1"
@@ -71,6 +72,150 @@ func AddComment(metadata *core.Metadata, comment string) (*core.Metadata, error)
 	return metadata, nil
 }
 
+// GetCaveatParameterDefaults returns the default values for caveat parameters found within
+// the given metadata message, if any.
+func GetCaveatParameterDefaults(metadata *core.Metadata) (map[string]any, error) {
+	if metadata == nil {
+		return nil, nil
+	}
+
+	for _, msg := range metadata.MetadataMessage {
+		var defaultsStruct structpb.Struct
+		if err := msg.UnmarshalTo(&defaultsStruct); err == nil {
+			return defaultsStruct.AsMap(), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// SetCaveatParameterDefaults stores the given default values for caveat parameters into the
+// given metadata message.
+func SetCaveatParameterDefaults(metadata *core.Metadata, defaults map[string]any) (*core.Metadata, error) {
+	if metadata == nil {
+		metadata = &core.Metadata{}
+	}
+
+	defaultsStruct, err := structpb.NewStruct(defaults)
+	if err != nil {
+		return metadata, err
+	}
+
+	encoded, err := anypb.New(defaultsStruct)
+	if err != nil {
+		return metadata, err
+	}
+
+	metadata.MetadataMessage = append(metadata.MetadataMessage, encoded)
+	return metadata, nil
+}
+
+// GetRelationshipOnlyCaveatParameters returns the names of caveat parameters designated as
+// suppliable only via relationship context, found within the given metadata message, if any.
+func GetRelationshipOnlyCaveatParameters(metadata *core.Metadata) ([]string, error) {
+	if metadata == nil {
+		return nil, nil
+	}
+
+	for _, msg := range metadata.MetadataMessage {
+		var namesList structpb.ListValue
+		if err := msg.UnmarshalTo(&namesList); err == nil {
+			names := make([]string, 0, len(namesList.Values))
+			for _, value := range namesList.Values {
+				names = append(names, value.GetStringValue())
+			}
+			return names, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// SetRelationshipOnlyCaveatParameters stores the given caveat parameter names as suppliable
+// only via relationship context into the given metadata message.
+func SetRelationshipOnlyCaveatParameters(metadata *core.Metadata, parameterNames []string) (*core.Metadata, error) {
+	if metadata == nil {
+		metadata = &core.Metadata{}
+	}
+
+	values := make([]any, 0, len(parameterNames))
+	for _, parameterName := range parameterNames {
+		values = append(values, parameterName)
+	}
+
+	namesList, err := structpb.NewList(values)
+	if err != nil {
+		return metadata, err
+	}
+
+	encoded, err := anypb.New(namesList)
+	if err != nil {
+		return metadata, err
+	}
+
+	metadata.MetadataMessage = append(metadata.MetadataMessage, encoded)
+	return metadata, nil
+}
+
+// sensitiveParametersKey is the field under which sensitive caveat parameter names are
+// stored within their metadata wrapper value.
+const sensitiveParametersKey = "sensitiveParameters"
+
+// GetSensitiveCaveatParameters returns the names of caveat parameters whose context values
+// are designated as sensitive and must be redacted from debug output, found within the given
+// metadata message, if any.
+func GetSensitiveCaveatParameters(metadata *core.Metadata) ([]string, error) {
+	if metadata == nil {
+		return nil, nil
+	}
+
+	for _, msg := range metadata.MetadataMessage {
+		var wrapper structpb.Value
+		if err := msg.UnmarshalTo(&wrapper); err != nil {
+			continue
+		}
+
+		namesList := wrapper.GetStructValue().GetFields()[sensitiveParametersKey].GetListValue()
+		if namesList == nil {
+			continue
+		}
+
+		names := make([]string, 0, len(namesList.Values))
+		for _, value := range namesList.Values {
+			names = append(names, value.GetStringValue())
+		}
+		return names, nil
+	}
+
+	return nil, nil
+}
+
+// SetSensitiveCaveatParameters stores the given caveat parameter names as sensitive, to be
+// redacted from debug output, into the given metadata message.
+func SetSensitiveCaveatParameters(metadata *core.Metadata, parameterNames []string) (*core.Metadata, error) {
+	if metadata == nil {
+		metadata = &core.Metadata{}
+	}
+
+	values := make([]any, 0, len(parameterNames))
+	for _, parameterName := range parameterNames {
+		values = append(values, parameterName)
+	}
+
+	wrapper, err := structpb.NewValue(map[string]any{sensitiveParametersKey: values})
+	if err != nil {
+		return metadata, err
+	}
+
+	encoded, err := anypb.New(wrapper)
+	if err != nil {
+		return metadata, err
+	}
+
+	metadata.MetadataMessage = append(metadata.MetadataMessage, encoded)
+	return metadata, nil
+}
+
 // GetRelationKind returns the kind of the relation.
 func GetRelationKind(relation *core.Relation) iv1.RelationMetadata_RelationKind {
 	metadata := relation.Metadata
@@ -2306,7 +2306,7 @@ func (m *Metadata) validate(all bool) error {
 			if _, ok := _Metadata_MetadataMessage_InLookup[a.GetTypeUrl()]; !ok {
 				err := MetadataValidationError{
 					field:  fmt.Sprintf("MetadataMessage[%v]", idx),
-					reason: "type URL must be in list [type.googleapis.com/impl.v1.DocComment type.googleapis.com/impl.v1.RelationMetadata]",
+					reason: "type URL must be in list [type.googleapis.com/impl.v1.DocComment type.googleapis.com/impl.v1.RelationMetadata type.googleapis.com/google.protobuf.Struct type.googleapis.com/google.protobuf.ListValue type.googleapis.com/google.protobuf.Value]",
 				}
 				if !all {
 					return err
@@ -2396,8 +2396,11 @@ var _ interface {
 } = MetadataValidationError{}
 
 var _Metadata_MetadataMessage_InLookup = map[string]struct{}{
-	"type.googleapis.com/impl.v1.DocComment":       {},
-	"type.googleapis.com/impl.v1.RelationMetadata": {},
+	"type.googleapis.com/impl.v1.DocComment":        {},
+	"type.googleapis.com/impl.v1.RelationMetadata":  {},
+	"type.googleapis.com/google.protobuf.Struct":    {},
+	"type.googleapis.com/google.protobuf.ListValue": {},
+	"type.googleapis.com/google.protobuf.Value":     {},
 }
 
 // Validate checks the field values on NamespaceDefinition with the rules
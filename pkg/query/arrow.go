@@ -121,7 +121,67 @@ func (a *Arrow) IterSubjectsImpl(ctx *Context, resource Object) (PathSeq, error)
 }
 
 func (a *Arrow) IterResourcesImpl(ctx *Context, subject ObjectAndRelation) (PathSeq, error) {
-	return nil, spiceerrors.MustBugf("unimplemented")
+	// Reverse mode inverts the walk: enumerate the right side's resources for
+	// the subject (the intermediate objects), then enumerate the left side's
+	// resources treating each intermediate as the subject.
+	return func(yield func(Path, error) bool) {
+		rightSeq, err := ctx.IterResources(a.right, subject)
+		if err != nil {
+			yield(Path{}, err)
+			return
+		}
+
+		totalResultPaths := 0
+		for rightPath, err := range rightSeq {
+			if err != nil {
+				yield(Path{}, err)
+				return
+			}
+
+			intermediate := rightPath.Resource.WithEllipses()
+			ctx.TraceStep(a, "walking left side back from intermediate %s:%s", intermediate.ObjectType, intermediate.ObjectID)
+
+			leftSeq, err := ctx.IterResources(a.left, intermediate)
+			if err != nil {
+				yield(Path{}, err)
+				return
+			}
+
+			for leftPath, err := range leftSeq {
+				if err != nil {
+					yield(Path{}, err)
+					return
+				}
+
+				// Both legs of the walk must hold, so caveats combine with AND.
+				var combinedCaveat *core.CaveatExpression
+				if leftPath.Caveat != nil && rightPath.Caveat != nil {
+					combinedCaveat = caveats.And(leftPath.Caveat, rightPath.Caveat)
+				} else if leftPath.Caveat != nil {
+					combinedCaveat = leftPath.Caveat
+				} else if rightPath.Caveat != nil {
+					combinedCaveat = rightPath.Caveat
+				}
+
+				combinedPath := Path{
+					Resource:   leftPath.Resource,
+					Relation:   leftPath.Relation,
+					Subject:    rightPath.Subject,
+					Caveat:     combinedCaveat,
+					Expiration: rightPath.Expiration,
+					Integrity:  rightPath.Integrity,
+					Metadata:   make(map[string]any),
+				}
+
+				totalResultPaths++
+				if !yield(combinedPath, nil) {
+					return
+				}
+			}
+		}
+
+		ctx.TraceStep(a, "reverse arrow completed with %d total result paths", totalResultPaths)
+	}, nil
 }
 
 func (a *Arrow) Clone() Iterator {
@@ -0,0 +1,128 @@
+package query
+
+import (
+	"github.com/authzed/spicedb/internal/caveats"
+)
+
+// defaultArrowChunkSize is the default number of intermediate objects handed to
+// the computed evaluator per call, sized to keep datastore IN-clauses bounded.
+const defaultArrowChunkSize = 100
+
+// ComputedEvaluator evaluates the computed side of an arrow for a chunk of
+// intermediate objects, yielding paths whose resources are among those objects.
+type ComputedEvaluator func(objects []Object) (PathSeq, error)
+
+// ArrowIterator implements tupleset->computed_userset semantics over path
+// sequences: for each path of the tupleset relation it evaluates the computed
+// relation on the path's subject object and emits the results rebound to the
+// original resource, AND-ing in the tupleset path's caveat and taking the
+// earlier expiration.
+//
+// Duplicate intermediate subjects are deduplicated before fan-out (OR-merging
+// their tupleset caveats) to avoid redundant sub-evaluations, and fan-out is
+// chunked so the datastore sees IN-clause sized batches.
+type ArrowIterator struct {
+	tupleset  PathSeq
+	computed  ComputedEvaluator
+	chunkSize int
+}
+
+// NewArrowIterator creates an ArrowIterator over the given tupleset sequence
+// and computed-side evaluator.
+func NewArrowIterator(tupleset PathSeq, computed ComputedEvaluator) *ArrowIterator {
+	return &ArrowIterator{
+		tupleset:  tupleset,
+		computed:  computed,
+		chunkSize: defaultArrowChunkSize,
+	}
+}
+
+// WithChunkSize overrides the number of intermediate objects evaluated per
+// computed call.
+func (a *ArrowIterator) WithChunkSize(chunkSize int) *ArrowIterator {
+	a.chunkSize = chunkSize
+	return a
+}
+
+// IterPaths returns the arrowed sequence of paths.
+func (a *ArrowIterator) IterPaths() PathSeq {
+	return func(yield func(Path, error) bool) {
+		// Collect the tupleset, deduplicating intermediate subjects so each is
+		// evaluated only once. Multiple tupleset paths from the same resource
+		// to the same intermediate are OR-merged, since any of them suffices;
+		// paths from distinct resources are kept separate so each can be
+		// rebound individually after fan-out.
+		intermediates := make(map[string][]Path)
+		ordered := make([]Object, 0)
+		broken := false
+		a.tupleset(func(p Path, err error) bool {
+			if err != nil {
+				yield(Path{}, err)
+				broken = true
+				return false
+			}
+
+			obj := GetObject(p.Subject)
+			key := obj.Key()
+			entries, seen := intermediates[key]
+			for i, existing := range entries {
+				if !existing.Resource.Equals(p.Resource) {
+					continue
+				}
+				merged, merr := existing.MergeOr(p)
+				if merr != nil {
+					yield(Path{}, merr)
+					broken = true
+					return false
+				}
+				merged.Relation = existing.Relation
+				intermediates[key][i] = merged
+				return true
+			}
+
+			intermediates[key] = append(entries, p)
+			if !seen {
+				ordered = append(ordered, obj)
+			}
+			return true
+		})
+		if broken {
+			return
+		}
+
+		// Fan out to the computed side in chunks.
+		for start := 0; start < len(ordered); start += a.chunkSize {
+			end := min(start+a.chunkSize, len(ordered))
+			chunk := ordered[start:end]
+
+			computedSeq, err := a.computed(chunk)
+			if err != nil {
+				yield(Path{}, err)
+				return
+			}
+
+			for computedPath, err := range computedSeq {
+				if err != nil {
+					yield(Path{}, err)
+					return
+				}
+
+				for _, tuplesetPath := range intermediates[computedPath.Resource.Key()] {
+					result := computedPath.WithResource(tuplesetPath.Resource, tuplesetPath.Relation)
+					result.Caveat = caveats.And(tuplesetPath.Caveat, computedPath.Caveat)
+
+					// Both the tupleset membership and the computed permission
+					// must remain valid, so the earlier expiration wins.
+					if tuplesetPath.Expiration != nil &&
+						(result.Expiration == nil || tuplesetPath.Expiration.Before(*result.Expiration)) {
+						result.Expiration = tuplesetPath.Expiration
+					}
+
+					if !yield(result, nil) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
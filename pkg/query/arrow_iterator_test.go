@@ -0,0 +1,161 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+// mapComputedEvaluator builds a ComputedEvaluator backed by a static map from
+// object key to computed paths, recording the chunks it was called with.
+func mapComputedEvaluator(byObject map[string][]Path, chunks *[][]Object) ComputedEvaluator {
+	return func(objects []Object) (PathSeq, error) {
+		if chunks != nil {
+			*chunks = append(*chunks, objects)
+		}
+		var out []Path
+		for _, obj := range objects {
+			out = append(out, byObject[obj.Key()]...)
+		}
+		return pathSeqOf(out...), nil
+	}
+}
+
+func TestArrowPathIterator(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	t.Run("RebindsToOriginalResource", func(t *testing.T) {
+		t.Parallel()
+
+		tupleset := pathSeqOf(MustPathFromString("document:doc1#parent@folder:folder1"))
+		computed := mapComputedEvaluator(map[string][]Path{
+			"folder:folder1": {MustPathFromString("folder:folder1#owner@user:alice")},
+		}, nil)
+
+		paths, err := CollectAll(NewArrowIterator(tupleset, computed).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1)
+		require.Equal("document", paths[0].Resource.ObjectType)
+		require.Equal("doc1", paths[0].Resource.ObjectID)
+		require.Equal("parent", paths[0].Relation)
+		require.Equal("alice", paths[0].Subject.ObjectID)
+	})
+
+	t.Run("ANDsTuplesetCaveat", func(t *testing.T) {
+		t.Parallel()
+
+		tupleset := pathSeqOf(caveatedPath("document:doc1#parent@folder:folder1", "tupleset_caveat"))
+		computed := mapComputedEvaluator(map[string][]Path{
+			"folder:folder1": {caveatedPath("folder:folder1#owner@user:alice", "computed_caveat")},
+		}, nil)
+
+		paths, err := CollectAll(NewArrowIterator(tupleset, computed).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1)
+
+		caveat := paths[0].Caveat
+		require.NotNil(caveat)
+		require.NotNil(caveat.GetOperation())
+		require.Equal(core.CaveatOperation_AND, caveat.GetOperation().Op)
+		require.Len(caveat.GetOperation().GetChildren(), 2)
+	})
+
+	t.Run("EarlierExpirationWins", func(t *testing.T) {
+		t.Parallel()
+
+		earlier := time.Now().Add(time.Hour)
+		later := time.Now().Add(2 * time.Hour)
+
+		tuplesetPath := MustPathFromString("document:doc1#parent@folder:folder1")
+		tuplesetPath.Expiration = &earlier
+		computedPath := MustPathFromString("folder:folder1#owner@user:alice")
+		computedPath.Expiration = &later
+
+		computed := mapComputedEvaluator(map[string][]Path{
+			"folder:folder1": {computedPath},
+		}, nil)
+
+		paths, err := CollectAll(NewArrowIterator(pathSeqOf(tuplesetPath), computed).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1)
+		require.NotNil(paths[0].Expiration)
+		require.Equal(earlier, *paths[0].Expiration)
+	})
+
+	t.Run("DuplicateIntermediatesEvaluatedOnce", func(t *testing.T) {
+		t.Parallel()
+
+		// The same parent folder is reachable through two tupleset
+		// relationships with different caveats; the computed side must only be
+		// evaluated once, and membership is conditional on either caveat.
+		tupleset := pathSeqOf(
+			caveatedPath("document:doc1#parent@folder:folder1", "caveat1"),
+			caveatedPath("document:doc1#parent@folder:folder1", "caveat2"),
+		)
+
+		var chunks [][]Object
+		computed := mapComputedEvaluator(map[string][]Path{
+			"folder:folder1": {MustPathFromString("folder:folder1#owner@user:alice")},
+		}, &chunks)
+
+		paths, err := CollectAll(NewArrowIterator(tupleset, computed).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1)
+		require.Len(chunks, 1)
+		require.Len(chunks[0], 1, "the duplicate intermediate must be deduplicated before fan-out")
+
+		caveat := paths[0].Caveat
+		require.NotNil(caveat)
+		require.NotNil(caveat.GetOperation())
+		require.Equal(core.CaveatOperation_OR, caveat.GetOperation().Op, "either tupleset path suffices")
+	})
+
+	t.Run("FanOutIsChunked", func(t *testing.T) {
+		t.Parallel()
+
+		var tuplesetPaths []Path
+		for i := range 5 {
+			tuplesetPaths = append(tuplesetPaths,
+				MustPathFromString(fmt.Sprintf("document:doc1#parent@folder:folder%d", i)))
+		}
+
+		var chunks [][]Object
+		computed := mapComputedEvaluator(map[string][]Path{}, &chunks)
+
+		_, err := CollectAll(NewArrowIterator(pathSeqOf(tuplesetPaths...), computed).WithChunkSize(2).IterPaths())
+		require.NoError(err)
+		require.Len(chunks, 3, "5 intermediates at chunk size 2 should take 3 calls")
+		require.Len(chunks[0], 2)
+		require.Len(chunks[2], 1)
+	})
+
+	t.Run("TuplesetError", func(t *testing.T) {
+		t.Parallel()
+
+		childErr := errors.New("tupleset failed")
+		computed := mapComputedEvaluator(map[string][]Path{}, nil)
+
+		_, err := CollectAll(NewArrowIterator(erroringPathSeq(childErr), computed).IterPaths())
+		require.ErrorIs(err, childErr)
+	})
+
+	t.Run("ComputedError", func(t *testing.T) {
+		t.Parallel()
+
+		computedErr := errors.New("computed failed")
+		computed := func(objects []Object) (PathSeq, error) {
+			return nil, computedErr
+		}
+
+		tupleset := pathSeqOf(MustPathFromString("document:doc1#parent@folder:folder1"))
+		_, err := CollectAll(NewArrowIterator(tupleset, computed).IterPaths())
+		require.ErrorIs(err, computedErr)
+	})
+}
@@ -0,0 +1,88 @@
+package query
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestCancellationMidStream(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	baseCtx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	ctx := &Context{
+		Context:  baseCtx,
+		Executor: LocalExecutor{},
+	}
+
+	union := NewUnion()
+	union.addSubIterator(NewLargeFixedIterator())
+
+	pathSeq, err := ctx.Check(union, NewObjects("document", "doc0", "doc1", "doc2", "doc3"), NewObject("user", "user0").WithEllipses())
+	require.NoError(err)
+
+	// Cancel after the first path and assert the iterator stops within one
+	// item, surfacing a wrapped context.Canceled.
+	yielded := 0
+	var seqErr error
+	for _, err := range pathSeq {
+		if err != nil {
+			seqErr = err
+			break
+		}
+		yielded++
+		cancel()
+	}
+
+	require.Equal(1, yielded, "the iterator must stop within one item of cancellation")
+	require.ErrorIs(seqErr, context.Canceled)
+
+	goleak.VerifyNone(t, goleak.IgnoreCurrent())
+}
+
+func TestCancellationBeforeExecution(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	baseCtx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	ctx := &Context{
+		Context:  baseCtx,
+		Executor: LocalExecutor{},
+	}
+
+	union := NewUnion()
+	union.addSubIterator(NewDocumentAccessFixedIterator())
+
+	_, err := ctx.Check(union, NewObjects("document", "doc1"), NewObject("user", "alice").WithEllipses())
+	require.ErrorIs(err, context.Canceled, "an already-canceled context must fail before execution")
+}
+
+func TestDeadlineExceededSurfaces(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	baseCtx, cancel := context.WithDeadline(t.Context(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	ctx := &Context{
+		Context:  baseCtx,
+		Executor: LocalExecutor{},
+	}
+
+	union := NewUnion()
+	union.addSubIterator(NewDocumentAccessFixedIterator())
+
+	_, err := ctx.Check(union, NewObjects("document", "doc1"), NewObject("user", "alice").WithEllipses())
+	require.ErrorIs(err, context.DeadlineExceeded)
+}
@@ -3,7 +3,9 @@ package query
 import (
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/authzed/spicedb/internal/caveats"
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 )
 
@@ -59,6 +61,19 @@ func (c *CaveatIterator) runCaveats(ctx *Context, subSeq PathSeq) (PathSeq, erro
 	return func(yield func(Path, error) bool) {
 		ctx.TraceStep(c, "applying caveat '%s' to sub-iterator results", c.caveat.CaveatName)
 
+		// When debug tracing is enabled, capture per-caveat evaluation outcomes into the trace.
+		if ctx.shouldTrace() && ctx.CaveatRunner != nil {
+			ctx.CaveatRunner.WithEvaluationObserver(func(caveatName string, _ map[string]any, result caveats.ExpressionResult, duration time.Duration) {
+				step, ok, err := ctx.CaveatRunner.EvaluationStepForResult(result)
+				if err != nil || !ok {
+					ctx.TraceStep(c, "caveat '%s' evaluated: value=%v, partial=%v, duration=%s", caveatName, result.Value(), result.IsPartial(), duration)
+					return
+				}
+
+				ctx.TraceStep(c, "caveat '%s' evaluated: result=%s, missing=%v, residual=%q, duration=%s", step.CaveatName, step.Result, step.MissingFields, step.ResidualExpression, duration)
+			})
+		}
+
 		processedCount := 0
 		passedCount := 0
 
@@ -0,0 +1,44 @@
+package query
+
+import (
+	"github.com/authzed/spicedb/internal/caveats"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+// PruneStaticCaveats partially evaluates each relation leaf's caveat against
+// the request's caveat context before execution. Leaves whose caveat is
+// statically false are replaced with empty iterators, so their branch never
+// issues a datastore query; leaves whose caveat is statically true emit paths
+// with the caveat removed; leaves whose caveat cannot be decided (missing
+// context values) are left intact, and their paths keep the residual
+// expression for the usual post-evaluation.
+//
+// The request context is treated as authoritative for the variables it
+// supplies. Requires the Context's CaveatRunner and Reader to be set; when no
+// caveat context was supplied, the plan is returned unchanged.
+func PruneStaticCaveats(ctx *Context, root Iterator) (Iterator, error) {
+	if ctx.CaveatRunner == nil || len(ctx.CaveatContext) == 0 {
+		return root, nil
+	}
+
+	return Walk(root, func(it Iterator) (Iterator, error) {
+		leaf, ok := it.(*RelationIterator)
+		if !ok || leaf.base.Caveat() == "" {
+			return it, nil
+		}
+
+		expr := caveats.CaveatAsExpr(&core.ContextualizedCaveat{CaveatName: leaf.base.Caveat()})
+		result, err := ctx.CaveatRunner.RunCaveatExpression(ctx, expr, ctx.CaveatContext, ctx.Reader, caveats.RunCaveatExpressionNoDebugging)
+		if err != nil {
+			return nil, err
+		}
+
+		if result.IsPartial() {
+			return it, nil
+		}
+		if result.Value() {
+			return leaf.withStaticallyTrueCaveat(), nil
+		}
+		return NewFixedIterator(), nil
+	})
+}
@@ -0,0 +1,136 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/internal/caveats"
+	"github.com/authzed/spicedb/internal/datastore/dsfortesting"
+	"github.com/authzed/spicedb/internal/datastore/memdb"
+	"github.com/authzed/spicedb/internal/testfixtures"
+	"github.com/authzed/spicedb/pkg/caveats/types"
+	corev1 "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/schema/v2"
+	"github.com/authzed/spicedb/pkg/tuple"
+)
+
+const prunableSchema = `
+caveat branch_one(expected int) {
+	expected == 1
+}
+
+caveat branch_two(expected int) {
+	expected == 2
+}
+
+caveat branch_three(expected int) {
+	expected == 3
+}
+
+definition user {}
+
+definition document {
+	relation first: user with branch_one
+	relation second: user with branch_two
+	relation third: user with branch_three
+	permission view = first + second + third
+}
+`
+
+func buildPrunableUnion(t *testing.T, dsSchema *schema.Schema) *Union {
+	t.Helper()
+
+	union := NewUnion()
+	for _, relation := range []string{"first", "second", "third"} {
+		union.addSubIterator(NewRelationIterator(dsSchema.Definitions()["document"].Relations()[relation].BaseRelations()[0]))
+	}
+	return union
+}
+
+func TestPruneStaticCaveats(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	rawDS, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ds, revision := testfixtures.DatastoreFromSchemaAndTestRelationships(rawDS, prunableSchema, []tuple.Relationship{
+		tuple.MustParse("document:doc1#first@user:alice[branch_one]"),
+		tuple.MustParse("document:doc1#second@user:alice[branch_two]"),
+		tuple.MustParse("document:doc1#third@user:alice[branch_three]"),
+	}, require)
+
+	defs, err := ds.SnapshotReader(revision).ListAllNamespaces(t.Context())
+	require.NoError(err)
+
+	namespaces := make([]*corev1.NamespaceDefinition, 0, len(defs))
+	for _, def := range defs {
+		namespaces = append(namespaces, def.Definition)
+	}
+	dsSchema, err := schema.BuildSchemaFromDefinitions(namespaces, nil)
+	require.NoError(err)
+
+	t.Run("OnlyTheStaticallyTrueBranchExecutes", func(t *testing.T) {
+		tracer := NewTraceLogger()
+		ctx := &Context{
+			Context:       t.Context(),
+			Executor:      LocalExecutor{},
+			Reader:        ds.SnapshotReader(revision),
+			CaveatContext: map[string]any{"expected": int64(2)},
+			CaveatRunner:  caveats.NewCaveatRunner(types.Default.TypeSet),
+			TraceLogger:   tracer,
+		}
+
+		pruned, err := PruneStaticCaveats(ctx, buildPrunableUnion(t, dsSchema))
+		require.NoError(err)
+
+		pathSeq, err := ctx.Check(pruned, NewObjects("document", "doc1"), NewObject("user", "alice").WithEllipses())
+		require.NoError(err)
+		paths, err := CollectAll(pathSeq)
+		require.NoError(err)
+
+		require.Len(paths, 1)
+		require.Nil(paths[0].Caveat, "a statically-true caveat must be removed from emitted paths")
+
+		queries := strings.Count(tracer.DumpTrace(), "querying datastore")
+		require.Equal(1, queries, "pruned branches must never reach the datastore")
+	})
+
+	t.Run("UndecidableCaveatsKeepTheResidualExpression", func(t *testing.T) {
+		ctx := &Context{
+			Context:       t.Context(),
+			Executor:      LocalExecutor{},
+			Reader:        ds.SnapshotReader(revision),
+			CaveatContext: map[string]any{"unrelated": int64(42)},
+			CaveatRunner:  caveats.NewCaveatRunner(types.Default.TypeSet),
+		}
+
+		pruned, err := PruneStaticCaveats(ctx, buildPrunableUnion(t, dsSchema))
+		require.NoError(err)
+
+		pathSeq, err := ctx.Check(pruned, NewObjects("document", "doc1"), NewObject("user", "alice").WithEllipses())
+		require.NoError(err)
+		paths, err := CollectAll(pathSeq)
+		require.NoError(err)
+
+		require.Len(paths, 1, "the union merges the three caveated paths for one endpoint")
+		require.NotNil(paths[0].Caveat, "undecided caveats must survive for post-evaluation")
+	})
+
+	t.Run("NoContextLeavesThePlanUntouched", func(t *testing.T) {
+		ctx := &Context{
+			Context:      t.Context(),
+			Executor:     LocalExecutor{},
+			Reader:       ds.SnapshotReader(revision),
+			CaveatRunner: caveats.NewCaveatRunner(types.Default.TypeSet),
+		}
+
+		union := buildPrunableUnion(t, dsSchema)
+		pruned, err := PruneStaticCaveats(ctx, union)
+		require.NoError(err)
+		require.Same(Iterator(union), pruned)
+	})
+}
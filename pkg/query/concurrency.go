@@ -0,0 +1,64 @@
+package query
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// collectBranchesConcurrently evaluates each child sequence on a bounded
+// worker pool, materializing its paths. Results are returned indexed by the
+// original branch position, so downstream merging remains deterministic
+// regardless of which branch finished first.
+//
+// Cancellation of ctx stops all branches, and a failing branch cancels its
+// siblings: each worker checks the group context between paths, so no branch
+// runs to completion after the overall evaluation is doomed.
+func collectBranchesConcurrently(ctx context.Context, concurrency int, children []PathSeq) ([][]Path, error) {
+	results := make([][]Path, len(children))
+
+	group, gctx := errgroup.WithContext(ctx)
+	group.SetLimit(concurrency)
+	for i, child := range children {
+		group.Go(func() error {
+			var collected []Path
+			for p, err := range child {
+				if err != nil {
+					return err
+				}
+				if err := gctx.Err(); err != nil {
+					return err
+				}
+				collected = append(collected, p)
+			}
+			results[i] = collected
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// replayBranches converts materialized branch results back into sequences, in
+// branch order, for consumption by the usual merge step.
+func replayBranches(results [][]Path) []PathSeq {
+	children := make([]PathSeq, len(results))
+	for i, paths := range results {
+		children[i] = pathSeqFromSlice(paths)
+	}
+	return children
+}
+
+// pathSeqFromSlice returns a PathSeq that yields the given paths in order.
+func pathSeqFromSlice(paths []Path) PathSeq {
+	return func(yield func(Path, error) bool) {
+		for _, p := range paths {
+			if !yield(p, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/internal/datastore/dsfortesting"
+	"github.com/authzed/spicedb/internal/datastore/memdb"
+	"github.com/authzed/spicedb/internal/testfixtures"
+	corev1 "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/schema/v2"
+)
+
+func TestConcurrentUnionMatchesSequential(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	children := []PathSeq{
+		pathSeqOf(
+			MustPathFromString("document:doc1#viewer@user:alice"),
+			MustPathFromString("document:doc3#viewer@user:alice"),
+		),
+		pathSeqOf(
+			MustPathFromString("document:doc1#viewer@user:alice"),
+			MustPathFromString("document:doc2#viewer@user:alice"),
+		),
+		pathSeqOf(
+			MustPathFromString("document:doc4#viewer@user:alice"),
+		),
+	}
+
+	sequential, err := CollectAll(NewUnionIterator(children...).IterPaths())
+	require.NoError(err)
+
+	parallel, err := CollectAll(NewUnionIterator(children...).WithConcurrency(t.Context(), 3).IterPaths())
+	require.NoError(err)
+
+	require.Equal(sequential, parallel, "parallel evaluation must not change the merged output")
+}
+
+func TestConcurrentIntersectionMatchesSequential(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	children := []PathSeq{
+		pathSeqOf(
+			MustPathFromString("document:doc1#viewer@user:alice"),
+			MustPathFromString("document:doc2#viewer@user:alice"),
+		),
+		pathSeqOf(
+			MustPathFromString("document:doc2#viewer@user:alice"),
+			MustPathFromString("document:doc3#viewer@user:alice"),
+		),
+	}
+
+	sequential, err := CollectAll(NewIntersectionIterator(children...).IterPaths())
+	require.NoError(err)
+
+	parallel, err := CollectAll(NewIntersectionIterator(children...).WithConcurrency(t.Context(), 2).IterPaths())
+	require.NoError(err)
+
+	require.Equal(sequential, parallel)
+}
+
+func TestConcurrentFailingChildCancelsSiblings(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	childErr := errors.New("branch failed")
+	failing := erroringPathSeq(childErr, MustPathFromString("document:doc1#viewer@user:alice"))
+
+	var siblingYields atomic.Int64
+	slowSibling := PathSeq(func(yield func(Path, error) bool) {
+		for i := range 1000 {
+			time.Sleep(time.Millisecond)
+			siblingYields.Add(1)
+			if !yield(MustPathFromString(fmt.Sprintf("document:slow%d#viewer@user:alice", i)), nil) {
+				return
+			}
+		}
+	})
+
+	union := NewUnionIterator(failing, slowSibling).WithConcurrency(t.Context(), 2)
+	_, err := CollectAll(union.IterPaths())
+	require.ErrorIs(err, childErr)
+	require.Less(siblingYields.Load(), int64(1000), "a failing branch must cancel its siblings")
+}
+
+func TestConcurrentParentCancellationStopsChildren(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	var yields atomic.Int64
+	slow := PathSeq(func(yield func(Path, error) bool) {
+		for i := range 1000 {
+			time.Sleep(time.Millisecond)
+			yields.Add(1)
+			if !yield(MustPathFromString(fmt.Sprintf("document:doc%d#viewer@user:alice", i)), nil) {
+				return
+			}
+		}
+	})
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	union := NewUnionIterator(slow, slow).WithConcurrency(ctx, 2)
+	_, err := CollectAll(union.IterPaths())
+	require.ErrorIs(err, context.Canceled)
+	require.Less(yields.Load(), int64(2000))
+}
+
+// delayedSeq simulates per-query datastore latency by sleeping before the
+// wrapped sequence starts yielding.
+func delayedSeq(delay time.Duration, seq PathSeq) PathSeq {
+	return func(yield func(Path, error) bool) {
+		time.Sleep(delay)
+		seq(yield)
+	}
+}
+
+func BenchmarkConcurrentUnion(b *testing.B) {
+	require := require.New(b)
+	rawDS, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ds, revision := testfixtures.StandardDatastoreWithData(rawDS, require)
+
+	objectDefs := []*corev1.NamespaceDefinition{testfixtures.UserNS.CloneVT(), testfixtures.FolderNS.CloneVT(), testfixtures.DocumentNS.CloneVT()}
+	dsSchema, err := schema.BuildSchemaFromDefinitions(objectDefs, nil)
+	require.NoError(err)
+
+	ctx := &Context{
+		Context:  b.Context(),
+		Executor: LocalExecutor{},
+		Reader:   ds.SnapshotReader(revision),
+	}
+
+	// Each branch issues a real memdb query, padded with artificial latency to
+	// stand in for a remote datastore round trip.
+	const branchLatency = 2 * time.Millisecond
+	makeChildren := func() []PathSeq {
+		children := make([]PathSeq, 0, 4)
+		for _, relation := range []string{"viewer", "editor", "owner", "viewer_and_editor"} {
+			leaf := NewRelationIterator(dsSchema.Definitions()["document"].Relations()[relation].BaseRelations()[0])
+			seq, err := ctx.IterSubjects(leaf, NewObject("document", "specialplan"))
+			require.NoError(err)
+			children = append(children, delayedSeq(branchLatency, seq))
+		}
+		return children
+	}
+
+	for _, concurrency := range []int{1, 2, 4} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			for b.Loop() {
+				union := NewUnionIterator(makeChildren()...)
+				if concurrency > 1 {
+					union = union.WithConcurrency(ctx, concurrency)
+				}
+				if _, err := CollectAll(union.IterPaths()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
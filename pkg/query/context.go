@@ -66,12 +66,7 @@ func (t *TraceLogger) ExitIterator(it Iterator, paths []Path) {
 	for i, p := range paths {
 		caveatInfo := ""
 		if p.Caveat != nil {
-			// Extract caveat name from CaveatExpression
-			if p.Caveat.GetCaveat() != nil {
-				caveatInfo = fmt.Sprintf("[%s]", p.Caveat.GetCaveat().CaveatName)
-			} else {
-				caveatInfo = "[complex_caveat]"
-			}
+			caveatInfo = "[" + caveats.StringifyExpression(p.Caveat) + "]"
 		}
 		pathStrs[i] = fmt.Sprintf("%s:%s#%s@%s:%s%s",
 			p.Resource.ObjectType, p.Resource.ObjectID, p.Relation,
@@ -197,6 +192,31 @@ func (ctx *Context) wrapPathSeqForTracing(it Iterator, pathSeq PathSeq) PathSeq
 	}
 }
 
+// checkCancelled returns a wrapped context error if the query's context has
+// been canceled or its deadline exceeded.
+func (ctx *Context) checkCancelled() error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("query execution halted: %w", err)
+	}
+	return nil
+}
+
+// wrapPathSeqForCancellation wraps a PathSeq so cancellation is noticed before
+// each yielded path, rather than only when the next datastore call fails.
+func (ctx *Context) wrapPathSeqForCancellation(pathSeq PathSeq) PathSeq {
+	return func(yield func(Path, error) bool) {
+		for path, err := range pathSeq {
+			if cerr := ctx.checkCancelled(); cerr != nil {
+				yield(Path{}, cerr)
+				return
+			}
+			if !yield(path, err) {
+				return
+			}
+		}
+	}
+}
+
 // Check tests if, for the underlying set of relationships (which may be a full expression or a basic lookup, depending on the iterator)
 // any of the `resources` are connected to `subject`.
 // Returns the sequence of matching paths, if they exist, at most `len(resources)`.
@@ -205,6 +225,10 @@ func (ctx *Context) Check(it Iterator, resources []Object, subject ObjectAndRela
 		return nil, spiceerrors.MustBugf("no executor has been set")
 	}
 
+	if err := ctx.checkCancelled(); err != nil {
+		return nil, err
+	}
+
 	tracedIterator := ctx.traceEnterIfEnabled(it, resources, subject)
 
 	pathSeq, err := ctx.Executor.Check(ctx, it, resources, subject)
@@ -212,7 +236,7 @@ func (ctx *Context) Check(it Iterator, resources []Object, subject ObjectAndRela
 		return nil, err
 	}
 
-	return ctx.wrapPathSeqForTracing(tracedIterator, pathSeq), nil
+	return ctx.wrapPathSeqForCancellation(ctx.wrapPathSeqForTracing(tracedIterator, pathSeq)), nil
 }
 
 // IterSubjects returns a sequence of all the paths in this set that match the given resource.
@@ -221,6 +245,10 @@ func (ctx *Context) IterSubjects(it Iterator, resource Object) (PathSeq, error)
 		return nil, spiceerrors.MustBugf("no executor has been set")
 	}
 
+	if err := ctx.checkCancelled(); err != nil {
+		return nil, err
+	}
+
 	tracedIterator := ctx.traceEnterIfEnabled(it, []Object{resource}, ObjectAndRelation{})
 
 	pathSeq, err := ctx.Executor.IterSubjects(ctx, it, resource)
@@ -228,7 +256,7 @@ func (ctx *Context) IterSubjects(it Iterator, resource Object) (PathSeq, error)
 		return nil, err
 	}
 
-	return ctx.wrapPathSeqForTracing(tracedIterator, pathSeq), nil
+	return ctx.wrapPathSeqForCancellation(ctx.wrapPathSeqForTracing(tracedIterator, pathSeq)), nil
 }
 
 // IterResources returns a sequence of all the relations in this set that match the given subject.
@@ -237,6 +265,10 @@ func (ctx *Context) IterResources(it Iterator, subject ObjectAndRelation) (PathS
 		return nil, spiceerrors.MustBugf("no executor has been set")
 	}
 
+	if err := ctx.checkCancelled(); err != nil {
+		return nil, err
+	}
+
 	tracedIterator := ctx.traceEnterIfEnabled(it, []Object{}, subject)
 
 	pathSeq, err := ctx.Executor.IterResources(ctx, it, subject)
@@ -244,7 +276,7 @@ func (ctx *Context) IterResources(it Iterator, subject ObjectAndRelation) (PathS
 		return nil, err
 	}
 
-	return ctx.wrapPathSeqForTracing(tracedIterator, pathSeq), nil
+	return ctx.wrapPathSeqForCancellation(ctx.wrapPathSeqForTracing(tracedIterator, pathSeq)), nil
 }
 
 // Executor as chooses how to proceed given an iterator -- perhaps in parallel, perhaps by RPC, etc -- and chooses how to process iteration from the subtree.
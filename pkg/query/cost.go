@@ -0,0 +1,148 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+)
+
+// RelationStatsProvider supplies estimated relationship counts per
+// namespace and relation, typically backed by the datastore's statistics.
+// The second return value reports whether an estimate was available.
+type RelationStatsProvider interface {
+	EstimatedRelationshipCount(definitionName, relationName string) (uint64, bool)
+}
+
+// Heuristic fallback costs used when no statistics are available for a node,
+// encoding the ordering "direct relation < arrow < union-of-many".
+const (
+	heuristicDirectCost    = 100.0
+	heuristicArrowCost     = 1000.0
+	heuristicRecursiveCost = 10000.0
+)
+
+// EstimateCardinality estimates the number of paths a subtree may produce,
+// consulting the stats provider for leaf relations and falling back to
+// heuristics elsewhere. The provider may be nil.
+func EstimateCardinality(it Iterator, stats RelationStatsProvider) float64 {
+	switch typed := it.(type) {
+	case *RelationIterator:
+		if stats != nil {
+			if count, ok := stats.EstimatedRelationshipCount(typed.base.DefinitionName(), typed.base.RelationName()); ok {
+				return float64(count)
+			}
+		}
+		return heuristicDirectCost
+
+	case *FixedIterator:
+		return float64(len(typed.paths))
+
+	case *Alias:
+		return EstimateCardinality(typed.subIt, stats)
+
+	case *Union:
+		total := 0.0
+		for _, sub := range typed.subIts {
+			total += EstimateCardinality(sub, stats)
+		}
+		return total
+
+	case *Intersection:
+		// An intersection produces at most its smallest operand.
+		smallest := 0.0
+		for idx, sub := range typed.subIts {
+			estimate := EstimateCardinality(sub, stats)
+			if idx == 0 || estimate < smallest {
+				smallest = estimate
+			}
+		}
+		return smallest
+
+	case *Exclusion:
+		// An exclusion produces at most its left operand.
+		return EstimateCardinality(typed.mainSet, stats)
+
+	case *Arrow:
+		left := EstimateCardinality(typed.left, stats)
+		right := EstimateCardinality(typed.right, stats)
+		return max(heuristicArrowCost, left+right)
+
+	case *IntersectionArrow:
+		left := EstimateCardinality(typed.left, stats)
+		right := EstimateCardinality(typed.right, stats)
+		return max(heuristicArrowCost, left+right)
+
+	case *RecursiveIterator:
+		return heuristicRecursiveCost
+
+	default:
+		subs := it.Subiterators()
+		if len(subs) == 0 {
+			return heuristicDirectCost
+		}
+		total := 0.0
+		for _, sub := range subs {
+			total += EstimateCardinality(sub, stats)
+		}
+		return total
+	}
+}
+
+// ReorderForCost rewrites an iterator tree so intersections evaluate their
+// cheapest operand first, which lets the short-circuiting intersection logic
+// narrow the resource set before touching expensive branches. Exclusions keep
+// their left operand fixed, but record whether the right side is cheap enough
+// to materialize up front versus probing per-path.
+func ReorderForCost(root Iterator, stats RelationStatsProvider) (Iterator, error) {
+	return Walk(root, func(it Iterator) (Iterator, error) {
+		switch typed := it.(type) {
+		case *Intersection:
+			estimates := make(map[Iterator]float64, len(typed.subIts))
+			for _, sub := range typed.subIts {
+				estimates[sub] = EstimateCardinality(sub, stats)
+			}
+			reordered := slices.Clone(typed.subIts)
+			slices.SortStableFunc(reordered, func(a, b Iterator) int {
+				switch {
+				case estimates[a] < estimates[b]:
+					return -1
+				case estimates[a] > estimates[b]:
+					return 1
+				default:
+					return 0
+				}
+			})
+			return &Intersection{subIts: reordered, costOrdered: true}, nil
+
+		case *Exclusion:
+			rightEstimate := EstimateCardinality(typed.excluded, stats)
+			leftEstimate := EstimateCardinality(typed.mainSet, stats)
+			return &Exclusion{
+				mainSet:          typed.mainSet,
+				excluded:         typed.excluded,
+				materializeRight: rightEstimate <= leftEstimate,
+			}, nil
+
+		default:
+			return it, nil
+		}
+	})
+}
+
+// explainCostSuffix renders the planner's cost decisions for Explain output.
+func explainCostSuffix(decisions ...string) string {
+	out := ""
+	for _, decision := range decisions {
+		if decision == "" {
+			continue
+		}
+		if out == "" {
+			out = fmt.Sprintf(" [%s", decision)
+		} else {
+			out += ", " + decision
+		}
+	}
+	if out != "" {
+		out += "]"
+	}
+	return out
+}
@@ -0,0 +1,190 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStatsProvider is a RelationStatsProvider backed by a static map keyed by
+// "definition#relation".
+type fakeStatsProvider struct {
+	counts map[string]uint64
+}
+
+func (f fakeStatsProvider) EstimatedRelationshipCount(definitionName, relationName string) (uint64, bool) {
+	count, ok := f.counts[definitionName+"#"+relationName]
+	return count, ok
+}
+
+func TestEstimateCardinality(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	t.Run("LeafUsesStats", func(t *testing.T) {
+		t.Parallel()
+
+		stats := fakeStatsProvider{counts: map[string]uint64{"document#viewer": 42}}
+		leaf := NewRelationIterator(createTestBaseRelation("document", "viewer", "user", ""))
+		require.Equal(42.0, EstimateCardinality(leaf, stats))
+	})
+
+	t.Run("LeafFallsBackToHeuristic", func(t *testing.T) {
+		t.Parallel()
+
+		leaf := NewRelationIterator(createTestBaseRelation("document", "viewer", "user", ""))
+		require.Equal(heuristicDirectCost, EstimateCardinality(leaf, nil))
+	})
+
+	t.Run("HeuristicOrdering", func(t *testing.T) {
+		t.Parallel()
+
+		direct := NewRelationIterator(createTestBaseRelation("document", "viewer", "user", ""))
+		arrow := NewArrow(direct.Clone(), direct.Clone())
+		union := NewUnion()
+		for range 20 {
+			union.addSubIterator(arrow.Clone())
+		}
+
+		directCost := EstimateCardinality(direct, nil)
+		arrowCost := EstimateCardinality(arrow, nil)
+		unionCost := EstimateCardinality(union, nil)
+		require.Less(directCost, arrowCost, "direct relations are assumed cheaper than arrows")
+		require.Less(arrowCost, unionCost, "arrows are assumed cheaper than wide unions")
+	})
+
+	t.Run("IntersectionIsBoundedBySmallest", func(t *testing.T) {
+		t.Parallel()
+
+		stats := fakeStatsProvider{counts: map[string]uint64{
+			"document#viewer": 1000,
+			"document#banned": 3,
+		}}
+		intersection := NewIntersection()
+		intersection.addSubIterator(NewRelationIterator(createTestBaseRelation("document", "viewer", "user", "")))
+		intersection.addSubIterator(NewRelationIterator(createTestBaseRelation("document", "banned", "user", "")))
+
+		require.Equal(3.0, EstimateCardinality(intersection, stats))
+	})
+}
+
+func TestReorderForCost(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	t.Run("IntersectionReordersCheapestFirst", func(t *testing.T) {
+		t.Parallel()
+
+		huge := NewRelationIterator(createTestBaseRelation("organization", "member", "user", ""))
+		tiny := NewRelationIterator(createTestBaseRelation("document", "banned", "user", ""))
+
+		intersection := NewIntersection()
+		intersection.addSubIterator(huge)
+		intersection.addSubIterator(tiny)
+
+		stats := fakeStatsProvider{counts: map[string]uint64{
+			"organization#member": 1_000_000,
+			"document#banned":     5,
+		}}
+
+		reordered, err := ReorderForCost(intersection, stats)
+		require.NoError(err)
+
+		subs := reordered.Subiterators()
+		require.Len(subs, 2)
+		require.Same(tiny, subs[0], "cheapest operand must be evaluated first")
+		require.Same(huge, subs[1])
+		require.Contains(reordered.Explain().Info, "cost-ordered")
+	})
+
+	t.Run("PlanShapeFollowsEstimates", func(t *testing.T) {
+		t.Parallel()
+
+		left := NewRelationIterator(createTestBaseRelation("document", "viewer", "user", ""))
+		right := NewRelationIterator(createTestBaseRelation("document", "editor", "user", ""))
+
+		buildIntersection := func() *Intersection {
+			intersection := NewIntersection()
+			intersection.addSubIterator(left)
+			intersection.addSubIterator(right)
+			return intersection
+		}
+
+		// With viewer cheap, it stays first; with editor cheap, they swap.
+		viewerCheap := fakeStatsProvider{counts: map[string]uint64{"document#viewer": 1, "document#editor": 100}}
+		editorCheap := fakeStatsProvider{counts: map[string]uint64{"document#viewer": 100, "document#editor": 1}}
+
+		planA, err := ReorderForCost(buildIntersection(), viewerCheap)
+		require.NoError(err)
+		require.Same(left, planA.Subiterators()[0])
+
+		planB, err := ReorderForCost(buildIntersection(), editorCheap)
+		require.NoError(err)
+		require.Same(right, planB.Subiterators()[0])
+	})
+
+	t.Run("ExclusionKeepsLeftFixed", func(t *testing.T) {
+		t.Parallel()
+
+		mainSet := NewRelationIterator(createTestBaseRelation("document", "viewer", "user", ""))
+		excluded := NewRelationIterator(createTestBaseRelation("document", "banned", "user", ""))
+		exclusion := NewExclusion(mainSet, excluded)
+
+		stats := fakeStatsProvider{counts: map[string]uint64{
+			"document#viewer": 1000,
+			"document#banned": 5,
+		}}
+
+		reordered, err := ReorderForCost(exclusion, stats)
+		require.NoError(err)
+
+		subs := reordered.Subiterators()
+		require.Same(mainSet, subs[0], "exclusion must keep its left operand first")
+		require.Same(excluded, subs[1])
+		require.Contains(reordered.Explain().Info, "materialize-right",
+			"a cheap right side should be materialized")
+	})
+
+	t.Run("ExclusionProbesExpensiveRightSide", func(t *testing.T) {
+		t.Parallel()
+
+		mainSet := NewRelationIterator(createTestBaseRelation("document", "viewer", "user", ""))
+		excluded := NewRelationIterator(createTestBaseRelation("organization", "member", "user", ""))
+		exclusion := NewExclusion(mainSet, excluded)
+
+		stats := fakeStatsProvider{counts: map[string]uint64{
+			"document#viewer":     10,
+			"organization#member": 1_000_000,
+		}}
+
+		reordered, err := ReorderForCost(exclusion, stats)
+		require.NoError(err)
+		require.NotContains(reordered.Explain().Info, "materialize-right")
+	})
+
+	t.Run("NestedIntersectionsAreReordered", func(t *testing.T) {
+		t.Parallel()
+
+		hugeInner := NewRelationIterator(createTestBaseRelation("organization", "member", "user", ""))
+		tinyInner := NewRelationIterator(createTestBaseRelation("document", "banned", "user", ""))
+		inner := NewIntersection()
+		inner.addSubIterator(hugeInner)
+		inner.addSubIterator(tinyInner)
+
+		union := NewUnion()
+		union.addSubIterator(inner)
+
+		stats := fakeStatsProvider{counts: map[string]uint64{
+			"organization#member": 1_000_000,
+			"document#banned":     5,
+		}}
+
+		reordered, err := ReorderForCost(union, stats)
+		require.NoError(err)
+
+		innerSubs := reordered.Subiterators()[0].Subiterators()
+		require.Same(tinyInner, innerSubs[0])
+	})
+}
@@ -39,6 +39,15 @@ func convertRelationSeqToPathSeq(relSeq iter.Seq2[tuple.Relationship, error]) Pa
 // The RelationIterator, being the leaf, generates this set by calling the datastore.
 type RelationIterator struct {
 	base *schema.BaseRelation
+
+	// hint, when non-zero, is translated into datastore query options (limit,
+	// sort order, resource ID filtering) by each query this leaf issues.
+	hint Hint
+
+	// stripCaveats is set by the planner when this leaf's caveat evaluated
+	// statically true against the request's caveat context, so emitted paths
+	// drop it rather than re-evaluating per result.
+	stripCaveats bool
 }
 
 var _ Iterator = &RelationIterator{}
@@ -49,6 +58,63 @@ func NewRelationIterator(base *schema.BaseRelation) *RelationIterator {
 	}
 }
 
+// WithHint returns a copy of the iterator that pushes the given hint into its
+// datastore queries.
+func (r *RelationIterator) WithHint(hint Hint) *RelationIterator {
+	cloned := *r
+	cloned.hint = hint
+	return &cloned
+}
+
+// withStaticallyTrueCaveat returns a copy of the iterator whose emitted paths
+// omit the leaf's caveat, for use when the planner has already proven the
+// caveat true under the request's context.
+func (r *RelationIterator) withStaticallyTrueCaveat() *RelationIterator {
+	cloned := *r
+	cloned.stripCaveats = true
+	return &cloned
+}
+
+// maybeStripCaveats clears the caveat from each emitted path when the leaf's
+// caveat has been statically proven true.
+func (r *RelationIterator) maybeStripCaveats(seq PathSeq) PathSeq {
+	if !r.stripCaveats {
+		return seq
+	}
+	return func(yield func(Path, error) bool) {
+		for p, err := range seq {
+			if err == nil {
+				p.Caveat = nil
+			}
+			if !yield(p, err) {
+				return
+			}
+		}
+	}
+}
+
+// hintedResourceIDs filters the requested resource IDs through the hint's
+// prefix and cursor, so excluded resources are never sent to the datastore.
+func (r *RelationIterator) hintedResourceIDs(resources []Object) []string {
+	resourceIDs := make([]string, 0, len(resources))
+	for _, res := range resources {
+		if r.hint.matchesResourceID(res.ObjectID) {
+			resourceIDs = append(resourceIDs, res.ObjectID)
+		}
+	}
+	return resourceIDs
+}
+
+// hintQueryOptions appends limit and sort options when a limit hint is
+// present; limited queries are sorted by resource so the cut is well-defined.
+func (r *RelationIterator) hintQueryOptions(opts []options.QueryOptionsOption) []options.QueryOptionsOption {
+	if r.hint.Limit > 0 {
+		limit := r.hint.Limit
+		opts = append(opts, options.WithLimit(&limit), options.WithSort(options.ByResource))
+	}
+	return opts
+}
+
 func (r *RelationIterator) buildSubjectRelationFilter() datastore.SubjectRelationFilter {
 	if r.base.Subrelation() == tuple.Ellipsis {
 		return datastore.SubjectRelationFilter{}.WithEllipsisRelation()
@@ -73,16 +139,23 @@ func (r *RelationIterator) CheckImpl(ctx *Context, resources []Object, subject O
 		return EmptyPathSeq(), nil
 	}
 
+	var seq PathSeq
+	var err error
 	if r.base.Wildcard() {
-		return r.checkWildcardImpl(ctx, resources, subject)
+		seq, err = r.checkWildcardImpl(ctx, resources, subject)
+	} else {
+		seq, err = r.checkNormalImpl(ctx, resources, subject)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return r.checkNormalImpl(ctx, resources, subject)
+	return r.maybeStripCaveats(seq), nil
 }
 
 func (r *RelationIterator) checkNormalImpl(ctx *Context, resources []Object, subject ObjectAndRelation) (PathSeq, error) {
-	resourceIDs := make([]string, len(resources))
-	for i, res := range resources {
-		resourceIDs[i] = res.ObjectID
+	resourceIDs := r.hintedResourceIDs(resources)
+	if len(resourceIDs) == 0 {
+		return EmptyPathSeq(), nil
 	}
 
 	filter := datastore.RelationshipsFilter{
@@ -100,11 +173,11 @@ func (r *RelationIterator) checkNormalImpl(ctx *Context, resources []Object, sub
 
 	ctx.TraceStep(r, "querying datastore for %s:%s with resources=%v", r.base.Type(), r.base.RelationName(), resourceIDs)
 
-	relIter, err := ctx.Reader.QueryRelationships(ctx, filter,
+	relIter, err := ctx.Reader.QueryRelationships(ctx, filter, r.hintQueryOptions([]options.QueryOptionsOption{
 		options.WithSkipCaveats(r.base.Caveat() == ""),
 		options.WithSkipExpiration(!r.base.Expiration()),
 		options.WithQueryShape(queryshape.CheckPermissionSelectDirectSubjects),
-	)
+	})...)
 	if err != nil {
 		return nil, err
 	}
@@ -114,9 +187,9 @@ func (r *RelationIterator) checkNormalImpl(ctx *Context, resources []Object, sub
 
 func (r *RelationIterator) checkWildcardImpl(ctx *Context, resources []Object, subject ObjectAndRelation) (PathSeq, error) {
 	// Query the datastore for wildcard relationships (subject ObjectID = "*")
-	resourceIDs := make([]string, len(resources))
-	for i, res := range resources {
-		resourceIDs[i] = res.ObjectID
+	resourceIDs := r.hintedResourceIDs(resources)
+	if len(resourceIDs) == 0 {
+		return EmptyPathSeq(), nil
 	}
 
 	filter := datastore.RelationshipsFilter{
@@ -132,11 +205,11 @@ func (r *RelationIterator) checkWildcardImpl(ctx *Context, resources []Object, s
 		},
 	}
 
-	relIter, err := ctx.Reader.QueryRelationships(ctx, filter,
+	relIter, err := ctx.Reader.QueryRelationships(ctx, filter, r.hintQueryOptions([]options.QueryOptionsOption{
 		options.WithSkipCaveats(r.base.Caveat() == ""),
 		options.WithSkipExpiration(!r.base.Expiration()),
 		options.WithQueryShape(queryshape.CheckPermissionSelectDirectSubjects),
-	)
+	})...)
 	if err != nil {
 		return nil, err
 	}
@@ -165,13 +238,24 @@ func (r *RelationIterator) checkWildcardImpl(ctx *Context, resources []Object, s
 }
 
 func (r *RelationIterator) IterSubjectsImpl(ctx *Context, resource Object) (PathSeq, error) {
+	var seq PathSeq
+	var err error
 	if r.base.Wildcard() {
-		return r.iterSubjectsWildcardImpl(ctx, resource)
+		seq, err = r.iterSubjectsWildcardImpl(ctx, resource)
+	} else {
+		seq, err = r.iterSubjectsNormalImpl(ctx, resource)
 	}
-	return r.iterSubjectsNormalImpl(ctx, resource)
+	if err != nil {
+		return nil, err
+	}
+	return r.maybeStripCaveats(seq), nil
 }
 
 func (r *RelationIterator) iterSubjectsNormalImpl(ctx *Context, resource Object) (PathSeq, error) {
+	if !r.hint.matchesResourceID(resource.ObjectID) {
+		return EmptyPathSeq(), nil
+	}
+
 	filter := datastore.RelationshipsFilter{
 		OptionalResourceType:     r.base.DefinitionName(),
 		OptionalResourceIds:      []string{resource.ObjectID},
@@ -184,11 +268,11 @@ func (r *RelationIterator) iterSubjectsNormalImpl(ctx *Context, resource Object)
 		},
 	}
 
-	relIter, err := ctx.Reader.QueryRelationships(ctx, filter,
+	relIter, err := ctx.Reader.QueryRelationships(ctx, filter, r.hintQueryOptions([]options.QueryOptionsOption{
 		options.WithSkipCaveats(r.base.Caveat() == ""),
 		options.WithSkipExpiration(!r.base.Expiration()),
 		options.WithQueryShape(queryshape.AllSubjectsForResources),
-	)
+	})...)
 	if err != nil {
 		return nil, err
 	}
@@ -197,6 +281,10 @@ func (r *RelationIterator) iterSubjectsNormalImpl(ctx *Context, resource Object)
 }
 
 func (r *RelationIterator) iterSubjectsWildcardImpl(ctx *Context, resource Object) (PathSeq, error) {
+	if !r.hint.matchesResourceID(resource.ObjectID) {
+		return EmptyPathSeq(), nil
+	}
+
 	filter := datastore.RelationshipsFilter{
 		OptionalResourceType:     r.base.DefinitionName(),
 		OptionalResourceIds:      []string{resource.ObjectID},
@@ -210,11 +298,11 @@ func (r *RelationIterator) iterSubjectsWildcardImpl(ctx *Context, resource Objec
 		},
 	}
 
-	relIter, err := ctx.Reader.QueryRelationships(ctx, filter,
+	relIter, err := ctx.Reader.QueryRelationships(ctx, filter, r.hintQueryOptions([]options.QueryOptionsOption{
 		options.WithSkipCaveats(r.base.Caveat() == ""),
 		options.WithSkipExpiration(!r.base.Expiration()),
 		options.WithQueryShape(queryshape.AllSubjectsForResources),
-	)
+	})...)
 	if err != nil {
 		return nil, err
 	}
@@ -222,14 +310,71 @@ func (r *RelationIterator) iterSubjectsWildcardImpl(ctx *Context, resource Objec
 	return convertRelationSeqToPathSeq(iter.Seq2[tuple.Relationship, error](relIter)), nil
 }
 
+// IterResourcesImpl issues a reverse query: the subject is fixed and all
+// matching resources of this base relation are streamed back.
 func (r *RelationIterator) IterResourcesImpl(ctx *Context, subject ObjectAndRelation) (PathSeq, error) {
-	return nil, spiceerrors.MustBugf("unimplemented")
+	if subject.ObjectType != r.base.Type() {
+		ctx.TraceStep(r, "subject type %s doesn't match base type %s, returning empty", subject.ObjectType, r.base.Type())
+		return EmptyPathSeq(), nil
+	}
+
+	// A wildcard base relation grants to every subject of its type, so the
+	// reverse query looks for the wildcard relationship and rebinds it to the
+	// concrete subject, mirroring checkWildcardImpl.
+	subjectID := subject.ObjectID
+	if r.base.Wildcard() {
+		subjectID = tuple.PublicWildcard
+	}
+
+	filter := datastore.SubjectsFilter{
+		SubjectType:        r.base.Type(),
+		OptionalSubjectIds: []string{subjectID},
+		RelationFilter:     r.buildSubjectRelationFilter(),
+	}
+
+	ctx.TraceStep(r, "reverse querying datastore for %s:%s with subject=%s:%s", r.base.DefinitionName(), r.base.RelationName(), subject.ObjectType, subjectID)
+
+	relIter, err := ctx.Reader.ReverseQueryRelationships(ctx, filter,
+		options.WithResRelation(&options.ResourceRelation{
+			Namespace: r.base.DefinitionName(),
+			Relation:  r.base.RelationName(),
+		}),
+		options.WithSkipCaveatsForReverse(r.base.Caveat() == ""),
+		options.WithSkipExpirationForReverse(!r.base.Expiration()),
+		options.WithQueryShapeForReverse(queryshape.MatchingResourcesForSubject),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	seq := func(yield func(Path, error) bool) {
+		for rel, err := range relIter {
+			if err != nil {
+				if !yield(Path{}, err) {
+					return
+				}
+				continue
+			}
+
+			if !r.hint.matchesResourceID(rel.Resource.ObjectID) {
+				continue
+			}
+
+			concreteRel := rel
+			if r.base.Wildcard() {
+				concreteRel.Subject = subject
+			}
+			if !yield(FromRelationship(concreteRel), nil) {
+				return
+			}
+		}
+	}
+	return r.maybeStripCaveats(seq), nil
 }
 
 func (r *RelationIterator) Clone() Iterator {
-	return &RelationIterator{
-		base: r.base,
-	}
+	cloned := *r
+	return &cloned
 }
 
 func (r *RelationIterator) Explain() Explain {
@@ -237,10 +382,14 @@ func (r *RelationIterator) Explain() Explain {
 	if r.base.Wildcard() {
 		relationName = "*"
 	}
+	info := fmt.Sprintf("Relation(%s:%s -> %s:%s, caveat: %v, expiration: %v)",
+		r.base.DefinitionName(), r.base.RelationName(), r.base.Type(), relationName,
+		r.base.Caveat() != "", r.base.Expiration())
+	if !r.hint.IsZero() {
+		info += " [pushdown: " + r.hint.explainInfo() + "]"
+	}
 	return Explain{
-		Info: fmt.Sprintf("Relation(%s:%s -> %s:%s, caveat: %v, expiration: %v)",
-			r.base.DefinitionName(), r.base.RelationName(), r.base.Type(), relationName,
-			r.base.Caveat() != "", r.base.Expiration()),
+		Info: info,
 	}
 }
 
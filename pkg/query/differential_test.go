@@ -0,0 +1,436 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/internal/caveats"
+	"github.com/authzed/spicedb/internal/datastore/dsfortesting"
+	"github.com/authzed/spicedb/internal/datastore/memdb"
+	"github.com/authzed/spicedb/internal/dispatch"
+	"github.com/authzed/spicedb/internal/dispatch/graph"
+	"github.com/authzed/spicedb/internal/graph/computed"
+	datastoremw "github.com/authzed/spicedb/internal/middleware/datastore"
+	"github.com/authzed/spicedb/internal/testfixtures"
+	caveattypes "github.com/authzed/spicedb/pkg/caveats/types"
+	"github.com/authzed/spicedb/pkg/datastore"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	v1 "github.com/authzed/spicedb/pkg/proto/dispatch/v1"
+	"github.com/authzed/spicedb/pkg/schema/v2"
+	"github.com/authzed/spicedb/pkg/tuple"
+	"github.com/authzed/spicedb/pkg/validationfile"
+)
+
+// differentialTripleCap bounds the number of (resource, relation, subject)
+// triples evaluated per fixture so the harness stays within CI time.
+const differentialTripleCap = 400
+
+// differentialFixture is one schema-plus-relationships corpus entry for the
+// differential harness.
+type differentialFixture struct {
+	name          string
+	schema        string
+	relationships []tuple.Relationship
+}
+
+// differentialHarness holds everything needed to answer one check question two
+// ways: through the query executor and through the existing graph dispatcher.
+type differentialHarness struct {
+	ds         datastore.Datastore
+	revision   datastore.Revision
+	dsSchema   *schema.Schema
+	dispatcher dispatch.Dispatcher
+	graphCtx   context.Context
+}
+
+// checkTriple identifies one (resource, relation, subject) question.
+type checkTriple struct {
+	definition string
+	relation   string
+	resourceID string
+	subject    tuple.ObjectAndRelation
+}
+
+func (c checkTriple) String() string {
+	return fmt.Sprintf("%s:%s#%s@%s", c.definition, c.resourceID, c.relation, tuple.StringONR(c.subject))
+}
+
+func buildDifferentialHarness(t *testing.T, fixture differentialFixture) *differentialHarness {
+	t.Helper()
+	req := require.New(t)
+
+	rawDS, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	req.NoError(err)
+
+	ds, revision := testfixtures.DatastoreFromSchemaAndTestRelationships(rawDS, fixture.schema, fixture.relationships, req)
+
+	reader := ds.SnapshotReader(revision)
+	defs, err := reader.ListAllNamespaces(t.Context())
+	req.NoError(err)
+	namespaces := make([]*core.NamespaceDefinition, 0, len(defs))
+	for _, def := range defs {
+		namespaces = append(namespaces, def.Definition)
+	}
+
+	revisionedCaveats, err := reader.ListAllCaveats(t.Context())
+	req.NoError(err)
+	caveatDefs := make([]*core.CaveatDefinition, 0, len(revisionedCaveats))
+	for _, revisioned := range revisionedCaveats {
+		caveatDefs = append(caveatDefs, revisioned.Definition)
+	}
+
+	dsSchema, err := schema.BuildSchemaFromDefinitions(namespaces, caveatDefs)
+	req.NoError(err)
+
+	dispatcher, err := graph.NewLocalOnlyDispatcher(graph.MustNewDefaultDispatcherParametersForTesting())
+	req.NoError(err)
+
+	graphCtx := datastoremw.ContextWithHandle(t.Context())
+	req.NoError(datastoremw.SetInContext(graphCtx, ds))
+
+	return &differentialHarness{
+		ds:         ds,
+		revision:   revision,
+		dsSchema:   dsSchema,
+		dispatcher: dispatcher,
+		graphCtx:   graphCtx,
+	}
+}
+
+// enumerateTriples builds the full cross product of definitions, their
+// relations and permissions, the resource IDs of that type present in the
+// fixture, and every concrete subject present in the fixture, capped at
+// differentialTripleCap. Everything is sorted so runs are deterministic.
+func enumerateTriples(h *differentialHarness, relationships []tuple.Relationship) []checkTriple {
+	resourceIDsByType := make(map[string][]string)
+	subjectKeys := make(map[string]tuple.ObjectAndRelation)
+	for _, rel := range relationships {
+		resourceType := rel.Resource.ObjectType
+		if !slices.Contains(resourceIDsByType[resourceType], rel.Resource.ObjectID) {
+			resourceIDsByType[resourceType] = append(resourceIDsByType[resourceType], rel.Resource.ObjectID)
+		}
+		if rel.Subject.ObjectID != tuple.PublicWildcard {
+			subjectKeys[tuple.StringONR(rel.Subject)] = rel.Subject
+		}
+	}
+
+	subjects := make([]tuple.ObjectAndRelation, 0, len(subjectKeys))
+	for _, key := range slices.Sorted(maps.Keys(subjectKeys)) {
+		subjects = append(subjects, subjectKeys[key])
+	}
+
+	var triples []checkTriple
+	for _, definitionName := range slices.Sorted(maps.Keys(h.dsSchema.Definitions())) {
+		def := h.dsSchema.Definitions()[definitionName]
+		relationNames := slices.Sorted(maps.Keys(def.Relations()))
+		relationNames = append(relationNames, slices.Sorted(maps.Keys(def.Permissions()))...)
+
+		for _, relationName := range relationNames {
+			for _, resourceID := range slices.Sorted(slices.Values(resourceIDsByType[definitionName])) {
+				for _, subject := range subjects {
+					if len(triples) >= differentialTripleCap {
+						return triples
+					}
+					triples = append(triples, checkTriple{
+						definition: definitionName,
+						relation:   relationName,
+						resourceID: resourceID,
+						subject:    subject,
+					})
+				}
+			}
+		}
+	}
+	return triples
+}
+
+// caveatExprNames collects the sorted, deduplicated caveat names referenced by
+// an expression; this is the normalized form both sides are compared in.
+func caveatExprNames(expr *core.CaveatExpression) []string {
+	names := make(map[string]struct{})
+	var collect func(*core.CaveatExpression)
+	collect = func(e *core.CaveatExpression) {
+		if e == nil {
+			return
+		}
+		if caveat := e.GetCaveat(); caveat != nil {
+			names[caveat.CaveatName] = struct{}{}
+			return
+		}
+		if op := e.GetOperation(); op != nil {
+			for _, child := range op.Children {
+				collect(child)
+			}
+		}
+	}
+	collect(expr)
+	return slices.Sorted(maps.Keys(names))
+}
+
+// executorAnswer runs the triple through the query executor and classifies the
+// result as a membership plus normalized caveat names.
+func (h *differentialHarness) executorAnswer(ctx context.Context, triple checkTriple) (v1.ResourceCheckResult_Membership, []string, error) {
+	plan, err := BuildIteratorFromSchema(h.dsSchema, triple.definition, triple.relation)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	queryCtx := &Context{
+		Context:      ctx,
+		Executor:     LocalExecutor{},
+		Reader:       h.ds.SnapshotReader(h.revision),
+		CaveatRunner: caveats.NewCaveatRunner(caveattypes.Default.TypeSet),
+	}
+
+	pathSeq, err := queryCtx.Check(plan, NewObjects(triple.definition, triple.resourceID), triple.subject)
+	if err != nil {
+		return 0, nil, err
+	}
+	paths, err := CollectAll(pathSeq)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if len(paths) == 0 {
+		return v1.ResourceCheckResult_NOT_MEMBER, nil, nil
+	}
+
+	caveatNames := make(map[string]struct{})
+	for _, path := range paths {
+		if path.Caveat == nil {
+			return v1.ResourceCheckResult_MEMBER, nil, nil
+		}
+		for _, name := range caveatExprNames(path.Caveat) {
+			caveatNames[name] = struct{}{}
+		}
+	}
+	return v1.ResourceCheckResult_CAVEATED_MEMBER, slices.Sorted(maps.Keys(caveatNames)), nil
+}
+
+// graphAnswer runs the triple through the existing dispatcher.
+func (h *differentialHarness) graphAnswer(triple checkTriple) (v1.ResourceCheckResult_Membership, []string, error) {
+	result, _, err := computed.ComputeCheck(h.graphCtx, h.dispatcher,
+		caveattypes.Default.TypeSet,
+		computed.CheckParameters{
+			ResourceType: tuple.RR(triple.definition, triple.relation),
+			Subject:      triple.subject,
+			AtRevision:   h.revision,
+			MaximumDepth: 50,
+		},
+		triple.resourceID,
+		100,
+	)
+	if err != nil {
+		return 0, nil, err
+	}
+	return result.Membership, caveatExprNames(result.Expression), nil
+}
+
+// compareTriple answers the triple both ways and returns a description of the
+// disagreement, or the empty string when both sides agree.
+func (h *differentialHarness) compareTriple(ctx context.Context, triple checkTriple) (string, error) {
+	graphMembership, graphCaveats, err := h.graphAnswer(triple)
+	if err != nil {
+		return "", fmt.Errorf("graph check of %s: %w", triple, err)
+	}
+
+	executorMembership, executorCaveats, err := h.executorAnswer(ctx, triple)
+	if err != nil {
+		return "", fmt.Errorf("executor check of %s: %w", triple, err)
+	}
+
+	if graphMembership != executorMembership {
+		return fmt.Sprintf("membership: graph says %s, executor says %s",
+			v1.ResourceCheckResult_Membership_name[int32(graphMembership)],
+			v1.ResourceCheckResult_Membership_name[int32(executorMembership)]), nil
+	}
+	if graphMembership == v1.ResourceCheckResult_CAVEATED_MEMBER && !slices.Equal(graphCaveats, executorCaveats) {
+		return fmt.Sprintf("caveats: graph references %v, executor references %v", graphCaveats, executorCaveats), nil
+	}
+	return "", nil
+}
+
+// shrinkCounterexample greedily removes relationships that are not needed to
+// reproduce the disagreement on the given triple, rebuilding the datastore for
+// each candidate subset, and returns the minimal set found.
+func shrinkCounterexample(t *testing.T, fixture differentialFixture, triple checkTriple) []tuple.Relationship {
+	t.Helper()
+
+	minimal := slices.Clone(fixture.relationships)
+	for i := 0; i < len(minimal); {
+		candidate := slices.Concat(minimal[:i], minimal[i+1:])
+		candidateFixture := differentialFixture{name: fixture.name, schema: fixture.schema, relationships: candidate}
+		h := buildDifferentialHarness(t, candidateFixture)
+		mismatch, err := h.compareTriple(t.Context(), triple)
+		if err == nil && mismatch == "" {
+			// This relationship is needed to reproduce the disagreement.
+			i++
+			continue
+		}
+		minimal = candidate
+	}
+	return minimal
+}
+
+// runDifferentialFixture asserts that the executor and the graph dispatcher
+// agree on every enumerated triple of the fixture, shrinking the first
+// disagreement to a minimal counterexample before failing.
+func runDifferentialFixture(t *testing.T, fixture differentialFixture) {
+	t.Helper()
+
+	h := buildDifferentialHarness(t, fixture)
+	triples := enumerateTriples(h, fixture.relationships)
+	require.NotEmpty(t, triples, "fixture %s enumerated no triples", fixture.name)
+
+	for _, triple := range triples {
+		mismatch, err := h.compareTriple(t.Context(), triple)
+		require.NoError(t, err)
+		if mismatch == "" {
+			continue
+		}
+
+		minimal := shrinkCounterexample(t, fixture, triple)
+		rendered := make([]string, 0, len(minimal))
+		for _, rel := range minimal {
+			rendered = append(rendered, tuple.MustString(rel))
+		}
+		t.Fatalf("executor disagrees with graph on %s (%s)\nminimal relationships:\n%s",
+			triple, mismatch, strings.Join(rendered, "\n"))
+	}
+}
+
+func mustParseAll(relationships ...string) []tuple.Relationship {
+	parsed := make([]tuple.Relationship, 0, len(relationships))
+	for _, rel := range relationships {
+		parsed = append(parsed, tuple.MustParse(rel))
+	}
+	return parsed
+}
+
+// differentialCorpus is the reduced corpus run in CI. Each fixture targets one
+// family of plan shapes; larger corpora can reuse runDifferentialFixture.
+var differentialCorpus = []differentialFixture{
+	{
+		name: "BasicRBAC",
+		schema: `
+			definition user {}
+
+			definition document {
+				relation owner: user
+				relation editor: user
+				relation viewer: user
+				permission edit = editor + owner
+				permission view = viewer + edit
+			}
+		`,
+		relationships: mustParseAll(
+			"document:doc1#owner@user:alice",
+			"document:doc1#viewer@user:bob",
+			"document:doc2#editor@user:bob",
+			"document:doc2#viewer@user:carol",
+		),
+	},
+	{
+		name: "FolderArrow",
+		schema: `
+			definition user {}
+
+			definition folder {
+				relation owner: user
+				permission view = owner
+			}
+
+			definition document {
+				relation parent: folder
+				relation viewer: user
+				permission view = viewer + parent->view
+			}
+		`,
+		relationships: mustParseAll(
+			"folder:root#owner@user:alice",
+			"document:spec#parent@folder:root",
+			"document:spec#viewer@user:bob",
+			"document:plain#viewer@user:carol",
+		),
+	},
+	{
+		name: "IntersectionAndExclusion",
+		schema: `
+			definition user {}
+
+			definition document {
+				relation editor: user
+				relation viewer: user
+				relation banned: user
+				permission comment = viewer & editor
+				permission view = viewer - banned
+			}
+		`,
+		relationships: mustParseAll(
+			"document:doc1#viewer@user:alice",
+			"document:doc1#editor@user:alice",
+			"document:doc1#viewer@user:bob",
+			"document:doc1#banned@user:bob",
+			"document:doc1#editor@user:carol",
+		),
+	},
+	{
+		name: "Caveated",
+		schema: `
+			caveat only_on_tuesday(day string) {
+				day == "tuesday"
+			}
+
+			definition user {}
+
+			definition document {
+				relation viewer: user with only_on_tuesday
+				relation editor: user
+				permission view = viewer + editor
+			}
+		`,
+		relationships: mustParseAll(
+			"document:doc1#viewer@user:alice[only_on_tuesday]",
+			"document:doc1#editor@user:bob",
+			"document:doc2#viewer@user:carol[only_on_tuesday]",
+		),
+	},
+}
+
+func TestDifferentialAgainstGraph(t *testing.T) {
+	t.Parallel()
+
+	for _, fixture := range differentialCorpus {
+		t.Run(fixture.name, func(t *testing.T) {
+			t.Parallel()
+			runDifferentialFixture(t, fixture)
+		})
+	}
+}
+
+// TestDifferentialFromConsistencyFixture seeds the harness from one of the
+// existing consistency test YAML fixtures, exercising the same loading path
+// larger corpora would use.
+func TestDifferentialFromConsistencyFixture(t *testing.T) {
+	t.Parallel()
+	req := require.New(t)
+
+	loaderDS, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	req.NoError(err)
+
+	populated, _, err := validationfile.PopulateFromFiles(t.Context(), loaderDS, caveattypes.Default.TypeSet,
+		[]string{"../../internal/services/integrationtesting/testconfigs/3letterrbac.yaml"})
+	req.NoError(err)
+
+	runDifferentialFixture(t, differentialFixture{
+		name:          "3letterrbac",
+		schema:        populated.Schema,
+		relationships: populated.Relationships,
+	})
+}
@@ -10,6 +10,10 @@ import (
 type Exclusion struct {
 	mainSet  Iterator
 	excluded Iterator
+
+	// materializeRight records the planner's estimate-driven choice to
+	// materialize the right side up front rather than probing it per-path.
+	materializeRight bool
 }
 
 var _ Iterator = &Exclusion{}
@@ -138,20 +142,73 @@ func (e *Exclusion) IterSubjectsImpl(ctx *Context, resource Object) (PathSeq, er
 }
 
 func (e *Exclusion) IterResourcesImpl(ctx *Context, subject ObjectAndRelation) (PathSeq, error) {
-	return nil, spiceerrors.MustBugf("unimplemented")
+	// Reverse mode: enumerate the main set's resources for the fixed subject,
+	// then remove (or caveat) those the excluded set also produces.
+	mainSeq, err := ctx.IterResources(e.mainSet, subject)
+	if err != nil {
+		return nil, err
+	}
+	mainPaths, err := CollectAll(mainSeq)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.TraceStep(e, "reverse main set returned %d paths", len(mainPaths))
+	if len(mainPaths) == 0 {
+		return EmptyPathSeq(), nil
+	}
+
+	excludedSeq, err := ctx.IterResources(e.excluded, subject)
+	if err != nil {
+		return nil, err
+	}
+	excludedPaths, err := CollectAll(excludedSeq)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.TraceStep(e, "reverse excluded set returned %d paths", len(excludedPaths))
+
+	excludedByResource := make(map[string]Path, len(excludedPaths))
+	for _, excludedPath := range excludedPaths {
+		excludedByResource[excludedPath.Resource.Key()] = excludedPath
+	}
+
+	var finalPaths []Path
+	for _, mainPath := range mainPaths {
+		resultPath := mainPath
+		shouldInclude := true
+
+		if excludedPath, ok := excludedByResource[mainPath.Resource.Key()]; ok {
+			resultPath, shouldInclude = combineExclusionCaveats(mainPath, excludedPath)
+		}
+
+		if shouldInclude {
+			finalPaths = append(finalPaths, resultPath)
+		}
+	}
+
+	return func(yield func(Path, error) bool) {
+		for _, path := range finalPaths {
+			if !yield(path, nil) {
+				return
+			}
+		}
+	}, nil
 }
 
 func (e *Exclusion) Clone() Iterator {
 	return &Exclusion{
-		mainSet:  e.mainSet.Clone(),
-		excluded: e.excluded.Clone(),
+		mainSet:          e.mainSet.Clone(),
+		excluded:         e.excluded.Clone(),
+		materializeRight: e.materializeRight,
 	}
 }
 
 func (e *Exclusion) Explain() Explain {
 	return Explain{
 		Name: "Exclusion",
-		Info: "Exclusion",
+		Info: "Exclusion" + explainCostSuffix(e.costDecision()),
 		SubExplain: []Explain{
 			e.mainSet.Explain(),
 			e.excluded.Explain(),
@@ -164,5 +221,12 @@ func (e *Exclusion) Subiterators() []Iterator {
 }
 
 func (e *Exclusion) ReplaceSubiterators(newSubs []Iterator) (Iterator, error) {
-	return &Exclusion{mainSet: newSubs[0], excluded: newSubs[1]}, nil
+	return &Exclusion{mainSet: newSubs[0], excluded: newSubs[1], materializeRight: e.materializeRight}, nil
+}
+
+func (e *Exclusion) costDecision() string {
+	if e.materializeRight {
+		return "materialize-right"
+	}
+	return ""
 }
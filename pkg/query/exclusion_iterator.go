@@ -0,0 +1,124 @@
+package query
+
+import (
+	"cmp"
+	"slices"
+)
+
+// ExcludedSubjectsMetadataKey is the Path metadata key under which an
+// ExclusionIterator records the concrete subjects carved out of a surviving
+// wildcard path, as a []ObjectAndRelation. Later expansion of the wildcard
+// must honor these exclusions.
+const ExcludedSubjectsMetadataKey = "excluded_subjects"
+
+// ExclusionIterator yields the paths of its left child minus those of its
+// right child: a left path passes through untouched unless the right child
+// produced a path with a matching endpoint. When the matching right path is
+// caveated the result carries the subtracted caveat; when it is unconditional
+// the left path is dropped entirely.
+//
+// A wildcard subject on the right excludes every matching concrete subject on
+// the left (caveat-aware). A wildcard on the left with concrete exclusions on
+// the right survives, recording the excluded subjects under
+// ExcludedSubjectsMetadataKey.
+//
+// The right child is fully buffered; the left child is streamed.
+type ExclusionIterator struct {
+	left  PathSeq
+	right PathSeq
+}
+
+// NewExclusionIterator creates an ExclusionIterator computing left minus right.
+func NewExclusionIterator(left, right PathSeq) *ExclusionIterator {
+	return &ExclusionIterator{left: left, right: right}
+}
+
+// IterPaths returns the subtracted sequence of paths, in the order the left
+// child yielded them.
+func (e *ExclusionIterator) IterPaths() PathSeq {
+	return func(yield func(Path, error) bool) {
+		// Buffer the right side, keyed by endpoint, OR-merging duplicates.
+		exclusions := make(map[string]Path)
+		broken := false
+		e.right(func(p Path, err error) bool {
+			if err != nil {
+				yield(Path{}, err)
+				broken = true
+				return false
+			}
+			key := endpointKey(p)
+			if existing, ok := exclusions[key]; ok {
+				merged, merr := mergeUnionPaths(existing, p)
+				if merr != nil {
+					yield(Path{}, merr)
+					broken = true
+					return false
+				}
+				exclusions[key] = merged
+				return true
+			}
+			exclusions[key] = p
+			return true
+		})
+		if broken {
+			return
+		}
+
+		e.left(func(p Path, err error) bool {
+			if err != nil {
+				yield(Path{}, err)
+				return false
+			}
+
+			result := p
+			excludedConcrete := make([]ObjectAndRelation, 0)
+			for _, exclusion := range exclusions {
+				if !exclusion.Resource.Equals(p.Resource) ||
+					exclusion.Subject.ObjectType != p.Subject.ObjectType {
+					continue
+				}
+
+				switch {
+				case exclusion.Subject.ObjectID == p.Subject.ObjectID &&
+					exclusion.Subject.Relation == p.Subject.Relation:
+					// Exact endpoint match.
+				case isWildcardPath(exclusion):
+					// A wildcard on the right excludes this concrete subject.
+					exclusion.Subject = p.Subject
+				case isWildcardPath(p):
+					// A concrete exclusion against a wildcard left path is
+					// recorded rather than applied.
+					excludedConcrete = append(excludedConcrete, exclusion.Subject)
+					continue
+				default:
+					continue
+				}
+
+				if exclusion.Caveat == nil {
+					// An unconditional exclusion removes the path entirely.
+					return true
+				}
+
+				merged, merr := result.MergeAndNot(exclusion)
+				if merr != nil {
+					yield(Path{}, merr)
+					return false
+				}
+				merged.Relation = result.Relation
+				result = merged
+			}
+
+			if len(excludedConcrete) > 0 {
+				slices.SortFunc(excludedConcrete, func(a, b ObjectAndRelation) int {
+					return cmp.Compare(ObjectAndRelationKey(a), ObjectAndRelationKey(b))
+				})
+				if result.Metadata == nil {
+					result.Metadata = make(map[string]any)
+				}
+				result.Metadata[ExcludedSubjectsMetadataKey] = excludedConcrete
+			}
+
+			return yield(result, nil)
+		})
+	}
+}
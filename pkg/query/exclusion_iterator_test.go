@@ -0,0 +1,221 @@
+package query
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+// TestExclusionIteratorMatrix covers the exclusion semantics matrix used by
+// the graph package: every combination of caveated/unconditional left paths
+// against caveated/unconditional/absent right paths.
+func TestExclusionIteratorMatrix(t *testing.T) {
+	t.Parallel()
+
+	plain := func() Path { return MustPathFromString("document:doc1#viewer@user:alice") }
+	caveated := func(name string) Path { return caveatedPath("document:doc1#viewer@user:alice", name) }
+
+	tcs := []struct {
+		name           string
+		left           Path
+		right          *Path
+		expectDropped  bool
+		expectCaveated bool
+	}{
+		{
+			name:          "unconditional left, unconditional right drops",
+			left:          plain(),
+			right:         func() *Path { p := plain(); return &p }(),
+			expectDropped: true,
+		},
+		{
+			name:           "unconditional left, caveated right survives negated",
+			left:           plain(),
+			right:          func() *Path { p := caveated("banned"); return &p }(),
+			expectCaveated: true,
+		},
+		{
+			name:          "caveated left, unconditional right drops",
+			left:          caveated("allowed"),
+			right:         func() *Path { p := plain(); return &p }(),
+			expectDropped: true,
+		},
+		{
+			name:           "caveated left, caveated right survives subtracted",
+			left:           caveated("allowed"),
+			right:          func() *Path { p := caveated("banned"); return &p }(),
+			expectCaveated: true,
+		},
+		{
+			name:  "unconditional left, absent right passes through",
+			left:  plain(),
+			right: nil,
+		},
+		{
+			name:           "caveated left, absent right passes through",
+			left:           caveated("allowed"),
+			right:          nil,
+			expectCaveated: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require := require.New(t)
+
+			right := EmptyPathSeq()
+			if tc.right != nil {
+				right = pathSeqOf(*tc.right)
+			}
+
+			paths, err := CollectAll(NewExclusionIterator(pathSeqOf(tc.left), right).IterPaths())
+			require.NoError(err)
+
+			if tc.expectDropped {
+				require.Empty(paths)
+				return
+			}
+
+			require.Len(paths, 1)
+			if tc.expectCaveated {
+				require.NotNil(paths[0].Caveat)
+			} else {
+				require.Nil(paths[0].Caveat)
+			}
+		})
+	}
+}
+
+func TestExclusionIteratorCaveatSubtraction(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	left := pathSeqOf(caveatedPath("document:doc1#viewer@user:alice", "allowed"))
+	right := pathSeqOf(caveatedPath("document:doc1#viewer@user:alice", "banned"))
+
+	paths, err := CollectAll(NewExclusionIterator(left, right).IterPaths())
+	require.NoError(err)
+	require.Len(paths, 1)
+
+	// caveats.Subtract produces allowed AND NOT(banned).
+	caveat := paths[0].Caveat
+	require.NotNil(caveat)
+	require.NotNil(caveat.GetOperation())
+	require.Equal(core.CaveatOperation_AND, caveat.GetOperation().Op)
+
+	children := caveat.GetOperation().GetChildren()
+	require.Len(children, 2)
+	require.Equal("allowed", children[0].GetCaveat().CaveatName)
+	require.NotNil(children[1].GetOperation())
+	require.Equal(core.CaveatOperation_NOT, children[1].GetOperation().Op)
+}
+
+func TestExclusionIteratorWildcards(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	t.Run("WildcardRightExcludesAllConcrete", func(t *testing.T) {
+		t.Parallel()
+
+		left := pathSeqOf(
+			MustPathFromString("document:doc1#viewer@user:alice"),
+			MustPathFromString("document:doc1#viewer@user:bob"),
+		)
+		right := pathSeqOf(MustPathFromString("document:doc1#viewer@user:*"))
+
+		paths, err := CollectAll(NewExclusionIterator(left, right).IterPaths())
+		require.NoError(err)
+		require.Empty(paths, "an unconditional wildcard exclusion removes every concrete subject")
+	})
+
+	t.Run("CaveatedWildcardRightSubtractsFromConcrete", func(t *testing.T) {
+		t.Parallel()
+
+		left := pathSeqOf(MustPathFromString("document:doc1#viewer@user:alice"))
+		right := pathSeqOf(caveatedPath("document:doc1#viewer@user:*", "banned"))
+
+		paths, err := CollectAll(NewExclusionIterator(left, right).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1)
+		require.NotNil(paths[0].Caveat, "a caveated wildcard exclusion leaves a conditional result")
+	})
+
+	t.Run("WildcardLeftRecordsConcreteExclusions", func(t *testing.T) {
+		t.Parallel()
+
+		left := pathSeqOf(MustPathFromString("document:doc1#viewer@user:*"))
+		right := pathSeqOf(
+			MustPathFromString("document:doc1#viewer@user:alice"),
+			MustPathFromString("document:doc1#viewer@user:bob"),
+		)
+
+		paths, err := CollectAll(NewExclusionIterator(left, right).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1, "the wildcard path must survive with exclusions recorded")
+
+		excluded, ok := paths[0].Metadata[ExcludedSubjectsMetadataKey].([]ObjectAndRelation)
+		require.True(ok, "excluded subjects must be recorded in metadata")
+		require.Len(excluded, 2)
+		require.Equal("alice", excluded[0].ObjectID)
+		require.Equal("bob", excluded[1].ObjectID)
+	})
+
+	t.Run("WildcardRightExcludesWildcardLeft", func(t *testing.T) {
+		t.Parallel()
+
+		left := pathSeqOf(MustPathFromString("document:doc1#viewer@user:*"))
+		right := pathSeqOf(MustPathFromString("document:doc1#viewer@user:*"))
+
+		paths, err := CollectAll(NewExclusionIterator(left, right).IterPaths())
+		require.NoError(err)
+		require.Empty(paths)
+	})
+}
+
+func TestExclusionIteratorMismatchedEndpoints(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	left := pathSeqOf(
+		MustPathFromString("document:doc1#viewer@user:alice"),
+		MustPathFromString("document:doc2#viewer@user:alice"),
+	)
+	right := pathSeqOf(
+		MustPathFromString("document:doc2#viewer@user:alice"),
+		MustPathFromString("document:doc1#viewer@user:bob"),
+	)
+
+	paths, err := CollectAll(NewExclusionIterator(left, right).IterPaths())
+	require.NoError(err)
+	require.Len(paths, 1, "only the exact endpoint match is excluded")
+	require.Equal("doc1", paths[0].Resource.ObjectID)
+}
+
+func TestExclusionIteratorChildErrors(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	childErr := errors.New("child failed")
+
+	t.Run("LeftError", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := CollectAll(NewExclusionIterator(erroringPathSeq(childErr), EmptyPathSeq()).IterPaths())
+		require.ErrorIs(err, childErr)
+	})
+
+	t.Run("RightError", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := CollectAll(NewExclusionIterator(EmptyPathSeq(), erroringPathSeq(childErr)).IterPaths())
+		require.ErrorIs(err, childErr)
+	})
+}
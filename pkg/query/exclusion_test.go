@@ -287,11 +287,14 @@ func TestExclusionUnimplementedMethods(t *testing.T) {
 		}, "Should panic since method is unimplemented")
 	})
 
-	t.Run("IterResourcesImpl Unimplemented", func(t *testing.T) {
+	t.Run("IterResourcesImpl", func(t *testing.T) {
 		t.Parallel()
-		require.Panics(func() {
-			_, _ = ctx.IterResources(exclusion, NewObject("user", "alice").WithEllipses())
-		}, "Should panic since method is unimplemented")
+		pathSeq, err := ctx.IterResources(exclusion, NewObject("user", "alice").WithEllipses())
+		require.NoError(err)
+
+		rels, err := CollectAll(pathSeq)
+		require.NoError(err)
+		require.Len(rels, 1, "nothing is excluded, so the main set's resource survives")
 	})
 }
 
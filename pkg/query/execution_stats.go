@@ -0,0 +1,310 @@
+package query
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// DispatchCountMetadataKey is the reserved metadata key under which the
+// instrumented root stamps the number of datastore queries issued so far at
+// the moment each path was emitted, when enabled via WithDispatchCountMetadata.
+const DispatchCountMetadataKey = "dispatch_count"
+
+// ExecutionStats is a snapshot of the counters gathered during one or more
+// executions of an instrumented plan.
+type ExecutionStats struct {
+	// DatastoreQueries is the number of queries issued by relation leaves.
+	DatastoreQueries uint64
+
+	// RelationshipsLoaded is the number of relationships streamed out of those
+	// queries.
+	RelationshipsLoaded uint64
+
+	// PathsEmitted is the number of paths yielded by the plan's root.
+	PathsEmitted uint64
+
+	// CacheHits is the number of cache hits recorded by caching layers via
+	// RecordCacheHit.
+	CacheHits uint64
+
+	// PeakBufferedPaths is the largest number of paths produced by any single
+	// node's sequence — the high-water mark of intermediate result sets.
+	PeakBufferedPaths uint64
+
+	// WallTime is the cumulative time spent iterating the plan's root.
+	WallTime time.Duration
+}
+
+// executionCounters is the shared, atomically-updated backing store for an
+// instrumented plan, so counts aggregate correctly across parallel branches.
+type executionCounters struct {
+	datastoreQueries    atomic.Uint64
+	relationshipsLoaded atomic.Uint64
+	pathsEmitted        atomic.Uint64
+	cacheHits           atomic.Uint64
+	peakBufferedPaths   atomic.Uint64
+	wallNanos           atomic.Int64
+}
+
+// recordPeak raises the buffered-paths high-water mark to n if it exceeds the
+// current value.
+func (c *executionCounters) recordPeak(n uint64) {
+	for {
+		current := c.peakBufferedPaths.Load()
+		if n <= current || c.peakBufferedPaths.CompareAndSwap(current, n) {
+			return
+		}
+	}
+}
+
+// ExecutionStatsOption configures an instrumented plan.
+type ExecutionStatsOption func(*ExecutionStatsIterator)
+
+// WithoutStats disables all counting: the instrumented plan delegates straight
+// to the wrapped tree with no per-path overhead.
+func WithoutStats() ExecutionStatsOption {
+	return func(e *ExecutionStatsIterator) {
+		e.disabled = true
+	}
+}
+
+// WithDispatchCountMetadata stamps each path emitted by the root with the
+// number of datastore queries issued so far, under DispatchCountMetadataKey.
+func WithDispatchCountMetadata() ExecutionStatsOption {
+	return func(e *ExecutionStatsIterator) {
+		e.stampDispatchCount = true
+	}
+}
+
+// WithExecutionStats wraps the root of a plan so that executing it gathers
+// ExecutionStats, retrievable from the returned iterator via Stats once the
+// run completes. Counters are atomic, so a single instrumented plan may be
+// executed from multiple goroutines and the totals remain correct.
+func WithExecutionStats(root Iterator, opts ...ExecutionStatsOption) *ExecutionStatsIterator {
+	instrumented := &ExecutionStatsIterator{counters: &executionCounters{}}
+	for _, opt := range opts {
+		opt(instrumented)
+	}
+	if instrumented.disabled {
+		instrumented.wrapped = root
+		return instrumented
+	}
+	instrumented.wrapped = wrapTreeForExecutionStats(root, instrumented.counters)
+	return instrumented
+}
+
+// wrapTreeForExecutionStats deep-wraps the tree so every node feeds the shared
+// counters.
+func wrapTreeForExecutionStats(it Iterator, counters *executionCounters) Iterator {
+	subs := it.Subiterators()
+	if len(subs) > 0 {
+		newSubs := make([]Iterator, len(subs))
+		for i, sub := range subs {
+			newSubs[i] = wrapTreeForExecutionStats(sub, counters)
+		}
+		rebuilt, err := it.ReplaceSubiterators(newSubs)
+		if err == nil {
+			it = rebuilt
+		}
+	}
+	return &executionStatsNode{wrapped: it, counters: counters}
+}
+
+// ExecutionStatsIterator is the instrumented root of a plan. It implements
+// Iterator, delegating to the wrapped tree while gathering counters.
+type ExecutionStatsIterator struct {
+	wrapped            Iterator
+	counters           *executionCounters
+	disabled           bool
+	stampDispatchCount bool
+}
+
+var _ Iterator = &ExecutionStatsIterator{}
+
+// Stats returns a snapshot of the counters gathered so far.
+func (e *ExecutionStatsIterator) Stats() ExecutionStats {
+	return ExecutionStats{
+		DatastoreQueries:    e.counters.datastoreQueries.Load(),
+		RelationshipsLoaded: e.counters.relationshipsLoaded.Load(),
+		PathsEmitted:        e.counters.pathsEmitted.Load(),
+		CacheHits:           e.counters.cacheHits.Load(),
+		PeakBufferedPaths:   e.counters.peakBufferedPaths.Load(),
+		WallTime:            time.Duration(e.counters.wallNanos.Load()),
+	}
+}
+
+// RecordCacheHit is called by caching layers (e.g. a plan cache) to attribute
+// a hit to this execution.
+func (e *ExecutionStatsIterator) RecordCacheHit() {
+	if !e.disabled {
+		e.counters.cacheHits.Add(1)
+	}
+}
+
+// recordRootSeq counts the paths leaving the plan and the cumulative wall time
+// spent iterating it, optionally stamping the dispatch count on each path.
+func (e *ExecutionStatsIterator) recordRootSeq(seq PathSeq) PathSeq {
+	if e.disabled {
+		return seq
+	}
+	start := time.Now()
+	return func(yield func(Path, error) bool) {
+		defer func() {
+			e.counters.wallNanos.Add(int64(time.Since(start)))
+		}()
+		seq(func(p Path, err error) bool {
+			if err == nil {
+				e.counters.pathsEmitted.Add(1)
+				if e.stampDispatchCount {
+					if p.Metadata == nil {
+						p.Metadata = make(map[string]any)
+					}
+					p.Metadata[DispatchCountMetadataKey] = e.counters.datastoreQueries.Load()
+				}
+			}
+			return yield(p, err)
+		})
+	}
+}
+
+func (e *ExecutionStatsIterator) CheckImpl(ctx *Context, resources []Object, subject ObjectAndRelation) (PathSeq, error) {
+	seq, err := e.wrapped.CheckImpl(ctx, resources, subject)
+	if err != nil {
+		return nil, err
+	}
+	return e.recordRootSeq(seq), nil
+}
+
+func (e *ExecutionStatsIterator) IterSubjectsImpl(ctx *Context, resource Object) (PathSeq, error) {
+	seq, err := e.wrapped.IterSubjectsImpl(ctx, resource)
+	if err != nil {
+		return nil, err
+	}
+	return e.recordRootSeq(seq), nil
+}
+
+func (e *ExecutionStatsIterator) IterResourcesImpl(ctx *Context, subject ObjectAndRelation) (PathSeq, error) {
+	seq, err := e.wrapped.IterResourcesImpl(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	return e.recordRootSeq(seq), nil
+}
+
+func (e *ExecutionStatsIterator) Clone() Iterator {
+	return &ExecutionStatsIterator{
+		wrapped:            e.wrapped.Clone(),
+		counters:           e.counters,
+		disabled:           e.disabled,
+		stampDispatchCount: e.stampDispatchCount,
+	}
+}
+
+func (e *ExecutionStatsIterator) Explain() Explain {
+	return e.wrapped.Explain()
+}
+
+func (e *ExecutionStatsIterator) Subiterators() []Iterator {
+	return e.wrapped.Subiterators()
+}
+
+func (e *ExecutionStatsIterator) ReplaceSubiterators(newSubs []Iterator) (Iterator, error) {
+	rebuilt, err := e.wrapped.ReplaceSubiterators(newSubs)
+	if err != nil {
+		return nil, err
+	}
+	return &ExecutionStatsIterator{
+		wrapped:            rebuilt,
+		counters:           e.counters,
+		disabled:           e.disabled,
+		stampDispatchCount: e.stampDispatchCount,
+	}, nil
+}
+
+// executionStatsNode instruments one interior or leaf node, feeding the shared
+// counters of the instrumented plan it belongs to.
+type executionStatsNode struct {
+	wrapped  Iterator
+	counters *executionCounters
+}
+
+var _ Iterator = &executionStatsNode{}
+
+func (n *executionStatsNode) isRelationLeaf() bool {
+	_, ok := n.wrapped.(*RelationIterator)
+	return ok
+}
+
+// recordNodeSeq counts relationships loaded by relation leaves and tracks the
+// largest result set any single node produced.
+func (n *executionStatsNode) recordNodeSeq(seq PathSeq) PathSeq {
+	isLeaf := n.isRelationLeaf()
+	return func(yield func(Path, error) bool) {
+		var produced uint64
+		defer func() {
+			n.counters.recordPeak(produced)
+		}()
+		seq(func(p Path, err error) bool {
+			if err == nil {
+				produced++
+				if isLeaf {
+					n.counters.relationshipsLoaded.Add(1)
+				}
+			}
+			return yield(p, err)
+		})
+	}
+}
+
+func (n *executionStatsNode) recordQuery() {
+	if n.isRelationLeaf() {
+		n.counters.datastoreQueries.Add(1)
+	}
+}
+
+func (n *executionStatsNode) CheckImpl(ctx *Context, resources []Object, subject ObjectAndRelation) (PathSeq, error) {
+	n.recordQuery()
+	seq, err := n.wrapped.CheckImpl(ctx, resources, subject)
+	if err != nil {
+		return nil, err
+	}
+	return n.recordNodeSeq(seq), nil
+}
+
+func (n *executionStatsNode) IterSubjectsImpl(ctx *Context, resource Object) (PathSeq, error) {
+	n.recordQuery()
+	seq, err := n.wrapped.IterSubjectsImpl(ctx, resource)
+	if err != nil {
+		return nil, err
+	}
+	return n.recordNodeSeq(seq), nil
+}
+
+func (n *executionStatsNode) IterResourcesImpl(ctx *Context, subject ObjectAndRelation) (PathSeq, error) {
+	n.recordQuery()
+	seq, err := n.wrapped.IterResourcesImpl(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	return n.recordNodeSeq(seq), nil
+}
+
+func (n *executionStatsNode) Clone() Iterator {
+	return &executionStatsNode{wrapped: n.wrapped.Clone(), counters: n.counters}
+}
+
+func (n *executionStatsNode) Explain() Explain {
+	return n.wrapped.Explain()
+}
+
+func (n *executionStatsNode) Subiterators() []Iterator {
+	return n.wrapped.Subiterators()
+}
+
+func (n *executionStatsNode) ReplaceSubiterators(newSubs []Iterator) (Iterator, error) {
+	rebuilt, err := n.wrapped.ReplaceSubiterators(newSubs)
+	if err != nil {
+		return nil, err
+	}
+	return &executionStatsNode{wrapped: rebuilt, counters: n.counters}, nil
+}
@@ -0,0 +1,139 @@
+package query
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/internal/datastore/dsfortesting"
+	"github.com/authzed/spicedb/internal/datastore/memdb"
+	"github.com/authzed/spicedb/internal/testfixtures"
+	corev1 "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/schema/v2"
+)
+
+func buildStatsFixture(t *testing.T) (*Context, func() Iterator) {
+	t.Helper()
+
+	require := require.New(t)
+	rawDS, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ds, revision := testfixtures.StandardDatastoreWithData(rawDS, require)
+
+	objectDefs := []*corev1.NamespaceDefinition{testfixtures.UserNS.CloneVT(), testfixtures.FolderNS.CloneVT(), testfixtures.DocumentNS.CloneVT()}
+	dsSchema, err := schema.BuildSchemaFromDefinitions(objectDefs, nil)
+	require.NoError(err)
+
+	ctx := &Context{
+		Context:  t.Context(),
+		Executor: LocalExecutor{},
+		Reader:   ds.SnapshotReader(revision),
+	}
+
+	buildPlan := func() Iterator {
+		union := NewUnion()
+		union.addSubIterator(NewRelationIterator(dsSchema.Definitions()["document"].Relations()["viewer_and_editor"].BaseRelations()[0]))
+		union.addSubIterator(NewRelationIterator(dsSchema.Definitions()["document"].Relations()["editor"].BaseRelations()[0]))
+		return union
+	}
+	return ctx, buildPlan
+}
+
+func TestExecutionStats(t *testing.T) {
+	t.Parallel()
+
+	ctx, buildPlan := buildStatsFixture(t)
+
+	t.Run("CountersForFixedPlan", func(t *testing.T) {
+		t.Parallel()
+		require := require.New(t)
+
+		instrumented := WithExecutionStats(buildPlan())
+
+		pathSeq, err := ctx.Check(instrumented, NewObjects("document", "specialplan"), NewObject("user", "multiroleguy").WithEllipses())
+		require.NoError(err)
+		paths, err := CollectAll(pathSeq)
+		require.NoError(err)
+		require.Len(paths, 1)
+
+		stats := instrumented.Stats()
+		require.Equal(uint64(2), stats.DatastoreQueries, "one query per relation leaf")
+		require.Equal(uint64(2), stats.RelationshipsLoaded, "multiroleguy holds both relations")
+		require.Equal(uint64(1), stats.PathsEmitted, "the union merges both into one path")
+		require.Equal(uint64(1), stats.PeakBufferedPaths)
+		require.Positive(stats.WallTime)
+		require.Zero(stats.CacheHits)
+	})
+
+	t.Run("DispatchCountMetadata", func(t *testing.T) {
+		t.Parallel()
+		require := require.New(t)
+
+		instrumented := WithExecutionStats(buildPlan(), WithDispatchCountMetadata())
+
+		pathSeq, err := ctx.Check(instrumented, NewObjects("document", "specialplan"), NewObject("user", "multiroleguy").WithEllipses())
+		require.NoError(err)
+		paths, err := CollectAll(pathSeq)
+		require.NoError(err)
+		require.Len(paths, 1)
+		require.Equal(uint64(2), paths[0].Metadata[DispatchCountMetadataKey])
+	})
+
+	t.Run("WithoutStatsIsInert", func(t *testing.T) {
+		t.Parallel()
+		require := require.New(t)
+
+		instrumented := WithExecutionStats(buildPlan(), WithoutStats())
+
+		pathSeq, err := ctx.Check(instrumented, NewObjects("document", "specialplan"), NewObject("user", "multiroleguy").WithEllipses())
+		require.NoError(err)
+		paths, err := CollectAll(pathSeq)
+		require.NoError(err)
+		require.Len(paths, 1)
+
+		require.Zero(instrumented.Stats(), "disabled instrumentation must record nothing")
+	})
+
+	t.Run("CacheHits", func(t *testing.T) {
+		t.Parallel()
+		require := require.New(t)
+
+		instrumented := WithExecutionStats(buildPlan())
+		instrumented.RecordCacheHit()
+		instrumented.RecordCacheHit()
+		require.Equal(uint64(2), instrumented.Stats().CacheHits)
+	})
+}
+
+func TestExecutionStatsParallel(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	ctx, buildPlan := buildStatsFixture(t)
+
+	instrumented := WithExecutionStats(buildPlan())
+
+	const executions = 16
+	var wg sync.WaitGroup
+	for range executions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pathSeq, err := ctx.Check(instrumented.Clone(), NewObjects("document", "specialplan"), NewObject("user", "multiroleguy").WithEllipses())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := CollectAll(pathSeq); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := instrumented.Stats()
+	require.Equal(uint64(2*executions), stats.DatastoreQueries, "clones share counters, so parallel totals aggregate")
+	require.Equal(uint64(executions), stats.PathsEmitted)
+}
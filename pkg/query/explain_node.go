@@ -0,0 +1,251 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExplainNode is a renderable description of a single node in a query plan
+// tree, suitable for surfacing to operators via ExplainText or ExplainDOT.
+type ExplainNode struct {
+	Operator      string
+	Relation      string
+	EstimatedCost float64
+	Children      []ExplainNode
+
+	// Stats carries runtime statistics for this node when the plan was
+	// executed under WithStats; nil otherwise.
+	Stats *ExplainStats
+}
+
+// ExplainStats holds the runtime statistics recorded for a plan node during
+// execution.
+type ExplainStats struct {
+	PathsProduced    int
+	DatastoreQueries int
+	TimeSpent        time.Duration
+}
+
+// BuildExplainNode converts an iterator tree into an ExplainNode tree,
+// attaching runtime statistics for trees wrapped via WithStats.
+func BuildExplainNode(it Iterator) ExplainNode {
+	var stats *ExplainStats
+	if si, ok := it.(*statsIterator); ok {
+		statsCopy := si.stats
+		stats = &statsCopy
+		it = si.wrapped
+	}
+
+	subs := it.Subiterators()
+	children := make([]ExplainNode, 0, len(subs))
+	cost := 1.0
+	for _, sub := range subs {
+		child := BuildExplainNode(sub)
+		cost += child.EstimatedCost
+		children = append(children, child)
+
+		// Datastore queries aggregate upward so each node reports the total
+		// issued beneath it.
+		if stats != nil && child.Stats != nil {
+			stats.DatastoreQueries += child.Stats.DatastoreQueries
+		}
+	}
+
+	return ExplainNode{
+		Operator:      operatorName(it),
+		Relation:      relationName(it),
+		EstimatedCost: cost,
+		Children:      children,
+		Stats:         stats,
+	}
+}
+
+// operatorName provides the short operator name for a plan node.
+func operatorName(it Iterator) string {
+	switch it.(type) {
+	case *RelationIterator:
+		return "Relation"
+	}
+
+	if name := it.Explain().Name; name != "" {
+		return name
+	}
+
+	// Fall back to the Info line, truncated at any argument list.
+	info := it.Explain().Info
+	if idx := strings.IndexByte(info, '('); idx > 0 {
+		return info[:idx]
+	}
+	return info
+}
+
+// relationName provides the relation a leaf node reads, if any.
+func relationName(it Iterator) string {
+	if r, ok := it.(*RelationIterator); ok {
+		return fmt.Sprintf("%s#%s", r.base.DefinitionName(), r.base.RelationName())
+	}
+	return ""
+}
+
+// ExplainText renders an ExplainNode tree as an indented, human-readable
+// plan description.
+func ExplainText(node ExplainNode) string {
+	var sb strings.Builder
+	writeExplainText(&sb, node, 0)
+	return sb.String()
+}
+
+func writeExplainText(sb *strings.Builder, node ExplainNode, depth int) {
+	sb.WriteString(strings.Repeat("\t", depth))
+	sb.WriteString(node.Operator)
+	if node.Relation != "" {
+		sb.WriteString(" on ")
+		sb.WriteString(node.Relation)
+	}
+	fmt.Fprintf(sb, " (cost=%.1f)", node.EstimatedCost)
+	if node.Stats != nil {
+		fmt.Fprintf(sb, " [paths=%d queries=%d time=%s]",
+			node.Stats.PathsProduced, node.Stats.DatastoreQueries, node.Stats.TimeSpent)
+	}
+	sb.WriteString("\n")
+	for _, child := range node.Children {
+		writeExplainText(sb, child, depth+1)
+	}
+}
+
+// ExplainDOT renders an ExplainNode tree in Graphviz DOT format. Node
+// identifiers are assigned in preorder, so the output is deterministic for a
+// given tree. Timing information is deliberately omitted from the labels to
+// keep the output stable for golden tests.
+func ExplainDOT(node ExplainNode) string {
+	var sb strings.Builder
+	sb.WriteString("digraph plan {\n")
+	nextID := 0
+	writeExplainDOT(&sb, node, &nextID)
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func writeExplainDOT(sb *strings.Builder, node ExplainNode, nextID *int) int {
+	id := *nextID
+	*nextID++
+
+	label := node.Operator
+	if node.Relation != "" {
+		label += "\\n" + node.Relation
+	}
+	label += fmt.Sprintf("\\ncost=%.1f", node.EstimatedCost)
+	if node.Stats != nil {
+		label += fmt.Sprintf("\\npaths=%d queries=%d", node.Stats.PathsProduced, node.Stats.DatastoreQueries)
+	}
+	fmt.Fprintf(sb, "  n%d [label=\"%s\"];\n", id, label)
+
+	for _, child := range node.Children {
+		childID := writeExplainDOT(sb, child, nextID)
+		fmt.Fprintf(sb, "  n%d -> n%d;\n", id, childID)
+	}
+	return id
+}
+
+// WithStats wraps an iterator tree so that executing it records runtime
+// statistics, which BuildExplainNode then attaches to the resulting
+// ExplainNodes.
+func WithStats(it Iterator) Iterator {
+	subs := it.Subiterators()
+	if len(subs) > 0 {
+		newSubs := make([]Iterator, len(subs))
+		for i, sub := range subs {
+			newSubs[i] = WithStats(sub)
+		}
+		rebuilt, err := it.ReplaceSubiterators(newSubs)
+		if err == nil {
+			it = rebuilt
+		}
+	}
+	return &statsIterator{wrapped: it}
+}
+
+// statsIterator wraps an Iterator, recording the paths produced, datastore
+// queries issued and time spent at this node during execution.
+type statsIterator struct {
+	wrapped Iterator
+	stats   ExplainStats
+}
+
+var _ Iterator = &statsIterator{}
+
+func (s *statsIterator) recordSeq(seq PathSeq, start time.Time) PathSeq {
+	return func(yield func(Path, error) bool) {
+		defer func() {
+			s.stats.TimeSpent += time.Since(start)
+		}()
+		seq(func(p Path, err error) bool {
+			if err == nil {
+				s.stats.PathsProduced++
+			}
+			return yield(p, err)
+		})
+	}
+}
+
+func (s *statsIterator) recordQuery() {
+	if len(s.wrapped.Subiterators()) == 0 {
+		if _, ok := s.wrapped.(*RelationIterator); ok {
+			s.stats.DatastoreQueries++
+		}
+	}
+}
+
+func (s *statsIterator) CheckImpl(ctx *Context, resources []Object, subject ObjectAndRelation) (PathSeq, error) {
+	start := time.Now()
+	s.recordQuery()
+	seq, err := s.wrapped.CheckImpl(ctx, resources, subject)
+	if err != nil {
+		s.stats.TimeSpent += time.Since(start)
+		return nil, err
+	}
+	return s.recordSeq(seq, start), nil
+}
+
+func (s *statsIterator) IterSubjectsImpl(ctx *Context, resource Object) (PathSeq, error) {
+	start := time.Now()
+	s.recordQuery()
+	seq, err := s.wrapped.IterSubjectsImpl(ctx, resource)
+	if err != nil {
+		s.stats.TimeSpent += time.Since(start)
+		return nil, err
+	}
+	return s.recordSeq(seq, start), nil
+}
+
+func (s *statsIterator) IterResourcesImpl(ctx *Context, subject ObjectAndRelation) (PathSeq, error) {
+	start := time.Now()
+	s.recordQuery()
+	seq, err := s.wrapped.IterResourcesImpl(ctx, subject)
+	if err != nil {
+		s.stats.TimeSpent += time.Since(start)
+		return nil, err
+	}
+	return s.recordSeq(seq, start), nil
+}
+
+func (s *statsIterator) Clone() Iterator {
+	return &statsIterator{wrapped: s.wrapped.Clone()}
+}
+
+func (s *statsIterator) Explain() Explain {
+	return s.wrapped.Explain()
+}
+
+func (s *statsIterator) Subiterators() []Iterator {
+	return s.wrapped.Subiterators()
+}
+
+func (s *statsIterator) ReplaceSubiterators(newSubs []Iterator) (Iterator, error) {
+	rebuilt, err := s.wrapped.ReplaceSubiterators(newSubs)
+	if err != nil {
+		return nil, err
+	}
+	return &statsIterator{wrapped: rebuilt}, nil
+}
@@ -0,0 +1,119 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildExplainNode(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	t.Run("NestedOperators", func(t *testing.T) {
+		t.Parallel()
+
+		union := NewUnion()
+		union.addSubIterator(NewDocumentAccessFixedIterator())
+		union.addSubIterator(NewMultiRoleFixedIterator())
+
+		arrow := NewArrow(NewSingleUserFixedIterator("alice"), union)
+
+		node := BuildExplainNode(arrow)
+		require.Equal("Arrow", node.Operator)
+		require.Len(node.Children, 2)
+		require.Equal("Fixed", node.Children[0].Operator)
+		require.Equal("Union", node.Children[1].Operator)
+		require.Len(node.Children[1].Children, 2)
+		require.Nil(node.Stats, "stats must be absent without WithStats")
+	})
+
+	t.Run("CostAccumulatesUpward", func(t *testing.T) {
+		t.Parallel()
+
+		union := NewUnion()
+		union.addSubIterator(NewDocumentAccessFixedIterator())
+		union.addSubIterator(NewMultiRoleFixedIterator())
+
+		node := BuildExplainNode(union)
+		require.Equal(3.0, node.EstimatedCost, "a parent's cost includes its children")
+		require.Equal(1.0, node.Children[0].EstimatedCost)
+	})
+}
+
+func TestExplainTextRendering(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	union := NewUnion()
+	union.addSubIterator(NewDocumentAccessFixedIterator())
+	union.addSubIterator(NewMultiRoleFixedIterator())
+
+	text := ExplainText(BuildExplainNode(union))
+	require.Contains(text, "Union (cost=3.0)")
+	require.Contains(text, "\tFixed (cost=1.0)")
+}
+
+func TestExplainDOTRendering(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	union := NewUnion()
+	union.addSubIterator(NewDocumentAccessFixedIterator())
+	union.addSubIterator(NewMultiRoleFixedIterator())
+
+	expected := `digraph plan {
+  n0 [label="Union\ncost=3.0"];
+  n1 [label="Fixed\ncost=1.0"];
+  n0 -> n1;
+  n2 [label="Fixed\ncost=1.0"];
+  n0 -> n2;
+}
+`
+
+	dot := ExplainDOT(BuildExplainNode(union))
+	require.Equal(expected, dot, "DOT output must be deterministic")
+
+	// Rendering the same tree twice produces identical output.
+	require.Equal(dot, ExplainDOT(BuildExplainNode(union)))
+}
+
+func TestWithStatsRecordsExecution(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	ctx := &Context{
+		Context:  t.Context(),
+		Executor: LocalExecutor{},
+	}
+
+	union := NewUnion()
+	union.addSubIterator(NewDocumentAccessFixedIterator())
+	union.addSubIterator(NewMultiRoleFixedIterator())
+
+	instrumented := WithStats(union)
+
+	pathSeq, err := ctx.Check(instrumented, NewObjects("document", "doc1"), NewObject("user", "alice").WithEllipses())
+	require.NoError(err)
+	paths, err := CollectAll(pathSeq)
+	require.NoError(err)
+	require.NotEmpty(paths)
+
+	node := BuildExplainNode(instrumented)
+	require.NotNil(node.Stats)
+	require.Equal(len(paths), node.Stats.PathsProduced)
+	require.Positive(node.Stats.TimeSpent)
+
+	// The child nodes also carry their own stats.
+	require.Len(node.Children, 2)
+	for _, child := range node.Children {
+		require.NotNil(child.Stats)
+	}
+
+	text := ExplainText(node)
+	require.Contains(text, "paths=")
+}
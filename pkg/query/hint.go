@@ -0,0 +1,128 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Hint describes a result-shaping request from the caller — a limit on the
+// number of resources, a resource ID prefix filter, and/or a resource ID
+// cursor — that leaf iterators may translate into datastore query options
+// instead of trimming after the fact.
+type Hint struct {
+	Limit            uint64
+	ResourceIDPrefix string
+	AfterResourceID  string
+}
+
+// IsZero returns whether the hint requests nothing.
+func (h Hint) IsZero() bool {
+	return h == Hint{}
+}
+
+// matchesResourceID returns whether a resource ID passes the hint's prefix and
+// cursor filters.
+func (h Hint) matchesResourceID(resourceID string) bool {
+	if h.ResourceIDPrefix != "" && !strings.HasPrefix(resourceID, h.ResourceIDPrefix) {
+		return false
+	}
+	if h.AfterResourceID != "" && resourceID <= h.AfterResourceID {
+		return false
+	}
+	return true
+}
+
+// explainInfo renders the hint for Explain output.
+func (h Hint) explainInfo() string {
+	parts := make([]string, 0, 3)
+	if h.Limit > 0 {
+		parts = append(parts, fmt.Sprintf("limit=%d", h.Limit))
+	}
+	if h.ResourceIDPrefix != "" {
+		parts = append(parts, "prefix="+h.ResourceIDPrefix)
+	}
+	if h.AfterResourceID != "" {
+		parts = append(parts, "after="+h.AfterResourceID)
+	}
+	return strings.Join(parts, " ")
+}
+
+// PushdownHint attempts to push the hint down into the leaves of the given
+// plan. Pushdown is only sound for plan shapes built purely from unions,
+// aliases and relation leaves — every other operator may change which
+// resources survive, so the hint must be applied by post-filtering instead.
+// Returns the (possibly rewritten) plan and whether the pushdown was accepted;
+// when false, the caller should wrap its results with ApplyHint.
+func PushdownHint(root Iterator, hint Hint) (Iterator, bool) {
+	if hint.IsZero() {
+		return root, true
+	}
+
+	switch typed := root.(type) {
+	case *RelationIterator:
+		return typed.WithHint(hint), true
+
+	case *Alias:
+		rewritten, ok := PushdownHint(typed.subIt, hint)
+		if !ok {
+			return root, false
+		}
+		return NewAlias(typed.relation, rewritten), true
+
+	case *Union:
+		// A union can push the hint into every branch: each branch then
+		// produces at most the limit in resource order, and the union's
+		// dedup keeps the merged result correct.
+		newSubs := make([]Iterator, len(typed.subIts))
+		for i, sub := range typed.subIts {
+			rewritten, ok := PushdownHint(sub, hint)
+			if !ok {
+				return root, false
+			}
+			newSubs[i] = rewritten
+		}
+		rebuilt, err := typed.ReplaceSubiterators(newSubs)
+		if err != nil {
+			return root, false
+		}
+		return rebuilt, true
+
+	default:
+		return root, false
+	}
+}
+
+// ApplyHint post-filters a path sequence according to the hint, for plans
+// where pushdown was not sound. The limit counts distinct resources.
+func ApplyHint(seq PathSeq, hint Hint) PathSeq {
+	if hint.IsZero() {
+		return seq
+	}
+
+	return func(yield func(Path, error) bool) {
+		seenResources := make(map[string]struct{})
+		for path, err := range seq {
+			if err != nil {
+				yield(path, err)
+				return
+			}
+
+			if !hint.matchesResourceID(path.Resource.ObjectID) {
+				continue
+			}
+
+			if hint.Limit > 0 {
+				if _, ok := seenResources[path.Resource.Key()]; !ok {
+					if uint64(len(seenResources)) >= hint.Limit {
+						return
+					}
+					seenResources[path.Resource.Key()] = struct{}{}
+				}
+			}
+
+			if !yield(path, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/internal/datastore/dsfortesting"
+	"github.com/authzed/spicedb/internal/datastore/memdb"
+	"github.com/authzed/spicedb/internal/testfixtures"
+	corev1 "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/schema/v2"
+)
+
+func TestHintMatchesResourceID(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	require.True(Hint{}.matchesResourceID("anything"))
+	require.True(Hint{ResourceIDPrefix: "doc"}.matchesResourceID("doc1"))
+	require.False(Hint{ResourceIDPrefix: "doc"}.matchesResourceID("folder1"))
+	require.True(Hint{AfterResourceID: "doc1"}.matchesResourceID("doc2"))
+	require.False(Hint{AfterResourceID: "doc1"}.matchesResourceID("doc1"))
+	require.False(Hint{AfterResourceID: "doc2"}.matchesResourceID("doc1"))
+}
+
+func TestPushdownHint(t *testing.T) {
+	t.Parallel()
+
+	hint := Hint{Limit: 5, ResourceIDPrefix: "doc"}
+
+	t.Run("RelationLeaf", func(t *testing.T) {
+		t.Parallel()
+		require := require.New(t)
+
+		leaf := NewRelationIterator(createTestBaseRelation("document", "viewer", "user", ""))
+		rewritten, ok := PushdownHint(leaf, hint)
+		require.True(ok)
+		require.Contains(rewritten.Explain().String(), "pushdown: limit=5 prefix=doc")
+	})
+
+	t.Run("UnionOverRelations", func(t *testing.T) {
+		t.Parallel()
+		require := require.New(t)
+
+		union := NewUnion()
+		union.addSubIterator(NewRelationIterator(createTestBaseRelation("document", "viewer", "user", "")))
+		union.addSubIterator(NewRelationIterator(createTestBaseRelation("document", "editor", "user", "")))
+
+		rewritten, ok := PushdownHint(union, hint)
+		require.True(ok)
+		for _, sub := range rewritten.Subiterators() {
+			require.Contains(sub.Explain().String(), "pushdown:")
+		}
+	})
+
+	t.Run("IntersectionRejected", func(t *testing.T) {
+		t.Parallel()
+		require := require.New(t)
+
+		intersection := NewIntersection()
+		intersection.addSubIterator(NewRelationIterator(createTestBaseRelation("document", "viewer", "user", "")))
+		intersection.addSubIterator(NewRelationIterator(createTestBaseRelation("document", "editor", "user", "")))
+
+		rewritten, ok := PushdownHint(intersection, hint)
+		require.False(ok, "intersections may drop resources, so pushdown is unsound")
+		require.NotContains(rewritten.Explain().String(), "pushdown:")
+	})
+
+	t.Run("UnionWithUnsupportedChildRejected", func(t *testing.T) {
+		t.Parallel()
+		require := require.New(t)
+
+		union := NewUnion()
+		union.addSubIterator(NewRelationIterator(createTestBaseRelation("document", "viewer", "user", "")))
+		union.addSubIterator(NewIntersection())
+
+		_, ok := PushdownHint(union, hint)
+		require.False(ok)
+	})
+
+	t.Run("ZeroHintIsNoop", func(t *testing.T) {
+		t.Parallel()
+		require := require.New(t)
+
+		leaf := NewRelationIterator(createTestBaseRelation("document", "viewer", "user", ""))
+		rewritten, ok := PushdownHint(leaf, Hint{})
+		require.True(ok)
+		require.Same(Iterator(leaf), rewritten)
+	})
+}
+
+func TestApplyHint(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	seq := pathSeqOf(
+		MustPathFromString("document:doc1#viewer@user:alice"),
+		MustPathFromString("document:doc2#viewer@user:alice"),
+		MustPathFromString("document:doc3#viewer@user:alice"),
+		MustPathFromString("folder:f1#viewer@user:alice"),
+	)
+
+	paths, err := CollectAll(ApplyHint(seq, Hint{Limit: 2, ResourceIDPrefix: "doc"}))
+	require.NoError(err)
+	require.Len(paths, 2)
+	require.Equal("doc1", paths[0].Resource.ObjectID)
+	require.Equal("doc2", paths[1].Resource.ObjectID)
+
+	paths, err = CollectAll(ApplyHint(seq, Hint{AfterResourceID: "doc1", ResourceIDPrefix: "doc"}))
+	require.NoError(err)
+	require.Len(paths, 2)
+	require.Equal("doc2", paths[0].Resource.ObjectID)
+}
+
+func TestHintedRelationIteratorAgainstDatastore(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	rawDS, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ds, revision := testfixtures.StandardDatastoreWithData(rawDS, require)
+
+	objectDefs := []*corev1.NamespaceDefinition{testfixtures.UserNS.CloneVT(), testfixtures.FolderNS.CloneVT(), testfixtures.DocumentNS.CloneVT()}
+	dsSchema, err := schema.BuildSchemaFromDefinitions(objectDefs, nil)
+	require.NoError(err)
+
+	vande := NewRelationIterator(dsSchema.Definitions()["document"].Relations()["viewer_and_editor"].BaseRelations()[0])
+	viewer := NewRelationIterator(dsSchema.Definitions()["document"].Relations()["viewer"].BaseRelations()[0])
+
+	ctx := &Context{
+		Context:  t.Context(),
+		Executor: LocalExecutor{},
+		Reader:   ds.SnapshotReader(revision),
+	}
+
+	// Establish the unhinted baseline for a resource with multiple subjects.
+	baseSeq, err := ctx.IterSubjects(vande, NewObject("document", "specialplan"))
+	require.NoError(err)
+	basePaths, err := CollectAll(baseSeq)
+	require.NoError(err)
+	require.Greater(len(basePaths), 1)
+
+	t.Run("LimitIsHonoredByTheDatastoreQuery", func(t *testing.T) {
+		hinted := vande.WithHint(Hint{Limit: 1})
+		relSeq, err := ctx.IterSubjects(hinted, NewObject("document", "specialplan"))
+		require.NoError(err)
+		paths, err := CollectAll(relSeq)
+		require.NoError(err)
+		require.Len(paths, 1)
+	})
+
+	t.Run("PrefixExcludesResourceWithoutQuerying", func(t *testing.T) {
+		hinted := vande.WithHint(Hint{ResourceIDPrefix: "master"})
+		relSeq, err := ctx.IterSubjects(hinted, NewObject("document", "specialplan"))
+		require.NoError(err)
+		paths, err := CollectAll(relSeq)
+		require.NoError(err)
+		require.Empty(paths)
+	})
+
+	t.Run("CheckFiltersResourceIDs", func(t *testing.T) {
+		hinted := viewer.WithHint(Hint{ResourceIDPrefix: "master"})
+		relSeq, err := ctx.Check(hinted,
+			NewObjects("document", "masterplan", "ownerplan"),
+
+			NewObject("user", "eng_lead").WithEllipses())
+		require.NoError(err)
+		paths, err := CollectAll(relSeq)
+		require.NoError(err)
+		for _, path := range paths {
+			require.Equal("masterplan", path.Resource.ObjectID)
+		}
+	})
+}
@@ -8,6 +8,10 @@ import (
 // This is equivalent to `permission foo = bar & baz`
 type Intersection struct {
 	subIts []Iterator
+
+	// costOrdered records that the planner reordered the operands
+	// cheapest-first based on cardinality estimates.
+	costOrdered bool
 }
 
 var _ Iterator = &Intersection{}
@@ -125,12 +129,76 @@ func (i *Intersection) IterSubjectsImpl(ctx *Context, resource Object) (PathSeq,
 }
 
 func (i *Intersection) IterResourcesImpl(ctx *Context, subject ObjectAndRelation) (PathSeq, error) {
-	return nil, spiceerrors.MustBugf("unimplemented")
+	// Reverse mode: every sub-iterator enumerates its resources for the fixed
+	// subject, and only resources produced by all of them survive, with
+	// caveats combined using AND semantics.
+	pathsByKey := make(map[string]Path)
+
+	for iterIdx, it := range i.subIts {
+		pathSeq, err := ctx.IterResources(it, subject)
+		if err != nil {
+			return nil, err
+		}
+		paths, err := CollectAll(pathSeq)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx.TraceStep(i, "reverse sub-iterator %d returned %d paths", iterIdx, len(paths))
+
+		if len(paths) == 0 {
+			return EmptyPathSeq(), nil
+		}
+
+		currentIterPaths := make(map[string]Path)
+		for _, path := range paths {
+			key := path.Resource.Key()
+			if existing, exists := currentIterPaths[key]; !exists {
+				currentIterPaths[key] = path
+			} else {
+				merged, err := existing.MergeOr(path)
+				if err != nil {
+					return nil, err
+				}
+				currentIterPaths[key] = merged
+			}
+		}
+
+		if iterIdx == 0 {
+			pathsByKey = currentIterPaths
+			continue
+		}
+
+		newPathsByKey := make(map[string]Path)
+		for key, currentPath := range currentIterPaths {
+			if existing, exists := pathsByKey[key]; exists {
+				combined, err := existing.MergeAnd(currentPath)
+				if err != nil {
+					return nil, err
+				}
+				newPathsByKey[key] = combined
+			}
+		}
+		pathsByKey = newPathsByKey
+
+		if len(pathsByKey) == 0 {
+			return EmptyPathSeq(), nil
+		}
+	}
+
+	return func(yield func(Path, error) bool) {
+		for _, path := range pathsByKey {
+			if !yield(path, nil) {
+				return
+			}
+		}
+	}, nil
 }
 
 func (i *Intersection) Clone() Iterator {
 	cloned := &Intersection{
-		subIts: make([]Iterator, len(i.subIts)),
+		subIts:      make([]Iterator, len(i.subIts)),
+		costOrdered: i.costOrdered,
 	}
 	for idx, subIt := range i.subIts {
 		cloned.subIts[idx] = subIt.Clone()
@@ -145,7 +213,7 @@ func (i *Intersection) Explain() Explain {
 	}
 	return Explain{
 		Name:       "Intersection",
-		Info:       "Intersection",
+		Info:       "Intersection" + explainCostSuffix(i.costDecision()),
 		SubExplain: subs,
 	}
 }
@@ -155,5 +223,12 @@ func (i *Intersection) Subiterators() []Iterator {
 }
 
 func (i *Intersection) ReplaceSubiterators(newSubs []Iterator) (Iterator, error) {
-	return &Intersection{subIts: newSubs}, nil
+	return &Intersection{subIts: newSubs, costOrdered: i.costOrdered}, nil
+}
+
+func (i *Intersection) costDecision() string {
+	if i.costOrdered {
+		return "cost-ordered"
+	}
+	return ""
 }
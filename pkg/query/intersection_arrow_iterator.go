@@ -0,0 +1,194 @@
+package query
+
+import (
+	"slices"
+
+	"github.com/authzed/spicedb/internal/caveats"
+)
+
+// IntersectionArrowIterator implements `tupleset.all(computed)` semantics over
+// path sequences: for each resource it collects the full tupleset, evaluates
+// the computed permission for every intermediate object, and yields a path for
+// a subject only when every branch matched, AND-ing all caveats together.
+//
+// An empty tupleset yields no result (not an unconditional match), matching
+// the graph package's semantics. Caveated tupleset relationships make even
+// membership conditional, so their caveats are AND-ed into the final result as
+// well.
+type IntersectionArrowIterator struct {
+	tupleset  PathSeq
+	computed  ComputedEvaluator
+	chunkSize int
+}
+
+// NewIntersectionArrowIterator creates an IntersectionArrowIterator over the
+// given tupleset sequence and computed-side evaluator.
+func NewIntersectionArrowIterator(tupleset PathSeq, computed ComputedEvaluator) *IntersectionArrowIterator {
+	return &IntersectionArrowIterator{
+		tupleset:  tupleset,
+		computed:  computed,
+		chunkSize: defaultArrowChunkSize,
+	}
+}
+
+// WithChunkSize overrides the number of intermediate objects evaluated per
+// computed call.
+func (a *IntersectionArrowIterator) WithChunkSize(chunkSize int) *IntersectionArrowIterator {
+	a.chunkSize = chunkSize
+	return a
+}
+
+// tuplesetGroup holds the tupleset paths of a single resource, one entry per
+// distinct intermediate object.
+type tuplesetGroup struct {
+	resource Object
+	relation string
+	branches map[string]Path // intermediate object key -> tupleset path
+}
+
+// IterPaths returns the intersection-arrowed sequence of paths, in
+// ComparePaths order.
+func (a *IntersectionArrowIterator) IterPaths() PathSeq {
+	return func(yield func(Path, error) bool) {
+		// Group the tupleset by resource, OR-merging duplicate branches to the
+		// same intermediate.
+		groups := make(map[string]*tuplesetGroup)
+		groupOrder := make([]string, 0)
+		intermediates := make(map[string]Object)
+		intermediateOrder := make([]Object, 0)
+		broken := false
+		a.tupleset(func(p Path, err error) bool {
+			if err != nil {
+				yield(Path{}, err)
+				broken = true
+				return false
+			}
+
+			resourceKey := p.Resource.Key()
+			group, ok := groups[resourceKey]
+			if !ok {
+				group = &tuplesetGroup{
+					resource: p.Resource,
+					relation: p.Relation,
+					branches: make(map[string]Path),
+				}
+				groups[resourceKey] = group
+				groupOrder = append(groupOrder, resourceKey)
+			}
+
+			obj := GetObject(p.Subject)
+			key := obj.Key()
+			if existing, ok := group.branches[key]; ok {
+				merged, merr := existing.MergeOr(p)
+				if merr != nil {
+					yield(Path{}, merr)
+					broken = true
+					return false
+				}
+				merged.Relation = existing.Relation
+				group.branches[key] = merged
+			} else {
+				group.branches[key] = p
+			}
+
+			if _, ok := intermediates[key]; !ok {
+				intermediates[key] = obj
+				intermediateOrder = append(intermediateOrder, obj)
+			}
+			return true
+		})
+		if broken {
+			return
+		}
+
+		// Evaluate the computed permission for every distinct intermediate,
+		// indexing the results per intermediate and subject.
+		computedResults := make(map[string]map[string]Path)
+		for start := 0; start < len(intermediateOrder); start += a.chunkSize {
+			end := min(start+a.chunkSize, len(intermediateOrder))
+
+			computedSeq, err := a.computed(intermediateOrder[start:end])
+			if err != nil {
+				yield(Path{}, err)
+				return
+			}
+
+			for computedPath, err := range computedSeq {
+				if err != nil {
+					yield(Path{}, err)
+					return
+				}
+
+				intermediateKey := computedPath.Resource.Key()
+				bySubject, ok := computedResults[intermediateKey]
+				if !ok {
+					bySubject = make(map[string]Path)
+					computedResults[intermediateKey] = bySubject
+				}
+
+				subjectKey := ObjectAndRelationKey(computedPath.Subject)
+				if existing, ok := bySubject[subjectKey]; ok {
+					merged, merr := mergeUnionPaths(existing, computedPath)
+					if merr != nil {
+						yield(Path{}, merr)
+						return
+					}
+					bySubject[subjectKey] = merged
+				} else {
+					bySubject[subjectKey] = computedPath
+				}
+			}
+		}
+
+		// For each resource, a subject matches only when every branch produced
+		// it; AND together each branch's tupleset and computed caveats.
+		var out []Path
+		for _, resourceKey := range groupOrder {
+			group := groups[resourceKey]
+
+			var resultsBySubject map[string]Path
+			first := true
+			for intermediateKey, tuplesetPath := range group.branches {
+				bySubject := computedResults[intermediateKey]
+				surviving := make(map[string]Path)
+				for subjectKey, computedPath := range bySubject {
+					branch := computedPath.WithResource(group.resource, group.relation)
+					branch.Caveat = caveats.And(tuplesetPath.Caveat, computedPath.Caveat)
+					if tuplesetPath.Expiration != nil &&
+						(branch.Expiration == nil || tuplesetPath.Expiration.Before(*branch.Expiration)) {
+						branch.Expiration = tuplesetPath.Expiration
+					}
+
+					if first {
+						surviving[subjectKey] = branch
+						continue
+					}
+					existing, ok := resultsBySubject[subjectKey]
+					if !ok {
+						continue
+					}
+					merged, merr := existing.MergeAnd(branch)
+					if merr != nil {
+						yield(Path{}, merr)
+						return
+					}
+					merged.Relation = group.relation
+					surviving[subjectKey] = merged
+				}
+				resultsBySubject = surviving
+				first = false
+			}
+
+			for _, p := range resultsBySubject {
+				out = append(out, p)
+			}
+		}
+
+		slices.SortFunc(out, ComparePaths)
+		for _, p := range out {
+			if !yield(p, nil) {
+				return
+			}
+		}
+	}
+}
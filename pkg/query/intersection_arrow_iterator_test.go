@@ -0,0 +1,133 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+func TestIntersectionArrowPathIterator(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	t.Run("EmptyTuplesetYieldsNothing", func(t *testing.T) {
+		t.Parallel()
+
+		computed := mapComputedEvaluator(map[string][]Path{
+			"folder:folder1": {MustPathFromString("folder:folder1#owner@user:alice")},
+		}, nil)
+
+		paths, err := CollectAll(NewIntersectionArrowIterator(EmptyPathSeq(), computed).IterPaths())
+		require.NoError(err)
+		require.Empty(paths, "an empty tupleset must not be an unconditional match")
+	})
+
+	t.Run("SingleElementTupleset", func(t *testing.T) {
+		t.Parallel()
+
+		tupleset := pathSeqOf(MustPathFromString("document:doc1#parent@folder:folder1"))
+		computed := mapComputedEvaluator(map[string][]Path{
+			"folder:folder1": {MustPathFromString("folder:folder1#owner@user:alice")},
+		}, nil)
+
+		paths, err := CollectAll(NewIntersectionArrowIterator(tupleset, computed).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1)
+		require.Equal("doc1", paths[0].Resource.ObjectID)
+		require.Equal("alice", paths[0].Subject.ObjectID)
+		require.Nil(paths[0].Caveat)
+	})
+
+	t.Run("AllBranchesMustMatch", func(t *testing.T) {
+		t.Parallel()
+
+		tupleset := pathSeqOf(
+			MustPathFromString("document:doc1#parent@folder:folder1"),
+			MustPathFromString("document:doc1#parent@folder:folder2"),
+		)
+
+		// alice has the permission on both folders; bob only on folder1.
+		computed := mapComputedEvaluator(map[string][]Path{
+			"folder:folder1": {
+				MustPathFromString("folder:folder1#owner@user:alice"),
+				MustPathFromString("folder:folder1#owner@user:bob"),
+			},
+			"folder:folder2": {MustPathFromString("folder:folder2#owner@user:alice")},
+		}, nil)
+
+		paths, err := CollectAll(NewIntersectionArrowIterator(tupleset, computed).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1, "only subjects matching every branch may be yielded")
+		require.Equal("alice", paths[0].Subject.ObjectID)
+	})
+
+	t.Run("MixedCaveatsAreANDed", func(t *testing.T) {
+		t.Parallel()
+
+		// One branch's membership is caveated; the other branch's computed
+		// permission is caveated. Both conditions must appear in the result.
+		tupleset := pathSeqOf(
+			caveatedPath("document:doc1#parent@folder:folder1", "member_caveat"),
+			MustPathFromString("document:doc1#parent@folder:folder2"),
+		)
+		computed := mapComputedEvaluator(map[string][]Path{
+			"folder:folder1": {MustPathFromString("folder:folder1#owner@user:alice")},
+			"folder:folder2": {caveatedPath("folder:folder2#owner@user:alice", "computed_caveat")},
+		}, nil)
+
+		paths, err := CollectAll(NewIntersectionArrowIterator(tupleset, computed).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1)
+
+		caveat := paths[0].Caveat
+		require.NotNil(caveat, "both branch conditions must be preserved")
+		require.NotNil(caveat.GetOperation())
+		require.Equal(core.CaveatOperation_AND, caveat.GetOperation().Op)
+
+		caveatStr := caveat.String()
+		require.Contains(caveatStr, "member_caveat")
+		require.Contains(caveatStr, "computed_caveat")
+	})
+
+	t.Run("MissingBranchDropsAllSubjects", func(t *testing.T) {
+		t.Parallel()
+
+		tupleset := pathSeqOf(
+			MustPathFromString("document:doc1#parent@folder:folder1"),
+			MustPathFromString("document:doc1#parent@folder:folder2"),
+		)
+
+		// folder2 has no owners at all, so no subject can match all branches.
+		computed := mapComputedEvaluator(map[string][]Path{
+			"folder:folder1": {MustPathFromString("folder:folder1#owner@user:alice")},
+		}, nil)
+
+		paths, err := CollectAll(NewIntersectionArrowIterator(tupleset, computed).IterPaths())
+		require.NoError(err)
+		require.Empty(paths)
+	})
+
+	t.Run("MultipleResources", func(t *testing.T) {
+		t.Parallel()
+
+		tupleset := pathSeqOf(
+			MustPathFromString("document:doc1#parent@folder:folder1"),
+			MustPathFromString("document:doc1#parent@folder:folder2"),
+			MustPathFromString("document:doc2#parent@folder:folder1"),
+		)
+		computed := mapComputedEvaluator(map[string][]Path{
+			"folder:folder1": {MustPathFromString("folder:folder1#owner@user:alice")},
+		}, nil)
+
+		paths, err := CollectAll(NewIntersectionArrowIterator(tupleset, computed).IterPaths())
+		require.NoError(err)
+
+		// doc1 requires both folders (folder2 fails); doc2 only folder1.
+		require.Len(paths, 1)
+		require.Equal("doc2", paths[0].Resource.ObjectID)
+		require.Equal("alice", paths[0].Subject.ObjectID)
+	})
+}
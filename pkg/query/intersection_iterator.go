@@ -0,0 +1,216 @@
+package query
+
+import (
+	"context"
+	"slices"
+
+	"github.com/authzed/spicedb/pkg/tuple"
+)
+
+// IntersectionIterator combines N child path sequences, yielding a path only
+// when every child produced a path with the same resource/subject endpoints.
+// Caveats from the children are combined with AND semantics and expiration
+// takes the minimum across branches.
+//
+// The iterator evaluates the child estimated to be smallest first to build the
+// candidate set, then streams each remaining child to probe and narrow the
+// candidates (semi-join style), so only the smallest branch is ever fully
+// buffered.
+//
+// A wildcard subject on one branch intersected with concrete subjects on
+// another produces the concrete subjects, carrying the caveats of both
+// branches.
+type IntersectionIterator struct {
+	children    []PathSeq
+	estimates   []int
+	concurrency int
+	ctx         context.Context
+}
+
+// NewIntersectionIterator creates an IntersectionIterator over the given child
+// sequences.
+func NewIntersectionIterator(children ...PathSeq) *IntersectionIterator {
+	return &IntersectionIterator{children: children}
+}
+
+// WithEstimatedSizes provides per-child cardinality estimates, which the
+// iterator uses to pick the smallest child as the build side. The number of
+// estimates must match the number of children; extra or missing estimates are
+// ignored.
+func (i *IntersectionIterator) WithEstimatedSizes(estimates ...int) *IntersectionIterator {
+	i.estimates = estimates
+	return i
+}
+
+// WithConcurrency evaluates the children on a bounded worker pool of n workers
+// before intersecting, overlapping their datastore latency. The given context
+// governs the pool: cancelling it stops all branches, and a failing branch
+// cancels its siblings.
+func (i *IntersectionIterator) WithConcurrency(ctx context.Context, n int) *IntersectionIterator {
+	i.concurrency = n
+	i.ctx = ctx
+	return i
+}
+
+// IterPaths returns the intersected sequence of paths, in ComparePaths order.
+func (i *IntersectionIterator) IterPaths() PathSeq {
+	if len(i.children) == 0 {
+		return EmptyPathSeq()
+	}
+
+	if i.concurrency > 1 && len(i.children) > 1 {
+		return func(yield func(Path, error) bool) {
+			results, err := collectBranchesConcurrently(i.ctx, i.concurrency, i.children)
+			if err != nil {
+				yield(Path{}, err)
+				return
+			}
+			prefetched := *i
+			prefetched.children = replayBranches(results)
+			prefetched.concurrency = 0
+			prefetched.IterPaths()(yield)
+		}
+	}
+
+	return func(yield func(Path, error) bool) {
+		order := i.evaluationOrder()
+
+		// Build the candidate set from the (estimated) smallest child,
+		// OR-merging any duplicate endpoints within that single branch.
+		candidates := make(map[string]Path)
+		broken := false
+		i.children[order[0]](func(p Path, err error) bool {
+			if err != nil {
+				yield(Path{}, err)
+				broken = true
+				return false
+			}
+			key := endpointKey(p)
+			if existing, ok := candidates[key]; ok {
+				merged, merr := mergeUnionPaths(existing, p)
+				if merr != nil {
+					yield(Path{}, merr)
+					broken = true
+					return false
+				}
+				candidates[key] = merged
+				return true
+			}
+			candidates[key] = p
+			return true
+		})
+		if broken {
+			return
+		}
+
+		// Stream each remaining child, narrowing the candidate set to the
+		// endpoints that child also produced.
+		for _, childIdx := range order[1:] {
+			if len(candidates) == 0 {
+				return
+			}
+
+			surviving := make(map[string]Path)
+			i.children[childIdx](func(p Path, err error) bool {
+				if err != nil {
+					yield(Path{}, err)
+					broken = true
+					return false
+				}
+
+				for _, candidate := range candidates {
+					merged, ok, merr := mergeIntersectionPaths(candidate, p)
+					if merr != nil {
+						yield(Path{}, merr)
+						broken = true
+						return false
+					}
+					if !ok {
+						continue
+					}
+
+					mergedKey := endpointKey(merged)
+					if existing, exists := surviving[mergedKey]; exists {
+						combined, cerr := mergeUnionPaths(existing, merged)
+						if cerr != nil {
+							yield(Path{}, cerr)
+							broken = true
+							return false
+						}
+						surviving[mergedKey] = combined
+					} else {
+						surviving[mergedKey] = merged
+					}
+				}
+				return true
+			})
+			if broken {
+				return
+			}
+			candidates = surviving
+		}
+
+		out := make([]Path, 0, len(candidates))
+		for _, p := range candidates {
+			out = append(out, p)
+		}
+		slices.SortFunc(out, ComparePaths)
+
+		for _, p := range out {
+			if !yield(p, nil) {
+				return
+			}
+		}
+	}
+}
+
+// evaluationOrder returns child indices sorted by estimated size, smallest
+// first, falling back to declaration order without estimates.
+func (i *IntersectionIterator) evaluationOrder() []int {
+	order := make([]int, len(i.children))
+	for idx := range order {
+		order[idx] = idx
+	}
+	if len(i.estimates) == len(i.children) {
+		slices.SortStableFunc(order, func(a, b int) int {
+			return i.estimates[a] - i.estimates[b]
+		})
+	}
+	return order
+}
+
+func isWildcardPath(p Path) bool {
+	return tuple.IsWildcardSubject(p.Subject)
+}
+
+// mergeIntersectionPaths merges a candidate with a probe path under AND
+// semantics if their endpoints are compatible, returning ok=false when they do
+// not intersect. Endpoints are compatible when they are equal, or when one
+// side holds a wildcard subject of the other's subject type; in the wildcard
+// case the merged path keeps the concrete subject along with both caveats.
+func mergeIntersectionPaths(candidate, probe Path) (Path, bool, error) {
+	if !candidate.Resource.Equals(probe.Resource) ||
+		candidate.Subject.ObjectType != probe.Subject.ObjectType {
+		return Path{}, false, nil
+	}
+
+	switch {
+	case candidate.Subject.ObjectID == probe.Subject.ObjectID:
+		if candidate.Subject.Relation != probe.Subject.Relation {
+			return Path{}, false, nil
+		}
+	case isWildcardPath(candidate):
+		// Adopt the probe's concrete subject before merging.
+		candidate.Subject = probe.Subject
+	case isWildcardPath(probe):
+		probe.Subject = candidate.Subject
+	default:
+		return Path{}, false, nil
+	}
+
+	merged, err := candidate.MergeAnd(probe)
+	if err != nil {
+		return Path{}, false, err
+	}
+	return merged, true, nil
+}
@@ -0,0 +1,196 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+func TestIntersectionIteratorBasics(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	t.Run("YieldsOnlyCommonEndpoints", func(t *testing.T) {
+		t.Parallel()
+
+		left := pathSeqOf(
+			MustPathFromString("document:doc1#viewer@user:alice"),
+			MustPathFromString("document:doc2#viewer@user:alice"),
+		)
+		right := pathSeqOf(
+			MustPathFromString("document:doc1#editor@user:alice"),
+			MustPathFromString("document:doc3#viewer@user:alice"),
+		)
+
+		paths, err := CollectAll(NewIntersectionIterator(left, right).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1)
+		require.Equal("doc1", paths[0].Resource.ObjectID)
+	})
+
+	t.Run("CaveatsAreANDed", func(t *testing.T) {
+		t.Parallel()
+
+		left := pathSeqOf(caveatedPath("document:doc1#viewer@user:alice", "caveat1"))
+		right := pathSeqOf(caveatedPath("document:doc1#viewer@user:alice", "caveat2"))
+
+		paths, err := CollectAll(NewIntersectionIterator(left, right).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1)
+
+		caveat := paths[0].Caveat
+		require.NotNil(caveat)
+		require.NotNil(caveat.GetOperation())
+		require.Equal(core.CaveatOperation_AND, caveat.GetOperation().Op)
+		require.Len(caveat.GetOperation().GetChildren(), 2)
+	})
+
+	t.Run("ExpirationTakesMin", func(t *testing.T) {
+		t.Parallel()
+
+		earlier := time.Now().Add(time.Hour)
+		later := time.Now().Add(2 * time.Hour)
+
+		pathEarlier := MustPathFromString("document:doc1#viewer@user:alice")
+		pathEarlier.Expiration = &earlier
+		pathLater := MustPathFromString("document:doc1#viewer@user:alice")
+		pathLater.Expiration = &later
+
+		paths, err := CollectAll(NewIntersectionIterator(pathSeqOf(pathLater), pathSeqOf(pathEarlier)).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1)
+		require.NotNil(paths[0].Expiration)
+		require.Equal(earlier, *paths[0].Expiration, "intersection keeps the earliest expiration")
+	})
+
+	t.Run("WildcardIntersectsConcreteSubjects", func(t *testing.T) {
+		t.Parallel()
+
+		wildcard := caveatedPath("document:doc1#viewer@user:*", "wildcard_caveat")
+		concrete := caveatedPath("document:doc1#viewer@user:alice", "concrete_caveat")
+
+		for name, children := range map[string][]PathSeq{
+			"WildcardFirst": {pathSeqOf(wildcard), pathSeqOf(concrete)},
+			"WildcardLast":  {pathSeqOf(concrete), pathSeqOf(wildcard)},
+		} {
+			t.Run(name, func(t *testing.T) {
+				paths, err := CollectAll(NewIntersectionIterator(children...).IterPaths())
+				require.NoError(err)
+				require.Len(paths, 1)
+				require.Equal("alice", paths[0].Subject.ObjectID, "wildcard must resolve to the concrete subject")
+
+				caveat := paths[0].Caveat
+				require.NotNil(caveat)
+				require.NotNil(caveat.GetOperation())
+				require.Equal(core.CaveatOperation_AND, caveat.GetOperation().Op)
+			})
+		}
+	})
+
+	t.Run("EstimatesPickBuildSide", func(t *testing.T) {
+		t.Parallel()
+
+		big := pathSeqOf(
+			MustPathFromString("document:doc1#viewer@user:alice"),
+			MustPathFromString("document:doc2#viewer@user:alice"),
+			MustPathFromString("document:doc3#viewer@user:alice"),
+		)
+		small := pathSeqOf(MustPathFromString("document:doc2#viewer@user:alice"))
+
+		paths, err := CollectAll(NewIntersectionIterator(big, small).WithEstimatedSizes(3, 1).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1)
+		require.Equal("doc2", paths[0].Resource.ObjectID)
+	})
+
+	t.Run("ChildErrorMidStream", func(t *testing.T) {
+		t.Parallel()
+
+		childErr := errors.New("child failed")
+		left := pathSeqOf(MustPathFromString("document:doc1#viewer@user:alice"))
+		right := erroringPathSeq(childErr)
+
+		_, err := CollectAll(NewIntersectionIterator(left, right).IterPaths())
+		require.ErrorIs(err, childErr)
+	})
+
+	t.Run("NoChildren", func(t *testing.T) {
+		t.Parallel()
+
+		paths, err := CollectAll(NewIntersectionIterator().IterPaths())
+		require.NoError(err)
+		require.Empty(paths)
+	})
+}
+
+func TestIntersectionIteratorAgainstBruteForce(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	// Randomized correctness check using concrete, uncaveated paths: the
+	// intersection of N children must match the set intersection of their
+	// endpoint keys.
+	rng := rand.New(rand.NewSource(42))
+
+	randomPaths := func() []Path {
+		count := rng.Intn(12)
+		seen := make(map[string]struct{})
+		paths := make([]Path, 0, count)
+		for range count {
+			doc := fmt.Sprintf("doc%d", rng.Intn(6))
+			user := fmt.Sprintf("user%d", rng.Intn(4))
+			p := MustPathFromString(fmt.Sprintf("document:%s#viewer@user:%s", doc, user))
+			key := endpointKey(p)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			paths = append(paths, p)
+		}
+		return paths
+	}
+
+	for trial := range 50 {
+		childCount := 2 + rng.Intn(3)
+		children := make([][]Path, childCount)
+		seqs := make([]PathSeq, childCount)
+		for i := range childCount {
+			children[i] = randomPaths()
+			seqs[i] = pathSeqOf(children[i]...)
+		}
+
+		// Brute-force reference: endpoint keys present in every child.
+		expected := make(map[string]struct{})
+		for _, p := range children[0] {
+			expected[endpointKey(p)] = struct{}{}
+		}
+		for _, child := range children[1:] {
+			childKeys := make(map[string]struct{})
+			for _, p := range child {
+				childKeys[endpointKey(p)] = struct{}{}
+			}
+			for key := range expected {
+				if _, ok := childKeys[key]; !ok {
+					delete(expected, key)
+				}
+			}
+		}
+
+		paths, err := CollectAll(NewIntersectionIterator(seqs...).IterPaths())
+		require.NoError(err, "trial %d", trial)
+
+		actual := make(map[string]struct{})
+		for _, p := range paths {
+			actual[endpointKey(p)] = struct{}{}
+		}
+		require.Equal(expected, actual, "trial %d mismatch", trial)
+	}
+}
@@ -157,13 +157,16 @@ func TestIntersectionIterator(t *testing.T) {
 		})
 	})
 
-	t.Run("IterResources_Unimplemented", func(t *testing.T) {
+	t.Run("IterResources_Empty", func(t *testing.T) {
 		t.Parallel()
 
 		intersect := NewIntersection()
-		require.Panics(func() {
-			_, _ = ctx.IterResources(intersect, NewObject("user", "alice").WithEllipses())
-		})
+		pathSeq, err := ctx.IterResources(intersect, NewObject("user", "alice").WithEllipses())
+		require.NoError(err)
+
+		rels, err := CollectAll(pathSeq)
+		require.NoError(err)
+		require.Empty(rels, "an intersection with no sub-iterators has no resources")
 	})
 }
 
@@ -1,10 +1,12 @@
 package query
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"iter"
 	"maps"
+	"slices"
 	"time"
 
 	"github.com/authzed/spicedb/internal/caveats"
@@ -46,6 +48,15 @@ func (p Path) Key() string {
 	return fmt.Sprintf("%s#%s@%s", p.Resource.Key(), p.Relation, ObjectAndRelationKey(p.Subject))
 }
 
+// ReferenceKey returns a comparable key for this Path based on its resource and subject,
+// usable directly as a map key without allocating a formatted string.
+func (p Path) ReferenceKey() tuple.ReferenceKey {
+	return tuple.KeyFromReference(tuple.RelationshipReference{
+		Resource: p.ResourceOAR(),
+		Subject:  p.Subject,
+	})
+}
+
 // MergeOr combines the paths, ORing the caveats and expiration and metadata together.
 // Returns a new Path with the merged values.
 func (p Path) MergeOr(other Path) (Path, error) {
@@ -79,8 +90,10 @@ func (p Path) MergeAnd(other Path) (Path, error) {
 func (p Path) MergeAndNot(other Path) (Path, error) {
 	return p.mergeFrom(other, func(pCaveat, otherCaveat *core.CaveatExpression) *core.CaveatExpression {
 		if otherCaveat != nil {
-			// If pCaveat is nil, this turns it into a negation (Invert() in caveats package)
-			// Otherwise it's a subtraction.
+			if pCaveat == nil {
+				// No caveat on the receiver: the result is a plain negation.
+				return caveats.Not(otherCaveat)
+			}
 			return caveats.Subtract(pCaveat, otherCaveat)
 		}
 		// If we're subtracting no caveat, then just the original one.
@@ -170,10 +183,12 @@ func FromRelationship(rel tuple.Relationship) Path {
 		integrity = []*core.RelationshipIntegrity{rel.OptionalIntegrity}
 	}
 
+	// Subject types and relations are drawn from the small fixed set defined by the schema;
+	// interning them deduplicates the backing strings across large result sets.
 	return Path{
 		Resource:   resource,
 		Relation:   rel.Resource.Relation,
-		Subject:    rel.Subject,
+		Subject:    tuple.InternONR(rel.Subject),
 		Caveat:     caveat,
 		Expiration: rel.OptionalExpiration,
 		Integrity:  integrity,
@@ -199,7 +214,7 @@ func (p Path) ToRelationship() (tuple.Relationship, error) {
 			caveat = p.Caveat.GetCaveat()
 		} else {
 			// For complex caveat expressions, we cannot directly convert to a single ContextualizedCaveat
-			return tuple.Relationship{}, errors.New("cannot convert Path with complex caveat expression to Relationship")
+			return tuple.Relationship{}, fmt.Errorf("cannot convert Path with complex caveat expression `%s` to Relationship", caveats.StringifyExpression(p.Caveat))
 		}
 	}
 
@@ -230,6 +245,33 @@ func MustPathFromString(relationshipStr string) Path {
 	return FromRelationship(rel)
 }
 
+// WithResource returns a copy of the path rebound to the given resource and relation,
+// keeping the subject, caveat, expiration, integrity and metadata unchanged.
+func (p Path) WithResource(resource Object, relation string) Path {
+	p.Resource = resource
+	p.Relation = relation
+	return p
+}
+
+// ComparePaths provides a total order over paths based on their resource and subject
+// endpoints. It returns 0 exactly when EqualsEndpoints returns true, which makes it
+// suitable as the sort order for streaming merge operations over path sequences.
+func ComparePaths(a, b Path) int {
+	if c := cmp.Compare(a.Resource.ObjectType, b.Resource.ObjectType); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Resource.ObjectID, b.Resource.ObjectID); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Subject.ObjectType, b.Subject.ObjectType); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Subject.ObjectID, b.Subject.ObjectID); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Subject.Relation, b.Subject.Relation)
+}
+
 // EqualsEndpoints checks if two paths have the same Resource and Subject endpoints (types and IDs only)
 func (p Path) EqualsEndpoints(other Path) bool {
 	return p.Resource.ObjectType == other.Resource.ObjectType &&
@@ -239,8 +281,17 @@ func (p Path) EqualsEndpoints(other Path) bool {
 		p.Subject.Relation == other.Subject.Relation
 }
 
+// PathEqualityOption configures optional behaviors for Path.Equals.
+type PathEqualityOption int
+
+const (
+	// WithSemanticCaveatComparison compares caveat expressions up to commutative equivalence
+	// (e.g. `Or(a, b)` matches `Or(b, a)`), rather than structurally.
+	WithSemanticCaveatComparison PathEqualityOption = iota
+)
+
 // Equals checks if two paths are fully equal (all fields match)
-func (p Path) Equals(other Path) bool {
+func (p Path) Equals(other Path, opts ...PathEqualityOption) bool {
 	// Check basic fields
 	if p.Resource.ObjectType != other.Resource.ObjectType ||
 		p.Resource.ObjectID != other.Resource.ObjectID ||
@@ -264,9 +315,13 @@ func (p Path) Equals(other Path) bool {
 		return false
 	}
 	if p.Caveat != nil && other.Caveat != nil {
-		// For now, just compare the string representation
-		// A more sophisticated comparison would parse the caveat structure
-		if p.Caveat.String() != other.Caveat.String() {
+		if slices.Contains(opts, WithSemanticCaveatComparison) {
+			if !caveats.ExpressionsEquivalent(p.Caveat, other.Caveat) {
+				return false
+			}
+		} else if p.Caveat.String() != other.Caveat.String() {
+			// For now, just compare the string representation
+			// A more sophisticated comparison would parse the caveat structure
 			return false
 		}
 	}
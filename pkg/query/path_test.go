@@ -933,3 +933,34 @@ func TestPath_MergeAndNot_Comprehensive(t *testing.T) {
 		require.Contains(err.Error(), "cannot merge paths with different subjects")
 	})
 }
+
+func TestPath_EqualsWithSemanticCaveatComparison(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	makePath := func(caveat *core.CaveatExpression) Path {
+		return Path{
+			Resource: NewObject("document", "doc1"),
+			Relation: "viewer",
+			Subject:  NewObjectAndRelation("alice", "user", ""),
+			Caveat:   caveat,
+		}
+	}
+
+	caveat1 := caveats.CaveatExprForTesting("caveat1")
+	caveat2 := caveats.CaveatExprForTesting("caveat2")
+
+	first := makePath(caveats.Or(caveat1, caveat2))
+	second := makePath(caveats.Or(caveat2, caveat1))
+
+	// Structurally, the commuted caveats compare unequal; semantically, they are equivalent.
+	require.False(first.Equals(second))
+	require.True(first.Equals(second, WithSemanticCaveatComparison))
+
+	// Differing caveats remain unequal under semantic comparison.
+	require.False(first.Equals(makePath(caveat1), WithSemanticCaveatComparison))
+	require.False(first.Equals(makePath(nil), WithSemanticCaveatComparison))
+
+	// Uncaveated paths are unaffected by the option.
+	require.True(makePath(nil).Equals(makePath(nil), WithSemanticCaveatComparison))
+}
@@ -0,0 +1,215 @@
+package query
+
+import (
+	"github.com/authzed/spicedb/pkg/spiceerrors"
+)
+
+// PathTreeOperation identifies the set operation a PathTree node represents.
+type PathTreeOperation string
+
+const (
+	// PathTreeLeaf holds the concrete paths found for a single relation.
+	PathTreeLeaf PathTreeOperation = "leaf"
+
+	// PathTreeUnion combines its children with OR semantics.
+	PathTreeUnion PathTreeOperation = "union"
+
+	// PathTreeIntersection combines its children with AND semantics.
+	PathTreeIntersection PathTreeOperation = "intersection"
+
+	// PathTreeExclusion subtracts its second child from its first.
+	PathTreeExclusion PathTreeOperation = "exclusion"
+
+	// PathTreeArrow holds the joined results of a walk; arrows evaluate as a
+	// unit, so their results live on the node itself rather than on children.
+	PathTreeArrow PathTreeOperation = "arrow"
+)
+
+// PathTree mirrors the shape of a plan: leaf nodes hold the concrete Paths
+// found for one relation (caveats and wildcards included), and intermediate
+// nodes record the set operation combining their children. It is the
+// structured, Expand-style counterpart to the flat PathSeq the executor
+// normally returns.
+type PathTree struct {
+	// Operation is the set operation this node represents.
+	Operation PathTreeOperation
+
+	// Info is the Explain description of the plan node this tree node mirrors.
+	Info string
+
+	// Paths holds the concrete paths for leaf and arrow nodes.
+	Paths []Path
+
+	// Children are the subtrees for union, intersection and exclusion nodes.
+	Children []*PathTree
+}
+
+// BuildPathTree executes the plan in tree mode: instead of merging, results
+// are attached to the node that produced them, preserving the plan's shape.
+// Subject and resources are fixed as in Check.
+func BuildPathTree(ctx *Context, it Iterator, resources []Object, subject ObjectAndRelation) (*PathTree, error) {
+	switch typed := it.(type) {
+	case *Union:
+		return buildPathTreeParent(ctx, it, PathTreeUnion, typed.subIts, resources, subject)
+	case *Intersection:
+		return buildPathTreeParent(ctx, it, PathTreeIntersection, typed.subIts, resources, subject)
+	case *Exclusion:
+		return buildPathTreeParent(ctx, it, PathTreeExclusion, []Iterator{typed.mainSet, typed.excluded}, resources, subject)
+	case *Alias:
+		// Aliases are transparent in the tree: the subtree carries the shape.
+		return BuildPathTree(ctx, typed.subIt, resources, subject)
+	case *Arrow:
+		return buildPathTreeNode(ctx, it, PathTreeArrow, resources, subject)
+	default:
+		return buildPathTreeNode(ctx, it, PathTreeLeaf, resources, subject)
+	}
+}
+
+func buildPathTreeParent(ctx *Context, it Iterator, op PathTreeOperation, children []Iterator, resources []Object, subject ObjectAndRelation) (*PathTree, error) {
+	childTrees := make([]*PathTree, 0, len(children))
+	for _, child := range children {
+		childTree, err := BuildPathTree(ctx, child, resources, subject)
+		if err != nil {
+			return nil, err
+		}
+		childTrees = append(childTrees, childTree)
+	}
+	return &PathTree{
+		Operation: op,
+		Info:      it.Explain().Info,
+		Children:  childTrees,
+	}, nil
+}
+
+func buildPathTreeNode(ctx *Context, it Iterator, op PathTreeOperation, resources []Object, subject ObjectAndRelation) (*PathTree, error) {
+	pathSeq, err := ctx.Check(it, resources, subject)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := CollectAll(pathSeq)
+	if err != nil {
+		return nil, err
+	}
+	return &PathTree{
+		Operation: op,
+		Info:      it.Explain().Info,
+		Paths:     paths,
+	}, nil
+}
+
+// Flatten merges the tree back down into the flat set of paths that the
+// normal merged execution would have returned, applying each node's set
+// operation with the same caveat combination rules the iterators use.
+func (p *PathTree) Flatten() ([]Path, error) {
+	switch p.Operation {
+	case PathTreeLeaf, PathTreeArrow:
+		return p.Paths, nil
+
+	case PathTreeUnion:
+		seen := make(map[string]Path)
+		for _, child := range p.Children {
+			childPaths, err := child.Flatten()
+			if err != nil {
+				return nil, err
+			}
+			for _, path := range childPaths {
+				key := path.Resource.Key()
+				if existing, exists := seen[key]; exists {
+					merged, err := existing.MergeOr(path)
+					if err != nil {
+						return nil, err
+					}
+					seen[key] = merged
+				} else {
+					seen[key] = path
+				}
+			}
+		}
+		out := make([]Path, 0, len(seen))
+		for _, path := range seen {
+			out = append(out, path)
+		}
+		return out, nil
+
+	case PathTreeIntersection:
+		var pathsByKey map[string]Path
+		for _, child := range p.Children {
+			childPaths, err := child.Flatten()
+			if err != nil {
+				return nil, err
+			}
+
+			currentPaths := make(map[string]Path)
+			for _, path := range childPaths {
+				key := path.Resource.Key()
+				if existing, exists := currentPaths[key]; exists {
+					merged, err := existing.MergeOr(path)
+					if err != nil {
+						return nil, err
+					}
+					currentPaths[key] = merged
+				} else {
+					currentPaths[key] = path
+				}
+			}
+
+			if pathsByKey == nil {
+				pathsByKey = currentPaths
+				continue
+			}
+
+			newPathsByKey := make(map[string]Path)
+			for key, currentPath := range currentPaths {
+				if existing, exists := pathsByKey[key]; exists {
+					combined, err := existing.MergeAnd(currentPath)
+					if err != nil {
+						return nil, err
+					}
+					newPathsByKey[key] = combined
+				}
+			}
+			pathsByKey = newPathsByKey
+		}
+
+		out := make([]Path, 0, len(pathsByKey))
+		for _, path := range pathsByKey {
+			out = append(out, path)
+		}
+		return out, nil
+
+	case PathTreeExclusion:
+		if len(p.Children) != 2 {
+			return nil, spiceerrors.MustBugf("exclusion tree node must have exactly two children, found %d", len(p.Children))
+		}
+
+		mainPaths, err := p.Children[0].Flatten()
+		if err != nil {
+			return nil, err
+		}
+		excludedPaths, err := p.Children[1].Flatten()
+		if err != nil {
+			return nil, err
+		}
+
+		excludedByResource := make(map[string]Path, len(excludedPaths))
+		for _, excludedPath := range excludedPaths {
+			excludedByResource[excludedPath.Resource.Key()] = excludedPath
+		}
+
+		var out []Path
+		for _, mainPath := range mainPaths {
+			resultPath := mainPath
+			shouldInclude := true
+			if excludedPath, ok := excludedByResource[mainPath.Resource.Key()]; ok {
+				resultPath, shouldInclude = combineExclusionCaveats(mainPath, excludedPath)
+			}
+			if shouldInclude {
+				out = append(out, resultPath)
+			}
+		}
+		return out, nil
+
+	default:
+		return nil, spiceerrors.MustBugf("unknown path tree operation: %s", p.Operation)
+	}
+}
@@ -0,0 +1,128 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/internal/datastore/dsfortesting"
+	"github.com/authzed/spicedb/internal/datastore/memdb"
+	"github.com/authzed/spicedb/internal/testfixtures"
+	corev1 "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/schema/v2"
+)
+
+func TestPathTreeFlattenMatchesDirectExecution(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	rawDS, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ds, revision := testfixtures.StandardDatastoreWithData(rawDS, require)
+
+	objectDefs := []*corev1.NamespaceDefinition{testfixtures.UserNS.CloneVT(), testfixtures.FolderNS.CloneVT(), testfixtures.DocumentNS.CloneVT()}
+	dsSchema, err := schema.BuildSchemaFromDefinitions(objectDefs, nil)
+	require.NoError(err)
+
+	docRelation := func(name string) *RelationIterator {
+		return NewRelationIterator(dsSchema.Definitions()["document"].Relations()[name].BaseRelations()[0])
+	}
+	folderOwner := NewRelationIterator(dsSchema.Definitions()["folder"].Relations()["owner"].BaseRelations()[0])
+
+	plans := map[string]func() Iterator{
+		"Union": func() Iterator {
+			union := NewUnion()
+			union.addSubIterator(docRelation("owner"))
+			union.addSubIterator(docRelation("editor"))
+			union.addSubIterator(docRelation("viewer"))
+			return union
+		},
+		"Intersection": func() Iterator {
+			intersection := NewIntersection()
+			intersection.addSubIterator(docRelation("viewer_and_editor"))
+			intersection.addSubIterator(docRelation("editor"))
+			return intersection
+		},
+		"Exclusion": func() Iterator {
+			return NewExclusion(docRelation("viewer_and_editor"), docRelation("editor"))
+		},
+		"Arrow": func() Iterator {
+			return NewArrow(docRelation("parent"), folderOwner)
+		},
+		"UnionOfExclusionAndArrow": func() Iterator {
+			union := NewUnion()
+			union.addSubIterator(NewExclusion(docRelation("viewer_and_editor"), docRelation("editor")))
+			union.addSubIterator(NewArrow(docRelation("parent"), folderOwner))
+			return union
+		},
+	}
+
+	subjects := []string{"multiroleguy", "missingrolegal", "vp_product", "eng_lead", "owner"}
+
+	for name, buildPlan := range plans {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := &Context{
+				Context:  t.Context(),
+				Executor: LocalExecutor{},
+				Reader:   ds.SnapshotReader(revision),
+			}
+
+			resources := NewObjects("document", standardDocuments...)
+			for _, subjectID := range subjects {
+				subject := NewObject("user", subjectID).WithEllipses()
+
+				directSeq, err := ctx.Check(buildPlan(), resources, subject)
+				require.NoError(err)
+				direct, err := CollectAll(directSeq)
+				require.NoError(err)
+
+				tree, err := BuildPathTree(ctx, buildPlan(), resources, subject)
+				require.NoError(err)
+				flattened, err := tree.Flatten()
+				require.NoError(err)
+
+				require.ElementsMatch(direct, flattened,
+					"flattened tree must equal direct execution for plan %s and subject %s", name, subjectID)
+			}
+		})
+	}
+}
+
+func TestPathTreeShape(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	rawDS, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ds, revision := testfixtures.StandardDatastoreWithData(rawDS, require)
+
+	objectDefs := []*corev1.NamespaceDefinition{testfixtures.UserNS.CloneVT(), testfixtures.FolderNS.CloneVT(), testfixtures.DocumentNS.CloneVT()}
+	dsSchema, err := schema.BuildSchemaFromDefinitions(objectDefs, nil)
+	require.NoError(err)
+
+	ctx := &Context{
+		Context:  t.Context(),
+		Executor: LocalExecutor{},
+		Reader:   ds.SnapshotReader(revision),
+	}
+
+	union := NewUnion()
+	union.addSubIterator(NewRelationIterator(dsSchema.Definitions()["document"].Relations()["viewer_and_editor"].BaseRelations()[0]))
+	union.addSubIterator(NewRelationIterator(dsSchema.Definitions()["document"].Relations()["editor"].BaseRelations()[0]))
+
+	tree, err := BuildPathTree(ctx, union, NewObjects("document", "specialplan"), NewObject("user", "multiroleguy").WithEllipses())
+	require.NoError(err)
+
+	require.Equal(PathTreeUnion, tree.Operation)
+	require.Len(tree.Children, 2)
+	require.Empty(tree.Paths, "intermediate nodes hold no paths")
+
+	for _, child := range tree.Children {
+		require.Equal(PathTreeLeaf, child.Operation)
+		require.Len(child.Paths, 1, "each leaf holds the concrete path it found")
+	}
+}
@@ -0,0 +1,86 @@
+package query
+
+import (
+	"sync"
+)
+
+// PlanCacheKey identifies a cached plan template. The canonical key is the
+// permission's CanonicalCacheKey as computed during namespace annotation
+// (internal/namespace), so aliased permissions — which share a canonical key —
+// share a single cache entry. The schema revision scopes entries to a specific
+// version of the schema, so a namespace definition change never serves a stale
+// plan.
+type PlanCacheKey struct {
+	CanonicalKey   string
+	SchemaRevision string
+}
+
+// PlanCache maps canonical permission keys to immutable plan templates, which
+// are instantiated per-request by cloning. It is safe for concurrent use.
+type PlanCache struct {
+	mu      sync.RWMutex
+	entries map[PlanCacheKey]Iterator
+}
+
+// NewPlanCache creates an empty PlanCache.
+func NewPlanCache() *PlanCache {
+	return &PlanCache{
+		entries: make(map[PlanCacheKey]Iterator),
+	}
+}
+
+// Get returns a fresh clone of the cached plan template for the given key, if
+// present.
+func (c *PlanCache) Get(key PlanCacheKey) (Iterator, bool) {
+	c.mu.RLock()
+	template, ok := c.entries[key]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return template.Clone(), true
+}
+
+// Insert stores a clone of the given plan as the template for the key, so
+// later mutation of the caller's tree cannot corrupt the cache.
+func (c *PlanCache) Insert(key PlanCacheKey, plan Iterator) {
+	template := plan.Clone()
+	c.mu.Lock()
+	c.entries[key] = template
+	c.mu.Unlock()
+}
+
+// GetOrBuild returns a clone of the cached plan template for the key, invoking
+// build to create and cache the template on a miss.
+func (c *PlanCache) GetOrBuild(key PlanCacheKey, build func() (Iterator, error)) (Iterator, error) {
+	if plan, ok := c.Get(key); ok {
+		return plan, nil
+	}
+
+	built, err := build()
+	if err != nil {
+		return nil, err
+	}
+	c.Insert(key, built)
+	return built.Clone(), nil
+}
+
+// InvalidateRevision removes all entries cached under the given schema
+// revision; it is called when namespace definitions change to release plans
+// that can no longer be requested.
+func (c *PlanCache) InvalidateRevision(schemaRevision string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.SchemaRevision == schemaRevision {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Len returns the number of cached plan templates.
+func (c *PlanCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
@@ -0,0 +1,122 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func buildTestPlan() Iterator {
+	union := NewUnion()
+	union.addSubIterator(NewRelationIterator(createTestBaseRelation("document", "viewer", "user", "")))
+	union.addSubIterator(NewRelationIterator(createTestBaseRelation("document", "editor", "user", "")))
+	return union
+}
+
+func TestPlanCache(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	t.Run("MissThenHit", func(t *testing.T) {
+		t.Parallel()
+
+		cache := NewPlanCache()
+		key := PlanCacheKey{CanonicalKey: "%abcd", SchemaRevision: "rev1"}
+
+		_, ok := cache.Get(key)
+		require.False(ok)
+
+		cache.Insert(key, buildTestPlan())
+		plan, ok := cache.Get(key)
+		require.True(ok)
+		require.NotNil(plan)
+	})
+
+	t.Run("GetReturnsClones", func(t *testing.T) {
+		t.Parallel()
+
+		cache := NewPlanCache()
+		key := PlanCacheKey{CanonicalKey: "%abcd", SchemaRevision: "rev1"}
+		cache.Insert(key, buildTestPlan())
+
+		first, ok := cache.Get(key)
+		require.True(ok)
+		second, ok := cache.Get(key)
+		require.True(ok)
+		require.NotSame(first, second, "each request must get its own plan instance")
+		require.Equal(first.Explain().String(), second.Explain().String())
+	})
+
+	t.Run("AliasedPermissionsShareOneEntry", func(t *testing.T) {
+		t.Parallel()
+
+		cache := NewPlanCache()
+
+		// Aliased permissions share a canonical cache key, so both resolve to
+		// the same template and the plan is only built once.
+		key := PlanCacheKey{CanonicalKey: "%sharedhash", SchemaRevision: "rev1"}
+
+		builds := 0
+		build := func() (Iterator, error) {
+			builds++
+			return buildTestPlan(), nil
+		}
+
+		viewPlan, err := cache.GetOrBuild(key, build)
+		require.NoError(err)
+		readPlan, err := cache.GetOrBuild(key, build)
+		require.NoError(err)
+
+		require.Equal(1, builds, "aliased permissions must share one cached plan")
+		require.Equal(1, cache.Len())
+		require.Equal(viewPlan.Explain().String(), readPlan.Explain().String())
+	})
+
+	t.Run("RevisionChangeMisses", func(t *testing.T) {
+		t.Parallel()
+
+		cache := NewPlanCache()
+		cache.Insert(PlanCacheKey{CanonicalKey: "%abcd", SchemaRevision: "rev1"}, buildTestPlan())
+
+		_, ok := cache.Get(PlanCacheKey{CanonicalKey: "%abcd", SchemaRevision: "rev2"})
+		require.False(ok, "a schema change must not serve plans from the old revision")
+	})
+
+	t.Run("InvalidateRevision", func(t *testing.T) {
+		t.Parallel()
+
+		cache := NewPlanCache()
+		cache.Insert(PlanCacheKey{CanonicalKey: "%abcd", SchemaRevision: "rev1"}, buildTestPlan())
+		cache.Insert(PlanCacheKey{CanonicalKey: "%efgh", SchemaRevision: "rev1"}, buildTestPlan())
+		cache.Insert(PlanCacheKey{CanonicalKey: "%abcd", SchemaRevision: "rev2"}, buildTestPlan())
+
+		cache.InvalidateRevision("rev1")
+		require.Equal(1, cache.Len())
+
+		_, ok := cache.Get(PlanCacheKey{CanonicalKey: "%abcd", SchemaRevision: "rev2"})
+		require.True(ok, "other revisions must be unaffected")
+	})
+}
+
+func BenchmarkPlanCacheAmortization(b *testing.B) {
+	cache := NewPlanCache()
+	key := PlanCacheKey{CanonicalKey: "%bench", SchemaRevision: "rev1"}
+
+	b.Run("BuildEveryTime", func(b *testing.B) {
+		for b.Loop() {
+			_ = buildTestPlan()
+		}
+	})
+
+	b.Run("CachedClone", func(b *testing.B) {
+		for b.Loop() {
+			_, err := cache.GetOrBuild(key, func() (Iterator, error) {
+				return buildTestPlan(), nil
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -2,6 +2,8 @@ package query
 
 import (
 	"fmt"
+
+	"github.com/authzed/spicedb/pkg/tuple"
 )
 
 const defaultMaxRecursionDepth = 50
@@ -56,7 +58,7 @@ func (r *RecursiveIterator) iterativeDeepening(ctx *Context, execute func(*Conte
 	}
 
 	return func(yield func(Path, error) bool) {
-		seen := make(map[string]bool)
+		seen := make(map[tuple.ReferenceKey]bool)
 
 		for depth := range maxDepth {
 			ctx.TraceStep(r, "Depth %d: starting iteration", depth)
@@ -87,7 +89,7 @@ func (r *RecursiveIterator) iterativeDeepening(ctx *Context, execute func(*Conte
 				totalPathCount++
 
 				// Deduplicate paths by key
-				key := path.Key()
+				key := path.ReferenceKey()
 				if !seen[key] {
 					seen[key] = true
 					newPathCount++
@@ -0,0 +1,170 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/internal/datastore/dsfortesting"
+	"github.com/authzed/spicedb/internal/datastore/memdb"
+	"github.com/authzed/spicedb/internal/testfixtures"
+	corev1 "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/schema/v2"
+	"github.com/authzed/spicedb/pkg/tuple"
+)
+
+// standardDocuments are the document resources present in the standard
+// fixtures, used for exhaustive forward enumeration.
+var standardDocuments = []string{
+	"companyplan", "masterplan", "healthplan", "specialplan", "ownerplan",
+}
+
+// forwardResources enumerates which of the given resources the subject can
+// reach by checking each one individually.
+func forwardResources(t *testing.T, ctx *Context, plan Iterator, resourceIDs []string, subject ObjectAndRelation) map[string]struct{} {
+	t.Helper()
+
+	found := make(map[string]struct{})
+	for _, resourceID := range resourceIDs {
+		pathSeq, err := ctx.Check(plan, NewObjects("document", resourceID), subject)
+		require.NoError(t, err)
+		paths, err := CollectAll(pathSeq)
+		require.NoError(t, err)
+		for _, path := range paths {
+			found[path.Resource.ObjectID] = struct{}{}
+		}
+	}
+	return found
+}
+
+// reverseResources enumerates resources via the reverse execution mode.
+func reverseResources(t *testing.T, ctx *Context, plan Iterator, subject ObjectAndRelation) map[string]struct{} {
+	t.Helper()
+
+	pathSeq, err := ctx.IterResources(plan, subject)
+	require.NoError(t, err)
+	paths, err := CollectAll(pathSeq)
+	require.NoError(t, err)
+
+	found := make(map[string]struct{})
+	for _, path := range paths {
+		found[path.Resource.ObjectID] = struct{}{}
+	}
+	return found
+}
+
+func TestReverseDifferential(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	rawDS, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ds, revision := testfixtures.StandardDatastoreWithData(rawDS, require)
+
+	objectDefs := []*corev1.NamespaceDefinition{testfixtures.UserNS.CloneVT(), testfixtures.FolderNS.CloneVT(), testfixtures.DocumentNS.CloneVT()}
+	dsSchema, err := schema.BuildSchemaFromDefinitions(objectDefs, nil)
+	require.NoError(err)
+
+	docRelation := func(name string) *RelationIterator {
+		return NewRelationIterator(dsSchema.Definitions()["document"].Relations()[name].BaseRelations()[0])
+	}
+	folderOwner := NewRelationIterator(dsSchema.Definitions()["folder"].Relations()["owner"].BaseRelations()[0])
+
+	plans := map[string]func() Iterator{
+		"RelationLeaf": func() Iterator {
+			return docRelation("viewer")
+		},
+		"Union": func() Iterator {
+			union := NewUnion()
+			union.addSubIterator(docRelation("owner"))
+			union.addSubIterator(docRelation("editor"))
+			union.addSubIterator(docRelation("viewer"))
+			return union
+		},
+		"Intersection": func() Iterator {
+			intersection := NewIntersection()
+			intersection.addSubIterator(docRelation("viewer_and_editor"))
+			intersection.addSubIterator(docRelation("editor"))
+			return intersection
+		},
+		"Exclusion": func() Iterator {
+			return NewExclusion(docRelation("viewer_and_editor"), docRelation("editor"))
+		},
+		"Arrow": func() Iterator {
+			return NewArrow(docRelation("parent"), folderOwner)
+		},
+	}
+
+	subjects := []string{
+		"eng_lead", "product_manager", "multiroleguy", "missingrolegal",
+		"owner", "vp_product", "legal", "unknownuser",
+	}
+
+	for name, buildPlan := range plans {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := &Context{
+				Context:  t.Context(),
+				Executor: LocalExecutor{},
+				Reader:   ds.SnapshotReader(revision),
+			}
+
+			for _, subjectID := range subjects {
+				subject := NewObject("user", subjectID).WithEllipses()
+
+				forward := forwardResources(t, ctx, buildPlan(), standardDocuments, subject)
+				reverse := reverseResources(t, ctx, buildPlan(), subject)
+
+				require.Equal(forward, reverse,
+					"forward and reverse enumeration disagree for plan %s and subject %s", name, subjectID)
+			}
+		})
+	}
+}
+
+func TestReverseWildcardLeaf(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	rawDS, err := dsfortesting.NewMemDBDatastoreForTesting(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ds, revision := testfixtures.DatastoreFromSchemaAndTestRelationships(rawDS, `
+		definition user {}
+
+		definition document {
+			relation viewer: user | user:*
+			permission view = viewer
+		}
+	`, []tuple.Relationship{
+		tuple.MustParse("document:public#viewer@user:*"),
+		tuple.MustParse("document:private#viewer@user:alice"),
+	}, require)
+
+	defs, err := ds.SnapshotReader(revision).ListAllNamespaces(t.Context())
+	require.NoError(err)
+	namespaces := make([]*corev1.NamespaceDefinition, 0, len(defs))
+	for _, def := range defs {
+		namespaces = append(namespaces, def.Definition)
+	}
+	dsSchema, err := schema.BuildSchemaFromDefinitions(namespaces, nil)
+	require.NoError(err)
+
+	ctx := &Context{
+		Context:  t.Context(),
+		Executor: LocalExecutor{},
+		Reader:   ds.SnapshotReader(revision),
+	}
+
+	baseRels := dsSchema.Definitions()["document"].Relations()["viewer"].BaseRelations()
+	union := NewUnion()
+	for _, baseRel := range baseRels {
+		union.addSubIterator(NewRelationIterator(baseRel))
+	}
+
+	reverse := reverseResources(t, ctx, union, NewObject("user", "bob").WithEllipses())
+	require.Contains(reverse, "public", "a wildcard grant must surface in reverse enumeration")
+	require.NotContains(reverse, "private")
+}
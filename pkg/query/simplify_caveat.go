@@ -292,13 +292,7 @@ func simplifyNotOperation(
 		return nil, true, nil
 	}
 
-	// Child is conditional - NOT conditional is still conditional
-	return &core.CaveatExpression{
-		OperationOrCaveat: &core.CaveatExpression_Operation{
-			Operation: &core.CaveatOperation{
-				Op:       core.CaveatOperation_NOT,
-				Children: []*core.CaveatExpression{simplified},
-			},
-		},
-	}, true, nil
+	// Child is conditional - NOT conditional is still conditional. Not eliminates any
+	// double negation introduced by nested subtractions.
+	return caveats.Not(simplified), true, nil
 }
@@ -84,7 +84,53 @@ func (u *Union) IterSubjectsImpl(ctx *Context, resource Object) (PathSeq, error)
 }
 
 func (u *Union) IterResourcesImpl(ctx *Context, subject ObjectAndRelation) (PathSeq, error) {
-	return nil, spiceerrors.MustBugf("unimplemented")
+	// Reverse mode mirrors CheckImpl: the subject is fixed, so paths are
+	// deduplicated by resource and merged with OR semantics.
+	var out []Path
+	ctx.TraceStep(u, "reverse processing %d sub-iterators", len(u.subIts))
+
+	for iterIdx, it := range u.subIts {
+		pathSeq, err := ctx.IterResources(it, subject)
+		if err != nil {
+			return nil, err
+		}
+		paths, err := CollectAll(pathSeq)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx.TraceStep(u, "sub-iterator %d returned %d paths", iterIdx, len(paths))
+		out = append(out, paths...)
+	}
+
+	seen := make(map[string]Path)
+	for _, path := range out {
+		key := path.Resource.Key()
+		if existing, exists := seen[key]; !exists {
+			seen[key] = path
+		} else {
+			merged, err := existing.MergeOr(path)
+			if err != nil {
+				return nil, err
+			}
+			seen[key] = merged
+		}
+	}
+
+	deduplicatedSlice := make([]Path, 0, len(seen))
+	for _, path := range seen {
+		deduplicatedSlice = append(deduplicatedSlice, path)
+	}
+
+	ctx.TraceStep(u, "deduplicated to %d paths", len(deduplicatedSlice))
+
+	return func(yield func(Path, error) bool) {
+		for _, path := range deduplicatedSlice {
+			if !yield(path, nil) {
+				return
+			}
+		}
+	}, nil
 }
 
 func (u *Union) Clone() Iterator {
@@ -0,0 +1,125 @@
+package query
+
+import (
+	"slices"
+)
+
+// unionDedupStrategy buffers the paths a union has observed, folding duplicate
+// endpoints together via mergeUnionPaths, and releases each endpoint's merged
+// path exactly once — either as an eviction during observation or on the final
+// flush.
+type unionDedupStrategy interface {
+	// observe folds p into the buffer. It returns any paths evicted to keep
+	// memory bounded, and an error if recording p would exceed a hard cap.
+	observe(p Path) ([]Path, error)
+
+	// flush returns every path still buffered, ending the iteration.
+	flush() []Path
+}
+
+// exactDedup is the map-based strategy: it holds every distinct endpoint seen,
+// guaranteeing complete dedup at O(distinct endpoints) memory. A non-zero
+// maxEndpoints converts runaway result sets into ErrResultSetTooLarge instead
+// of unbounded growth.
+type exactDedup struct {
+	maxEndpoints int
+	buffered     map[string]Path
+}
+
+func newExactDedup(maxEndpoints int) *exactDedup {
+	return &exactDedup{
+		maxEndpoints: maxEndpoints,
+		buffered:     make(map[string]Path),
+	}
+}
+
+func (d *exactDedup) observe(p Path) ([]Path, error) {
+	key := endpointKey(p)
+	if existing, ok := d.buffered[key]; ok {
+		merged, err := mergeUnionPaths(existing, p)
+		if err != nil {
+			return nil, err
+		}
+		d.buffered[key] = merged
+		return nil, nil
+	}
+
+	if d.maxEndpoints > 0 && len(d.buffered) >= d.maxEndpoints {
+		return nil, ErrResultSetTooLarge
+	}
+	d.buffered[key] = p
+	return nil, nil
+}
+
+func (d *exactDedup) flush() []Path {
+	// Flush in ComparePaths order so the output is deterministic and itself
+	// consumable by an ordered merge.
+	out := make([]Path, 0, len(d.buffered))
+	for _, p := range d.buffered {
+		out = append(out, p)
+	}
+	slices.SortFunc(out, ComparePaths)
+	return out
+}
+
+// windowedDedup holds only the last window distinct endpoints, evicting the
+// oldest as new ones arrive. Memory stays flat regardless of result size, at
+// the cost of missing duplicates further than the window apart — acceptable
+// when the children yield in sorted order, since duplicate endpoints from
+// different branches then appear close together in the combined stream.
+type windowedDedup struct {
+	window   int
+	buffered map[string]Path
+	order    []string
+}
+
+func newWindowedDedup(window int) *windowedDedup {
+	return &windowedDedup{
+		window:   window,
+		buffered: make(map[string]Path, window),
+		order:    make([]string, 0, window),
+	}
+}
+
+func (d *windowedDedup) observe(p Path) ([]Path, error) {
+	key := endpointKey(p)
+	if existing, ok := d.buffered[key]; ok {
+		merged, err := mergeUnionPaths(existing, p)
+		if err != nil {
+			return nil, err
+		}
+		d.buffered[key] = merged
+		return nil, nil
+	}
+
+	var evicted []Path
+	if len(d.order) >= d.window {
+		oldest := d.order[0]
+		d.order = d.order[1:]
+		evicted = append(evicted, d.buffered[oldest])
+		delete(d.buffered, oldest)
+	}
+
+	d.buffered[key] = p
+	d.order = append(d.order, key)
+	return evicted, nil
+}
+
+func (d *windowedDedup) flush() []Path {
+	out := make([]Path, 0, len(d.order))
+	for _, key := range d.order {
+		out = append(out, d.buffered[key])
+	}
+	return out
+}
+
+// selectUnionDedup chooses the dedup strategy for a union based on what the
+// planner knows about its children: windowed when every child guarantees
+// sorted output, exact otherwise. maxEndpoints caps the exact strategy's
+// memory, converting oversized result sets into ErrResultSetTooLarge.
+func selectUnionDedup(childrenSorted bool, window, maxEndpoints int) unionDedupStrategy {
+	if childrenSorted && window > 0 {
+		return newWindowedDedup(window)
+	}
+	return newExactDedup(maxEndpoints)
+}
@@ -0,0 +1,130 @@
+package query
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectUnionDedup(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	require.IsType(&windowedDedup{}, selectUnionDedup(true, 100, 0))
+	require.IsType(&exactDedup{}, selectUnionDedup(false, 100, 0))
+	require.IsType(&exactDedup{}, selectUnionDedup(true, 0, 0), "no window means no windowed mode")
+}
+
+func TestExactDedupCap(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	dedup := newExactDedup(2)
+	_, err := dedup.observe(MustPathFromString("document:doc1#viewer@user:alice"))
+	require.NoError(err)
+	_, err = dedup.observe(MustPathFromString("document:doc2#viewer@user:alice"))
+	require.NoError(err)
+
+	// Re-observing an endpoint merges rather than growing the buffer.
+	_, err = dedup.observe(MustPathFromString("document:doc1#viewer@user:alice"))
+	require.NoError(err)
+
+	_, err = dedup.observe(MustPathFromString("document:doc3#viewer@user:alice"))
+	require.ErrorIs(err, ErrResultSetTooLarge)
+}
+
+func TestWindowedDedup(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	t.Run("DedupesWithinWindow", func(t *testing.T) {
+		t.Parallel()
+
+		union := NewUnionIterator(
+			pathSeqOf(
+				MustPathFromString("document:doc1#viewer@user:alice"),
+				MustPathFromString("document:doc2#viewer@user:alice"),
+			),
+			pathSeqOf(
+				MustPathFromString("document:doc1#viewer@user:alice"),
+				MustPathFromString("document:doc3#viewer@user:alice"),
+			),
+		).WithWindowedDedup(16)
+
+		paths, err := CollectAll(union.IterPaths())
+		require.NoError(err)
+		require.Len(paths, 3)
+	})
+
+	t.Run("EvictsBeyondWindow", func(t *testing.T) {
+		t.Parallel()
+
+		dedup := newWindowedDedup(1)
+		evicted, err := dedup.observe(MustPathFromString("document:doc1#viewer@user:alice"))
+		require.NoError(err)
+		require.Empty(evicted)
+
+		evicted, err = dedup.observe(MustPathFromString("document:doc2#viewer@user:alice"))
+		require.NoError(err)
+		require.Len(evicted, 1, "a full window must evict the oldest endpoint")
+		require.Equal("doc1", evicted[0].Resource.ObjectID)
+
+		// The evicted endpoint is no longer tracked, so a duplicate now passes
+		// through — the documented tradeoff of the windowed mode.
+		evicted, err = dedup.observe(MustPathFromString("document:doc1#viewer@user:alice"))
+		require.NoError(err)
+		require.Len(evicted, 1)
+		require.Equal("doc2", evicted[0].Resource.ObjectID)
+		require.Len(dedup.flush(), 1)
+	})
+}
+
+func TestWindowedDedupFlatMemoryStress(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping 5M path stress test in short mode")
+	}
+
+	require := require.New(t)
+
+	const totalPaths = 5_000_000
+	const window = 1024
+
+	// Two identical sorted branches; the windowed interleave places duplicate
+	// endpoints adjacently, so the window catches every one of them.
+	branch := func(subject string) PathSeq {
+		return func(yield func(Path, error) bool) {
+			for i := range totalPaths / 2 {
+				path := MustPathFromString(fmt.Sprintf("document:doc%09d#viewer@user:%s", i, subject))
+				if !yield(path, nil) {
+					return
+				}
+			}
+		}
+	}
+
+	runtime.GC()
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	union := NewUnionIterator(branch("alice"), branch("alice")).WithWindowedDedup(window)
+
+	count := 0
+	for _, err := range union.IterPaths() {
+		require.NoError(err)
+		count++
+	}
+	require.Equal(totalPaths/2, count, "every cross-branch duplicate must be caught")
+
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+
+	// Heap growth must track the window, not the 5M paths streamed through.
+	growth := int64(after.HeapAlloc) - int64(before.HeapAlloc)
+	require.Less(growth, int64(64*1024*1024),
+		"windowed dedup must keep memory flat; heap grew by %d bytes", growth)
+}
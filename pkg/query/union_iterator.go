@@ -0,0 +1,300 @@
+package query
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// ErrResultSetTooLarge is returned when a buffering iterator would exceed its
+// configured cap on the number of distinct endpoints held in memory.
+var ErrResultSetTooLarge = errors.New("result set exceeds the configured buffer limit")
+
+// UnionIterator merges N child path sequences into a single sequence, combining
+// paths with equal endpoints via OR semantics so the caller never sees duplicate
+// endpoints.
+//
+// By default the iterator performs a streaming k-way merge and requires every
+// child to yield its paths in ComparePaths order; this keeps memory bounded by
+// the number of children. If the children cannot guarantee ordering, use
+// WithUnorderedBuffer to fall back to a per-endpoint buffer with a configurable
+// cap, beyond which the iterator fails with ErrResultSetTooLarge.
+type UnionIterator struct {
+	children    []PathSeq
+	unordered   bool
+	bufferCap   int
+	window      int
+	concurrency int
+	ctx         context.Context
+}
+
+// NewUnionIterator creates a UnionIterator over the given child sequences,
+// which must each yield paths in ComparePaths order.
+func NewUnionIterator(children ...PathSeq) *UnionIterator {
+	return &UnionIterator{children: children}
+}
+
+// WithUnorderedBuffer switches the iterator into buffering mode, which accepts
+// children yielding in any order at the cost of holding up to maxEndpoints
+// distinct endpoints in memory. A maxEndpoints of 0 means unlimited.
+func (u *UnionIterator) WithUnorderedBuffer(maxEndpoints int) *UnionIterator {
+	u.unordered = true
+	u.bufferCap = maxEndpoints
+	return u
+}
+
+// WithWindowedDedup switches the iterator into windowed streaming mode: the
+// children are concatenated and duplicate endpoints are detected only within a
+// sliding window of the last window endpoints, keeping memory flat regardless
+// of result size. Only sound when the planner knows the children yield in
+// sorted order; see windowedDedup.
+func (u *UnionIterator) WithWindowedDedup(window int) *UnionIterator {
+	u.window = window
+	return u
+}
+
+// WithConcurrency evaluates the children on a bounded worker pool of n workers
+// before merging, overlapping their datastore latency. The given context
+// governs the pool: cancelling it stops all branches, and a failing branch
+// cancels its siblings. Branch results are kept indexed by declaration order,
+// so the merged output is identical to sequential evaluation.
+func (u *UnionIterator) WithConcurrency(ctx context.Context, n int) *UnionIterator {
+	u.concurrency = n
+	u.ctx = ctx
+	return u
+}
+
+// IterPaths returns the merged sequence of paths.
+func (u *UnionIterator) IterPaths() PathSeq {
+	if u.concurrency > 1 && len(u.children) > 1 {
+		return func(yield func(Path, error) bool) {
+			results, err := collectBranchesConcurrently(u.ctx, u.concurrency, u.children)
+			if err != nil {
+				yield(Path{}, err)
+				return
+			}
+			prefetched := *u
+			prefetched.children = replayBranches(results)
+			prefetched.concurrency = 0
+			prefetched.IterPaths()(yield)
+		}
+	}
+
+	switch {
+	case u.window > 0:
+		return u.iterWindowed(newWindowedDedup(u.window))
+	case u.unordered:
+		return u.iterBuffered(newExactDedup(u.bufferCap))
+	default:
+		return u.iterOrdered()
+	}
+}
+
+// mergeUnionPaths merges two paths with equal endpoints using OR semantics.
+// Unlike Path.MergeOr, expiration here takes the *latest* of the two: under a
+// union the result remains valid for as long as any branch remains valid, and
+// no expiration on either side wins outright.
+func mergeUnionPaths(a, b Path) (Path, error) {
+	merged, err := a.MergeOr(b)
+	if err != nil {
+		return Path{}, err
+	}
+	switch {
+	case a.Expiration == nil || b.Expiration == nil:
+		merged.Expiration = nil
+	case a.Expiration.After(*b.Expiration):
+		merged.Expiration = a.Expiration
+	default:
+		merged.Expiration = b.Expiration
+	}
+	return merged, nil
+}
+
+// endpointKey builds a map key covering exactly the fields ComparePaths orders by.
+func endpointKey(p Path) string {
+	return fmt.Sprintf("%s:%s@%s:%s#%s", p.Resource.ObjectType, p.Resource.ObjectID,
+		p.Subject.ObjectType, p.Subject.ObjectID, p.Subject.Relation)
+}
+
+type pathHeapEntry struct {
+	path  Path
+	child int
+}
+
+type pathHeap []pathHeapEntry
+
+func (h pathHeap) Len() int           { return len(h) }
+func (h pathHeap) Less(i, j int) bool { return ComparePaths(h[i].path, h[j].path) < 0 }
+func (h pathHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *pathHeap) Push(x any)        { *h = append(*h, x.(pathHeapEntry)) }
+func (h *pathHeap) Pop() any {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	*h = old[:n-1]
+	return entry
+}
+
+func (u *UnionIterator) iterOrdered() PathSeq {
+	return func(yield func(Path, error) bool) {
+		pulls := make([]func() (Path, error, bool), len(u.children))
+		lasts := make([]*Path, len(u.children))
+		for i, child := range u.children {
+			pull, stop := iter.Pull2(iter.Seq2[Path, error](child))
+			defer stop()
+			pulls[i] = pull
+		}
+
+		h := &pathHeap{}
+
+		// advance pulls the next path from child i onto the heap, validating
+		// that the child respects ComparePaths order. It returns false if the
+		// overall iteration must terminate.
+		advance := func(i int) bool {
+			p, err, ok := pulls[i]()
+			if !ok {
+				return true
+			}
+			if err != nil {
+				yield(Path{}, err)
+				return false
+			}
+			if last := lasts[i]; last != nil && ComparePaths(*last, p) > 0 {
+				yield(Path{}, fmt.Errorf("union child %d yielded paths out of ComparePaths order", i))
+				return false
+			}
+			cp := p
+			lasts[i] = &cp
+			heap.Push(h, pathHeapEntry{path: p, child: i})
+			return true
+		}
+
+		for i := range u.children {
+			if !advance(i) {
+				return
+			}
+		}
+
+		for h.Len() > 0 {
+			entry := heap.Pop(h).(pathHeapEntry)
+			merged := entry.path
+			if !advance(entry.child) {
+				return
+			}
+
+			// Fold in every other head with the same endpoints before yielding.
+			for h.Len() > 0 && ComparePaths((*h)[0].path, merged) == 0 {
+				next := heap.Pop(h).(pathHeapEntry)
+				m, err := mergeUnionPaths(merged, next.path)
+				if err != nil {
+					yield(Path{}, err)
+					return
+				}
+				merged = m
+				if !advance(next.child) {
+					return
+				}
+			}
+
+			if !yield(merged, nil) {
+				return
+			}
+		}
+	}
+}
+
+// iterWindowed interleaves the children by ComparePaths order (without the
+// ordered mode's validation or exact inline merging) and routes the combined
+// stream through the windowed dedup strategy. With sorted children, duplicate
+// endpoints become adjacent in the interleaved stream and any window catches
+// them; evictions stream out as they happen, keeping memory flat.
+func (u *UnionIterator) iterWindowed(dedup unionDedupStrategy) PathSeq {
+	return func(yield func(Path, error) bool) {
+		pulls := make([]func() (Path, error, bool), len(u.children))
+		for i, child := range u.children {
+			pull, stop := iter.Pull2(iter.Seq2[Path, error](child))
+			defer stop()
+			pulls[i] = pull
+		}
+
+		h := &pathHeap{}
+
+		advance := func(i int) bool {
+			p, err, ok := pulls[i]()
+			if !ok {
+				return true
+			}
+			if err != nil {
+				yield(Path{}, err)
+				return false
+			}
+			heap.Push(h, pathHeapEntry{path: p, child: i})
+			return true
+		}
+
+		for i := range u.children {
+			if !advance(i) {
+				return
+			}
+		}
+
+		for h.Len() > 0 {
+			entry := heap.Pop(h).(pathHeapEntry)
+			if !advance(entry.child) {
+				return
+			}
+
+			evicted, err := dedup.observe(entry.path)
+			if err != nil {
+				yield(Path{}, err)
+				return
+			}
+			for _, e := range evicted {
+				if !yield(e, nil) {
+					return
+				}
+			}
+		}
+
+		for _, p := range dedup.flush() {
+			if !yield(p, nil) {
+				return
+			}
+		}
+	}
+}
+
+// iterBuffered concatenates the children and routes every path through the
+// given dedup strategy, yielding evictions as they happen and flushing the
+// remainder once the children are exhausted.
+func (u *UnionIterator) iterBuffered(dedup unionDedupStrategy) PathSeq {
+	return func(yield func(Path, error) bool) {
+		for _, child := range u.children {
+			for p, err := range child {
+				if err != nil {
+					yield(Path{}, err)
+					return
+				}
+
+				evicted, err := dedup.observe(p)
+				if err != nil {
+					yield(Path{}, err)
+					return
+				}
+				for _, e := range evicted {
+					if !yield(e, nil) {
+						return
+					}
+				}
+			}
+		}
+
+		for _, p := range dedup.flush() {
+			if !yield(p, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+// pathSeqOf builds an error-free PathSeq over the given paths, in order.
+func pathSeqOf(paths ...Path) PathSeq {
+	return func(yield func(Path, error) bool) {
+		for _, p := range paths {
+			if !yield(p, nil) {
+				return
+			}
+		}
+	}
+}
+
+// erroringPathSeq yields the given paths and then fails with err.
+func erroringPathSeq(err error, paths ...Path) PathSeq {
+	return func(yield func(Path, error) bool) {
+		for _, p := range paths {
+			if !yield(p, nil) {
+				return
+			}
+		}
+		yield(Path{}, err)
+	}
+}
+
+func caveatedPath(relationship, caveatName string) Path {
+	path := MustPathFromString(relationship)
+	path.Caveat = &core.CaveatExpression{
+		OperationOrCaveat: &core.CaveatExpression_Caveat{
+			Caveat: &core.ContextualizedCaveat{
+				CaveatName: caveatName,
+			},
+		},
+	}
+	return path
+}
+
+func TestUnionIteratorStreamingMerge(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	t.Run("MergesDuplicateEndpoints", func(t *testing.T) {
+		t.Parallel()
+
+		left := pathSeqOf(
+			MustPathFromString("document:doc1#viewer@user:alice"),
+			MustPathFromString("document:doc2#viewer@user:alice"),
+		)
+		right := pathSeqOf(
+			MustPathFromString("document:doc1#editor@user:alice"),
+			MustPathFromString("document:doc3#viewer@user:alice"),
+		)
+
+		paths, err := CollectAll(NewUnionIterator(left, right).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 3, "equal endpoints must be merged into a single path")
+		require.Equal("doc1", paths[0].Resource.ObjectID)
+		require.Equal("doc2", paths[1].Resource.ObjectID)
+		require.Equal("doc3", paths[2].Resource.ObjectID)
+	})
+
+	t.Run("CaveatsAreORed", func(t *testing.T) {
+		t.Parallel()
+
+		left := pathSeqOf(caveatedPath("document:doc1#viewer@user:alice", "caveat1"))
+		right := pathSeqOf(caveatedPath("document:doc1#viewer@user:alice", "caveat2"))
+
+		paths, err := CollectAll(NewUnionIterator(left, right).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1)
+
+		caveat := paths[0].Caveat
+		require.NotNil(caveat, "merged path should carry the combined caveat")
+		require.NotNil(caveat.GetOperation())
+		require.Equal(core.CaveatOperation_OR, caveat.GetOperation().Op)
+		require.Len(caveat.GetOperation().GetChildren(), 2)
+	})
+
+	t.Run("UncaveatedBranchWins", func(t *testing.T) {
+		t.Parallel()
+
+		left := pathSeqOf(caveatedPath("document:doc1#viewer@user:alice", "caveat1"))
+		right := pathSeqOf(MustPathFromString("document:doc1#viewer@user:alice"))
+
+		paths, err := CollectAll(NewUnionIterator(left, right).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1)
+		require.Nil(paths[0].Caveat, "an unconditional branch makes the union unconditional")
+	})
+
+	t.Run("ExpirationTakesMax", func(t *testing.T) {
+		t.Parallel()
+
+		earlier := time.Now().Add(time.Hour)
+		later := time.Now().Add(2 * time.Hour)
+
+		pathEarlier := MustPathFromString("document:doc1#viewer@user:alice")
+		pathEarlier.Expiration = &earlier
+		pathLater := MustPathFromString("document:doc1#viewer@user:alice")
+		pathLater.Expiration = &later
+
+		paths, err := CollectAll(NewUnionIterator(pathSeqOf(pathEarlier), pathSeqOf(pathLater)).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1)
+		require.NotNil(paths[0].Expiration)
+		require.Equal(later, *paths[0].Expiration, "union keeps the latest expiration")
+	})
+
+	t.Run("NoExpirationWinsOverExpiration", func(t *testing.T) {
+		t.Parallel()
+
+		expiring := MustPathFromString("document:doc1#viewer@user:alice")
+		expiration := time.Now().Add(time.Hour)
+		expiring.Expiration = &expiration
+
+		paths, err := CollectAll(NewUnionIterator(
+			pathSeqOf(expiring),
+			pathSeqOf(MustPathFromString("document:doc1#viewer@user:alice")),
+		).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1)
+		require.Nil(paths[0].Expiration, "a branch without expiration keeps the union alive indefinitely")
+	})
+
+	t.Run("ChildErrorMidStream", func(t *testing.T) {
+		t.Parallel()
+
+		childErr := errors.New("child failed")
+		left := pathSeqOf(
+			MustPathFromString("document:doc1#viewer@user:alice"),
+			MustPathFromString("document:doc2#viewer@user:alice"),
+		)
+		right := erroringPathSeq(childErr, MustPathFromString("document:doc1#viewer@user:alice"))
+
+		_, err := CollectAll(NewUnionIterator(left, right).IterPaths())
+		require.ErrorIs(err, childErr)
+	})
+
+	t.Run("OutOfOrderChildIsRejected", func(t *testing.T) {
+		t.Parallel()
+
+		outOfOrder := pathSeqOf(
+			MustPathFromString("document:doc2#viewer@user:alice"),
+			MustPathFromString("document:doc1#viewer@user:alice"),
+		)
+
+		_, err := CollectAll(NewUnionIterator(outOfOrder).IterPaths())
+		require.Error(err)
+		require.Contains(err.Error(), "out of ComparePaths order")
+	})
+
+	t.Run("EmptyChildren", func(t *testing.T) {
+		t.Parallel()
+
+		paths, err := CollectAll(NewUnionIterator(EmptyPathSeq(), EmptyPathSeq()).IterPaths())
+		require.NoError(err)
+		require.Empty(paths)
+	})
+}
+
+func TestUnionIteratorUnorderedBuffer(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	t.Run("MergesOutOfOrderChildren", func(t *testing.T) {
+		t.Parallel()
+
+		left := pathSeqOf(
+			MustPathFromString("document:doc2#viewer@user:alice"),
+			MustPathFromString("document:doc1#viewer@user:alice"),
+		)
+		right := pathSeqOf(MustPathFromString("document:doc1#editor@user:alice"))
+
+		paths, err := CollectAll(NewUnionIterator(left, right).WithUnorderedBuffer(0).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 2)
+
+		// Output remains in ComparePaths order regardless of input order.
+		require.Equal("doc1", paths[0].Resource.ObjectID)
+		require.Equal("doc2", paths[1].Resource.ObjectID)
+	})
+
+	t.Run("BufferCapExceeded", func(t *testing.T) {
+		t.Parallel()
+
+		child := pathSeqOf(
+			MustPathFromString("document:doc1#viewer@user:alice"),
+			MustPathFromString("document:doc2#viewer@user:alice"),
+			MustPathFromString("document:doc3#viewer@user:alice"),
+		)
+
+		_, err := CollectAll(NewUnionIterator(child).WithUnorderedBuffer(2).IterPaths())
+		require.ErrorIs(err, ErrResultSetTooLarge)
+	})
+
+	t.Run("MergingDoesNotCountAgainstCap", func(t *testing.T) {
+		t.Parallel()
+
+		child := pathSeqOf(
+			MustPathFromString("document:doc1#viewer@user:alice"),
+			MustPathFromString("document:doc1#editor@user:alice"),
+			MustPathFromString("document:doc1#owner@user:alice"),
+		)
+
+		paths, err := CollectAll(NewUnionIterator(child).WithUnorderedBuffer(1).IterPaths())
+		require.NoError(err)
+		require.Len(paths, 1, "duplicate endpoints share a single buffer slot")
+	})
+}
@@ -158,13 +158,16 @@ func TestUnionIterator(t *testing.T) {
 		})
 	})
 
-	t.Run("IterResources_Unimplemented", func(t *testing.T) {
+	t.Run("IterResources_Empty", func(t *testing.T) {
 		t.Parallel()
 
 		union := NewUnion()
-		require.Panics(func() {
-			_, _ = ctx.IterResources(union, NewObject("user", "alice").WithEllipses())
-		})
+		pathSeq, err := ctx.IterResources(union, NewObject("user", "alice").WithEllipses())
+		require.NoError(err)
+
+		rels, err := CollectAll(pathSeq)
+		require.NoError(err)
+		require.Empty(rels, "a union with no sub-iterators has no resources")
 	})
 }
 
@@ -0,0 +1,207 @@
+package schema
+
+import (
+	"sort"
+	"strings"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+// Aliases returns a map from each permission in the definition that is a pure
+// alias of another relation or permission to its terminal target, with chains
+// of aliases (edit -> other_edit -> owner) resolved to the final target. A
+// permission is considered an alias if it *directly* refers to another
+// permission or relation without any other form of expression, after
+// deduplicating structurally-identical operands (so `viewer & viewer` aliases
+// `viewer`).
+//
+// The map is computed lazily on first use and memoized.
+func (vdef *ValidatedDefinition) Aliases() (map[string]string, error) {
+	vdef.aliasesOnce.Do(func() {
+		vdef.aliases, vdef.aliasesErr = computePermissionAliases(vdef)
+	})
+	return vdef.aliases, vdef.aliasesErr
+}
+
+// AliasOf returns the terminal relation or permission aliased by the given
+// permission, if any. Returns false if the relation is not an alias or if the
+// alias map could not be computed due to a cycle amongst the permissions.
+func (vdef *ValidatedDefinition) AliasOf(relationName string) (string, bool) {
+	aliases, err := vdef.Aliases()
+	if err != nil {
+		return "", false
+	}
+
+	target, ok := aliases[relationName]
+	return target, ok
+}
+
+// computePermissionAliases computes a map of aliases between the various permissions in a
+// namespace. A permission is considered an alias if it *directly* refers to another permission
+// or relation without any other form of expression.
+func computePermissionAliases(typeDefinition *ValidatedDefinition) (map[string]string, error) {
+	aliases := map[string]string{}
+	done := map[string]struct{}{}
+	unresolvedAliases := map[string]string{}
+
+	for _, rel := range typeDefinition.Namespace().Relation {
+		aliasedPermOrRel, ok := directAliasTarget(rel)
+		if !ok {
+			done[rel.Name] = struct{}{}
+			continue
+		}
+
+		// If the aliased item is a relation, then we've found the alias target.
+		if !typeDefinition.IsPermission(aliasedPermOrRel) {
+			done[rel.Name] = struct{}{}
+			aliases[rel.Name] = aliasedPermOrRel
+			continue
+		}
+
+		// Otherwise, add the permission to the working set.
+		unresolvedAliases[rel.Name] = aliasedPermOrRel
+	}
+
+	for len(unresolvedAliases) > 0 {
+		startingCount := len(unresolvedAliases)
+		for relName, aliasedPermission := range unresolvedAliases {
+			if _, ok := done[aliasedPermission]; ok {
+				done[relName] = struct{}{}
+
+				if alias, ok := aliases[aliasedPermission]; ok {
+					aliases[relName] = alias
+				} else {
+					aliases[relName] = aliasedPermission
+				}
+				delete(unresolvedAliases, relName)
+				continue
+			}
+		}
+		if len(unresolvedAliases) == startingCount {
+			keys := make([]string, 0, len(unresolvedAliases))
+			for key := range unresolvedAliases {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			return nil, NewPermissionsCycleErr(typeDefinition.Namespace().Name, keys)
+		}
+	}
+
+	return aliases, nil
+}
+
+// directAliasTarget returns the relation or permission directly aliased by the given relation,
+// if any. A relation is a direct alias if its rewrite is a union or intersection whose operands,
+// after deduplicating structurally-identical operands (both have set semantics, so `viewer & viewer`
+// is `viewer`), reduce to a single computed userset. Exclusions are never aliases: their operands
+// are order-sensitive and are left untouched.
+func directAliasTarget(rel *core.Relation) (string, bool) {
+	rewrite := rel.GetUsersetRewrite()
+	if rewrite == nil {
+		return "", false
+	}
+
+	var operation *core.SetOperation
+	switch {
+	case rewrite.GetUnion() != nil:
+		operation = rewrite.GetUnion()
+
+	case rewrite.GetIntersection() != nil:
+		operation = rewrite.GetIntersection()
+
+	default:
+		return "", false
+	}
+
+	distinct := dedupedOperands(operation)
+	if len(distinct) != 1 {
+		return "", false
+	}
+
+	computedUserset := distinct[0].GetComputedUserset()
+	if computedUserset == nil {
+		return "", false
+	}
+
+	return computedUserset.GetRelation(), true
+}
+
+// dedupedOperands returns the operands of the given set operation with structurally-identical
+// operands collapsed, preserving the order of first occurrence.
+func dedupedOperands(operation *core.SetOperation) []*core.SetOperation_Child {
+	seen := map[string]struct{}{}
+	distinct := make([]*core.SetOperation_Child, 0, len(operation.Child))
+	for _, child := range operation.Child {
+		form := canonicalOperandForm(child)
+		if _, ok := seen[form]; ok {
+			continue
+		}
+		seen[form] = struct{}{}
+		distinct = append(distinct, child)
+	}
+	return distinct
+}
+
+// canonicalOperandForm returns a stable string form for a set operation operand, used to
+// recognize structurally-identical operands. Operands of nested unions and intersections are
+// themselves deduplicated and sorted (set semantics), so `(a + b)` and `(b + a)` share a form,
+// while exclusion operands keep their order.
+func canonicalOperandForm(child *core.SetOperation_Child) string {
+	switch ct := child.ChildType.(type) {
+	case *core.SetOperation_Child_ComputedUserset:
+		return "computed:" + ct.ComputedUserset.GetRelation()
+
+	case *core.SetOperation_Child_TupleToUserset:
+		return "arrow:" + ct.TupleToUserset.GetTupleset().GetRelation() + "->" + ct.TupleToUserset.GetComputedUserset().GetRelation()
+
+	case *core.SetOperation_Child_FunctionedTupleToUserset:
+		return "arrow:" + ct.FunctionedTupleToUserset.GetTupleset().GetRelation() + "-(" + ct.FunctionedTupleToUserset.GetFunction().String() + ")->" + ct.FunctionedTupleToUserset.GetComputedUserset().GetRelation()
+
+	case *core.SetOperation_Child_UsersetRewrite:
+		return canonicalRewriteForm(ct.UsersetRewrite)
+
+	case *core.SetOperation_Child_XNil:
+		return "nil"
+
+	case *core.SetOperation_Child_XThis:
+		return "this"
+
+	default:
+		// Unknown child kinds are kept distinct by their string representation.
+		return "unknown:" + child.String()
+	}
+}
+
+// canonicalRewriteForm returns the canonical operand form for a nested rewrite.
+func canonicalRewriteForm(rewrite *core.UsersetRewrite) string {
+	formsFor := func(operation *core.SetOperation) []string {
+		forms := make([]string, 0, len(operation.Child))
+		for _, child := range dedupedOperands(operation) {
+			forms = append(forms, canonicalOperandForm(child))
+		}
+		return forms
+	}
+
+	switch {
+	case rewrite.GetUnion() != nil:
+		forms := formsFor(rewrite.GetUnion())
+		sort.Strings(forms)
+		return "union(" + strings.Join(forms, ",") + ")"
+
+	case rewrite.GetIntersection() != nil:
+		forms := formsFor(rewrite.GetIntersection())
+		sort.Strings(forms)
+		return "intersection(" + strings.Join(forms, ",") + ")"
+
+	case rewrite.GetExclusion() != nil:
+		exclusion := rewrite.GetExclusion()
+		forms := make([]string, 0, len(exclusion.Child))
+		for _, child := range exclusion.Child {
+			forms = append(forms, canonicalOperandForm(child))
+		}
+		return "exclusion(" + strings.Join(forms, ",") + ")"
+
+	default:
+		return "unknown:" + rewrite.String()
+	}
+}
@@ -0,0 +1,113 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ns "github.com/authzed/spicedb/pkg/namespace"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+func TestAliases(t *testing.T) {
+	testCases := []struct {
+		name            string
+		toCheck         *core.NamespaceDefinition
+		expectedAliases map[string]string
+		expectedError   string
+	}{
+		{
+			"no aliases",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("owner", nil),
+				ns.MustRelation("viewer", nil),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("owner"),
+				)),
+			),
+			map[string]string{},
+			"",
+		},
+		{
+			"alias of a relation",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("owner", nil),
+				ns.MustRelation("edit", ns.Union(
+					ns.ComputedUserset("owner"),
+				)),
+			),
+			map[string]string{"edit": "owner"},
+			"",
+		},
+		{
+			"nested aliases resolve to the terminal target",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("owner", nil),
+				ns.MustRelation("other_edit", ns.Union(
+					ns.ComputedUserset("owner"),
+				)),
+				ns.MustRelation("edit", ns.Union(
+					ns.ComputedUserset("other_edit"),
+				)),
+			),
+			map[string]string{
+				"other_edit": "owner",
+				"edit":       "owner",
+			},
+			"",
+		},
+		{
+			"aliasing cycle",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("first", ns.Union(
+					ns.ComputedUserset("second"),
+				)),
+				ns.MustRelation("second", ns.Union(
+					ns.ComputedUserset("first"),
+				)),
+			),
+			nil,
+			"under definition `document`, there exists a cycle in permissions: document#first -> document#second -> document#first",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			ctx := t.Context()
+			ts := NewTypeSystem(ResolverForPredefinedDefinitions(PredefinedElements{
+				Definitions: []*core.NamespaceDefinition{tc.toCheck},
+			}))
+
+			def, err := NewDefinition(ts, tc.toCheck)
+			require.NoError(err)
+
+			vdef, err := def.Validate(ctx)
+			if tc.expectedError != "" {
+				// Permission cycles are reported by validation, with the full cycle path.
+				require.EqualError(err, tc.expectedError)
+				return
+			}
+			require.NoError(err)
+
+			aliases, err := vdef.Aliases()
+			require.NoError(err)
+			require.Equal(tc.expectedAliases, aliases)
+
+			for aliasName, expectedTarget := range tc.expectedAliases {
+				target, ok := vdef.AliasOf(aliasName)
+				require.True(ok)
+				require.Equal(expectedTarget, target)
+			}
+
+			_, ok := vdef.AliasOf("owner")
+			require.False(ok, "a non-alias relation must not report an alias target")
+		})
+	}
+}
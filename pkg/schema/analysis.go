@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"github.com/authzed/spicedb/pkg/genutil/mapz"
+	"github.com/authzed/spicedb/pkg/graph"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/tuple"
+)
+
+// UnreferencedRelations returns, in declaration order, the names of relations in the definition
+// that no permission in the *same* definition references in its userset rewrite. References via
+// arrows count for both the tupleset relation and the computed relation, as do references to the
+// relation as the subject relation of another relation's allowed types (e.g. `document#banned`).
+//
+// Note that a relation returned here may still be referenced from *other* definitions, such as
+// by an arrow walking into this definition; callers surfacing these as warnings should say so.
+func (def *Definition) UnreferencedRelations() ([]string, error) {
+	referenced := mapz.NewSet[string]()
+	for _, rel := range def.nsDef.Relation {
+		_, err := graph.WalkRewrite(rel.GetUsersetRewrite(), func(childOneof *core.SetOperation_Child) (any, error) {
+			switch child := childOneof.ChildType.(type) {
+			case *core.SetOperation_Child_ComputedUserset:
+				referenced.Add(child.ComputedUserset.GetRelation())
+
+			case *core.SetOperation_Child_TupleToUserset:
+				referenced.Add(child.TupleToUserset.GetTupleset().GetRelation())
+				referenced.Add(child.TupleToUserset.GetComputedUserset().GetRelation())
+
+			case *core.SetOperation_Child_FunctionedTupleToUserset:
+				referenced.Add(child.FunctionedTupleToUserset.GetTupleset().GetRelation())
+				referenced.Add(child.FunctionedTupleToUserset.GetComputedUserset().GetRelation())
+			}
+			return nil, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, allowed := range rel.GetTypeInformation().GetAllowedDirectRelations() {
+			if allowed.Namespace == def.nsDef.Name && allowed.GetRelation() != tuple.Ellipsis {
+				referenced.Add(allowed.GetRelation())
+			}
+		}
+	}
+
+	unreferenced := make([]string, 0)
+	for _, rel := range def.nsDef.Relation {
+		if def.IsPermission(rel.Name) {
+			continue
+		}
+
+		if !referenced.Has(rel.Name) {
+			unreferenced = append(unreferenced, rel.Name)
+		}
+	}
+	return unreferenced, nil
+}
+
+// RelationsWithoutAllowedSubjectTypes returns, in declaration order, the names of relations in
+// the definition that have no allowed subject types, meaning no relationship can ever be written
+// for them.
+func (def *Definition) RelationsWithoutAllowedSubjectTypes() []string {
+	found := make([]string, 0)
+	for _, rel := range def.nsDef.Relation {
+		if def.IsPermission(rel.Name) {
+			continue
+		}
+
+		if len(rel.GetTypeInformation().GetAllowedDirectRelations()) == 0 {
+			found = append(found, rel.Name)
+		}
+	}
+	return found
+}
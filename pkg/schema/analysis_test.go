@@ -0,0 +1,137 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ns "github.com/authzed/spicedb/pkg/namespace"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+func TestUnreferencedRelations(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		toCheck              *core.NamespaceDefinition
+		expectedUnreferenced []string
+		expectedEmptyTypes   []string
+	}{
+		{
+			"all relations referenced",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("viewer", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+				)),
+			),
+			[]string{},
+			[]string{},
+		},
+		{
+			"unreferenced relation",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("viewer", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("unused", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+				)),
+			),
+			[]string{"unused"},
+			[]string{},
+		},
+		{
+			"relations referenced via an arrow",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("parent", nil, ns.AllowedRelation("folder", "...")),
+				ns.MustRelation("viewer", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+					ns.TupleToUserset("parent", "view"),
+				)),
+			),
+			[]string{},
+			[]string{},
+		},
+		{
+			"relations referenced via a functioned arrow",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("parent", nil, ns.AllowedRelation("folder", "...")),
+				ns.MustRelation("view", ns.Union(
+					ns.MustFunctionedTupleToUserset("parent", "all", "view"),
+				)),
+			),
+			[]string{},
+			[]string{},
+		},
+		{
+			"relations referenced in nested rewrites",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("owner", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("editor", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("banned", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("owner"),
+					ns.Rewrite(ns.Exclusion(
+						ns.ComputedUserset("editor"),
+						ns.ComputedUserset("banned"),
+					)),
+				)),
+			),
+			[]string{},
+			[]string{},
+		},
+		{
+			"relation referenced only as a subject relation within the definition",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("banned", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("viewer", nil,
+					ns.AllowedRelation("user", "..."),
+					ns.AllowedRelation("document", "banned"),
+				),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+				)),
+			),
+			[]string{},
+			[]string{},
+		},
+		{
+			"relation without allowed subject types",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("orphan", nil),
+				ns.MustRelation("viewer", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+				)),
+			),
+			[]string{"orphan"},
+			[]string{"orphan"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			ts := NewTypeSystem(ResolverForPredefinedDefinitions(PredefinedElements{
+				Definitions: []*core.NamespaceDefinition{tc.toCheck},
+			}))
+
+			def, err := NewDefinition(ts, tc.toCheck)
+			require.NoError(err)
+
+			unreferenced, err := def.UnreferencedRelations()
+			require.NoError(err)
+			require.Equal(tc.expectedUnreferenced, unreferenced)
+
+			require.Equal(tc.expectedEmptyTypes, def.RelationsWithoutAllowedSubjectTypes())
+		})
+	}
+}
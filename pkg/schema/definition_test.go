@@ -409,6 +409,92 @@ func TestDefinition(t *testing.T) {
 			},
 			"",
 		},
+		{
+			"cycle between two permissions",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("viewer", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("first", ns.Union(
+					ns.ComputedUserset("second"),
+					ns.ComputedUserset("viewer"),
+				)),
+				ns.MustRelation("second", ns.Union(
+					ns.ComputedUserset("first"),
+					ns.ComputedUserset("viewer"),
+				)),
+			),
+			[]*core.NamespaceDefinition{
+				ns.Namespace("user"),
+			},
+			nil,
+			"under definition `document`, there exists a cycle in permissions: document#first -> document#second -> document#first",
+		},
+		{
+			"cycle across four permissions",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("viewer", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("first", ns.Union(
+					ns.ComputedUserset("second"),
+					ns.ComputedUserset("viewer"),
+				)),
+				ns.MustRelation("second", ns.Union(
+					ns.ComputedUserset("third"),
+					ns.ComputedUserset("viewer"),
+				)),
+				ns.MustRelation("third", ns.Union(
+					ns.ComputedUserset("fourth"),
+					ns.ComputedUserset("viewer"),
+				)),
+				ns.MustRelation("fourth", ns.Union(
+					ns.ComputedUserset("first"),
+					ns.ComputedUserset("viewer"),
+				)),
+			),
+			[]*core.NamespaceDefinition{
+				ns.Namespace("user"),
+			},
+			nil,
+			"under definition `document`, there exists a cycle in permissions: document#first -> document#second -> document#third -> document#fourth -> document#first",
+		},
+		{
+			"cycle through an arrow over the same definition",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("parent", nil, ns.AllowedRelation("document", "...")),
+				ns.MustRelation("viewer", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("first", ns.Union(
+					ns.TupleToUserset("parent", "second"),
+					ns.ComputedUserset("viewer"),
+				)),
+				ns.MustRelation("second", ns.Union(
+					ns.ComputedUserset("first"),
+					ns.ComputedUserset("viewer"),
+				)),
+			),
+			[]*core.NamespaceDefinition{
+				ns.Namespace("user"),
+			},
+			nil,
+			"under definition `document`, there exists a cycle in permissions: document#first -> document#second -> document#first",
+		},
+		{
+			"recursive arrow to the same permission is not a cycle",
+			ns.Namespace(
+				"folder",
+				ns.MustRelation("parent", nil, ns.AllowedRelation("folder", "...")),
+				ns.MustRelation("viewer", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+					ns.TupleToUserset("parent", "view"),
+				)),
+			),
+			[]*core.NamespaceDefinition{
+				ns.Namespace("user"),
+			},
+			nil,
+			"",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -453,6 +539,43 @@ func TestDefinition(t *testing.T) {
 	}
 }
 
+func TestValidateAggregatesErrors(t *testing.T) {
+	require := require.New(t)
+
+	ctx := t.Context()
+	ts := NewTypeSystem(ResolverForPredefinedDefinitions(PredefinedElements{
+		Definitions: []*core.NamespaceDefinition{
+			ns.Namespace("user"),
+			ns.Namespace(
+				"document",
+				ns.MustRelation("editor", nil, ns.AllowedRelation("someother", "...")),
+				ns.MustRelation("viewer", nil,
+					ns.AllowedRelation("user", "..."),
+					ns.AllowedRelation("user", "..."),
+				),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("undefined"),
+				)),
+			),
+		},
+	}))
+
+	def, err := ts.GetDefinition(ctx, "document")
+	require.NoError(err)
+
+	_, verr := def.Validate(ctx)
+	require.Error(verr)
+
+	joined, ok := verr.(interface{ Unwrap() []error })
+	require.True(ok, "expected a multi-error from Validate; got %T", verr)
+
+	validationErrors := joined.Unwrap()
+	require.Len(validationErrors, 3)
+	require.ErrorContains(validationErrors[0], "could not lookup definition `someother` for relation `editor`")
+	require.ErrorContains(validationErrors[1], "found duplicate allowed subject type `user` on relation `viewer` under definition `document`")
+	require.ErrorContains(validationErrors[2], "relation/permission `undefined` not found under definition `document`")
+}
+
 type tsTester func(t *testing.T, ts *ValidatedDefinition)
 
 func noError[T any](result T, err error) T {
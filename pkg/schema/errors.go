@@ -199,6 +199,7 @@ type PermissionsCycleError struct {
 	error
 	definitionName  string
 	permissionNames []string
+	cyclePath       []string
 }
 
 // MarshalZerologObject implements zerolog object marshalling.
@@ -208,10 +209,14 @@ func (err PermissionsCycleError) MarshalZerologObject(e *zerolog.Event) {
 
 // DetailsMetadata returns the metadata for details for this error.
 func (err PermissionsCycleError) DetailsMetadata() map[string]string {
-	return map[string]string{
+	metadata := map[string]string{
 		"definition_name":  err.definitionName,
 		"permission_names": strings.Join(err.permissionNames, ","),
 	}
+	if len(err.cyclePath) > 0 {
+		metadata["cycle_path"] = strings.Join(err.cyclePath, " -> ")
+	}
+	return metadata
 }
 
 // DuplicateAllowedRelationError indicates that an allowed relation was redefined on a relation.
@@ -370,6 +375,17 @@ func NewPermissionsCycleErr(nsName string, permissionNames []string) error {
 	}
 }
 
+// NewPermissionsCyclePathErr constructs an error indicating that a cycle exists amongst
+// permissions, including the ordered list of `namespace#relation` hops forming the cycle.
+func NewPermissionsCyclePathErr(nsName string, permissionNames []string, cyclePath []string) error {
+	return PermissionsCycleError{
+		error:           fmt.Errorf("under definition `%s`, there exists a cycle in permissions: %s", nsName, strings.Join(cyclePath, " -> ")),
+		definitionName:  nsName,
+		permissionNames: permissionNames,
+		cyclePath:       cyclePath,
+	}
+}
+
 // NewUnusedCaveatParameterErr constructs indicating that a parameter was unused in a caveat expression.
 func NewUnusedCaveatParameterErr(caveatName string, paramName string) error {
 	return UnusedCaveatParameterError{
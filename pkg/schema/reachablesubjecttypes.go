@@ -0,0 +1,149 @@
+package schema
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/authzed/spicedb/pkg/genutil/mapz"
+	"github.com/authzed/spicedb/pkg/graph"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/tuple"
+)
+
+// ReachableSubjectType describes a subject type that can, directly or transitively, hold a
+// permission or relation on a definition.
+type ReachableSubjectType struct {
+	// SubjectType is the name of the subject's definition.
+	SubjectType string
+
+	// SubjectRelation is the relation on the subject's definition, if the subject was referenced
+	// as a sub-relation (e.g. `group#member`). Empty for terminal subjects.
+	SubjectRelation string
+
+	// IsWildcard indicates the subject type was referenced as a wildcard (e.g. `user:*`).
+	IsWildcard bool
+
+	// IsCaveated indicates the reference to this subject type requires a caveat.
+	IsCaveated bool
+}
+
+func (rst ReachableSubjectType) key() string {
+	return tuple.JoinRelRef(rst.SubjectType, rst.SubjectRelation) + "|" + strconv.FormatBool(rst.IsWildcard) + "|" + strconv.FormatBool(rst.IsCaveated)
+}
+
+// ReachableSubjectTypes returns the deduplicated set of subject types that can possibly hold the
+// given permission or relation, resolving computed usersets, set operations and arrows, including
+// those which cross into other definitions. Sub-relation subjects (e.g. `group#member`) are both
+// reported and followed, so the subjects reachable *through* them are reported as well. The
+// returned slice is sorted for stability.
+func (vd *ValidatedDefinition) ReachableSubjectTypes(ctx context.Context, relationName string) ([]ReachableSubjectType, error) {
+	if !vd.HasRelation(relationName) {
+		return nil, asTypeError(NewRelationNotFoundErr(vd.nsDef.Name, relationName))
+	}
+
+	found := mapz.NewSet[string]()
+	results := []ReachableSubjectType{}
+
+	visited := mapz.NewSet[string]()
+	toVisit := []nsAndRel{{Namespace: vd.nsDef.Name, Relation: relationName}}
+
+	for len(toVisit) > 0 {
+		current := toVisit[0]
+		toVisit = toVisit[1:]
+		if !visited.Add(tuple.JoinRelRef(current.Namespace, current.Relation)) {
+			continue
+		}
+
+		def, err := vd.ts.GetDefinition(ctx, current.Namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		relation, ok := def.GetRelation(current.Relation)
+		if !ok {
+			return nil, asTypeError(NewRelationNotFoundErr(current.Namespace, current.Relation))
+		}
+
+		// Collect the directly allowed subject types, following any sub-relation references.
+		for _, allowedRelation := range relation.GetTypeInformation().GetAllowedDirectRelations() {
+			entry := ReachableSubjectType{
+				SubjectType: allowedRelation.GetNamespace(),
+				IsCaveated:  allowedRelation.GetRequiredCaveat() != nil,
+			}
+
+			switch {
+			case allowedRelation.GetPublicWildcard() != nil:
+				entry.IsWildcard = true
+
+			case allowedRelation.GetRelation() != tuple.Ellipsis:
+				entry.SubjectRelation = allowedRelation.GetRelation()
+				toVisit = append(toVisit, nsAndRel{Namespace: allowedRelation.GetNamespace(), Relation: allowedRelation.GetRelation()})
+			}
+
+			if found.Add(entry.key()) {
+				results = append(results, entry)
+			}
+		}
+
+		// Walk the rewrite, following computed usersets and arrows.
+		_, err = graph.WalkRewrite(relation.GetUsersetRewrite(), func(childOneof *core.SetOperation_Child) (any, error) {
+			switch child := childOneof.ChildType.(type) {
+			case *core.SetOperation_Child_ComputedUserset:
+				toVisit = append(toVisit, nsAndRel{Namespace: current.Namespace, Relation: child.ComputedUserset.Relation})
+
+			case *core.SetOperation_Child_TupleToUserset:
+				targets, err := vd.arrowTargets(ctx, def, child.TupleToUserset.Tupleset.Relation, child.TupleToUserset.ComputedUserset.Relation)
+				if err != nil {
+					return nil, err
+				}
+				toVisit = append(toVisit, targets...)
+
+			case *core.SetOperation_Child_FunctionedTupleToUserset:
+				targets, err := vd.arrowTargets(ctx, def, child.FunctionedTupleToUserset.Tupleset.Relation, child.FunctionedTupleToUserset.ComputedUserset.Relation)
+				if err != nil {
+					return nil, err
+				}
+				toVisit = append(toVisit, targets...)
+			}
+			return nil, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].key() < results[j].key()
+	})
+	return results, nil
+}
+
+// arrowTargets resolves the computed userset relation of an arrow against each of the tupleset
+// relation's allowed subject definitions, skipping any definition on which the relation does
+// not exist.
+func (vd *ValidatedDefinition) arrowTargets(ctx context.Context, def *Definition, tuplesetRelation string, computedRelationName string) ([]nsAndRel, error) {
+	allowedRelations, err := def.AllowedDirectRelationsAndWildcards(tuplesetRelation)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]nsAndRel, 0, len(allowedRelations))
+	for _, allowedRelation := range allowedRelations {
+		if allowedRelation.GetPublicWildcard() != nil {
+			continue
+		}
+
+		targetDef, err := vd.ts.GetDefinition(ctx, allowedRelation.GetNamespace())
+		if err != nil {
+			return nil, err
+		}
+
+		if !targetDef.HasRelation(computedRelationName) {
+			continue
+		}
+
+		targets = append(targets, nsAndRel{Namespace: allowedRelation.GetNamespace(), Relation: computedRelationName})
+	}
+	return targets, nil
+}
@@ -0,0 +1,131 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/pkg/caveats"
+	ns "github.com/authzed/spicedb/pkg/namespace"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+func TestReachableSubjectTypes(t *testing.T) {
+	emptyEnv := caveats.NewEnvironmentWithDefaultTypeSet()
+
+	definitions := []*core.NamespaceDefinition{
+		ns.Namespace("user"),
+		ns.Namespace(
+			"group",
+			ns.MustRelation("direct_member", nil,
+				ns.AllowedRelation("user", "..."),
+				ns.AllowedPublicNamespace("user"),
+				ns.AllowedRelation("group", "member"),
+			),
+			ns.MustRelation("member", ns.Union(
+				ns.ComputedUserset("direct_member"),
+			)),
+		),
+		ns.Namespace(
+			"folder",
+			ns.MustRelation("parent", nil,
+				ns.AllowedRelation("folder", "..."),
+			),
+			ns.MustRelation("viewer", nil,
+				ns.AllowedRelationWithCaveat("user", "...", ns.AllowedCaveat("somecaveat")),
+			),
+			ns.MustRelation("view", ns.Union(
+				ns.ComputedUserset("viewer"),
+				ns.TupleToUserset("parent", "view"),
+			)),
+		),
+		ns.Namespace(
+			"document",
+			ns.MustRelation("folder", nil,
+				ns.AllowedRelation("folder", "..."),
+			),
+			ns.MustRelation("viewer", nil,
+				ns.AllowedRelation("user", "..."),
+				ns.AllowedRelation("group", "member"),
+			),
+			ns.MustRelation("view", ns.Union(
+				ns.ComputedUserset("viewer"),
+				ns.TupleToUserset("folder", "view"),
+			)),
+		),
+	}
+
+	testCases := []struct {
+		name          string
+		definition    string
+		relation      string
+		expected      []ReachableSubjectType
+		expectedError string
+	}{
+		{
+			"permission crossing definitions via arrows and sub-relations",
+			"document",
+			"view",
+			[]ReachableSubjectType{
+				{SubjectType: "group", SubjectRelation: "member"},
+				{SubjectType: "user"},
+				{SubjectType: "user", IsCaveated: true},
+				{SubjectType: "user", IsWildcard: true},
+			},
+			"",
+		},
+		{
+			"recursive permission terminates",
+			"folder",
+			"view",
+			[]ReachableSubjectType{
+				{SubjectType: "user", IsCaveated: true},
+			},
+			"",
+		},
+		{
+			"relation with sub-relation subject",
+			"group",
+			"direct_member",
+			[]ReachableSubjectType{
+				{SubjectType: "group", SubjectRelation: "member"},
+				{SubjectType: "user"},
+				{SubjectType: "user", IsWildcard: true},
+			},
+			"",
+		},
+		{
+			"unknown relation",
+			"document",
+			"unknown",
+			nil,
+			"relation/permission `unknown` not found under definition `document`",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			ctx := t.Context()
+			ts := NewTypeSystem(ResolverForPredefinedDefinitions(PredefinedElements{
+				Definitions: definitions,
+				Caveats: []*Caveat{
+					ns.MustCaveatDefinition(emptyEnv, "somecaveat", "1 == 1"),
+				},
+			}))
+
+			vdef, err := ts.GetValidatedDefinition(ctx, tc.definition)
+			require.NoError(err)
+
+			found, err := vdef.ReachableSubjectTypes(ctx, tc.relation)
+			if tc.expectedError != "" {
+				require.EqualError(err, tc.expectedError)
+				return
+			}
+
+			require.NoError(err)
+			require.Equal(tc.expected, found)
+		})
+	}
+}
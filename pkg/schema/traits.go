@@ -0,0 +1,191 @@
+package schema
+
+import (
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/spiceerrors"
+	"github.com/authzed/spicedb/pkg/tuple"
+)
+
+// PermissionTraits captures cheap structural facts about a relation or permission, for callers
+// (such as LookupResources and the query planner) that need to pick an evaluation strategy
+// without re-walking the expression proto each time.
+//
+// Traits account for nested rewrites and transitive references *within* the definition: a
+// permission whose operand references another local permission containing an exclusion is
+// itself marked as having an exclusion. References crossing into other definitions are not
+// expanded; HasArrows is set instead, so callers needing cross-definition facts (such as
+// wildcard reachability behind arrows) can fall back to a full reachability walk.
+type PermissionTraits struct {
+	// HasIntersection is true if an intersection appears anywhere in the expression or in a
+	// transitively-referenced local permission, forcing check-style verification.
+	HasIntersection bool
+
+	// HasExclusion is true if an exclusion appears anywhere in the expression or in a
+	// transitively-referenced local permission, forcing check-style verification.
+	HasExclusion bool
+
+	// HasArrows is true if the expression, or a transitively-referenced local permission,
+	// contains an arrow.
+	HasArrows bool
+
+	// CanHaveWildcardSubjects is true if a wildcard subject type is allowed on a relation
+	// transitively referenced within this definition.
+	CanHaveWildcardSubjects bool
+
+	// CaveatFree is true if no caveated subject type is allowed on any relation transitively
+	// referenced within this definition.
+	CaveatFree bool
+
+	// MaxRewriteDepth is the maximum nesting depth of rewrite expressions encountered when
+	// fully expanding local references; a plain relation has depth zero.
+	MaxRewriteDepth int
+}
+
+// PermissionTraits returns the traits for the given relation or permission. Traits for all
+// relations are computed on first use and memoized on the validated definition.
+func (vdef *ValidatedDefinition) PermissionTraits(relationName string) (PermissionTraits, error) {
+	vdef.traitsOnce.Do(func() {
+		vdef.traits, vdef.traitsErr = computePermissionTraits(vdef)
+	})
+	if vdef.traitsErr != nil {
+		return PermissionTraits{}, vdef.traitsErr
+	}
+
+	traits, ok := vdef.traits[relationName]
+	if !ok {
+		return PermissionTraits{}, asTypeError(NewRelationNotFoundErr(vdef.nsDef.Name, relationName))
+	}
+	return traits, nil
+}
+
+func computePermissionTraits(vdef *ValidatedDefinition) (map[string]PermissionTraits, error) {
+	computation := &traitsComputation{
+		vdef:     vdef.Definition,
+		traits:   make(map[string]PermissionTraits, len(vdef.nsDef.Relation)),
+		visiting: map[string]struct{}{},
+	}
+
+	for _, rel := range vdef.nsDef.Relation {
+		if _, err := computation.traitsFor(rel.Name); err != nil {
+			return nil, err
+		}
+	}
+	return computation.traits, nil
+}
+
+type traitsComputation struct {
+	vdef     *Definition
+	traits   map[string]PermissionTraits
+	visiting map[string]struct{}
+}
+
+func (tc *traitsComputation) traitsFor(relationName string) (PermissionTraits, error) {
+	if traits, ok := tc.traits[relationName]; ok {
+		return traits, nil
+	}
+
+	if _, ok := tc.visiting[relationName]; ok {
+		// A self-referencing permission (`permission view = view`) resolves to the empty set
+		// along that branch; it contributes nothing to the traits of its referents.
+		return PermissionTraits{CaveatFree: true}, nil
+	}
+	tc.visiting[relationName] = struct{}{}
+	defer delete(tc.visiting, relationName)
+
+	rel, ok := tc.vdef.GetRelation(relationName)
+	if !ok {
+		return PermissionTraits{}, spiceerrors.MustBugf("missing relation %s in traits computation", relationName)
+	}
+
+	var traits PermissionTraits
+	if rewrite := rel.GetUsersetRewrite(); rewrite != nil {
+		rewriteTraits, err := tc.rewriteTraits(rewrite)
+		if err != nil {
+			return PermissionTraits{}, err
+		}
+		traits = rewriteTraits
+	} else {
+		traits = PermissionTraits{CaveatFree: true}
+		for _, allowedRelation := range rel.GetTypeInformation().GetAllowedDirectRelations() {
+			if allowedRelation.GetPublicWildcard() != nil {
+				traits.CanHaveWildcardSubjects = true
+			}
+			if allowedRelation.GetRequiredCaveat() != nil {
+				traits.CaveatFree = false
+			}
+
+			// Sub-relation subjects within this same definition fold in the traits of the
+			// referenced relation.
+			if allowedRelation.GetNamespace() == tc.vdef.nsDef.Name &&
+				allowedRelation.GetPublicWildcard() == nil &&
+				allowedRelation.GetRelation() != tuple.Ellipsis {
+				subTraits, err := tc.traitsFor(allowedRelation.GetRelation())
+				if err != nil {
+					return PermissionTraits{}, err
+				}
+				traits = mergeTraits(traits, subTraits, 0)
+			}
+		}
+	}
+
+	tc.traits[relationName] = traits
+	return traits, nil
+}
+
+func (tc *traitsComputation) rewriteTraits(rewrite *core.UsersetRewrite) (PermissionTraits, error) {
+	traits := PermissionTraits{CaveatFree: true, MaxRewriteDepth: 1}
+
+	var children []*core.SetOperation_Child
+	switch rw := rewrite.RewriteOperation.(type) {
+	case *core.UsersetRewrite_Union:
+		children = rw.Union.Child
+	case *core.UsersetRewrite_Intersection:
+		traits.HasIntersection = true
+		children = rw.Intersection.Child
+	case *core.UsersetRewrite_Exclusion:
+		traits.HasExclusion = true
+		children = rw.Exclusion.Child
+	default:
+		return PermissionTraits{}, spiceerrors.MustBugf("unknown rewrite kind %T", rw)
+	}
+
+	for _, childOneof := range children {
+		switch child := childOneof.ChildType.(type) {
+		case *core.SetOperation_Child_ComputedUserset:
+			subTraits, err := tc.traitsFor(child.ComputedUserset.Relation)
+			if err != nil {
+				return PermissionTraits{}, err
+			}
+			traits = mergeTraits(traits, subTraits, 1+subTraits.MaxRewriteDepth)
+
+		case *core.SetOperation_Child_UsersetRewrite:
+			subTraits, err := tc.rewriteTraits(child.UsersetRewrite)
+			if err != nil {
+				return PermissionTraits{}, err
+			}
+			traits = mergeTraits(traits, subTraits, 1+subTraits.MaxRewriteDepth)
+
+		case *core.SetOperation_Child_TupleToUserset, *core.SetOperation_Child_FunctionedTupleToUserset:
+			traits.HasArrows = true
+
+		case *core.SetOperation_Child_XNil:
+			// Contributes nothing.
+
+		default:
+			return PermissionTraits{}, spiceerrors.MustBugf("unknown set operation child %T", child)
+		}
+	}
+	return traits, nil
+}
+
+// mergeTraits folds the traits of a referenced relation or nested expression into the traits of
+// its referent, with the referenced expression sitting at the given depth below the referent.
+func mergeTraits(traits PermissionTraits, subTraits PermissionTraits, depth int) PermissionTraits {
+	traits.HasIntersection = traits.HasIntersection || subTraits.HasIntersection
+	traits.HasExclusion = traits.HasExclusion || subTraits.HasExclusion
+	traits.HasArrows = traits.HasArrows || subTraits.HasArrows
+	traits.CanHaveWildcardSubjects = traits.CanHaveWildcardSubjects || subTraits.CanHaveWildcardSubjects
+	traits.CaveatFree = traits.CaveatFree && subTraits.CaveatFree
+	traits.MaxRewriteDepth = max(traits.MaxRewriteDepth, depth)
+	return traits
+}
@@ -0,0 +1,125 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/pkg/caveats"
+	ns "github.com/authzed/spicedb/pkg/namespace"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+func TestPermissionTraits(t *testing.T) {
+	emptyEnv := caveats.NewEnvironmentWithDefaultTypeSet()
+
+	document := ns.Namespace(
+		"document",
+		ns.MustRelation("owner", nil, ns.AllowedRelation("user", "...")),
+		ns.MustRelation("editor", nil, ns.AllowedRelation("user", "...")),
+		ns.MustRelation("viewer", nil,
+			ns.AllowedRelation("user", "..."),
+			ns.AllowedPublicNamespace("user"),
+			ns.AllowedRelationWithCaveat("user", "...", ns.AllowedCaveat("somecaveat")),
+		),
+		ns.MustRelation("banned", nil, ns.AllowedRelation("user", "...")),
+		ns.MustRelation("parent", nil, ns.AllowedRelation("folder", "...")),
+		ns.MustRelation("simple", ns.Union(
+			ns.ComputedUserset("owner"),
+			ns.ComputedUserset("editor"),
+		)),
+		ns.MustRelation("deep", ns.Union(
+			ns.ComputedUserset("owner"),
+			ns.Rewrite(ns.Intersection(
+				ns.ComputedUserset("editor"),
+				ns.Rewrite(ns.Exclusion(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("banned"),
+				)),
+			)),
+		)),
+		ns.MustRelation("indirect", ns.Union(
+			ns.ComputedUserset("deep"),
+		)),
+		ns.MustRelation("arrowed", ns.Union(
+			ns.ComputedUserset("owner"),
+			ns.TupleToUserset("parent", "view"),
+		)),
+	)
+
+	ts := NewTypeSystem(ResolverForPredefinedDefinitions(PredefinedElements{
+		Definitions: []*core.NamespaceDefinition{
+			ns.Namespace("user"),
+			ns.Namespace("folder"),
+			document,
+		},
+		Caveats: []*Caveat{
+			ns.MustCaveatDefinition(emptyEnv, "somecaveat", "1 == 1"),
+		},
+	}))
+
+	vdef, err := ts.GetValidatedDefinition(t.Context(), "document")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		relationName string
+		expected     PermissionTraits
+	}{
+		{
+			"owner",
+			PermissionTraits{CaveatFree: true},
+		},
+		{
+			"viewer",
+			PermissionTraits{CanHaveWildcardSubjects: true},
+		},
+		{
+			"simple",
+			PermissionTraits{CaveatFree: true, MaxRewriteDepth: 1},
+		},
+		{
+			"deep",
+			PermissionTraits{
+				HasIntersection:         true,
+				HasExclusion:            true,
+				CanHaveWildcardSubjects: true,
+				MaxRewriteDepth:         3,
+			},
+		},
+		{
+			"indirect",
+			PermissionTraits{
+				HasIntersection:         true,
+				HasExclusion:            true,
+				CanHaveWildcardSubjects: true,
+				MaxRewriteDepth:         4,
+			},
+		},
+		{
+			"arrowed",
+			PermissionTraits{HasArrows: true, CaveatFree: true, MaxRewriteDepth: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.relationName, func(t *testing.T) {
+			traits, err := vdef.PermissionTraits(tc.relationName)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, traits)
+		})
+	}
+
+	t.Run("unknown relation", func(t *testing.T) {
+		_, err := vdef.PermissionTraits("unknown")
+		require.ErrorContains(t, err, "relation/permission `unknown` not found")
+	})
+
+	t.Run("memoized across calls", func(t *testing.T) {
+		first, err := vdef.PermissionTraits("deep")
+		require.NoError(t, err)
+
+		second, err := vdef.PermissionTraits("deep")
+		require.NoError(t, err)
+		require.Equal(t, first, second)
+	})
+}
@@ -2,14 +2,17 @@ package schema
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
+	"sync"
 
 	"github.com/authzed/spicedb/pkg/genutil/mapz"
 	"github.com/authzed/spicedb/pkg/graph"
 	nspkg "github.com/authzed/spicedb/pkg/namespace"
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 	iv1 "github.com/authzed/spicedb/pkg/proto/impl/v1"
+	"github.com/authzed/spicedb/pkg/spiceerrors"
 	"github.com/authzed/spicedb/pkg/tuple"
 )
 
@@ -34,8 +37,16 @@ func (ts *TypeSystem) GetValidatedDefinition(ctx context.Context, definition str
 	return vdef, nil
 }
 
+// Validate validates the definition, collecting all independent problems (missing allowed
+// types, references to undefined relations, illegal wildcard or caveat usage, permission
+// cycles) into a single error implementing `Unwrap() []error`, so that a large schema can be
+// fixed in one pass rather than one error at a time. Errors that make further validation
+// meaningless (such as a failure to resolve a referenced definition's type system) still
+// return immediately.
 func (def *Definition) Validate(ctx context.Context) (*ValidatedDefinition, error) {
-	for _, relation := range def.relationMap {
+	validationErrors := make([]error, 0)
+
+	for _, relation := range def.nsDef.Relation {
 		// Validate type annotations first.
 		// If there's type annotation metadata, the annotated terminal types are a superset of the reachable ones.
 		if annotations := nspkg.GetTypeAnnotations(relation); len(annotations) != 0 {
@@ -45,11 +56,12 @@ func (def *Definition) Validate(ctx context.Context) (*ValidatedDefinition, erro
 			}
 			for _, typ := range tset {
 				if !slices.Contains(annotations, typ) {
-					return nil, NewTypeWithSourceError(
+					validationErrors = append(validationErrors, NewTypeWithSourceError(
 						fmt.Errorf("incomplete type annotation on relation `%s` in definition `%s`: `%s` found as reachable type, but not contained in provided set %v", relation.GetName(), def.nsDef.GetName(), typ, backtickNames(annotations)),
 						relation,
 						relation.GetName(),
-					)
+					))
+					break
 				}
 			}
 		}
@@ -62,11 +74,11 @@ func (def *Definition) Validate(ctx context.Context) (*ValidatedDefinition, erro
 				relationName := child.ComputedUserset.GetRelation()
 				_, ok := def.relationMap[relationName]
 				if !ok {
-					return NewTypeWithSourceError(
+					validationErrors = append(validationErrors, asTypeError(NewTypeWithSourceError(
 						NewRelationNotFoundErr(def.nsDef.Name, relationName),
 						childOneof,
 						relationName,
-					), nil
+					)))
 				}
 
 			case *core.SetOperation_Child_TupleToUserset:
@@ -83,27 +95,30 @@ func (def *Definition) Validate(ctx context.Context) (*ValidatedDefinition, erro
 				relationName := tupleset.GetRelation()
 				found, ok := def.relationMap[relationName]
 				if !ok {
-					return NewTypeWithSourceError(
+					validationErrors = append(validationErrors, asTypeError(NewTypeWithSourceError(
 						NewRelationNotFoundErr(def.nsDef.Name, relationName),
 						childOneof,
 						relationName,
-					), nil
+					)))
+					return nil, nil
 				}
 
 				if nspkg.GetRelationKind(found) == iv1.RelationMetadata_PERMISSION {
-					return NewTypeWithSourceError(
+					validationErrors = append(validationErrors, asTypeError(NewTypeWithSourceError(
 						NewPermissionUsedOnLeftOfArrowErr(def.nsDef.Name, relation.Name, relationName),
-						childOneof, relationName), nil
+						childOneof, relationName)))
+					return nil, nil
 				}
 
 				// Ensure the tupleset relation doesn't itself import wildcard.
 				referencedWildcard, err := def.TypeSystem().referencesWildcardType(ctx, def, relationName)
 				if err != nil {
-					return err, nil
+					validationErrors = append(validationErrors, asTypeError(err))
+					return nil, nil
 				}
 
 				if referencedWildcard != nil {
-					return NewTypeWithSourceError(
+					validationErrors = append(validationErrors, asTypeError(NewTypeWithSourceError(
 						NewWildcardUsedInArrowErr(
 							def.nsDef.Name,
 							relation.Name,
@@ -112,7 +127,7 @@ func (def *Definition) Validate(ctx context.Context) (*ValidatedDefinition, erro
 							tuple.StringCoreRR(referencedWildcard.ReferencingRelation),
 						),
 						childOneof, relationName,
-					), nil
+					)))
 				}
 
 			case *core.SetOperation_Child_FunctionedTupleToUserset:
@@ -129,27 +144,30 @@ func (def *Definition) Validate(ctx context.Context) (*ValidatedDefinition, erro
 				relationName := tupleset.GetRelation()
 				found, ok := def.relationMap[relationName]
 				if !ok {
-					return NewTypeWithSourceError(
+					validationErrors = append(validationErrors, asTypeError(NewTypeWithSourceError(
 						NewRelationNotFoundErr(def.nsDef.Name, relationName),
 						childOneof,
 						relationName,
-					), nil
+					)))
+					return nil, nil
 				}
 
 				if nspkg.GetRelationKind(found) == iv1.RelationMetadata_PERMISSION {
-					return NewTypeWithSourceError(
+					validationErrors = append(validationErrors, asTypeError(NewTypeWithSourceError(
 						NewPermissionUsedOnLeftOfArrowErr(def.nsDef.Name, relation.Name, relationName),
-						childOneof, relationName), nil
+						childOneof, relationName)))
+					return nil, nil
 				}
 
 				// Ensure the tupleset relation doesn't itself import wildcard.
 				referencedWildcard, err := def.TypeSystem().referencesWildcardType(ctx, def, relationName)
 				if err != nil {
-					return err, nil
+					validationErrors = append(validationErrors, asTypeError(err))
+					return nil, nil
 				}
 
 				if referencedWildcard != nil {
-					return NewTypeWithSourceError(
+					validationErrors = append(validationErrors, asTypeError(NewTypeWithSourceError(
 						NewWildcardUsedInArrowErr(
 							def.nsDef.Name,
 							relation.Name,
@@ -158,13 +176,13 @@ func (def *Definition) Validate(ctx context.Context) (*ValidatedDefinition, erro
 							tuple.StringCoreRR(referencedWildcard.ReferencingRelation),
 						),
 						childOneof, relationName,
-					), nil
+					)))
 				}
 			}
 			return nil, nil
 		})
 		if rerr != nil {
-			return nil, asTypeError(rerr.(error))
+			return nil, spiceerrors.MustBugf("unexpected value returned from rewrite walk: %v", rerr)
 		}
 		if err != nil {
 			return nil, err
@@ -187,17 +205,19 @@ func (def *Definition) Validate(ctx context.Context) (*ValidatedDefinition, erro
 
 		if usersetRewrite == nil || hasThis {
 			if len(allowedDirectRelations) == 0 {
-				return nil, NewTypeWithSourceError(
+				validationErrors = append(validationErrors, NewTypeWithSourceError(
 					NewMissingAllowedRelationsErr(def.nsDef.Name, relation.Name),
 					relation, relation.Name,
-				)
+				))
+				continue
 			}
 		} else {
 			if len(allowedDirectRelations) != 0 {
 				// NOTE: This is a legacy error and should never really occur with schema.
-				return nil, NewTypeWithSourceError(
+				validationErrors = append(validationErrors, NewTypeWithSourceError(
 					fmt.Errorf("direct relations are not allowed under relation `%s`", relation.Name),
-					relation, relation.Name)
+					relation, relation.Name))
+				continue
 			}
 		}
 
@@ -211,11 +231,12 @@ func (def *Definition) Validate(ctx context.Context) (*ValidatedDefinition, erro
 		for _, allowedRelation := range allowedDirectRelations {
 			source := SourceForAllowedRelation(allowedRelation)
 			if !encountered.Add(source) {
-				return nil, NewTypeWithSourceError(
+				validationErrors = append(validationErrors, NewTypeWithSourceError(
 					NewDuplicateAllowedRelationErr(def.nsDef.Name, relation.Name, source),
 					allowedRelation,
 					source,
-				)
+				))
+				continue
 			}
 
 			// Check the namespace.
@@ -223,21 +244,23 @@ func (def *Definition) Validate(ctx context.Context) (*ValidatedDefinition, erro
 				if allowedRelation.GetPublicWildcard() == nil && allowedRelation.GetRelation() != tuple.Ellipsis {
 					_, ok := def.relationMap[allowedRelation.GetRelation()]
 					if !ok {
-						return nil, NewTypeWithSourceError(
+						validationErrors = append(validationErrors, NewTypeWithSourceError(
 							NewRelationNotFoundErr(allowedRelation.GetNamespace(), allowedRelation.GetRelation()),
 							allowedRelation,
 							allowedRelation.GetRelation(),
-						)
+						))
+						continue
 					}
 				}
 			} else {
 				subjectTS, err := def.TypeSystem().GetDefinition(ctx, allowedRelation.GetNamespace())
 				if err != nil {
-					return nil, NewTypeWithSourceError(
+					validationErrors = append(validationErrors, NewTypeWithSourceError(
 						fmt.Errorf("could not lookup definition `%s` for relation `%s`: %w", allowedRelation.GetNamespace(), relation.Name, err),
 						allowedRelation,
 						allowedRelation.GetNamespace(),
-					)
+					))
+					continue
 				}
 
 				// Check for relations.
@@ -245,11 +268,12 @@ func (def *Definition) Validate(ctx context.Context) (*ValidatedDefinition, erro
 					// Ensure the relation exists.
 					ok := subjectTS.HasRelation(allowedRelation.GetRelation())
 					if !ok {
-						return nil, NewTypeWithSourceError(
+						validationErrors = append(validationErrors, NewTypeWithSourceError(
 							NewRelationNotFoundErr(allowedRelation.GetNamespace(), allowedRelation.GetRelation()),
 							allowedRelation,
 							allowedRelation.GetRelation(),
-						)
+						))
+						continue
 					}
 
 					// Ensure the relation doesn't itself import wildcard.
@@ -259,7 +283,7 @@ func (def *Definition) Validate(ctx context.Context) (*ValidatedDefinition, erro
 					}
 
 					if referencedWildcard != nil {
-						return nil, NewTypeWithSourceError(
+						validationErrors = append(validationErrors, NewTypeWithSourceError(
 							NewTransitiveWildcardErr(
 								def.nsDef.Name,
 								relation.GetName(),
@@ -270,7 +294,8 @@ func (def *Definition) Validate(ctx context.Context) (*ValidatedDefinition, erro
 							),
 							allowedRelation,
 							tuple.JoinRelRef(allowedRelation.GetNamespace(), allowedRelation.GetRelation()),
-						)
+						))
+						continue
 					}
 				}
 			}
@@ -279,17 +304,132 @@ func (def *Definition) Validate(ctx context.Context) (*ValidatedDefinition, erro
 			if allowedRelation.GetRequiredCaveat() != nil {
 				_, err := def.TypeSystem().resolver.LookupCaveat(ctx, allowedRelation.GetRequiredCaveat().CaveatName)
 				if err != nil {
-					return nil, NewTypeWithSourceError(
+					validationErrors = append(validationErrors, NewTypeWithSourceError(
 						fmt.Errorf("could not lookup caveat `%s` for relation `%s`: %w", allowedRelation.GetRequiredCaveat().CaveatName, relation.Name, err),
 						allowedRelation,
 						source,
-					)
+					))
 				}
 			}
 		}
 	}
 
-	return &ValidatedDefinition{def}, nil
+	if err := def.checkPermissionCycles(); err != nil {
+		validationErrors = append(validationErrors, err)
+	}
+
+	switch len(validationErrors) {
+	case 0:
+		return &ValidatedDefinition{Definition: def}, nil
+	case 1:
+		return nil, validationErrors[0]
+	default:
+		return nil, errors.Join(validationErrors...)
+	}
+}
+
+// checkPermissionCycles performs explicit cycle detection over the permission-reference graph of
+// the definition, reporting the complete cycle as an ordered list of `namespace#relation` hops.
+// References via an arrow's computed userset are followed only when the arrow's tupleset relation
+// allows subjects of the same definition, since arrows cannot recurse into their own definition
+// otherwise. Direct self-references are skipped: recursion through an arrow consumes a
+// relationship on each hop (e.g. the nested-groups pattern `permission member = direct +
+// parent->member`) and so always terminates, while a self-reference without an arrow is surfaced
+// as a development warning and caught at runtime.
+func (def *Definition) checkPermissionCycles() error {
+	edges := make(map[string][]string, len(def.nsDef.Relation))
+	for _, rel := range def.nsDef.Relation {
+		rewrite := rel.GetUsersetRewrite()
+		if rewrite == nil {
+			continue
+		}
+
+		collected := make([]string, 0)
+		addArrowTarget := func(tuplesetName string, targetName string) {
+			if targetName == rel.Name {
+				return
+			}
+
+			tupleset, ok := def.relationMap[tuplesetName]
+			if !ok {
+				return
+			}
+
+			for _, allowed := range tupleset.GetTypeInformation().GetAllowedDirectRelations() {
+				if allowed.Namespace == def.nsDef.Name {
+					collected = append(collected, targetName)
+					return
+				}
+			}
+		}
+
+		if _, err := graph.WalkRewrite(rewrite, func(childOneof *core.SetOperation_Child) (any, error) {
+			switch child := childOneof.ChildType.(type) {
+			case *core.SetOperation_Child_ComputedUserset:
+				if child.ComputedUserset.GetRelation() != rel.Name {
+					collected = append(collected, child.ComputedUserset.GetRelation())
+				}
+
+			case *core.SetOperation_Child_TupleToUserset:
+				addArrowTarget(child.TupleToUserset.GetTupleset().GetRelation(), child.TupleToUserset.GetComputedUserset().GetRelation())
+
+			case *core.SetOperation_Child_FunctionedTupleToUserset:
+				addArrowTarget(child.FunctionedTupleToUserset.GetTupleset().GetRelation(), child.FunctionedTupleToUserset.GetComputedUserset().GetRelation())
+			}
+			return nil, nil
+		}); err != nil {
+			return err
+		}
+
+		edges[rel.Name] = collected
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := make(map[string]int, len(edges))
+
+	var visit func(relationName string, stack []string) error
+	visit = func(relationName string, stack []string) error {
+		states[relationName] = visiting
+		stack = append(stack, relationName)
+
+		for _, target := range edges[relationName] {
+			switch states[target] {
+			case visiting:
+				start := slices.Index(stack, target)
+				cyclePath := make([]string, 0, len(stack)-start+1)
+				for _, name := range stack[start:] {
+					cyclePath = append(cyclePath, def.nsDef.Name+"#"+name)
+				}
+				cyclePath = append(cyclePath, def.nsDef.Name+"#"+target)
+
+				permissionNames := slices.Clone(stack[start:])
+				slices.Sort(permissionNames)
+				return NewPermissionsCyclePathErr(def.nsDef.Name, permissionNames, cyclePath)
+
+			case unvisited:
+				if err := visit(target, stack); err != nil {
+					return err
+				}
+			}
+		}
+
+		states[relationName] = visited
+		return nil
+	}
+
+	for _, rel := range def.nsDef.Relation {
+		if states[rel.Name] == unvisited {
+			if err := visit(rel.Name, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
 }
 
 // referencesWildcardType returns true if the relation references a wildcard type, either directly or via
@@ -375,4 +515,12 @@ func (ts *TypeSystem) computeReferencesWildcardType(ctx context.Context, def *De
 // ValidatedDefinition is a typesafe reference to a definition that has been validated.
 type ValidatedDefinition struct {
 	*Definition
+
+	aliasesOnce sync.Once
+	aliases     map[string]string
+	aliasesErr  error
+
+	traitsOnce sync.Once
+	traits     map[string]PermissionTraits
+	traitsErr  error
 }
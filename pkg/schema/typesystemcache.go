@@ -0,0 +1,120 @@
+package schema
+
+import (
+	"container/list"
+	"context"
+	"encoding/hex"
+	"hash/fnv"
+	"sync"
+)
+
+// ValidatedDefinitionCache is a small LRU cache of validated definitions, shared across
+// TypeSystem instances, keyed by a content hash of the serialized definition along with a
+// caller-provided resolver key identifying the resolver (and its revision) that produced it.
+// Validating a definition is expensive and the result is immutable, so callers serving many
+// requests over the same schema can reuse a single validated definition, including its
+// memoized alias and trait maps, rather than rebuilding it per request.
+//
+// A cached ValidatedDefinition is safe for concurrent read use: its memoized maps are built
+// under sync.Once and its type system locks around its own internal state.
+//
+// The content hash only covers the definition itself, not the definitions it references, so
+// callers must change the resolver key or explicitly invalidate when the schema is rewritten.
+type ValidatedDefinitionCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element // GUARDED_BY(mu)
+	order      *list.List               // GUARDED_BY(mu)
+}
+
+type validatedDefinitionCacheEntry struct {
+	key            string
+	definitionName string
+	vdef           *ValidatedDefinition
+}
+
+// NewValidatedDefinitionCache constructs an empty cache holding at most maxEntries validated
+// definitions, evicting the least recently used beyond that.
+func NewValidatedDefinitionCache(maxEntries int) *ValidatedDefinitionCache {
+	return &ValidatedDefinitionCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element, maxEntries),
+		order:      list.New(),
+	}
+}
+
+// GetValidatedDefinition returns the validated definition with the given name from the given
+// type system, reusing a previously validated definition if one was cached under the same
+// resolver key and the definition's serialized form is unchanged.
+func (c *ValidatedDefinitionCache) GetValidatedDefinition(ctx context.Context, ts *TypeSystem, resolverKey string, definitionName string) (*ValidatedDefinition, error) {
+	def, err := ts.GetDefinition(ctx, definitionName)
+	if err != nil {
+		return nil, err
+	}
+
+	serialized, err := def.Namespace().MarshalVT()
+	if err != nil {
+		return nil, err
+	}
+
+	hasher := fnv.New64a()
+	if _, err := hasher.Write(serialized); err != nil {
+		return nil, err
+	}
+
+	key := resolverKey + "|" + definitionName + "|" + hex.EncodeToString(hasher.Sum(nil))
+
+	c.mu.Lock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		vdef := element.Value.(*validatedDefinitionCacheEntry).vdef
+		c.mu.Unlock()
+		return vdef, nil
+	}
+	c.mu.Unlock()
+
+	vdef, err := ts.GetValidatedDefinition(ctx, definitionName)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.entries[key] = c.order.PushFront(&validatedDefinitionCacheEntry{
+			key:            key,
+			definitionName: definitionName,
+			vdef:           vdef,
+		})
+		for c.order.Len() > c.maxEntries {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*validatedDefinitionCacheEntry).key)
+		}
+	}
+	return vdef, nil
+}
+
+// InvalidateDefinition removes all cached entries for the given definition name, across all
+// resolver keys. It must be called for definitions whose referenced definitions changed
+// without the definition itself changing, such as after a schema write.
+func (c *ValidatedDefinitionCache) InvalidateDefinition(definitionName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, element := range c.entries {
+		if element.Value.(*validatedDefinitionCacheEntry).definitionName == definitionName {
+			c.order.Remove(element)
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Clear removes all cached entries.
+func (c *ValidatedDefinitionCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element, c.maxEntries)
+	c.order = list.New()
+}
@@ -0,0 +1,136 @@
+package schema
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ns "github.com/authzed/spicedb/pkg/namespace"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+func cacheTestDefs(viewerRelation string) []*core.NamespaceDefinition {
+	return []*core.NamespaceDefinition{
+		ns.Namespace("user"),
+		ns.Namespace(
+			"document",
+			ns.MustRelation("owner", nil, ns.AllowedRelation("user", "...")),
+			ns.MustRelation(viewerRelation, nil, ns.AllowedRelation("user", "...")),
+			ns.MustRelation("view", ns.Union(
+				ns.ComputedUserset("owner"),
+				ns.ComputedUserset(viewerRelation),
+			)),
+		),
+	}
+}
+
+func typeSystemForCacheTest(defs []*core.NamespaceDefinition) *TypeSystem {
+	return NewTypeSystem(ResolverForPredefinedDefinitions(PredefinedElements{
+		Definitions: defs,
+	}))
+}
+
+func TestValidatedDefinitionCache(t *testing.T) {
+	ctx := t.Context()
+	cache := NewValidatedDefinitionCache(10)
+
+	first, err := cache.GetValidatedDefinition(ctx, typeSystemForCacheTest(cacheTestDefs("viewer")), "rev1", "document")
+	require.NoError(t, err)
+
+	t.Run("reused across type systems", func(t *testing.T) {
+		second, err := cache.GetValidatedDefinition(ctx, typeSystemForCacheTest(cacheTestDefs("viewer")), "rev1", "document")
+		require.NoError(t, err)
+		require.Same(t, first, second)
+	})
+
+	t.Run("not reused across resolver keys", func(t *testing.T) {
+		other, err := cache.GetValidatedDefinition(ctx, typeSystemForCacheTest(cacheTestDefs("viewer")), "rev2", "document")
+		require.NoError(t, err)
+		require.NotSame(t, first, other)
+	})
+
+	t.Run("recomputed after a definition change", func(t *testing.T) {
+		changed, err := cache.GetValidatedDefinition(ctx, typeSystemForCacheTest(cacheTestDefs("reader")), "rev1", "document")
+		require.NoError(t, err)
+		require.NotSame(t, first, changed)
+
+		_, ok := changed.GetRelation("reader")
+		require.True(t, ok)
+
+		// The unchanged serialized form under the same resolver key is still served from the
+		// cache.
+		unchanged, err := cache.GetValidatedDefinition(ctx, typeSystemForCacheTest(cacheTestDefs("viewer")), "rev1", "document")
+		require.NoError(t, err)
+		require.Same(t, first, unchanged)
+	})
+
+	t.Run("recomputed after explicit invalidation", func(t *testing.T) {
+		cache.InvalidateDefinition("document")
+
+		recomputed, err := cache.GetValidatedDefinition(ctx, typeSystemForCacheTest(cacheTestDefs("viewer")), "rev1", "document")
+		require.NoError(t, err)
+		require.NotSame(t, first, recomputed)
+
+		cache.Clear()
+
+		cleared, err := cache.GetValidatedDefinition(ctx, typeSystemForCacheTest(cacheTestDefs("viewer")), "rev1", "document")
+		require.NoError(t, err)
+		require.NotSame(t, recomputed, cleared)
+	})
+
+	t.Run("least recently used entries are evicted", func(t *testing.T) {
+		small := NewValidatedDefinitionCache(1)
+
+		document, err := small.GetValidatedDefinition(ctx, typeSystemForCacheTest(cacheTestDefs("viewer")), "rev1", "document")
+		require.NoError(t, err)
+
+		_, err = small.GetValidatedDefinition(ctx, typeSystemForCacheTest(cacheTestDefs("viewer")), "rev1", "user")
+		require.NoError(t, err)
+
+		evicted, err := small.GetValidatedDefinition(ctx, typeSystemForCacheTest(cacheTestDefs("viewer")), "rev1", "document")
+		require.NoError(t, err)
+		require.NotSame(t, document, evicted)
+	})
+}
+
+func BenchmarkValidatedDefinitionCacheRepeatedLookups(b *testing.B) {
+	// A single large definition with many relations and permissions over them.
+	relations := make([]*core.Relation, 0, 200)
+	for i := 0; i < 100; i++ {
+		relations = append(relations, ns.MustRelation(fmt.Sprintf("relation%d", i), nil, ns.AllowedRelation("user", "...")))
+		relations = append(relations, ns.MustRelation(fmt.Sprintf("permission%d", i), ns.Union(
+			ns.ComputedUserset(fmt.Sprintf("relation%d", i)),
+		)))
+	}
+
+	defs := []*core.NamespaceDefinition{
+		ns.Namespace("user"),
+		ns.Namespace("document", relations...),
+	}
+
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			vdef, err := typeSystemForCacheTest(defs).GetValidatedDefinition(b.Context(), "document")
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, ok := vdef.GetRelation("permission99"); !ok {
+				b.Fatal("missing relation")
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		cache := NewValidatedDefinitionCache(10)
+		for i := 0; i < b.N; i++ {
+			vdef, err := cache.GetValidatedDefinition(b.Context(), typeSystemForCacheTest(defs), "rev1", "document")
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, ok := vdef.GetRelation("permission99"); !ok {
+				b.Fatal("missing relation")
+			}
+		}
+	})
+}
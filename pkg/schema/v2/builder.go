@@ -13,6 +13,7 @@ const (
 	CaveatTypeDuration   = "duration"
 	CaveatTypeTimestamp  = "timestamp"
 	CaveatTypeIPAddress  = "ipaddress"
+	CaveatTypeDecimal    = "decimal"
 	CaveatTypeList       = "list"
 	CaveatTypeMap        = "map"
 	CaveatTypeAny        = "any"
@@ -0,0 +1,200 @@
+package schema
+
+import (
+	"context"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+// WildcardUsageWarning flags a permission operand that can resolve to a wildcard subject but
+// appears in a position where wildcards rarely behave the way schema authors expect: underneath
+// an intersection, where the wildcard does not grant everyone the permission but instead only
+// intersects, or on the right side of an exclusion, where a single wildcard relationship
+// excludes *every* subject.
+type WildcardUsageWarning struct {
+	// Permission is the name of the permission containing the operand.
+	Permission string
+
+	// Operation is the containing set operation: "intersection" or "exclusion".
+	Operation string
+
+	// OperandIndex is the zero-indexed position of the operand within its containing operation.
+	OperandIndex int
+
+	// SubjectType is the definition name of a wildcard-capable subject reachable from the
+	// operand (e.g. `user` for a `user:*` reference).
+	SubjectType string
+}
+
+// WildcardUsageWarnings walks each permission's rewrite expression and returns a warning for
+// every operand that can resolve to a wildcard subject while sitting under an intersection or
+// on the right side of an exclusion. Wildcard reachability follows computed usersets, nested
+// operations and arrows, so a wildcard only reachable through another definition is still
+// reported. These are advisory: the schema remains valid.
+func (vd *ValidatedDefinition) WildcardUsageWarnings(ctx context.Context) ([]WildcardUsageWarning, error) {
+	warnings := []WildcardUsageWarning{}
+	for _, rel := range vd.nsDef.Relation {
+		rewrite := rel.GetUsersetRewrite()
+		if rewrite == nil {
+			continue
+		}
+
+		if err := vd.collectWildcardUsageWarnings(ctx, rel.Name, rewrite, &warnings); err != nil {
+			return nil, err
+		}
+	}
+	return warnings, nil
+}
+
+func (vd *ValidatedDefinition) collectWildcardUsageWarnings(ctx context.Context, permissionName string, rewrite *core.UsersetRewrite, warnings *[]WildcardUsageWarning) error {
+	checkOperand := func(operation string, index int, childOneof *core.SetOperation_Child) error {
+		subjectType, found, err := vd.operandWildcardSubject(ctx, childOneof)
+		if err != nil {
+			return err
+		}
+
+		if found {
+			*warnings = append(*warnings, WildcardUsageWarning{
+				Permission:   permissionName,
+				Operation:    operation,
+				OperandIndex: index,
+				SubjectType:  subjectType,
+			})
+		}
+		return nil
+	}
+
+	recurseNested := func(childOneof *core.SetOperation_Child) error {
+		if nested, ok := childOneof.ChildType.(*core.SetOperation_Child_UsersetRewrite); ok {
+			return vd.collectWildcardUsageWarnings(ctx, permissionName, nested.UsersetRewrite, warnings)
+		}
+		return nil
+	}
+
+	switch rw := rewrite.RewriteOperation.(type) {
+	case *core.UsersetRewrite_Union:
+		// Wildcards under a union behave as expected; only recurse.
+		for _, childOneof := range rw.Union.Child {
+			if err := recurseNested(childOneof); err != nil {
+				return err
+			}
+		}
+
+	case *core.UsersetRewrite_Intersection:
+		for index, childOneof := range rw.Intersection.Child {
+			if err := checkOperand("intersection", index, childOneof); err != nil {
+				return err
+			}
+			if err := recurseNested(childOneof); err != nil {
+				return err
+			}
+		}
+
+	case *core.UsersetRewrite_Exclusion:
+		// The left operand of an exclusion behaves like a union member; only the
+		// subtracted operands invert the wildcard's meaning.
+		for index, childOneof := range rw.Exclusion.Child {
+			if index > 0 {
+				if err := checkOperand("exclusion", index, childOneof); err != nil {
+					return err
+				}
+			}
+			if err := recurseNested(childOneof); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// operandWildcardSubject returns the definition name of a wildcard-capable subject type
+// reachable from the given operand, if any. Nested operations are treated conservatively: a
+// wildcard reachable from any branch is reported.
+func (vd *ValidatedDefinition) operandWildcardSubject(ctx context.Context, childOneof *core.SetOperation_Child) (string, bool, error) {
+	switch child := childOneof.ChildType.(type) {
+	case *core.SetOperation_Child_ComputedUserset:
+		return vd.reachableWildcardSubject(ctx, vd.nsDef.Name, child.ComputedUserset.Relation)
+
+	case *core.SetOperation_Child_UsersetRewrite:
+		return vd.rewriteWildcardSubject(ctx, child.UsersetRewrite)
+
+	case *core.SetOperation_Child_TupleToUserset:
+		return vd.arrowWildcardSubject(ctx, child.TupleToUserset.Tupleset.Relation, child.TupleToUserset.ComputedUserset.Relation)
+
+	case *core.SetOperation_Child_FunctionedTupleToUserset:
+		return vd.arrowWildcardSubject(ctx, child.FunctionedTupleToUserset.Tupleset.Relation, child.FunctionedTupleToUserset.ComputedUserset.Relation)
+
+	default:
+		return "", false, nil
+	}
+}
+
+func (vd *ValidatedDefinition) rewriteWildcardSubject(ctx context.Context, rewrite *core.UsersetRewrite) (string, bool, error) {
+	var children []*core.SetOperation_Child
+	switch rw := rewrite.RewriteOperation.(type) {
+	case *core.UsersetRewrite_Union:
+		children = rw.Union.Child
+	case *core.UsersetRewrite_Intersection:
+		children = rw.Intersection.Child
+	case *core.UsersetRewrite_Exclusion:
+		children = rw.Exclusion.Child
+	}
+
+	for _, childOneof := range children {
+		subjectType, found, err := vd.operandWildcardSubject(ctx, childOneof)
+		if err != nil || found {
+			return subjectType, found, err
+		}
+	}
+	return "", false, nil
+}
+
+// arrowWildcardSubject reports a wildcard reachable through an arrow: the arrow's computed
+// relation is resolved against each of the tupleset relation's allowed subject definitions.
+func (vd *ValidatedDefinition) arrowWildcardSubject(ctx context.Context, tuplesetRelation string, computedRelationName string) (string, bool, error) {
+	allowedRelations, err := vd.AllowedDirectRelationsAndWildcards(tuplesetRelation)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, allowedRelation := range allowedRelations {
+		if allowedRelation.GetPublicWildcard() != nil {
+			continue
+		}
+
+		targetDef, err := vd.ts.GetDefinition(ctx, allowedRelation.GetNamespace())
+		if err != nil {
+			return "", false, err
+		}
+
+		if !targetDef.HasRelation(computedRelationName) {
+			continue
+		}
+
+		subjectType, found, err := vd.reachableWildcardSubject(ctx, allowedRelation.GetNamespace(), computedRelationName)
+		if err != nil || found {
+			return subjectType, found, err
+		}
+	}
+	return "", false, nil
+}
+
+func (vd *ValidatedDefinition) reachableWildcardSubject(ctx context.Context, namespaceName string, relationName string) (string, bool, error) {
+	targetDef, err := vd.ts.GetValidatedDefinition(ctx, namespaceName)
+	if err != nil {
+		return "", false, err
+	}
+
+	reachable, err := targetDef.ReachableSubjectTypes(ctx, relationName)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, subjectType := range reachable {
+		if subjectType.IsWildcard {
+			return subjectType.SubjectType, true, nil
+		}
+	}
+	return "", false, nil
+}
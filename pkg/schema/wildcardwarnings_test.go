@@ -0,0 +1,157 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ns "github.com/authzed/spicedb/pkg/namespace"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+func TestWildcardUsageWarnings(t *testing.T) {
+	testCases := []struct {
+		name            string
+		toCheck         *core.NamespaceDefinition
+		otherNamespaces []*core.NamespaceDefinition
+		expected        []WildcardUsageWarning
+	}{
+		{
+			"no wildcards",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("viewer", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("editor", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("view", ns.Intersection(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("editor"),
+				)),
+			),
+			nil,
+			[]WildcardUsageWarning{},
+		},
+		{
+			"wildcard under a union",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("viewer", nil, ns.AllowedPublicNamespace("user")),
+				ns.MustRelation("editor", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("view", ns.Union(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("editor"),
+				)),
+			),
+			nil,
+			[]WildcardUsageWarning{},
+		},
+		{
+			"wildcard under an intersection",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("viewer", nil, ns.AllowedPublicNamespace("user")),
+				ns.MustRelation("editor", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("view", ns.Intersection(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("editor"),
+				)),
+			),
+			nil,
+			[]WildcardUsageWarning{
+				{Permission: "view", Operation: "intersection", OperandIndex: 0, SubjectType: "user"},
+			},
+		},
+		{
+			"wildcard on the right side of an exclusion",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("viewer", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("banned", nil, ns.AllowedPublicNamespace("user")),
+				ns.MustRelation("view", ns.Exclusion(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("banned"),
+				)),
+			),
+			nil,
+			[]WildcardUsageWarning{
+				{Permission: "view", Operation: "exclusion", OperandIndex: 1, SubjectType: "user"},
+			},
+		},
+		{
+			"wildcard on the left side of an exclusion",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("viewer", nil, ns.AllowedPublicNamespace("user")),
+				ns.MustRelation("banned", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("view", ns.Exclusion(
+					ns.ComputedUserset("viewer"),
+					ns.ComputedUserset("banned"),
+				)),
+			),
+			nil,
+			[]WildcardUsageWarning{},
+		},
+		{
+			"wildcard reachable through a nested operation",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("viewer", nil, ns.AllowedPublicNamespace("user")),
+				ns.MustRelation("editor", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("owner", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("view", ns.Intersection(
+					ns.ComputedUserset("owner"),
+					ns.Rewrite(ns.Union(
+						ns.ComputedUserset("viewer"),
+						ns.ComputedUserset("editor"),
+					)),
+				)),
+			),
+			nil,
+			[]WildcardUsageWarning{
+				{Permission: "view", Operation: "intersection", OperandIndex: 1, SubjectType: "user"},
+			},
+		},
+		{
+			"wildcard only reachable behind an arrow",
+			ns.Namespace(
+				"document",
+				ns.MustRelation("folder", nil, ns.AllowedRelation("folder", "...")),
+				ns.MustRelation("editor", nil, ns.AllowedRelation("user", "...")),
+				ns.MustRelation("view", ns.Intersection(
+					ns.ComputedUserset("editor"),
+					ns.TupleToUserset("folder", "view"),
+				)),
+			),
+			[]*core.NamespaceDefinition{
+				ns.Namespace(
+					"folder",
+					ns.MustRelation("viewer", nil, ns.AllowedPublicNamespace("user")),
+					ns.MustRelation("view", ns.Union(
+						ns.ComputedUserset("viewer"),
+					)),
+				),
+			},
+			[]WildcardUsageWarning{
+				{Permission: "view", Operation: "intersection", OperandIndex: 1, SubjectType: "user"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			ctx := t.Context()
+			definitions := append([]*core.NamespaceDefinition{ns.Namespace("user"), tc.toCheck}, tc.otherNamespaces...)
+			ts := NewTypeSystem(ResolverForPredefinedDefinitions(PredefinedElements{
+				Definitions: definitions,
+			}))
+
+			vdef, err := ts.GetValidatedDefinition(ctx, tc.toCheck.Name)
+			require.NoError(err)
+
+			warnings, err := vdef.WildcardUsageWarnings(ctx)
+			require.NoError(err)
+			require.Equal(tc.expected, warnings)
+		})
+	}
+}
@@ -715,6 +715,23 @@ func TestCompile(t *testing.T) {
 					`!user_ip.in_cidr('1.2.3.0')`),
 			},
 		},
+		{
+			"caveat decimal example",
+			withTenantPrefix,
+			`caveat under_limit(amount decimal, limit decimal) {
+				amount <= limit
+			}`,
+			``,
+			[]SchemaDefinition{
+				namespace.MustCaveatDefinition(caveats.MustEnvForVariablesWithDefaultTypeSet(
+					map[string]caveattypes.VariableType{
+						"amount": caveattypes.Default.DecimalType,
+						"limit":  caveattypes.Default.DecimalType,
+					},
+				), "sometenant/under_limit",
+					`amount <= limit`),
+			},
+		},
 		{
 			"caveat subtree example",
 			withTenantPrefix,
@@ -1196,3 +1213,71 @@ func TestCompileWithCustomCaveatTypeSet(t *testing.T) {
 	}, AllowUnprefixedObjectType(), CaveatTypeSet(sts.TypeSet))
 	require.NoError(t, err)
 }
+
+func TestCompileCaveatWithParameterDefaults(t *testing.T) {
+	t.Parallel()
+
+	schema := `
+		caveat somecaveat(somecondition int = 42, somebool bool = true, somestring string = "hello", somedouble double = -1.25, somedur duration = "720h") {
+			somecondition == 42
+		}
+	`
+
+	compiled, err := Compile(InputSchema{
+		input.Source("defaults"), schema,
+	}, AllowUnprefixedObjectType())
+	require.NoError(t, err)
+	require.Len(t, compiled.CaveatDefinitions, 1)
+
+	defaults, err := namespace.GetCaveatParameterDefaults(compiled.CaveatDefinitions[0].Metadata)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"somecondition": float64(42),
+		"somebool":      true,
+		"somestring":    "hello",
+		"somedouble":    -1.25,
+		"somedur":       "720h",
+	}, defaults)
+}
+
+func TestCompileCaveatWithInvalidParameterDefaults(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		schema        string
+		expectedError string
+	}{
+		{
+			"string default for int parameter",
+			`caveat somecaveat(somecondition int = "hello") { somecondition == 42 }`,
+			"invalid default value for caveat parameter `somecondition`",
+		},
+		{
+			"numeric default for duration parameter",
+			`caveat somecaveat(somedur duration = 10) { somedur < duration("24h") }`,
+			"invalid default value for caveat parameter `somedur`",
+		},
+		{
+			"numeric default for bool parameter",
+			`caveat somecaveat(somebool bool = 42) { somebool }`,
+			"invalid default value for caveat parameter `somebool`",
+		},
+		{
+			"non-literal default",
+			`caveat somecaveat(somecondition int = someidentifier) { somecondition == 42 }`,
+			"Unexpected token at root level",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Compile(InputSchema{
+				input.Source("defaults"), test.schema,
+			}, AllowUnprefixedObjectType())
+			require.ErrorContains(t, err, test.expectedError)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/ccoveille/go-safecast"
@@ -128,6 +129,7 @@ func translateCaveatDefinition(tctx *translationContext, defNode *dslNode) (*cor
 
 	env := caveats.NewEnvironmentWithTypeSet(tctx.caveatTypeSet)
 	parameters := make(map[string]caveattypes.VariableType, len(paramNodes))
+	parameterDefaults := make(map[string]any)
 	for _, paramNode := range paramNodes {
 		paramName, err := paramNode.GetString(dslshape.NodeCaveatParameterPredicateName)
 		if err != nil {
@@ -153,6 +155,26 @@ func translateCaveatDefinition(tctx *translationContext, defNode *dslNode) (*cor
 		if err != nil {
 			return nil, paramNode.WithSourceErrorf(paramName, "invalid type for caveat parameter `%s` on caveat `%s`: %w", paramName, definitionName, err)
 		}
+
+		// default value, if any.
+		if paramNode.Has(dslshape.NodeCaveatParameterPredicateDefaultValue) {
+			defaultValueText, err := paramNode.GetString(dslshape.NodeCaveatParameterPredicateDefaultValue)
+			if err != nil {
+				return nil, paramNode.WithSourceErrorf(paramName, "invalid default value for parameter: %w", err)
+			}
+
+			defaultValue, err := parseCaveatParameterDefaultLiteral(defaultValueText)
+			if err != nil {
+				return nil, paramNode.WithSourceErrorf(paramName, "invalid default value for caveat parameter `%s` on caveat `%s`: %w", paramName, definitionName, err)
+			}
+
+			// Ensure the default value is convertible to the parameter's type.
+			if _, err := translatedType.ConvertValue(defaultValue); err != nil {
+				return nil, paramNode.WithSourceErrorf(paramName, "invalid default value for caveat parameter `%s` on caveat `%s`: %w", paramName, definitionName, err)
+			}
+
+			parameterDefaults[paramName] = defaultValue
+		}
 	}
 
 	caveatPath, err := tctx.prefixedPath(definitionName)
@@ -192,10 +214,49 @@ func translateCaveatDefinition(tctx *translationContext, defNode *dslNode) (*cor
 	}
 
 	def.Metadata = addComments(def.Metadata, defNode)
+	if len(parameterDefaults) > 0 {
+		def.Metadata, err = namespace.SetCaveatParameterDefaults(def.Metadata, parameterDefaults)
+		if err != nil {
+			return nil, defNode.WithSourceErrorf(definitionName, "invalid default value(s) on caveat `%s`: %w", definitionName, err)
+		}
+	}
+
 	def.SourcePosition = getSourcePosition(defNode, tctx.mapper)
 	return def, nil
 }
 
+// parseCaveatParameterDefaultLiteral parses the raw source text of a caveat parameter default
+// value literal into its Go value.
+func parseCaveatParameterDefaultLiteral(literalText string) (any, error) {
+	switch {
+	case literalText == "true":
+		return true, nil
+
+	case literalText == "false":
+		return false, nil
+
+	case strings.HasPrefix(literalText, `"`):
+		unquoted, err := strconv.Unquote(literalText)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string literal %s: %w", literalText, err)
+		}
+		return unquoted, nil
+
+	case strings.HasPrefix(literalText, `'`):
+		if len(literalText) < 2 || !strings.HasSuffix(literalText, `'`) {
+			return nil, fmt.Errorf("invalid string literal %s", literalText)
+		}
+		return literalText[1 : len(literalText)-1], nil
+
+	default:
+		parsed, err := strconv.ParseFloat(literalText, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid numeric literal %s: %w", literalText, err)
+		}
+		return parsed, nil
+	}
+}
+
 func translateCaveatTypeReference(tctx *translationContext, typeRefNode *dslNode) (*caveattypes.VariableType, error) {
 	typeName, err := typeRefNode.GetString(dslshape.NodeCaveatTypeReferencePredicateType)
 	if err != nil {
@@ -117,6 +117,9 @@ const (
 	// The defined type of the caveat parameter.
 	NodeCaveatParameterPredicateType = "caveat-parameter-type"
 
+	// The raw source text of the default value literal for the caveat parameter, if any.
+	NodeCaveatParameterPredicateDefaultValue = "caveat-parameter-default-value"
+
 	//
 	// NodeTypeCaveatTypeReference
 	//
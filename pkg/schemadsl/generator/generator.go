@@ -6,6 +6,7 @@ import (
 	"maps"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/authzed/spicedb/pkg/caveats"
@@ -141,6 +142,11 @@ func (sg *sourceGenerator) emitCaveat(caveat *core.CaveatDefinition) error {
 	parameterNames := slices.Collect(maps.Keys(caveat.ParameterTypes))
 	sort.Strings(parameterNames)
 
+	parameterDefaults, err := namespace.GetCaveatParameterDefaults(caveat.Metadata)
+	if err != nil {
+		return fmt.Errorf("invalid parameter defaults on caveat: %w", err)
+	}
+
 	for index, paramName := range parameterNames {
 		if index > 0 {
 			sg.append(", ")
@@ -154,6 +160,11 @@ func (sg *sourceGenerator) emitCaveat(caveat *core.CaveatDefinition) error {
 		sg.append(paramName)
 		sg.append(" ")
 		sg.append(decoded.String())
+
+		if defaultValue, ok := parameterDefaults[paramName]; ok {
+			sg.append(" = ")
+			sg.append(stringifyDefaultValue(defaultValue))
+		}
 	}
 
 	sg.append(")")
@@ -186,6 +197,24 @@ func (sg *sourceGenerator) emitCaveat(caveat *core.CaveatDefinition) error {
 	return nil
 }
 
+// stringifyDefaultValue returns the schema literal form of the given caveat parameter
+// default value.
+func stringifyDefaultValue(defaultValue any) string {
+	switch value := defaultValue.(type) {
+	case string:
+		return strconv.Quote(value)
+
+	case bool:
+		return strconv.FormatBool(value)
+
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
 func (sg *sourceGenerator) emitNamespace(namespace *core.NamespaceDefinition) error {
 	sg.emitComments(namespace.Metadata)
 	sg.append("definition ")
@@ -77,6 +77,24 @@ caveat somecaveat(someParam int) {
 	}
 }
 
+func TestGenerateCaveatWithParameterDefaults(t *testing.T) {
+	schema := `caveat somecaveat(someBool bool = true, someDouble double = -1.25, someParam int = 42, someString string = "hello") {
+	someParam == 42
+}`
+
+	compiled, err := compiler.Compile(compiler.InputSchema{
+		Source:       input.Source("schema"),
+		SchemaString: schema,
+	}, compiler.AllowUnprefixedObjectType())
+	require.NoError(t, err)
+	require.Len(t, compiled.CaveatDefinitions, 1)
+
+	source, ok, err := GenerateCaveatSource(compiled.CaveatDefinitions[0], caveattypes.Default.TypeSet)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, schema, source)
+}
+
 func TestGenerateNamespace(t *testing.T) {
 	type generatorTest struct {
 		name     string
@@ -150,6 +168,20 @@ func TestGenerateNamespace(t *testing.T) {
 			),
 			`definition foos/test {
 	permission someperm = (rela - relb - rely->relz - nil) + relc
+}`,
+			true,
+		},
+		{
+			"permission with functioned arrows",
+			namespace.Namespace("foos/test",
+				namespace.MustRelation("someperm", namespace.Union(
+					namespace.MustFunctionedTupleToUserset("rely", "all", "relz"),
+					namespace.MustFunctionedTupleToUserset("rely", "any", "relz"),
+					namespace.ComputedUserset("relc"),
+				)),
+			),
+			`definition foos/test {
+	permission someperm = rely.all(relz) + rely.any(relz) + relc
 }`,
 			true,
 		},
@@ -203,9 +203,99 @@ func (p *sourceParser) consumeCaveatParameter() (AstNode, bool) {
 
 	paramNode.MustDecorate(dslshape.NodeCaveatParameterPredicateName, name)
 	paramNode.Connect(dslshape.NodeCaveatParameterPredicateType, p.consumeCaveatTypeReference())
+
+	// Check for a default value.
+	// = literal
+	if _, ok := p.tryConsume(lexer.TokenTypeEquals); ok {
+		defaultValue, ok := p.consumeCaveatParameterDefaultLiteral()
+		if !ok {
+			return paramNode, false
+		}
+
+		paramNode.MustDecorate(dslshape.NodeCaveatParameterPredicateDefaultValue, defaultValue)
+	}
+
 	return paramNode, true
 }
 
+// consumeCaveatParameterDefaultLiteral attempts to consume the literal default value for a
+// caveat parameter: a string, a (possibly negative) number, or `true`/`false`. Returns the
+// raw source text of the literal.
+func (p *sourceParser) consumeCaveatParameterDefaultLiteral() (string, bool) {
+	switch {
+	case p.isToken(lexer.TokenTypeString):
+		token, _ := p.consume(lexer.TokenTypeString)
+		return token.Value, true
+
+	case p.isToken(lexer.TokenTypeMinus):
+		p.consume(lexer.TokenTypeMinus)
+		numeric, ok := p.consumeCaveatParameterNumericLiteral()
+		if !ok {
+			return "", false
+		}
+		return "-" + numeric, true
+
+	case p.isToken(lexer.TokenTypeIdentifier):
+		if p.currentToken.Value == "true" || p.currentToken.Value == "false" {
+			identifier, _ := p.consumeIdentifier()
+			return identifier, true
+		}
+
+		return p.consumeCaveatParameterNumericLiteral()
+
+	default:
+		p.emitErrorf("expected a literal default value for the caveat parameter, found token %v", p.currentToken.Kind)
+		return "", false
+	}
+}
+
+// consumeCaveatParameterNumericLiteral attempts to consume a numeric default value literal
+// for a caveat parameter. Digits lex as identifiers and decimal points as periods, so a
+// numeric literal is an all-digit identifier optionally followed by a period and a second
+// all-digit identifier.
+func (p *sourceParser) consumeCaveatParameterNumericLiteral() (string, bool) {
+	integerPart, ok := p.consumeIdentifier()
+	if !ok {
+		return "", false
+	}
+
+	if !isDigits(integerPart) {
+		p.emitErrorf("expected a literal default value for the caveat parameter, found: %s", integerPart)
+		return "", false
+	}
+
+	if _, ok := p.tryConsume(lexer.TokenTypePeriod); !ok {
+		return integerPart, true
+	}
+
+	fractionalPart, ok := p.consumeIdentifier()
+	if !ok {
+		return "", false
+	}
+
+	if !isDigits(fractionalPart) {
+		p.emitErrorf("expected a literal default value for the caveat parameter, found: %s", fractionalPart)
+		return "", false
+	}
+
+	return integerPart + "." + fractionalPart, true
+}
+
+// isDigits returns whether the given string is non-empty and consists solely of digits.
+func isDigits(value string) bool {
+	if len(value) == 0 {
+		return false
+	}
+
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
 // consumeCaveatTypeReference attempts to consume a caveat type reference.
 // ```typeName<childType>```
 func (p *sourceParser) consumeCaveatTypeReference() AstNode {
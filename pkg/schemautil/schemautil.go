@@ -11,9 +11,10 @@ import (
 )
 
 // ValidateSchemaChanges validates the schema found in the compiled schema and returns a
-// ValidatedSchemaChanges, if fully validated.
-func ValidateSchemaChanges(ctx context.Context, compiled *compiler.CompiledSchema, isAdditiveOnly bool) (*shared.ValidatedSchemaChanges, error) {
-	return ValidateSchemaChangesWithCaveatTypeSet(ctx, compiled, caveattypes.Default.TypeSet, isAdditiveOnly)
+// ValidatedSchemaChanges, if fully validated. If forceAllowedTypeRemoval is true, allowed
+// subject types may be removed from relations even when relationships using them exist.
+func ValidateSchemaChanges(ctx context.Context, compiled *compiler.CompiledSchema, isAdditiveOnly bool, forceAllowedTypeRemoval bool) (*shared.ValidatedSchemaChanges, error) {
+	return ValidateSchemaChangesWithCaveatTypeSet(ctx, compiled, caveattypes.Default.TypeSet, isAdditiveOnly, forceAllowedTypeRemoval)
 }
 
 func ValidateSchemaChangesWithCaveatTypeSet(
@@ -21,8 +22,9 @@ func ValidateSchemaChangesWithCaveatTypeSet(
 	compiled *compiler.CompiledSchema,
 	caveatTypeSet *caveattypes.TypeSet,
 	isAdditiveOnly bool,
+	forceAllowedTypeRemoval bool,
 ) (*shared.ValidatedSchemaChanges, error) {
-	return shared.ValidateSchemaChanges(ctx, compiled, caveatTypeSet, isAdditiveOnly)
+	return shared.ValidateSchemaChanges(ctx, compiled, caveatTypeSet, isAdditiveOnly, forceAllowedTypeRemoval)
 }
 
 // ApplySchemaChanges applies schema changes found in the validated changes struct, via the specified
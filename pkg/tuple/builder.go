@@ -0,0 +1,156 @@
+package tuple
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+// RelationshipBuilder is a fluent builder for relationships. Builders are values: each
+// method returns an updated copy, so a builder (or the relationship it started from) is
+// never mutated by further chaining.
+type RelationshipBuilder struct {
+	rel Relationship
+	err error
+}
+
+// NewRelationship starts building a relationship for the given resource type and ID.
+func NewRelationship(resourceType string, resourceID string) RelationshipBuilder {
+	return RelationshipBuilder{
+		rel: Relationship{
+			RelationshipReference: RelationshipReference{
+				Resource: ObjectAndRelation{
+					ObjectType: resourceType,
+					ObjectID:   resourceID,
+				},
+			},
+		},
+	}
+}
+
+// BuilderFromRelationship starts building from an existing relationship, producing
+// modified copies without mutating the original.
+func BuilderFromRelationship(rel Relationship) RelationshipBuilder {
+	return RelationshipBuilder{rel: rel}
+}
+
+// Relation sets the relation on the resource.
+func (b RelationshipBuilder) Relation(relation string) RelationshipBuilder {
+	b.rel.Resource.Relation = relation
+	return b
+}
+
+// Subject sets the subject to the given type and ID, with the ellipsis relation.
+func (b RelationshipBuilder) Subject(subjectType string, subjectID string) RelationshipBuilder {
+	return b.SubjectWithRelation(subjectType, subjectID, Ellipsis)
+}
+
+// SubjectWithRelation sets the subject to the given type, ID and relation.
+func (b RelationshipBuilder) SubjectWithRelation(subjectType string, subjectID string, relation string) RelationshipBuilder {
+	b.rel.Subject = ObjectAndRelation{
+		ObjectType: subjectType,
+		ObjectID:   subjectID,
+		Relation:   relation,
+	}
+	return b
+}
+
+// WithCaveat sets the caveat on the relationship being built, converting the given context
+// from native Go values. Typed slices and string-keyed maps (e.g. []string, map[string]int)
+// are normalized before conversion.
+func (b RelationshipBuilder) WithCaveat(caveatName string, contexts ...map[string]any) RelationshipBuilder {
+	var context *structpb.Struct
+	if len(contexts) > 0 {
+		combined := map[string]any{}
+		for _, current := range contexts {
+			for key, value := range current {
+				combined[key] = normalizeContextValue(value)
+			}
+		}
+
+		contextStruct, err := structpb.NewStruct(combined)
+		if err != nil {
+			if b.err == nil {
+				b.err = err
+			}
+			return b
+		}
+		context = contextStruct
+	}
+
+	b.rel.OptionalCaveat = &core.ContextualizedCaveat{
+		CaveatName: caveatName,
+		Context:    context,
+	}
+	return b
+}
+
+// WithExpiration sets the expiration on the relationship being built.
+func (b RelationshipBuilder) WithExpiration(expiration time.Time) RelationshipBuilder {
+	b.rel.OptionalExpiration = &expiration
+	return b
+}
+
+// Build validates the relationship and returns it, or an error describing every field
+// that failed validation.
+func (b RelationshipBuilder) Build() (Relationship, error) {
+	if b.err != nil {
+		return Relationship{}, b.err
+	}
+
+	if fieldErrors := b.rel.ValidateDetailed(); len(fieldErrors) > 0 {
+		messages := make([]string, 0, len(fieldErrors))
+		for _, fieldError := range fieldErrors {
+			messages = append(messages, fieldError.Error())
+		}
+		return Relationship{}, errors.New(strings.Join(messages, "; "))
+	}
+
+	return b.rel, nil
+}
+
+// MustBuild validates the relationship and returns it, panicking on error.
+func (b RelationshipBuilder) MustBuild() Relationship {
+	rel, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return rel
+}
+
+// normalizeContextValue converts typed slices and string-keyed maps into the []any and
+// map[string]any forms accepted by structpb. Scalars and already-generic values are
+// returned unchanged; unsupported values are left for structpb to reject.
+func normalizeContextValue(value any) any {
+	switch reflected := reflect.ValueOf(value); reflected.Kind() {
+	case reflect.Slice, reflect.Array:
+		if _, ok := value.([]byte); ok {
+			return value
+		}
+
+		normalized := make([]any, 0, reflected.Len())
+		for i := 0; i < reflected.Len(); i++ {
+			normalized = append(normalized, normalizeContextValue(reflected.Index(i).Interface()))
+		}
+		return normalized
+
+	case reflect.Map:
+		if reflected.Type().Key().Kind() != reflect.String {
+			return value
+		}
+
+		normalized := make(map[string]any, reflected.Len())
+		for _, key := range reflected.MapKeys() {
+			normalized[key.String()] = normalizeContextValue(reflected.MapIndex(key).Interface())
+		}
+		return normalized
+
+	default:
+		return value
+	}
+}
@@ -0,0 +1,128 @@
+package tuple
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelationshipBuilder(t *testing.T) {
+	t.Run("basic relationship", func(t *testing.T) {
+		rel := NewRelationship("document", "doc1").
+			Relation("viewer").
+			Subject("user", "alice").
+			MustBuild()
+		require.Equal(t, MustParse("document:doc1#viewer@user:alice"), rel)
+	})
+
+	t.Run("subject with relation", func(t *testing.T) {
+		rel := NewRelationship("document", "doc1").
+			Relation("viewer").
+			SubjectWithRelation("group", "admins", "members").
+			MustBuild()
+		require.Equal(t, MustParse("document:doc1#viewer@group:admins#members"), rel)
+	})
+
+	t.Run("caveat with native context types", func(t *testing.T) {
+		rel := NewRelationship("document", "doc1").
+			Relation("viewer").
+			Subject("user", "alice").
+			WithCaveat("somecaveat", map[string]any{
+				"count":   42,
+				"ratio":   1.5,
+				"enabled": true,
+				"name":    "tom",
+				"tags":    []string{"a", "b"},
+				"limits":  map[string]int{"max": 10},
+			}).
+			MustBuild()
+
+		require.NotNil(t, rel.OptionalCaveat)
+		require.Equal(t, "somecaveat", rel.OptionalCaveat.CaveatName)
+
+		context := rel.OptionalCaveat.Context.AsMap()
+		require.Equal(t, float64(42), context["count"])
+		require.Equal(t, 1.5, context["ratio"])
+		require.Equal(t, true, context["enabled"])
+		require.Equal(t, "tom", context["name"])
+		require.Equal(t, []any{"a", "b"}, context["tags"])
+		require.Equal(t, map[string]any{"max": float64(10)}, context["limits"])
+	})
+
+	t.Run("expiration", func(t *testing.T) {
+		expiration := time.Date(2322, 1, 2, 3, 4, 5, 0, time.UTC)
+		rel := NewRelationship("document", "doc1").
+			Relation("viewer").
+			Subject("user", "alice").
+			WithExpiration(expiration).
+			MustBuild()
+		require.NotNil(t, rel.OptionalExpiration)
+		require.True(t, rel.OptionalExpiration.Equal(expiration))
+	})
+}
+
+func TestRelationshipBuilderFromExisting(t *testing.T) {
+	original := MustParse("document:doc1#viewer@user:alice[somecaveat:{\"hi\":\"there\"}]")
+
+	modified := BuilderFromRelationship(original).
+		Subject("user", "bob").
+		WithCaveat("anothercaveat", map[string]any{"count": 1}).
+		MustBuild()
+
+	require.Equal(t, "bob", modified.Subject.ObjectID)
+	require.Equal(t, "anothercaveat", modified.OptionalCaveat.CaveatName)
+
+	// The original is unchanged.
+	require.Equal(t, MustParse("document:doc1#viewer@user:alice[somecaveat:{\"hi\":\"there\"}]"), original)
+}
+
+func TestRelationshipBuilderValidationErrors(t *testing.T) {
+	tcs := []struct {
+		name          string
+		builder       RelationshipBuilder
+		expectedError string
+	}{
+		{
+			name:          "missing relation",
+			builder:       NewRelationship("document", "doc1").Subject("user", "alice"),
+			expectedError: "resource.relation",
+		},
+		{
+			name:          "missing subject",
+			builder:       NewRelationship("document", "doc1").Relation("viewer"),
+			expectedError: "subject.object_type",
+		},
+		{
+			name:          "invalid resource type",
+			builder:       NewRelationship("docu ment", "doc1").Relation("viewer").Subject("user", "alice"),
+			expectedError: "resource.object_type",
+		},
+		{
+			name:          "wildcard subject with relation",
+			builder:       NewRelationship("document", "doc1").Relation("viewer").SubjectWithRelation("user", "*", "members"),
+			expectedError: "subject.relation: wildcard subjects may not specify a relation",
+		},
+		{
+			name: "unsupported caveat context value",
+			builder: NewRelationship("document", "doc1").Relation("viewer").Subject("user", "alice").
+				WithCaveat("somecaveat", map[string]any{"ch": make(chan int)}),
+			expectedError: "invalid type",
+		},
+		{
+			name: "expiration in the past",
+			builder: NewRelationship("document", "doc1").Relation("viewer").Subject("user", "alice").
+				WithExpiration(time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)),
+			expectedError: "expiration must be set to a future time",
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.builder.Build()
+			require.ErrorContains(t, err, tc.expectedError)
+			require.Panics(t, func() { tc.builder.MustBuild() })
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package tuple
 
 import (
+	"cmp"
+	"slices"
+	"strings"
 	"time"
 
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 )
@@ -18,11 +22,124 @@ func ONREqualOrWildcard(onr, target ObjectAndRelation) bool {
 	return ONREqual(onr, target) || (onr.ObjectID == PublicWildcard && onr.ObjectType == target.ObjectType)
 }
 
+// IsWildcardSubject returns true if the given subject is a wildcard (`type:*`).
+func IsWildcardSubject(onr ObjectAndRelation) bool {
+	return onr.ObjectID == PublicWildcard
+}
+
+// SubjectCovers returns true if the stored subject covers the requested subject: either the
+// two are exactly equal, or the stored subject is a wildcard of the requested subject's
+// type and the requested subject is concrete. A wildcard never covers subjects with a
+// non-ellipsis relation, nor another wildcard that is not exactly equal to it.
+func SubjectCovers(stored, requested ObjectAndRelation) bool {
+	// A wildcard subject carrying a relation is invalid and covers nothing.
+	if IsWildcardSubject(stored) && stored.Relation != Ellipsis {
+		return false
+	}
+
+	if ONREqual(stored, requested) {
+		return true
+	}
+
+	return IsWildcardSubject(stored) &&
+		stored.ObjectType == requested.ObjectType &&
+		!IsWildcardSubject(requested) &&
+		requested.Relation == Ellipsis
+}
+
+// SplitWildcards partitions the given relationships into those with concrete subjects and
+// those with wildcard subjects, preserving their relative order.
+func SplitWildcards(rels []Relationship) (concrete, wildcard []Relationship) {
+	for _, rel := range rels {
+		if IsWildcardSubject(rel.Subject) {
+			wildcard = append(wildcard, rel)
+		} else {
+			concrete = append(concrete, rel)
+		}
+	}
+	return concrete, wildcard
+}
+
 // Equal returns true if the two relationships are exactly the same.
 func Equal(lhs, rhs Relationship) bool {
 	return ONREqual(lhs.Resource, rhs.Resource) && ONREqual(lhs.Subject, rhs.Subject) && caveatEqual(lhs.OptionalCaveat, rhs.OptionalCaveat) && expirationEqual(lhs.OptionalExpiration, rhs.OptionalExpiration)
 }
 
+// Compare returns a total ordering of relationships, ordering by resource type, resource ID,
+// relation, subject type, subject ID and subject relation, followed by caveat name, canonical
+// caveat context bytes and expiration. The shared prefix matches the datastore's
+// sort-by-resource ordering, so cursors built over either agree.
+func Compare(lhs, rhs Relationship) int {
+	return cmp.Or(
+		strings.Compare(lhs.Resource.ObjectType, rhs.Resource.ObjectType),
+		strings.Compare(lhs.Resource.ObjectID, rhs.Resource.ObjectID),
+		strings.Compare(lhs.Resource.Relation, rhs.Resource.Relation),
+		strings.Compare(lhs.Subject.ObjectType, rhs.Subject.ObjectType),
+		strings.Compare(lhs.Subject.ObjectID, rhs.Subject.ObjectID),
+		strings.Compare(lhs.Subject.Relation, rhs.Subject.Relation),
+		compareCaveats(lhs.OptionalCaveat, rhs.OptionalCaveat),
+		compareExpirations(lhs.OptionalExpiration, rhs.OptionalExpiration),
+	)
+}
+
+// Sort sorts the given relationships in place into the total ordering defined by Compare.
+func Sort(rels []Relationship) {
+	slices.SortFunc(rels, Compare)
+}
+
+// IsSorted returns true if the given relationships are in the total ordering defined by
+// Compare.
+func IsSorted(rels []Relationship) bool {
+	return slices.IsSortedFunc(rels, Compare)
+}
+
+func compareCaveats(lhs, rhs *core.ContextualizedCaveat) int {
+	if lhs == nil && rhs == nil {
+		return 0
+	}
+
+	// Relationships without a caveat sort before those with one.
+	if lhs == nil {
+		return -1
+	}
+
+	if rhs == nil {
+		return 1
+	}
+
+	return cmp.Or(
+		strings.Compare(lhs.CaveatName, rhs.CaveatName),
+		strings.Compare(canonicalContextString(lhs.Context), canonicalContextString(rhs.Context)),
+	)
+}
+
+func canonicalContextString(context *structpb.Struct) string {
+	if context == nil || len(context.Fields) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeCanonicalJSONStruct(&sb, context)
+	return sb.String()
+}
+
+func compareExpirations(lhs, rhs *time.Time) int {
+	if lhs == nil && rhs == nil {
+		return 0
+	}
+
+	// Relationships without an expiration sort before those with one.
+	if lhs == nil {
+		return -1
+	}
+
+	if rhs == nil {
+		return 1
+	}
+
+	return lhs.Compare(*rhs)
+}
+
 func expirationEqual(lhs, rhs *time.Time) bool {
 	if lhs == nil && rhs == nil {
 		return true
@@ -267,3 +267,213 @@ func TestEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestCompare(t *testing.T) {
+	orderedTestCases := []struct {
+		name string
+		lhs  Relationship
+		rhs  Relationship
+	}{
+		{
+			name: "resource type",
+			lhs:  MustParse("adoc:foo#viewer@user:tom"),
+			rhs:  MustParse("bdoc:foo#viewer@user:tom"),
+		},
+		{
+			name: "resource ID",
+			lhs:  MustParse("document:abc#viewer@user:tom"),
+			rhs:  MustParse("document:def#viewer@user:tom"),
+		},
+		{
+			name: "relation",
+			lhs:  MustParse("document:foo#editor@user:tom"),
+			rhs:  MustParse("document:foo#viewer@user:tom"),
+		},
+		{
+			name: "subject type",
+			lhs:  MustParse("document:foo#viewer@group:eng#member"),
+			rhs:  MustParse("document:foo#viewer@user:tom"),
+		},
+		{
+			name: "subject ID",
+			lhs:  MustParse("document:foo#viewer@user:fred"),
+			rhs:  MustParse("document:foo#viewer@user:tom"),
+		},
+		{
+			name: "subject relation",
+			lhs:  MustParse("document:foo#viewer@group:eng#member"),
+			rhs:  MustParse("document:foo#viewer@group:eng#xmember"),
+		},
+		{
+			name: "caveat-only difference: no caveat sorts first",
+			lhs:  MustParse("document:foo#viewer@user:tom"),
+			rhs:  MustParse("document:foo#viewer@user:tom[somecaveat]"),
+		},
+		{
+			name: "caveat-only difference: caveat name",
+			lhs:  MustParse("document:foo#viewer@user:tom[acaveat]"),
+			rhs:  MustParse("document:foo#viewer@user:tom[bcaveat]"),
+		},
+		{
+			name: "caveat-only difference: context",
+			lhs:  MustParse(`document:foo#viewer@user:tom[somecaveat:{"hi":"aaa"}]`),
+			rhs:  MustParse(`document:foo#viewer@user:tom[somecaveat:{"hi":"bbb"}]`),
+		},
+		{
+			name: "expiration-only difference: no expiration sorts first",
+			lhs:  MustParse("document:foo#viewer@user:tom"),
+			rhs:  MustParse("document:foo#viewer@user:tom[expiration:2020-01-01T00:00:00Z]"),
+		},
+		{
+			name: "expiration-only difference: earlier expiration sorts first",
+			lhs:  MustParse("document:foo#viewer@user:tom[expiration:2020-01-01T00:00:00Z]"),
+			rhs:  MustParse("document:foo#viewer@user:tom[expiration:2020-01-02T00:00:00Z]"),
+		},
+	}
+
+	for _, tc := range orderedTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+			require.Negative(Compare(tc.lhs, tc.rhs))
+			require.Positive(Compare(tc.rhs, tc.lhs))
+			require.Zero(Compare(tc.lhs, tc.lhs))
+			require.Zero(Compare(tc.rhs, tc.rhs))
+		})
+	}
+
+	t.Run("context key order does not affect ordering", func(t *testing.T) {
+		base := makeRel(
+			StringToONR("document", "foo", "viewer"),
+			StringToONR("user", "tom", "..."),
+		)
+
+		first := MustWithCaveat(base, "somecaveat", map[string]any{"a": 1, "b": 2})
+		second := MustWithCaveat(base, "somecaveat", map[string]any{"b": 2, "a": 1})
+		require.Zero(t, Compare(first, second))
+	})
+}
+
+func TestSortAndIsSorted(t *testing.T) {
+	require := require.New(t)
+
+	sorted := []Relationship{
+		MustParse("document:foo#viewer@user:tom"),
+		MustParse("document:foo#viewer@user:tom[acaveat]"),
+		MustParse("document:foo#viewer@user:tom[somecaveat]"),
+		MustParse(`document:foo#viewer@user:tom[somecaveat:{"hi":"there"}]`),
+		MustParse("document:foo#viewer@user:tom[somecaveat][expiration:2020-01-01T00:00:00Z]"),
+		MustParse("document:foo#viewer@user:tom[expiration:2020-01-01T00:00:00Z]"),
+	}
+
+	shuffled := []Relationship{sorted[4], sorted[0], sorted[5], sorted[2], sorted[1], sorted[3]}
+	require.False(IsSorted(shuffled))
+
+	Sort(shuffled)
+	require.True(IsSorted(shuffled))
+
+	// The expiration-only entry sorts before the caveated entries, and the caveated entry
+	// without context sorts before the one with context, even when expiring.
+	expected := []Relationship{sorted[0], sorted[5], sorted[1], sorted[2], sorted[4], sorted[3]}
+	for index, rel := range expected {
+		require.True(Equal(rel, shuffled[index]), "mismatch at index %d: %s vs %s", index, MustString(rel), MustString(shuffled[index]))
+	}
+}
+
+func TestIsWildcardSubject(t *testing.T) {
+	require.True(t, IsWildcardSubject(StringToONR("user", "*", "...")))
+	require.False(t, IsWildcardSubject(StringToONR("user", "tom", "...")))
+	require.False(t, IsWildcardSubject(StringToONR("group", "eng", "member")))
+}
+
+func TestSubjectCovers(t *testing.T) {
+	tests := []struct {
+		name      string
+		stored    ObjectAndRelation
+		requested ObjectAndRelation
+		want      bool
+	}{
+		{
+			name:      "exact match",
+			stored:    StringToONR("user", "tom", "..."),
+			requested: StringToONR("user", "tom", "..."),
+			want:      true,
+		},
+		{
+			name:      "exact match with relation",
+			stored:    StringToONR("group", "eng", "member"),
+			requested: StringToONR("group", "eng", "member"),
+			want:      true,
+		},
+		{
+			name:      "wildcard covers concrete subject",
+			stored:    StringToONR("user", "*", "..."),
+			requested: StringToONR("user", "tom", "..."),
+			want:      true,
+		},
+		{
+			name:      "wildcard does not cover subject with relation",
+			stored:    StringToONR("group", "*", "..."),
+			requested: StringToONR("group", "eng", "member"),
+			want:      false,
+		},
+		{
+			name:      "wildcard vs identical wildcard",
+			stored:    StringToONR("user", "*", "..."),
+			requested: StringToONR("user", "*", "..."),
+			want:      true,
+		},
+		{
+			name:      "wildcard vs wildcard of another type",
+			stored:    StringToONR("user", "*", "..."),
+			requested: StringToONR("group", "*", "..."),
+			want:      false,
+		},
+		{
+			name:      "wildcard with relation is invalid and covers nothing",
+			stored:    StringToONR("group", "*", "member"),
+			requested: StringToONR("group", "eng", "member"),
+			want:      false,
+		},
+		{
+			name:      "wildcard with relation does not cover itself",
+			stored:    StringToONR("group", "*", "member"),
+			requested: StringToONR("group", "*", "member"),
+			want:      false,
+		},
+		{
+			name:      "type mismatch",
+			stored:    StringToONR("user", "*", "..."),
+			requested: StringToONR("serviceaccount", "sa1", "..."),
+			want:      false,
+		},
+		{
+			name:      "concrete subject does not cover wildcard",
+			stored:    StringToONR("user", "tom", "..."),
+			requested: StringToONR("user", "*", "..."),
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, SubjectCovers(tt.stored, tt.requested))
+		})
+	}
+}
+
+func TestSplitWildcards(t *testing.T) {
+	rels := []Relationship{
+		MustParse("document:first#viewer@user:tom"),
+		MustParse("document:first#viewer@user:*"),
+		MustParse("document:second#viewer@group:eng#member"),
+		MustParse("document:second#viewer@group:*"),
+	}
+
+	concrete, wildcard := SplitWildcards(rels)
+	require.Equal(t, []Relationship{rels[0], rels[2]}, concrete)
+	require.Equal(t, []Relationship{rels[1], rels[3]}, wildcard)
+
+	concrete, wildcard = SplitWildcards(nil)
+	require.Empty(t, concrete)
+	require.Empty(t, wildcard)
+}
@@ -0,0 +1,150 @@
+// Package enc provides encoders and decoders for flat, interchange-friendly relationship
+// formats: JSON-lines and CSV. Both formats share the same columns (resource_type,
+// resource_id, relation, subject_type, subject_id, subject_relation, caveat_name,
+// caveat_context, expiration), with the caveat context serialized as canonical JSON and
+// the expiration as an RFC 3339 timestamp.
+package enc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/tuple"
+)
+
+// columnNames are the shared column names of the flat relationship formats, in order.
+var columnNames = []string{
+	"resource_type",
+	"resource_id",
+	"relation",
+	"subject_type",
+	"subject_id",
+	"subject_relation",
+	"caveat_name",
+	"caveat_context",
+	"expiration",
+}
+
+// RowError is the error returned by the decoders when a row fails to decode or validate,
+// carrying the 1-indexed row number of the offending row. For CSV, the header is row 1.
+type RowError struct {
+	error
+
+	// RowNumber is the 1-indexed row on which decoding failed.
+	RowNumber int
+}
+
+// Unwrap returns the underlying cause of the row error.
+func (err RowError) Unwrap() error {
+	return errors.Unwrap(err.error)
+}
+
+func newRowError(rowNumber int, cause error) RowError {
+	return RowError{
+		error:     fmt.Errorf("error decoding relationship on row %d: %w", rowNumber, cause),
+		RowNumber: rowNumber,
+	}
+}
+
+// relationshipFields holds the string form of each column for a single relationship.
+type relationshipFields struct {
+	resourceType    string
+	resourceID      string
+	relation        string
+	subjectType     string
+	subjectID       string
+	subjectRelation string
+	caveatName      string
+	caveatContext   string
+	expiration      string
+}
+
+// fieldsFromRelationship flattens a relationship into its column values. Optional columns
+// are empty strings when unset; an ellipsis subject relation is written as empty.
+func fieldsFromRelationship(rel tuple.Relationship) relationshipFields {
+	fields := relationshipFields{
+		resourceType: rel.Resource.ObjectType,
+		resourceID:   rel.Resource.ObjectID,
+		relation:     rel.Resource.Relation,
+		subjectType:  rel.Subject.ObjectType,
+		subjectID:    rel.Subject.ObjectID,
+	}
+
+	if rel.Subject.Relation != tuple.Ellipsis {
+		fields.subjectRelation = rel.Subject.Relation
+	}
+
+	if rel.OptionalCaveat != nil && rel.OptionalCaveat.CaveatName != "" {
+		fields.caveatName = rel.OptionalCaveat.CaveatName
+		fields.caveatContext = tuple.CanonicalCaveatContextString(rel.OptionalCaveat.Context)
+	}
+
+	if rel.OptionalExpiration != nil {
+		fields.expiration = rel.OptionalExpiration.Format(time.RFC3339Nano)
+	}
+
+	return fields
+}
+
+// relationshipFromFields builds and validates a relationship from its column values.
+func relationshipFromFields(fields relationshipFields) (tuple.Relationship, error) {
+	subjectRelation := fields.subjectRelation
+	if subjectRelation == "" {
+		subjectRelation = tuple.Ellipsis
+	}
+
+	rel := tuple.Relationship{
+		RelationshipReference: tuple.RelationshipReference{
+			Resource: tuple.ObjectAndRelation{
+				ObjectType: fields.resourceType,
+				ObjectID:   fields.resourceID,
+				Relation:   fields.relation,
+			},
+			Subject: tuple.ObjectAndRelation{
+				ObjectType: fields.subjectType,
+				ObjectID:   fields.subjectID,
+				Relation:   subjectRelation,
+			},
+		},
+	}
+
+	if fields.caveatName == "" && fields.caveatContext != "" {
+		return tuple.Relationship{}, errors.New("caveat_context requires a caveat_name")
+	}
+
+	if fields.caveatName != "" {
+		caveat := &core.ContextualizedCaveat{CaveatName: fields.caveatName}
+		if fields.caveatContext != "" {
+			context := &structpb.Struct{}
+			if err := protojson.Unmarshal([]byte(fields.caveatContext), context); err != nil {
+				return tuple.Relationship{}, fmt.Errorf("caveat_context must be a valid JSON object: %w", err)
+			}
+			caveat.Context = context
+		}
+		rel.OptionalCaveat = caveat
+	}
+
+	if fields.expiration != "" {
+		expiration, err := time.Parse(time.RFC3339Nano, fields.expiration)
+		if err != nil {
+			return tuple.Relationship{}, fmt.Errorf("expiration must be an RFC 3339 formatted timestamp: %w", err)
+		}
+		rel.OptionalExpiration = &expiration
+	}
+
+	if fieldErrors := rel.ValidateDetailed(); len(fieldErrors) > 0 {
+		messages := make([]string, 0, len(fieldErrors))
+		for _, fieldError := range fieldErrors {
+			messages = append(messages, fieldError.Error())
+		}
+		return tuple.Relationship{}, errors.New(strings.Join(messages, "; "))
+	}
+
+	return rel, nil
+}
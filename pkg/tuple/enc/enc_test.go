@@ -0,0 +1,232 @@
+package enc
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/pkg/tuple"
+)
+
+var encodingTestRelationships = []tuple.Relationship{
+	tuple.MustParse("document:firstdoc#viewer@user:tom"),
+	tuple.MustParse("document:firstdoc#viewer@group:admins#members"),
+	tuple.MustParse("document:firstdoc#viewer@user:*"),
+	tuple.MustWithCaveat(tuple.MustParse("document:firstdoc#viewer@user:fred"), "somecaveat"),
+	tuple.MustWithCaveat(
+		tuple.MustParse("document:firstdoc#viewer@user:sarah"),
+		"somecaveat",
+		map[string]any{
+			"hi":     "there",
+			"nested": map[string]any{"count": 42, "items": []any{"a", "b"}},
+		},
+	),
+	tuple.MustParse("document:firstdoc#viewer@user:tom[expiration:2322-01-02T03:04:05.0000006Z]"),
+	tuple.MustParse("document:firstdoc#viewer@user:tom[somecaveat:{\"hi\":\"there\"}][expiration:2322-01-02T03:04:05Z]"),
+}
+
+func requireSameRelationships(t *testing.T, expected []tuple.Relationship, found []tuple.Relationship) {
+	t.Helper()
+
+	require.Len(t, found, len(expected))
+	for index, rel := range expected {
+		require.Equal(t, tuple.CanonicalString(rel), tuple.CanonicalString(found[index]))
+	}
+}
+
+func TestJSONLRoundTrip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	encoder := NewJSONLEncoder(buf)
+	for _, rel := range encodingTestRelationships {
+		require.NoError(t, encoder.Append(rel))
+	}
+
+	decoder := NewJSONLDecoder(buf)
+	found := make([]tuple.Relationship, 0, len(encodingTestRelationships))
+	for {
+		rel, err := decoder.Next()
+		require.NoError(t, err)
+		if rel == nil {
+			break
+		}
+		found = append(found, *rel)
+	}
+
+	requireSameRelationships(t, encodingTestRelationships, found)
+}
+
+func TestJSONLOmitsEmptyOptionalFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	encoder := NewJSONLEncoder(buf)
+	require.NoError(t, encoder.Append(tuple.MustParse("document:firstdoc#viewer@user:tom")))
+
+	line := strings.TrimSpace(buf.String())
+	require.NotContains(t, line, "subject_relation")
+	require.NotContains(t, line, "caveat_name")
+	require.NotContains(t, line, "caveat_context")
+	require.NotContains(t, line, "expiration")
+}
+
+func TestJSONLDecoderReportsRowNumbers(t *testing.T) {
+	tcs := []struct {
+		name              string
+		contents          string
+		expectedRowNumber int
+		expectedError     string
+	}{
+		{
+			name:              "malformed JSON",
+			contents:          `{"resource_type": "document"` + "\n",
+			expectedRowNumber: 1,
+			expectedError:     "error decoding relationship on row 1",
+		},
+		{
+			name: "invalid relationship on later row",
+			contents: `{"resource_type":"document","resource_id":"firstdoc","relation":"viewer","subject_type":"user","subject_id":"tom"}
+
+{"resource_type":"docu ment","resource_id":"firstdoc","relation":"viewer","subject_type":"user","subject_id":"tom"}
+`,
+			expectedRowNumber: 3,
+			expectedError:     "resource.object_type: invalid object type",
+		},
+		{
+			name:              "context without caveat name",
+			contents:          `{"resource_type":"document","resource_id":"firstdoc","relation":"viewer","subject_type":"user","subject_id":"tom","caveat_context":{"hi":"there"}}` + "\n",
+			expectedRowNumber: 1,
+			expectedError:     "caveat_context requires a caveat_name",
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			decoder := NewJSONLDecoder(strings.NewReader(tc.contents))
+			var err error
+			for err == nil {
+				var rel *tuple.Relationship
+				rel, err = decoder.Next()
+				if rel == nil && err == nil {
+					require.Fail(t, "expected a decode error")
+				}
+			}
+
+			require.ErrorContains(t, err, tc.expectedError)
+
+			var rowError RowError
+			require.True(t, errors.As(err, &rowError))
+			require.Equal(t, tc.expectedRowNumber, rowError.RowNumber)
+		})
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	encoder := NewCSVEncoder(buf)
+	for _, rel := range encodingTestRelationships {
+		require.NoError(t, encoder.Append(rel))
+	}
+	require.NoError(t, encoder.Flush())
+
+	decoder := NewCSVDecoder(buf)
+	found := make([]tuple.Relationship, 0, len(encodingTestRelationships))
+	for {
+		rel, err := decoder.Next()
+		require.NoError(t, err)
+		if rel == nil {
+			break
+		}
+		found = append(found, *rel)
+	}
+
+	requireSameRelationships(t, encodingTestRelationships, found)
+}
+
+func TestCSVDecoderValidatesHeader(t *testing.T) {
+	decoder := NewCSVDecoder(strings.NewReader("resource_type,resource_id\ndocument,firstdoc\n"))
+	_, err := decoder.Next()
+	require.ErrorContains(t, err, "unexpected CSV header")
+
+	var rowError RowError
+	require.True(t, errors.As(err, &rowError))
+	require.Equal(t, 1, rowError.RowNumber)
+}
+
+func TestCSVDecoderReportsRowNumbers(t *testing.T) {
+	contents := strings.Join(columnNames, ",") + "\n" +
+		"document,firstdoc,viewer,user,tom,,,,\n" +
+		"docu ment,firstdoc,viewer,user,tom,,,,\n"
+
+	decoder := NewCSVDecoder(strings.NewReader(contents))
+	rel, err := decoder.Next()
+	require.NoError(t, err)
+	require.NotNil(t, rel)
+
+	_, err = decoder.Next()
+	require.ErrorContains(t, err, "resource.object_type")
+
+	var rowError RowError
+	require.True(t, errors.As(err, &rowError))
+	require.Equal(t, 3, rowError.RowNumber)
+}
+
+func TestCSVDecoderEmptyOptionalColumns(t *testing.T) {
+	contents := strings.Join(columnNames, ",") + "\n" +
+		"document,firstdoc,viewer,user,tom,,,,\n"
+
+	decoder := NewCSVDecoder(strings.NewReader(contents))
+	rel, err := decoder.Next()
+	require.NoError(t, err)
+	require.NotNil(t, rel)
+	require.Equal(t, tuple.Ellipsis, rel.Subject.Relation)
+	require.Nil(t, rel.OptionalCaveat)
+	require.Nil(t, rel.OptionalExpiration)
+}
+
+func TestCSVDecoderEmptyInput(t *testing.T) {
+	decoder := NewCSVDecoder(strings.NewReader(""))
+	rel, err := decoder.Next()
+	require.NoError(t, err)
+	require.Nil(t, rel)
+}
+
+func FuzzCSVQuoting(f *testing.F) {
+	f.Add(`simple`)
+	f.Add(`with,commas,and,"quotes"`)
+	f.Add("newlines\nand\r\ncarriage returns")
+	f.Add(`trailing backslash \`)
+	f.Add("unicode ☃ and controls \t\x00")
+
+	f.Fuzz(func(t *testing.T, contextValue string) {
+		if !utf8.ValidString(contextValue) {
+			t.Skip("caveat context strings must be valid UTF-8")
+		}
+
+		expiration := time.Date(2322, 1, 2, 3, 4, 5, 0, time.UTC)
+		rel := tuple.MustWithCaveat(
+			tuple.MustParse("document:firstdoc#viewer@user:tom"),
+			"somecaveat",
+			map[string]any{"value": contextValue},
+		)
+		rel.OptionalExpiration = &expiration
+
+		buf := &bytes.Buffer{}
+		encoder := NewCSVEncoder(buf)
+		require.NoError(t, encoder.Append(rel))
+		require.NoError(t, encoder.Flush())
+
+		decoder := NewCSVDecoder(buf)
+		found, err := decoder.Next()
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		require.Equal(t, tuple.CanonicalString(rel), tuple.CanonicalString(*found))
+
+		rel2, err := decoder.Next()
+		require.NoError(t, err)
+		require.Nil(t, rel2)
+	})
+}
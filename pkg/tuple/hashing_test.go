@@ -2,6 +2,8 @@ package tuple
 
 import (
 	b64 "encoding/base64"
+	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -35,3 +37,88 @@ func TestCanonicalBytes(t *testing.T) {
 		})
 	}
 }
+
+func TestHashIsStable(t *testing.T) {
+	// NOTE: these values must *not* change across releases; persisted structures rely on
+	// the hash remaining stable.
+	hash, err := Hash(MustParse("document:foo#viewer@user:tom"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x67c5638806b04074), hash)
+
+	hash, err = Hash(MustParse(`document:foo#viewer@user:tom[somecaveat:{"hi":"there"}][expiration:2020-01-01T00:00:00Z]`))
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x1f9f2e78e3f2aa78), hash)
+
+	require.Equal(t, uint64(0x67c5638806b04074), HashReference(MustParse("document:foo#viewer@user:tom").RelationshipReference))
+}
+
+func TestHashMatchesReferenceHash(t *testing.T) {
+	for _, tc := range testCases {
+		tc := tc
+		if tc.relFormat.Resource.ObjectType == "" || tc.relFormat.OptionalCaveat != nil || tc.relFormat.OptionalExpiration != nil {
+			continue
+		}
+
+		t.Run(tc.input, func(t *testing.T) {
+			hash, err := Hash(tc.relFormat)
+			require.NoError(t, err)
+			require.Equal(t, hash, HashReference(tc.relFormat.RelationshipReference))
+		})
+	}
+}
+
+func TestHashCollisions(t *testing.T) {
+	require := require.New(t)
+
+	seen := make(map[uint64]Relationship, 100_000)
+	for resourceID := 0; resourceID < 1_000; resourceID++ {
+		for subjectID := 0; subjectID < 100; subjectID++ {
+			rel := makeRel(
+				StringToONR("document", strconv.Itoa(resourceID), "viewer"),
+				StringToONR("user", strconv.Itoa(subjectID), "..."),
+			)
+
+			hash, err := Hash(rel)
+			require.NoError(err)
+
+			if existing, ok := seen[hash]; ok {
+				require.True(Equal(existing, rel), "hash collision between %s and %s", MustString(existing), MustString(rel))
+			}
+			seen[hash] = rel
+		}
+	}
+}
+
+func BenchmarkHash(b *testing.B) {
+	rel := MustParse(`document:foo#viewer@user:tom[somecaveat:{"hi":"there"}]`)
+
+	b.Run("xxhash canonical bytes", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := Hash(rel); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("sprintf", func(b *testing.B) {
+		for b.Loop() {
+			_ = fmt.Sprintf("%s:%s#%s@%s:%s#%s%s", rel.Resource.ObjectType, rel.Resource.ObjectID, rel.Resource.Relation, rel.Subject.ObjectType, rel.Subject.ObjectID, rel.Subject.Relation, rel.OptionalCaveat.String())
+		}
+	})
+}
+
+func BenchmarkHashReference(b *testing.B) {
+	ref := MustParse("document:foo#viewer@user:tom").RelationshipReference
+
+	b.Run("xxhash", func(b *testing.B) {
+		for b.Loop() {
+			_ = HashReference(ref)
+		}
+	})
+
+	b.Run("sprintf", func(b *testing.B) {
+		for b.Loop() {
+			_ = fmt.Sprintf("%s:%s#%s@%s:%s#%s", ref.Resource.ObjectType, ref.Resource.ObjectID, ref.Resource.Relation, ref.Subject.ObjectType, ref.Subject.ObjectID, ref.Subject.Relation)
+		}
+	})
+}
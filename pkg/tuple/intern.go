@@ -0,0 +1,74 @@
+package tuple
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// internerShardCount is the number of shards in the string interner; sharding keeps lock
+// contention low when many goroutines decode relationships concurrently.
+const internerShardCount = 32
+
+// interner is the process-wide interner used by InternONR. Object types and relations are
+// drawn from the small fixed set defined by the schema, so the interner stays tiny even
+// when loading millions of relationships.
+var interner = newStringInterner()
+
+type stringInterner struct {
+	shards [internerShardCount]internerShard
+}
+
+type internerShard struct {
+	lock    sync.RWMutex
+	strings map[string]string // GUARDED_BY(lock)
+}
+
+func newStringInterner() *stringInterner {
+	si := &stringInterner{}
+	for index := range si.shards {
+		si.shards[index].strings = make(map[string]string)
+	}
+	return si
+}
+
+func (si *stringInterner) intern(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	shard := &si.shards[xxhash.Sum64String(s)%internerShardCount]
+
+	shard.lock.RLock()
+	interned, ok := shard.strings[s]
+	shard.lock.RUnlock()
+	if ok {
+		return interned
+	}
+
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	if interned, ok := shard.strings[s]; ok {
+		return interned
+	}
+
+	// NOTE: Clone detaches the string from any larger backing buffer (such as a scanned
+	// database row) before it is retained by the interner.
+	cloned := strings.Clone(s)
+	shard.strings[cloned] = cloned
+	return cloned
+}
+
+// InternONR returns a copy of the given ObjectAndRelation whose object type and relation
+// share their backing strings with all other interned values, cutting memory usage on large
+// result sets. Object IDs are left untouched, as their cardinality is unbounded.
+//
+// Interning only changes which buffers back the strings: the returned value compares equal
+// to, and hashes identically with, the original.
+func InternONR(onr ObjectAndRelation) ObjectAndRelation {
+	onr.ObjectType = interner.intern(onr.ObjectType)
+	onr.Relation = interner.intern(onr.Relation)
+	return onr
+}
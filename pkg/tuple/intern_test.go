@@ -0,0 +1,96 @@
+package tuple
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInternONR(t *testing.T) {
+	original := StringToONR("document", "somedoc", "viewer")
+	interned := InternONR(original)
+
+	// Interned values compare equal to, and hash identically with, the originals.
+	require.True(t, ONREqual(original, interned))
+	require.Equal(t, original, interned)
+
+	originalRel := makeRel(original, StringToONR("user", "tom", "..."))
+	internedRel := makeRel(interned, InternONR(StringToONR("user", "tom", "...")))
+	require.Equal(t, HashReference(originalRel.RelationshipReference), HashReference(internedRel.RelationshipReference))
+
+	originalHash, err := Hash(originalRel)
+	require.NoError(t, err)
+	internedHash, err := Hash(internedRel)
+	require.NoError(t, err)
+	require.Equal(t, originalHash, internedHash)
+
+	// Repeated interning of distinct-but-equal strings yields the same backing buffers.
+	other := InternONR(StringToONR(strings.Clone("document"), "otherdoc", strings.Clone("viewer")))
+	require.Same(t, unsafe.StringData(interned.ObjectType), unsafe.StringData(other.ObjectType))
+	require.Same(t, unsafe.StringData(interned.Relation), unsafe.StringData(other.Relation))
+}
+
+func TestInternONRConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for goroutine := 0; goroutine < 8; goroutine++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1_000; i++ {
+				onr := InternONR(StringToONR("document", strconv.Itoa(i), "viewer"))
+				if !ONREqual(onr, StringToONR("document", strconv.Itoa(i), "viewer")) {
+					panic("interned ONR does not match original")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkInternONRLoad(b *testing.B) {
+	const relCount = 1_000_000
+
+	// Builds relationships from freshly allocated strings, as the datastore decoding paths
+	// do when scanning rows.
+	load := func(intern bool) []Relationship {
+		rels := make([]Relationship, 0, relCount)
+		for i := 0; i < relCount; i++ {
+			resource := StringToONR(strings.Clone("document"), strconv.Itoa(i), strings.Clone("viewer"))
+			subject := StringToONR(strings.Clone("user"), strconv.Itoa(i), strings.Clone("..."))
+			if intern {
+				resource = InternONR(resource)
+				subject = InternONR(subject)
+			}
+			rels = append(rels, makeRel(resource, subject))
+		}
+		return rels
+	}
+
+	for _, tc := range []struct {
+		name   string
+		intern bool
+	}{
+		{"without interning", false},
+		{"with interning", true},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			var retained []Relationship
+			for b.Loop() {
+				retained = load(tc.intern)
+			}
+
+			runtime.GC()
+			var ms runtime.MemStats
+			runtime.ReadMemStats(&ms)
+			b.ReportMetric(float64(ms.HeapAlloc), "heap-bytes")
+			runtime.KeepAlive(retained)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package tuple
+
+// ReferenceKey is a flattened, explicitly comparable form of a RelationshipReference.
+//
+// It holds exactly six string fields and no pointers, slices or maps, so it is guaranteed
+// to be valid as a Go map key: two keys compare equal with == exactly when the underlying
+// references identify the same resource and subject. Prefer it over maps keyed by
+// formatted tuple strings, which allocate on every lookup.
+type ReferenceKey struct {
+	ResourceType     string
+	ResourceID       string
+	ResourceRelation string
+	SubjectType      string
+	SubjectID        string
+	SubjectRelation  string
+}
+
+// KeyFromRelationship returns the ReferenceKey for the relationship's reference, ignoring
+// its caveat, expiration and integrity.
+func KeyFromRelationship(rel Relationship) ReferenceKey {
+	return KeyFromReference(rel.RelationshipReference)
+}
+
+// KeyFromReference returns the ReferenceKey for the given relationship reference.
+func KeyFromReference(ref RelationshipReference) ReferenceKey {
+	return ReferenceKey{
+		ResourceType:     ref.Resource.ObjectType,
+		ResourceID:       ref.Resource.ObjectID,
+		ResourceRelation: ref.Resource.Relation,
+		SubjectType:      ref.Subject.ObjectType,
+		SubjectID:        ref.Subject.ObjectID,
+		SubjectRelation:  ref.Subject.Relation,
+	}
+}
+
+// Reference converts the key back into the RelationshipReference it was built from.
+func (rk ReferenceKey) Reference() RelationshipReference {
+	return RelationshipReference{
+		Resource: ObjectAndRelation{
+			ObjectType: rk.ResourceType,
+			ObjectID:   rk.ResourceID,
+			Relation:   rk.ResourceRelation,
+		},
+		Subject: ObjectAndRelation{
+			ObjectType: rk.SubjectType,
+			ObjectID:   rk.SubjectID,
+			Relation:   rk.SubjectRelation,
+		},
+	}
+}
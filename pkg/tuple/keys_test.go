@@ -0,0 +1,106 @@
+package tuple
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyFromRelationship(t *testing.T) {
+	tcs := []struct {
+		name        string
+		relString   string
+		expectedKey ReferenceKey
+	}{
+		{
+			name:      "subject without relation",
+			relString: "document:firstdoc#viewer@user:tom",
+			expectedKey: ReferenceKey{
+				ResourceType:     "document",
+				ResourceID:       "firstdoc",
+				ResourceRelation: "viewer",
+				SubjectType:      "user",
+				SubjectID:        "tom",
+				SubjectRelation:  Ellipsis,
+			},
+		},
+		{
+			name:      "subject with relation",
+			relString: "document:firstdoc#viewer@group:admins#members",
+			expectedKey: ReferenceKey{
+				ResourceType:     "document",
+				ResourceID:       "firstdoc",
+				ResourceRelation: "viewer",
+				SubjectType:      "group",
+				SubjectID:        "admins",
+				SubjectRelation:  "members",
+			},
+		},
+		{
+			name:      "caveat and expiration are ignored",
+			relString: "document:firstdoc#viewer@user:tom[somecaveat][expiration:2322-01-02T03:04:05Z]",
+			expectedKey: ReferenceKey{
+				ResourceType:     "document",
+				ResourceID:       "firstdoc",
+				ResourceRelation: "viewer",
+				SubjectType:      "user",
+				SubjectID:        "tom",
+				SubjectRelation:  Ellipsis,
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			rel := MustParse(tc.relString)
+			key := KeyFromRelationship(rel)
+			require.Equal(t, tc.expectedKey, key)
+			require.Equal(t, key, KeyFromReference(rel.RelationshipReference))
+			require.Equal(t, rel.RelationshipReference, key.Reference())
+		})
+	}
+}
+
+func TestReferenceKeyAsMapKey(t *testing.T) {
+	seen := map[ReferenceKey]int{}
+	seen[KeyFromRelationship(MustParse("document:firstdoc#viewer@user:tom"))] = 1
+	seen[KeyFromRelationship(MustParse("document:firstdoc#viewer@user:tom[somecaveat]"))] = 2
+	seen[KeyFromRelationship(MustParse("document:firstdoc#viewer@group:admins#members"))] = 3
+	seen[KeyFromRelationship(MustParse("document:firstdoc#viewer@group:admins"))] = 4
+
+	// Separately parsed relationships with the same reference collide, including those
+	// differing only in caveat or expiration; differing subject relations do not.
+	require.Len(t, seen, 3)
+	require.Equal(t, 2, seen[KeyFromRelationship(MustParse("document:firstdoc#viewer@user:tom"))])
+	require.Equal(t, 3, seen[KeyFromRelationship(MustParse("document:firstdoc#viewer@group:admins#members"))])
+	require.Equal(t, 4, seen[KeyFromRelationship(MustParse("document:firstdoc#viewer@group:admins"))])
+}
+
+func BenchmarkReferenceKeyVersusStringKeys(b *testing.B) {
+	rels := make([]Relationship, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		rels = append(rels, MustParse("document:doc-"+strconv.Itoa(i)+"#viewer@user:user-"+strconv.Itoa(i%128)))
+	}
+
+	b.Run("string keys", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			seen := make(map[string]struct{}, len(rels))
+			for _, rel := range rels {
+				seen[StringWithoutCaveatOrExpiration(rel)] = struct{}{}
+			}
+		}
+	})
+
+	b.Run("reference keys", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			seen := make(map[ReferenceKey]struct{}, len(rels))
+			for _, rel := range rels {
+				seen[KeyFromRelationship(rel)] = struct{}{}
+			}
+		}
+	})
+}
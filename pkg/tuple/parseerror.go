@@ -0,0 +1,237 @@
+package tuple
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseSegment identifies the portion of a relationship string that failed to parse.
+type ParseSegment string
+
+const (
+	// ParseSegmentResource is the `resourcetype:resourceid` portion of a relationship string.
+	ParseSegmentResource ParseSegment = "resource"
+
+	// ParseSegmentRelation is the `#relation` portion of a relationship string.
+	ParseSegmentRelation ParseSegment = "relation"
+
+	// ParseSegmentSubject is the `@subjecttype:subjectid` portion of a relationship string.
+	ParseSegmentSubject ParseSegment = "subject"
+
+	// ParseSegmentCaveat is the `[caveatname:{...}]` portion of a relationship string.
+	ParseSegmentCaveat ParseSegment = "caveat"
+
+	// ParseSegmentExpiration is the `[expiration:...]` portion of a relationship string.
+	ParseSegmentExpiration ParseSegment = "expiration"
+)
+
+// Expectation messages for the various segments, shared between diagnosis and validation.
+const (
+	expectedObjectType   = "object type must be alphanumeric lowercase, between 2 and 64 characters, with an optional `/`-delimited prefix"
+	expectedObjectID     = "object id may only contain alphanumeric characters and `/_|-=+`, and must be between 1 and 1024 characters"
+	expectedSubjectID    = "subject id may only contain alphanumeric characters and `/_|-=+`, between 1 and 1024 characters, or be `*` for public"
+	expectedRelationName = "relation must be alphanumeric lowercase and between 3 and 64 characters"
+	expectedCaveatName   = "caveat name must be alphanumeric lowercase, between 2 and 64 characters, with an optional `/`-delimited prefix"
+	expectedTimestamp    = "expected an RFC 3339 formatted timestamp"
+)
+
+// ParseError is the typed error returned by Parse, carrying the offending segment, the byte
+// offset at which that segment begins, and a short description of what was expected.
+type ParseError struct {
+	error
+
+	// Segment is the portion of the relationship string that failed to parse.
+	Segment ParseSegment
+
+	// ByteOffset is the byte offset within the input at which the offending segment begins.
+	ByteOffset int
+
+	// Expectation is a short description of what the parser expected at the offset.
+	Expectation string
+}
+
+// Unwrap returns the underlying cause of the parse error, if any.
+func (err ParseError) Unwrap() error {
+	return errors.Unwrap(err.error)
+}
+
+func newParseError(segment ParseSegment, byteOffset int, expectation string) ParseError {
+	return ParseError{
+		error:       fmt.Errorf("invalid relationship string: malformed %s at offset %d: %s", segment, byteOffset, expectation),
+		Segment:     segment,
+		ByteOffset:  byteOffset,
+		Expectation: expectation,
+	}
+}
+
+func newParseErrorWithCause(segment ParseSegment, byteOffset int, expectation string, cause error) ParseError {
+	return ParseError{
+		error:       fmt.Errorf("invalid relationship string: malformed %s at offset %d: %s: %w", segment, byteOffset, expectation, cause),
+		Segment:     segment,
+		ByteOffset:  byteOffset,
+		Expectation: expectation,
+	}
+}
+
+// diagnoseParseError determines which segment of an unparseable relationship string is
+// malformed, by walking its expected structure left to right. It is only invoked once the
+// parser regex has failed to match, so perfomance is not a concern.
+func diagnoseParseError(input string) ParseError {
+	atIndex := strings.Index(input, "@")
+	if atIndex < 0 {
+		return newParseError(ParseSegmentSubject, len(input), "expected `@` followed by a subject reference")
+	}
+
+	resourcePart := input[:atIndex]
+	hashIndex := strings.Index(resourcePart, "#")
+	if hashIndex < 0 {
+		return newParseError(ParseSegmentRelation, len(resourcePart), "expected `#` followed by a relation name")
+	}
+
+	if err, ok := diagnoseObjectReference(resourcePart[:hashIndex], 0, false); !ok {
+		return err
+	}
+
+	relationOffset := hashIndex + 1
+	if !relationRegex.MatchString(resourcePart[relationOffset:]) {
+		return newParseError(ParseSegmentRelation, relationOffset, expectedRelationName)
+	}
+
+	subjectOffset := atIndex + 1
+	remaining := input[subjectOffset:]
+	subjectPart := remaining
+	if bracketIndex := strings.Index(remaining, "["); bracketIndex >= 0 {
+		subjectPart = remaining[:bracketIndex]
+	}
+
+	subjectRelation := ""
+	subjectRelationOffset := -1
+	if subjectHashIndex := strings.Index(subjectPart, "#"); subjectHashIndex >= 0 {
+		subjectRelation = subjectPart[subjectHashIndex+1:]
+		subjectRelationOffset = subjectOffset + subjectHashIndex + 1
+		subjectPart = subjectPart[:subjectHashIndex]
+	}
+
+	if err, ok := diagnoseObjectReference(subjectPart, subjectOffset, true); !ok {
+		return err
+	}
+
+	if subjectRelationOffset >= 0 && subjectRelation != Ellipsis && !relationRegex.MatchString(subjectRelation) {
+		return newParseError(ParseSegmentSubject, subjectRelationOffset, expectedRelationName)
+	}
+
+	return diagnoseAnnotations(input)
+}
+
+// diagnoseObjectReference checks a `type:id` portion of a relationship string, reporting
+// the malformed piece if any. The boolean return is false if an error was found.
+func diagnoseObjectReference(typeAndID string, baseOffset int, isSubject bool) (ParseError, bool) {
+	segment := ParseSegmentResource
+	if isSubject {
+		segment = ParseSegmentSubject
+	}
+
+	colonIndex := strings.Index(typeAndID, ":")
+	if colonIndex < 0 {
+		return newParseError(segment, baseOffset+len(typeAndID), "expected `:` followed by an object id"), false
+	}
+
+	if !objectTypeRegex.MatchString(typeAndID[:colonIndex]) {
+		return newParseError(segment, baseOffset, expectedObjectType), false
+	}
+
+	objectID := typeAndID[colonIndex+1:]
+	if isSubject {
+		if !anchoredSubjectIDRegex.MatchString(objectID) || len(objectID) > 1024 {
+			return newParseError(segment, baseOffset+colonIndex+1, expectedSubjectID), false
+		}
+	} else if err := ValidateResourceID(objectID); err != nil {
+		return newParseError(segment, baseOffset+colonIndex+1, expectedObjectID), false
+	}
+
+	return ParseError{}, true
+}
+
+// diagnoseAnnotations checks the bracketed caveat and expiration blocks trailing the
+// subject of a relationship string.
+func diagnoseAnnotations(input string) ParseError {
+	offset := strings.Index(input, "[")
+	if offset < 0 {
+		// The structure appears well formed; fall back to a generic error over the whole string.
+		return genericParseError()
+	}
+
+	expirationSeen := false
+	caveatSeen := false
+	for offset < len(input) {
+		if input[offset] != '[' {
+			return newParseError(ParseSegmentSubject, offset, "unexpected trailing characters after the subject")
+		}
+
+		remaining := input[offset:]
+		if timestampStr, ok := strings.CutPrefix(remaining, "[expiration:"); ok {
+			if expirationSeen {
+				return newParseError(ParseSegmentExpiration, offset, "expiration may only be specified once")
+			}
+			expirationSeen = true
+
+			closeIndex := strings.Index(timestampStr, "]")
+			if closeIndex < 0 {
+				return newParseError(ParseSegmentExpiration, offset, "expected `]` closing the expiration block")
+			}
+
+			if _, err := time.Parse(expirationFormat, timestampStr[:closeIndex]); err != nil {
+				return newParseError(ParseSegmentExpiration, offset+len("[expiration:"), expectedTimestamp)
+			}
+
+			offset += len("[expiration:") + closeIndex + 1
+			continue
+		}
+
+		// A caveat block: `[caveatname]` or `[caveatname:{...}]`.
+		if caveatSeen {
+			return newParseError(ParseSegmentCaveat, offset, "caveat may only be specified once")
+		}
+		caveatSeen = true
+
+		blockEnd := strings.Index(remaining, "]")
+		contextStart := strings.Index(remaining, ":{")
+		if contextStart >= 0 && (blockEnd < 0 || contextStart < blockEnd) {
+			caveatName := remaining[1:contextStart]
+			if !caveatNameRegex.MatchString(caveatName) {
+				return newParseError(ParseSegmentCaveat, offset+1, expectedCaveatName)
+			}
+
+			contextEnd := strings.LastIndex(remaining, "}]")
+			if contextEnd < 0 {
+				return newParseError(ParseSegmentCaveat, offset+contextStart+1, "expected `}]` closing the caveat context")
+			}
+
+			if !json.Valid([]byte(remaining[contextStart+1 : contextEnd+1])) {
+				return newParseError(ParseSegmentCaveat, offset+contextStart+1, "caveat context must be a valid JSON object")
+			}
+
+			offset += contextEnd + 2
+			continue
+		}
+
+		if blockEnd < 0 {
+			return newParseError(ParseSegmentCaveat, offset, "expected `]` closing the caveat block")
+		}
+
+		if !caveatNameRegex.MatchString(remaining[1:blockEnd]) {
+			return newParseError(ParseSegmentCaveat, offset+1, expectedCaveatName)
+		}
+
+		offset += blockEnd + 1
+	}
+
+	return genericParseError()
+}
+
+func genericParseError() ParseError {
+	return newParseError(ParseSegmentResource, 0, "relationship must be of the form `resourcetype:resourceid#relation@subjecttype:subjectid`")
+}
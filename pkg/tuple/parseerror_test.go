@@ -0,0 +1,160 @@
+package tuple
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseErrorSegments(t *testing.T) {
+	tcs := []struct {
+		name            string
+		input           string
+		expectedSegment ParseSegment
+		expectedOffset  int
+	}{
+		{
+			name:            "missing subject",
+			input:           "document:foo#viewer",
+			expectedSegment: ParseSegmentSubject,
+			expectedOffset:  19,
+		},
+		{
+			name:            "missing relation",
+			input:           "document:foo@user:tom",
+			expectedSegment: ParseSegmentRelation,
+			expectedOffset:  12,
+		},
+		{
+			name:            "invalid resource type",
+			input:           "DOCUMENT:foo#viewer@user:tom",
+			expectedSegment: ParseSegmentResource,
+			expectedOffset:  0,
+		},
+		{
+			name:            "missing resource id",
+			input:           "document#viewer@user:tom",
+			expectedSegment: ParseSegmentResource,
+			expectedOffset:  8,
+		},
+		{
+			name:            "invalid resource id",
+			input:           "document:f☃o#viewer@user:tom",
+			expectedSegment: ParseSegmentResource,
+			expectedOffset:  9,
+		},
+		{
+			name:            "invalid relation",
+			input:           "document:foo#VIEWER@user:tom",
+			expectedSegment: ParseSegmentRelation,
+			expectedOffset:  13,
+		},
+		{
+			name:            "invalid subject type",
+			input:           "document:foo#viewer@USER:tom",
+			expectedSegment: ParseSegmentSubject,
+			expectedOffset:  20,
+		},
+		{
+			name:            "invalid subject id",
+			input:           "document:foo#viewer@user:t☃m",
+			expectedSegment: ParseSegmentSubject,
+			expectedOffset:  25,
+		},
+		{
+			name:            "invalid subject relation",
+			input:           "document:foo#viewer@group:eng#MEMBER",
+			expectedSegment: ParseSegmentSubject,
+			expectedOffset:  30,
+		},
+		{
+			name:            "invalid caveat name",
+			input:           "document:foo#viewer@user:tom[SOMECAVEAT]",
+			expectedSegment: ParseSegmentCaveat,
+			expectedOffset:  29,
+		},
+		{
+			name:            "invalid caveat context JSON",
+			input:           `document:foo#viewer@user:tom[somecaveat:{"hi": not json}]`,
+			expectedSegment: ParseSegmentCaveat,
+			expectedOffset:  40,
+		},
+		{
+			name:            "unterminated caveat context",
+			input:           `document:foo#viewer@user:tom[somecaveat:{"hi":`,
+			expectedSegment: ParseSegmentCaveat,
+			expectedOffset:  40,
+		},
+		{
+			name:            "duplicate caveat",
+			input:           "document:foo#viewer@user:tom[somecaveat][anothercaveat]",
+			expectedSegment: ParseSegmentCaveat,
+			expectedOffset:  40,
+		},
+		{
+			name:            "invalid expiration timestamp",
+			input:           "document:foo#viewer@user:tom[expiration:not-a-timestamp]",
+			expectedSegment: ParseSegmentExpiration,
+			expectedOffset:  40,
+		},
+		{
+			name:            "out of range expiration timestamp",
+			input:           "document:foo#viewer@user:tom[expiration:2020-13-01T00:00:00Z]",
+			expectedSegment: ParseSegmentExpiration,
+			expectedOffset:  40,
+		},
+		{
+			name:            "duplicate expiration",
+			input:           "document:foo#viewer@user:tom[expiration:2020-01-01T00:00:00Z][somecaveat][expiration:2021-01-01T00:00:00Z]",
+			expectedSegment: ParseSegmentExpiration,
+			expectedOffset:  73,
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.input)
+			require.Error(t, err)
+
+			var parseErr ParseError
+			require.ErrorAs(t, err, &parseErr)
+			require.Equal(t, tc.expectedSegment, parseErr.Segment)
+			require.Equal(t, tc.expectedOffset, parseErr.ByteOffset)
+			require.NotEmpty(t, parseErr.Expectation)
+			require.Contains(t, parseErr.Error(), string(tc.expectedSegment))
+		})
+	}
+}
+
+func TestParseErrorForOverLongObjectIDs(t *testing.T) {
+	longID := strings.Repeat("a", 1025)
+
+	_, err := Parse("document:" + longID + "#viewer@user:tom")
+	var parseErr ParseError
+	require.ErrorAs(t, err, &parseErr)
+	require.Equal(t, ParseSegmentResource, parseErr.Segment)
+	require.Equal(t, 9, parseErr.ByteOffset)
+
+	_, err = Parse("document:foo#viewer@user:" + longID)
+	require.ErrorAs(t, err, &parseErr)
+	require.Equal(t, ParseSegmentSubject, parseErr.Segment)
+	require.Equal(t, 25, parseErr.ByteOffset)
+}
+
+func TestMustParsePanicsWithParseErrorDetail(t *testing.T) {
+	defer func() {
+		recovered := recover()
+		require.NotNil(t, recovered)
+
+		err, ok := recovered.(error)
+		require.True(t, ok)
+
+		var parseErr ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, ParseSegmentRelation, parseErr.Segment)
+	}()
+
+	MustParse("document:foo@user:tom")
+}
@@ -8,6 +8,7 @@ import (
 	"maps"
 	"regexp"
 	"slices"
+	"strings"
 	"time"
 
 	"google.golang.org/protobuf/types/known/structpb"
@@ -40,18 +41,31 @@ var subjectExpr = fmt.Sprintf(
 var (
 	caveatExpr     = fmt.Sprintf(`\[(?P<caveatName>(%s))(:(?P<caveatContext>(\{(.+)\})))?\]`, caveatNameExpr)
 	expirationExpr = `\[expiration:(?P<expirationDateTime>([\d\-\.:TZ]+))\]`
+
+	// leadingExpirationExpr allows the expiration annotation to be written before the caveat
+	// block; Go regular expressions disallow reusing a group name, so it captures under a
+	// distinct name.
+	leadingExpirationExpr = `\[expiration:(?P<leadingExpirationDateTime>([\d\-\.:TZ]+))\]`
 )
 
 var (
 	resourceIDRegex = regexp.MustCompile(fmt.Sprintf("^%s$", resourceIDExpr))
 	subjectIDRegex  = regexp.MustCompile(fmt.Sprintf("^%s$", subjectIDExpr))
+	objectTypeRegex = regexp.MustCompile(fmt.Sprintf("^%s$", namespaceNameExpr))
+	relationRegex   = regexp.MustCompile(fmt.Sprintf("^%s$", relationExpr))
+	caveatNameRegex = regexp.MustCompile(fmt.Sprintf("^%s$", caveatNameExpr))
+
+	// anchoredSubjectIDRegex wraps the subject ID expression in a group before anchoring it,
+	// as the expression contains a top-level alternation.
+	anchoredSubjectIDRegex = regexp.MustCompile(fmt.Sprintf("^(%s)$", subjectIDExpr))
 )
 
 var parserRegex = regexp.MustCompile(
 	fmt.Sprintf(
-		`^%s@%s(%s)?(%s)?$`,
+		`^%s@%s(%s)?(%s)?(%s)?$`,
 		onrExpr,
 		subjectExpr,
+		leadingExpirationExpr,
 		caveatExpr,
 		expirationExpr,
 	),
@@ -100,6 +114,8 @@ var (
 	resourceRelIndex        = slices.Index(parserRegex.SubexpNames(), "resourceRel")
 	subjectTypeIndex        = slices.Index(parserRegex.SubexpNames(), "subjectType")
 	expirationDateTimeIndex = slices.Index(parserRegex.SubexpNames(), "expirationDateTime")
+
+	leadingExpirationDateTimeIndex = slices.Index(parserRegex.SubexpNames(), "leadingExpirationDateTime")
 )
 
 // Parse unmarshals the string form of a Tuple and returns an error on failure,
@@ -108,7 +124,7 @@ var (
 func Parse(relString string) (Relationship, error) {
 	groups := parserRegex.FindStringSubmatch(relString)
 	if len(groups) == 0 {
-		return Relationship{}, errors.New("invalid relationship string")
+		return Relationship{}, diagnoseParseError(relString)
 	}
 
 	subjectRelation := Ellipsis
@@ -128,24 +144,33 @@ func Parse(relString string) (Relationship, error) {
 			contextMap := make(map[string]any, 1)
 			err := json.Unmarshal([]byte(caveatContextString), &contextMap)
 			if err != nil {
-				return Relationship{}, fmt.Errorf("invalid caveat context JSON: %w", err)
+				return Relationship{}, newParseErrorWithCause(ParseSegmentCaveat, strings.Index(relString, caveatContextString), "caveat context must be a valid JSON object", err)
 			}
 
 			caveatContext, err := structpb.NewStruct(contextMap)
 			if err != nil {
-				return Relationship{}, fmt.Errorf("invalid caveat context: %w", err)
+				return Relationship{}, newParseErrorWithCause(ParseSegmentCaveat, strings.Index(relString, caveatContextString), "caveat context holds an unsupported value", err)
 			}
 
 			optionalCaveat.Context = caveatContext
 		}
 	}
 
+	// The expiration annotation may appear on either side of the caveat block, but only once.
 	expirationTimeStr := groups[expirationDateTimeIndex]
+	if leadingExpirationTimeStr := groups[leadingExpirationDateTimeIndex]; len(leadingExpirationTimeStr) > 0 {
+		if len(expirationTimeStr) > 0 {
+			return Relationship{}, newParseError(ParseSegmentExpiration, strings.LastIndex(relString, "[expiration:"), "expiration may only be specified once")
+		}
+
+		expirationTimeStr = leadingExpirationTimeStr
+	}
+
 	var optionalExpiration *time.Time
 	if len(expirationTimeStr) > 0 {
 		expirationTime, err := time.Parse(expirationFormat, expirationTimeStr)
 		if err != nil {
-			return Relationship{}, fmt.Errorf("invalid expiration time: %w", err)
+			return Relationship{}, newParseErrorWithCause(ParseSegmentExpiration, strings.Index(relString, "[expiration:")+len("[expiration:"), expectedTimestamp, err)
 		}
 
 		optionalExpiration = &expirationTime
@@ -153,12 +178,12 @@ func Parse(relString string) (Relationship, error) {
 
 	resourceID := groups[resourceIDIndex]
 	if err := ValidateResourceID(resourceID); err != nil {
-		return Relationship{}, fmt.Errorf("invalid resource id: %w", err)
+		return Relationship{}, newParseErrorWithCause(ParseSegmentResource, len(groups[resourceTypeIndex])+1, expectedObjectID, err)
 	}
 
 	subjectID := groups[subjectIDIndex]
 	if err := ValidateSubjectID(subjectID); err != nil {
-		return Relationship{}, fmt.Errorf("invalid subject id: %w", err)
+		return Relationship{}, newParseErrorWithCause(ParseSegmentSubject, strings.Index(relString, "@")+len(groups[subjectTypeIndex])+2, expectedSubjectID, err)
 	}
 
 	return Relationship{
@@ -531,6 +531,78 @@ var testCases = []struct {
 		}),
 		stableCanonicalization: "ZG9jdW1lbnQ6Zm9vI3ZpZXdlckB1c2VyOnRvbSMuLi4gd2l0aCBzb21lY2F2ZWF0Ontmb286NDIuMDAwMDAwfSB3aXRoICRleHBpcmF0aW9uOjIwMjAtMDEtMDFUMDA6MDA6MDBa",
 	},
+	{
+		input:          `document:foo#viewer@user:tom[expiration:2020-01-01T00:00:00Z][somecaveat]`,
+		expectedOutput: `document:foo#viewer@user:tom[somecaveat][expiration:2020-01-01T00:00:00Z]`,
+		relFormat: MustWithExpiration(
+			MustWithCaveat(
+				makeRel(
+					StringToONR("document", "foo", "viewer"),
+					StringToONR("user", "tom", "..."),
+				),
+				"somecaveat",
+			),
+			time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		),
+		v1Format:               ecv1rel("document", "foo", "viewer", "user", "tom", "", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), "somecaveat", map[string]any{}),
+		stableCanonicalization: "ZG9jdW1lbnQ6Zm9vI3ZpZXdlckB1c2VyOnRvbSMuLi4gd2l0aCBzb21lY2F2ZWF0IHdpdGggJGV4cGlyYXRpb246MjAyMC0wMS0wMVQwMDowMDowMFo=",
+	},
+	{
+		input:          `document:foo#viewer@user:tom[expiration:2020-01-01T00:00:00Z][somecaveat:{"foo":42}]`,
+		expectedOutput: `document:foo#viewer@user:tom[somecaveat:{"foo":42}][expiration:2020-01-01T00:00:00Z]`,
+		relFormat: MustWithExpiration(
+			MustWithCaveat(
+				makeRel(
+					StringToONR("document", "foo", "viewer"),
+					StringToONR("user", "tom", "..."),
+				),
+				"somecaveat",
+				map[string]any{
+					"foo": 42,
+				},
+			),
+			time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		),
+		v1Format: ecv1rel("document", "foo", "viewer", "user", "tom", "", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), "somecaveat", map[string]any{
+			"foo": 42,
+		}),
+		stableCanonicalization: "ZG9jdW1lbnQ6Zm9vI3ZpZXdlckB1c2VyOnRvbSMuLi4gd2l0aCBzb21lY2F2ZWF0Ontmb286NDIuMDAwMDAwfSB3aXRoICRleHBpcmF0aW9uOjIwMjAtMDEtMDFUMDA6MDA6MDBa",
+	},
+	{
+		input:          `document:foo#viewer@user:tom[expiration:2020-01-01T00:00:00Z][expiration:2020-01-01T00:00:00Z]`,
+		expectedOutput: "",
+	},
+	{
+		input:          `document:foo#viewer@user:tom[expiration:2020-01-01T00:00:00Z][somecaveat][expiration:2020-01-01T00:00:00Z]`,
+		expectedOutput: "",
+	},
+	{
+		input:          `document:foo#viewer@user:tom[expiration:not-a-timestamp]`,
+		expectedOutput: "",
+	},
+	{
+		input:          `document:foo#viewer@user:tom[expiration:2020-13-01T00:00:00Z]`,
+		expectedOutput: "",
+	},
+	{
+		input:          `document:foo#viewer@user:tom[expiration:2020-01-01T99:00:00Z][somecaveat]`,
+		expectedOutput: "",
+	},
+	{
+		// Sub-second expirations are truncated to microseconds by the datastores, so
+		// microsecond precision must round-trip.
+		input:          `document:foo#viewer@user:tom[expiration:2020-01-01T00:00:03.123456Z]`,
+		expectedOutput: `document:foo#viewer@user:tom[expiration:2020-01-01T00:00:03.123456Z]`,
+		relFormat: MustWithExpiration(
+			makeRel(
+				StringToONR("document", "foo", "viewer"),
+				StringToONR("user", "tom", "..."),
+			),
+			time.Date(2020, 1, 1, 0, 0, 3, 123456000, time.UTC),
+		),
+		v1Format:               ev1rel("document", "foo", "viewer", "user", "tom", "", time.Date(2020, 1, 1, 0, 0, 3, 123456000, time.UTC)),
+		stableCanonicalization: "ZG9jdW1lbnQ6Zm9vI3ZpZXdlckB1c2VyOnRvbSMuLi4gd2l0aCAkZXhwaXJhdGlvbjoyMDIwLTAxLTAxVDAwOjAwOjAzWg==",
+	},
 	{
 		input:          `document:foo#viewer@user:tom[expiration:2020-01-01T00:00:02.542Z]`,
 		expectedOutput: `document:foo#viewer@user:tom[expiration:2020-01-01T00:00:02.542Z]`,
@@ -672,6 +744,54 @@ func TestConvert(t *testing.T) {
 	}
 }
 
+func TestCanonicalString(t *testing.T) {
+	// The canonical form must round-trip through the parser back to the same relationship.
+	for _, tc := range testCases {
+		tc := tc
+		t.Run("roundtrip/"+tc.input, func(t *testing.T) {
+			if tc.relFormat.Resource.ObjectType == "" {
+				return
+			}
+
+			canonical := CanonicalString(tc.relFormat)
+			parsed, err := Parse(canonical)
+			require.NoError(t, err)
+			require.True(t, Equal(tc.relFormat, parsed), "found difference in reparsed relationship: %v vs %v", tc.relFormat, parsed)
+		})
+	}
+
+	t.Run("key order independence", func(t *testing.T) {
+		base := makeRel(
+			StringToONR("document", "foo", "viewer"),
+			StringToONR("user", "tom", "..."),
+		)
+
+		first := MustWithCaveat(base, "somecaveat", map[string]any{
+			"a": 1,
+			"b": map[string]any{
+				"y": []any{1, 2, 3},
+				"x": "hi",
+			},
+			"c": true,
+		})
+		second := MustWithCaveat(base, "somecaveat", map[string]any{
+			"c": true,
+			"b": map[string]any{
+				"x": "hi",
+				"y": []any{1, 2, 3},
+			},
+			"a": 1,
+		})
+
+		require.Equal(t, CanonicalString(first), CanonicalString(second))
+		require.Equal(
+			t,
+			`document:foo#viewer@user:tom[somecaveat:{"a":1,"b":{"x":"hi","y":[1,2,3]},"c":true}]`,
+			CanonicalString(first),
+		)
+	})
+}
+
 func TestValidate(t *testing.T) {
 	for _, tc := range testCases {
 		tc := tc
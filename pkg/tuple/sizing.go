@@ -0,0 +1,72 @@
+package tuple
+
+import (
+	"google.golang.org/protobuf/proto"
+)
+
+// estimatedOverheadBytes is the fixed per-relationship overhead assumed by EstimateSize,
+// covering separators, field tags and per-row storage overhead in the datastores.
+const estimatedOverheadBytes = 32
+
+// estimatedExpirationBytes is the assumed size of a serialized expiration timestamp.
+const estimatedExpirationBytes = 32
+
+// EstimateSize returns an approximation of the serialized size of the relationship, in
+// bytes, for use when batching writes against per-transaction byte limits.
+//
+// The estimate is conservative: it is always at least the length of the relationship's
+// canonical byte serialization, and overcounts by no more than a factor of three plus the
+// fixed per-relationship overhead.
+func EstimateSize(rel Relationship) int {
+	size := estimatedOverheadBytes +
+		len(rel.Resource.ObjectType) + len(rel.Resource.ObjectID) + len(rel.Resource.Relation) +
+		len(rel.Subject.ObjectType) + len(rel.Subject.ObjectID) + len(rel.Subject.Relation)
+
+	if rel.OptionalCaveat != nil && rel.OptionalCaveat.CaveatName != "" {
+		size += len(rel.OptionalCaveat.CaveatName)
+		if rel.OptionalCaveat.Context != nil {
+			// The canonical context serialization is bounded by twice the proto-encoded size,
+			// as the proto encoding carries a tag and length for every key and value.
+			size += 2 * proto.Size(rel.OptionalCaveat.Context)
+		}
+	}
+
+	if rel.OptionalExpiration != nil {
+		size += estimatedExpirationBytes
+	}
+
+	return size
+}
+
+// BatchBySize splits the given relationships into batches, each of which holds at most
+// maxCount relationships whose estimated sizes sum to at most maxBytes. A non-positive
+// maxCount or maxBytes disables that limit. A single relationship whose estimated size
+// exceeds maxBytes is placed into a batch of its own.
+//
+// The relative order of the relationships is preserved across the batches.
+func BatchBySize(rels []Relationship, maxBytes int, maxCount int) [][]Relationship {
+	if len(rels) == 0 {
+		return nil
+	}
+
+	batches := make([][]Relationship, 0, 1)
+	batchStart := 0
+	batchBytes := 0
+
+	for index, rel := range rels {
+		estimated := EstimateSize(rel)
+		currentCount := index - batchStart
+
+		overCount := maxCount > 0 && currentCount+1 > maxCount
+		overBytes := maxBytes > 0 && currentCount > 0 && batchBytes+estimated > maxBytes
+		if overCount || overBytes {
+			batches = append(batches, rels[batchStart:index])
+			batchStart = index
+			batchBytes = 0
+		}
+
+		batchBytes += estimated
+	}
+
+	return append(batches, rels[batchStart:])
+}
@@ -0,0 +1,92 @@
+package tuple
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateSizeNeverUndercountsCanonicalBytes(t *testing.T) {
+	for _, tc := range testCases {
+		tc := tc
+		if tc.relFormat.Resource.ObjectType == "" {
+			continue
+		}
+
+		t.Run(tc.input, func(t *testing.T) {
+			canonical, err := CanonicalBytes(tc.relFormat)
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, EstimateSize(tc.relFormat), len(canonical))
+		})
+	}
+}
+
+func TestEstimateSizeWithLargeNestedCaveatContext(t *testing.T) {
+	rel := MustWithCaveat(
+		MustParse("document:foo#viewer@user:tom"),
+		"somecaveat",
+		map[string]any{
+			"nested": map[string]any{
+				"payload": strings.Repeat("a", 10_000),
+				"items":   []any{"first", "second", map[string]any{"deep": strings.Repeat("b", 5_000)}},
+			},
+		},
+	)
+
+	canonical, err := CanonicalBytes(rel)
+	require.NoError(t, err)
+
+	estimated := EstimateSize(rel)
+	require.GreaterOrEqual(t, estimated, len(canonical))
+
+	// The estimate is conservative, but within the documented factor of three (plus overhead).
+	require.LessOrEqual(t, estimated, 3*len(canonical)+estimatedOverheadBytes)
+}
+
+func TestBatchBySize(t *testing.T) {
+	rels := make([]Relationship, 0, 10)
+	for i := 0; i < 10; i++ {
+		rels = append(rels, MustParse("document:doc"+strconv.Itoa(i)+"#viewer@user:tom"))
+	}
+	perRelSize := EstimateSize(rels[0])
+
+	t.Run("splits by count", func(t *testing.T) {
+		batches := BatchBySize(rels, 0, 3)
+		require.Len(t, batches, 4)
+		require.Len(t, batches[0], 3)
+		require.Len(t, batches[3], 1)
+	})
+
+	t.Run("splits by bytes", func(t *testing.T) {
+		batches := BatchBySize(rels, perRelSize*2, 0)
+		require.Len(t, batches, 5)
+		for _, batch := range batches {
+			require.Len(t, batch, 2)
+		}
+	})
+
+	t.Run("oversized relationship gets its own batch", func(t *testing.T) {
+		batches := BatchBySize(rels, perRelSize-1, 0)
+		require.Len(t, batches, 10)
+	})
+
+	t.Run("no limits yields a single batch", func(t *testing.T) {
+		batches := BatchBySize(rels, 0, 0)
+		require.Len(t, batches, 1)
+		require.Len(t, batches[0], 10)
+	})
+
+	t.Run("preserves order", func(t *testing.T) {
+		flattened := make([]Relationship, 0, len(rels))
+		for _, batch := range BatchBySize(rels, perRelSize*3, 2) {
+			flattened = append(flattened, batch...)
+		}
+		require.Equal(t, rels, flattened)
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		require.Nil(t, BatchBySize(nil, 100, 100))
+	})
+}
@@ -0,0 +1,214 @@
+package tuple
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// defaultMaxLineLength is the default maximum length, in bytes, permitted for a single line
+// read by a StreamParser.
+const defaultMaxLineLength = 1 << 20 // 1 MiB
+
+// StreamParserOption customizes the behavior of a StreamParser.
+type StreamParserOption func(*StreamParser)
+
+// WithMaxLineLength sets the maximum length, in bytes, permitted for a single line read by
+// the parser. Lines beyond the cap are reported as parse errors without being buffered in
+// full, to guard against malformed data.
+func WithMaxLineLength(maxLineLength int) StreamParserOption {
+	return func(sp *StreamParser) {
+		sp.maxLineLength = maxLineLength
+	}
+}
+
+// WithCollectedErrors switches the parser into collect mode: parse errors are accumulated,
+// up to the given cap, and parsing continues with the next line rather than aborting. Once
+// the cap is reached, the next parse error aborts the stream. The collected errors are
+// available via Errors.
+func WithCollectedErrors(errorCap int) StreamParserOption {
+	return func(sp *StreamParser) {
+		sp.collectErrors = true
+		sp.errorCap = errorCap
+	}
+}
+
+// StreamParseError is the error reported by a StreamParser for a line that could not be
+// parsed.
+type StreamParseError struct {
+	error
+
+	// SourceCode is the trimmed line that failed to parse. Empty for over-long lines, whose
+	// contents are not retained.
+	SourceCode string
+
+	// LineNumber is the one-indexed line number at which the error occurred.
+	LineNumber int
+
+	// ColumnPosition is the one-indexed column position at which the error occurred.
+	ColumnPosition int
+}
+
+// Unwrap returns the underlying cause of the parse error, if any. For unparseable
+// relationships, this is the error returned by Parse.
+func (err StreamParseError) Unwrap() error {
+	return errors.Unwrap(err.error)
+}
+
+// StreamParser reads newline-separated relationships from a reader, yielding them one at a
+// time without buffering the full input. Blank lines and lines beginning with `//` are
+// skipped.
+type StreamParser struct {
+	reader        *bufio.Reader
+	maxLineLength int
+	collectErrors bool
+	errorCap      int
+
+	lineNumber  int
+	currentLine string
+	count       int
+	collected   []StreamParseError
+	done        bool
+}
+
+// NewStreamParser returns a parser over the newline-separated relationships read from the
+// given reader.
+func NewStreamParser(r io.Reader, opts ...StreamParserOption) *StreamParser {
+	sp := &StreamParser{
+		reader:        bufio.NewReader(r),
+		maxLineLength: defaultMaxLineLength,
+	}
+	for _, opt := range opts {
+		opt(sp)
+	}
+	return sp
+}
+
+// Next returns the next relationship in the stream, or (nil, nil) once the stream has been
+// exhausted. In strict mode (the default), the first parse error aborts the stream; in
+// collect mode, parse errors are accumulated and the offending lines skipped. I/O errors
+// always abort.
+func (sp *StreamParser) Next() (*Relationship, error) {
+	for !sp.done {
+		line, overLong, err := sp.readLine()
+		if errors.Is(err, io.EOF) {
+			sp.done = true
+			return nil, nil
+		}
+		if err != nil {
+			sp.done = true
+			return nil, err
+		}
+
+		sp.lineNumber++
+
+		if overLong {
+			if err := sp.handleParseError(StreamParseError{
+				error:          fmt.Errorf("line %d is longer than the maximum allowed %d bytes", sp.lineNumber, sp.maxLineLength),
+				LineNumber:     sp.lineNumber,
+				ColumnPosition: sp.maxLineLength + 1,
+			}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) == 0 || strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+
+		rel, err := Parse(trimmed)
+		if err != nil {
+			if err := sp.handleParseError(StreamParseError{
+				error:          fmt.Errorf("error parsing relationship `%s` on line %d: %w", trimmed, sp.lineNumber, err),
+				SourceCode:     trimmed,
+				LineNumber:     sp.lineNumber,
+				ColumnPosition: strings.Index(line, trimmed) + 1,
+			}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		sp.count++
+		sp.currentLine = trimmed
+		return &rel, nil
+	}
+
+	return nil, nil
+}
+
+// Count returns the number of relationships successfully parsed so far.
+func (sp *StreamParser) Count() int {
+	return sp.count
+}
+
+// LineNumber returns the one-indexed line number of the most recently returned relationship
+// or error.
+func (sp *StreamParser) LineNumber() int {
+	return sp.lineNumber
+}
+
+// CurrentLine returns the trimmed source line of the most recently returned relationship.
+func (sp *StreamParser) CurrentLine() string {
+	return sp.currentLine
+}
+
+// Errors returns the parse errors collected so far. Always empty in strict mode.
+func (sp *StreamParser) Errors() []StreamParseError {
+	return sp.collected
+}
+
+func (sp *StreamParser) handleParseError(parseErr StreamParseError) error {
+	if !sp.collectErrors {
+		sp.done = true
+		return parseErr
+	}
+
+	if len(sp.collected) >= sp.errorCap {
+		sp.done = true
+		return fmt.Errorf("too many parse errors; stopped collecting after %d: %w", sp.errorCap, parseErr)
+	}
+
+	sp.collected = append(sp.collected, parseErr)
+	return nil
+}
+
+// readLine reads the next newline-terminated line from the reader, reporting whether it
+// exceeded the maximum line length. Over-long lines are drained rather than buffered.
+func (sp *StreamParser) readLine() (string, bool, error) {
+	var sb strings.Builder
+	overLong := false
+
+	for {
+		chunk, err := sp.reader.ReadSlice('\n')
+		if !overLong {
+			// +1 allows for the trailing newline itself.
+			if sb.Len()+len(chunk) > sp.maxLineLength+1 {
+				overLong = true
+			} else {
+				sb.Write(chunk)
+			}
+		}
+
+		switch {
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+
+		case errors.Is(err, io.EOF):
+			if sb.Len() == 0 && !overLong {
+				return "", false, io.EOF
+			}
+			return sb.String(), overLong, nil
+
+		case err != nil:
+			return "", false, err
+
+		default:
+			return sb.String(), overLong, nil
+		}
+	}
+}
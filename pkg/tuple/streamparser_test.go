@@ -0,0 +1,137 @@
+package tuple
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const streamParserFixture = `// a comment, followed by a blank line
+
+document:first#viewer@user:tom
+document:second#viewer@user:fred[somecaveat:{"hi": not json}]
+document:third#viewer@user:sarah
+document:fourth#viewer@user:` + "%OVERLONG%" + `
+document:fifth#viewer@user:tom[somecaveat]`
+
+func fixtureWithOverlongLine(maxLineLength int) string {
+	return strings.ReplaceAll(streamParserFixture, "%OVERLONG%", strings.Repeat("a", maxLineLength))
+}
+
+func TestStreamParserStrictMode(t *testing.T) {
+	parser := NewStreamParser(strings.NewReader(fixtureWithOverlongLine(128)), WithMaxLineLength(128))
+
+	rel, err := parser.Next()
+	require.NoError(t, err)
+	require.NotNil(t, rel)
+	require.Equal(t, "document:first#viewer@user:tom", MustString(*rel))
+	require.Equal(t, 3, parser.LineNumber())
+	require.Equal(t, "document:first#viewer@user:tom", parser.CurrentLine())
+
+	rel, err = parser.Next()
+	require.Nil(t, rel)
+	require.Error(t, err)
+
+	var parseErr StreamParseError
+	require.ErrorAs(t, err, &parseErr)
+	require.Equal(t, 4, parseErr.LineNumber)
+	require.Equal(t, 1, parseErr.ColumnPosition)
+	require.Equal(t, `document:second#viewer@user:fred[somecaveat:{"hi": not json}]`, parseErr.SourceCode)
+	require.Error(t, parseErr.Unwrap())
+
+	// The stream is aborted after the first error.
+	rel, err = parser.Next()
+	require.Nil(t, rel)
+	require.NoError(t, err)
+	require.Equal(t, 1, parser.Count())
+}
+
+func TestStreamParserCollectMode(t *testing.T) {
+	parser := NewStreamParser(
+		strings.NewReader(fixtureWithOverlongLine(128)),
+		WithMaxLineLength(128),
+		WithCollectedErrors(10),
+	)
+
+	parsed := make([]string, 0, 3)
+	for {
+		rel, err := parser.Next()
+		require.NoError(t, err)
+		if rel == nil {
+			break
+		}
+		parsed = append(parsed, MustString(*rel))
+	}
+
+	require.Equal(t, []string{
+		"document:first#viewer@user:tom",
+		"document:third#viewer@user:sarah",
+		"document:fifth#viewer@user:tom[somecaveat]",
+	}, parsed)
+	require.Equal(t, 3, parser.Count())
+
+	collected := parser.Errors()
+	require.Len(t, collected, 2)
+
+	require.Equal(t, 4, collected[0].LineNumber)
+	require.Equal(t, `document:second#viewer@user:fred[somecaveat:{"hi": not json}]`, collected[0].SourceCode)
+
+	// The over-long line's contents are not retained.
+	require.Equal(t, 6, collected[1].LineNumber)
+	require.Equal(t, 129, collected[1].ColumnPosition)
+	require.Empty(t, collected[1].SourceCode)
+	require.ErrorContains(t, collected[1], "longer than the maximum allowed 128 bytes")
+}
+
+func TestStreamParserCollectModeErrorCap(t *testing.T) {
+	parser := NewStreamParser(
+		strings.NewReader(fixtureWithOverlongLine(128)),
+		WithMaxLineLength(128),
+		WithCollectedErrors(1),
+	)
+
+	rel, err := parser.Next()
+	require.NoError(t, err)
+	require.Equal(t, "document:first#viewer@user:tom", MustString(*rel))
+
+	// The first error is collected; the second aborts the stream.
+	rel, err = parser.Next()
+	require.NoError(t, err)
+	require.Equal(t, "document:third#viewer@user:sarah", MustString(*rel))
+
+	rel, err = parser.Next()
+	require.Nil(t, rel)
+	require.ErrorContains(t, err, "too many parse errors; stopped collecting after 1")
+	require.Len(t, parser.Errors(), 1)
+}
+
+func TestStreamParserMissingTrailingNewline(t *testing.T) {
+	parser := NewStreamParser(strings.NewReader("document:first#viewer@user:tom"))
+
+	rel, err := parser.Next()
+	require.NoError(t, err)
+	require.Equal(t, "document:first#viewer@user:tom", MustString(*rel))
+
+	rel, err = parser.Next()
+	require.Nil(t, rel)
+	require.NoError(t, err)
+	require.Equal(t, 1, parser.Count())
+}
+
+func TestStreamParserLongLineSpanningBufferedReads(t *testing.T) {
+	// A valid relationship whose line is longer than bufio's default buffer, to exercise
+	// the ErrBufferFull handling in readLine.
+	contextValue := strings.Repeat("a", 5000)
+	input := `document:first#viewer@user:tom[somecaveat:{"key":"` + contextValue + `"}]` + "\ndocument:second#viewer@user:fred\n"
+
+	parser := NewStreamParser(strings.NewReader(input))
+
+	rel, err := parser.Next()
+	require.NoError(t, err)
+	require.Equal(t, contextValue, rel.OptionalCaveat.Context.Fields["key"].GetStringValue())
+
+	rel, err = parser.Next()
+	require.NoError(t, err)
+	require.Equal(t, "document:second#viewer@user:fred", MustString(*rel))
+}
@@ -1,7 +1,9 @@
 package tuple
 
 import (
+	"encoding/json"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -109,6 +111,101 @@ func StringExpiration(expiration *time.Time) (string, error) {
 	return "[expiration:" + expiration.Format(expirationFormat) + "]", nil
 }
 
+// CanonicalString converts a relationship to a string with deterministic output: caveat
+// context keys are sorted recursively and numbers are formatted in a stable manner. The
+// output otherwise matches the standard string form and remains parseable by Parse.
+//
+// Will panic if the relationship's caveat context contains an unknown value type.
+func CanonicalString(rel Relationship) string {
+	spiceerrors.DebugAssertf(rel.ValidateNotEmpty, "relationship must not be empty")
+
+	caveatString := canonicalStringCaveat(rel.OptionalCaveat)
+
+	expirationString, err := StringExpiration(rel.OptionalExpiration)
+	if err != nil {
+		panic(err)
+	}
+
+	return StringONR(rel.Resource) + "@" + StringONR(rel.Subject) + caveatString + expirationString
+}
+
+// canonicalStringCaveat converts a contextualized caveat to a string with its context keys
+// sorted recursively. If the caveat is nil or empty, returns empty string.
+func canonicalStringCaveat(caveat *core.ContextualizedCaveat) string {
+	if caveat == nil || caveat.CaveatName == "" {
+		return ""
+	}
+
+	contextString := CanonicalCaveatContextString(caveat.Context)
+	if contextString == "" {
+		return "[" + caveat.CaveatName + "]"
+	}
+
+	return "[" + caveat.CaveatName + ":" + contextString + "]"
+}
+
+// CanonicalCaveatContextString converts the context of a caveat to canonical JSON: keys are
+// sorted recursively and numbers are formatted in a stable manner. If the context is nil or
+// empty, returns an empty string.
+//
+// Will panic if the context contains an unknown value type.
+func CanonicalCaveatContextString(context *structpb.Struct) string {
+	if context == nil || len(context.Fields) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeCanonicalJSONStruct(&sb, context)
+	return sb.String()
+}
+
+func writeCanonicalJSONStruct(sb *strings.Builder, context *structpb.Struct) {
+	sb.WriteString("{")
+	for i, key := range sortedContextKeys(context.Fields) {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		writeJSONString(sb, key)
+		sb.WriteString(":")
+		writeCanonicalJSONValue(sb, context.Fields[key])
+	}
+	sb.WriteString("}")
+}
+
+func writeCanonicalJSONValue(sb *strings.Builder, value *structpb.Value) {
+	switch value.Kind.(type) {
+	case *structpb.Value_NullValue:
+		sb.WriteString("null")
+	case *structpb.Value_NumberValue:
+		sb.WriteString(strconv.FormatFloat(value.GetNumberValue(), 'f', -1, 64))
+	case *structpb.Value_StringValue:
+		writeJSONString(sb, value.GetStringValue())
+	case *structpb.Value_BoolValue:
+		sb.WriteString(strconv.FormatBool(value.GetBoolValue()))
+	case *structpb.Value_StructValue:
+		writeCanonicalJSONStruct(sb, value.GetStructValue())
+	case *structpb.Value_ListValue:
+		sb.WriteString("[")
+		for i, elem := range value.GetListValue().Values {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			writeCanonicalJSONValue(sb, elem)
+		}
+		sb.WriteString("]")
+	default:
+		panic(spiceerrors.MustBugf("unknown structpb.Value type: %T", value.Kind))
+	}
+}
+
+func writeJSONString(sb *strings.Builder, value string) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	sb.Write(encoded)
+}
+
 // StringWithoutCaveatOrExpiration converts a relationship to a string, without its caveat or expiration included.
 func StringWithoutCaveatOrExpiration(rel Relationship) string {
 	spiceerrors.DebugAssertf(rel.ValidateNotEmpty, "relationship must not be empty")
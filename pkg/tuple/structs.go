@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
@@ -125,6 +126,72 @@ func (r Relationship) Validate() error {
 	return nil
 }
 
+// MaxCaveatContextBytes is the maximum size, in bytes, permitted by ValidateDetailed for a
+// relationship's caveat context. It matches the server's default maximum relationship
+// context size.
+const MaxCaveatContextBytes = 25_000
+
+// FieldError describes a single validation failure found on a relationship field.
+type FieldError struct {
+	// FieldPath is the dotted path of the field that failed validation, e.g. "resource.object_id".
+	FieldPath string
+
+	// Message is a human-readable description of the validation failure.
+	Message string
+}
+
+func (fe FieldError) Error() string {
+	return fe.FieldPath + ": " + fe.Message
+}
+
+// ValidateDetailed returns every validation failure found on the relationship, each with the
+// path of the offending field, rather than stopping at the first problem. Intended for bulk
+// import error reporting; hot paths should continue to use Validate.
+func (r Relationship) ValidateDetailed() []FieldError {
+	var fieldErrors []FieldError
+	report := func(fieldPath string, message string) {
+		fieldErrors = append(fieldErrors, FieldError{FieldPath: fieldPath, Message: message})
+	}
+
+	if !objectTypeRegex.MatchString(r.Resource.ObjectType) {
+		report("resource.object_type", "invalid object type; must match "+namespaceNameExpr)
+	}
+
+	if err := ValidateResourceID(r.Resource.ObjectID); err != nil {
+		report("resource.object_id", err.Error())
+	}
+
+	if !relationRegex.MatchString(r.Resource.Relation) {
+		report("resource.relation", "invalid relation; must match "+relationExpr)
+	}
+
+	if !objectTypeRegex.MatchString(r.Subject.ObjectType) {
+		report("subject.object_type", "invalid object type; must match "+namespaceNameExpr)
+	}
+
+	if err := ValidateSubjectID(r.Subject.ObjectID); err != nil {
+		report("subject.object_id", err.Error())
+	}
+
+	if r.Subject.ObjectID == PublicWildcard && r.Subject.Relation != Ellipsis {
+		report("subject.relation", "wildcard subjects may not specify a relation")
+	} else if r.Subject.Relation != Ellipsis && !relationRegex.MatchString(r.Subject.Relation) {
+		report("subject.relation", "invalid relation; must match "+relationExpr)
+	}
+
+	if r.OptionalCaveat != nil {
+		if size := proto.Size(r.OptionalCaveat.Context); size > MaxCaveatContextBytes {
+			report("caveat.context", fmt.Sprintf("caveat context is %d bytes; must be <= %d bytes", size, MaxCaveatContextBytes))
+		}
+	}
+
+	if r.OptionalExpiration != nil && !r.OptionalExpiration.After(time.Now()) {
+		report("expiration", "expiration must be set to a future time")
+	}
+
+	return fieldErrors
+}
+
 // WithoutIntegrity returns a copy of the relationship without its integrity.
 func (r Relationship) WithoutIntegrity() Relationship {
 	r.OptionalIntegrity = nil
@@ -1,6 +1,7 @@
 package tuple
 
 import (
+	"strings"
 	"testing"
 	"time"
 	"unsafe"
@@ -144,3 +145,117 @@ func TestToCoreTuple(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDetailed(t *testing.T) {
+	pastExpiration := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	futureExpiration := time.Now().Add(24 * time.Hour)
+
+	tcs := []struct {
+		name               string
+		rel                Relationship
+		expectedFieldPaths []string
+	}{
+		{
+			name:               "valid relationship",
+			rel:                MustParse("document:foo#viewer@user:tom"),
+			expectedFieldPaths: nil,
+		},
+		{
+			name:               "valid caveated relationship with expiration",
+			rel:                MustWithExpiration(MustParse("document:foo#viewer@user:tom[somecaveat]"), futureExpiration),
+			expectedFieldPaths: nil,
+		},
+		{
+			name: "multiple violations reported at once",
+			rel: Relationship{
+				RelationshipReference: RelationshipReference{
+					Resource: ObjectAndRelation{
+						ObjectType: "INVALID",
+						ObjectID:   "*",
+						Relation:   "viewer",
+					},
+					Subject: ObjectAndRelation{
+						ObjectType: "user",
+						ObjectID:   PublicWildcard,
+						Relation:   "member",
+					},
+				},
+				OptionalExpiration: &pastExpiration,
+			},
+			expectedFieldPaths: []string{
+				"resource.object_type",
+				"resource.object_id",
+				"subject.relation",
+				"expiration",
+			},
+		},
+		{
+			name: "over-length object IDs",
+			rel: Relationship{
+				RelationshipReference: RelationshipReference{
+					Resource: ObjectAndRelation{
+						ObjectType: "document",
+						ObjectID:   strings.Repeat("f", 1025),
+						Relation:   "viewer",
+					},
+					Subject: ObjectAndRelation{
+						ObjectType: "user",
+						ObjectID:   strings.Repeat("f", 1025),
+						Relation:   Ellipsis,
+					},
+				},
+			},
+			expectedFieldPaths: []string{
+				"resource.object_id",
+				"subject.object_id",
+			},
+		},
+		{
+			name: "invalid relations",
+			rel: Relationship{
+				RelationshipReference: RelationshipReference{
+					Resource: ObjectAndRelation{
+						ObjectType: "document",
+						ObjectID:   "foo",
+						Relation:   "INVALID",
+					},
+					Subject: ObjectAndRelation{
+						ObjectType: "user",
+						ObjectID:   "tom",
+						Relation:   "INVALID",
+					},
+				},
+			},
+			expectedFieldPaths: []string{
+				"resource.relation",
+				"subject.relation",
+			},
+		},
+		{
+			name: "caveat context over the size limit",
+			rel: MustWithCaveat(
+				MustParse("document:foo#viewer@user:tom"),
+				"somecaveat",
+				map[string]any{"value": strings.Repeat("a", MaxCaveatContextBytes)},
+			),
+			expectedFieldPaths: []string{
+				"caveat.context",
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			fieldErrors := tc.rel.ValidateDetailed()
+
+			fieldPaths := make([]string, 0, len(fieldErrors))
+			for _, fieldError := range fieldErrors {
+				require.NotEmpty(t, fieldError.Message)
+				require.Contains(t, fieldError.Error(), fieldError.FieldPath)
+				fieldPaths = append(fieldPaths, fieldError.FieldPath)
+			}
+
+			require.ElementsMatch(t, tc.expectedFieldPaths, fieldPaths)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+// Package typesystem exposes type-system level computations over validated
+// schema definitions for use by external tooling, such as schema linters and
+// cache debuggers.
+package typesystem
+
+import (
+	"context"
+
+	"github.com/authzed/spicedb/internal/namespace"
+	"github.com/authzed/spicedb/pkg/schema"
+)
+
+// ComputedKeyPrefix is the prefix carried by every *computed* canonical key,
+// i.e. keys derived from a permission's rewrite expression. Keys for pure
+// relations are the bare relation name and carry no prefix. The prefix is part
+// of the public contract of ComputeCanonicalKeys.
+const ComputedKeyPrefix = "%"
+
+// ComputeCanonicalKeys returns the map from relation and permission name to
+// canonical cache key for the given validated definition, with permission
+// aliases resolved so aliased permissions share a key.
+//
+// No datastore is required: a definition validated against a resolver over
+// compiled object definitions (schema.ResolverForSchema) is sufficient.
+func ComputeCanonicalKeys(_ context.Context, def *schema.ValidatedDefinition) (map[string]string, error) {
+	return namespace.CanonicalCacheKeys(def)
+}
@@ -0,0 +1,65 @@
+package typesystem_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/authzed/spicedb/pkg/schema"
+	"github.com/authzed/spicedb/pkg/schemadsl/compiler"
+	"github.com/authzed/spicedb/pkg/schemadsl/input"
+	"github.com/authzed/spicedb/pkg/typesystem"
+)
+
+func ExampleComputeCanonicalKeys() {
+	compiled, err := compiler.Compile(compiler.InputSchema{
+		Source: input.Source("schema"),
+		SchemaString: `
+			definition user {}
+
+			definition document {
+				relation owner: user
+				relation viewer: user
+				permission edit = owner
+				permission view = viewer + edit
+				permission another_view = edit + viewer
+			}
+		`,
+	}, compiler.AllowUnprefixedObjectType())
+	if err != nil {
+		panic(err)
+	}
+
+	ctx := context.Background()
+	ts := schema.NewTypeSystem(schema.ResolverForSchema(*compiled))
+
+	def, err := schema.NewDefinition(ts, compiled.ObjectDefinitions[1])
+	if err != nil {
+		panic(err)
+	}
+
+	validated, err := def.Validate(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	keys, err := typesystem.ComputeCanonicalKeys(ctx, validated)
+	if err != nil {
+		panic(err)
+	}
+
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Printf("%s: %s\n", name, keys[name])
+	}
+	// Output:
+	// another_view: %1:1d7a64ae6edf8908
+	// edit: %1:e93302a2d987414a
+	// owner: owner
+	// view: %1:1d7a64ae6edf8908
+	// viewer: viewer
+}
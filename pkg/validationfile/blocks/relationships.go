@@ -1,6 +1,7 @@
 package blocks
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -35,46 +36,58 @@ func (pr *ParsedRelationships) UnmarshalYAML(node *yamlv3.Node) error {
 		return nil
 	}
 
-	seenTuples := map[string]bool{}
-	lines := strings.Split(relationshipsString, "\n")
-	relationships := make([]tuple.Relationship, 0, len(lines))
-	for index, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if len(trimmed) == 0 || strings.HasPrefix(trimmed, "//") {
-			continue
-		}
+	column, err := safecast.ToUint64(node.Column)
+	if err != nil {
+		return err
+	}
 
-		// +1 for the key, and *2 for newlines in YAML
-		errorLine, err := safecast.ToUint64(node.Line + 1 + (index * 2))
-		if err != nil {
-			return err
-		}
-		column, err := safecast.ToUint64(node.Column)
+	// +1 for the key, and *2 for newlines in YAML
+	lineInFile := func(lineNumber int) (uint64, error) {
+		return safecast.ToUint64(node.Line + 1 + ((lineNumber - 1) * 2))
+	}
+
+	seenTuples := map[string]bool{}
+	relationships := make([]tuple.Relationship, 0, strings.Count(relationshipsString, "\n")+1)
+	parser := tuple.NewStreamParser(strings.NewReader(relationshipsString))
+	for {
+		rel, err := parser.Next()
 		if err != nil {
+			var parseErr tuple.StreamParseError
+			if errors.As(err, &parseErr) {
+				errorLine, err := lineInFile(parseErr.LineNumber)
+				if err != nil {
+					return err
+				}
+
+				return spiceerrors.NewWithSourceError(
+					fmt.Errorf("error parsing relationship `%s`: %w", parseErr.SourceCode, parseErr.Unwrap()),
+					parseErr.SourceCode,
+					errorLine,
+					column,
+				)
+			}
 			return err
 		}
-
-		rel, err := tuple.Parse(trimmed)
-		if err != nil {
-			return spiceerrors.NewWithSourceError(
-				fmt.Errorf("error parsing relationship `%s`: %w", trimmed, err),
-				trimmed,
-				errorLine,
-				column,
-			)
+		if rel == nil {
+			break
 		}
 
-		_, ok := seenTuples[tuple.StringWithoutCaveatOrExpiration(rel)]
+		_, ok := seenTuples[tuple.StringWithoutCaveatOrExpiration(*rel)]
 		if ok {
+			errorLine, err := lineInFile(parser.LineNumber())
+			if err != nil {
+				return err
+			}
+
 			return spiceerrors.NewWithSourceError(
-				fmt.Errorf("found repeated relationship `%s`", trimmed),
-				trimmed,
+				fmt.Errorf("found repeated relationship `%s`", parser.CurrentLine()),
+				parser.CurrentLine(),
 				errorLine,
 				column,
 			)
 		}
-		seenTuples[tuple.StringWithoutCaveatOrExpiration(rel)] = true
-		relationships = append(relationships, rel)
+		seenTuples[tuple.StringWithoutCaveatOrExpiration(*rel)] = true
+		relationships = append(relationships, *rel)
 	}
 
 	pr.Relationships = relationships
@@ -126,7 +126,7 @@ relationships: >-
 	errWithSource, ok := spiceerrors.AsWithSourceError(err)
 	require.True(t, ok)
 
-	require.Equal(t, "error parsing relationship `document:firstdocwriter@user:tom`: invalid relationship string", err.Error())
+	require.Equal(t, "error parsing relationship `document:firstdocwriter@user:tom`: invalid relationship string: malformed relation at offset 23: expected `#` followed by a relation name", err.Error())
 	require.Equal(t, uint64(5), errWithSource.LineNumber)
 }
 
@@ -143,7 +143,7 @@ relationships: >-
 	errWithSource, ok := spiceerrors.AsWithSourceError(err)
 	require.True(t, ok)
 
-	require.Equal(t, "error parsing relationship `document:firstdoc#readeruser:fred`: invalid relationship string", err.Error())
+	require.Equal(t, "error parsing relationship `document:firstdoc#readeruser:fred`: invalid relationship string: malformed subject at offset 33: expected `@` followed by a subject reference", err.Error())
 	require.Equal(t, uint64(7), errWithSource.LineNumber)
 }
 
@@ -166,7 +166,7 @@ relationships: >-
 	errWithSource, ok := spiceerrors.AsWithSourceError(err)
 	require.True(t, ok)
 
-	require.Equal(t, "error parsing relationship `document:firstdoc#readeruser:fred`: invalid relationship string", err.Error())
+	require.Equal(t, "error parsing relationship `document:firstdoc#readeruser:fred`: invalid relationship string: malformed subject at offset 33: expected `@` followed by a subject reference", err.Error())
 	require.Equal(t, uint64(13), errWithSource.LineNumber)
 }
 
@@ -16,7 +16,6 @@ import (
 	"github.com/authzed/spicedb/internal/relationships"
 	caveattypes "github.com/authzed/spicedb/pkg/caveats/types"
 	"github.com/authzed/spicedb/pkg/datastore"
-	"github.com/authzed/spicedb/pkg/genutil/slicez"
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 	"github.com/authzed/spicedb/pkg/schema"
 	"github.com/authzed/spicedb/pkg/schemadsl/compiler"
@@ -25,6 +24,16 @@ import (
 	"github.com/authzed/spicedb/pkg/tuple"
 )
 
+const (
+	// maxWriteBatchCount is the maximum number of relationships written per transaction
+	// when populating a datastore.
+	maxWriteBatchCount = 500
+
+	// maxWriteBatchBytes is the maximum estimated total size of the relationships written
+	// per transaction, kept well under the per-transaction byte limits of the backends.
+	maxWriteBatchBytes = 4 * 1024 * 1024 // 4 MiB
+)
+
 // PopulatedValidationFile contains the fully parsed information from a validation file.
 type PopulatedValidationFile struct {
 	// Schema is the entered schema text, if any.
@@ -197,15 +206,17 @@ func PopulateFromFilesContents(ctx context.Context, ds datastore.Datastore, cave
 		return err
 	})
 
-	slicez.ForEachChunk(updates, 500, func(chunked []tuple.RelationshipUpdate) {
+	// Batch by estimated size as well as count, to stay within the per-transaction byte
+	// limits of the datastore backends.
+	offset := 0
+	for _, chunkedRels := range tuple.BatchBySize(rels, maxWriteBatchBytes, maxWriteBatchCount) {
 		if err != nil {
-			return
+			break
 		}
 
-		chunkedRels := make([]tuple.Relationship, 0, len(chunked))
-		for _, update := range chunked {
-			chunkedRels = append(chunkedRels, update.Relationship)
-		}
+		chunked := updates[offset : offset+len(chunkedRels)]
+		offset += len(chunkedRels)
+
 		revision, err = ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
 			err = relationships.ValidateRelationshipsForCreateOrTouch(ctx, rwt, caveatTypeSet, chunkedRels...)
 			if err != nil {
@@ -214,7 +225,7 @@ func PopulateFromFilesContents(ctx context.Context, ds datastore.Datastore, cave
 
 			return rwt.WriteRelationships(ctx, chunked)
 		})
-	})
+	}
 
 	if err != nil {
 		return nil, nil, err